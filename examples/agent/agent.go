@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vanna-ai/ont-run/pkg/cloud"
+	"github.com/vanna-ai/ont-run/pkg/notify"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/server"
+)
+
+// ToolCallResult reports what happened to a single tool call requested by the model.
+type ToolCallResult struct {
+	Name       string         `json:"name"`
+	Arguments  map[string]any `json:"arguments"`
+	Status     string         `json:"status"` // "executed", "approval_required", "rejected" or "failed"
+	Result     any            `json:"result,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	ApprovalID string         `json:"approvalId,omitempty"`
+}
+
+// ChatReply is the response body of POST /chat.
+type ChatReply struct {
+	Message   string           `json:"message,omitempty"`
+	ToolCalls []ToolCallResult `json:"toolCalls"`
+}
+
+// pendingCall is a destructive tool call waiting for a human decision.
+type pendingCall struct {
+	Name      string
+	Arguments map[string]any
+}
+
+// Agent proxies chat to ont-run.com and executes the returned tool calls
+// through the server's REST API, with the credentials of the agent chatting.
+// Read-only functions run immediately; mutations are parked until a human
+// approves them.
+type Agent struct {
+	config   *ont.Config
+	api      http.Handler
+	auth     server.AuthFunc
+	client   *cloud.Client
+	uuid     string
+	logger   ont.Logger
+	notifier notify.Notifier // optional; told about calls awaiting approval
+
+	mu      sync.Mutex
+	pending map[string]pendingCall
+}
+
+// NewAgent creates an agent for the given ontology, served by api with auth.
+// notifier may be nil.
+func NewAgent(config *ont.Config, api http.Handler, auth server.AuthFunc, client *cloud.Client, uuid string, logger ont.Logger, notifier notify.Notifier) *Agent {
+	return &Agent{
+		config:   config,
+		api:      api,
+		auth:     auth,
+		client:   client,
+		uuid:     uuid,
+		logger:   logger,
+		notifier: notifier,
+		pending:  make(map[string]pendingCall),
+	}
+}
+
+// HandleChat handles POST /chat with a body of {"messages": [...]}.
+func (a *Agent) HandleChat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	authResult, err := a.auth(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	var body struct {
+		Messages []cloud.ChatMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := a.client.Chat(a.uuid, body.Messages, map[string]any{
+		"functions": a.toolNames(authResult.AccessGroups),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if !resp.Success {
+		if resp.LimitReached {
+			http.Error(w, "Chat limit reached", http.StatusTooManyRequests)
+			return
+		}
+		http.Error(w, resp.Message, http.StatusBadGateway)
+		return
+	}
+
+	reply := ChatReply{Message: resp.Message, ToolCalls: []ToolCallResult{}}
+	for _, call := range resp.ToolCalls {
+		reply.ToolCalls = append(reply.ToolCalls, a.dispatch(r, call))
+	}
+
+	writeJSON(w, reply)
+}
+
+// HandleApprove handles POST /chat/approve with a body of
+// {"approvalId": "...", "approve": true}.
+func (a *Agent) HandleApprove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if _, err := a.auth(r); err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	var body struct {
+		ApprovalID string `json:"approvalId"`
+		Approve    bool   `json:"approve"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	a.mu.Lock()
+	call, ok := a.pending[body.ApprovalID]
+	delete(a.pending, body.ApprovalID)
+	a.mu.Unlock()
+
+	if !ok {
+		http.Error(w, "Unknown or already decided approval", http.StatusNotFound)
+		return
+	}
+
+	if !body.Approve {
+		a.logger.Info("Tool call rejected", "function", call.Name, "approvalId", body.ApprovalID)
+		writeJSON(w, ToolCallResult{Name: call.Name, Arguments: call.Arguments, Status: "rejected"})
+		return
+	}
+
+	a.logger.Info("Tool call approved", "function", call.Name, "approvalId", body.ApprovalID)
+	writeJSON(w, a.execute(r, call.Name, call.Arguments))
+}
+
+// dispatch runs read-only calls and parks mutations for approval.
+func (a *Agent) dispatch(r *http.Request, call cloud.ToolCall) ToolCallResult {
+	// The cloud may already have resolved the call; pass it through untouched
+	if call.Result != nil {
+		return ToolCallResult{Name: call.Name, Arguments: call.Arguments, Status: "executed", Result: call.Result}
+	}
+
+	fn, ok := a.config.Functions[call.Name]
+	if !ok || !fn.IncludeInMcpListTools {
+		return ToolCallResult{Name: call.Name, Arguments: call.Arguments, Status: "failed", Error: "unknown function"}
+	}
+
+	if fn.IsReadOnly {
+		return a.execute(r, call.Name, call.Arguments)
+	}
+
+	id := newApprovalID()
+	a.mu.Lock()
+	a.pending[id] = pendingCall{Name: call.Name, Arguments: call.Arguments}
+	a.mu.Unlock()
+
+	a.logger.Info("Tool call awaiting approval", "function", call.Name, "approvalId", id)
+	a.notifyApproval(id, call)
+	return ToolCallResult{Name: call.Name, Arguments: call.Arguments, Status: "approval_required", ApprovalID: id}
+}
+
+// execute calls a function through the REST API with the headers of r, so
+// the call is authenticated, access-checked and validated like the agent
+// calling it directly.
+func (a *Agent) execute(r *http.Request, name string, args map[string]any) ToolCallResult {
+	result := ToolCallResult{Name: name, Arguments: args}
+	if args == nil {
+		args = map[string]any{}
+	}
+	body, err := json.Marshal(args)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, "/api/"+name, bytes.NewReader(body))
+	if err != nil {
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Del("Content-Length")
+	rec := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+	a.api.ServeHTTP(rec, req)
+
+	if rec.status != http.StatusOK {
+		result.Status = "failed"
+		result.Error = strings.TrimSpace(rec.body.String())
+		return result
+	}
+	if err := json.Unmarshal(rec.body.Bytes(), &result.Result); err != nil {
+		result.Status = "failed"
+		result.Error = fmt.Sprintf("invalid response: %v", err)
+		return result
+	}
+	result.Status = "executed"
+	return result
+}
+
+// responseRecorder captures the response of an API call made by execute.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header            { return r.header }
+func (r *responseRecorder) WriteHeader(status int)         { r.status = status }
+func (r *responseRecorder) Write(data []byte) (int, error) { return r.body.Write(data) }
+
+// notifyApproval tells reviewers about a parked call without delaying the chat reply.
+func (a *Agent) notifyApproval(id string, call cloud.ToolCall) {
+	if a.notifier == nil {
+		return
+	}
+	msg := notify.Message{
+		Event:   "approval.requested",
+		Subject: fmt.Sprintf("Approval needed: %s", call.Name),
+		Body:    fmt.Sprintf("The assistant wants to call %s with %v.\nApprove or reject it with POST /chat/approve and approvalId %s.", call.Name, call.Arguments, id),
+		Data: map[string]any{
+			"approvalId": id,
+			"function":   call.Name,
+			"arguments":  call.Arguments,
+		},
+	}
+	go func() {
+		if err := a.notifier.Notify(context.Background(), msg); err != nil {
+			a.logger.Error("Approval notification failed", "approvalId", id, "error", err)
+		}
+	}()
+}
+
+// toolNames lists the functions the model may call for a caller with
+// accessGroups.
+func (a *Agent) toolNames(accessGroups []string) []string {
+	names := make([]string, 0, len(a.config.Functions))
+	for name, fn := range a.config.Functions {
+		if fn.IncludeInMcpListTools && fn.CheckAccess(accessGroups) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newApprovalID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vanna-ai/ont-run/pkg/server"
+)
+
+// apiKeyAuth returns an AuthFunc that lets support agents in with a shared
+// "Authorization: Bearer <key>" header. The server, /chat and /chat/approve
+// all use it, so the assistant can only do what the agent talking to it
+// may do.
+func apiKeyAuth(key string) server.AuthFunc {
+	return func(r *http.Request) (*server.AuthResult, error) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			return nil, errors.New("missing or invalid API key")
+		}
+		return &server.AuthResult{AccessGroups: []string{"agent"}}, nil
+	}
+}
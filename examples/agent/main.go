@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/vanna-ai/ont-run/pkg/cloud"
+	"github.com/vanna-ai/ont-run/pkg/notify"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/server"
+)
+
+func main() {
+	ontology := DefineOntology()
+
+	if err := ontology.Validate(); err != nil {
+		log.Fatalf("Invalid ontology: %v", err)
+	}
+
+	// The chat proxy needs the ontology to be registered with ont-run.com
+	uuid := os.Getenv("ONT_UUID")
+	if uuid == "" {
+		log.Fatalf("ONT_UUID must be set to the ontology's cloud UUID")
+	}
+	ontology.UUID = uuid
+	ontology.Cloud = true
+
+	apiKey := os.Getenv("AGENT_API_KEY")
+	if apiKey == "" {
+		log.Fatalf("AGENT_API_KEY must be set to the key support agents send")
+	}
+	auth := apiKeyAuth(apiKey)
+
+	logger := ont.ConsoleLogger()
+
+	// MCP clients get the visualizer for listTickets (table) and ticketStats (chart)
+	srv := server.New(ontology,
+		server.WithLogger(logger),
+		server.WithAuth(auth),
+		server.WithVisualizerHTML(server.DefaultVisualizerHTML()),
+	)
+	api := srv.Handler()
+
+	var cloudOpts []cloud.ClientOption
+	if baseURL := os.Getenv("ONT_BASE_URL"); baseURL != "" {
+		cloudOpts = append(cloudOpts, cloud.WithBaseURL(baseURL))
+	}
+
+	// Optionally tell reviewers when a destructive call is waiting for approval
+	var notifier notify.Notifier
+	if webhookURL := os.Getenv("APPROVAL_WEBHOOK_URL"); webhookURL != "" {
+		notifier = notify.NewWebhook(webhookURL, notify.WithSecret([]byte(os.Getenv("APPROVAL_WEBHOOK_SECRET"))))
+	}
+
+	agent := NewAgent(ontology, api, auth, cloud.NewClient(cloudOpts...), uuid, logger, notifier)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chat", agent.HandleChat)
+	mux.HandleFunc("/chat/approve", agent.HandleApprove)
+	mux.Handle("/", api)
+
+	cloud.TryRegisterWithCloud(uuid, ontology, cloudOpts...)
+
+	log.Println("Starting server on :8080...")
+	if err := http.ListenAndServe(":8080", mux); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
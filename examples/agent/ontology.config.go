@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/vanna-ai/ont-run/examples/agent/resolvers"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func DefineOntology() *ont.Config {
+	ticket := ont.Object(map[string]ont.Schema{
+		"id":       ont.String(),
+		"title":    ont.String(),
+		"status":   ont.String().Enum("open", "closed"),
+		"priority": ont.Integer().Min(1).Max(5),
+	})
+
+	return &ont.Config{
+		Name:         "agent-app",
+		Title:        "Agent App - Support Desk Example Ontology",
+		Instructions: "A support desk. Use 'listTickets' to see tickets and 'ticketStats' for a chart of open tickets by priority. 'closeTicket' is destructive and requires human approval before it runs.",
+
+		AccessGroups: map[string]ont.AccessGroup{
+			"agent": {
+				Description: "Support agents and the AI assistant acting on their behalf",
+			},
+		},
+
+		Entities: map[string]ont.Entity{
+			"Ticket": {
+				Description: "A customer support ticket",
+			},
+		},
+
+		Functions: map[string]ont.Function{
+			"listTickets": {
+				Description:           "List support tickets, optionally filtered by status",
+				Access:                []string{"agent"},
+				Entities:              []string{"Ticket"},
+				IsReadOnly:            true,
+				IncludeInMcpListTools: true,
+				Inputs: ont.Object(map[string]ont.Schema{
+					"status": ont.String().Enum("open", "closed"),
+				}).Optional("status"),
+				// MCP output schemas must be objects; the visualizer renders "data"
+				Outputs: ont.Object(map[string]ont.Schema{
+					"data": ont.Array(ticket),
+				}),
+				UI:       &ont.UiConfig{Type: "table"},
+				Resolver: resolvers.ListTickets,
+			},
+			"ticketStats": {
+				Description:           "Count open tickets by priority",
+				Access:                []string{"agent"},
+				Entities:              []string{"Ticket"},
+				IsReadOnly:            true,
+				IncludeInMcpListTools: true,
+				Inputs:                ont.Object(map[string]ont.Schema{}),
+				Outputs: ont.Object(map[string]ont.Schema{
+					"data": ont.Array(ont.Object(map[string]ont.Schema{
+						"priority": ont.Integer(),
+						"count":    ont.Integer().NonNegative(),
+					})),
+				}),
+				UI: &ont.UiConfig{
+					Type:      "chart",
+					ChartType: "bar",
+					XAxis:     "priority",
+					LeftYAxis: []string{"count"},
+				},
+				Resolver: resolvers.TicketStats,
+			},
+			"closeTicket": {
+				Description:           "Close a ticket. Destructive: requires human approval when requested by the assistant",
+				Access:                []string{"agent"},
+				Entities:              []string{"Ticket"},
+				IsReadOnly:            false,
+				IncludeInMcpListTools: true,
+				Inputs: ont.Object(map[string]ont.Schema{
+					"id": ont.String(),
+				}),
+				Outputs:  ticket,
+				Resolver: resolvers.CloseTicket,
+			},
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package resolvers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// Ticket is the output shape for a support ticket.
+type Ticket struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+}
+
+// PriorityCount is one bar in the ticketStats chart.
+type PriorityCount struct {
+	Priority int `json:"priority"`
+	Count    int `json:"count"`
+}
+
+// TicketList is the response type for the listTickets function.
+type TicketList struct {
+	Data []Ticket `json:"data"`
+}
+
+// TicketStatsOutput is the response type for the ticketStats function.
+type TicketStatsOutput struct {
+	Data []PriorityCount `json:"data"`
+}
+
+// In a real app these would live in a database.
+var (
+	ticketsMu sync.RWMutex
+	tickets   = map[string]*Ticket{
+		"T-1": {ID: "T-1", Title: "Cannot reset password", Status: "open", Priority: 2},
+		"T-2": {ID: "T-2", Title: "Invoice shows wrong currency", Status: "open", Priority: 3},
+		"T-3": {ID: "T-3", Title: "App crashes on launch", Status: "open", Priority: 1},
+		"T-4": {ID: "T-4", Title: "Feature request: dark mode", Status: "closed", Priority: 5},
+	}
+)
+
+// ListTickets returns tickets sorted by ID, optionally filtered by status.
+func ListTickets(ctx ont.Context, input any) (any, error) {
+	inputMap, ok := input.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid input type")
+	}
+
+	status, _ := inputMap["status"].(string)
+
+	ticketsMu.RLock()
+	defer ticketsMu.RUnlock()
+
+	result := []Ticket{}
+	for _, t := range tickets {
+		if status == "" || t.Status == status {
+			result = append(result, *t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return TicketList{Data: result}, nil
+}
+
+// TicketStats counts open tickets by priority.
+func TicketStats(ctx ont.Context, input any) (any, error) {
+	ticketsMu.RLock()
+	defer ticketsMu.RUnlock()
+
+	counts := make(map[int]int)
+	for _, t := range tickets {
+		if t.Status == "open" {
+			counts[t.Priority]++
+		}
+	}
+
+	result := []PriorityCount{}
+	for priority := 1; priority <= 5; priority++ {
+		result = append(result, PriorityCount{Priority: priority, Count: counts[priority]})
+	}
+
+	return TicketStatsOutput{Data: result}, nil
+}
+
+// CloseTicket marks a ticket as closed.
+func CloseTicket(ctx ont.Context, input any) (any, error) {
+	inputMap, ok := input.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid input type")
+	}
+
+	id, _ := inputMap["id"].(string)
+
+	ticketsMu.Lock()
+	defer ticketsMu.Unlock()
+
+	t, ok := tickets[id]
+	if !ok {
+		return nil, fmt.Errorf("ticket '%s' not found", id)
+	}
+
+	ctx.Logger().Info("Closing ticket", "id", id)
+	t.Status = "closed"
+
+	return *t, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// statusRecorder captures the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// auditMiddleware logs every function call with the caller's identity and
+// the outcome, including denied and unauthenticated attempts that never
+// reach a resolver.
+func auditMiddleware(next http.Handler, secret []byte, logger ont.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		actor := "anonymous"
+		if token := bearerToken(r); token != "" {
+			if claims, err := verifyToken(token, secret); err == nil {
+				actor = claims.Subject
+			} else {
+				actor = "invalid-token"
+			}
+		}
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		logger.Info("audit",
+			"actor", actor,
+			"function", strings.TrimPrefix(r.URL.Path, "/api/"),
+			"status", rec.status,
+			"durationMs", time.Since(start).Milliseconds(),
+		)
+	})
+}
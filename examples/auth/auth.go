@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/server"
+)
+
+// roleHierarchy expands a token's role into every access group it implies.
+// Higher roles inherit the groups of the roles below them, so the ontology
+// only has to list the lowest group that may call a function.
+var roleHierarchy = map[string][]string{
+	"admin":    {"admin", "manager", "employee", "public"},
+	"manager":  {"manager", "employee", "public"},
+	"employee": {"employee", "public"},
+}
+
+// Claims are the JWT claims this example understands.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// jwtAuth returns an AuthFunc that verifies HS256 bearer tokens.
+// Requests without a token are treated as "public"; requests with an
+// invalid or expired token are rejected.
+func jwtAuth(secret []byte) server.AuthFunc {
+	return func(r *http.Request) (*server.AuthResult, error) {
+		token := bearerToken(r)
+		if token == "" {
+			return &server.AuthResult{AccessGroups: []string{"public"}}, nil
+		}
+
+		claims, err := verifyToken(token, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		groups, ok := roleHierarchy[claims.Role]
+		if !ok {
+			return nil, fmt.Errorf("unknown role '%s'", claims.Role)
+		}
+
+		return &server.AuthResult{
+			AccessGroups: groups,
+			UserContext: map[string]any{
+				"userId": claims.Subject,
+				"role":   claims.Role,
+			},
+		}, nil
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer ..." header.
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// signToken creates an HS256 JWT for the given claims.
+func signToken(claims Claims, secret []byte) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claims: %w", err)
+	}
+
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + sign(unsigned, secret), nil
+}
+
+// verifyToken checks the signature and expiry of an HS256 JWT.
+func verifyToken(token string, secret []byte) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	if parts[0] != jwtHeader {
+		return nil, fmt.Errorf("unsupported token header")
+	}
+
+	expected := sign(parts[0]+"."+parts[1], secret)
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token payload: %w", err)
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if claims.ExpiresAt != 0 && time.Now().Unix() > claims.ExpiresAt {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	return &claims, nil
+}
+
+func sign(data string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/codegen/typescript"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/server"
+)
+
+func main() {
+	secret := []byte(os.Getenv("JWT_SECRET"))
+	if len(secret) == 0 {
+		secret = []byte("dev-secret-change-me")
+	}
+
+	// `go run . token <subject> <role>` mints a token for local testing
+	if len(os.Args) == 4 && os.Args[1] == "token" {
+		token, err := signToken(Claims{
+			Subject:   os.Args[2],
+			Role:      os.Args[3],
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}, secret)
+		if err != nil {
+			log.Fatalf("Failed to sign token: %v", err)
+		}
+		fmt.Println(token)
+		return
+	}
+
+	ontology := DefineOntology()
+
+	if err := ontology.Validate(); err != nil {
+		log.Fatalf("Invalid ontology: %v", err)
+	}
+
+	if os.Getenv("NODE_ENV") != "production" {
+		log.Println("Generating TypeScript SDK...")
+		if err := typescript.GenerateTypeScript(ontology, "./sdk"); err != nil {
+			log.Fatalf("Failed to generate SDK: %v", err)
+		}
+	}
+
+	logger := ont.ConsoleLogger()
+	srv := server.New(ontology,
+		server.WithLogger(logger),
+		server.WithAuth(jwtAuth(secret)),
+	)
+
+	log.Println("Starting server on :8080...")
+	if err := http.ListenAndServe(":8080", auditMiddleware(srv.Handler(), secret, logger)); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
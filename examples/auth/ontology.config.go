@@ -0,0 +1,85 @@
+package main
+
+import (
+	"github.com/vanna-ai/ont-run/examples/auth/resolvers"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func DefineOntology() *ont.Config {
+	employee := ont.Object(map[string]ont.Schema{
+		"id":         ont.String(),
+		"name":       ont.String(),
+		"department": ont.String(),
+		// Redacted (null) unless the caller is a manager or above
+		"email":  ont.Nullable(ont.String().Email()),
+		"salary": ont.Nullable(ont.Number().NonNegative()),
+	})
+
+	return &ont.Config{
+		Name:         "auth-app",
+		Title:        "Auth App - Multi-role Example Ontology",
+		Instructions: "An HR directory protected by JWT auth. Employees can look up colleagues, managers see contact and salary details, and admins can change salaries.",
+
+		AccessGroups: map[string]ont.AccessGroup{
+			"public": {
+				Description: "Unauthenticated callers",
+			},
+			"employee": {
+				Description: "Any signed-in employee",
+			},
+			"manager": {
+				Description: "Managers; inherit employee access",
+			},
+			"admin": {
+				Description: "HR administrators; inherit manager access",
+			},
+		},
+
+		Entities: map[string]ont.Entity{
+			"Employee": {
+				Description: "A person employed by the company",
+			},
+		},
+
+		Functions: map[string]ont.Function{
+			"whoAmI": {
+				Description:           "Return the identity and access groups of the caller",
+				Access:                []string{"public"},
+				Entities:              []string{},
+				IsReadOnly:            true,
+				IncludeInMcpListTools: true,
+				Inputs:                ont.Object(map[string]ont.Schema{}),
+				Outputs: ont.Object(map[string]ont.Schema{
+					"userId":       ont.Nullable(ont.String()),
+					"accessGroups": ont.Array(ont.String()),
+				}),
+				Resolver: resolvers.WhoAmI,
+			},
+			"getEmployee": {
+				Description:           "Get an employee by ID; sensitive fields are redacted for non-managers",
+				Access:                []string{"employee"},
+				Entities:              []string{"Employee"},
+				IsReadOnly:            true,
+				IncludeInMcpListTools: true,
+				Inputs: ont.Object(map[string]ont.Schema{
+					"id": ont.String(),
+				}),
+				Outputs:  employee,
+				Resolver: resolvers.GetEmployee,
+			},
+			"updateSalary": {
+				Description:           "Change an employee's salary",
+				Access:                []string{"admin"},
+				Entities:              []string{"Employee"},
+				IsReadOnly:            false,
+				IncludeInMcpListTools: true,
+				Inputs: ont.Object(map[string]ont.Schema{
+					"id":     ont.String(),
+					"salary": ont.Number().NonNegative(),
+				}),
+				Outputs:  employee,
+				Resolver: resolvers.UpdateSalary,
+			},
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package resolvers
+
+import (
+	"sync"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// employeeRecord is the full, unredacted employee record.
+type employeeRecord struct {
+	ID         string
+	Name       string
+	Department string
+	Email      string
+	Salary     float64
+}
+
+// In a real app these would live in a database.
+var (
+	employeesMu sync.RWMutex
+	employees   = map[string]*employeeRecord{
+		"e1": {ID: "e1", Name: "Alice Chen", Department: "Engineering", Email: "alice@example.com", Salary: 185000},
+		"e2": {ID: "e2", Name: "Bob Okafor", Department: "Sales", Email: "bob@example.com", Salary: 120000},
+	}
+)
+
+// hasGroup reports whether the caller belongs to the given access group.
+func hasGroup(ctx ont.Context, group string) bool {
+	for _, g := range ctx.AccessGroups() {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// toOutput converts a record to its output shape, redacting sensitive
+// fields unless the caller is a manager (admins inherit manager access).
+func toOutput(ctx ont.Context, e *employeeRecord) map[string]any {
+	out := map[string]any{
+		"id":         e.ID,
+		"name":       e.Name,
+		"department": e.Department,
+		"email":      nil,
+		"salary":     nil,
+	}
+	if hasGroup(ctx, "manager") {
+		out["email"] = e.Email
+		out["salary"] = e.Salary
+	}
+	return out
+}
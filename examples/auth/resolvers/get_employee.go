@@ -0,0 +1,27 @@
+package resolvers
+
+import (
+	"fmt"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// GetEmployee retrieves an employee, redacting fields the caller may not see.
+func GetEmployee(ctx ont.Context, input any) (any, error) {
+	inputMap, ok := input.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid input type")
+	}
+
+	id, _ := inputMap["id"].(string)
+
+	employeesMu.RLock()
+	defer employeesMu.RUnlock()
+
+	e, ok := employees[id]
+	if !ok {
+		return nil, fmt.Errorf("employee '%s' not found", id)
+	}
+
+	return toOutput(ctx, e), nil
+}
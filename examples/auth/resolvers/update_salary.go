@@ -0,0 +1,37 @@
+package resolvers
+
+import (
+	"fmt"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// UpdateSalary changes an employee's salary and records an audit entry
+// with the previous and new values.
+func UpdateSalary(ctx ont.Context, input any) (any, error) {
+	inputMap, ok := input.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid input type")
+	}
+
+	id, _ := inputMap["id"].(string)
+	salary, _ := inputMap["salary"].(float64)
+
+	employeesMu.Lock()
+	defer employeesMu.Unlock()
+
+	e, ok := employees[id]
+	if !ok {
+		return nil, fmt.Errorf("employee '%s' not found", id)
+	}
+
+	ctx.Logger().Info("audit: salary changed",
+		"actor", ctx.UserContext()["userId"],
+		"employee", id,
+		"from", e.Salary,
+		"to", salary,
+	)
+	e.Salary = salary
+
+	return toOutput(ctx, e), nil
+}
@@ -0,0 +1,18 @@
+package resolvers
+
+import (
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// WhoAmI returns the caller's identity as resolved by the auth function.
+func WhoAmI(ctx ont.Context, input any) (any, error) {
+	var userID any
+	if id, ok := ctx.UserContext()["userId"].(string); ok {
+		userID = id
+	}
+
+	return map[string]any{
+		"userId":       userID,
+		"accessGroups": append([]string{}, ctx.AccessGroups()...),
+	}, nil
+}
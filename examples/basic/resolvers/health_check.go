@@ -8,8 +8,8 @@ import (
 
 // HealthCheckOutput is the response type for the healthCheck function.
 type HealthCheckOutput struct {
-	Status    string `json:"status"`
-	Timestamp string `json:"timestamp"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // HealthCheck returns the health status of the server.
@@ -18,6 +18,6 @@ func HealthCheck(ctx ont.Context, input any) (any, error) {
 
 	return HealthCheckOutput{
 		Status:    "ok",
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Timestamp: time.Now().UTC(),
 	}, nil
 }
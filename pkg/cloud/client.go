@@ -12,6 +12,8 @@ import (
 	"os"
 	"sort"
 	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
 )
 
 const (
@@ -30,6 +32,7 @@ type Client struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
+	transport  transportConfig
 }
 
 // ClientOption configures the Client.
@@ -69,6 +72,7 @@ func NewClient(opts ...ClientOption) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.applyTransport()
 
 	return c
 }
@@ -93,6 +97,23 @@ type FunctionShape struct {
 	Entities      []string       `json:"entities"`
 	InputsSchema  map[string]any `json:"inputsSchema"`
 	OutputsSchema map[string]any `json:"outputsSchema,omitempty"`
+
+	// The contract beyond the schemas, shown in the review UI. Unset
+	// fields are omitted, so they don't change the hash of functions that
+	// don't use them.
+	UI         *ontology.UiConfig `json:"ui,omitempty"`
+	IsReadOnly bool               `json:"isReadOnly,omitempty"`
+	Tags       []string           `json:"tags,omitempty"`
+	Stability  ontology.Stability `json:"stability,omitempty"`
+	Deprecated string             `json:"deprecated,omitempty"`
+	// UsesUserContext and UsesOrganizationContext mirror the function's
+	// flags.
+	UsesUserContext         bool `json:"usesUserContext,omitempty"`
+	UsesOrganizationContext bool `json:"usesOrganizationContext,omitempty"`
+	// SensitiveInputs and SensitiveOutputs are the JSON pointers of
+	// sensitive fields (see ontology.SensitiveFields).
+	SensitiveInputs  []string `json:"sensitiveInputs,omitempty"`
+	SensitiveOutputs []string `json:"sensitiveOutputs,omitempty"`
 }
 
 // RegisterRequest is the request body for registration.
@@ -147,7 +168,7 @@ func (c *Client) Register(uuid string, snapshot OntologySnapshot) (*Registration
 		httpReq.Header.Set(APIKeyHeader, c.apiKey)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -228,7 +249,7 @@ func (c *Client) Chat(uuid string, messages []ChatMessage, context map[string]an
 		httpReq.Header.Set(APIKeyHeader, c.apiKey)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -264,11 +285,21 @@ type VersionEntry struct {
 type VersionsResponse struct {
 	Success  bool           `json:"success"`
 	Versions []VersionEntry `json:"versions"`
+	// NextCursor fetches the next page; it is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+	// Total is the number of versions matching the filters.
+	Total int `json:"total,omitempty"`
 }
 
 // Versions retrieves the version history for an ontology.
 func (c *Client) Versions(uuid string) (*VersionsResponse, error) {
-	req := map[string]string{"uuid": uuid}
+	return c.ListVersions(uuid, VersionsQuery{})
+}
+
+// ListVersions retrieves one page of the version history for an ontology,
+// filtered by query.
+func (c *Client) ListVersions(uuid string, query VersionsQuery) (*VersionsResponse, error) {
+	req := query.request(uuid)
 
 	body, err := json.Marshal(req)
 	if err != nil {
@@ -285,7 +316,7 @@ func (c *Client) Versions(uuid string) (*VersionsResponse, error) {
 		httpReq.Header.Set(APIKeyHeader, c.apiKey)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -346,7 +377,7 @@ func (c *Client) Review(uuid, versionID, action, comment string) (*ReviewRespons
 		httpReq.Header.Set(APIKeyHeader, c.apiKey)
 	}
 
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := c.do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -0,0 +1,110 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// VersionResponse is the response from version: a registered version and
+// the snapshot it was registered with.
+type VersionResponse struct {
+	Success     bool             `json:"success"`
+	Version     VersionEntry     `json:"version"`
+	OntologyDef OntologySnapshot `json:"ontologyDef"`
+}
+
+// Version retrieves a registered version with its snapshot.
+func (c *Client) Version(uuid, versionID string) (*VersionResponse, error) {
+	req := map[string]string{"uuid": uuid, "versionId": versionID}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.baseURL+"/api/agent/version", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set(APIKeyHeader, c.apiKey)
+	}
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("version failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var versionResp VersionResponse
+	if err := json.Unmarshal(respBody, &versionResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &versionResp, nil
+}
+
+// CompareVersions fetches two registered versions and compares them from
+// v1 to v2, with the same analysis as Config.DiffLock: added, removed and
+// modified functions, their schema changes and which are breaking. Cloud
+// snapshots hold descriptions, access, entities, schemas, stability and
+// deprecation, so changes to other lock file properties don't show.
+func (c *Client) CompareVersions(uuid, v1, v2 string) (*ontology.LockDiff, error) {
+	old, err := c.Version(uuid, v1)
+	if err != nil {
+		return nil, fmt.Errorf("version %s: %w", v1, err)
+	}
+	new, err := c.Version(uuid, v2)
+	if err != nil {
+		return nil, fmt.Errorf("version %s: %w", v2, err)
+	}
+	diff := ontology.DiffSnapshots(old.OntologyDef.lockSnapshot(), new.OntologyDef.lockSnapshot())
+	diff.HashChanged = old.Version.Hash != new.Version.Hash
+	return diff, nil
+}
+
+// lockSnapshot converts a cloud snapshot to the lock file's form.
+func (s OntologySnapshot) lockSnapshot() ontology.OntologySnapshot {
+	functions := make(map[string]ontology.FunctionShape, len(s.Functions))
+	for name, fn := range s.Functions {
+		shape := ontology.FunctionShape{
+			Description:   fn.Description,
+			Access:        fn.Access,
+			Entities:      fn.Entities,
+			InputsSchema:  fn.InputsSchema,
+			OutputsSchema: fn.OutputsSchema,
+			Stability:     fn.Stability,
+			Deprecated:    fn.Deprecated,
+		}
+		// Lock files omit unset flags
+		if fn.UsesUserContext {
+			shape.UsesUserContext = &fn.UsesUserContext
+		}
+		if fn.UsesOrganizationContext {
+			shape.UsesOrganizationContext = &fn.UsesOrganizationContext
+		}
+		functions[name] = shape
+	}
+	return ontology.OntologySnapshot{
+		Name:         s.Name,
+		AccessGroups: s.AccessGroups,
+		Entities:     s.Entities,
+		Functions:    functions,
+	}
+}
@@ -0,0 +1,175 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// DescriptionsRequest is the request body for description suggestions.
+type DescriptionsRequest struct {
+	UUID        string                        `json:"uuid"`
+	OntologyDef OntologySnapshot              `json:"ontologyDef"`
+	Findings    []ontology.DescriptionFinding `json:"findings"`
+}
+
+// DescriptionsResponse is the response from description suggestions.
+type DescriptionsResponse struct {
+	Success      bool                             `json:"success"`
+	Suggestions  []ontology.DescriptionSuggestion `json:"suggestions,omitempty"`
+	LimitReached bool                             `json:"limitReached,omitempty"`
+	Message      string                           `json:"message,omitempty"`
+}
+
+// ImproveDescriptions asks the AI agent to rewrite the descriptions with
+// findings, given the whole ontology for context.
+func (c *Client) ImproveDescriptions(uuid string, snapshot OntologySnapshot, findings []ontology.DescriptionFinding) (*DescriptionsResponse, error) {
+	req := DescriptionsRequest{
+		UUID:        uuid,
+		OntologyDef: snapshot,
+		Findings:    findings,
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest("POST", c.baseURL+"/api/agent/descriptions", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set(APIKeyHeader, c.apiKey)
+	}
+
+	resp, err := c.do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("descriptions failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var descriptionsResp DescriptionsResponse
+	if err := json.Unmarshal(respBody, &descriptionsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &descriptionsResp, nil
+}
+
+// SuggestDescriptions analyzes the descriptions of config locally (see
+// Config.AnalyzeDescriptions) and, if there are findings, asks ont-run.com
+// for rewrites, which replace the local suggestions. Print the report's
+// Diff to review them.
+func SuggestDescriptions(uuid string, config *ontology.Config, opts ...ClientOption) (*ontology.DescriptionReport, error) {
+	return suggestDescriptions(NewClient(opts...), uuid, config)
+}
+
+func suggestDescriptions(client *Client, uuid string, config *ontology.Config) (*ontology.DescriptionReport, error) {
+	report := config.AnalyzeDescriptions()
+	if len(report.Findings) == 0 {
+		return report, nil
+	}
+
+	resp, err := client.ImproveDescriptions(uuid, ExtractOntologySnapshot(config), report.Findings)
+	if err != nil {
+		return report, err
+	}
+	if !resp.Success {
+		return report, fmt.Errorf("descriptions failed: %s", resp.Message)
+	}
+
+	report.Suggest(resp.Suggestions...)
+	return report, nil
+}
+
+// DescriptionsExitLowScore is returned by DescriptionsCommand.Run when the
+// description score is below --min, so CI can flag ontologies agents will
+// struggle with.
+const DescriptionsExitLowScore = 3
+
+// DescriptionsCommand scores the descriptions of an ontology and prints the
+// proposed rewrites as a diff, for backends to mount as a subcommand of
+// their own binary, like ReviewCommand:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "descriptions" {
+//		os.Exit(cloud.NewDescriptionsCommand(config).Run(os.Args[2:]))
+//	}
+//
+// Its usage is:
+//
+//	descriptions [--local] [--min score]
+//
+// Rewrites come from ont-run.com (see SuggestDescriptions) unless --local
+// is set or the config has no UUID. If ont-run.com can't be reached, the
+// command warns and prints the local proposals, which are only derived from
+// names. Nothing is changed in the config.
+type DescriptionsCommand struct {
+	Config *ontology.Config
+	Client *Client
+	Out    io.Writer
+	Err    io.Writer
+}
+
+// NewDescriptionsCommand returns a descriptions command for config, using
+// the terminal's standard streams.
+func NewDescriptionsCommand(config *ontology.Config, opts ...ClientOption) *DescriptionsCommand {
+	return &DescriptionsCommand{
+		Config: config,
+		Client: NewClient(opts...),
+		Out:    os.Stdout,
+		Err:    os.Stderr,
+	}
+}
+
+// Run runs the command with args and returns its exit code: ReviewExitOK,
+// ReviewExitUsage for usage mistakes or DescriptionsExitLowScore.
+func (d *DescriptionsCommand) Run(args []string) int {
+	fs := flag.NewFlagSet("descriptions", flag.ContinueOnError)
+	fs.SetOutput(d.Err)
+	local := fs.Bool("local", false, "only propose descriptions derived from names")
+	minScore := fs.Int("min", 0, "exit with 3 if the score is below this")
+	if err := fs.Parse(args); err != nil {
+		return ReviewExitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(d.Err, "descriptions: unexpected argument %q\n", fs.Arg(0))
+		return ReviewExitUsage
+	}
+
+	var report *ontology.DescriptionReport
+	if *local || d.Config.UUID == "" {
+		report = d.Config.AnalyzeDescriptions()
+	} else {
+		var err error
+		if report, err = suggestDescriptions(d.Client, d.Config.UUID, d.Config); err != nil {
+			fmt.Fprintf(d.Err, "warning: %v; showing local proposals\n", err)
+		}
+	}
+
+	fmt.Fprint(d.Out, report)
+	if diff := report.Diff(); diff != "" {
+		fmt.Fprintf(d.Out, "\n%s", diff)
+	}
+	if report.Score < *minScore {
+		return DescriptionsExitLowScore
+	}
+	return ReviewExitOK
+}
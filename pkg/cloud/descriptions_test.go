@@ -0,0 +1,86 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func descriptionsConfig() *ontology.Config {
+	return &ontology.Config{
+		Name:         "test",
+		UUID:         "uuid",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Anyone, without signing in"}},
+		Functions: map[string]ontology.Function{
+			"syncOrders": {
+				Description: "Sync orders",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+}
+
+func runDescriptions(ts *httptest.Server, config *ontology.Config, args ...string) (int, string, string) {
+	var out, errOut bytes.Buffer
+	cmd := NewDescriptionsCommand(config, WithBaseURL(ts.URL))
+	cmd.Out, cmd.Err = &out, &errOut
+	code := cmd.Run(args)
+	return code, out.String(), errOut.String()
+}
+
+func TestDescriptionsCommand(t *testing.T) {
+	var requests []DescriptionsRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/agent/descriptions" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		var req DescriptionsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		json.NewEncoder(w).Encode(DescriptionsResponse{
+			Success: true,
+			Suggestions: []ontology.DescriptionSuggestion{{
+				Path:     "functions/syncOrders",
+				Current:  "Sync orders",
+				Proposed: "Copies orders changed since a date from the shop into the warehouse.",
+			}},
+		})
+	}))
+	t.Cleanup(ts.Close)
+
+	code, out, _ := runDescriptions(ts, descriptionsConfig())
+	if code != ReviewExitOK || len(requests) != 1 || requests[0].UUID != "uuid" {
+		t.Fatalf("exit code %d, requests %+v", code, requests)
+	}
+	if !strings.HasPrefix(out, "Description score: ") || !strings.Contains(out, "@@ functions/syncOrders @@\n-Sync orders\n+Copies orders") {
+		t.Errorf("output:\n%s", out)
+	}
+
+	code, out, _ = runDescriptions(ts, descriptionsConfig(), "--local", "--min", "100")
+	if code != DescriptionsExitLowScore || len(requests) != 1 || strings.Contains(out, "Copies orders") {
+		t.Errorf("--local --min 100 = %d, %d requests:\n%s", code, len(requests), out)
+	}
+
+	if code, _, _ := runDescriptions(ts, descriptionsConfig(), "extra"); code != ReviewExitUsage {
+		t.Errorf("extra argument: exit code %d, want %d", code, ReviewExitUsage)
+	}
+}
+
+func TestDescriptionsCommandFallsBackToLocal(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(ts.Close)
+
+	code, out, errOut := runDescriptions(ts, descriptionsConfig())
+	if code != ReviewExitOK || !strings.HasPrefix(out, "Description score: ") || !strings.Contains(errOut, "showing local proposals") {
+		t.Errorf("exit code %d, stderr %q:\n%s", code, errOut, out)
+	}
+}
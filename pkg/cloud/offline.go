@@ -0,0 +1,47 @@
+package cloud
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"sync/atomic"
+)
+
+// OfflineEnvVar is the environment variable that turns on offline mode
+// when set to "1" or "true".
+const OfflineEnvVar = "ONT_OFFLINE"
+
+// ErrOffline is returned by every Client call in offline mode.
+var ErrOffline = errors.New("cloud: offline mode, outbound calls are disabled")
+
+// offline is set by SetOffline.
+var offline atomic.Bool
+
+// SetOffline turns offline mode on or off for the process. In offline
+// mode no Client sends a request, whatever its options, and servers skip
+// cloud registration even if Config.Cloud and Config.UUID are set. The
+// ONT_OFFLINE environment variable turns it on regardless of SetOffline.
+func SetOffline(on bool) {
+	offline.Store(on)
+}
+
+// Offline reports whether offline mode is on.
+func Offline() bool {
+	if offline.Load() {
+		return true
+	}
+	switch os.Getenv(OfflineEnvVar) {
+	case "1", "true":
+		return true
+	}
+	return false
+}
+
+// do sends a request unless offline mode is on. Every Client call goes
+// through it.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if Offline() {
+		return nil, ErrOffline
+	}
+	return c.httpClient.Do(req)
+}
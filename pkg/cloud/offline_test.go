@@ -0,0 +1,71 @@
+package cloud
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestOffline(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"success": true}`))
+	}))
+	defer ts.Close()
+
+	config := &ontology.Config{Name: "test", Cloud: true, UUID: "uuid"}
+	client := NewClient(WithBaseURL(ts.URL), WithAPIKey("key"))
+	calls := map[string]func() error{
+		"Register": func() error { _, err := client.Register("uuid", OntologySnapshot{}); return err },
+		"Chat":     func() error { _, err := client.Chat("uuid", nil, nil); return err },
+		"Versions": func() error { _, err := client.Versions("uuid"); return err },
+		"Version":  func() error { _, err := client.Version("uuid", "v1"); return err },
+		"Review":   func() error { _, err := client.Review("uuid", "v1", "approve", ""); return err },
+		"ImproveDescriptions": func() error {
+			_, err := client.ImproveDescriptions("uuid", OntologySnapshot{}, nil)
+			return err
+		},
+		"RegisterWithCloud": func() error {
+			_, err := RegisterWithCloud("uuid", config, WithBaseURL(ts.URL))
+			return err
+		},
+	}
+
+	for name, enable := range map[string]func(t *testing.T){
+		"SetOffline": func(t *testing.T) {
+			SetOffline(true)
+			t.Cleanup(func() { SetOffline(false) })
+		},
+		"env": func(t *testing.T) { t.Setenv(OfflineEnvVar, "1") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			enable(t)
+			if !Offline() {
+				t.Fatal("Offline() = false")
+			}
+			for name, call := range calls {
+				if err := call(); !errors.Is(err, ErrOffline) {
+					t.Errorf("%s error = %v, want ErrOffline", name, err)
+				}
+			}
+			// Offline mode returns before the registration is scheduled, so
+			// there is nothing to wait for.
+			TryRegisterWithCloud("uuid", config, WithBaseURL(ts.URL))
+			if status, ok := LastRegistration("uuid"); ok {
+				t.Errorf("registration scheduled in offline mode: %+v", status)
+			}
+			if n := requests.Load(); n != 0 {
+				t.Fatalf("%d requests sent in offline mode", n)
+			}
+		})
+	}
+
+	if _, err := client.Versions("uuid"); err != nil || requests.Load() != 1 {
+		t.Errorf("online Versions() error = %v, requests = %d", err, requests.Load())
+	}
+}
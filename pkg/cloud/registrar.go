@@ -0,0 +1,209 @@
+package cloud
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// DefaultRegistrationInterval is the minimum time between two
+// registrations of the same ontology by TryRegisterWithCloud.
+const DefaultRegistrationInterval = time.Minute
+
+// Registration states.
+const (
+	RegistrationPending    = "pending"
+	RegistrationRegistered = "registered"
+	RegistrationFailed     = "failed"
+)
+
+// RegistrationStatus is the outcome of the last registration of an
+// ontology.
+type RegistrationStatus struct {
+	UUID string `json:"uuid"`
+	// Hash is the hash of the registered snapshot.
+	Hash string `json:"hash"`
+	// State is RegistrationPending while the request is in flight.
+	State     string `json:"state"`
+	VersionID string `json:"versionId,omitempty"`
+	Verified  bool   `json:"verified,omitempty"`
+	// Message is the server's warning, e.g. that the free tier limit was reached.
+	Message string `json:"message,omitempty"`
+	// Error is set if the request failed.
+	Error string    `json:"error,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// Registrar registers ontologies in the background, one request at a time
+// per UUID. A snapshot that is already registered or in flight isn't sent
+// again, and registrations of the same UUID are at least an interval apart;
+// a config registered sooner waits, and only the latest waiting config is
+// sent. This keeps hot reloads and blue-green swaps from racing to
+// register the same ontology.
+type Registrar struct {
+	interval time.Duration
+	// register sends a registration; tests replace it.
+	register func(uuid string, snapshot OntologySnapshot, opts ...ClientOption) (*RegistrationResult, error)
+
+	mu      sync.Mutex
+	entries map[string]*registration
+}
+
+// registration tracks the registrations of one UUID.
+type registration struct {
+	status  RegistrationStatus
+	running bool
+	started time.Time
+	// next waits for the running registration or the interval.
+	next  *pendingRegistration
+	timer *time.Timer
+}
+
+type pendingRegistration struct {
+	snapshot OntologySnapshot
+	hash     string
+	opts     []ClientOption
+}
+
+// NewRegistrar returns a registrar that registers each UUID at most once
+// per interval.
+func NewRegistrar(interval time.Duration) *Registrar {
+	return &Registrar{
+		interval: interval,
+		register: func(uuid string, snapshot OntologySnapshot, opts ...ClientOption) (*RegistrationResult, error) {
+			return NewClient(opts...).Register(uuid, snapshot)
+		},
+		entries: map[string]*registration{},
+	}
+}
+
+// registrar is the process-wide registrar used by TryRegisterWithCloud.
+var registrar = NewRegistrar(DefaultRegistrationInterval)
+
+// LastRegistration returns the status of the last registration of uuid by
+// TryRegisterWithCloud, if there was one.
+func LastRegistration(uuid string) (RegistrationStatus, bool) {
+	return registrar.Status(uuid)
+}
+
+// Register registers config as uuid in the background. It never blocks.
+func (r *Registrar) Register(uuid string, config *ontology.Config, opts ...ClientOption) {
+	snapshot := ExtractOntologySnapshot(config)
+	pending := &pendingRegistration{snapshot: snapshot, hash: computeSnapshotHash(snapshot), opts: opts}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[uuid]
+	if !ok {
+		e = &registration{}
+		r.entries[uuid] = e
+	}
+	if e.registers(pending.hash) {
+		// Already sent; drop any older config waiting to replace it
+		e.next = nil
+		return
+	}
+	e.next = pending
+	r.schedule(uuid, e)
+}
+
+// Status returns the status of the last registration of uuid, if there
+// was one.
+func (r *Registrar) Status(uuid string) (RegistrationStatus, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entries[uuid]
+	if !ok || e.status.UUID == "" {
+		return RegistrationStatus{}, false
+	}
+	return e.status, true
+}
+
+// registers reports whether the snapshot with hash is registered or in
+// flight.
+func (e *registration) registers(hash string) bool {
+	return e.status.Hash == hash && (e.status.State == RegistrationPending || e.status.State == RegistrationRegistered)
+}
+
+// schedule starts the waiting registration of uuid, or sets a timer for
+// when the interval since the last one has passed. r.mu must be held.
+func (r *Registrar) schedule(uuid string, e *registration) {
+	if e.next == nil || e.running || e.timer != nil {
+		return
+	}
+	if wait := r.interval - time.Since(e.started); !e.started.IsZero() && wait > 0 {
+		e.timer = time.AfterFunc(wait, func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			e.timer = nil
+			r.schedule(uuid, e)
+		})
+		return
+	}
+
+	next := e.next
+	e.next = nil
+	e.running = true
+	e.started = time.Now()
+	e.status = RegistrationStatus{UUID: uuid, Hash: next.hash, State: RegistrationPending, At: e.started}
+	go r.run(uuid, e, next)
+}
+
+// run sends a registration and records its outcome.
+func (r *Registrar) run(uuid string, e *registration, p *pendingRegistration) {
+	result, err := r.register(uuid, p.snapshot, p.opts...)
+	logRegistration(result, err)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.running = false
+	e.status.At = time.Now()
+	switch {
+	case err != nil:
+		e.status.State, e.status.Error = RegistrationFailed, err.Error()
+	case result == nil || !result.Success:
+		e.status.State = RegistrationFailed
+		if result != nil {
+			e.status.Message = result.Message
+		}
+	default:
+		e.status.State = RegistrationRegistered
+		e.status.VersionID, e.status.Verified, e.status.Message = result.VersionID, result.Verified, result.Message
+	}
+	if e.next != nil && e.registers(e.next.hash) {
+		e.next = nil
+	}
+	r.schedule(uuid, e)
+}
+
+// logRegistration logs the outcome of a registration.
+func logRegistration(result *RegistrationResult, err error) {
+	if err != nil {
+		log.Printf("[cloud] Registration failed: %v", err)
+		return
+	}
+
+	if result == nil {
+		return
+	}
+
+	if result.Success {
+		if result.Verified {
+			log.Printf("[cloud] Registered successfully (verified, hash: %s)", result.Hash)
+		} else {
+			log.Printf("[cloud] Registered successfully (anonymous, hash: %s)", result.Hash)
+		}
+
+		if result.VersionID != "" {
+			log.Printf("[cloud] New version created: %s", result.VersionID)
+		}
+	} else {
+		if result.LimitReached {
+			log.Printf("[cloud] Warning: Free tier limit reached. Run 'npx ont-run login' to upgrade.")
+		} else if result.Message != "" {
+			log.Printf("[cloud] Registration warning: %s", result.Message)
+		}
+	}
+}
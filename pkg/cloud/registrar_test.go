@@ -0,0 +1,70 @@
+package cloud
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestRegistrar(t *testing.T) {
+	var sent atomic.Int32
+	release := make(chan struct{})
+	r := NewRegistrar(50 * time.Millisecond)
+	r.register = func(uuid string, snapshot OntologySnapshot, opts ...ClientOption) (*RegistrationResult, error) {
+		sent.Add(1)
+		<-release
+		return &RegistrationResult{Success: true, VersionID: snapshot.Name}, nil
+	}
+	config := func(name string) *ontology.Config {
+		return &ontology.Config{Name: name}
+	}
+	waitFor := func(state, versionID string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if status, ok := r.Status("uuid"); ok && status.State == state && status.VersionID == versionID {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		status, _ := r.Status("uuid")
+		t.Fatalf("Status() = %+v, want %s %s", status, state, versionID)
+	}
+
+	// Concurrent registrations of the same config send one request
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Register("uuid", config("v1"))
+		}()
+	}
+	wg.Wait()
+	if status, _ := r.Status("uuid"); status.State != RegistrationPending {
+		t.Errorf("Status() = %+v, want pending", status)
+	}
+
+	// Configs registered meanwhile wait; only the latest is sent
+	r.Register("uuid", config("v2"))
+	r.Register("uuid", config("v3"))
+	close(release)
+	waitFor(RegistrationRegistered, "v1")
+	waitFor(RegistrationRegistered, "v3")
+	if n := sent.Load(); n != 2 {
+		t.Errorf("sent %d registrations, want 2", n)
+	}
+
+	// Registering the current config again sends nothing
+	r.Register("uuid", config("v3"))
+	time.Sleep(80 * time.Millisecond)
+	if n := sent.Load(); n != 2 {
+		t.Errorf("sent %d registrations after re-registering, want 2", n)
+	}
+	if _, ok := r.Status("other"); ok {
+		t.Error("Status() of an unregistered UUID is set")
+	}
+}
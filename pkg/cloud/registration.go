@@ -35,13 +35,31 @@ func ExtractOntologySnapshot(config *ontology.Config) OntologySnapshot {
 		copy(fnEntities, fn.Entities)
 		sort.Strings(fnEntities)
 
-		functions[name] = FunctionShape{
+		shape := FunctionShape{
 			Description:   fn.Description,
 			Access:        access,
 			Entities:      fnEntities,
-			InputsSchema:  fn.Inputs.JSONSchema(),
-			OutputsSchema: fn.Outputs.JSONSchema(),
+			InputsSchema:  config.JSONSchemaFor(fn.Inputs),
+			OutputsSchema: config.JSONSchemaFor(fn.Outputs),
+			UI:            fn.UI,
+			IsReadOnly:    fn.IsReadOnly,
+			Stability:     fn.Stability,
+			Deprecated:    fn.Deprecated,
+
+			UsesUserContext:         fn.UsesUserContext,
+			UsesOrganizationContext: fn.UsesOrganizationContext,
 		}
+		if len(fn.Tags) > 0 {
+			shape.Tags = append([]string(nil), fn.Tags...)
+			sort.Strings(shape.Tags)
+		}
+		if fn.Inputs != nil {
+			shape.SensitiveInputs = ontology.SensitiveFields(fn.Inputs)
+		}
+		if fn.Outputs != nil {
+			shape.SensitiveOutputs = ontology.SensitiveFields(fn.Outputs)
+		}
+		functions[name] = shape
 	}
 
 	return OntologySnapshot{
@@ -67,39 +85,16 @@ func RegisterWithCloud(uuid string, config *ontology.Config, opts ...ClientOptio
 
 // TryRegisterWithCloud attempts to register the ontology with ont-run.com.
 // This function never blocks and logs errors instead of returning them.
-// It's designed to be called at server startup.
+// It's designed to be called at server startup, and is safe to call again
+// on reload: repeated calls are deduplicated and rate-limited (see
+// Registrar), and LastRegistration reports the outcome.
 func TryRegisterWithCloud(uuid string, config *ontology.Config, opts ...ClientOption) {
 	if uuid == "" {
 		return // No UUID means no cloud registration
 	}
-
-	go func() {
-		result, err := RegisterWithCloud(uuid, config, opts...)
-		if err != nil {
-			log.Printf("[cloud] Registration failed: %v", err)
-			return
-		}
-
-		if result == nil {
-			return
-		}
-
-		if result.Success {
-			if result.Verified {
-				log.Printf("[cloud] Registered successfully (verified, hash: %s)", result.Hash)
-			} else {
-				log.Printf("[cloud] Registered successfully (anonymous, hash: %s)", result.Hash)
-			}
-
-			if result.VersionID != "" {
-				log.Printf("[cloud] New version created: %s", result.VersionID)
-			}
-		} else {
-			if result.LimitReached {
-				log.Printf("[cloud] Warning: Free tier limit reached. Run 'npx ont-run login' to upgrade.")
-			} else if result.Message != "" {
-				log.Printf("[cloud] Registration warning: %s", result.Message)
-			}
-		}
-	}()
+	if Offline() {
+		log.Printf("[cloud] Offline mode: skipping registration")
+		return
+	}
+	registrar.Register(uuid, config, opts...)
 }
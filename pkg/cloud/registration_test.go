@@ -0,0 +1,67 @@
+package cloud
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestExtractOntologySnapshot(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "shop",
+		AccessGroups: map[string]ontology.AccessGroup{"admin": {Description: "Admins"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"chargeCard": {
+				Description: "Charge a card",
+				Access:      []string{"admin"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"cardNumber": ontology.String().Sensitive()}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				UI:          &ontology.UiConfig{Type: "table"},
+				Tags:        []string{"payments", "billing"},
+				Stability:   ontology.StabilityBeta,
+				Deprecated:  "use createCharge",
+
+				UsesUserContext: true,
+			},
+			"getOrder": {
+				Description: "Get an order",
+				Access:      []string{"admin"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				IsReadOnly:  true,
+			},
+		},
+	}
+	snapshot := ExtractOntologySnapshot(config)
+
+	charge := snapshot.Functions["chargeCard"]
+	if charge.UI == nil || charge.UI.Type != "table" || charge.Stability != ontology.StabilityBeta || charge.Deprecated != "use createCharge" {
+		t.Errorf("chargeCard = %+v", charge)
+	}
+	if !charge.UsesUserContext || charge.UsesOrganizationContext {
+		t.Errorf("UsesUserContext = %v, UsesOrganizationContext = %v", charge.UsesUserContext, charge.UsesOrganizationContext)
+	}
+	// The lock form records set flags and omits unset ones
+	locked := snapshot.lockSnapshot().Functions["chargeCard"]
+	if locked.UsesUserContext == nil || !*locked.UsesUserContext || locked.UsesOrganizationContext != nil {
+		t.Errorf("locked UsesUserContext = %v, UsesOrganizationContext = %v", locked.UsesUserContext, locked.UsesOrganizationContext)
+	}
+	if !reflect.DeepEqual(charge.Tags, []string{"billing", "payments"}) {
+		t.Errorf("Tags = %v", charge.Tags)
+	}
+	if !reflect.DeepEqual(charge.SensitiveInputs, []string{"/cardNumber"}) || len(charge.SensitiveOutputs) != 0 {
+		t.Errorf("SensitiveInputs = %v, SensitiveOutputs = %v", charge.SensitiveInputs, charge.SensitiveOutputs)
+	}
+
+	// Functions without the metadata serialize as before, keeping their hash
+	data, _ := json.Marshal(snapshot.Functions["getOrder"])
+	for _, key := range []string{"ui", "tags", "stability", "deprecated", "sensitiveInputs", "sensitiveOutputs"} {
+		if strings.Contains(string(data), `"`+key+`"`) {
+			t.Errorf("getOrder snapshot has %q: %s", key, data)
+		}
+	}
+}
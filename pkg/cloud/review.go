@@ -0,0 +1,303 @@
+package cloud
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// Exit codes returned by ReviewCommand.Run.
+const (
+	ReviewExitOK    = 0
+	ReviewExitError = 1
+	ReviewExitUsage = 2
+	// ReviewExitBreaking means a listed or compared pending version has
+	// breaking changes, so CI can hold a deploy until it is reviewed.
+	ReviewExitBreaking = 3
+)
+
+// ReviewCommand runs the cloud review workflow from a terminal or CI, for
+// backends to mount as a subcommand of their own binary:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "review" {
+//		os.Exit(cloud.NewReviewCommand(config.UUID).Run(os.Args[2:]))
+//	}
+//
+// Its commands are:
+//
+//	list [--status pending,approved,rejected]
+//	diff <version> [--against <version>]
+//	approve <version> [-m comment] [-y]
+//	reject <version> [-m comment]
+//
+// Pending versions are compared with the latest approved version before
+// them, with the same analysis as CompareVersions.
+type ReviewCommand struct {
+	UUID   string
+	Client *Client
+	// In answers the approval prompt.
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// NewReviewCommand returns a review command for the ontology registered as
+// uuid, using the terminal's standard streams.
+func NewReviewCommand(uuid string, opts ...ClientOption) *ReviewCommand {
+	return &ReviewCommand{
+		UUID:   uuid,
+		Client: NewClient(opts...),
+		In:     os.Stdin,
+		Out:    os.Stdout,
+		Err:    os.Stderr,
+	}
+}
+
+// Run runs the command in args and returns its exit code.
+func (r *ReviewCommand) Run(args []string) int {
+	if len(args) == 0 {
+		r.usage()
+		return ReviewExitUsage
+	}
+	switch args[0] {
+	case "list":
+		return r.list(args[1:])
+	case "diff":
+		return r.diff(args[1:])
+	case "approve":
+		return r.review(args[1:], "approve")
+	case "reject":
+		return r.review(args[1:], "reject")
+	case "help", "-h", "--help":
+		r.usage()
+		return ReviewExitOK
+	}
+	fmt.Fprintf(r.Err, "unknown command %q\n", args[0])
+	r.usage()
+	return ReviewExitUsage
+}
+
+func (r *ReviewCommand) usage() {
+	fmt.Fprint(r.Err, `Usage: review <command> [arguments]
+
+Commands:
+  list [--status pending,approved,rejected]  list versions and flag breaking pending ones
+  diff <version> [--against <version>]       compare a version with another, by default the latest approved one
+  approve <version> [-m comment] [-y]        approve a version after confirming its changes
+  reject <version> [-m comment]              reject a version
+`)
+}
+
+func (r *ReviewCommand) list(args []string) int {
+	fs := r.flags("list")
+	status := fs.String("status", "", "comma-separated statuses to list")
+	if _, code := r.parse(fs, args, 0); code != ReviewExitOK {
+		return code
+	}
+
+	history, err := r.history()
+	if err != nil {
+		return r.fail(err)
+	}
+
+	code := ReviewExitOK
+	tw := tabwriter.NewWriter(r.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tCREATED\tHASH\tVERIFIED\tBREAKING")
+	for _, version := range history {
+		if *status != "" && !contains(strings.Split(*status, ","), version.Status) {
+			continue
+		}
+		breaking := "-"
+		if version.Status == VersionPending {
+			diff, _, err := r.compare(history, version.ID, "")
+			if err != nil {
+				return r.fail(err)
+			}
+			if diff != nil && len(diff.BreakingFunctions()) > 0 {
+				breaking = strings.Join(diff.BreakingFunctions(), ",")
+				code = ReviewExitBreaking
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%s\n", version.ID, version.Status, version.CreatedAt, version.Hash, version.Verified, breaking)
+	}
+	tw.Flush()
+	return code
+}
+
+func (r *ReviewCommand) diff(args []string) int {
+	fs := r.flags("diff")
+	against := fs.String("against", "", "version to compare with (default: the latest approved before it)")
+	positional, code := r.parse(fs, args, 1)
+	if code != ReviewExitOK {
+		return code
+	}
+
+	history, err := r.history()
+	if err != nil {
+		return r.fail(err)
+	}
+	diff, base, err := r.compare(history, positional[0], *against)
+	if err != nil {
+		return r.fail(err)
+	}
+	if diff == nil {
+		fmt.Fprintf(r.Out, "No approved version before %s to compare with\n", positional[0])
+		return ReviewExitOK
+	}
+	r.printDiff(diff, base, positional[0])
+	if len(diff.BreakingFunctions()) > 0 {
+		return ReviewExitBreaking
+	}
+	return ReviewExitOK
+}
+
+func (r *ReviewCommand) review(args []string, action string) int {
+	fs := r.flags(action)
+	comment := fs.String("m", "", "review comment")
+	yes := false
+	if action == "approve" {
+		fs.BoolVar(&yes, "y", false, "approve without confirming")
+	}
+	positional, code := r.parse(fs, args, 1)
+	if code != ReviewExitOK {
+		return code
+	}
+	versionID := positional[0]
+
+	if action == "approve" && !yes {
+		history, err := r.history()
+		if err != nil {
+			return r.fail(err)
+		}
+		diff, base, err := r.compare(history, versionID, "")
+		if err != nil {
+			return r.fail(err)
+		}
+		if diff != nil {
+			r.printDiff(diff, base, versionID)
+		}
+		fmt.Fprintf(r.Out, "Approve version %s? [y/N] ", versionID)
+		answer, _ := bufio.NewReader(r.In).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(r.Out, "Not approved")
+			return ReviewExitError
+		}
+	}
+
+	resp, err := r.Client.Review(r.UUID, versionID, action, *comment)
+	if err != nil {
+		return r.fail(err)
+	}
+	if !resp.Success {
+		return r.fail(fmt.Errorf("%s %s: %s", action, versionID, resp.Message))
+	}
+	if action == "approve" {
+		fmt.Fprintf(r.Out, "Approved version %s\n", versionID)
+	} else {
+		fmt.Fprintf(r.Out, "Rejected version %s\n", versionID)
+	}
+	return ReviewExitOK
+}
+
+// history returns every version, oldest first.
+func (r *ReviewCommand) history() ([]VersionEntry, error) {
+	var history []VersionEntry
+	for version, err := range r.Client.AllVersions(r.UUID, VersionsQuery{}) {
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, version)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].CreatedAt < history[j].CreatedAt
+	})
+	return history, nil
+}
+
+// compare compares versionID with base, or with the latest approved
+// version before it if base is empty. The diff is nil if there is no such
+// version.
+func (r *ReviewCommand) compare(history []VersionEntry, versionID, base string) (*ontology.LockDiff, string, error) {
+	if base == "" {
+		found := false
+		for _, version := range history {
+			if version.ID == versionID {
+				found = true
+				break
+			}
+			if version.Status == VersionApproved {
+				base = version.ID
+			}
+		}
+		if !found {
+			return nil, "", fmt.Errorf("version %s not found", versionID)
+		}
+		if base == "" {
+			return nil, "", nil
+		}
+	}
+	diff, err := r.Client.CompareVersions(r.UUID, base, versionID)
+	if err != nil {
+		return nil, "", err
+	}
+	return diff, base, nil
+}
+
+func (r *ReviewCommand) printDiff(diff *ontology.LockDiff, base, versionID string) {
+	fmt.Fprintf(r.Out, "Changes from %s to %s:\n%s", base, versionID, diff.String())
+	if !strings.HasSuffix(diff.String(), "\n") {
+		fmt.Fprintln(r.Out)
+	}
+	if breaking := diff.BreakingFunctions(); len(breaking) > 0 {
+		fmt.Fprintf(r.Out, "Breaking changes: %s\n", strings.Join(breaking, ", "))
+	}
+}
+
+func (r *ReviewCommand) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(r.Err)
+	return fs
+}
+
+// parse parses flags before and after the positional arguments, so both
+// "approve -y v2" and "approve v2 -y" work, and checks that there are n
+// positional arguments.
+func (r *ReviewCommand) parse(fs *flag.FlagSet, args []string, n int) ([]string, int) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, ReviewExitUsage
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != n {
+		fmt.Fprintf(r.Err, "%s: expected %d argument(s), got %d\n", fs.Name(), n, len(positional))
+		return nil, ReviewExitUsage
+	}
+	return positional, ReviewExitOK
+}
+
+func (r *ReviewCommand) fail(err error) int {
+	fmt.Fprintf(r.Err, "error: %v\n", err)
+	return ReviewExitError
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
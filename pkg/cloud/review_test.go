@@ -0,0 +1,125 @@
+package cloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func reviewServer(t *testing.T, reviews *[]ReviewRequest) *httptest.Server {
+	snapshot := func(outputs ontology.Schema) OntologySnapshot {
+		return ExtractOntologySnapshot(&ontology.Config{
+			Name:         "test",
+			AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+			Functions: map[string]ontology.Function{
+				"getUser": {
+					Description: "Get a user",
+					Access:      []string{"public"},
+					Inputs:      ontology.Object(map[string]ontology.Schema{}),
+					Outputs:     outputs,
+				},
+			},
+		})
+	}
+	versions := []VersionEntry{
+		{ID: "v2", Hash: "h2", CreatedAt: "2026-02-01T00:00:00Z", Status: VersionPending},
+		{ID: "v1", Hash: "h1", CreatedAt: "2026-01-01T00:00:00Z", Status: VersionApproved},
+	}
+	snapshots := map[string]OntologySnapshot{
+		"v1": snapshot(ontology.Object(map[string]ontology.Schema{"name": ontology.String(), "email": ontology.String()})),
+		"v2": snapshot(ontology.Object(map[string]ontology.Schema{"name": ontology.String()})),
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/agent/versions":
+			json.NewEncoder(w).Encode(VersionsResponse{Success: true, Versions: versions})
+		case "/api/agent/version":
+			var req map[string]string
+			json.NewDecoder(r.Body).Decode(&req)
+			for _, v := range versions {
+				if v.ID == req["versionId"] {
+					json.NewEncoder(w).Encode(VersionResponse{Success: true, Version: v, OntologyDef: snapshots[v.ID]})
+					return
+				}
+			}
+			http.NotFound(w, r)
+		case "/api/agent/review":
+			var req ReviewRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			*reviews = append(*reviews, req)
+			json.NewEncoder(w).Encode(ReviewResponse{Success: true})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func runReview(ts *httptest.Server, stdin string, args ...string) (int, string, string) {
+	var out, errOut bytes.Buffer
+	cmd := NewReviewCommand("uuid", WithBaseURL(ts.URL))
+	cmd.In, cmd.Out, cmd.Err = strings.NewReader(stdin), &out, &errOut
+	code := cmd.Run(args)
+	return code, out.String(), errOut.String()
+}
+
+func TestReviewCommand(t *testing.T) {
+	var reviews []ReviewRequest
+	ts := reviewServer(t, &reviews)
+
+	code, out, _ := runReview(ts, "", "list")
+	if code != ReviewExitBreaking {
+		t.Errorf("list exit code = %d, want %d", code, ReviewExitBreaking)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "v1") || !strings.HasPrefix(lines[2], "v2") || !strings.HasSuffix(lines[2], "getUser") {
+		t.Errorf("list output:\n%s", out)
+	}
+
+	code, out, _ = runReview(ts, "", "list", "--status", "approved")
+	if code != ReviewExitOK || strings.Contains(out, "v2") {
+		t.Errorf("list --status approved = %d:\n%s", code, out)
+	}
+
+	code, out, _ = runReview(ts, "", "diff", "v2")
+	if code != ReviewExitBreaking || !strings.Contains(out, "Changes from v1 to v2") || !strings.Contains(out, "Breaking changes: getUser") {
+		t.Errorf("diff = %d:\n%s", code, out)
+	}
+
+	// Declining the prompt doesn't approve
+	code, out, _ = runReview(ts, "n\n", "approve", "v2")
+	if code != ReviewExitError || len(reviews) != 0 || !strings.Contains(out, "Approve version v2? [y/N]") {
+		t.Errorf("declined approve = %d, reviews %v:\n%s", code, reviews, out)
+	}
+
+	code, _, _ = runReview(ts, "y\n", "approve", "v2", "-m", "looks good")
+	if code != ReviewExitOK || len(reviews) != 1 || reviews[0] != (ReviewRequest{UUID: "uuid", VersionID: "v2", Action: "approve", Comment: "looks good"}) {
+		t.Errorf("approve = %d, reviews %v", code, reviews)
+	}
+
+	code, _, _ = runReview(ts, "", "reject", "-m", "no", "v2")
+	if code != ReviewExitOK || len(reviews) != 2 || reviews[1].Action != "reject" || reviews[1].Comment != "no" {
+		t.Errorf("reject = %d, reviews %v", code, reviews)
+	}
+}
+
+func TestReviewCommandUsage(t *testing.T) {
+	var reviews []ReviewRequest
+	ts := reviewServer(t, &reviews)
+
+	for _, args := range [][]string{nil, {"unknown"}, {"approve"}, {"diff", "v1", "v2"}, {"list", "--bogus"}} {
+		if code, _, _ := runReview(ts, "", args...); code != ReviewExitUsage {
+			t.Errorf("Run(%q) = %d, want %d", args, code, ReviewExitUsage)
+		}
+	}
+	if code, _, errOut := runReview(ts, "", "diff", "v9"); code != ReviewExitError || !strings.Contains(errOut, "version v9 not found") {
+		t.Errorf("diff v9 = %d: %s", code, errOut)
+	}
+}
@@ -0,0 +1,81 @@
+package cloud
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+)
+
+// transportConfig holds the transport options, applied to the HTTP
+// client's transport once all options are set.
+type transportConfig struct {
+	proxy         *url.URL
+	rootCAs       *x509.CertPool
+	tlsMinVersion uint16
+}
+
+// WithProxy sends requests through the proxy at proxyURL, e.g.
+// "http://proxy.corp:3128". By default the client uses the proxy named by
+// the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *Client) {
+		c.transport.proxy = proxyURL
+	}
+}
+
+// WithRootCAs verifies the server's certificate against pool instead of
+// the system roots, e.g. for a TLS-inspecting proxy with a private CA:
+//
+//	pem, _ := os.ReadFile("/etc/ssl/corp-ca.pem")
+//	pool, _ := x509.SystemCertPool()
+//	pool.AppendCertsFromPEM(pem)
+//	client := cloud.NewClient(cloud.WithRootCAs(pool))
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return func(c *Client) {
+		c.transport.rootCAs = pool
+	}
+}
+
+// WithTLSMinVersion sets the minimum TLS version, e.g. tls.VersionTLS13.
+// The default is Go's, currently TLS 1.2.
+func WithTLSMinVersion(version uint16) ClientOption {
+	return func(c *Client) {
+		c.transport.tlsMinVersion = version
+	}
+}
+
+// applyTransport applies the transport options to a copy of the HTTP
+// client, so a client passed to WithHTTPClient isn't modified. They are
+// ignored if that client's transport isn't an *http.Transport.
+func (c *Client) applyTransport() {
+	cfg := c.transport
+	if cfg.proxy == nil && cfg.rootCAs == nil && cfg.tlsMinVersion == 0 {
+		return
+	}
+	base, ok := c.httpClient.Transport.(*http.Transport)
+	if c.httpClient.Transport == nil {
+		base, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return
+	}
+	transport := base.Clone()
+	if cfg.proxy != nil {
+		transport.Proxy = http.ProxyURL(cfg.proxy)
+	}
+	if cfg.rootCAs != nil || cfg.tlsMinVersion != 0 {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		if cfg.rootCAs != nil {
+			transport.TLSClientConfig.RootCAs = cfg.rootCAs
+		}
+		if cfg.tlsMinVersion != 0 {
+			transport.TLSClientConfig.MinVersion = cfg.tlsMinVersion
+		}
+	}
+	client := *c.httpClient
+	client.Transport = transport
+	c.httpClient = &client
+}
@@ -0,0 +1,57 @@
+package cloud
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func versionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"success": true}`))
+}
+
+func TestWithProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		versionsHandler(w, r)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	client := NewClient(WithBaseURL("http://ont-run.invalid"), WithProxy(proxyURL))
+	if _, err := client.Versions("uuid"); err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+	if proxied != "http://ont-run.invalid/api/agent/versions" {
+		t.Errorf("proxy received %q", proxied)
+	}
+}
+
+func TestWithRootCAs(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(versionsHandler))
+	ts.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	ts.StartTLS()
+	defer ts.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	if _, err := NewClient(WithBaseURL(ts.URL)).Versions("uuid"); err == nil {
+		t.Error("Versions() with system roots succeeded")
+	}
+	if _, err := NewClient(WithBaseURL(ts.URL), WithRootCAs(pool)).Versions("uuid"); err != nil {
+		t.Errorf("Versions() with root CAs error = %v", err)
+	}
+	if _, err := NewClient(WithBaseURL(ts.URL), WithRootCAs(pool), WithTLSMinVersion(tls.VersionTLS13)).Versions("uuid"); err == nil {
+		t.Error("Versions() below the minimum TLS version succeeded")
+	}
+
+	httpClient := &http.Client{}
+	NewClient(WithHTTPClient(httpClient), WithRootCAs(pool))
+	if httpClient.Transport != nil {
+		t.Error("WithRootCAs modified the client passed to WithHTTPClient")
+	}
+}
@@ -0,0 +1,79 @@
+package cloud
+
+import (
+	"iter"
+	"time"
+)
+
+// Version statuses.
+const (
+	VersionPending  = "pending"
+	VersionApproved = "approved"
+	VersionRejected = "rejected"
+)
+
+// VersionsQuery selects a page of versions. The zero value selects every
+// version, in one page.
+type VersionsQuery struct {
+	// Limit is the page size; zero leaves it to the server.
+	Limit int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+	// Status keeps versions with one of these statuses, e.g. VersionPending.
+	Status []string
+	// Since and Until keep versions created in [Since, Until). Zero values
+	// leave that end open.
+	Since time.Time
+	Until time.Time
+}
+
+// versionsRequest is the request body for versions.
+type versionsRequest struct {
+	UUID   string   `json:"uuid"`
+	Limit  int      `json:"limit,omitempty"`
+	Cursor string   `json:"cursor,omitempty"`
+	Status []string `json:"status,omitempty"`
+	Since  string   `json:"since,omitempty"`
+	Until  string   `json:"until,omitempty"`
+}
+
+func (q VersionsQuery) request(uuid string) versionsRequest {
+	req := versionsRequest{UUID: uuid, Limit: q.Limit, Cursor: q.Cursor, Status: q.Status}
+	if !q.Since.IsZero() {
+		req.Since = q.Since.UTC().Format(time.RFC3339)
+	}
+	if !q.Until.IsZero() {
+		req.Until = q.Until.UTC().Format(time.RFC3339)
+	}
+	return req
+}
+
+// AllVersions iterates over every version matching query, fetching pages
+// as it goes. Iteration stops after yielding an error.
+//
+//	for version, err := range client.AllVersions(uuid, cloud.VersionsQuery{Status: []string{cloud.VersionPending}}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(version.ID)
+//	}
+func (c *Client) AllVersions(uuid string, query VersionsQuery) iter.Seq2[VersionEntry, error] {
+	return func(yield func(VersionEntry, error) bool) {
+		for {
+			page, err := c.ListVersions(uuid, query)
+			if err != nil {
+				yield(VersionEntry{}, err)
+				return
+			}
+			for _, version := range page.Versions {
+				if !yield(version, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" || page.NextCursor == query.Cursor {
+				return
+			}
+			query.Cursor = page.NextCursor
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package cloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAllVersions(t *testing.T) {
+	var requests []versionsRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req versionsRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+
+		// Five versions, two per page
+		start, _ := strconv.Atoi(req.Cursor)
+		resp := VersionsResponse{Success: true, Total: 5}
+		for i := start; i < start+req.Limit && i < 5; i++ {
+			resp.Versions = append(resp.Versions, VersionEntry{ID: strconv.Itoa(i), Status: VersionPending})
+		}
+		if start+req.Limit < 5 {
+			resp.NextCursor = strconv.Itoa(start + req.Limit)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer ts.Close()
+
+	client := NewClient(WithBaseURL(ts.URL))
+	query := VersionsQuery{
+		Limit:  2,
+		Status: []string{VersionPending},
+		Since:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	var ids []string
+	for version, err := range client.AllVersions("uuid", query) {
+		if err != nil {
+			t.Fatalf("AllVersions() error = %v", err)
+		}
+		ids = append(ids, version.ID)
+	}
+	if len(ids) != 5 || ids[4] != "4" {
+		t.Errorf("AllVersions() = %v", ids)
+	}
+	if len(requests) != 3 || requests[2].Cursor != "4" {
+		t.Fatalf("requests = %+v", requests)
+	}
+	if req := requests[0]; req.UUID != "uuid" || req.Status[0] != VersionPending || req.Since != "2026-01-01T00:00:00Z" || req.Until != "" {
+		t.Errorf("request = %+v", req)
+	}
+
+	// Stopping early fetches no further pages
+	requests = nil
+	for range client.AllVersions("uuid", query) {
+		break
+	}
+	if len(requests) != 1 {
+		t.Errorf("requests after break = %d", len(requests))
+	}
+}
@@ -0,0 +1,262 @@
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// CBOR major types.
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+type cborCodec struct{}
+
+// CBOR returns the codec for application/cbor (RFC 8949). Integers are
+// encoded in their smallest form and map keys are sorted. Decoding accepts
+// half, single and double floats and skips tags; indefinite-length items
+// are rejected.
+func CBOR() Codec {
+	return cborCodec{}
+}
+
+func (cborCodec) ContentType() string {
+	return "application/cbor"
+}
+
+func (cborCodec) Marshal(v any) ([]byte, error) {
+	value, err := jsonValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+	var buf []byte
+	if buf, err = appendCBOR(buf, value); err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+	return buf, nil
+}
+
+func (cborCodec) Unmarshal(data []byte) (any, error) {
+	d := &cborDecoder{data: data}
+	v, err := d.value(0)
+	if err == nil && d.pos != len(d.data) {
+		err = fmt.Errorf("%d bytes of trailing data", len(d.data)-d.pos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cbor: %w", err)
+	}
+	return v, nil
+}
+
+func appendCBOR(buf []byte, v any) ([]byte, error) {
+	var err error
+	switch t := v.(type) {
+	case nil:
+		return append(buf, 0xf6), nil
+	case bool:
+		if t {
+			return append(buf, 0xf5), nil
+		}
+		return append(buf, 0xf4), nil
+	case float64:
+		if i, ok := integer(t); ok {
+			return appendCBORInt(buf, i), nil
+		}
+		buf = append(buf, 0xfb)
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(t)), nil
+	case int64:
+		return appendCBORInt(buf, t), nil
+	case string:
+		return append(appendCBORHead(buf, cborText, uint64(len(t))), t...), nil
+	case []byte:
+		return append(appendCBORHead(buf, cborBytes, uint64(len(t))), t...), nil
+	case []any:
+		buf = appendCBORHead(buf, cborArray, uint64(len(t)))
+		for _, item := range t {
+			if buf, err = appendCBOR(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		buf = appendCBORHead(buf, cborMap, uint64(len(t)))
+		for _, k := range sortedKeys(t) {
+			buf, _ = appendCBOR(buf, k)
+			if buf, err = appendCBOR(buf, t[k]); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, unsupportedType(v)
+}
+
+func appendCBORInt(buf []byte, i int64) []byte {
+	if i < 0 {
+		return appendCBORHead(buf, cborNegInt, uint64(-1-i))
+	}
+	return appendCBORHead(buf, cborUint, uint64(i))
+}
+
+// appendCBORHead writes a major type and its argument in the shortest form.
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) next(n uint64) ([]byte, error) {
+	if err := checkLength(n, len(d.data)-d.pos); err != nil {
+		return nil, err
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// head reads an item's major type, additional information and argument.
+func (d *cborDecoder) head() (major, info byte, arg uint64, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major, info = b[0]>>5, b[0]&0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size := uint64(1) << (info - 24)
+		raw, err := d.next(size)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, c := range raw {
+			arg = arg<<8 | uint64(c)
+		}
+		return major, info, arg, nil
+	case info == 31:
+		return 0, 0, 0, fmt.Errorf("indefinite-length items are not supported")
+	}
+	return 0, 0, 0, fmt.Errorf("invalid additional information %d", info)
+}
+
+func (d *cborDecoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("nesting exceeds %d levels", maxDepth)
+	}
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborUint:
+		return decodedUint(arg), nil
+	case cborNegInt:
+		if arg > math.MaxInt64 {
+			return -float64(arg) - 1, nil
+		}
+		return decodedInt(-1 - int64(arg)), nil
+	case cborBytes:
+		b, err := d.next(arg)
+		return append([]byte{}, b...), err
+	case cborText:
+		b, err := d.next(arg)
+		return string(b), err
+	case cborArray:
+		if err := checkLength(arg, len(d.data)-d.pos); err != nil {
+			return nil, err
+		}
+		arr := make([]any, arg)
+		for i := range arr {
+			if arr[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	case cborMap:
+		if err := checkLength(2*arg, len(d.data)-d.pos); err != nil {
+			return nil, err
+		}
+		obj := make(map[string]any, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key must be a string, got %T", key)
+			}
+			if obj[name], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	case cborTag:
+		// Tags only annotate the value that follows
+		return d.value(depth + 1)
+	}
+
+	// Simple values and floats
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		return halfFloat(uint16(arg)), nil
+	case 26:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case 27:
+		return math.Float64frombits(arg), nil
+	}
+	return nil, fmt.Errorf("unsupported simple value %d", arg)
+}
+
+// halfFloat converts an IEEE 754 half-precision float.
+func halfFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,125 @@
+// Package codec encodes API values in binary formats, MessagePack and CBOR,
+// for callers that want smaller payloads than JSON such as mobile apps and
+// high-volume machine clients.
+//
+// Codecs work on the JSON data model: nil, bool, float64, string, []any and
+// map[string]any, plus int64 for integers beyond ±2^53 and []byte. Marshal
+// converts other values (structs, typed slices, time.Time) through
+// encoding/json first, so every format carries exactly what the JSON API
+// would. Unmarshal returns the same types ontology.DecodeJSON does, so
+// decoded requests validate against the same schemas.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// Codec encodes and decodes values in one format.
+type Codec interface {
+	// ContentType is the media type the codec is negotiated by, e.g.
+	// "application/msgpack".
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte) (any, error)
+}
+
+// maxDepth bounds the nesting of decoded values, so a hostile payload can't
+// exhaust the stack.
+const maxDepth = 1000
+
+// maxExactFloat is the largest integer float64 holds exactly (2^53).
+const maxExactFloat = 1 << 53
+
+var errTruncated = errors.New("unexpected end of data")
+
+// jsonValue converts v to the JSON data model, passing through values that
+// are already in it.
+func jsonValue(v any) (any, error) {
+	switch t := v.(type) {
+	case nil, bool, float64, int64, string, []byte:
+		return v, nil
+	case int:
+		return int64(t), nil
+	case []any:
+		out := make([]any, len(t))
+		for i, item := range t {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, item := range t {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = converted
+		}
+		return out, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return ontology.DecodeJSON(bytes.NewReader(data))
+}
+
+// integer reports whether f is a whole number that can be encoded as an
+// integer.
+func integer(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// decodedInt returns an integer as DecodeJSON would: float64 when exact,
+// int64 beyond ±2^53.
+func decodedInt(i int64) any {
+	if i > maxExactFloat || i < -maxExactFloat {
+		return i
+	}
+	return float64(i)
+}
+
+// decodedUint is decodedInt for unsigned integers.
+func decodedUint(u uint64) any {
+	if u > math.MaxInt64 {
+		return float64(u)
+	}
+	return decodedInt(int64(u))
+}
+
+// sortedKeys returns the keys of m in order, so encodings are deterministic.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkLength rejects a declared length that can't fit in the rest of the
+// input, before anything is allocated for it.
+func checkLength(n uint64, remaining int) error {
+	if n > uint64(remaining) {
+		return errTruncated
+	}
+	return nil
+}
+
+func unsupportedType(v any) error {
+	return fmt.Errorf("unsupported type %T", v)
+}
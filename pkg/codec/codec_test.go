@@ -0,0 +1,139 @@
+package codec
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	value := map[string]any{
+		"null":   nil,
+		"bool":   true,
+		"small":  -3.0,
+		"int":    70000.0,
+		"float":  1.5,
+		"big":    int64(9007199254740993),
+		"text":   strings.Repeat("é", 40),
+		"bytes":  []byte{0, 1, 2},
+		"list":   []any{"a", false, map[string]any{}},
+		"nested": map[string]any{"k": []any{}},
+	}
+	for _, c := range []Codec{MessagePack(), CBOR()} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			data, err := c.Marshal(value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			got, err := c.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, value) {
+				t.Errorf("round trip = %#v, want %#v", got, value)
+			}
+		})
+	}
+}
+
+func TestMarshalGoValues(t *testing.T) {
+	type user struct {
+		Name    string    `json:"name"`
+		Created time.Time `json:"created"`
+	}
+	want := map[string]any{"name": "Ada", "created": "2024-05-01T10:00:00Z"}
+	for _, c := range []Codec{MessagePack(), CBOR()} {
+		data, err := c.Marshal(user{Name: "Ada", Created: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)})
+		if err != nil {
+			t.Fatalf("%s: Marshal failed: %v", c.ContentType(), err)
+		}
+		got, _ := c.Unmarshal(data)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %#v, want the JSON form %#v", c.ContentType(), got, want)
+		}
+	}
+}
+
+func TestEncodings(t *testing.T) {
+	tests := []struct {
+		codec Codec
+		value any
+		want  string
+	}{
+		{MessagePack(), 1.0, "01"},
+		{MessagePack(), -1.0, "ff"},
+		{MessagePack(), 256.0, "cd0100"},
+		{MessagePack(), "a", "a161"},
+		{MessagePack(), []any{true, nil}, "92c3c0"},
+		{MessagePack(), map[string]any{"b": 1.0, "a": 2.0}, "82a16102a16201"},
+		{CBOR(), 10.0, "0a"},
+		{CBOR(), 1000.0, "1903e8"},
+		{CBOR(), -100.0, "3863"},
+		{CBOR(), 1.1, "fb3ff199999999999a"},
+		{CBOR(), "IETF", "6449455446"},
+		{CBOR(), map[string]any{"b": []any{2.0, 3.0}, "a": 1.0}, "a26161016162820203"},
+	}
+	for _, tt := range tests {
+		data, err := tt.codec.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("%s: Marshal(%v) failed: %v", tt.codec.ContentType(), tt.value, err)
+		}
+		if got := hex.EncodeToString(data); got != tt.want {
+			t.Errorf("%s: Marshal(%v) = %s, want %s", tt.codec.ContentType(), tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeOtherForms(t *testing.T) {
+	tests := []struct {
+		codec Codec
+		data  string
+		want  any
+	}{
+		{MessagePack(), "ca3fc00000", 1.5}, // float32
+		{MessagePack(), "d0ff", -1.0},      // int8
+		{CBOR(), "f93e00", 1.5},            // half float
+		{CBOR(), "c074323031332d30332d32315432303a30343a30305a", "2013-03-21T20:04:00Z"}, // tagged date-time
+		{CBOR(), "f7", nil}, // undefined
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		got, err := tt.codec.Unmarshal(data)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Unmarshal(%s) = %#v, %v; want %#v", tt.codec.ContentType(), tt.data, got, err, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		codec   Codec
+		data    string
+		wantErr string
+	}{
+		{MessagePack(), "a3616263ff", "trailing data"},
+		{MessagePack(), "a561", "unexpected end of data"},
+		{MessagePack(), "dfffffffff", "unexpected end of data"},
+		{MessagePack(), "810102", "map key must be a string"},
+		{MessagePack(), "d40102", "unsupported type code 0xd4"},
+		{CBOR(), "9f01ff", "indefinite-length"},
+		{CBOR(), "9bffffffffffffffff", "unexpected end of data"},
+		{CBOR(), "a10102", "map key must be a string"},
+	}
+	for _, tt := range tests {
+		data, _ := hex.DecodeString(tt.data)
+		_, err := tt.codec.Unmarshal(data)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Unmarshal(%s): expected error containing %q, got %v", tt.codec.ContentType(), tt.data, tt.wantErr, err)
+		}
+	}
+}
+
+func TestUnmarshalDepth(t *testing.T) {
+	data := []byte(strings.Repeat("\x91", maxDepth+2) + "\xc0")
+	if _, err := MessagePack().Unmarshal(data); err == nil || !strings.Contains(err.Error(), "nesting") {
+		t.Errorf("expected nesting error, got %v", err)
+	}
+}
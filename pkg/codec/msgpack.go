@@ -0,0 +1,277 @@
+package codec
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+type msgpackCodec struct{}
+
+// MessagePack returns the codec for application/msgpack. Integers are
+// encoded in their smallest form and byte slices as bin; extension types
+// are rejected when decoding.
+func MessagePack() Codec {
+	return msgpackCodec{}
+}
+
+func (msgpackCodec) ContentType() string {
+	return "application/msgpack"
+}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error) {
+	value, err := jsonValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("msgpack: %w", err)
+	}
+	var buf []byte
+	if buf, err = appendMsgpack(buf, value); err != nil {
+		return nil, fmt.Errorf("msgpack: %w", err)
+	}
+	return buf, nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte) (any, error) {
+	d := &msgpackDecoder{data: data}
+	v, err := d.value(0)
+	if err == nil && d.pos != len(d.data) {
+		err = fmt.Errorf("%d bytes of trailing data", len(d.data)-d.pos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("msgpack: %w", err)
+	}
+	return v, nil
+}
+
+func appendMsgpack(buf []byte, v any) ([]byte, error) {
+	var err error
+	switch t := v.(type) {
+	case nil:
+		return append(buf, 0xc0), nil
+	case bool:
+		if t {
+			return append(buf, 0xc3), nil
+		}
+		return append(buf, 0xc2), nil
+	case float64:
+		if i, ok := integer(t); ok {
+			return appendMsgpackInt(buf, i), nil
+		}
+		buf = append(buf, 0xcb)
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(t)), nil
+	case int64:
+		return appendMsgpackInt(buf, t), nil
+	case string:
+		buf = appendMsgpackHead(buf, len(t), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		return append(buf, t...), nil
+	case []byte:
+		buf = appendMsgpackHead(buf, len(t), 0, -1, 0xc4, 0xc5, 0xc6)
+		return append(buf, t...), nil
+	case []any:
+		buf = appendMsgpackHead(buf, len(t), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, item := range t {
+			if buf, err = appendMsgpack(buf, item); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		buf = appendMsgpackHead(buf, len(t), 0x80, 15, 0, 0xde, 0xdf)
+		for _, k := range sortedKeys(t) {
+			buf, _ = appendMsgpack(buf, k)
+			if buf, err = appendMsgpack(buf, t[k]); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	}
+	return nil, unsupportedType(v)
+}
+
+// appendMsgpackHead writes the type and length of a string, binary, array
+// or map: the fix form up to fixMax (if any), then the 8, 16 or 32-bit
+// form (a zero code means the format has no such form).
+func appendMsgpackHead(buf []byte, n int, fix byte, fixMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case n <= fixMax:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint8 && code8 != 0:
+		return append(buf, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, code32), uint32(n))
+	}
+}
+
+func appendMsgpackInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i <= 127:
+		return append(buf, byte(i))
+	case i >= -32 && i < 0:
+		return append(buf, byte(i))
+	case i >= 0 && i <= math.MaxUint8:
+		return append(buf, 0xcc, byte(i))
+	case i >= 0 && i <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(i))
+	case i >= 0:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), uint64(i))
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(i))
+	}
+}
+
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *msgpackDecoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (d *msgpackDecoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("nesting exceeds %d levels", maxDepth)
+	}
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	code := b[0]
+	switch {
+	case code <= 0x7f:
+		return float64(code), nil
+	case code >= 0xe0:
+		return float64(int8(code)), nil
+	case code&0xe0 == 0xa0:
+		return d.str(uint64(code & 0x1f))
+	case code&0xf0 == 0x90:
+		return d.array(uint64(code&0x0f), depth)
+	case code&0xf0 == 0x80:
+		return d.object(uint64(code&0x0f), depth)
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xca:
+		u, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.uint(8)
+		return math.Float64frombits(u), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (code - 0xcc))
+		return decodedUint(u), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		u, err := d.uint(size)
+		// Sign-extend from the encoded width
+		shift := 64 - 8*size
+		return decodedInt(int64(u<<shift) >> shift), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (code - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(n)
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (code - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		if err := checkLength(n, len(d.data)-d.pos); err != nil {
+			return nil, err
+		}
+		b, err := d.next(int(n))
+		return append([]byte{}, b...), err
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (code - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n, depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (code - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.object(n, depth)
+	}
+	return nil, fmt.Errorf("unsupported type code 0x%02x", code)
+}
+
+func (d *msgpackDecoder) str(n uint64) (string, error) {
+	if err := checkLength(n, len(d.data)-d.pos); err != nil {
+		return "", err
+	}
+	b, err := d.next(int(n))
+	return string(b), err
+}
+
+func (d *msgpackDecoder) array(n uint64, depth int) ([]any, error) {
+	if err := checkLength(n, len(d.data)-d.pos); err != nil {
+		return nil, err
+	}
+	arr := make([]any, n)
+	for i := range arr {
+		item, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = item
+	}
+	return arr, nil
+}
+
+func (d *msgpackDecoder) object(n uint64, depth int) (map[string]any, error) {
+	if err := checkLength(2*n, len(d.data)-d.pos); err != nil {
+		return nil, err
+	}
+	obj := make(map[string]any, n)
+	for i := uint64(0); i < n; i++ {
+		key, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("map key must be a string, got %T", key)
+		}
+		if obj[name], err = d.value(depth + 1); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
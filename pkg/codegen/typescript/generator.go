@@ -3,36 +3,137 @@ package typescript
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/vanna-ai/ont-run/pkg/ontology"
 )
 
+// Option configures TypeScript generation.
+type Option func(*options)
+
+type options struct {
+	fieldNaming ontology.FieldNaming
+	envelope    bool
+	internal    bool
+	tags        []string
+}
+
+// WithFieldNaming sets the naming strategy for generated property names.
+// Pair it with server.WithFieldNaming so the server translates keys at the boundary.
+func WithFieldNaming(naming ontology.FieldNaming) Option {
+	return func(o *options) {
+		o.fieldNaming = naming
+	}
+}
+
+// WithInternal includes internal functions, for SDKs used only by trusted
+// callers. By default they are left out.
+func WithInternal() Option {
+	return func(o *options) {
+		o.internal = true
+	}
+}
+
+// OnlyTags generates a client for the functions tagged with one of tags,
+// so areas of a large ontology can ship as separate SDKs.
+func OnlyTags(tags ...string) Option {
+	return func(o *options) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// WithResponseEnvelope generates a client that unwraps {data, meta} response
+// envelopes. Pair it with server.WithResponseEnvelope.
+func WithResponseEnvelope() Option {
+	return func(o *options) {
+		o.envelope = true
+	}
+}
+
 // GenerateTypeScript generates a TypeScript SDK in the specified output directory.
-func GenerateTypeScript(config *ontology.Config, outputDir string) error {
+func GenerateTypeScript(config *ontology.Config, outputDir string, opts ...Option) error {
+	o := &options{fieldNaming: ontology.CamelCase}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if !o.internal {
+		config = config.Public()
+	}
+	if len(o.tags) > 0 {
+		config = config.Tagged(o.tags...)
+	}
+
 	// Create output directory
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	// Generate types.ts
-	if err := generateTypes(config, outputDir); err != nil {
+	usesBytes, err := generateTypes(config, outputDir, o)
+	if err != nil {
 		return fmt.Errorf("failed to generate types.ts: %w", err)
 	}
 
 	// Generate index.ts (client)
-	if err := generateClient(config, outputDir); err != nil {
+	if err := generateClient(config, outputDir, usesBytes, o); err != nil {
 		return fmt.Errorf("failed to generate index.ts: %w", err)
 	}
 
 	return nil
 }
 
-func generateTypes(config *ontology.Config, outputDir string) error {
+// batchTypes mirrors ontology.BatchResult.
+const batchTypes = `export interface BatchItemResult<T> {
+  index: number;
+  status: 'ok' | 'error';
+  data?: T | null;
+  error?: string;
+}
+
+export interface BatchResult<T> {
+  results: BatchItemResult<T>[];
+  succeeded: number;
+  failed: number;
+}
+
+`
+
+// base64Type is the wire type of ontology.Bytes fields.
+const base64Type = `/** Binary data as a standard base64 string. Convert with toBase64 and fromBase64. */
+export type Base64 = string;
+
+`
+
+// base64Helpers convert between Uint8Array and Base64 fields in browsers and Node.
+const base64Helpers = `/** Encodes bytes for a Base64 field. */
+export function toBase64(bytes: Uint8Array): Types.Base64 {
+  let binary = '';
+  for (let i = 0; i < bytes.length; i++) {
+    binary += String.fromCharCode(bytes[i]);
+  }
+  return btoa(binary);
+}
+
+/** Decodes a Base64 field. */
+export function fromBase64(data: Types.Base64): Uint8Array {
+  const binary = atob(data);
+  const bytes = new Uint8Array(binary.length);
+  for (let i = 0; i < binary.length; i++) {
+    bytes[i] = binary.charCodeAt(i);
+  }
+  return bytes;
+}
+
+`
+
+// generateTypes writes types.ts and reports whether any type uses Base64.
+func generateTypes(config *ontology.Config, outputDir string, o *options) (bool, error) {
 	var buf bytes.Buffer
 
 	buf.WriteString("// Auto-generated from ont.lock - do not edit manually\n\n")
@@ -44,25 +145,64 @@ func generateTypes(config *ontology.Config, outputDir string) error {
 	}
 	sort.Strings(funcNames)
 
+	var decls bytes.Buffer
+
+	// Shared types for named schemas, referenced by name from function types
+	namedSchemas := config.NamedSchemas()
+	schemaNames := make([]string, 0, len(namedSchemas))
+	for name := range namedSchemas {
+		schemaNames = append(schemaNames, name)
+	}
+	sort.Strings(schemaNames)
+	for _, name := range schemaNames {
+		writeTypeDeclaration(&decls, schemaTypeName(name), namedSchemas[name], o)
+	}
+
 	// Generate interface for each function's inputs/outputs
 	for _, name := range funcNames {
 		fn := config.Functions[name]
+		typeName := capitalize(ontology.FunctionIdentifier(name))
 
-		// Generate input type
-		buf.WriteString(fmt.Sprintf("export interface %sInput {\n", capitalize(name)))
-		writeObjectProperties(&buf, fn.Inputs, "  ")
-		buf.WriteString("}\n\n")
+		// Generate input type, with headers and query parameters declared apart
+		if hasParams(fn) {
+			writeParamsDeclaration(&decls, typeName+"Params", fn, o)
+			writeTypeDeclaration(&decls, typeName+"Body", fn.Inputs, o)
+			decls.WriteString(fmt.Sprintf("export type %[1]sInput = %[1]sBody & %[1]sParams;\n\n", typeName))
+		} else {
+			writeTypeDeclaration(&decls, typeName+"Input", fn.Inputs, o)
+		}
 
 		// Generate output type
-		buf.WriteString(fmt.Sprintf("export interface %sOutput {\n", capitalize(name)))
-		writeObjectProperties(&buf, fn.Outputs, "  ")
-		buf.WriteString("}\n\n")
+		writeTypeDeclaration(&decls, typeName+"Output", fn.Outputs, o)
+	}
+
+	// Shared batch types, only when a function returns ont.MultiStatus
+	if strings.Contains(decls.String(), "BatchResult<") {
+		buf.WriteString(batchTypes)
+	}
+	usesBytes := strings.Contains(decls.String(), "Base64")
+	if usesBytes {
+		buf.WriteString(base64Type)
 	}
+	buf.Write(decls.Bytes())
 
-	return os.WriteFile(filepath.Join(outputDir, "types.ts"), buf.Bytes(), 0644)
+	return usesBytes, os.WriteFile(filepath.Join(outputDir, "types.ts"), buf.Bytes(), 0644)
 }
 
-func writeObjectProperties(buf *bytes.Buffer, schema ontology.Schema, indent string) {
+// writeTypeDeclaration emits an interface for object schemas and a type
+// alias for everything else (e.g. unions).
+func writeTypeDeclaration(buf *bytes.Buffer, typeName string, schema ontology.Schema, o *options) {
+	writeJSDoc(buf, schema, "")
+	if obj, ok := schema.(*ontology.ObjectSchema); ok && obj.BatchItem() == nil {
+		buf.WriteString(fmt.Sprintf("export interface %s {\n", typeName))
+		writeObjectProperties(buf, schema, "  ", o)
+		buf.WriteString("}\n\n")
+		return
+	}
+	buf.WriteString(fmt.Sprintf("export type %s = %s;\n\n", typeName, schemaToTypeScript(schema, o)))
+}
+
+func writeObjectProperties(buf *bytes.Buffer, schema ontology.Schema, indent string, o *options) {
 	obj, ok := schema.(*ontology.ObjectSchema)
 	if !ok {
 		return
@@ -80,67 +220,135 @@ func writeObjectProperties(buf *bytes.Buffer, schema ontology.Schema, indent str
 		requiredSet[name] = true
 	}
 
+	// Computed fields are filled in by the server
+	computedSet := make(map[string]bool)
+	for _, name := range obj.ComputedFields() {
+		computedSet[name] = true
+	}
+
 	for _, propName := range propNames {
 		propSchema := obj.Properties()[propName]
-		tsType := schemaToTypeScript(propSchema)
+		tsType := schemaToTypeScript(propSchema, o)
 		optional := ""
 		if !requiredSet[propName] {
 			optional = "?"
 		}
 
 		// Add format comment if applicable
+		fieldName := o.fieldNaming.Apply(propName)
+		if computedSet[propName] {
+			fieldName = "readonly " + fieldName
+		}
+		writeJSDoc(buf, propSchema, indent)
 		comment := getFormatComment(propSchema)
 		if comment != "" {
-			buf.WriteString(fmt.Sprintf("%s%s%s: %s; // %s\n", indent, propName, optional, tsType, comment))
+			buf.WriteString(fmt.Sprintf("%s%s%s: %s; // %s\n", indent, fieldName, optional, tsType, comment))
 		} else {
-			buf.WriteString(fmt.Sprintf("%s%s%s: %s;\n", indent, propName, optional, tsType))
+			buf.WriteString(fmt.Sprintf("%s%s%s: %s;\n", indent, fieldName, optional, tsType))
 		}
 	}
+	if signature := indexSignature(obj, o); signature != "" {
+		buf.WriteString(indent + signature + "\n")
+	}
 }
 
-func schemaToTypeScript(schema ontology.Schema) string {
+// indexSignature returns the index signature of an object with pattern
+// properties, or "". Declared properties must fit the index type, so it is
+// unknown when there are any.
+func indexSignature(obj *ontology.ObjectSchema, o *options) string {
+	patterns := obj.PatternSchemas()
+	if len(patterns) == 0 {
+		return ""
+	}
+	valueType := "unknown"
+	if len(obj.Properties()) == 0 {
+		keys := make([]string, 0, len(patterns))
+		for pattern := range patterns {
+			keys = append(keys, pattern)
+		}
+		sort.Strings(keys)
+		var types []string
+		for _, pattern := range keys {
+			if tsType := schemaToTypeScript(patterns[pattern], o); !contains(types, tsType) {
+				types = append(types, tsType)
+			}
+		}
+		valueType = strings.Join(types, " | ")
+	}
+	return "[key: string]: " + valueType + ";"
+}
+
+func schemaToTypeScript(schema ontology.Schema, o *options) string {
 	switch s := schema.(type) {
 	case *ontology.StringSchema:
+		// Enums become unions of literal types
+		if values := s.EnumValues(); len(values) > 0 {
+			literals := make([]string, len(values))
+			for i, v := range values {
+				literals[i] = stringLiteral(v)
+			}
+			return strings.Join(literals, " | ")
+		}
 		return "string"
 	case *ontology.NumberSchema:
+		if values := s.EnumValues(); len(values) > 0 {
+			literals := make([]string, len(values))
+			for i, v := range values {
+				literals[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+			return strings.Join(literals, " | ")
+		}
 		return "number"
 	case *ontology.BooleanSchema:
 		return "boolean"
+	case *ontology.BytesSchema:
+		return "Base64"
 	case *ontology.ArraySchema:
-		itemType := schemaToTypeScript(s.ItemSchema())
+		itemType := schemaToTypeScript(s.ItemSchema(), o)
+		if strings.Contains(itemType, " | ") || strings.Contains(itemType, " & ") {
+			itemType = "(" + itemType + ")"
+		}
 		return itemType + "[]"
 	case *ontology.ObjectSchema:
+		if item := s.BatchItem(); item != nil {
+			return "BatchResult<" + schemaToTypeScript(item, o) + ">"
+		}
 		// Inline object type
-		var buf bytes.Buffer
-		buf.WriteString("{ ")
-		propNames := make([]string, 0, len(s.Properties()))
-		for name := range s.Properties() {
-			propNames = append(propNames, name)
+		return inlineObjectType(s, o, nil)
+	case *ontology.DiscriminatedUnionSchema:
+		// One inline object per variant, with the tag as a literal type
+		tagField := o.fieldNaming.Apply(s.Discriminator())
+		types := make([]string, 0, len(s.Variants()))
+		for _, tag := range s.Tags() {
+			tagMember := fmt.Sprintf("%s: '%s';", tagField, tag)
+			types = append(types, inlineObjectType(s.Variants()[tag], o, []string{tagMember}))
 		}
-		sort.Strings(propNames)
-
-		requiredSet := make(map[string]bool)
-		for _, name := range s.Required() {
-			requiredSet[name] = true
+		return strings.Join(types, " | ")
+	case *ontology.NullableSchema:
+		innerType := schemaToTypeScript(s.InnerSchema(), o)
+		if strings.HasSuffix(innerType, " | null") {
+			// Nullable(Nullable(x)) is still x | null
+			return innerType
 		}
-
-		for i, propName := range propNames {
-			propSchema := s.Properties()[propName]
-			tsType := schemaToTypeScript(propSchema)
-			optional := ""
-			if !requiredSet[propName] {
-				optional = "?"
-			}
-			if i > 0 {
-				buf.WriteString(" ")
+		return innerType + " | null"
+	case *ontology.OneOfSchema:
+		types := make([]string, len(s.Alternatives()))
+		for i, alt := range s.Alternatives() {
+			types[i] = schemaToTypeScript(alt, o)
+		}
+		return strings.Join(types, " | ")
+	case *ontology.AllOfSchema:
+		types := make([]string, len(s.Schemas()))
+		for i, part := range s.Schemas() {
+			types[i] = schemaToTypeScript(part, o)
+			if strings.Contains(types[i], " | ") {
+				// & binds tighter than |
+				types[i] = "(" + types[i] + ")"
 			}
-			buf.WriteString(fmt.Sprintf("%s%s: %s;", propName, optional, tsType))
 		}
-		buf.WriteString(" }")
-		return buf.String()
-	case *ontology.NullableSchema:
-		innerType := schemaToTypeScript(s.InnerSchema())
-		return innerType + " | null"
+		return strings.Join(types, " & ")
+	case *ontology.RefSchema:
+		return schemaTypeName(s.Name())
 	case *ontology.AnySchema:
 		return "unknown"
 	default:
@@ -148,16 +356,126 @@ func schemaToTypeScript(schema ontology.Schema) string {
 	}
 }
 
+// inlineObjectType renders an object schema as an inline TypeScript type.
+// Leading members are emitted first, e.g. a discriminator tag.
+func inlineObjectType(s *ontology.ObjectSchema, o *options, leading []string) string {
+	propNames := make([]string, 0, len(s.Properties()))
+	for name := range s.Properties() {
+		propNames = append(propNames, name)
+	}
+	sort.Strings(propNames)
+
+	requiredSet := make(map[string]bool)
+	for _, name := range s.Required() {
+		requiredSet[name] = true
+	}
+
+	members := append([]string{}, leading...)
+	for _, propName := range propNames {
+		propSchema := s.Properties()[propName]
+		tsType := schemaToTypeScript(propSchema, o)
+		optional := ""
+		if !requiredSet[propName] {
+			optional = "?"
+		}
+		members = append(members, fmt.Sprintf("%s%s: %s;", o.fieldNaming.Apply(propName), optional, tsType))
+	}
+	if signature := indexSignature(s, o); signature != "" {
+		members = append(members, signature)
+	}
+	return "{ " + strings.Join(members, " ") + " }"
+}
+
+// stringLiteral quotes s as a single-quoted TypeScript string literal.
+func stringLiteral(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`, "\n", `\n`).Replace(s) + "'"
+}
+
+// writeJSDoc documents a declaration with the schema's description and
+// examples, if it has any.
+func writeJSDoc(buf *bytes.Buffer, schema ontology.Schema, indent string) {
+	description, examples := schemaDocs(schema)
+
+	var lines []string
+	if description != "" {
+		lines = append(lines, strings.Split(description, "\n")...)
+	}
+	for _, example := range examples {
+		data, err := json.Marshal(example)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, "@example "+string(data))
+	}
+	if message, ok := deprecation(schema); ok {
+		lines = append(lines, strings.TrimSpace("@deprecated "+message))
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	for i, line := range lines {
+		lines[i] = strings.ReplaceAll(line, "*/", "*\\/")
+	}
+	if len(lines) == 1 {
+		buf.WriteString(fmt.Sprintf("%s/** %s */\n", indent, lines[0]))
+		return
+	}
+	buf.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		buf.WriteString(strings.TrimRight(fmt.Sprintf("%s * %s", indent, line), " ") + "\n")
+	}
+	buf.WriteString(indent + " */\n")
+}
+
+// schemaDocs returns the description and examples of schema, looking through
+// a Nullable wrapper that has none of its own. Sensitive schemas have no
+// examples.
+func schemaDocs(schema ontology.Schema) (string, []any) {
+	d, ok := schema.(ontology.Described)
+	if !ok {
+		return "", nil
+	}
+	if d.Description() == "" && len(d.Examples()) == 0 {
+		if n, ok := schema.(*ontology.NullableSchema); ok {
+			return schemaDocs(n.InnerSchema())
+		}
+	}
+	if r, ok := schema.(ontology.Redactable); ok && r.IsSensitive() {
+		return d.Description(), nil
+	}
+	return d.Description(), d.Examples()
+}
+
+// deprecation returns the deprecation of a schema or, for a nullable
+// schema, of the schema it wraps.
+func deprecation(schema ontology.Schema) (string, bool) {
+	if d, ok := schema.(ontology.Deprecatable); ok {
+		if message, deprecated := d.Deprecation(); deprecated {
+			return message, true
+		}
+	}
+	if n, ok := schema.(*ontology.NullableSchema); ok {
+		return deprecation(n.InnerSchema())
+	}
+	return "", false
+}
+
 func getFormatComment(schema ontology.Schema) string {
-	if s, ok := schema.(*ontology.StringSchema); ok {
-		if s.Format() != "" {
-			return s.Format() + " format"
+	switch s := schema.(type) {
+	case *ontology.StringSchema:
+		if s.FormatName() != "" {
+			return s.FormatName() + " format"
+		}
+	case *ontology.BytesSchema:
+		if s.MaxBytesLimit() > 0 {
+			return fmt.Sprintf("at most %d bytes", s.MaxBytesLimit())
 		}
 	}
 	return ""
 }
 
-func generateClient(config *ontology.Config, outputDir string) error {
+func generateClient(config *ontology.Config, outputDir string, usesBytes bool, o *options) error {
 	var buf bytes.Buffer
 
 	buf.WriteString("// Auto-generated from ont.lock - do not edit manually\n\n")
@@ -165,7 +483,16 @@ func generateClient(config *ontology.Config, outputDir string) error {
 	buf.WriteString("export * from './types';\n\n")
 
 	// Generate error class
-	buf.WriteString(`export class OntologyError extends Error {
+	buf.WriteString(`/** An invalid input field, located by a JSON pointer such as /users/3/email. */
+export interface ValidationIssue {
+  path: string;
+  message: string;
+}
+
+export class OntologyError extends Error {
+  /** Every invalid input field, for 400 responses. */
+  readonly issues: ValidationIssue[] = [];
+
   constructor(
     message: string,
     public readonly status: number,
@@ -173,14 +500,83 @@ func generateClient(config *ontology.Config, outputDir string) error {
   ) {
     super(message);
     this.name = 'OntologyError';
+    if (status === 400) {
+      try {
+        this.issues = JSON.parse(message).issues ?? [];
+      } catch {
+        // Not a JSON error body
+      }
+    }
   }
 }
 
 `)
 
+	if usesBytes {
+		buf.WriteString(base64Helpers)
+	}
+	for _, fn := range config.Functions {
+		if hasParams(fn) {
+			buf.WriteString(paramsHelper)
+			break
+		}
+	}
+
+	if o.envelope {
+		buf.WriteString("export interface ResponseMeta {\n")
+		buf.WriteString("  requestId: string;\n")
+		buf.WriteString("  durationMs: number;\n")
+		buf.WriteString("  warnings: string[];\n")
+		buf.WriteString("}\n\n")
+	}
+
+	buf.WriteString(`/** A binary format for calls, e.g. MessagePack with @msgpack/msgpack. */
+export interface Codec {
+  contentType: string;
+  encode(value: unknown): Uint8Array;
+  decode(data: Uint8Array): unknown;
+}
+
+`)
+
+	// Headers can be static or resolved per request (e.g. to attach a fresh auth token)
+	buf.WriteString("export type HeadersProvider =\n")
+	buf.WriteString("  | Record<string, string>\n")
+	buf.WriteString("  | (() => Record<string, string> | Promise<Record<string, string>>);\n\n")
+
 	// Generate client class
 	buf.WriteString("export class OntologyClient {\n")
-	buf.WriteString("  constructor(private baseUrl: string = '') {}\n\n")
+	buf.WriteString("  constructor(\n")
+	buf.WriteString("    private baseUrl: string = '',\n")
+	buf.WriteString("    private headers: HeadersProvider = {}\n")
+	buf.WriteString("  ) {}\n\n")
+	if o.envelope {
+		buf.WriteString("  /** Called with the response metadata of every successful call. */\n")
+		buf.WriteString("  onMeta?: (functionName: string, meta: ResponseMeta) => void;\n\n")
+	}
+	buf.WriteString("  /** Called with the non-fatal warnings reported by a resolver, if any. */\n")
+	buf.WriteString("  onWarnings?: (functionName: string, warnings: string[]) => void;\n\n")
+	buf.WriteString("  /**\n")
+	buf.WriteString("   * Sends and receives calls in a binary format instead of JSON. The server\n")
+	buf.WriteString("   * must accept it (server.WithCodecs); errors are always JSON.\n")
+	buf.WriteString("   */\n")
+	buf.WriteString("  codec?: Codec;\n\n")
+	buf.WriteString("  private async resolveHeaders(): Promise<Record<string, string>> {\n")
+	buf.WriteString("    const extra = typeof this.headers === 'function' ? await this.headers() : this.headers;\n")
+	buf.WriteString("    if (this.codec) {\n")
+	buf.WriteString("      return { 'Content-Type': this.codec.contentType, Accept: this.codec.contentType, ...extra };\n")
+	buf.WriteString("    }\n")
+	buf.WriteString("    return { 'Content-Type': 'application/json', ...extra };\n")
+	buf.WriteString("  }\n\n")
+	buf.WriteString("  private encodeBody(input: unknown): BodyInit {\n")
+	buf.WriteString("    return this.codec ? this.codec.encode(input) : JSON.stringify(input);\n")
+	buf.WriteString("  }\n\n")
+	buf.WriteString("  private async decodeBody(response: Response): Promise<any> {\n")
+	buf.WriteString("    if (this.codec && response.headers.get('Content-Type') === this.codec.contentType) {\n")
+	buf.WriteString("      return this.codec.decode(new Uint8Array(await response.arrayBuffer()));\n")
+	buf.WriteString("    }\n")
+	buf.WriteString("    return response.json();\n")
+	buf.WriteString("  }\n\n")
 
 	// Get sorted function names for deterministic output
 	funcNames := make([]string, 0, len(config.Functions))
@@ -192,27 +588,88 @@ func generateClient(config *ontology.Config, outputDir string) error {
 	// Generate method for each function
 	for _, name := range funcNames {
 		fn := config.Functions[name]
-		inputType := capitalize(name) + "Input"
-		outputType := capitalize(name) + "Output"
+		method := ontology.FunctionIdentifier(name)
+		path := functionPath(name, fn)
+		inputType := capitalize(method) + "Input"
+		outputType := capitalize(method) + "Output"
 
 		// JSDoc comment
 		buf.WriteString(fmt.Sprintf("  /**\n"))
 		buf.WriteString(fmt.Sprintf("   * %s\n", fn.Description))
+		if fn.LongDescription != "" {
+			buf.WriteString("   *\n")
+			for _, line := range strings.Split(strings.TrimSpace(fn.LongDescription), "\n") {
+				buf.WriteString(strings.TrimRight(fmt.Sprintf("   * %s", strings.ReplaceAll(line, "*/", "*\\/")), " ") + "\n")
+			}
+		}
+		for _, example := range fn.Examples {
+			buf.WriteString(fmt.Sprintf("   * @example %s\n", strings.ReplaceAll(exampleCall(method, fn, example), "*/", "*\\/")))
+		}
+		switch fn.Stability {
+		case ontology.StabilityExperimental:
+			buf.WriteString("   * @experimental Requires the X-Ont-Experimental: true header; may change without notice.\n")
+		case ontology.StabilityBeta:
+			buf.WriteString("   * @beta\n")
+		}
+		if fn.Deprecated != "" {
+			buf.WriteString(fmt.Sprintf("   * @deprecated %s\n", fn.Deprecated))
+		}
 		buf.WriteString(fmt.Sprintf("   */\n"))
 
 		// Method signature
-		buf.WriteString(fmt.Sprintf("  async %s(input: Types.%s): Promise<Types.%s> {\n", name, inputType, outputType))
-		buf.WriteString(fmt.Sprintf("    const response = await fetch(`${this.baseUrl}/api/%s`, {\n", name))
-		buf.WriteString("      method: 'POST',\n")
-		buf.WriteString("      headers: { 'Content-Type': 'application/json' },\n")
-		buf.WriteString("      body: JSON.stringify(input),\n")
+		buf.WriteString(fmt.Sprintf("  async %s(input: Types.%s): Promise<Types.%s> {\n", method, inputType, outputType))
+		if hasParams(fn) {
+			buf.WriteString(fmt.Sprintf("    const { body, headers, query } = %s;\n", splitParamsCall(fn, "input")))
+			buf.WriteString(fmt.Sprintf("    const response = await fetch(`${this.baseUrl}/api/%s${query}`, {\n", path))
+			buf.WriteString("      method: 'POST',\n")
+			buf.WriteString("      headers: { ...(await this.resolveHeaders()), ...headers },\n")
+			buf.WriteString("      body: this.encodeBody(body),\n")
+		} else {
+			buf.WriteString(fmt.Sprintf("    const response = await fetch(`${this.baseUrl}/api/%s`, {\n", path))
+			buf.WriteString("      method: 'POST',\n")
+			buf.WriteString("      headers: await this.resolveHeaders(),\n")
+			buf.WriteString("      body: this.encodeBody(input),\n")
+		}
 		buf.WriteString("    });\n\n")
 		buf.WriteString("    if (!response.ok) {\n")
 		buf.WriteString("      const text = await response.text();\n")
 		buf.WriteString(fmt.Sprintf("      throw new OntologyError(text || response.statusText, response.status, '%s');\n", name))
 		buf.WriteString("    }\n\n")
-		buf.WriteString("    return response.json();\n")
+		if o.envelope {
+			buf.WriteString("    const body = await this.decodeBody(response);\n")
+			buf.WriteString(fmt.Sprintf("    this.onMeta?.('%s', body.meta);\n", name))
+			buf.WriteString("    if (body.meta.warnings.length > 0) {\n")
+			buf.WriteString(fmt.Sprintf("      this.onWarnings?.('%s', body.meta.warnings);\n", name))
+			buf.WriteString("    }\n")
+			buf.WriteString("    return body.data;\n")
+		} else {
+			buf.WriteString("    const warnings = response.headers.get('X-Ont-Warnings');\n")
+			buf.WriteString("    if (warnings) {\n")
+			buf.WriteString(fmt.Sprintf("      this.onWarnings?.('%s', JSON.parse(warnings));\n", name))
+			buf.WriteString("    }\n\n")
+			buf.WriteString("    return this.decodeBody(response);\n")
+		}
 		buf.WriteString("  }\n\n")
+
+		if fn.Watchable {
+			writeWatchMethod(&buf, name, fn, inputType, outputType, o)
+		}
+
+		// Former names forward to the renamed method
+		for _, alias := range fn.Aliases {
+			buf.WriteString(fmt.Sprintf("  /** @deprecated Renamed to {@link OntologyClient.%s}. */\n", method))
+			buf.WriteString(fmt.Sprintf("  async %s(input: Types.%s): Promise<Types.%s> {\n", alias, inputType, outputType))
+			buf.WriteString(fmt.Sprintf("    return this.%s(input);\n", method))
+			buf.WriteString("  }\n\n")
+		}
+	}
+
+	members := clientMemberNames(config, funcNames)
+	if err := writeTagNamespaces(&buf, config, members); err != nil {
+		return err
+	}
+	if err := writeVersionNamespaces(&buf, config, funcNames, members); err != nil {
+		return err
 	}
 
 	buf.WriteString("}\n")
@@ -220,9 +677,203 @@ func generateClient(config *ontology.Config, outputDir string) error {
 	return os.WriteFile(filepath.Join(outputDir, "index.ts"), buf.Bytes(), 0644)
 }
 
+// writeWatchMethod generates a long-polling helper for a watchable function.
+// It calls onChange with every new result until the signal is aborted.
+func writeWatchMethod(buf *bytes.Buffer, name string, fn ontology.Function, inputType, outputType string, o *options) {
+	buf.WriteString("  /**\n")
+	buf.WriteString(fmt.Sprintf("   * Watch %s for changes. Calls onChange with the current result and then\n", name))
+	buf.WriteString("   * with every change, until the signal is aborted.\n")
+	if fn.Deprecated != "" {
+		buf.WriteString(fmt.Sprintf("   * @deprecated %s\n", fn.Deprecated))
+	}
+	buf.WriteString("   */\n")
+	buf.WriteString(fmt.Sprintf("  async watch%s(\n", capitalize(ontology.FunctionIdentifier(name))))
+	buf.WriteString(fmt.Sprintf("    input: Types.%s,\n", inputType))
+	buf.WriteString(fmt.Sprintf("    onChange: (output: Types.%s) => void,\n", outputType))
+	buf.WriteString("    signal?: AbortSignal\n")
+	buf.WriteString("  ): Promise<void> {\n")
+	buf.WriteString("    let since = input.since;\n")
+	buf.WriteString("    while (!signal?.aborted) {\n")
+	buf.WriteString("      let response: Response;\n")
+	buf.WriteString("      try {\n")
+	if hasParams(fn) {
+		buf.WriteString(fmt.Sprintf("        const { body, headers, query } = %s;\n", splitParamsCall(fn, "{ ...input, since }")))
+		buf.WriteString(fmt.Sprintf("        response = await fetch(`${this.baseUrl}/api/%s/watch${query}`, {\n", functionPath(name, fn)))
+		buf.WriteString("          method: 'POST',\n")
+		buf.WriteString("          headers: { ...(await this.resolveHeaders()), ...headers },\n")
+		buf.WriteString("          body: this.encodeBody(body),\n")
+	} else {
+		buf.WriteString(fmt.Sprintf("        response = await fetch(`${this.baseUrl}/api/%s/watch`, {\n", functionPath(name, fn)))
+		buf.WriteString("          method: 'POST',\n")
+		buf.WriteString("          headers: await this.resolveHeaders(),\n")
+		buf.WriteString("          body: this.encodeBody({ ...input, since }),\n")
+	}
+	buf.WriteString("          signal,\n")
+	buf.WriteString("        });\n")
+	buf.WriteString("      } catch (err) {\n")
+	buf.WriteString("        if (signal?.aborted) return;\n")
+	buf.WriteString("        throw err;\n")
+	buf.WriteString("      }\n\n")
+	buf.WriteString("      // 304: nothing changed before the server's timeout\n")
+	buf.WriteString("      if (response.status === 304) continue;\n")
+	buf.WriteString("      if (!response.ok) {\n")
+	buf.WriteString("        const text = await response.text();\n")
+	buf.WriteString(fmt.Sprintf("        throw new OntologyError(text || response.statusText, response.status, '%s');\n", name))
+	buf.WriteString("      }\n\n")
+	if o.envelope {
+		buf.WriteString("      const body = await this.decodeBody(response);\n")
+		buf.WriteString(fmt.Sprintf("      this.onMeta?.('%s', body.meta);\n", name))
+		buf.WriteString("      if (body.meta.warnings.length > 0) {\n")
+		buf.WriteString(fmt.Sprintf("        this.onWarnings?.('%s', body.meta.warnings);\n", name))
+		buf.WriteString("      }\n")
+		buf.WriteString(fmt.Sprintf("      const output: Types.%s = body.data;\n", outputType))
+	} else {
+		buf.WriteString("      const warnings = response.headers.get('X-Ont-Warnings');\n")
+		buf.WriteString("      if (warnings) {\n")
+		buf.WriteString(fmt.Sprintf("        this.onWarnings?.('%s', JSON.parse(warnings));\n", name))
+		buf.WriteString("      }\n")
+		buf.WriteString(fmt.Sprintf("      const output: Types.%s = await this.decodeBody(response);\n", outputType))
+	}
+	buf.WriteString("      since = output.cursor;\n")
+	buf.WriteString("      onChange(output);\n")
+	buf.WriteString("    }\n")
+	buf.WriteString("  }\n\n")
+}
+
+// clientMembers are the OntologyClient members tag namespaces must not shadow.
+var clientMembers = []string{"constructor", "baseUrl", "headers", "onMeta", "onWarnings", "codec", "resolveHeaders", "encodeBody", "decodeBody"}
+
+// clientMemberNames returns the OntologyClient members, methods included,
+// that namespaces must not shadow.
+func clientMemberNames(config *ontology.Config, funcNames []string) map[string]bool {
+	members := make(map[string]bool)
+	for _, name := range clientMembers {
+		members[name] = true
+	}
+	for _, name := range funcNames {
+		method := ontology.FunctionIdentifier(name)
+		members[method] = true
+		for _, alias := range config.Functions[name].Aliases {
+			members[alias] = true
+		}
+		if config.Functions[name].Watchable {
+			members["watch"+capitalize(method)] = true
+		}
+	}
+	return members
+}
+
+// writeTagNamespaces generates a property per tag that groups the tagged
+// functions, so client.billing.invoiceReport(...) calls invoiceReport.
+func writeTagNamespaces(buf *bytes.Buffer, config *ontology.Config, members map[string]bool) error {
+	for _, tag := range config.Tags() {
+		namespace := ontology.TagNamespace(tag)
+		if members[namespace] {
+			return fmt.Errorf("tag '%s' conflicts with client member '%s'", tag, namespace)
+		}
+		members[namespace] = true
+
+		buf.WriteString(fmt.Sprintf("  /** Functions tagged '%s'. */\n", tag))
+		buf.WriteString(fmt.Sprintf("  readonly %s = {\n", namespace))
+		for _, name := range config.FunctionsWithTag(tag) {
+			method := ontology.FunctionIdentifier(name)
+			writeNamespaceMember(buf, method, method, config.Functions[name])
+		}
+		buf.WriteString("  };\n\n")
+	}
+	return nil
+}
+
+// writeVersionNamespaces generates a property per function version that
+// groups the functions at that version, so client.v1.getUser(...) calls
+// getUserV1 and client.v2.getUser(...) calls getUser if it is version 2.
+func writeVersionNamespaces(buf *bytes.Buffer, config *ontology.Config, funcNames []string, members map[string]bool) error {
+	byVersion := make(map[int][]string)
+	for _, name := range funcNames {
+		if version := ontology.FunctionVersion(name, config.Functions[name]); version > 0 {
+			byVersion[version] = append(byVersion[version], name)
+		}
+	}
+	versions := make([]int, 0, len(byVersion))
+	for version := range byVersion {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+
+	for _, version := range versions {
+		namespace := fmt.Sprintf("v%d", version)
+		if members[namespace] {
+			return fmt.Errorf("version namespace '%s' conflicts with a client member", namespace)
+		}
+		members[namespace] = true
+
+		buf.WriteString(fmt.Sprintf("  /** Functions at version %d. */\n", version))
+		buf.WriteString(fmt.Sprintf("  readonly %s = {\n", namespace))
+		for _, name := range byVersion[version] {
+			base, _, _ := ontology.ParseVersionedName(name)
+			writeNamespaceMember(buf, base, ontology.FunctionIdentifier(name), config.Functions[name])
+		}
+		buf.WriteString("  };\n\n")
+	}
+	return nil
+}
+
+// writeNamespaceMember generates a namespace property named member that
+// calls the client method, and its watch helper if fn is watchable.
+func writeNamespaceMember(buf *bytes.Buffer, member, method string, fn ontology.Function) {
+	inputType := capitalize(method) + "Input"
+	outputType := capitalize(method) + "Output"
+	if fn.Deprecated != "" {
+		buf.WriteString(fmt.Sprintf("    /** %s @deprecated %s */\n", fn.Description, fn.Deprecated))
+	} else {
+		buf.WriteString(fmt.Sprintf("    /** %s */\n", fn.Description))
+	}
+	buf.WriteString(fmt.Sprintf("    %s: (input: Types.%s): Promise<Types.%s> => this.%s(input),\n", member, inputType, outputType, method))
+	if fn.Watchable {
+		watch := "watch" + capitalize(method)
+		buf.WriteString(fmt.Sprintf("    %s: (input: Types.%s, onChange: (output: Types.%s) => void, signal?: AbortSignal): Promise<void> =>\n", "watch"+capitalize(member), inputType, outputType))
+		buf.WriteString(fmt.Sprintf("      this.%s(input, onChange, signal),\n", watch))
+	}
+}
+
+// exampleCall renders an example as a client call with its result, with
+// sensitive values redacted: "await client.getUser({"id":"42"}) // => {...}".
+func exampleCall(method string, fn ontology.Function, example ontology.Example) string {
+	transcript := example.Transcript(fn)
+	input, _ := json.Marshal(transcript.Input)
+	call := fmt.Sprintf("await client.%s(%s)", method, input)
+	if transcript.Output != nil {
+		output, _ := json.Marshal(transcript.Output)
+		call += " // => " + string(output)
+	}
+	if example.Description != "" {
+		call = example.Description + "\n   * " + call
+	}
+	return call
+}
+
+// functionPath returns the route of a function under /api: its versioned
+// route for older versions, e.g. "v1/getUser", and its name otherwise.
+func functionPath(name string, fn ontology.Function) string {
+	if _, _, ok := ontology.ParseVersionedName(name); ok {
+		route, _ := ontology.VersionedRoute(name, fn)
+		return route
+	}
+	return name
+}
+
 func capitalize(s string) string {
 	if len(s) == 0 {
 		return s
 	}
 	return strings.ToUpper(s[:1]) + s[1:]
 }
+
+// schemaTypeName returns the TypeScript type name of a named schema;
+// versions such as "User@2" become "UserV2".
+func schemaTypeName(name string) string {
+	if base, version, ok := ontology.ParseVersionedName(name); ok {
+		return fmt.Sprintf("%sV%d", base, version)
+	}
+	return name
+}
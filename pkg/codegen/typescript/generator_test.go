@@ -96,6 +96,18 @@ func TestGenerateTypeScript(t *testing.T) {
 	if !strings.Contains(indexStr, "import type * as Types from './types'") {
 		t.Error("index.ts should import types")
 	}
+
+	if !strings.Contains(indexStr, "private headers: HeadersProvider = {}") {
+		t.Error("index.ts client should accept a headers provider")
+	}
+
+	if !strings.Contains(indexStr, "headers: await this.resolveHeaders()") {
+		t.Error("index.ts methods should send resolved headers")
+	}
+
+	if !strings.Contains(indexStr, "this.onWarnings?.('getUser', JSON.parse(warnings));") {
+		t.Error("index.ts methods should report warnings from the response header")
+	}
 }
 
 func TestGenerateTypeScriptMultipleFunctions(t *testing.T) {
@@ -285,3 +297,1220 @@ func TestGenerateTypeScriptDeterministic(t *testing.T) {
 		t.Error("Functions should be in alphabetical order")
 	}
 }
+
+func TestGenerateTypeScriptSnakeCase(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"listUsers": {
+				Description: "List users",
+				Access:      []string{"admin"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"pageSize": ontology.Integer(),
+				}),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"users": ontology.Array(ontology.Object(map[string]ontology.Schema{
+						"firstName": ontology.String(),
+					})),
+				}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir, WithFieldNaming(ontology.SnakeCase)); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	typesStr := string(typesContent)
+
+	if !strings.Contains(typesStr, "page_size: number") {
+		t.Error("types.ts should use snake_case for top-level fields")
+	}
+
+	if !strings.Contains(typesStr, "{ first_name: string; }") {
+		t.Error("types.ts should use snake_case for nested fields")
+	}
+
+	if strings.Contains(typesStr, "pageSize") {
+		t.Error("types.ts should not contain camelCase field names")
+	}
+}
+
+func TestGenerateTypeScriptComputedFields(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getPerson": {
+				Description: "Get a person",
+				Access:      []string{"admin"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"firstName": ontology.String(),
+					"lastName":  ontology.String(),
+				}).Computed("fullName", ontology.String(), func(obj map[string]any) (any, error) {
+					return obj["firstName"].(string) + " " + obj["lastName"].(string), nil
+				}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	if !strings.Contains(string(typesContent), "readonly fullName: string;") {
+		t.Error("types.ts should declare computed fields as readonly")
+	}
+}
+
+func TestGenerateTypeScriptResponseEnvelope(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getStatus": {
+				Description: "Get status",
+				Access:      []string{"admin"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"status": ontology.String()}),
+			},
+		},
+	}
+
+	plainDir := t.TempDir()
+	if err := GenerateTypeScript(config, plainDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	plain, _ := os.ReadFile(filepath.Join(plainDir, "index.ts"))
+	if strings.Contains(string(plain), "ResponseMeta") {
+		t.Error("index.ts should not reference envelopes unless enabled")
+	}
+
+	envDir := t.TempDir()
+	if err := GenerateTypeScript(config, envDir, WithResponseEnvelope()); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	env, _ := os.ReadFile(filepath.Join(envDir, "index.ts"))
+	envStr := string(env)
+
+	if !strings.Contains(envStr, "export interface ResponseMeta") {
+		t.Error("index.ts should declare ResponseMeta")
+	}
+	if !strings.Contains(envStr, "this.onMeta?.('getStatus', body.meta);") {
+		t.Error("index.ts should report envelope metadata")
+	}
+	if !strings.Contains(envStr, "return body.data;") {
+		t.Error("index.ts should unwrap envelope data")
+	}
+}
+
+func TestGenerateTypeScriptOneOf(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"findUser": {
+				Description: "Find a user by ID or email",
+				Access:      []string{"admin"},
+				Inputs: ontology.OneOf(
+					ontology.Object(map[string]ontology.Schema{"byId": ontology.String()}),
+					ontology.Object(map[string]ontology.Schema{"byEmail": ontology.String()}),
+				),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"ids": ontology.Array(ontology.OneOf(ontology.String(), ontology.Integer())),
+				}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	typesStr := string(typesContent)
+
+	if !strings.Contains(typesStr, "export type FindUserInput = { byId: string; } | { byEmail: string; };") {
+		t.Errorf("types.ts should declare union input as a type alias, got:\n%s", typesStr)
+	}
+
+	if !strings.Contains(typesStr, "ids: (string | number)[];") {
+		t.Error("types.ts should parenthesize union array items")
+	}
+}
+
+func TestGenerateTypeScriptAllOf(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Schemas: map[string]ontology.Schema{
+			"User": ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+		},
+		Functions: map[string]ontology.Function{
+			"listAdmins": {
+				Description: "List admins",
+				Access:      []string{"admin"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"admins": ontology.Array(ontology.AllOf(
+						ontology.Ref("User"),
+						ontology.Object(map[string]ontology.Schema{"level": ontology.Integer()}),
+					)),
+					"owner": ontology.AllOf(ontology.Ref("User"), ontology.OneOf(
+						ontology.Object(map[string]ontology.Schema{"email": ontology.String()}),
+						ontology.Object(map[string]ontology.Schema{"phone": ontology.String()}),
+					)),
+				}),
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+	typesStr := string(typesContent)
+
+	if !strings.Contains(typesStr, "admins: (User & { level: number; })[];") {
+		t.Errorf("types.ts should declare an intersection array, got:\n%s", typesStr)
+	}
+	if !strings.Contains(typesStr, "owner: User & ({ email: string; } | { phone: string; });") {
+		t.Error("types.ts should parenthesize unions inside intersections")
+	}
+}
+
+func TestGenerateTypeScriptPatternProperties(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"admin": {Description: "Admins"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"setLabels": {
+				Description: "Set labels",
+				Access:      []string{"admin"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"id": ontology.String(),
+					"labels": ontology.Object(map[string]ontology.Schema{}).
+						PatternProperties("^[a-z]+$", ontology.String()).
+						MaxProperties(10),
+				}).PatternProperties("^x-", ontology.Integer()),
+				Outputs: ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	typesStr := string(typesContent)
+
+	if !strings.Contains(typesStr, "labels: { [key: string]: string; };") {
+		t.Errorf("types.ts should type pattern properties, got:\n%s", typesStr)
+	}
+	if !strings.Contains(typesStr, "  [key: string]: unknown;\n}") {
+		t.Error("types.ts should use an unknown index signature beside declared properties")
+	}
+}
+
+func TestGenerateTypeScriptDiscriminatedUnion(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"pay": {
+				Description: "Make a payment",
+				Access:      []string{"admin"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"method": ontology.DiscriminatedUnion("type", map[string]*ontology.ObjectSchema{
+						"card": ontology.Object(map[string]ontology.Schema{"number": ontology.String()}),
+						"bank": ontology.Object(map[string]ontology.Schema{"iban": ontology.String()}),
+					}),
+				}),
+				Outputs: ontology.Object(map[string]ontology.Schema{"ok": ontology.Boolean()}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	want := "method: { type: 'bank'; iban: string; } | { type: 'card'; number: string; };"
+	if !strings.Contains(string(typesContent), want) {
+		t.Errorf("types.ts should contain %q, got:\n%s", want, typesContent)
+	}
+}
+
+func TestGenerateTypeScriptMultiStatus(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"admin": {Description: "Admins"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"createUsers": {
+				Description: "Create users in bulk",
+				Access:      []string{"admin"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"users": ontology.Array(ontology.Object(map[string]ontology.Schema{"name": ontology.String()})),
+				}),
+				Outputs: ontology.MultiStatus(ontology.Object(map[string]ontology.Schema{"id": ontology.String()})),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	typesStr := string(typesContent)
+
+	if !strings.Contains(typesStr, "export interface BatchResult<T>") {
+		t.Error("types.ts should declare BatchResult when used")
+	}
+
+	if !strings.Contains(typesStr, "export type CreateUsersOutput = BatchResult<{ id: string; }>;") {
+		t.Errorf("types.ts should type batch outputs with BatchResult, got:\n%s", typesStr)
+	}
+}
+
+func TestGenerateTypeScriptWatchable(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"listOrders": {
+				Description: "List orders",
+				Access:      []string{"public"},
+				IsReadOnly:  true,
+				Watchable:   true,
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"since": ontology.String(),
+				}).Optional("since"),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"cursor": ontology.String(),
+					"orders": ontology.Array(ontology.String()),
+				}),
+			},
+			"getOrder": {
+				Description: "Get an order",
+				Access:      []string{"public"},
+				IsReadOnly:  true,
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	clientContent, err := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read index.ts: %v", err)
+	}
+
+	clientStr := string(clientContent)
+
+	if !strings.Contains(clientStr, "async watchListOrders(") {
+		t.Error("index.ts should contain a watch helper for watchable functions")
+	}
+
+	if !strings.Contains(clientStr, "onChange: (output: Types.ListOrdersOutput) => void") {
+		t.Error("watch helper should pass typed output to onChange")
+	}
+
+	if !strings.Contains(clientStr, "/api/listOrders/watch") {
+		t.Error("watch helper should call the watch endpoint")
+	}
+
+	if !strings.Contains(clientStr, "if (response.status === 304) continue;") {
+		t.Error("watch helper should keep polling on 304 Not Modified")
+	}
+
+	if strings.Contains(clientStr, "watchGetOrder") {
+		t.Error("index.ts should not contain watch helpers for non-watchable functions")
+	}
+}
+
+func TestGenerateTypeScriptNamedSchemas(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Schemas: map[string]ontology.Schema{
+			"User": ontology.Object(map[string]ontology.Schema{
+				"id":   ontology.String(),
+				"name": ontology.String(),
+			}),
+			"Role": ontology.OneOf(ontology.String(), ontology.Integer()),
+		},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Ref("User"),
+			},
+			"listUsers": {
+				Description: "List users",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"users": ontology.Array(ontology.Ref("User")),
+					"role":  ontology.Nullable(ontology.Ref("Role")),
+				}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	typesStr := string(typesContent)
+
+	if strings.Count(typesStr, "export interface User {") != 1 {
+		t.Errorf("types.ts should declare User once, got:\n%s", typesStr)
+	}
+
+	if !strings.Contains(typesStr, "export type Role = string | number;") {
+		t.Error("types.ts should declare non-object named schemas as type aliases")
+	}
+
+	if !strings.Contains(typesStr, "export type GetUserOutput = User;") {
+		t.Error("types.ts should alias function types to named schemas")
+	}
+
+	if !strings.Contains(typesStr, "users: User[];") {
+		t.Error("types.ts should reference named schemas by name")
+	}
+
+	if !strings.Contains(typesStr, "role: Role | null;") {
+		t.Error("types.ts should reference nullable named schemas by name")
+	}
+}
+
+func TestGenerateTypeScriptSchemaVersions(t *testing.T) {
+	users := ontology.NewSchemaRegistry().
+		Register("User", 1, ontology.Object(map[string]ontology.Schema{"name": ontology.String()})).
+		Register("User", 2, ontology.Object(map[string]ontology.Schema{"firstName": ontology.String()}))
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Schemas:  users.Schemas(),
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Ref("User@1"),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	for _, want := range []string{
+		"export type User = UserV2;",
+		"export interface UserV1 {",
+		"export interface UserV2 {",
+		"export type GetUserOutput = UserV1;",
+	} {
+		if !strings.Contains(string(typesContent), want) {
+			t.Errorf("types.ts should contain %q, got:\n%s", want, typesContent)
+		}
+	}
+}
+
+func TestGenerateTypeScriptEntitySchemas(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{
+			"User": {
+				Description: "A user",
+				Key:         "id",
+				Schema: ontology.Object(map[string]ontology.Schema{
+					"id":   ontology.String(),
+					"name": ontology.String(),
+				}),
+			},
+		},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Entities:    []string{"User"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Ref("User"),
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	for _, want := range []string{
+		"export interface User {",
+		"export type GetUserOutput = User;",
+	} {
+		if !strings.Contains(string(typesContent), want) {
+			t.Errorf("types.ts should contain %q, got:\n%s", want, typesContent)
+		}
+	}
+}
+
+func TestGenerateTypeScriptRecursiveSchema(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Schemas: map[string]ontology.Schema{
+			"Comment": ontology.Object(map[string]ontology.Schema{
+				"body":    ontology.String(),
+				"replies": ontology.Array(ontology.Ref("Comment")),
+			}),
+		},
+		Functions: map[string]ontology.Function{
+			"getThread": {
+				Description: "Get a comment thread",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Ref("Comment"),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	if !strings.Contains(string(typesContent), "export interface Comment {\n  body: string;\n  replies: Comment[];\n}") {
+		t.Errorf("types.ts should declare a self-referencing interface, got:\n%s", typesContent)
+	}
+}
+
+func TestGenerateTypeScriptDescriptions(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"findCustomer": {
+				Description:     "Find a customer",
+				LongDescription: "Matches the email exactly.\n\nReturns */ nothing for archived customers.",
+				Access:          []string{"public"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"email": ontology.String().Email().Describe("Customer email address").Example("ada@example.com"),
+					"note":  ontology.Nullable(ontology.String().Describe("Why they are being looked up")),
+				}).Describe("Lookup parameters"),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"id": ontology.String(),
+				}),
+				Examples: []ontology.Example{
+					{Description: "An active customer", Input: map[string]any{"email": "ada@example.com"}, Output: map[string]any{"id": "c-1"}},
+				},
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	typesStr := string(typesContent)
+
+	for _, want := range []string{
+		"/** Lookup parameters */\nexport interface FindCustomerInput {",
+		"  /**\n   * Customer email address\n   * @example \"ada@example.com\"\n   */\n  email: string; // email format",
+		"  /** Why they are being looked up */\n  note: string | null;",
+		"export interface FindCustomerOutput {\n  id: string;",
+	} {
+		if !strings.Contains(typesStr, want) {
+			t.Errorf("types.ts missing %q:\n%s", want, typesStr)
+		}
+	}
+
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	for _, want := range []string{
+		"   * Find a customer\n   *\n   * Matches the email exactly.\n   *\n   * Returns *\\/ nothing for archived customers.\n",
+		"   * @example An active customer\n   * await client.findCustomer({\"email\":\"ada@example.com\"}) // => {\"id\":\"c-1\"}\n   */",
+	} {
+		if !strings.Contains(string(clientContent), want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+}
+
+func TestGenerateTypeScriptEnums(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"setPriority": {
+				Description: "Set a ticket's priority",
+				Access:      []string{"public"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"priority": ontology.Integer().Enum(1, 2, 3),
+					"ratio":    ontology.Number().Enum(0.5, 1.5),
+					"status":   ontology.String().Enum("open", "won't fix"),
+					"labels":   ontology.Array(ontology.String().Enum("bug", "feature")),
+				}),
+				Outputs: ontology.Object(map[string]ontology.Schema{
+					"ok": ontology.Boolean(),
+				}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+
+	typesContent, err := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read types.ts: %v", err)
+	}
+
+	typesStr := string(typesContent)
+
+	for _, want := range []string{
+		"priority: 1 | 2 | 3;",
+		"ratio: 0.5 | 1.5;",
+		`status: 'open' | 'won\'t fix';`,
+		"labels: ('bug' | 'feature')[];",
+	} {
+		if !strings.Contains(typesStr, want) {
+			t.Errorf("types.ts missing %q:\n%s", want, typesStr)
+		}
+	}
+}
+
+func TestGenerateTypeScriptTagNamespaces(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"invoiceReport": {
+				Description: "Invoice report",
+				Access:      []string{"public"},
+				Tags:        []string{"billing", "finance-ops"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+			"refund": {
+				Description: "Refund a payment",
+				Access:      []string{"public"},
+				Tags:        []string{"billing"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+			"health": {
+				Description: "Health check",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, err := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read index.ts: %v", err)
+	}
+	clientStr := string(clientContent)
+
+	for _, want := range []string{
+		"async invoiceReport(",
+		"readonly billing = {",
+		"invoiceReport: (input: Types.InvoiceReportInput): Promise<Types.InvoiceReportOutput> => this.invoiceReport(input),",
+		"refund: (input: Types.RefundInput): Promise<Types.RefundOutput> => this.refund(input),",
+		"readonly financeOps = {",
+	} {
+		if !strings.Contains(clientStr, want) {
+			t.Errorf("index.ts missing %q:\n%s", want, clientStr)
+		}
+	}
+	if strings.Contains(clientStr, "health: (") {
+		t.Error("untagged functions should not be namespaced")
+	}
+
+	// A billing-only client
+	billingDir := t.TempDir()
+	if err := GenerateTypeScript(config, billingDir, OnlyTags("billing")); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	billingContent, err := os.ReadFile(filepath.Join(billingDir, "index.ts"))
+	if err != nil {
+		t.Fatalf("Failed to read index.ts: %v", err)
+	}
+	if !strings.Contains(string(billingContent), "async refund(") || strings.Contains(string(billingContent), "async health(") {
+		t.Errorf("OnlyTags should keep only tagged functions:\n%s", billingContent)
+	}
+
+	// Tags that would shadow client members are rejected
+	fn := config.Functions["health"]
+	fn.Tags = []string{"on-warnings"}
+	config.Functions["health"] = fn
+	if err := GenerateTypeScript(config, t.TempDir()); err == nil {
+		t.Error("expected an error for a tag that shadows a client member")
+	}
+}
+
+func TestGenerateTypeScriptBytes(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getThumbnail": {
+				Description: "Get a thumbnail",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"id": ontology.String()}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"image": ontology.Bytes().MaxBytes(1024)}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+
+	for _, want := range []string{"export type Base64 = string;", "image: Base64; // at most 1024 bytes"} {
+		if !strings.Contains(string(typesContent), want) {
+			t.Errorf("types.ts missing %q:\n%s", want, typesContent)
+		}
+	}
+	for _, want := range []string{"export function toBase64(bytes: Uint8Array): Types.Base64", "export function fromBase64(data: Types.Base64): Uint8Array"} {
+		if !strings.Contains(string(clientContent), want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+
+	// Helpers are only generated when a schema uses Bytes
+	config.Functions["getThumbnail"] = ontology.Function{
+		Description: "Get a thumbnail",
+		Access:      []string{"public"},
+		Inputs:      ontology.Object(map[string]ontology.Schema{}),
+		Outputs:     ontology.Object(map[string]ontology.Schema{}),
+	}
+	tmpDir = t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, _ = os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	if strings.Contains(string(clientContent), "toBase64") {
+		t.Error("index.ts should not contain base64 helpers without Bytes fields")
+	}
+}
+
+func TestGenerateTypeScriptStability(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"searchUsers": {
+				Description: "Search users",
+				Access:      []string{"public"},
+				Stability:   ontology.StabilityExperimental,
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Stability:   ontology.StabilityBeta,
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	for _, want := range []string{
+		"   * Search users\n   * @experimental Requires the X-Ont-Experimental: true header",
+		"   * Get a user\n   * @beta\n",
+	} {
+		if !strings.Contains(string(clientContent), want) {
+			t.Errorf("index.ts missing %q:\n%s", want, clientContent)
+		}
+	}
+}
+
+func TestGenerateTypeScriptInternal(t *testing.T) {
+	config := &ontology.Config{
+		Name: "test",
+		AccessGroups: map[string]ontology.AccessGroup{
+			"public": {Description: "Public"},
+			"ops":    {Description: "Operators"},
+		},
+		Entities: map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+			"purgeCache": {
+				Description: "Purge caches",
+				Access:      []string{"ops"},
+				Internal:    true,
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	if strings.Contains(string(clientContent), "purgeCache") || strings.Contains(string(typesContent), "PurgeCache") {
+		t.Error("internal functions should be left out by default")
+	}
+
+	tmpDir = t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir, WithInternal()); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, _ = os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	if !strings.Contains(string(clientContent), "async purgeCache(") {
+		t.Error("WithInternal should include internal functions")
+	}
+}
+
+func TestGenerateTypeScriptAliases(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Aliases:     []string{"fetchUser"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	client := string(clientContent)
+	for _, want := range []string{
+		"/** @deprecated Renamed to {@link OntologyClient.getUser}. */",
+		"async fetchUser(input: Types.GetUserInput): Promise<Types.GetUserOutput> {",
+		"return this.getUser(input);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+}
+
+func TestGenerateTypeScriptFunctionVersions(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Version:     2,
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"name": ontology.String()}),
+			},
+			"getUser@1": {
+				Description: "Get a user (v1)",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"fullName": ontology.String()}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if !strings.Contains(string(typesContent), "export interface GetUserV1Output {") {
+		t.Errorf("types.ts missing GetUserV1Output:\n%s", typesContent)
+	}
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	client := string(clientContent)
+	for _, want := range []string{
+		"async getUserV1(input: Types.GetUserV1Input): Promise<Types.GetUserV1Output> {",
+		"fetch(`${this.baseUrl}/api/v1/getUser`",
+		"fetch(`${this.baseUrl}/api/getUser`",
+		"readonly v1 = {\n    /** Get a user (v1) */\n    getUser: (input: Types.GetUserV1Input): Promise<Types.GetUserV1Output> => this.getUserV1(input),",
+		"readonly v2 = {\n    /** Get a user */\n    getUser: (input: Types.GetUserInput): Promise<Types.GetUserOutput> => this.getUser(input),",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+}
+
+func TestGenerateTypeScriptValidationIssues(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions:    map[string]ontology.Function{},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	for _, want := range []string{"export interface ValidationIssue {", "readonly issues: ValidationIssue[] = [];"} {
+		if !strings.Contains(string(clientContent), want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+}
+
+func TestGenerateTypeScriptCodec(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{"name": ontology.String()}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	client := string(clientContent)
+	for _, want := range []string{
+		"export interface Codec {",
+		"codec?: Codec;",
+		"Accept: this.codec.contentType",
+		"body: this.encodeBody(input),",
+		"return this.decodeBody(response);",
+	} {
+		if !strings.Contains(client, want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+	if strings.Contains(client, "body: JSON.stringify(input)") {
+		t.Error("methods should encode through encodeBody")
+	}
+}
+
+func TestGenerateTypeScriptOptionalAndNullable(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"updateUser": {
+				Description: "Update a user",
+				Access:      []string{"public"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"id":       ontology.String(),
+					"nickname": ontology.String(),
+					"manager":  ontology.Nullable(ontology.String()),
+					"bio":      ontology.Nullable(ontology.Nullable(ontology.String())),
+					"tags":     ontology.Array(ontology.Nullable(ontology.String())),
+				}).Optional("nickname", "bio"),
+				Outputs: ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	types := string(typesContent)
+	for _, want := range []string{
+		"id: string;",
+		"nickname?: string;",
+		"manager: string | null;",
+		"bio?: string | null;",
+		"tags: (string | null)[];",
+	} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types.ts missing %q", want)
+		}
+	}
+	if strings.Contains(types, "| null | null") {
+		t.Error("nested nullables should render a single null")
+	}
+}
+
+func TestGenerateTypeScriptParams(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"listOrders": {
+				Description: "List orders",
+				Access:      []string{"public"},
+				Inputs:      ontology.Object(map[string]ontology.Schema{"status": ontology.String()}),
+				Outputs:     ontology.Object(map[string]ontology.Schema{}),
+				Headers:     ontology.Object(map[string]ontology.Schema{"X-Tenant-Id": ontology.String()}),
+				Query:       ontology.Object(map[string]ontology.Schema{"page": ontology.Integer()}).Optional("page"),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	for _, want := range []string{
+		"export interface ListOrdersParams {",
+		"'X-Tenant-Id': string; // header",
+		"page?: number; // query parameter",
+		"export interface ListOrdersBody {",
+		"export type ListOrdersInput = ListOrdersBody & ListOrdersParams;",
+	} {
+		if !strings.Contains(string(typesContent), want) {
+			t.Errorf("types.ts missing %q", want)
+		}
+	}
+
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	for _, want := range []string{
+		"function splitParams(",
+		"const { body, headers, query } = splitParams(input, ['X-Tenant-Id'], ['page']);",
+		"fetch(`${this.baseUrl}/api/listOrders${query}`",
+		"headers: { ...(await this.resolveHeaders()), ...headers },",
+		"body: this.encodeBody(body),",
+	} {
+		if !strings.Contains(string(clientContent), want) {
+			t.Errorf("index.ts missing %q", want)
+		}
+	}
+}
+
+func TestGenerateTypeScriptDeprecated(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Deprecated:  "use findUser",
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"id":       ontology.String(),
+					"username": ontology.String().Describe("Login name").Deprecated("use id"),
+				}).Optional("username"),
+				Outputs: ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if !strings.Contains(string(typesContent), "   * Login name\n   * @deprecated use id\n   */\n  username?: string;") {
+		t.Errorf("types.ts should mark username deprecated:\n%s", typesContent)
+	}
+	clientContent, _ := os.ReadFile(filepath.Join(tmpDir, "index.ts"))
+	if !strings.Contains(string(clientContent), "   * @deprecated use findUser\n   */\n  async getUser(") {
+		t.Error("index.ts should mark getUser deprecated")
+	}
+}
+
+func TestGenerateTypeScriptSensitiveExamples(t *testing.T) {
+	config := &ontology.Config{
+		Name:         "test",
+		AccessGroups: map[string]ontology.AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]ontology.Entity{},
+		Functions: map[string]ontology.Function{
+			"login": {
+				Description: "Log in",
+				Access:      []string{"public"},
+				Inputs: ontology.Object(map[string]ontology.Schema{
+					"user":     ontology.String().Example("ada"),
+					"password": ontology.String().Describe("The password").Example("hunter2").Sensitive(),
+				}),
+				Outputs: ontology.Object(map[string]ontology.Schema{}),
+			},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	if err := GenerateTypeScript(config, tmpDir); err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	typesContent, _ := os.ReadFile(filepath.Join(tmpDir, "types.ts"))
+	if strings.Contains(string(typesContent), "hunter2") || !strings.Contains(string(typesContent), "The password") {
+		t.Errorf("types.ts should document password without its example:\n%s", typesContent)
+	}
+	if !strings.Contains(string(typesContent), `"ada"`) {
+		t.Errorf("types.ts should keep the user example:\n%s", typesContent)
+	}
+}
@@ -0,0 +1,112 @@
+package typescript
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// paramsHelper moves the header and query parameter fields of a call's
+// input out of the body.
+const paramsHelper = `/** Moves header and query parameter fields out of a call's input. */
+function splitParams(input: object, headerNames: string[], queryNames: string[]) {
+  const body: Record<string, unknown> = { ...input };
+  const headers: Record<string, string> = {};
+  const query = new URLSearchParams();
+  for (const name of headerNames) {
+    if (body[name] !== undefined) headers[name] = String(body[name]);
+    delete body[name];
+  }
+  for (const name of queryNames) {
+    const value = body[name];
+    if (value !== undefined) {
+      for (const item of Array.isArray(value) ? value : [value]) query.append(name, String(item));
+    }
+    delete body[name];
+  }
+  const search = query.toString();
+  return { body, headers, query: search ? '?' + search : '' };
+}
+
+`
+
+// hasParams reports whether a function declares Headers or Query.
+func hasParams(fn ontology.Function) bool {
+	return fn.Headers != nil || fn.Query != nil
+}
+
+// writeParamsDeclaration declares a function's headers and query
+// parameters. Their names are sent as is, so field naming doesn't apply.
+func writeParamsDeclaration(buf *bytes.Buffer, typeName string, fn ontology.Function, o *options) {
+	buf.WriteString(fmt.Sprintf("export interface %s {\n", typeName))
+	for _, params := range []struct {
+		schema *ontology.ObjectSchema
+		kind   string
+	}{{fn.Headers, "header"}, {fn.Query, "query parameter"}} {
+		if params.schema == nil {
+			continue
+		}
+		required := params.schema.Required()
+		for _, name := range paramNames(params.schema) {
+			schema := params.schema.Properties()[name]
+			optional := "?"
+			if contains(required, name) {
+				optional = ""
+			}
+			writeJSDoc(buf, schema, "  ")
+			buf.WriteString(fmt.Sprintf("  %s%s: %s; // %s\n", propertyKey(name), optional, schemaToTypeScript(schema, o), params.kind))
+		}
+	}
+	buf.WriteString("}\n\n")
+}
+
+// paramNames returns the sorted property names of a Headers or Query schema.
+func paramNames(schema *ontology.ObjectSchema) []string {
+	if schema == nil {
+		return nil
+	}
+	names := make([]string, 0, len(schema.Properties()))
+	for name := range schema.Properties() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitParamsCall renders the splitParams call for a function's input.
+func splitParamsCall(fn ontology.Function, input string) string {
+	return fmt.Sprintf("splitParams(%s, %s, %s)", input, stringArray(paramNames(fn.Headers)), stringArray(paramNames(fn.Query)))
+}
+
+// stringArray renders a TypeScript array of string literals.
+func stringArray(values []string) string {
+	literals := make([]string, len(values))
+	for i, v := range values {
+		literals[i] = stringLiteral(v)
+	}
+	return "[" + strings.Join(literals, ", ") + "]"
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// propertyKey quotes a property name that isn't an identifier, such as a
+// header name with dashes.
+func propertyKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return stringLiteral(name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
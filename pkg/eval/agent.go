@@ -0,0 +1,90 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/vanna-ai/ont-run/pkg/cloud"
+)
+
+// Agent runs a scenario's goal and returns the calls it made.
+type Agent interface {
+	Run(ctx context.Context, s Scenario) ([]Call, error)
+}
+
+// AgentFunc adapts a function to an Agent, e.g. to drive a local model.
+type AgentFunc func(ctx context.Context, s Scenario) ([]Call, error)
+
+// Run calls f.
+func (f AgentFunc) Run(ctx context.Context, s Scenario) ([]Call, error) {
+	return f(ctx, s)
+}
+
+// ChatAgent returns an agent that sends each goal as a user message to the
+// ont-run.com chat agent of the ontology registered as uuid, with the
+// scenario's context, and returns the tool calls of its response.
+func ChatAgent(client *cloud.Client, uuid string) Agent {
+	return AgentFunc(func(ctx context.Context, s Scenario) ([]Call, error) {
+		messages := []cloud.ChatMessage{{Role: "user", Content: s.Goal}}
+		resp, err := client.Chat(uuid, messages, s.Context)
+		if err != nil {
+			return nil, err
+		}
+		if resp.LimitReached {
+			return nil, fmt.Errorf("chat limit reached: %s", resp.Message)
+		}
+		calls := make([]Call, len(resp.ToolCalls))
+		for i, tc := range resp.ToolCalls {
+			calls[i] = Call{Function: tc.Name, Arguments: tc.Arguments, Result: tc.Result}
+		}
+		return calls, nil
+	})
+}
+
+// Executor runs a call and returns its result.
+type Executor func(ctx context.Context, call Call) (any, error)
+
+// HTTPExecutor returns an executor that calls functions over the REST API
+// of the server at baseURL (POST {baseURL}/api/{function}), e.g. an
+// httptest.Server serving server.New(config).Handler(). Header is sent
+// with each call, for authentication.
+func HTTPExecutor(baseURL string, header http.Header) Executor {
+	client := &http.Client{}
+	return func(ctx context.Context, call Call) (any, error) {
+		body, err := json.Marshal(call.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseURL, "/")+"/api/"+call.Function, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send request: %w", err)
+		}
+		defer resp.Body.Close()
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		}
+		var result any
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		return result, nil
+	}
+}
@@ -0,0 +1,89 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultScenarios is the pattern Command loads scenarios from when none
+// is given.
+const DefaultScenarios = "testdata/eval/*.json"
+
+// Command runs scenarios from a terminal or CI, for backends to mount as a
+// subcommand of their own binary, like cloud.ReviewCommand:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "eval" {
+//		runner := eval.NewRunner(eval.ChatAgent(cloud.NewClient(), config.UUID),
+//			eval.WithExecutor(eval.HTTPExecutor("http://localhost:8080", nil)))
+//		os.Exit(eval.NewCommand(runner).Run(os.Args[2:]))
+//	}
+//
+// Its usage is:
+//
+//	eval [--min accuracy] [--json] [pattern...]
+//
+// Scenarios are loaded from the files matching the patterns, by default
+// DefaultScenarios. The report is printed as text, or as JSON with --json.
+type Command struct {
+	Runner *Runner
+	Out    io.Writer
+	Err    io.Writer
+}
+
+// NewCommand returns an eval command running scenarios with runner, using
+// the terminal's standard streams.
+func NewCommand(runner *Runner) *Command {
+	return &Command{Runner: runner, Out: os.Stdout, Err: os.Stderr}
+}
+
+// Run runs the command with args and returns its exit code: 0 if the
+// accuracy is at least --min, 1 if it is below or on errors and 2 on usage
+// mistakes.
+func (c *Command) Run(args []string) int {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	fs.SetOutput(c.Err)
+	minAccuracy := fs.Float64("min", 1, "fraction of scenarios that must pass, from 0 to 1")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *minAccuracy < 0 || *minAccuracy > 1 {
+		fmt.Fprintf(c.Err, "eval: --min must be between 0 and 1, got %v\n", *minAccuracy)
+		return 2
+	}
+
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{DefaultScenarios}
+	}
+	var scenarios []Scenario
+	for _, pattern := range patterns {
+		loaded, err := LoadScenarios(pattern)
+		if err != nil {
+			fmt.Fprintf(c.Err, "error: %v\n", err)
+			return 1
+		}
+		scenarios = append(scenarios, loaded...)
+	}
+
+	report := c.Runner.Run(context.Background(), scenarios)
+	if *asJSON {
+		enc := json.NewEncoder(c.Out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(c.Err, "error: %v\n", err)
+			return 1
+		}
+	} else {
+		fmt.Fprint(c.Out, report)
+	}
+	if err := report.Check(*minAccuracy); err != nil {
+		fmt.Fprintf(c.Err, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,378 @@
+// Package eval regression-tests how agents use an ontology. A scenario
+// gives an agent a user goal and lists the function calls it is expected
+// to make; running scenarios scores tool selection, so changes to
+// descriptions and schemas can be checked against agent behavior and not
+// just against the lock file.
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Scenario is a user goal and the calls an agent should make for it.
+type Scenario struct {
+	Name string `json:"name"`
+	// Goal is the user's message to the agent.
+	Goal string `json:"goal"`
+	// Context is passed to the agent with the goal, e.g. the current page.
+	Context map[string]any `json:"context,omitempty"`
+	// Expect lists the calls the agent must make.
+	Expect []ExpectedCall `json:"expect"`
+	// Ordered requires the expected calls to be made in order.
+	Ordered bool `json:"ordered,omitempty"`
+	// Forbid lists functions the agent must not call.
+	Forbid []string `json:"forbid,omitempty"`
+}
+
+// ExpectedCall is a call a scenario expects. Arguments and Result match
+// if every field they set has the same value in the actual call; other
+// fields are ignored.
+type ExpectedCall struct {
+	Function  string         `json:"function"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Result    any            `json:"result,omitempty"`
+}
+
+// Call is a function call an agent made.
+type Call struct {
+	Function  string         `json:"function"`
+	Arguments map[string]any `json:"arguments"`
+	Result    any            `json:"result,omitempty"`
+	// Error is the call's error message, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// LoadScenarios reads the scenario files matching pattern, e.g.
+// "testdata/eval/*.json". Each file holds one scenario as JSON; a scenario
+// without a name is named after its file.
+func LoadScenarios(pattern string) ([]Scenario, error) {
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no scenario files match %s", pattern)
+	}
+	scenarios := make([]Scenario, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var s Scenario
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if s.Name == "" {
+			s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		if s.Goal == "" {
+			return nil, fmt.Errorf("%s: scenario has no goal", path)
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// Result is the outcome of one scenario.
+type Result struct {
+	Scenario string `json:"scenario"`
+	Calls    []Call `json:"calls"`
+	// Matched is the number of expected calls the agent made.
+	Matched int `json:"matched"`
+	// Missing lists the expected calls the agent didn't make.
+	Missing []ExpectedCall `json:"missing,omitempty"`
+	// Unexpected lists the calls that matched no expected call.
+	Unexpected []Call `json:"unexpected,omitempty"`
+	// Forbidden lists the calls to forbidden functions.
+	Forbidden []Call `json:"forbidden,omitempty"`
+	// Error is set if the agent failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Passed reports whether the agent made every expected call and no
+// forbidden one. Extra calls are allowed.
+func (r *Result) Passed() bool {
+	return r.Error == "" && len(r.Missing) == 0 && len(r.Forbidden) == 0
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Accuracy is the fraction of scenarios that passed.
+func (r *Report) Accuracy() float64 {
+	if len(r.Results) == 0 {
+		return 0
+	}
+	passed := 0
+	for _, result := range r.Results {
+		if result.Passed() {
+			passed++
+		}
+	}
+	return float64(passed) / float64(len(r.Results))
+}
+
+// Precision is the fraction of calls made that were expected.
+func (r *Report) Precision() float64 {
+	matched, calls := 0, 0
+	for _, result := range r.Results {
+		matched += result.Matched
+		calls += len(result.Calls)
+	}
+	if calls == 0 {
+		return 0
+	}
+	return float64(matched) / float64(calls)
+}
+
+// Recall is the fraction of expected calls that were made.
+func (r *Report) Recall() float64 {
+	matched, expected := 0, 0
+	for _, result := range r.Results {
+		matched += result.Matched
+		expected += result.Matched + len(result.Missing)
+	}
+	if expected == 0 {
+		return 0
+	}
+	return float64(matched) / float64(expected)
+}
+
+// Check returns an error listing the failed scenarios if accuracy is
+// below min, for use in tests:
+//
+//	if err := report.Check(0.9); err != nil {
+//		t.Fatal(err)
+//	}
+func (r *Report) Check(min float64) error {
+	if r.Accuracy() >= min {
+		return nil
+	}
+	var failed []string
+	for _, result := range r.Results {
+		if !result.Passed() {
+			failed = append(failed, result.Scenario)
+		}
+	}
+	return fmt.Errorf("accuracy %.0f%% is below %.0f%%; failed: %s", r.Accuracy()*100, min*100, strings.Join(failed, ", "))
+}
+
+// String summarizes the report, one line per scenario with the reasons it
+// failed, then the scores.
+func (r *Report) String() string {
+	var b strings.Builder
+	for _, result := range r.Results {
+		if result.Passed() {
+			fmt.Fprintf(&b, "PASS %s\n", result.Scenario)
+			continue
+		}
+		fmt.Fprintf(&b, "FAIL %s\n", result.Scenario)
+		if result.Error != "" {
+			fmt.Fprintf(&b, "  error: %s\n", result.Error)
+		}
+		for _, call := range result.Missing {
+			fmt.Fprintf(&b, "  missing: %s%s\n", call.Function, formatArgs(call.Arguments))
+		}
+		for _, call := range result.Forbidden {
+			fmt.Fprintf(&b, "  forbidden: %s%s\n", call.Function, formatArgs(call.Arguments))
+		}
+		for _, call := range result.Unexpected {
+			fmt.Fprintf(&b, "  unexpected: %s%s", call.Function, formatArgs(call.Arguments))
+			if call.Error != "" {
+				fmt.Fprintf(&b, " failed: %s", call.Error)
+			}
+			b.WriteString("\n")
+		}
+	}
+	fmt.Fprintf(&b, "accuracy %.0f%%, precision %.0f%%, recall %.0f%% (%d scenarios)\n",
+		r.Accuracy()*100, r.Precision()*100, r.Recall()*100, len(r.Results))
+	return b.String()
+}
+
+func formatArgs(args map[string]any) string {
+	data, err := json.Marshal(args)
+	if err != nil || len(args) == 0 {
+		return "()"
+	}
+	return "(" + string(data) + ")"
+}
+
+// Runner runs scenarios through an agent.
+type Runner struct {
+	agent   Agent
+	execute Executor
+}
+
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithExecutor runs each call the agent makes with execute, e.g. against a
+// test server (see HTTPExecutor), recording its result or error in place
+// of the agent's.
+func WithExecutor(execute Executor) RunnerOption {
+	return func(r *Runner) {
+		r.execute = execute
+	}
+}
+
+// NewRunner creates a runner for agent.
+func NewRunner(agent Agent, opts ...RunnerOption) *Runner {
+	r := &Runner{agent: agent}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run runs the scenarios in order and scores them.
+func (r *Runner) Run(ctx context.Context, scenarios []Scenario) *Report {
+	report := &Report{Results: make([]Result, 0, len(scenarios))}
+	for _, s := range scenarios {
+		report.Results = append(report.Results, r.RunScenario(ctx, s))
+	}
+	return report
+}
+
+// RunScenario runs one scenario and scores it.
+func (r *Runner) RunScenario(ctx context.Context, s Scenario) Result {
+	calls, err := r.agent.Run(ctx, s)
+	if err != nil {
+		return Result{Scenario: s.Name, Error: err.Error(), Missing: s.Expect}
+	}
+	if r.execute != nil {
+		for i, call := range calls {
+			result, err := r.execute(ctx, call)
+			calls[i].Result, calls[i].Error = result, ""
+			if err != nil {
+				calls[i].Error = err.Error()
+			}
+		}
+	}
+	return Score(s, calls)
+}
+
+// Score matches the calls an agent made against a scenario. Each expected
+// call matches the first unmatched call to its function with matching
+// arguments and result, after the previous expected call if the scenario
+// is ordered.
+func Score(s Scenario, calls []Call) Result {
+	result := Result{Scenario: s.Name, Calls: calls}
+	used := make([]bool, len(calls))
+	next := 0
+	for _, want := range s.Expect {
+		start := 0
+		if s.Ordered {
+			start = next
+		}
+		found := -1
+		for i := start; i < len(calls); i++ {
+			if !used[i] && matchesCall(want, calls[i]) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			result.Missing = append(result.Missing, want)
+			continue
+		}
+		used[found] = true
+		next = found + 1
+		result.Matched++
+	}
+	for i, call := range calls {
+		switch {
+		case contains(s.Forbid, call.Function):
+			result.Forbidden = append(result.Forbidden, call)
+		case !used[i]:
+			result.Unexpected = append(result.Unexpected, call)
+		}
+	}
+	return result
+}
+
+// matchesCall reports whether call is the expected call.
+func matchesCall(want ExpectedCall, call Call) bool {
+	if want.Function != call.Function {
+		return false
+	}
+	if len(want.Arguments) > 0 && !subset(jsonForm(want.Arguments), jsonForm(call.Arguments)) {
+		return false
+	}
+	if want.Result != nil && (call.Error != "" || !subset(jsonForm(want.Result), jsonForm(call.Result))) {
+		return false
+	}
+	return true
+}
+
+// subset reports whether want matches got: objects match if got has each
+// of want's fields with a matching value, other values if they're equal.
+func subset(want, got any) bool {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return false
+		}
+		for _, key := range sortedKeys(w) {
+			v, ok := g[key]
+			if !ok || !subset(w[key], v) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		g, ok := got.([]any)
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !subset(w[i], g[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return want == got
+	}
+}
+
+// jsonForm returns v as decoded JSON, so typed values compare with the
+// scenario's.
+func jsonForm(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var result any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return v
+	}
+	return result
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
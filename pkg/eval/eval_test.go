@@ -0,0 +1,178 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/server"
+)
+
+func TestScore(t *testing.T) {
+	s := Scenario{
+		Name: "refund",
+		Expect: []ExpectedCall{
+			{Function: "getOrder", Arguments: map[string]any{"id": "42"}},
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Result: map[string]any{"status": "refunded"}},
+		},
+		Ordered: true,
+		Forbid:  []string{"deleteOrder"},
+	}
+	tests := []struct {
+		name       string
+		calls      []Call
+		passed     bool
+		matched    int
+		unexpected int
+	}{
+		{"exact", []Call{
+			{Function: "getOrder", Arguments: map[string]any{"id": "42", "expand": true}},
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Result: map[string]any{"status": "refunded", "amount": 10}},
+		}, true, 2, 0},
+		{"extra call", []Call{
+			{Function: "listOrders", Arguments: map[string]any{}},
+			{Function: "getOrder", Arguments: map[string]any{"id": "42"}},
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Result: map[string]any{"status": "refunded"}},
+		}, true, 2, 1},
+		{"wrong order", []Call{
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Result: map[string]any{"status": "refunded"}},
+			{Function: "getOrder", Arguments: map[string]any{"id": "42"}},
+		}, false, 1, 1},
+		{"wrong argument", []Call{
+			{Function: "getOrder", Arguments: map[string]any{"id": "43"}},
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Result: map[string]any{"status": "refunded"}},
+		}, false, 1, 1},
+		{"failed call", []Call{
+			{Function: "getOrder", Arguments: map[string]any{"id": "42"}},
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Error: "status 500"},
+		}, false, 1, 1},
+		{"forbidden", []Call{
+			{Function: "getOrder", Arguments: map[string]any{"id": "42"}},
+			{Function: "refundOrder", Arguments: map[string]any{"id": "42"}, Result: map[string]any{"status": "refunded"}},
+			{Function: "deleteOrder", Arguments: map[string]any{"id": "42"}},
+		}, false, 2, 0},
+	}
+	for _, tt := range tests {
+		result := Score(s, tt.calls)
+		if result.Passed() != tt.passed || result.Matched != tt.matched || len(result.Unexpected) != tt.unexpected {
+			t.Errorf("%s: Score() = passed %v, matched %d, unexpected %d", tt.name, result.Passed(), result.Matched, len(result.Unexpected))
+		}
+	}
+}
+
+func TestRunner(t *testing.T) {
+	config := &ont.Config{
+		Name:         "shop",
+		AccessGroups: map[string]ont.AccessGroup{"user": {Description: "Users"}},
+		Entities:     map[string]ont.Entity{},
+		Functions: map[string]ont.Function{
+			"getOrder": {
+				Description: "Get an order by ID",
+				Access:      []string{"user"},
+				Inputs:      ont.Object(map[string]ont.Schema{"id": ont.String()}),
+				Outputs:     ont.Object(map[string]ont.Schema{"id": ont.String(), "status": ont.String()}),
+				Resolver: func(ctx ont.Context, input any) (any, error) {
+					id := input.(map[string]any)["id"].(string)
+					return map[string]any{"id": id, "status": "shipped"}, nil
+				},
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(server.New(config).Handler())
+	defer ts.Close()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"status.json":  `{"goal": "Where is order 42?", "expect": [{"function": "getOrder", "arguments": {"id": "42"}, "result": {"status": "shipped"}}]}`,
+		"cancel.json":  `{"goal": "Cancel order 42", "expect": [{"function": "cancelOrder"}]}`,
+		"invalid.json": `{"goal": "Where is my order?", "expect": [{"function": "getOrder", "result": {"status": "shipped"}}]}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	scenarios, err := LoadScenarios(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("LoadScenarios() error = %v", err)
+	}
+
+	agent := AgentFunc(func(ctx context.Context, s Scenario) ([]Call, error) {
+		switch s.Goal {
+		case "Where is order 42?":
+			return []Call{{Function: "getOrder", Arguments: map[string]any{"id": "42"}}}, nil
+		case "Where is my order?":
+			return []Call{{Function: "getOrder", Arguments: map[string]any{}}}, nil
+		}
+		return nil, errors.New("no idea")
+	})
+	report := NewRunner(agent, WithExecutor(HTTPExecutor(ts.URL, nil))).Run(context.Background(), scenarios)
+
+	passed := map[string]bool{}
+	for _, result := range report.Results {
+		passed[result.Scenario] = result.Passed()
+	}
+	if !passed["status"] || passed["cancel"] || passed["invalid"] {
+		t.Errorf("Run() =\n%s", report)
+	}
+	if got := report.Accuracy(); got != 1.0/3 {
+		t.Errorf("Accuracy() = %v", got)
+	}
+	if got := report.Precision(); got != 0.5 {
+		t.Errorf("Precision() = %v", got)
+	}
+	if err := report.Check(0.5); err == nil || !strings.Contains(err.Error(), "failed: cancel, invalid") {
+		t.Errorf("Check() = %v", err)
+	}
+	if !strings.Contains(report.String(), "FAIL invalid\n  missing: getOrder()\n  unexpected: getOrder() failed: status 400") {
+		t.Errorf("String() =\n%s", report)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"status.json": `{"goal": "Where is order 42?", "expect": [{"function": "getOrder", "arguments": {"id": "42"}}]}`,
+		"cancel.json": `{"goal": "Cancel order 42", "expect": [{"function": "cancelOrder"}]}`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	agent := AgentFunc(func(ctx context.Context, s Scenario) ([]Call, error) {
+		return []Call{{Function: "getOrder", Arguments: map[string]any{"id": "42"}}}, nil
+	})
+	run := func(args ...string) (int, string, string) {
+		var out, errOut strings.Builder
+		cmd := NewCommand(NewRunner(agent))
+		cmd.Out, cmd.Err = &out, &errOut
+		code := cmd.Run(args)
+		return code, out.String(), errOut.String()
+	}
+
+	pattern := filepath.Join(dir, "*.json")
+	if code, out, errOut := run(pattern); code != 1 || !strings.Contains(out, "FAIL cancel") || !strings.Contains(errOut, "failed: cancel") {
+		t.Errorf("Run() = %d, stderr %q:\n%s", code, errOut, out)
+	}
+	if code, out, _ := run("--min", "0.5", "--json", pattern); code != 0 || !strings.Contains(out, `"scenario": "status"`) {
+		t.Errorf("Run(--min 0.5 --json) = %d:\n%s", code, out)
+	}
+	if code, _, _ := run(filepath.Join(dir, "status.json")); code != 0 {
+		t.Errorf("Run(status.json) = %d, want 0", code)
+	}
+	if code, _, errOut := run(filepath.Join(dir, "*.yaml")); code != 1 || !strings.Contains(errOut, "no scenario files match") {
+		t.Errorf("Run(*.yaml) = %d: %s", code, errOut)
+	}
+	if code, _, _ := run("--min", "90", pattern); code != 2 {
+		t.Errorf("Run(--min 90) = %d, want 2", code)
+	}
+}
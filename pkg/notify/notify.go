@@ -0,0 +1,44 @@
+// Package notify delivers notifications for workflow features such as
+// approval requests and alerts, over email or webhooks.
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// Message is a notification. Email delivery uses Subject and Body; webhooks
+// receive the whole message as JSON.
+type Message struct {
+	// Event identifies the kind of notification, e.g. "approval.requested".
+	Event   string `json:"event"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	// To lists recipients. Email delivery falls back to the notifier's
+	// default recipients when empty.
+	To   []string       `json:"to,omitempty"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// Notifier delivers messages.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Multi delivers each message to every notifier, returning the joined errors
+// of those that failed.
+func Multi(notifiers ...Notifier) Notifier {
+	return multi(notifiers)
+}
+
+type multi []Notifier
+
+func (m multi) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("shh")
+	var received Message
+	var signed bool
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signed = VerifySignature(secret, body, r.Header.Get(SignatureHeader))
+		json.Unmarshal(body, &received)
+	}))
+	defer ts.Close()
+
+	msg := Message{Event: "approval.requested", Subject: "Approve closeTicket", Data: map[string]any{"id": "T-1"}}
+	if err := NewWebhook(ts.URL, WithSecret(secret)).Notify(context.Background(), msg); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if !signed {
+		t.Error("webhook body should carry a valid signature")
+	}
+	if received.Event != "approval.requested" || received.Data["id"] != "T-1" {
+		t.Errorf("received = %+v", received)
+	}
+}
+
+func TestWebhookFailure(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	err := NewWebhook(ts.URL).Notify(context.Background(), Message{})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Notify error = %v, want 500 failure", err)
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	var gotTo []string
+	var gotMsg string
+	var gotAuth smtp.Auth
+
+	s := NewSMTP(SMTPConfig{
+		Addr:     "smtp.example.com:587",
+		Username: "bot",
+		Password: "secret",
+		From:     "ont@example.com",
+		To:       []string{"ops@example.com"},
+	})
+	s.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+	s.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		gotTo, gotMsg, gotAuth = to, string(msg), auth
+		return nil
+	}
+
+	err := s.Notify(context.Background(), Message{Subject: "Approval needed – T-1", Body: "line 1\nline 2"})
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	if len(gotTo) != 1 || gotTo[0] != "ops@example.com" {
+		t.Errorf("recipients = %v, want default recipients", gotTo)
+	}
+	if gotAuth == nil {
+		t.Error("PLAIN auth should be used when a username is set")
+	}
+	for _, want := range []string{
+		"From: ont@example.com\r\n",
+		"Subject: =?utf-8?q?Approval_needed_=E2=80=93_T-1?=\r\n",
+		"Date: Tue, 02 Jan 2024 03:04:05 +0000\r\n",
+		"\r\n\r\nline 1\r\nline 2",
+	} {
+		if !strings.Contains(gotMsg, want) {
+			t.Errorf("message missing %q:\n%s", want, gotMsg)
+		}
+	}
+}
+
+func TestSMTPNoRecipients(t *testing.T) {
+	s := NewSMTP(SMTPConfig{Addr: "localhost:25", From: "ont@example.com"})
+	if err := s.Notify(context.Background(), Message{Subject: "x"}); err == nil {
+		t.Error("Notify without recipients should fail")
+	}
+}
+
+type failingNotifier struct{ err error }
+
+func (f failingNotifier) Notify(ctx context.Context, msg Message) error {
+	return f.err
+}
+
+func TestMulti(t *testing.T) {
+	boom := errors.New("boom")
+	var delivered int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+	}))
+	defer ts.Close()
+
+	err := Multi(failingNotifier{boom}, NewWebhook(ts.URL)).Notify(context.Background(), Message{})
+	if !errors.Is(err, boom) {
+		t.Errorf("Multi error = %v, want boom", err)
+	}
+	if delivered != 1 {
+		t.Error("Multi should keep delivering after a failure")
+	}
+}
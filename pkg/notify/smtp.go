@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig configures email delivery.
+type SMTPConfig struct {
+	// Addr is the server's host:port, e.g. "smtp.example.com:587".
+	// STARTTLS is used when the server supports it.
+	Addr     string
+	Username string // PLAIN auth is used when set
+	Password string
+	From     string
+	// To is used for messages that don't name recipients.
+	To []string
+}
+
+// SMTP sends messages as plain text email.
+type SMTP struct {
+	config SMTPConfig
+	send   func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+	now    func() time.Time
+}
+
+// NewSMTP creates an email notifier.
+func NewSMTP(config SMTPConfig) *SMTP {
+	return &SMTP{config: config, send: smtp.SendMail, now: time.Now}
+}
+
+// Notify emails msg to its recipients, or the configured defaults.
+func (s *SMTP) Notify(ctx context.Context, msg Message) error {
+	to := msg.To
+	if len(to) == 0 {
+		to = s.config.To
+	}
+	if len(to) == 0 {
+		return fmt.Errorf("smtp: no recipients for %q", msg.Subject)
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		host, _, err := net.SplitHostPort(s.config.Addr)
+		if err != nil {
+			return fmt.Errorf("smtp: invalid address %q: %w", s.config.Addr, err)
+		}
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, host)
+	}
+
+	if err := s.send(s.config.Addr, auth, s.config.From, to, s.message(msg, to)); err != nil {
+		return fmt.Errorf("smtp: failed to send %q: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// message renders msg as an RFC 5322 email with CRLF line endings.
+func (s *SMTP) message(msg Message, to []string) []byte {
+	var b bytes.Buffer
+	header := func(name, value string) {
+		b.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", s.config.From)
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", s.now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	b.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return b.Bytes()
+}
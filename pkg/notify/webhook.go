@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of a webhook body as "sha256=<hex>".
+const SignatureHeader = "X-Ont-Signature"
+
+// Webhook posts messages as JSON to a URL.
+type Webhook struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// WebhookOption configures a Webhook.
+type WebhookOption func(*Webhook)
+
+// WithSecret signs each request body with HMAC-SHA256 so receivers can
+// verify it came from this server (see VerifySignature).
+func WithSecret(secret []byte) WebhookOption {
+	return func(w *Webhook) {
+		w.secret = secret
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for delivery.
+func WithHTTPClient(client *http.Client) WebhookOption {
+	return func(w *Webhook) {
+		w.client = client
+	}
+}
+
+// NewWebhook creates a notifier that posts to url.
+func NewWebhook(url string, opts ...WebhookOption) *Webhook {
+	w := &Webhook{url: url, client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Notify posts msg and fails on any non-2xx response.
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		req.Header.Set(SignatureHeader, sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook delivery failed: %s: %s", resp.Status, text)
+	}
+	return nil
+}
+
+// VerifySignature reports whether header is the signature of body under secret.
+func VerifySignature(secret, body []byte, header string) bool {
+	return hmac.Equal([]byte(header), []byte(sign(secret, body)))
+}
+
+func sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,46 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveAlias returns the function an alias names, for serving renamed
+// functions under their old names.
+func (c *Config) ResolveAlias(alias string) (string, bool) {
+	for _, name := range sortedKeys(c.Functions) {
+		if contains(c.Functions[name].Aliases, alias) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// AliasWarning is the warning returned to callers that use an alias.
+func AliasWarning(alias, name string) string {
+	return fmt.Sprintf("'%s' is deprecated: it was renamed to '%s'", alias, name)
+}
+
+// validateAliases checks that a function's aliases are usable as routes and
+// tool names and don't shadow other functions or aliases.
+func (c *Config) validateAliases(name string, fn Function) error {
+	seen := make(map[string]bool)
+	for _, alias := range fn.Aliases {
+		if alias == "" || strings.ContainsAny(alias, "/ ") {
+			return fmt.Errorf("invalid alias '%s'", alias)
+		}
+		if seen[alias] {
+			return fmt.Errorf("duplicate alias '%s'", alias)
+		}
+		seen[alias] = true
+		if _, ok := c.Functions[alias]; ok {
+			return fmt.Errorf("alias '%s' conflicts with function '%s'", alias, alias)
+		}
+		for other, otherFn := range c.Functions {
+			if other != name && contains(otherFn.Aliases, alias) {
+				return fmt.Errorf("alias '%s' is also an alias of function '%s'", alias, other)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	config := queryConfig()
+	before := config.Hash()
+
+	fn := config.Functions["getCustomer"]
+	fn.Aliases = []string{"fetchCustomer", "customerById"}
+	config.Functions["getCustomer"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if name, ok := config.ResolveAlias("fetchCustomer"); !ok || name != "getCustomer" {
+		t.Errorf("ResolveAlias = %q, %v", name, ok)
+	}
+	if _, ok := config.ResolveAlias("getCustomer"); ok {
+		t.Error("a function name is not an alias")
+	}
+
+	if config.Hash() == before {
+		t.Error("hash should change when aliases change")
+	}
+	shape := config.ExtractSnapshot().Functions["getCustomer"]
+	if !reflect.DeepEqual(shape.Aliases, []string{"customerById", "fetchCustomer"}) {
+		t.Errorf("snapshot aliases = %v", shape.Aliases)
+	}
+}
+
+func TestValidateAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases []string
+		wantErr string
+	}{
+		{"empty", []string{""}, "invalid alias"},
+		{"path", []string{"customers/get"}, "invalid alias"},
+		{"duplicate", []string{"fetchCustomer", "fetchCustomer"}, "duplicate alias"},
+		{"function name", []string{"sendMessage"}, "conflicts with function 'sendMessage'"},
+		{"other alias", []string{"removeCustomer"}, "alias 'removeCustomer' is also an alias of function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := queryConfig()
+			other := config.Functions["deleteCustomer"]
+			other.Aliases = []string{"removeCustomer"}
+			config.Functions["deleteCustomer"] = other
+			fn := config.Functions["getCustomer"]
+			fn.Aliases = tt.aliases
+			config.Functions["getCustomer"] = fn
+
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package ontology
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func adminSchema() *AllOfSchema {
+	user := Object(map[string]Schema{
+		"id":   String(),
+		"name": String(),
+	}).Optional("name")
+	return AllOf(user, Object(map[string]Schema{
+		"level": Integer().Min(1),
+	}))
+}
+
+func TestAllOfValidate(t *testing.T) {
+	schema := adminSchema()
+	validate := Compile(schema)
+
+	tests := []struct {
+		name    string
+		data    any
+		wantErr string
+	}{
+		{"all match", map[string]any{"id": "u1", "level": 2}, ""},
+		{"first fails", map[string]any{"level": 2}, "schema 0: required field 'id' is missing"},
+		{"second fails", map[string]any{"id": "u1", "level": 0}, "schema 1: field 'level'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, err := range []error{schema.Validate(tt.data), validate(tt.data)} {
+				if tt.wantErr == "" && err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			}
+		})
+	}
+
+	if got := schema.TypeName(); got != "object & object" {
+		t.Errorf("TypeName() = %q", got)
+	}
+}
+
+func TestAllOfJSONSchema(t *testing.T) {
+	js := adminSchema().Describe("An admin").JSONSchema()
+	if js["type"] != "object" || js["description"] != "An admin" {
+		t.Errorf("JSONSchema() = %v", js)
+	}
+	if parts, ok := js["allOf"].([]any); !ok || len(parts) != 2 {
+		t.Fatalf("allOf = %v", js["allOf"])
+	}
+
+	data, _ := json.Marshal(js)
+	imported, err := ParseJSONSchema(data)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+	allOf, ok := imported.(*AllOfSchema)
+	if !ok || len(allOf.Schemas()) != 2 {
+		t.Fatalf("imported %T", imported)
+	}
+	if err := allOf.Validate(map[string]any{"id": "u1"}); err == nil {
+		t.Error("imported schema should require level")
+	}
+}
+
+func TestAllOfStrictAndNaming(t *testing.T) {
+	schema := adminSchema()
+	if err := RejectUnknownFields(schema, map[string]any{"id": "u1", "level": 1}); err != nil {
+		t.Errorf("fields of either schema should be known: %v", err)
+	}
+	err := RejectUnknownFields(schema, map[string]any{"id": "u1", "level": 1, "extra": true})
+	if err == nil || !strings.Contains(err.Error(), "unknown field 'extra'") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+
+	wire := ToWireNames(schema, map[string]any{"id": "u1", "level": 1}, SnakeCase)
+	if _, ok := wire.(map[string]any)["level"]; !ok {
+		t.Errorf("ToWireNames() = %v", wire)
+	}
+	if got := Coerce(schema, map[string]any{"id": "u1", "level": "3"}); got.(map[string]any)["level"] != 3.0 {
+		t.Errorf("Coerce() = %v", got)
+	}
+}
+
+func TestAllOfAssignable(t *testing.T) {
+	to := Object(map[string]Schema{"id": String(), "level": Integer()})
+	if err := assignable(adminSchema(), to); err != nil {
+		t.Errorf("merged objects should be assignable: %v", err)
+	}
+	if err := assignable(Object(map[string]Schema{"id": String()}), adminSchema()); err == nil {
+		t.Error("an object without level should not be assignable")
+	}
+}
@@ -0,0 +1,148 @@
+package ontology
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultApprovalsPath is where approvals are kept, next to ont.lock.
+const DefaultApprovalsPath = ".ont/approvals.json"
+
+// Approval records that an approver reviewed the ontology with Hash. The
+// signature covers the hash, approver and time, so approvals can be
+// checked without ont-run.com.
+type Approval struct {
+	Hash       string    `json:"hash"`
+	Approver   string    `json:"approver"`
+	ApprovedAt time.Time `json:"approvedAt"`
+	// Signature is the approver's base64 Ed25519 signature.
+	Signature string `json:"signature"`
+}
+
+// ApprovalsFile is the .ont/approvals.json file structure.
+type ApprovalsFile struct {
+	Version   int        `json:"version"`
+	Approvals []Approval `json:"approvals"`
+}
+
+// approvalMessage is the message an approval signs.
+func approvalMessage(hash, approver string, at time.Time) []byte {
+	return []byte("ont-approval:v1\n" + hash + "\n" + approver + "\n" + at.UTC().Format(time.RFC3339))
+}
+
+// SignApproval approves the ontology with hash as approver.
+func SignApproval(hash, approver string, key ed25519.PrivateKey) Approval {
+	at := time.Now().UTC().Truncate(time.Second)
+	return Approval{
+		Hash:       hash,
+		Approver:   approver,
+		ApprovedAt: at,
+		Signature:  base64.StdEncoding.EncodeToString(ed25519.Sign(key, approvalMessage(hash, approver, at))),
+	}
+}
+
+// Verify checks the approval's signature with the approver's public key.
+func (a Approval) Verify(key ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(a.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize || !ed25519.Verify(key, approvalMessage(a.Hash, a.Approver, a.ApprovedAt), sig) {
+		return fmt.Errorf("signature by '%s' does not verify", a.Approver)
+	}
+	return nil
+}
+
+// ReadApprovals reads an approvals file. A missing file has no approvals.
+func ReadApprovals(path string) (*ApprovalsFile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ApprovalsFile{Version: 1}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approvals file: %w", err)
+	}
+
+	var file ApprovalsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse approvals file: %w", err)
+	}
+	return &file, nil
+}
+
+// AddApproval appends approval to the approvals file at path, creating it
+// if needed. Commit the file with ont.lock.
+func AddApproval(path string, approval Approval) error {
+	file, err := ReadApprovals(path)
+	if err != nil {
+		return err
+	}
+	file.Version = 1
+	file.Approvals = append(file.Approvals, approval)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal approvals file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create approvals directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write approvals file: %w", err)
+	}
+	return nil
+}
+
+// ParseApproverKey decodes a base64 Ed25519 public key, e.g. from an
+// environment variable.
+func ParseApproverKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid approver key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid approver key: %d bytes, want %d", len(key), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifyApproved checks that the config matches the lock file at lockPath
+// and that one of approvers, by name, signed an approval of it in the
+// approvals file at approvalsPath.
+func (c *Config) VerifyApproved(lockPath, approvalsPath string, approvers map[string]ed25519.PublicKey) error {
+	if err := c.VerifyLock(lockPath); err != nil {
+		return err
+	}
+	file, err := ReadApprovals(approvalsPath)
+	if err != nil {
+		return err
+	}
+
+	hash := c.Hash()
+	var problems []error
+	for _, approval := range file.Approvals {
+		if approval.Hash != hash {
+			continue
+		}
+		key, ok := approvers[approval.Approver]
+		if !ok {
+			problems = append(problems, fmt.Errorf("'%s' is not an approver", approval.Approver))
+			continue
+		}
+		if err := approval.Verify(key); err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		return nil
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("ontology %s is not approved: %w", hash, errors.Join(problems...))
+	}
+	return fmt.Errorf("ontology %s is not approved", hash)
+}
@@ -0,0 +1,73 @@
+package ontology
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyApproved(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "ont.lock")
+	approvalsPath := filepath.Join(dir, ".ont", "approvals.json")
+
+	config := entityConfig()
+	if err := config.WriteLock(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	alicePub, alice, _ := ed25519.GenerateKey(nil)
+	_, mallory, _ := ed25519.GenerateKey(nil)
+	approvers := map[string]ed25519.PublicKey{"alice": alicePub}
+
+	if err := config.VerifyApproved(lockPath, approvalsPath, approvers); err == nil || !strings.Contains(err.Error(), "is not approved") {
+		t.Errorf("VerifyApproved() without approvals = %v", err)
+	}
+
+	// A forged or unknown approval doesn't count
+	if err := AddApproval(approvalsPath, SignApproval(config.Hash(), "alice", mallory)); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddApproval(approvalsPath, SignApproval(config.Hash(), "mallory", mallory)); err != nil {
+		t.Fatal(err)
+	}
+	err := config.VerifyApproved(lockPath, approvalsPath, approvers)
+	if err == nil || !strings.Contains(err.Error(), "signature by 'alice' does not verify") || !strings.Contains(err.Error(), "'mallory' is not an approver") {
+		t.Errorf("VerifyApproved() with bad approvals = %v", err)
+	}
+
+	if err := AddApproval(approvalsPath, SignApproval(config.Hash(), "alice", alice)); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.VerifyApproved(lockPath, approvalsPath, approvers); err != nil {
+		t.Errorf("VerifyApproved() = %v", err)
+	}
+	file, err := ReadApprovals(approvalsPath)
+	if err != nil || len(file.Approvals) != 3 {
+		t.Fatalf("ReadApprovals() = %+v, %v", file, err)
+	}
+
+	// Changing the config needs a new lock and approval
+	config.Entities["Team"] = Entity{Description: "A group of users"}
+	if err := config.VerifyApproved(lockPath, approvalsPath, approvers); err == nil || !strings.Contains(err.Error(), "hash mismatch") {
+		t.Errorf("VerifyApproved() after change = %v", err)
+	}
+	if err := config.WriteLock(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.VerifyApproved(lockPath, approvalsPath, approvers); err == nil || !strings.Contains(err.Error(), "is not approved") {
+		t.Errorf("VerifyApproved() of new lock = %v", err)
+	}
+}
+
+func TestParseApproverKey(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	key, err := ParseApproverKey(base64.StdEncoding.EncodeToString(pub))
+	if err != nil || !key.Equal(pub) {
+		t.Errorf("ParseApproverKey() = %v, %v", key, err)
+	}
+	if _, err := ParseApproverKey("c2hvcnQ="); err == nil {
+		t.Error("ParseApproverKey() should reject short keys")
+	}
+}
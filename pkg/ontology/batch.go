@@ -0,0 +1,84 @@
+package ontology
+
+// Batch item statuses.
+const (
+	BatchStatusOK    = "ok"
+	BatchStatusError = "error"
+)
+
+// BatchResult collects the per-item outcomes of a batch operation so that
+// one failing item doesn't fail the whole call. Return it from resolvers
+// whose output schema is MultiStatus; the REST API responds with
+// 207 Multi-Status when any item failed.
+type BatchResult struct {
+	Results   []BatchItemResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+// BatchItemResult is the outcome of a single item in a batch.
+type BatchItemResult struct {
+	// Index is the position of the item in the input array.
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	Data   any    `json:"data,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewBatchResult creates an empty batch result.
+func NewBatchResult() *BatchResult {
+	return &BatchResult{Results: []BatchItemResult{}}
+}
+
+// OK records a successful item.
+func (b *BatchResult) OK(index int, data any) {
+	b.Results = append(b.Results, BatchItemResult{Index: index, Status: BatchStatusOK, Data: data})
+	b.Succeeded++
+}
+
+// Fail records a failed item.
+func (b *BatchResult) Fail(index int, err error) {
+	b.Results = append(b.Results, BatchItemResult{Index: index, Status: BatchStatusError, Error: err.Error()})
+	b.Failed++
+}
+
+// HasFailures reports whether any item failed.
+func (b *BatchResult) HasFailures() bool {
+	return b.Failed > 0
+}
+
+// MultiStatus creates the output schema for a batch operation whose
+// successful items produce data matching item.
+func MultiStatus(item Schema) *ObjectSchema {
+	result := Object(map[string]Schema{
+		"index":  Integer().NonNegative(),
+		"status": String().Enum(BatchStatusOK, BatchStatusError),
+		"data":   Nullable(item),
+		"error":  String(),
+	}).Optional("data", "error")
+
+	schema := Object(map[string]Schema{
+		"results":   Array(result),
+		"succeeded": Integer().NonNegative(),
+		"failed":    Integer().NonNegative(),
+	})
+	schema.batchItem = item
+	return schema
+}
+
+// BatchItem returns the item schema if this schema was created by
+// MultiStatus, or nil otherwise.
+func (o *ObjectSchema) BatchItem() Schema {
+	return o.batchItem
+}
+
+// BatchHasFailures reports whether output is a batch result with failed items.
+func BatchHasFailures(output any) bool {
+	switch b := output.(type) {
+	case *BatchResult:
+		return b != nil && b.HasFailures()
+	case BatchResult:
+		return b.HasFailures()
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package ontology
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchResult(t *testing.T) {
+	result := NewBatchResult()
+	result.OK(0, map[string]any{"id": "a"})
+	result.Fail(1, errors.New("duplicate email"))
+	result.OK(2, map[string]any{"id": "c"})
+
+	if result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("Succeeded/Failed = %d/%d, want 2/1", result.Succeeded, result.Failed)
+	}
+
+	if !result.HasFailures() || !BatchHasFailures(result) || !BatchHasFailures(*result) {
+		t.Error("batch with a failed item should report failures")
+	}
+
+	failed := result.Results[1]
+	if failed.Index != 1 || failed.Status != BatchStatusError || failed.Error != "duplicate email" {
+		t.Errorf("failed item = %+v", failed)
+	}
+
+	if BatchHasFailures(map[string]any{"failed": 1}) {
+		t.Error("only BatchResult values should be treated as batches")
+	}
+}
+
+func TestMultiStatusSchema(t *testing.T) {
+	schema := MultiStatus(Object(map[string]Schema{"id": String()}))
+
+	if schema.BatchItem() == nil {
+		t.Error("MultiStatus schema should expose its item schema")
+	}
+	if Object(map[string]Schema{}).BatchItem() != nil {
+		t.Error("plain object schema should not have a batch item")
+	}
+
+	result := NewBatchResult()
+	result.OK(0, map[string]any{"id": "a"})
+	result.Fail(1, errors.New("boom"))
+
+	if err := schema.Validate(result); err != nil {
+		t.Errorf("BatchResult should validate against MultiStatus: %v", err)
+	}
+
+	bad := NewBatchResult()
+	bad.OK(0, map[string]any{"id": 42})
+	if err := schema.Validate(bad); err == nil {
+		t.Error("item data should be validated against the item schema")
+	}
+}
@@ -0,0 +1,79 @@
+package ontology
+
+import "encoding/base64"
+
+// BytesSchema represents binary data, sent over the wire as a base64 string.
+type BytesSchema struct {
+	annotations
+	refinements
+	messages
+	transforms
+	maxBytes *int
+}
+
+// Bytes creates a schema for binary data such as small images or
+// attachments. JSON carries it as a standard base64 string; resolvers may
+// return []byte, which encoding/json marshals the same way.
+func Bytes() *BytesSchema {
+	return &BytesSchema{}
+}
+
+// MaxBytes sets the maximum decoded size in bytes.
+func (b *BytesSchema) MaxBytes(n int) *BytesSchema {
+	b.constrain("maxBytes")
+	b.maxBytes = &n
+	return b
+}
+
+// MaxBytesLimit returns the limit set with MaxBytes, or 0 if there is none.
+func (b *BytesSchema) MaxBytesLimit() int {
+	if b.maxBytes == nil {
+		return 0
+	}
+	return *b.maxBytes
+}
+
+func (b *BytesSchema) TypeName() string {
+	return "bytes"
+}
+
+func (b *BytesSchema) Validate(data any) error {
+	if err := b.validate(data); err != nil {
+		return b.message(err)
+	}
+	return b.refine(data)
+}
+
+func (b *BytesSchema) validate(data any) error {
+	var size int
+	switch v := data.(type) {
+	case []byte:
+		size = len(v)
+	case string:
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return constraintError("type", "bytes", b.shown(v), "string is not valid base64")
+		}
+		size = len(decoded)
+	default:
+		return constraintError("type", "bytes", b.shown(data), "expected base64 string or []byte, got %T", data)
+	}
+
+	if b.maxBytes != nil && size > *b.maxBytes {
+		return constraintError("maxBytes", *b.maxBytes, size, "data is %d bytes, more than the maximum of %d", size, *b.maxBytes)
+	}
+	return nil
+}
+
+func (b *BytesSchema) JSONSchema() map[string]any {
+	result := map[string]any{
+		"type":            "string",
+		"contentEncoding": "base64",
+	}
+	if b.maxBytes != nil {
+		// Base64 encodes every 3 bytes as 4 characters, with padding
+		result["maxLength"] = 4 * ((*b.maxBytes + 2) / 3)
+		result["x-ont-maxBytes"] = *b.maxBytes
+	}
+	return b.annotateRefinements(b.annotate(result))
+}
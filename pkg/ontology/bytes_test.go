@@ -0,0 +1,64 @@
+package ontology
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestBytesSchemaValidation(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n'}
+	encoded := base64.StdEncoding.EncodeToString(png)
+
+	tests := []struct {
+		name    string
+		schema  *BytesSchema
+		input   any
+		wantErr bool
+	}{
+		{"base64 string", Bytes(), encoded, false},
+		{"byte slice", Bytes(), png, false},
+		{"empty string", Bytes(), "", false},
+		{"invalid base64", Bytes(), "not base64!", true},
+		{"wrong type", Bytes(), 42, true},
+		{"within limit", Bytes().MaxBytes(6), encoded, false},
+		{"string over limit", Bytes().MaxBytes(5), encoded, true},
+		{"slice over limit", Bytes().MaxBytes(5), png, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBytesJSONSchema(t *testing.T) {
+	schema := Bytes().MaxBytes(10).Describe("Thumbnail").JSONSchema()
+	if schema["type"] != "string" || schema["contentEncoding"] != "base64" {
+		t.Errorf("unexpected JSON Schema: %v", schema)
+	}
+	// 10 bytes encode to 16 base64 characters
+	if schema["maxLength"] != 16 || schema["x-ont-maxBytes"] != 10 {
+		t.Errorf("unexpected limits: %v", schema)
+	}
+	if schema["description"] != "Thumbnail" {
+		t.Errorf("expected description, got %v", schema["description"])
+	}
+}
+
+func TestBytesStructOutput(t *testing.T) {
+	type attachment struct {
+		Name string `json:"name"`
+		Data []byte `json:"data"`
+	}
+	schema := Object(map[string]Schema{"name": String(), "data": Bytes().MaxBytes(4)})
+	if err := schema.Validate(attachment{Name: "a.txt", Data: []byte("abc")}); err != nil {
+		t.Errorf("expected struct with []byte to validate: %v", err)
+	}
+	if err := schema.Validate(attachment{Name: "a.txt", Data: []byte("abcde")}); err == nil {
+		t.Error("expected error for oversized []byte")
+	}
+}
@@ -0,0 +1,75 @@
+package ontology
+
+import "fmt"
+
+// Call invokes the named function on behalf of the caller in ctx, as if it
+// had been called over the API: access is checked against the caller's
+// access groups, input is validated and transformed, and output goes through
+// the output transform and computed fields. Helpers that compose functions,
+// such as reports, use it so composed calls keep each function's rules.
+func (c *Config) Call(ctx Context, name string, input any) (any, error) {
+	fn, ok := c.Functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", name)
+	}
+	if !fn.CheckAccess(ctx.AccessGroups()) {
+		return nil, fmt.Errorf("function '%s': access denied", name)
+	}
+	if err := fn.ValidateInput(input); err != nil {
+		return nil, fmt.Errorf("function '%s': %w", name, err)
+	}
+	if ok, err := fn.CheckAccessRule(ctx.AccessGroups(), ctx.UserContext(), input); !ok {
+		if err != nil {
+			return nil, fmt.Errorf("function '%s': access denied: %w", name, err)
+		}
+		return nil, fmt.Errorf("function '%s': access denied", name)
+	}
+	input, err := fn.ApplyInputTransform(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("function '%s': %w", name, err)
+	}
+
+	output, err := c.Resolve(ctx, fn, input)
+	if err != nil {
+		return nil, fmt.Errorf("function '%s': %w", name, err)
+	}
+
+	output, err = fn.ApplyOutputTransform(ctx, output)
+	if err != nil {
+		return nil, fmt.Errorf("function '%s': %w", name, err)
+	}
+	output, err = ApplyComputed(fn.Outputs, output)
+	if err != nil {
+		return nil, fmt.Errorf("function '%s': %w", name, err)
+	}
+	return output, nil
+}
+
+// Resolve runs fn's resolver (or its canary, see Function.Canary, falling
+// back as set by Function.Fallback), or its steps if fn is a pipeline. Input
+// is expected to be validated and transformed already. Calls running longer
+// than Function.Timeout fail with ErrTimeout.
+func (c *Config) Resolve(ctx Context, fn Function, input any) (any, error) {
+	if fn.Timeout > 0 {
+		return c.resolveWithTimeout(ctx, fn, input)
+	}
+	if fn.Pipeline != nil {
+		return c.runPipeline(ctx, fn.Pipeline, input)
+	}
+	if fn.Resolver == nil {
+		return nil, fmt.Errorf("no resolver")
+	}
+	if fn.contextUse != nil && !(fn.UsesUserContext && fn.UsesOrganizationContext) {
+		tracked := &trackedContext{Context: ctx, reads: &contextReads{}}
+		defer fn.contextUse.check(fn, tracked)
+		ctx = tracked
+	}
+	resolver := fn.Resolver
+	if fn.Canary != nil {
+		resolver = fn.resolveCanary
+	}
+	if fn.Fallback != nil {
+		return fn.resolveFallback(ctx, resolver, input)
+	}
+	return resolver(ctx, input)
+}
@@ -0,0 +1,147 @@
+package ontology
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Canary routes part of a function's traffic to a new resolver
+// implementation, e.g. while rewriting a resolver in native Go.
+type Canary struct {
+	// Resolver is the new implementation.
+	Resolver ResolverFunc `json:"-"`
+	// Percent of calls (0-100) served by Resolver instead of the
+	// function's Resolver.
+	Percent float64 `json:"percent"`
+	// CompareOutputs runs both resolvers on every call and reports calls
+	// where their outputs differ; a panic counts as the resolver's error. The
+	// caller gets the output of the resolver Percent selected; the call
+	// waits for both. Since both resolvers run,
+	// concurrently and on the same input, it requires a read-only function
+	// whose resolvers don't modify their input.
+	CompareOutputs bool `json:"compareOutputs,omitempty"`
+	// OnMismatch receives the calls where the outputs differ. By default
+	// they are logged as warnings.
+	OnMismatch func(ctx Context, m CanaryMismatch) `json:"-"`
+}
+
+// CanaryMismatch describes a call whose resolvers disagreed.
+type CanaryMismatch struct {
+	Input any
+	// Output and Err are the result of the function's resolver.
+	Output any
+	Err    error
+	// CanaryOutput and CanaryErr are the result of the canary resolver.
+	CanaryOutput any
+	CanaryErr    error
+	// Paths are the JSON pointers at which the outputs differ, when both
+	// resolvers succeeded.
+	Paths []string
+}
+
+// resolveCanary calls the function's resolver or its canary, and compares
+// their outputs if CompareOutputs is set.
+func (f *Function) resolveCanary(ctx Context, input any) (any, error) {
+	canary := f.Canary
+	useCanary := rand.Float64()*100 < canary.Percent
+	if !canary.CompareOutputs {
+		if useCanary {
+			return canary.Resolver(ctx, input)
+		}
+		return f.Resolver(ctx, input)
+	}
+
+	var m CanaryMismatch
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.Output, m.Err = recoverResolver(ctx, input, f.Resolver)
+	}()
+	go func() {
+		defer wg.Done()
+		m.CanaryOutput, m.CanaryErr = recoverResolver(ctx, input, canary.Resolver)
+	}()
+	wg.Wait()
+
+	if m.Err == nil && m.CanaryErr == nil {
+		m.Paths = DiffJSON(m.Output, m.CanaryOutput)
+	}
+	if len(m.Paths) > 0 || (m.Err == nil) != (m.CanaryErr == nil) {
+		m.Input = input
+		if canary.OnMismatch != nil {
+			canary.OnMismatch(ctx, m)
+		} else {
+			ctx.Logger().Warn("Canary output mismatch", "paths", m.Paths, "error", m.Err, "canaryError", m.CanaryErr)
+		}
+	}
+
+	if useCanary {
+		return m.CanaryOutput, m.CanaryErr
+	}
+	return m.Output, m.Err
+}
+
+// DiffJSON returns the JSON pointers at which the JSON forms of a and b
+// differ, sorted, e.g. ["/items/0/price"]. It returns nil if they are equal.
+func DiffJSON(a, b any) []string {
+	return diffJSON(toJSONValue(a), toJSONValue(b), "")
+}
+
+func diffJSON(a, b any, path string) []string {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			return []string{path}
+		}
+		keys := make(map[string]bool)
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		var paths []string
+		for _, k := range sortedKeys(keys) {
+			paths = append(paths, diffJSON(av[k], bv[k], pointer(path, k))...)
+		}
+		return paths
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return []string{path}
+		}
+		var paths []string
+		for i := range av {
+			paths = append(paths, diffJSON(av[i], bv[i], fmt.Sprintf("%s/%d", path, i))...)
+		}
+		sort.Strings(paths)
+		return paths
+	}
+	if !reflect.DeepEqual(a, b) {
+		return []string{path}
+	}
+	return nil
+}
+
+// validateCanary checks a function's canary settings.
+func validateCanary(fn Function) error {
+	canary := fn.Canary
+	if canary.Resolver == nil {
+		return fmt.Errorf("canary: resolver is required")
+	}
+	if fn.Resolver == nil {
+		return fmt.Errorf("canary: the function needs a resolver to compare against")
+	}
+	if canary.Percent < 0 || canary.Percent > 100 {
+		return fmt.Errorf("canary: percent must be between 0 and 100")
+	}
+	if canary.CompareOutputs && !fn.IsReadOnly {
+		return fmt.Errorf("canary: comparing outputs runs both resolvers, so it requires a read-only function")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func canaryFunction(canary *Canary) Function {
+	return Function{
+		Description: "Get a user",
+		Access:      []string{"admin"},
+		IsReadOnly:  true,
+		Inputs:      Object(map[string]Schema{}),
+		Outputs:     Object(map[string]Schema{"name": String()}),
+		Resolver: func(ctx Context, input any) (any, error) {
+			return map[string]any{"name": "old", "tags": []any{"a"}}, nil
+		},
+		Canary: canary,
+	}
+}
+
+func TestCanaryRouting(t *testing.T) {
+	config := &Config{}
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+	newResolver := func(ctx Context, input any) (any, error) {
+		return map[string]any{"name": "new"}, nil
+	}
+
+	for percent, want := range map[float64]string{0: "old", 100: "new"} {
+		output, err := config.Resolve(ctx, canaryFunction(&Canary{Resolver: newResolver, Percent: percent}), map[string]any{})
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if got := output.(map[string]any)["name"]; got != want {
+			t.Errorf("Percent %v: got %v, want %v", percent, got, want)
+		}
+	}
+}
+
+func TestCanaryCompareOutputs(t *testing.T) {
+	config := &Config{}
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+
+	var mismatches []CanaryMismatch
+	fn := canaryFunction(&Canary{
+		Resolver: func(ctx Context, input any) (any, error) {
+			return map[string]any{"name": "new", "tags": []any{"b"}}, nil
+		},
+		CompareOutputs: true,
+		OnMismatch:     func(ctx Context, m CanaryMismatch) { mismatches = append(mismatches, m) },
+	})
+
+	output, err := config.Resolve(ctx, fn, map[string]any{})
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if output.(map[string]any)["name"] != "old" {
+		t.Errorf("caller should get the current resolver's output, got %v", output)
+	}
+	if len(mismatches) != 1 || !reflect.DeepEqual(mismatches[0].Paths, []string{"/name", "/tags/0"}) {
+		t.Errorf("unexpected mismatches: %+v", mismatches)
+	}
+
+	// Matching outputs aren't reported; an error on one side is
+	mismatches = nil
+	fn.Canary.Resolver = fn.Resolver
+	config.Resolve(ctx, fn, map[string]any{})
+	if len(mismatches) != 0 {
+		t.Errorf("matching outputs reported: %+v", mismatches)
+	}
+	fn.Canary.Resolver = func(ctx Context, input any) (any, error) { return nil, errors.New("boom") }
+	config.Resolve(ctx, fn, map[string]any{})
+	if len(mismatches) != 1 || mismatches[0].CanaryErr == nil {
+		t.Errorf("expected canary error to be reported, got %+v", mismatches)
+	}
+
+	// A panicking canary is reported as its error, without failing the call
+	mismatches = nil
+	fn.Canary.Resolver = panics
+	output, err = config.Resolve(ctx, fn, map[string]any{})
+	if err != nil || output.(map[string]any)["name"] != "old" {
+		t.Errorf("Resolve with panicking canary = %v, %v", output, err)
+	}
+	if len(mismatches) != 1 || mismatches[0].CanaryErr == nil || !strings.Contains(mismatches[0].CanaryErr.Error(), "panicked") {
+		t.Errorf("expected canary panic to be reported, got %+v", mismatches)
+	}
+}
+
+func TestValidateCanary(t *testing.T) {
+	resolver := func(ctx Context, input any) (any, error) { return nil, nil }
+	tests := []struct {
+		name    string
+		canary  *Canary
+		mutate  func(*Function)
+		wantErr string
+	}{
+		{"no resolver", &Canary{Percent: 10}, nil, "canary: resolver is required"},
+		{"percent", &Canary{Resolver: resolver, Percent: 150}, nil, "between 0 and 100"},
+		{"mutation", &Canary{Resolver: resolver, CompareOutputs: true}, func(fn *Function) { fn.IsReadOnly = false }, "requires a read-only function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Name:         "test",
+				AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+				Entities:     map[string]Entity{},
+				Functions:    map[string]Function{"getUser": canaryFunction(tt.canary)},
+			}
+			if tt.mutate != nil {
+				fn := config.Functions["getUser"]
+				tt.mutate(&fn)
+				config.Functions["getUser"] = fn
+			}
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
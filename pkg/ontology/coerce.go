@@ -0,0 +1,138 @@
+package ontology
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coerceDateTimeLayouts are the ISO 8601 forms Coerce accepts for
+// date-time strings. Layouts without an offset are read as UTC.
+var coerceDateTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Coerce converts loosely typed values in data to the types schema expects,
+// for clients that stringify everything (query parameters, some MCP
+// clients). It converts numeric strings to numbers ("42" -> 42), "true" and
+// "false" to booleans, numbers to strings, and ISO 8601 date-times in
+// common forms ("2024-05-01", "2024-05-01 10:00:00") to RFC 3339. Values
+// that can't be converted are left as-is for validation to reject. Data
+// should be in its JSON form; it is not modified.
+func Coerce(schema Schema, data any) any {
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		result := make(map[string]any, len(m))
+		for key, val := range m {
+			if prop, ok := s.properties[key]; ok {
+				val = Coerce(prop, val)
+			}
+			result[key] = val
+		}
+		return result
+	case *ArraySchema:
+		arr, ok := data.([]any)
+		if !ok {
+			return data
+		}
+		result := make([]any, len(arr))
+		for i, item := range arr {
+			result[i] = Coerce(s.items, item)
+		}
+		return result
+	case *NumberSchema:
+		str, ok := data.(string)
+		if !ok {
+			return data
+		}
+		if s.isInt64 {
+			if i, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64); err == nil {
+				return i
+			}
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		if err != nil {
+			return data
+		}
+		return n
+	case *BooleanSchema:
+		str, ok := data.(string)
+		if !ok {
+			return data
+		}
+		switch strings.ToLower(strings.TrimSpace(str)) {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+		return data
+	case *StringSchema:
+		if n, ok := data.(float64); ok {
+			return strconv.FormatFloat(n, 'f', -1, 64)
+		}
+		if str, ok := data.(string); ok && s.format == "date-time" {
+			return coerceDateTime(str)
+		}
+		return data
+	case *NullableSchema:
+		if data == nil {
+			return nil
+		}
+		return Coerce(s.inner, data)
+	case *RefSchema:
+		if s.target == nil {
+			return data
+		}
+		return Coerce(s.target, data)
+	case *DiscriminatedUnionSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		tag, _ := m[s.discriminator].(string)
+		variant, ok := s.variants[tag]
+		if !ok {
+			return data
+		}
+		return Coerce(variant, data)
+	case *OneOfSchema:
+		// Use the first alternative the coerced data matches
+		for _, alt := range s.alternatives {
+			coerced := Coerce(alt, data)
+			if alt.Validate(coerced) == nil {
+				return coerced
+			}
+		}
+		return data
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			data = Coerce(part, data)
+		}
+		return data
+	default:
+		return data
+	}
+}
+
+// coerceDateTime rewrites an ISO 8601 date-time in one of the accepted
+// layouts as RFC 3339, or returns it unchanged.
+func coerceDateTime(str string) string {
+	trimmed := strings.TrimSpace(str)
+	for _, layout := range coerceDateTimeLayouts {
+		if t, err := time.Parse(layout, trimmed); err == nil {
+			return t.Format(time.RFC3339Nano)
+		}
+	}
+	return str
+}
@@ -0,0 +1,84 @@
+package ontology
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoerce(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"limit":  Integer(),
+		"ratio":  Number(),
+		"active": Boolean(),
+		"zip":    String(),
+		"since":  String().DateTime(),
+		"tags":   Array(Integer()),
+		"owner":  Nullable(Object(map[string]Schema{"age": Integer()})),
+		"choice": OneOf(Boolean(), Integer()),
+		"extra":  Any(),
+	})
+
+	got := Coerce(schema, map[string]any{
+		"limit":   " 42 ",
+		"ratio":   "0.5",
+		"active":  "TRUE",
+		"zip":     90210.0,
+		"since":   "2024-05-01 10:30:00",
+		"tags":    []any{"1", 2.0},
+		"owner":   map[string]any{"age": "37"},
+		"choice":  "7",
+		"extra":   "1",
+		"unknown": "2",
+	})
+	want := map[string]any{
+		"limit":   42.0,
+		"ratio":   0.5,
+		"active":  true,
+		"zip":     "90210",
+		"since":   "2024-05-01T10:30:00Z",
+		"tags":    []any{1.0, 2.0},
+		"owner":   map[string]any{"age": 37.0},
+		"choice":  7.0,
+		"extra":   "1",
+		"unknown": "2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Coerce = %v, want %v", got, want)
+	}
+	if err := schema.Validate(got); err != nil {
+		t.Errorf("coerced data should validate: %v", err)
+	}
+}
+
+func TestCoerceLeavesInvalidValues(t *testing.T) {
+	tests := []struct {
+		schema Schema
+		data   any
+	}{
+		{Integer(), "forty-two"},
+		{Boolean(), "yes"},
+		{Boolean(), "1"},
+		{String().DateTime(), "May 1st"},
+		{Nullable(Integer()), nil},
+		{Object(map[string]Schema{"a": Integer()}), "not an object"},
+	}
+	for _, tt := range tests {
+		if got := Coerce(tt.schema, tt.data); !reflect.DeepEqual(got, tt.data) {
+			t.Errorf("Coerce(%s, %v) = %v, want unchanged", tt.schema.TypeName(), tt.data, got)
+		}
+	}
+}
+
+func TestCoerceDateTimeLayouts(t *testing.T) {
+	tests := map[string]string{
+		"2024-05-01":                "2024-05-01T00:00:00Z",
+		"2024-05-01T10:30":          "2024-05-01T10:30:00Z",
+		"2024-05-01T10:30:00+02:00": "2024-05-01T10:30:00+02:00",
+		"2024-05-01 10:30:00.5Z":    "2024-05-01T10:30:00.5Z",
+	}
+	for input, want := range tests {
+		if got := Coerce(String().DateTime(), input); got != want {
+			t.Errorf("Coerce(%q) = %v, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package ontology
+
+import "fmt"
+
+// ValidateFunc checks a value against a compiled schema.
+type ValidateFunc func(data any) error
+
+// validators holds a function's compiled input and output validators.
+type validators struct {
+	input  ValidateFunc
+	output ValidateFunc
+}
+
+// Compile returns a validator equivalent to schema.Validate that is built
+// once and reused: objects, arrays, unions and references are resolved to
+// closures up front, and values in their JSON form (map[string]any, []any)
+// are checked without reflection. Other values, such as structs returned by
+// resolvers, fall back to Validate. Config.Validate compiles the inputs and
+// outputs of every function, so ValidateInput and ValidateOutput use
+// compiled validators once the config is validated.
+//
+// Compile schemas with references after Config.Validate has resolved them;
+// unresolved references fall back to Validate.
+func Compile(schema Schema) ValidateFunc {
+	return (&compiler{refs: make(map[string]*ValidateFunc)}).compile(schema)
+}
+
+// compiler compiles one schema tree. refs shares the validator of each
+// named schema, so recursive schemas compile to a cycle of closures.
+type compiler struct {
+	refs map[string]*ValidateFunc
+}
+
+func (c *compiler) compile(schema Schema) ValidateFunc {
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		return c.compileObject(s)
+	case *ArraySchema:
+		return c.compileArray(s)
+	case *NullableSchema:
+		inner := c.compile(s.inner)
+		return func(data any) error {
+			if !isNull(data) {
+				if err := inner(data); err != nil {
+					return err
+				}
+			}
+			return s.refine(data)
+		}
+	case *OneOfSchema:
+		return c.compileOneOf(s)
+	case *AllOfSchema:
+		return c.compileAllOf(s)
+	case *DiscriminatedUnionSchema:
+		return c.compileUnion(s)
+	case *RefSchema:
+		return c.compileRef(s)
+	case nil:
+		return func(any) error { return fmt.Errorf("schema is nil") }
+	}
+	// Scalars don't use reflection
+	return schema.Validate
+}
+
+// compiledProperty is an object property and its validator.
+type compiledProperty struct {
+	name     string
+	validate ValidateFunc
+}
+
+func (c *compiler) compileObject(o *ObjectSchema, allowed ...string) ValidateFunc {
+	props := make([]compiledProperty, 0, len(o.properties))
+	for _, name := range sortedKeys(o.properties) {
+		props = append(props, compiledProperty{name, c.compile(o.properties[name])})
+	}
+	return func(data any) error {
+		m, ok := data.(map[string]any)
+		if !ok {
+			// Structs and other map types
+			return o.Validate(data)
+		}
+		if o.strict {
+			for key := range m {
+				if _, ok := o.properties[key]; !ok && !contains(allowed, key) && !o.matchesPattern(key) {
+					// Report the first unknown field in order, as Validate does
+					return o.unknownField(m, allowed...)
+				}
+			}
+		}
+		for _, name := range o.required {
+			if _, ok := m[name]; !ok {
+				return missingField(name, o.properties[name], fmt.Sprintf("required field '%s' is missing", name))
+			}
+		}
+		for _, prop := range props {
+			if v, ok := m[prop.name]; ok {
+				if err := prop.validate(v); err != nil {
+					return fmt.Errorf("field '%s': %w", prop.name, err)
+				}
+			}
+		}
+		if err := o.checkKeys(m); err != nil {
+			return err
+		}
+		return o.checkRules(m)
+	}
+}
+
+func (c *compiler) compileArray(a *ArraySchema) ValidateFunc {
+	items := c.compile(a.items)
+	return func(data any) error {
+		arr, ok := data.([]any)
+		if !ok {
+			// Typed slices and arrays
+			return a.Validate(data)
+		}
+		if arr == nil {
+			return a.message(constraintError("type", "array", nil, "array cannot be nil - use empty slice []T{} instead"))
+		}
+		if err := a.checkLength(len(arr)); err != nil {
+			return a.message(err)
+		}
+		for i, item := range arr {
+			if err := items(item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		if err := a.checkUnique(arr); err != nil {
+			return a.message(err)
+		}
+		return a.refine(data)
+	}
+}
+
+func (c *compiler) compileOneOf(o *OneOfSchema) ValidateFunc {
+	alternatives := make([]ValidateFunc, len(o.alternatives))
+	for i, alt := range o.alternatives {
+		alternatives[i] = c.compile(alt)
+	}
+	return func(data any) error {
+		matches := 0
+		for _, alt := range alternatives {
+			if alt(data) == nil {
+				matches++
+			}
+		}
+		switch matches {
+		case 1:
+			return o.refine(data)
+		case 0:
+			// Validate collects the message of every alternative
+			return o.Validate(data)
+		default:
+			return fmt.Errorf("value matches %d alternatives, expected exactly one", matches)
+		}
+	}
+}
+
+func (c *compiler) compileAllOf(a *AllOfSchema) ValidateFunc {
+	schemas := make([]ValidateFunc, len(a.schemas))
+	for i, schema := range a.schemas {
+		schemas[i] = c.compile(schema)
+	}
+	return func(data any) error {
+		for i, schema := range schemas {
+			if err := schema(data); err != nil {
+				return fmt.Errorf("schema %d: %w", i, err)
+			}
+		}
+		return a.refine(data)
+	}
+}
+
+func (c *compiler) compileUnion(d *DiscriminatedUnionSchema) ValidateFunc {
+	variants := make(map[string]ValidateFunc, len(d.variants))
+	for _, tag := range d.Tags() {
+		variants[tag] = c.compileObject(d.variants[tag], d.discriminator)
+	}
+	return func(data any) error {
+		m, ok := data.(map[string]any)
+		if !ok {
+			return d.Validate(data)
+		}
+		tag, ok := m[d.discriminator].(string)
+		variant, known := variants[tag]
+		if !ok || !known {
+			// Validate reports the missing or unknown tag
+			return d.Validate(data)
+		}
+		if err := variant(m); err != nil {
+			return fmt.Errorf("variant '%s': %w", tag, err)
+		}
+		return d.refine(data)
+	}
+}
+
+func (c *compiler) compileRef(r *RefSchema) ValidateFunc {
+	compiled, ok := c.refs[r.name]
+	if !ok {
+		compiled = new(ValidateFunc)
+		c.refs[r.name] = compiled
+		if r.target != nil {
+			*compiled = c.compile(r.target)
+		}
+	}
+	return func(data any) error {
+		if *compiled == nil {
+			return r.Validate(data)
+		}
+		if err := (*compiled)(data); err != nil {
+			return err
+		}
+		return r.refine(data)
+	}
+}
+
+// compileValidators compiles the inputs and outputs of every function, and
+// prepares the reporting of undeclared context use.
+func (c *Config) compileValidators() {
+	for name, fn := range c.Functions {
+		fn.validators = &validators{input: Compile(fn.CallInputs()), output: Compile(fn.Outputs)}
+		fn.contextUse = &contextUse{name: name}
+		c.Functions[name] = fn
+	}
+}
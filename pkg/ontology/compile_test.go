@@ -0,0 +1,138 @@
+package ontology
+
+import (
+	"fmt"
+	"testing"
+)
+
+func compileTestSchema() *ObjectSchema {
+	return Object(map[string]Schema{
+		"id":    String().UUID(),
+		"email": String().Email(),
+		"age":   Nullable(Integer().Min(0)),
+		"tags":  Array(String()).MaxItems(2),
+		"payment": DiscriminatedUnion("type", map[string]*ObjectSchema{
+			"card": Object(map[string]Schema{"last4": String()}).Strict(),
+		}),
+		"contact": OneOf(
+			Object(map[string]Schema{"phone": String()}).Strict(),
+			Object(map[string]Schema{"fax": String()}).Strict(),
+		),
+	}).Optional("age", "payment", "contact").Strict()
+}
+
+func TestCompileMatchesValidate(t *testing.T) {
+	schema := compileTestSchema()
+	valid := func(change func(m map[string]any)) map[string]any {
+		m := map[string]any{
+			"id":    "123e4567-e89b-12d3-a456-426614174000",
+			"email": "ada@example.com",
+			"tags":  []any{"a"},
+		}
+		change(m)
+		return m
+	}
+
+	tests := map[string]any{
+		"valid":          valid(func(m map[string]any) {}),
+		"null":           valid(func(m map[string]any) { m["age"] = nil }),
+		"missing":        valid(func(m map[string]any) { delete(m, "email") }),
+		"format":         valid(func(m map[string]any) { m["id"] = "nope" }),
+		"unknown":        valid(func(m map[string]any) { m["zz"] = 1.0 }),
+		"nil array":      valid(func(m map[string]any) { m["tags"] = []any(nil) }),
+		"max items":      valid(func(m map[string]any) { m["tags"] = []any{"a", "b", "c"} }),
+		"item":           valid(func(m map[string]any) { m["tags"] = []any{1.0} }),
+		"variant":        valid(func(m map[string]any) { m["payment"] = map[string]any{"type": "card", "last4": "1234"} }),
+		"variant field":  valid(func(m map[string]any) { m["payment"] = map[string]any{"type": "card"} }),
+		"unknown tag":    valid(func(m map[string]any) { m["payment"] = map[string]any{"type": "cash"} }),
+		"one of":         valid(func(m map[string]any) { m["contact"] = map[string]any{"fax": "1"} }),
+		"no alternative": valid(func(m map[string]any) { m["contact"] = map[string]any{"email": "1"} }),
+		"not an object":  "ada",
+		"struct": struct {
+			ID    string   `json:"id"`
+			Email string   `json:"email"`
+			Tags  []string `json:"tags"`
+		}{"123e4567-e89b-12d3-a456-426614174000", "bad", []string{}},
+	}
+
+	compiled := Compile(schema)
+	for name, data := range tests {
+		want, got := schema.Validate(data), compiled(data)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s: compiled = %v, Validate = %v", name, got, want)
+		}
+	}
+}
+
+func TestCompileRecursiveRef(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]Entity{},
+		Schemas: map[string]Schema{
+			"Comment": Object(map[string]Schema{
+				"text":    String().Min(1),
+				"replies": Array(Ref("Comment")),
+			}),
+		},
+		Functions: map[string]Function{
+			"getThread": {
+				Description: "Get a thread",
+				Access:      []string{"public"},
+				Inputs:      Object(map[string]Schema{}),
+				Outputs:     Ref("Comment"),
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	fn := config.Functions["getThread"]
+	if fn.validators == nil {
+		t.Fatal("Validate should compile the function's validators")
+	}
+	thread := map[string]any{"text": "a", "replies": []any{
+		map[string]any{"text": "b", "replies": []any{map[string]any{"text": "", "replies": []any{}}}},
+	}}
+	err := fn.ValidateOutput(thread)
+	want := "output validation failed: field 'replies': item 0: field 'replies': item 0: field 'text': string length 0 is less than minimum 1"
+	if err == nil || err.Error() != want {
+		t.Errorf("got %v, want %s", err, want)
+	}
+}
+
+func benchmarkItems(n int) []any {
+	items := make([]any, n)
+	for i := range items {
+		items[i] = map[string]any{
+			"id":    "123e4567-e89b-12d3-a456-426614174000",
+			"email": "ada@example.com",
+			"age":   float64(i),
+			"tags":  []any{"a", "b"},
+		}
+	}
+	return items
+}
+
+func BenchmarkValidateLargeArray(b *testing.B) {
+	schema := Array(compileTestSchema())
+	items := benchmarkItems(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := schema.Validate(items); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompiledLargeArray(b *testing.B) {
+	validate := Compile(Array(compileTestSchema()))
+	items := benchmarkItems(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := validate(items); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
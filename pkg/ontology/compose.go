@@ -0,0 +1,134 @@
+package ontology
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Extend returns a new object with the properties of o and other. Properties
+// of other replace those of o with the same name. Required fields, computed
+// fields, rules, refinements and transforms of both objects carry over; the
+// result is strict if o is.
+// Like Pick, Omit and Partial, Extend leaves o unchanged, so a shared entity
+// shape can be reused across functions.
+func (o *ObjectSchema) Extend(other *ObjectSchema) *ObjectSchema {
+	result := o.derive(func(name string) bool {
+		_, replaced := other.properties[name]
+		return !replaced
+	})
+	for _, name := range sortedKeys(other.properties) {
+		result.properties[name] = other.properties[name]
+		if contains(other.required, name) {
+			result.required = append(result.required, name)
+		}
+		other.copyComputed(result, name)
+	}
+	result.rules = append(result.rules, other.rules...)
+	result.patterns = append(slices.Clone(result.patterns), other.patterns...)
+	result.refines = append(append(result.refines, o.refines...), other.refines...)
+	result.transformFns = append(append(result.transformFns, o.transformFns...), other.transformFns...)
+	return result
+}
+
+// Pick returns a new object with only the named properties. Rules that read
+// other properties are dropped, as are refinements and object transforms.
+// Pick panics on an unknown property name.
+func (o *ObjectSchema) Pick(names ...string) *ObjectSchema {
+	o.mustHave(names)
+	return o.derive(func(name string) bool {
+		return contains(names, name)
+	})
+}
+
+// Omit returns a new object without the named properties. Rules that read
+// them are dropped, as are refinements and object transforms. Omit panics
+// on an unknown property name.
+func (o *ObjectSchema) Omit(names ...string) *ObjectSchema {
+	o.mustHave(names)
+	return o.derive(func(name string) bool {
+		return !contains(names, name)
+	})
+}
+
+// Partial returns a new object whose properties are all optional, e.g. for
+// patch-style update inputs. Rules, refinements and object transforms are
+// dropped since the fields they read may now be missing.
+func (o *ObjectSchema) Partial() *ObjectSchema {
+	result := o.derive(func(string) bool { return true })
+	result.required = []string{}
+	result.rules = nil
+	return result
+}
+
+// derive copies o with the properties for which keep returns true.
+// Examples are not copied since they may not fit the new shape.
+func (o *ObjectSchema) derive(keep func(name string) bool) *ObjectSchema {
+	result := &ObjectSchema{
+		properties: make(map[string]Schema),
+		required:   []string{},
+		strict:     o.strict,
+		patterns:   o.patterns,
+	}
+	result.description = o.description
+
+	for _, name := range sortedKeys(o.properties) {
+		if !keep(name) {
+			continue
+		}
+		result.properties[name] = o.properties[name]
+		o.copyComputed(result, name)
+	}
+	for _, name := range o.required {
+		if _, ok := result.properties[name]; ok {
+			result.required = append(result.required, name)
+		}
+	}
+	for _, rule := range o.rules {
+		if result.readsOnlyOwnFields(rule.expr) {
+			result.rules = append(result.rules, rule)
+		}
+	}
+	return result
+}
+
+// copyComputed copies the computed definition of a property, if any, to dst.
+func (o *ObjectSchema) copyComputed(dst *ObjectSchema, name string) {
+	if fn, ok := o.computed[name]; ok {
+		if dst.computed == nil {
+			dst.computed = make(map[string]ComputeFunc)
+		}
+		dst.computed[name] = fn
+	} else {
+		delete(dst.computed, name)
+	}
+	if expr, ok := o.computedExprs[name]; ok {
+		if dst.computedExprs == nil {
+			dst.computedExprs = make(map[string]string)
+		}
+		dst.computedExprs[name] = expr
+	} else {
+		delete(dst.computedExprs, name)
+	}
+}
+
+// readsOnlyOwnFields reports whether every path in expr starts at one of
+// the object's properties.
+func (o *ObjectSchema) readsOnlyOwnFields(expr *Expr) bool {
+	for _, path := range expr.Paths() {
+		root, _, _ := strings.Cut(path, ".")
+		if _, ok := o.properties[root]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mustHave panics if any name isn't a property of o.
+func (o *ObjectSchema) mustHave(names []string) {
+	for _, name := range names {
+		if _, ok := o.properties[name]; !ok {
+			panic(fmt.Sprintf("ontology: object has no property '%s'", name))
+		}
+	}
+}
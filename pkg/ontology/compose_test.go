@@ -0,0 +1,124 @@
+package ontology
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func customerShape() *ObjectSchema {
+	return Object(map[string]Schema{
+		"id":    String().UUID(),
+		"name":  String(),
+		"email": String().Email(),
+		"notes": String(),
+	}).Optional("notes").Describe("A customer")
+}
+
+func sortedRequired(o *ObjectSchema) []string {
+	required := append([]string(nil), o.Required()...)
+	sort.Strings(required)
+	return required
+}
+
+func TestObjectExtend(t *testing.T) {
+	base := customerShape()
+	extended := base.Extend(Object(map[string]Schema{
+		"tier":  String().Enum("free", "pro"),
+		"notes": String().Max(10),
+	}).Optional("tier"))
+
+	if got := sortedKeys(extended.Properties()); !reflect.DeepEqual(got, []string{"email", "id", "name", "notes", "tier"}) {
+		t.Errorf("properties = %v", got)
+	}
+	// notes is replaced by the required version from other
+	if got := sortedRequired(extended); !reflect.DeepEqual(got, []string{"email", "id", "name", "notes"}) {
+		t.Errorf("required = %v", got)
+	}
+	if extended.Description() != "A customer" {
+		t.Errorf("description = %q", extended.Description())
+	}
+	if len(base.Properties()) != 4 || len(base.Required()) != 3 {
+		t.Error("Extend should not modify the receiver")
+	}
+}
+
+func TestObjectPickOmit(t *testing.T) {
+	base := customerShape().Strict()
+
+	picked := base.Pick("id", "notes")
+	if got := sortedKeys(picked.Properties()); !reflect.DeepEqual(got, []string{"id", "notes"}) {
+		t.Errorf("Pick properties = %v", got)
+	}
+	if got := picked.Required(); !reflect.DeepEqual(got, []string{"id"}) {
+		t.Errorf("Pick required = %v", got)
+	}
+	if !picked.IsStrict() {
+		t.Error("Pick should keep strictness")
+	}
+
+	omitted := base.Omit("email", "notes")
+	if got := sortedKeys(omitted.Properties()); !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Errorf("Omit properties = %v", got)
+	}
+	if err := omitted.Validate(map[string]any{"id": "7c9e6679-7425-40de-944b-e07fc1f90ae7", "name": "Ada"}); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Pick should panic on an unknown property")
+		}
+	}()
+	base.Pick("nope")
+}
+
+func TestObjectPartial(t *testing.T) {
+	base := customerShape()
+	patch := base.Partial()
+	if len(patch.Required()) != 0 {
+		t.Errorf("required = %v", patch.Required())
+	}
+	if err := patch.Validate(map[string]any{"name": "Ada"}); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+	if err := patch.Validate(map[string]any{"email": "nope"}); err == nil {
+		t.Error("Partial should keep property constraints")
+	}
+	if len(base.Required()) != 3 {
+		t.Error("Partial should not modify the receiver")
+	}
+}
+
+func TestObjectCompositionRulesAndComputed(t *testing.T) {
+	base := Object(map[string]Schema{
+		"start": Integer(),
+		"end":   Integer(),
+		"price": Number(),
+	}).Rule("start <= end", "").ComputedExpr("doubled", Number(), "price * 2")
+
+	if got := len(base.Pick("start", "end").Rules()); got != 1 {
+		t.Errorf("Pick kept %d rules, want 1", got)
+	}
+	if got := len(base.Omit("end").Rules()); got != 0 {
+		t.Errorf("Omit kept %d rules, want 0", got)
+	}
+	if got := len(base.Partial().Rules()); got != 0 {
+		t.Errorf("Partial kept %d rules, want 0", got)
+	}
+
+	picked := base.Pick("price", "doubled")
+	if got := picked.ComputedFields(); !reflect.DeepEqual(got, []string{"doubled"}) {
+		t.Errorf("computed fields = %v", got)
+	}
+	doubled := picked.JSONSchema()["properties"].(map[string]any)["doubled"].(map[string]any)
+	if doubled["x-ont-expression"] != "price * 2" {
+		t.Errorf("doubled schema = %v", doubled)
+	}
+
+	// Replacing a computed property with a plain one drops the computation
+	replaced := base.Extend(Object(map[string]Schema{"doubled": Number()}))
+	if len(replaced.ComputedFields()) != 0 {
+		t.Errorf("computed fields = %v", replaced.ComputedFields())
+	}
+}
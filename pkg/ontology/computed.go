@@ -0,0 +1,189 @@
+package ontology
+
+import "fmt"
+
+// ComputeFunc derives a field value from the other fields of an output object.
+// The object is in its JSON form: numbers are float64, nested objects are
+// map[string]any and arrays are []any.
+type ComputeFunc func(obj map[string]any) (any, error)
+
+// Computed declares a derived property. The server computes it after the
+// resolver returns, so resolvers don't set it themselves. The property is
+// part of the schema (marked readOnly), so generated clients and the lock
+// file see it like any other field. Computed properties belong on outputs.
+func (o *ObjectSchema) Computed(name string, schema Schema, fn ComputeFunc) *ObjectSchema {
+	if o.computed == nil {
+		o.computed = make(map[string]ComputeFunc)
+	}
+	o.properties[name] = schema
+	o.computed[name] = fn
+	if !contains(o.required, name) {
+		o.required = append(o.required, name)
+	}
+	return o
+}
+
+// ComputedFields returns the names of the computed properties.
+func (o *ObjectSchema) ComputedFields() []string {
+	return sortedKeys(o.computed)
+}
+
+// ApplyComputed fills in computed properties declared anywhere in schema.
+// Data without computed properties in its schema is returned unchanged;
+// otherwise it is converted to its JSON form first.
+func ApplyComputed(schema Schema, data any) (any, error) {
+	if !hasComputed(schema) {
+		return data, nil
+	}
+	return applyComputed(schema, toJSONValue(data), "")
+}
+
+func applyComputed(schema Schema, data any, path string) (any, error) {
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data, nil
+		}
+		// Nested values first so computed fields can use them
+		for name, propSchema := range s.properties {
+			if _, isComputed := s.computed[name]; isComputed {
+				continue
+			}
+			val, exists := m[name]
+			if !exists {
+				continue
+			}
+			updated, err := applyComputed(propSchema, val, joinPath(path, name))
+			if err != nil {
+				return nil, err
+			}
+			m[name] = updated
+		}
+		for _, name := range sortedKeys(s.computed) {
+			val, err := s.computed[name](m)
+			if err != nil {
+				return nil, fmt.Errorf("computed field '%s': %w", joinPath(path, name), err)
+			}
+			m[name] = val
+		}
+		return m, nil
+	case *ArraySchema:
+		arr, ok := data.([]any)
+		if !ok {
+			return data, nil
+		}
+		for i, item := range arr {
+			updated, err := applyComputed(s.items, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			arr[i] = updated
+		}
+		return arr, nil
+	case *NullableSchema:
+		if data == nil {
+			return nil, nil
+		}
+		return applyComputed(s.inner, data, path)
+	case *RefSchema:
+		if s.target == nil {
+			return data, nil
+		}
+		return applyComputed(s.target, data, path)
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			var err error
+			if data, err = applyComputed(part, data, path); err != nil {
+				return nil, err
+			}
+		}
+		return data, nil
+	case *DiscriminatedUnionSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data, nil
+		}
+		tag, _ := m[s.discriminator].(string)
+		variant, ok := s.variants[tag]
+		if !ok {
+			return data, nil
+		}
+		return applyComputed(variant, m, path)
+	case *OneOfSchema:
+		return applyComputedOneOf(s, data, path)
+	default:
+		return data, nil
+	}
+}
+
+// applyComputedOneOf fills in the computed properties of the alternative
+// data matches. Alternatives can't be matched before their computed
+// properties are set, since those are required, so each one is applied to
+// a copy of data and the first whose result validates is kept. Data that
+// matches no alternative is returned unchanged for validation to report.
+func applyComputedOneOf(s *OneOfSchema, data any, path string) (any, error) {
+	for _, alt := range s.alternatives {
+		if !hasComputed(alt) {
+			if alt.Validate(data) == nil {
+				return data, nil
+			}
+			continue
+		}
+		if updated, ok := tryComputed(alt, toJSONValue(data), path); ok && alt.Validate(updated) == nil {
+			return updated, nil
+		}
+	}
+	return data, nil
+}
+
+// tryComputed applies the computed properties of an alternative that data
+// may not match, so their functions may fail or panic on it.
+func tryComputed(schema Schema, data any, path string) (updated any, ok bool) {
+	defer func() {
+		if recover() != nil {
+			updated, ok = nil, false
+		}
+	}()
+	updated, err := applyComputed(schema, data, path)
+	return updated, err == nil
+}
+
+// hasComputed reports whether schema declares computed properties at any depth.
+func hasComputed(schema Schema) bool {
+	refs := make(map[string]*RefSchema)
+	collectRefs(schema, refs)
+	schemas := []Schema{schema}
+	for _, ref := range refs {
+		if ref.target != nil {
+			schemas = append(schemas, ref.target)
+		}
+	}
+
+	found := false
+	for _, s := range schemas {
+		walkSchema(s, func(s Schema) {
+			if obj, ok := s.(*ObjectSchema); ok && len(obj.computed) > 0 {
+				found = true
+			}
+		})
+	}
+	return found
+}
+
+// withReadOnly returns a copy of a JSON Schema marked readOnly.
+func withReadOnly(schema map[string]any) map[string]any {
+	result := make(map[string]any, len(schema)+1)
+	for k, v := range schema {
+		result[k] = v
+	}
+	result["readOnly"] = true
+	return result
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,179 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fullNameSchema() *ObjectSchema {
+	return Object(map[string]Schema{
+		"firstName": String(),
+		"lastName":  String(),
+	}).Computed("fullName", String(), func(obj map[string]any) (any, error) {
+		return obj["firstName"].(string) + " " + obj["lastName"].(string), nil
+	})
+}
+
+func TestComputedField(t *testing.T) {
+	type person struct {
+		FirstName string `json:"firstName"`
+		LastName  string `json:"lastName"`
+	}
+
+	got, err := ApplyComputed(fullNameSchema(), person{FirstName: "Ada", LastName: "Lovelace"})
+	if err != nil {
+		t.Fatalf("ApplyComputed() error = %v", err)
+	}
+
+	want := map[string]any{"firstName": "Ada", "lastName": "Lovelace", "fullName": "Ada Lovelace"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyComputed() = %#v, want %#v", got, want)
+	}
+
+	if err := fullNameSchema().Validate(got); err != nil {
+		t.Errorf("computed output should validate: %v", err)
+	}
+}
+
+func TestComputedFieldNested(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"items": Array(Object(map[string]Schema{
+			"price":    Number(),
+			"quantity": Integer(),
+		}).Computed("subtotal", Number(), func(obj map[string]any) (any, error) {
+			return obj["price"].(float64) * obj["quantity"].(float64), nil
+		})),
+	}).Computed("total", Number(), func(obj map[string]any) (any, error) {
+		// Item subtotals are computed before the parent's fields
+		total := 0.0
+		for _, item := range obj["items"].([]any) {
+			total += item.(map[string]any)["subtotal"].(float64)
+		}
+		return total, nil
+	})
+
+	data := map[string]any{
+		"items": []any{
+			map[string]any{"price": 2.5, "quantity": float64(2)},
+			map[string]any{"price": 1.0, "quantity": float64(3)},
+		},
+	}
+
+	got, err := ApplyComputed(schema, data)
+	if err != nil {
+		t.Fatalf("ApplyComputed() error = %v", err)
+	}
+
+	if total := got.(map[string]any)["total"]; total != 8.0 {
+		t.Errorf("total = %v, want 8", total)
+	}
+}
+
+func TestComputedFieldOneOf(t *testing.T) {
+	company := Object(map[string]Schema{"companyName": String()}).Strict()
+	schema := OneOf(fullNameSchema().Strict(), company)
+
+	got, err := ApplyComputed(schema, map[string]any{"firstName": "Ada", "lastName": "Lovelace"})
+	if err != nil {
+		t.Fatalf("ApplyComputed() error = %v", err)
+	}
+	if got.(map[string]any)["fullName"] != "Ada Lovelace" {
+		t.Errorf("ApplyComputed() = %v, want fullName set", got)
+	}
+	if err := schema.Validate(got); err != nil {
+		t.Errorf("computed output should validate: %v", err)
+	}
+
+	// The other alternative's computed fields aren't applied
+	got, err = ApplyComputed(schema, map[string]any{"companyName": "Acme"})
+	if err != nil || !reflect.DeepEqual(got, map[string]any{"companyName": "Acme"}) {
+		t.Errorf("ApplyComputed() = %v, %v, want unchanged", got, err)
+	}
+}
+
+func TestComputedFieldAllOf(t *testing.T) {
+	schema := AllOf(fullNameSchema(), Object(map[string]Schema{
+		"age": Integer(),
+	}).Computed("adult", Boolean(), func(obj map[string]any) (any, error) {
+		return obj["age"].(float64) >= 18, nil
+	}))
+
+	got, err := ApplyComputed(schema, map[string]any{"firstName": "Ada", "lastName": "Lovelace", "age": float64(36)})
+	if err != nil {
+		t.Fatalf("ApplyComputed() error = %v", err)
+	}
+	m := got.(map[string]any)
+	if m["fullName"] != "Ada Lovelace" || m["adult"] != true {
+		t.Errorf("ApplyComputed() = %v, want fields of every part computed", got)
+	}
+	if err := schema.Validate(got); err != nil {
+		t.Errorf("computed output should validate: %v", err)
+	}
+}
+
+func TestComputedFieldDiscriminatedUnion(t *testing.T) {
+	schema := DiscriminatedUnion("kind", map[string]*ObjectSchema{
+		"person":  fullNameSchema(),
+		"company": Object(map[string]Schema{"companyName": String()}),
+	})
+
+	got, err := ApplyComputed(schema, map[string]any{"kind": "person", "firstName": "Ada", "lastName": "Lovelace"})
+	if err != nil {
+		t.Fatalf("ApplyComputed() error = %v", err)
+	}
+	if got.(map[string]any)["fullName"] != "Ada Lovelace" {
+		t.Errorf("ApplyComputed() = %v, want fullName set", got)
+	}
+	if err := schema.Validate(got); err != nil {
+		t.Errorf("computed output should validate: %v", err)
+	}
+
+	got, err = ApplyComputed(schema, map[string]any{"kind": "company", "companyName": "Acme"})
+	if err != nil || !reflect.DeepEqual(got, map[string]any{"kind": "company", "companyName": "Acme"}) {
+		t.Errorf("ApplyComputed() = %v, %v, want unchanged", got, err)
+	}
+}
+
+func TestComputedFieldError(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"child": Object(map[string]Schema{}).Computed("x", String(), func(obj map[string]any) (any, error) {
+			return nil, errors.New("boom")
+		}),
+	})
+
+	_, err := ApplyComputed(schema, map[string]any{"child": map[string]any{}})
+	if err == nil || !strings.Contains(err.Error(), "computed field 'child.x'") {
+		t.Errorf("ApplyComputed() error = %v, want error naming child.x", err)
+	}
+}
+
+func TestComputedFieldJSONSchema(t *testing.T) {
+	schema := fullNameSchema().JSONSchema()
+	props := schema["properties"].(map[string]any)
+
+	fullName := props["fullName"].(map[string]any)
+	if fullName["readOnly"] != true {
+		t.Error("computed property should be marked readOnly")
+	}
+	if _, ok := props["firstName"].(map[string]any)["readOnly"]; ok {
+		t.Error("regular property should not be marked readOnly")
+	}
+	if !contains(schema["required"].([]string), "fullName") {
+		t.Error("computed property should be required")
+	}
+}
+
+func TestApplyComputedWithoutComputedFields(t *testing.T) {
+	type out struct{ Name string }
+	data := out{Name: "x"}
+
+	got, err := ApplyComputed(Object(map[string]Schema{"Name": String()}), data)
+	if err != nil {
+		t.Fatalf("ApplyComputed() error = %v", err)
+	}
+	if got != data {
+		t.Error("data should be returned unchanged when no fields are computed")
+	}
+}
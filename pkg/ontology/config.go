@@ -3,8 +3,11 @@
 package ontology
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 )
 
 // Config represents the complete ontology configuration.
@@ -18,16 +21,57 @@ type Config struct {
 	AccessGroups map[string]AccessGroup `json:"accessGroups" validate:"required"`
 	Entities     map[string]Entity      `json:"entities" validate:"required"`
 	Functions    map[string]Function    `json:"functions" validate:"required"`
+	Schemas      map[string]Schema      `json:"schemas,omitempty"` // Named schemas referenced with Ref
+	// Events are the domain events resolvers publish with Context.Emit, by
+	// name. Callers subscribe to them over SSE and MCP resource
+	// subscriptions. They are recorded in the lock file.
+	Events map[string]Event `json:"events,omitempty"`
+	// PublicConfig holds runtime settings for frontends, such as the API
+	// base path, auth client IDs and feature flags. The server serves it at
+	// /config.json and injects it into the SPA's index.html. Values must be
+	// plain JSON and never secrets. It isn't part of the hash, so it can
+	// differ between environments.
+	PublicConfig map[string]any `json:"publicConfig,omitempty"`
+}
+
+// Public returns a copy of the config without internal functions, for the
+// surfaces that describe the ontology to outsiders. The functions are shared
+// with c.
+func (c *Config) Public() *Config {
+	public := *c
+	public.Functions = make(map[string]Function, len(c.Functions))
+	for name, fn := range c.Functions {
+		if !fn.Internal {
+			public.Functions[name] = fn
+		}
+	}
+	return &public
 }
 
 // AccessGroup defines a group of users with specific permissions.
 type AccessGroup struct {
 	Description string `json:"description" validate:"required"`
+	// Descriptions translates Description by locale (see Localize).
+	Descriptions map[string]string `json:"descriptions,omitempty"`
 }
 
 // Entity represents a domain object in the ontology.
 type Entity struct {
 	Description string `json:"description" validate:"required"`
+	// Descriptions translates Description by locale (see Localize).
+	Descriptions map[string]string `json:"descriptions,omitempty"`
+	// LongDescription documents the entity in markdown, for the graph and
+	// introspection. It isn't part of the hash.
+	LongDescription string `json:"longDescription,omitempty"`
+	// Schema optionally declares the entity's fields. Functions reference
+	// it with Ref and the entity's name, like a named schema; generated
+	// SDKs declare it as a shared type, and the lock file records it.
+	Schema Schema `json:"-"`
+	// Key names the property that identifies an entity, e.g. "id". It must
+	// be a required property of Schema.
+	Key string `json:"key,omitempty"`
+	// Relations links the entity to others, for the domain graph.
+	Relations []Relation `json:"relations,omitempty"`
 }
 
 // UiConfig configures visualization for MCP Apps.
@@ -52,17 +96,139 @@ type Function struct {
 	Inputs      Schema       `json:"inputs" validate:"required"`
 	Outputs     Schema       `json:"outputs" validate:"required"`
 	Resolver    ResolverFunc `json:"-"` // Excluded from serialization
+	// TransformInput rewrites validated input before it reaches the resolver
+	// (e.g. trimming strings or migrating legacy field names).
+	TransformInput TransformFunc `json:"-"`
+	// TransformOutput rewrites the resolver's output before it is validated and sent.
+	TransformOutput TransformFunc `json:"-"`
 	// UI enables MCP App visualization. Set to non-nil to enable.
 	UI *UiConfig `json:"ui,omitempty"`
 	// IsReadOnly indicates if this function is a query (true) or mutation (false).
 	IsReadOnly bool `json:"isReadOnly" validate:"required"`
+	// UsesUserContext declares that the resolver reads the caller's user
+	// context, and UsesOrganizationContext that it reads the caller's
+	// organization (see OrganizationContext). Both are recorded in the lock
+	// file, and resolvers that read either without declaring it are logged.
+	// Validate can't report undeclared reads: resolvers are Go functions,
+	// so what they read is only known once they run.
+	UsesUserContext         bool `json:"usesUserContext,omitempty"`
+	UsesOrganizationContext bool `json:"usesOrganizationContext,omitempty"`
 	// IncludeInMcpListTools specifies whether this function should be included in MCP listTools responses.
 	IncludeInMcpListTools bool `json:"includeInMcpListTools" validate:"required"`
+	// Watchable exposes the function as a change feed. Its inputs must accept an
+	// optional "since" cursor and its outputs must return the current "cursor";
+	// clients long-poll until the cursor moves past "since".
+	Watchable bool `json:"watchable,omitempty"`
+	// Pipeline implements the function by chaining other functions instead
+	// of a Resolver.
+	Pipeline *Pipeline `json:"pipeline,omitempty"`
+	// AccessRule further restricts callers with an expression (see ParseExpr)
+	// over the validated input, the caller's user context and access groups,
+	// e.g. "input.orgId == user.orgId || 'admin' in accessGroups". It is
+	// recorded in the lock file.
+	AccessRule string `json:"accessRule,omitempty"`
+	// Tags group related functions, e.g. "billing" or "admin". They are
+	// shown in introspection and MCP tool metadata, and generated SDKs expose
+	// tagged functions under a namespace per tag (client.billing.invoiceReport).
+	Tags []string `json:"tags,omitempty"`
+	// Owner, Team and Runbook say who to page when the function fails: an
+	// owner (e.g. an email address), the owning team and a runbook URL. They
+	// are recorded in the lock file and included in failure alerts.
+	Owner   string `json:"owner,omitempty"`
+	Team    string `json:"team,omitempty"`
+	Runbook string `json:"runbook,omitempty"`
+	// Stability marks the function experimental, beta or stable (the
+	// default). Experimental functions are only served to callers that opt
+	// in. It is recorded in the lock file.
+	Stability Stability `json:"stability,omitempty"`
+	// Internal hides the function from public surfaces: MCP tools, ontology
+	// queries, the graph and generated SDKs (see Config.Public). It is still
+	// served over REST to callers in its access groups.
+	Internal bool `json:"internal,omitempty"`
+	// Aliases are former names of a renamed function. They are still served
+	// as routes and MCP tools, with a deprecation warning, until removed. They
+	// are recorded in the lock file.
+	Aliases []string `json:"aliases,omitempty"`
+	// FieldReferences take the allowed values of input fields from other
+	// functions (see FieldOptions). They are recorded in the lock file.
+	FieldReferences []FieldReference `json:"fieldReferences,omitempty"`
+	// RateLimit caps how often the function is called. The server answers
+	// calls over the limit with 429. It is recorded in the lock file.
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Version numbers the function when older versions are served side by
+	// side under versioned names, e.g. "getUser@1" next to getUser with
+	// Version 2. Versions are also served at /api/v{N}/{name}. It is
+	// recorded in the lock file.
+	Version int `json:"version,omitempty"`
+	// Canary sends a share of calls to a new resolver implementation,
+	// optionally comparing its outputs with the current one.
+	Canary *Canary `json:"canary,omitempty"`
+	// Headers and Query declare inputs sent as HTTP headers (by header
+	// name, e.g. "X-Tenant-Id") and URL query parameters over REST. They are
+	// validated with the body and merged into the resolver input under their
+	// property names; MCP tools take them as ordinary arguments (see
+	// CallInputs). They are recorded in the lock file.
+	Headers *ObjectSchema `json:"headers,omitempty"`
+	Query   *ObjectSchema `json:"query,omitempty"`
+	// Deprecated marks the function deprecated, with a message such as "use
+	// searchCustomers". It is still served, with a deprecation warning, and
+	// shown in MCP tool descriptions and as @deprecated in generated SDKs. It
+	// is recorded in the lock file.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Init prepares the function before it serves calls, e.g. preparing
+	// statements or loading a model. The server runs it during warm-up, or
+	// on the first call if that comes first.
+	Init InitFunc `json:"-"`
+	// Fallback calls a second resolver when the function's resolver is slow
+	// or fails. It is recorded in the lock file.
+	Fallback *Fallback `json:"fallback,omitempty"`
+	// Timeout bounds how long a call may run. Calls still running after it
+	// fail with ErrTimeout, and the resolver's request context is cancelled.
+	// Zero means no limit. It is recorded in the lock file.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// OutputVersions are older shapes of Outputs, by version name, that
+	// REST callers select with an Accept profile (see OutputVersion).
+	// OutputProfile optionally names the version Outputs itself is, so
+	// callers can pin it before it is replaced. Both are recorded in the
+	// lock file.
+	OutputVersions map[string]OutputVersion `json:"outputVersions,omitempty"`
+	OutputProfile  string                   `json:"outputProfile,omitempty"`
+	// Descriptions translates Description by locale, e.g. {"de": "..."},
+	// for MCP tool listings and introspection (see Localize). They are
+	// recorded in the lock file.
+	Descriptions map[string]string `json:"descriptions,omitempty"`
+	// LongDescription documents usage and caveats in markdown, beyond the
+	// one-line Description. It is added to the MCP tool description,
+	// truncated to fit (see server.WithToolDescriptionLimit), and to
+	// introspection and generated SDKs. Description stays the summary used
+	// in lists and hashes; LongDescription isn't part of the hash.
+	LongDescription string `json:"longDescription,omitempty"`
+	// Examples are documented calls, checked against the schemas by
+	// Validate, shown in MCP tool descriptions and generated SDKs, and
+	// replayed by RunExamples. They aren't part of the hash.
+	Examples []Example `json:"examples,omitempty"`
+	// SkipOutputValidation serves the resolver's output without validating
+	// it, for trusted hot paths returning very large results.
+	SkipOutputValidation bool `json:"-"`
+
+	// calls names the functions a composed function (e.g. a Report) calls.
+	calls []string
+
+	// validators are compiled by Config.Validate.
+	validators *validators
+	// contextUse reports undeclared context use, once Validate has run.
+	contextUse *contextUse
 }
 
 // ResolverFunc is the function signature for resolving API calls.
 type ResolverFunc func(ctx Context, input any) (any, error)
 
+// InitFunc initializes a function once before its first call.
+type InitFunc func(ctx context.Context) error
+
+// TransformFunc rewrites a value as it passes between the server and a resolver.
+type TransformFunc func(ctx Context, value any) (any, error)
+
 // Context provides contextual information for resolver functions.
 type Context interface {
 	// Request returns the underlying HTTP request.
@@ -76,6 +242,25 @@ type Context interface {
 
 	// UserContext returns user-specific context data.
 	UserContext() map[string]any
+
+	// Warn records a non-fatal warning that is returned to the client
+	// alongside the result (e.g. a deprecated parameter was used).
+	Warn(message string)
+
+	// Warnings returns the warnings recorded so far.
+	Warnings() []string
+
+	// WithLogFields returns a context whose Logger adds keysAndValues to
+	// every entry, e.g. ctx.WithLogFields("orderId", id). The server's
+	// contexts already include the function name and request ID, and the
+	// MCP session ID for tool calls. Warnings are shared with ctx.
+	WithLogFields(keysAndValues ...any) Context
+
+	// Emit publishes the named event from Config.Events to its
+	// subscribers, e.g. ctx.Emit("orderCreated", order). The payload is
+	// validated against the event's schema. Contexts that don't publish
+	// events return ErrNoEmitter.
+	Emit(event string, payload any) error
 }
 
 // Logger provides structured logging capabilities.
@@ -92,6 +277,14 @@ type requestContext struct {
 	logger       Logger
 	accessGroups []string
 	userContext  map[string]any
+
+	// warnings are shared by the contexts derived with WithLogFields.
+	warnings *warningList
+}
+
+type warningList struct {
+	mu       sync.Mutex
+	messages []string
 }
 
 func (c *requestContext) Request() *http.Request {
@@ -110,6 +303,30 @@ func (c *requestContext) UserContext() map[string]any {
 	return c.userContext
 }
 
+func (c *requestContext) Warn(message string) {
+	c.warnings.mu.Lock()
+	defer c.warnings.mu.Unlock()
+	c.warnings.messages = append(c.warnings.messages, message)
+}
+
+func (c *requestContext) Warnings() []string {
+	c.warnings.mu.Lock()
+	defer c.warnings.mu.Unlock()
+	result := make([]string, len(c.warnings.messages))
+	copy(result, c.warnings.messages)
+	return result
+}
+
+func (c *requestContext) WithLogFields(keysAndValues ...any) Context {
+	derived := *c
+	derived.logger = WithLogFields(c.logger, keysAndValues...)
+	return &derived
+}
+
+func (c *requestContext) Emit(event string, payload any) error {
+	return ErrNoEmitter
+}
+
 // NewContext creates a new request context.
 func NewContext(r *http.Request, logger Logger, accessGroups []string, userContext map[string]any) Context {
 	return &requestContext{
@@ -117,6 +334,7 @@ func NewContext(r *http.Request, logger Logger, accessGroups []string, userConte
 		logger:       logger,
 		accessGroups: accessGroups,
 		userContext:  userContext,
+		warnings:     &warningList{},
 	}
 }
 
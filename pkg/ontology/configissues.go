@@ -0,0 +1,159 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IssueCategory is what a ConfigIssue concerns.
+type IssueCategory string
+
+// Categories of config issues.
+const (
+	IssueConfig      IssueCategory = "config"
+	IssueAccessGroup IssueCategory = "accessGroup"
+	IssueEntity      IssueCategory = "entity"
+	IssueSchema      IssueCategory = "schema"
+	IssueFunction    IssueCategory = "function"
+	IssueEvent       IssueCategory = "event"
+	// IssueNames: functions or aliases share a route, tool or SDK name.
+	IssueNames IssueCategory = "names"
+)
+
+// ConfigIssue is an error or warning found by Config.ValidateAll.
+type ConfigIssue struct {
+	Category IssueCategory `json:"category"`
+	// Name is the access group, entity, schema, function or event at fault, if
+	// the issue concerns one.
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+	// Warning issues point at likely mistakes but don't fail Validate.
+	Warning bool `json:"warning,omitempty"`
+
+	err error
+}
+
+func (i *ConfigIssue) Error() string {
+	return i.Message
+}
+
+func (i *ConfigIssue) Unwrap() error {
+	return i.err
+}
+
+// ConfigIssues is every issue found by Config.ValidateAll, in a stable
+// order: errors by category, then warnings.
+type ConfigIssues []*ConfigIssue
+
+// add records err, if any, as an error.
+func (c *ConfigIssues) add(category IssueCategory, name string, err error) {
+	if err != nil {
+		*c = append(*c, &ConfigIssue{Category: category, Name: name, Message: err.Error(), err: err})
+	}
+}
+
+// warn records a warning.
+func (c *ConfigIssues) warn(category IssueCategory, name, format string, args ...any) {
+	*c = append(*c, &ConfigIssue{Category: category, Name: name, Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+// Errors returns the issues that aren't warnings.
+func (c ConfigIssues) Errors() ConfigIssues {
+	return c.filter(false)
+}
+
+// Warnings returns the warnings.
+func (c ConfigIssues) Warnings() ConfigIssues {
+	return c.filter(true)
+}
+
+func (c ConfigIssues) filter(warning bool) ConfigIssues {
+	var result ConfigIssues
+	for _, issue := range c {
+		if issue.Warning == warning {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
+
+// Err returns the errors as an error, or nil if there are none.
+func (c ConfigIssues) Err() error {
+	if errs := c.Errors(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (c ConfigIssues) Error() string {
+	if len(c) == 1 {
+		return c[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d config issues:\n", len(c))
+	for _, issue := range c {
+		b.WriteString("  - " + issue.Error() + "\n")
+	}
+	return b.String()
+}
+
+// Unwrap returns the issues, for errors.Is and errors.As.
+func (c ConfigIssues) Unwrap() []error {
+	errs := make([]error, len(c))
+	for i, issue := range c {
+		errs[i] = issue
+	}
+	return errs
+}
+
+// collectWarnings records likely mistakes that don't make the config
+// invalid: access groups and entities no function uses, functions that call
+// or take options from deprecated functions, and composed functions that
+// don't declare the context their callees use.
+func (c *Config) collectWarnings(issues *ConfigIssues) {
+	usedGroups, usedEntities := make(map[string]bool), make(map[string]bool)
+	for _, fn := range c.Functions {
+		for _, group := range fn.Access {
+			usedGroups[group] = true
+		}
+		for _, entity := range fn.Entities {
+			usedEntities[entity] = true
+		}
+	}
+	for _, event := range c.Events {
+		for _, group := range event.Access {
+			usedGroups[group] = true
+		}
+	}
+	for _, name := range sortedKeys(c.AccessGroups) {
+		if !usedGroups[name] {
+			issues.warn(IssueAccessGroup, name, "access group '%s' is not used by any function", name)
+		}
+	}
+	for _, name := range sortedKeys(c.Entities) {
+		if !usedEntities[name] {
+			issues.warn(IssueEntity, name, "entity '%s' is not used by any function", name)
+		}
+	}
+
+	for _, name := range sortedKeys(c.Functions) {
+		fn := c.Functions[name]
+		for _, callee := range functionCallees(fn) {
+			calleeFn := c.Functions[callee]
+			if calleeFn.Deprecated != "" && fn.Deprecated == "" {
+				issues.warn(IssueFunction, name, "function '%s' calls deprecated function '%s'", name, callee)
+			}
+			if calleeFn.UsesUserContext && !fn.UsesUserContext {
+				issues.warn(IssueFunction, name, "function '%s' calls '%s', which uses the user context, but doesn't set UsesUserContext", name, callee)
+			}
+			if calleeFn.UsesOrganizationContext && !fn.UsesOrganizationContext {
+				issues.warn(IssueFunction, name, "function '%s' calls '%s', which uses the organization, but doesn't set UsesOrganizationContext", name, callee)
+			}
+		}
+		for _, ref := range fn.FieldReferences {
+			if c.Functions[ref.FunctionName].Deprecated != "" && fn.Deprecated == "" {
+				issues.warn(IssueFunction, name, "function '%s' takes options for '%s' from deprecated function '%s'", name, ref.Path, ref.FunctionName)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConfigValidateAll(t *testing.T) {
+	config := queryConfig()
+	config.AccessGroups["auditors"] = AccessGroup{}
+	config.Entities["Invoice"] = Entity{Description: "An invoice"}
+	fn := config.Functions["getCustomer"]
+	fn.Description = ""
+	config.Functions["getCustomer"] = fn
+	fn = config.Functions["sendMessage"]
+	fn.Access = []string{"billing"}
+	config.Functions["sendMessage"] = fn
+	fn = config.Functions["deleteCustomer"]
+	fn.Deprecated = "use archiveCustomer"
+	config.Functions["deleteCustomer"] = fn
+
+	issues := config.ValidateAll()
+	type summary struct {
+		Category IssueCategory
+		Name     string
+		Warning  bool
+	}
+	var got []summary
+	for _, issue := range issues {
+		got = append(got, summary{issue.Category, issue.Name, issue.Warning})
+	}
+	want := []summary{
+		{IssueAccessGroup, "auditors", false},
+		{IssueFunction, "getCustomer", false},
+		{IssueFunction, "sendMessage", false},
+		{IssueAccessGroup, "auditors", true},
+		{IssueEntity, "Invoice", true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateAll() = %v, want %v", issues, want)
+	}
+	if len(issues.Errors()) != 3 || len(issues.Warnings()) != 2 {
+		t.Errorf("Errors() = %d, Warnings() = %d", len(issues.Errors()), len(issues.Warnings()))
+	}
+
+	err := config.Validate()
+	var issue *ConfigIssue
+	if !errors.As(err, &issue) || issue.Message != "access group 'auditors': description is required" {
+		t.Errorf("Validate() = %v, want the first issue to unwrap", err)
+	}
+}
+
+func TestConfigValidateAllWarnings(t *testing.T) {
+	config := graphConfig()
+	fn := config.Functions["getCustomer"]
+	fn.Deprecated = "use findCustomer"
+	fn.UsesUserContext = true
+	config.Functions["getCustomer"] = fn
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() = %v, warnings should not fail it", err)
+	}
+	var messages []string
+	for _, issue := range config.ValidateAll().Warnings() {
+		messages = append(messages, issue.Message)
+	}
+	for _, want := range []string{
+		"function 'pipeline' calls deprecated function 'getCustomer'",
+		"function 'pipeline' calls 'getCustomer', which uses the user context, but doesn't set UsesUserContext",
+	} {
+		if !contains(messages, want) {
+			t.Errorf("Warnings() = %q, want %q", messages, want)
+		}
+	}
+}
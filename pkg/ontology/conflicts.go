@@ -0,0 +1,42 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nameConflicts returns an error for each REST route, MCP tool name or
+// generated SDK type name that two functions or aliases end up with, such
+// as getUser and GetUser both generating GetUserInput.
+func (c *Config) nameConflicts() []error {
+	// owners maps each served name, e.g. "route '/api/getUser'", to what
+	// serves it
+	owners := make(map[string][]string)
+	add := func(owner string, names ...string) {
+		for _, name := range names {
+			if !contains(owners[name], owner) {
+				owners[name] = append(owners[name], owner)
+			}
+		}
+	}
+	for _, name := range sortedKeys(c.Functions) {
+		fn := c.Functions[name]
+		id := FunctionIdentifier(name)
+		owner := fmt.Sprintf("function '%s'", name)
+		add(owner, "route '/api/"+name+"'", "MCP tool '"+id+"'", "SDK type '"+capitalize(id)+"'")
+		if route, ok := VersionedRoute(name, fn); ok {
+			add(owner, "route '/api/"+route+"'")
+		}
+		for _, alias := range fn.Aliases {
+			add(fmt.Sprintf("alias '%s' of '%s'", alias, name), "route '/api/"+alias+"'", "MCP tool '"+alias+"'")
+		}
+	}
+
+	var conflicts []error
+	for _, name := range sortedKeys(owners) {
+		if len(owners[name]) > 1 {
+			conflicts = append(conflicts, fmt.Errorf("name conflict: %s is used by %s", name, strings.Join(owners[name], " and ")))
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,31 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateNameConflicts(t *testing.T) {
+	config := queryConfig()
+	config.Functions["GetCustomer"] = config.Functions["getCustomer"]
+	config.Functions["SendMessage"] = config.Functions["sendMessage"]
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Validate() should reject functions whose SDK types collide")
+	}
+	for _, want := range []string{
+		"SDK type 'GetCustomer' is used by function 'GetCustomer' and function 'getCustomer'",
+		"SDK type 'SendMessage' is used by function 'SendMessage' and function 'sendMessage'",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, want %q", err, want)
+		}
+	}
+
+	delete(config.Functions, "GetCustomer")
+	delete(config.Functions, "SendMessage")
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
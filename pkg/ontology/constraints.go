@@ -0,0 +1,141 @@
+package ontology
+
+// Constraints returns the schema's own constraints, keyed by the JSON
+// Schema keywords JSONSchema emits for them (e.g. "minLength", "pattern",
+// "enum"), for tools such as docs and form builders. Nested schemas aren't
+// included; walk them with Properties, ItemSchema and the like. The map is
+// a copy and empty if there are no constraints.
+func (o *ObjectSchema) Constraints() map[string]any {
+	result := map[string]any{}
+	if len(o.required) > 0 {
+		result["required"] = append([]string(nil), o.required...)
+	}
+	if o.strict {
+		result["additionalProperties"] = false
+	}
+	if o.minProperties != nil {
+		result["minProperties"] = *o.minProperties
+	}
+	if o.maxProperties != nil {
+		result["maxProperties"] = *o.maxProperties
+	}
+	if len(o.rules) > 0 {
+		result["x-ont-rules"] = o.Rules()
+	}
+	return o.annotateRefinements(result)
+}
+
+// Constraints returns the schema's constraints (see ObjectSchema.Constraints).
+func (s *StringSchema) Constraints() map[string]any {
+	result := map[string]any{}
+	if s.format != "" {
+		result["format"] = s.format
+	}
+	if s.minLength != nil {
+		result["minLength"] = *s.minLength
+	}
+	if s.maxLength != nil {
+		result["maxLength"] = *s.maxLength
+	}
+	if s.pattern != nil {
+		result["pattern"] = s.pattern.String()
+	}
+	if len(s.enum) > 0 {
+		result["enum"] = append([]string(nil), s.enum...)
+	}
+	return s.annotateRefinements(result)
+}
+
+// Constraints returns the schema's constraints (see ObjectSchema.Constraints).
+func (n *NumberSchema) Constraints() map[string]any {
+	result := map[string]any{}
+	if n.isInt64 {
+		result["format"] = "int64"
+	}
+	if n.minimum != nil {
+		result["minimum"] = *n.minimum
+	}
+	if n.maximum != nil {
+		result["maximum"] = *n.maximum
+	}
+	if n.exclusiveMinimum != nil {
+		result["exclusiveMinimum"] = *n.exclusiveMinimum
+	}
+	if n.exclusiveMaximum != nil {
+		result["exclusiveMaximum"] = *n.exclusiveMaximum
+	}
+	if n.multipleOf != nil {
+		result["multipleOf"] = *n.multipleOf
+	}
+	if len(n.enum) > 0 {
+		result["enum"] = append([]float64(nil), n.enum...)
+	}
+	return n.annotateRefinements(result)
+}
+
+// Constraints returns the schema's constraints (see ObjectSchema.Constraints).
+func (a *ArraySchema) Constraints() map[string]any {
+	result := map[string]any{}
+	if a.minItems != nil {
+		result["minItems"] = *a.minItems
+	}
+	if a.maxItems != nil {
+		result["maxItems"] = *a.maxItems
+	}
+	if a.unique {
+		result["uniqueItems"] = true
+	}
+	if a.uniqueBy != "" {
+		result["x-ont-uniqueBy"] = a.uniqueBy
+	}
+	return a.annotateRefinements(result)
+}
+
+// Constraints returns the schema's constraints (see ObjectSchema.Constraints).
+func (b *BytesSchema) Constraints() map[string]any {
+	result := map[string]any{}
+	if b.maxBytes != nil {
+		result["x-ont-maxBytes"] = *b.maxBytes
+	}
+	return b.annotateRefinements(result)
+}
+
+// Constraints returns the schema's refinements, its only constraints.
+func (b *BooleanSchema) Constraints() map[string]any {
+	return b.annotateRefinements(map[string]any{})
+}
+
+// Constraints returns the schema's own refinements; the wrapped schema's
+// constraints are on InnerSchema.
+func (n *NullableSchema) Constraints() map[string]any {
+	return n.annotateRefinements(map[string]any{})
+}
+
+// Constraints returns the schema's own refinements; each alternative has
+// its own constraints.
+func (o *OneOfSchema) Constraints() map[string]any {
+	return o.annotateRefinements(map[string]any{})
+}
+
+// Constraints returns the schema's own refinements; each part has its own
+// constraints.
+func (a *AllOfSchema) Constraints() map[string]any {
+	return a.annotateRefinements(map[string]any{})
+}
+
+// Constraints returns the schema's own refinements; each variant has its
+// own constraints.
+func (d *DiscriminatedUnionSchema) Constraints() map[string]any {
+	return d.annotateRefinements(map[string]any{})
+}
+
+// Constraints returns the schema's refinements, its only constraints.
+func (a *AnySchema) Constraints() map[string]any {
+	return a.annotateRefinements(map[string]any{})
+}
+
+// Constraints returns the reference's own refinements; the target's
+// constraints are on Target (or Deref).
+func (r *RefSchema) Constraints() map[string]any {
+	return r.annotateRefinements(map[string]any{})
+}
@@ -0,0 +1,45 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConstraints(t *testing.T) {
+	positive := func(v any) error {
+		if v.(float64) <= 0 {
+			return errors.New("not positive")
+		}
+		return nil
+	}
+	tests := []struct {
+		name   string
+		schema Schema
+		want   map[string]any
+	}{
+		{"string", String().Min(2).Max(10).Pattern(`^[a-z]+$`), map[string]any{"minLength": 2, "maxLength": 10, "pattern": `^[a-z]+$`}},
+		{"enum", String().Enum("a", "b"), map[string]any{"enum": []string{"a", "b"}}},
+		{"email", String().Email(), map[string]any{"format": "email"}},
+		{"number", Number().Min(0).Max(1).MultipleOf(0.5), map[string]any{"minimum": 0.0, "maximum": 1.0, "multipleOf": 0.5}},
+		{"refined", Number().Refine(positive, "must be positive"), map[string]any{"x-ont-refinements": []string{"must be positive"}}},
+		{"array", Array(String()).MinItems(1).UniqueBy("id"), map[string]any{"minItems": 1, "uniqueItems": true, "x-ont-uniqueBy": "id"}},
+		{"object", Object(map[string]Schema{"a": String(), "b": String()}).Optional("b").Strict(), map[string]any{"required": []string{"a"}, "additionalProperties": false}},
+		{"bytes", Bytes().MaxBytes(1024), map[string]any{"x-ont-maxBytes": 1024}},
+		{"boolean", Boolean(), map[string]any{}},
+		{"nullable", Nullable(String().Min(1)), map[string]any{}},
+		{"ref", Ref("User"), map[string]any{}},
+	}
+	for _, tt := range tests {
+		if got := tt.schema.Constraints(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Constraints() = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+
+	// The result is a copy
+	s := String().Enum("a", "b")
+	s.Constraints()["enum"].([]string)[0] = "z"
+	if err := s.Validate("a"); err != nil {
+		t.Errorf("changing Constraints() changed the schema: %v", err)
+	}
+}
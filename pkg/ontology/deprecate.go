@@ -0,0 +1,164 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeprecationWarning is the warning returned to callers of a deprecated
+// function or of a deprecated field they set.
+func DeprecationWarning(name, message string) string {
+	if message == "" {
+		return fmt.Sprintf("'%s' is deprecated", name)
+	}
+	return fmt.Sprintf("'%s' is deprecated: %s", name, message)
+}
+
+// Deprecation returns the message set with Deprecated and whether the
+// schema is deprecated.
+func (a *annotations) Deprecation() (string, bool) {
+	return a.deprecation, a.deprecated
+}
+
+// setDeprecated marks the schema deprecated, for schemas built from other
+// sources.
+func (a *annotations) setDeprecated(message string) {
+	a.deprecated = true
+	a.deprecation = message
+}
+
+// Deprecatable is implemented by schemas that can be marked deprecated.
+// Deprecated schemas stay valid; they are emitted as JSON Schema
+// "deprecated" (with the message as "x-ont-deprecated") and as @deprecated
+// in the generated TypeScript, so clients are warned before removal.
+type Deprecatable interface {
+	Deprecation() (message string, deprecated bool)
+}
+
+// Deprecated marks the object deprecated, with a message such as "use
+// Customer instead".
+func (o *ObjectSchema) Deprecated(message string) *ObjectSchema {
+	o.setDeprecated(message)
+	return o
+}
+
+// Deprecated marks the string deprecated, with a message such as "use
+// fullName".
+func (s *StringSchema) Deprecated(message string) *StringSchema {
+	s.setDeprecated(message)
+	return s
+}
+
+// Deprecated marks the number deprecated.
+func (n *NumberSchema) Deprecated(message string) *NumberSchema {
+	n.setDeprecated(message)
+	return n
+}
+
+// Deprecated marks the boolean deprecated.
+func (b *BooleanSchema) Deprecated(message string) *BooleanSchema {
+	b.setDeprecated(message)
+	return b
+}
+
+// Deprecated marks the binary data deprecated.
+func (b *BytesSchema) Deprecated(message string) *BytesSchema {
+	b.setDeprecated(message)
+	return b
+}
+
+// Deprecated marks the array deprecated.
+func (a *ArraySchema) Deprecated(message string) *ArraySchema {
+	a.setDeprecated(message)
+	return a
+}
+
+// Deprecated marks the value deprecated.
+func (n *NullableSchema) Deprecated(message string) *NullableSchema {
+	n.setDeprecated(message)
+	return n
+}
+
+// Deprecated marks the union deprecated.
+func (o *OneOfSchema) Deprecated(message string) *OneOfSchema {
+	o.setDeprecated(message)
+	return o
+}
+
+// Deprecated marks the intersection deprecated.
+func (a *AllOfSchema) Deprecated(message string) *AllOfSchema {
+	a.setDeprecated(message)
+	return a
+}
+
+// Deprecated marks the union deprecated.
+func (d *DiscriminatedUnionSchema) Deprecated(message string) *DiscriminatedUnionSchema {
+	d.setDeprecated(message)
+	return d
+}
+
+// Deprecated marks the value deprecated.
+func (a *AnySchema) Deprecated(message string) *AnySchema {
+	a.setDeprecated(message)
+	return a
+}
+
+// Deprecated marks the use of the named schema deprecated.
+func (r *RefSchema) Deprecated(message string) *RefSchema {
+	r.setDeprecated(message)
+	return r
+}
+
+// DeprecatedFields returns a warning for each deprecated field of schema
+// that value sets, such as an input sending a field marked with
+// Deprecated. Fields are named by their path, e.g. "'customer/fullName' is
+// deprecated: use name".
+func DeprecatedFields(schema Schema, value any) []string {
+	var warnings []string
+	collectDeprecated(schema, value, "", map[string]bool{}, &warnings)
+	return warnings
+}
+
+// collectDeprecated appends a warning for each deprecated field set in
+// value at path, once per path. Array items share the path "*".
+func collectDeprecated(schema Schema, value any, path string, seen map[string]bool, warnings *[]string) {
+	if schema == nil || value == nil {
+		return
+	}
+	if d, ok := schema.(Deprecatable); ok && path != "" && !seen[path] {
+		if message, deprecated := d.Deprecation(); deprecated {
+			seen[path] = true
+			*warnings = append(*warnings, DeprecationWarning(strings.TrimPrefix(path, "/"), message))
+		}
+	}
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		object, _ := value.(map[string]any)
+		for _, name := range sortedKeys(object) {
+			if prop, ok := s.properties[name]; ok {
+				collectDeprecated(prop, object[name], pointer(path, name), seen, warnings)
+			}
+		}
+	case *ArraySchema:
+		items, _ := value.([]any)
+		for _, item := range items {
+			collectDeprecated(s.items, item, path+"/*", seen, warnings)
+		}
+	case *NullableSchema:
+		collectDeprecated(s.inner, value, path, seen, warnings)
+	case *RefSchema:
+		collectDeprecated(s.target, value, path, seen, warnings)
+	case *OneOfSchema:
+		for _, alternative := range s.alternatives {
+			collectDeprecated(alternative, value, path, seen, warnings)
+		}
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			collectDeprecated(part, value, path, seen, warnings)
+		}
+	case *DiscriminatedUnionSchema:
+		for _, tag := range s.Tags() {
+			collectDeprecated(s.variants[tag], value, path, seen, warnings)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package ontology
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDeprecatedSchema(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"name":     String(),
+		"fullName": String().Deprecated("use name"),
+		"legacy":   Nullable(Integer()).Deprecated(""),
+	})
+
+	props := schema.JSONSchema()["properties"].(map[string]any)
+	full := props["fullName"].(map[string]any)
+	if full["deprecated"] != true || full["x-ont-deprecated"] != "use name" {
+		t.Errorf("fullName JSON Schema = %v", full)
+	}
+	legacy := props["legacy"].(map[string]any)
+	if _, ok := legacy["x-ont-deprecated"]; ok || legacy["deprecated"] != true {
+		t.Errorf("legacy JSON Schema = %v", legacy)
+	}
+	if _, ok := props["name"].(map[string]any)["deprecated"]; ok {
+		t.Error("name should not be deprecated")
+	}
+
+	// Deprecated fields are still valid
+	if err := schema.Validate(map[string]any{"name": "Ada", "fullName": "Ada L", "legacy": nil}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	data, _ := json.Marshal(schema.JSONSchema())
+	imported, err := ParseJSONSchema(data)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+	field := imported.(*ObjectSchema).Properties()["fullName"].(Deprecatable)
+	if message, ok := field.Deprecation(); !ok || message != "use name" {
+		t.Errorf("imported deprecation = %q, %v", message, ok)
+	}
+}
+
+func TestDeprecatedFunction(t *testing.T) {
+	config := queryConfig()
+	before := config.Hash()
+
+	fn := config.Functions["getCustomer"]
+	fn.Deprecated = "use findCustomer"
+	config.Functions["getCustomer"] = fn
+	if config.Hash() == before {
+		t.Error("hash should change when a function is deprecated")
+	}
+	if got := config.ExtractSnapshot().Functions["getCustomer"].Deprecated; got != "use findCustomer" {
+		t.Errorf("snapshot deprecated = %q", got)
+	}
+	if got := DeprecationWarning("getCustomer", fn.Deprecated); got != "'getCustomer' is deprecated: use findCustomer" {
+		t.Errorf("DeprecationWarning = %q", got)
+	}
+}
+
+func TestDeprecatedFields(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"name": String(),
+		"customer": Object(map[string]Schema{
+			"fullName": String().Deprecated("use name"),
+		}).Optional("fullName"),
+		"tags": Array(Object(map[string]Schema{
+			"legacy": Nullable(Integer()).Deprecated(""),
+		})),
+	}).Optional("customer", "tags")
+
+	got := DeprecatedFields(schema, map[string]any{
+		"name":     "Ada",
+		"customer": map[string]any{"fullName": "Ada L"},
+		"tags":     []any{map[string]any{"legacy": 1}, map[string]any{"legacy": 2}},
+	})
+	want := []string{"'customer/fullName' is deprecated: use name", "'tags/*/legacy' is deprecated"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DeprecatedFields() = %q, want %q", got, want)
+	}
+	if got := DeprecatedFields(schema, map[string]any{"name": "Ada", "customer": map[string]any{}}); got != nil {
+		t.Errorf("DeprecatedFields() = %q for unset fields", got)
+	}
+}
+
+func TestDiffRemovedDeprecatedField(t *testing.T) {
+	old := Object(map[string]Schema{"name": String(), "fullName": String().Deprecated("use name")})
+	diff := DiffInputSchemas(old, Object(map[string]Schema{"name": String()}))
+	if got, want := diff.String(), "/fullName: removed after deprecation: use name (breaking)"; got != want {
+		t.Errorf("diff = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package ontology
+
+import "fmt"
+
+// annotations holds documentation shared by every schema type. It is
+// emitted as JSON Schema "description" and "examples", so MCP clients and
+// generated SDKs see it too.
+type annotations struct {
+	description string
+	examples    []any
+	deprecated  bool
+	deprecation string
+	sensitive   bool
+}
+
+// Description returns the text set with Describe.
+func (a *annotations) Description() string {
+	return a.description
+}
+
+// Examples returns the values added with Example.
+func (a *annotations) Examples() []any {
+	return a.examples
+}
+
+// addExamples appends examples, for schemas built from other sources.
+func (a *annotations) addExamples(examples []any) {
+	a.examples = append(a.examples, examples...)
+}
+
+// annotate adds the description and examples to a JSON Schema.
+func (a *annotations) annotate(result map[string]any) map[string]any {
+	if a.description != "" {
+		result["description"] = a.description
+	}
+	if len(a.examples) > 0 && !a.sensitive {
+		result["examples"] = a.examples
+	}
+	if a.deprecated {
+		result["deprecated"] = true
+		if a.deprecation != "" {
+			result["x-ont-deprecated"] = a.deprecation
+		}
+	}
+	if a.sensitive {
+		result["x-ont-sensitive"] = true
+	}
+	return result
+}
+
+// Described is implemented by schemas that carry a description and examples.
+type Described interface {
+	Description() string
+	Examples() []any
+}
+
+// Describe documents the object for API consumers and AI clients.
+func (o *ObjectSchema) Describe(text string) *ObjectSchema {
+	o.description = text
+	return o
+}
+
+// Example adds an example value.
+func (o *ObjectSchema) Example(v any) *ObjectSchema {
+	o.examples = append(o.examples, v)
+	return o
+}
+
+// Describe documents the string for API consumers and AI clients.
+func (s *StringSchema) Describe(text string) *StringSchema {
+	s.description = text
+	return s
+}
+
+// Example adds an example value.
+func (s *StringSchema) Example(v any) *StringSchema {
+	s.examples = append(s.examples, v)
+	return s
+}
+
+// Describe documents the number for API consumers and AI clients.
+func (n *NumberSchema) Describe(text string) *NumberSchema {
+	n.description = text
+	return n
+}
+
+// Example adds an example value.
+func (n *NumberSchema) Example(v any) *NumberSchema {
+	n.examples = append(n.examples, v)
+	return n
+}
+
+// Describe documents the boolean for API consumers and AI clients.
+func (b *BooleanSchema) Describe(text string) *BooleanSchema {
+	b.description = text
+	return b
+}
+
+// Example adds an example value.
+func (b *BooleanSchema) Example(v any) *BooleanSchema {
+	b.examples = append(b.examples, v)
+	return b
+}
+
+// Describe documents the binary data for API consumers and AI clients.
+func (b *BytesSchema) Describe(text string) *BytesSchema {
+	b.description = text
+	return b
+}
+
+// Example adds an example value.
+func (b *BytesSchema) Example(v any) *BytesSchema {
+	b.examples = append(b.examples, v)
+	return b
+}
+
+// Describe documents the array for API consumers and AI clients.
+func (a *ArraySchema) Describe(text string) *ArraySchema {
+	a.description = text
+	return a
+}
+
+// Example adds an example value.
+func (a *ArraySchema) Example(v any) *ArraySchema {
+	a.examples = append(a.examples, v)
+	return a
+}
+
+// Describe documents the value for API consumers and AI clients.
+func (n *NullableSchema) Describe(text string) *NullableSchema {
+	n.description = text
+	return n
+}
+
+// Example adds an example value.
+func (n *NullableSchema) Example(v any) *NullableSchema {
+	n.examples = append(n.examples, v)
+	return n
+}
+
+// Describe documents the union for API consumers and AI clients.
+func (o *OneOfSchema) Describe(text string) *OneOfSchema {
+	o.description = text
+	return o
+}
+
+// Example adds an example value.
+func (o *OneOfSchema) Example(v any) *OneOfSchema {
+	o.examples = append(o.examples, v)
+	return o
+}
+
+// Describe documents the intersection for API consumers and AI clients.
+func (a *AllOfSchema) Describe(text string) *AllOfSchema {
+	a.description = text
+	return a
+}
+
+// Example adds an example value.
+func (a *AllOfSchema) Example(v any) *AllOfSchema {
+	a.examples = append(a.examples, v)
+	return a
+}
+
+// Describe documents the union for API consumers and AI clients.
+func (d *DiscriminatedUnionSchema) Describe(text string) *DiscriminatedUnionSchema {
+	d.description = text
+	return d
+}
+
+// Example adds an example value.
+func (d *DiscriminatedUnionSchema) Example(v any) *DiscriminatedUnionSchema {
+	d.examples = append(d.examples, v)
+	return d
+}
+
+// Describe documents the value for API consumers and AI clients.
+func (a *AnySchema) Describe(text string) *AnySchema {
+	a.description = text
+	return a
+}
+
+// Example adds an example value.
+func (a *AnySchema) Example(v any) *AnySchema {
+	a.examples = append(a.examples, v)
+	return a
+}
+
+// Describe documents this use of the named schema, e.g. a "billingAddress"
+// property that refers to "Address".
+func (r *RefSchema) Describe(text string) *RefSchema {
+	r.description = text
+	return r
+}
+
+// Example adds an example value.
+func (r *RefSchema) Example(v any) *RefSchema {
+	r.examples = append(r.examples, v)
+	return r
+}
+
+// checkExamples validates every example in schema against the schema that
+// declares it, so documentation can't drift from the contract.
+func checkExamples(schema Schema) error {
+	var err error
+	walkSchema(schema, func(s Schema) {
+		d, ok := s.(Described)
+		if !ok || err != nil {
+			return
+		}
+		for i, example := range d.Examples() {
+			if verr := s.Validate(example); verr != nil {
+				err = fmt.Errorf("example %d for %s is invalid: %w", i, s.TypeName(), verr)
+				return
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,76 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDescribeJSONSchema(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"email": String().Email().Describe("Customer email address").Example("ada@example.com"),
+		"limit": Integer().Describe("Maximum results").Example(10).Example(50),
+		"note":  Nullable(String()).Describe("Free-form note"),
+		"owner": Ref("User").Describe("Assigned owner"),
+	}).Describe("Search parameters")
+
+	js := schema.JSONSchema()
+	if js["description"] != "Search parameters" {
+		t.Errorf("object description = %v", js["description"])
+	}
+
+	props := js["properties"].(map[string]any)
+	email := props["email"].(map[string]any)
+	if email["description"] != "Customer email address" || email["format"] != "email" {
+		t.Errorf("email = %v", email)
+	}
+	if !reflect.DeepEqual(email["examples"], []any{"ada@example.com"}) {
+		t.Errorf("email examples = %v", email["examples"])
+	}
+	if !reflect.DeepEqual(props["limit"].(map[string]any)["examples"], []any{10, 50}) {
+		t.Errorf("limit examples = %v", props["limit"])
+	}
+	if props["note"].(map[string]any)["description"] != "Free-form note" {
+		t.Errorf("nullable description missing: %v", props["note"])
+	}
+	if owner := props["owner"].(map[string]any); owner["$ref"] != "#/$defs/User" || owner["description"] != "Assigned owner" {
+		t.Errorf("ref = %v", owner)
+	}
+
+	if _, ok := String().JSONSchema()["description"]; ok {
+		t.Error("undocumented schemas should not emit a description")
+	}
+}
+
+func TestInvalidExample(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]Entity{},
+		Functions: map[string]Function{
+			"search": {
+				Description: "Search",
+				Access:      []string{"public"},
+				Inputs: Object(map[string]Schema{
+					"limit": Integer().Max(100).Example(500),
+				}),
+				Outputs: Object(map[string]Schema{}),
+			},
+		},
+	}
+
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "function 'search' inputs: example 0 for integer is invalid") {
+		t.Errorf("Validate error = %v, want invalid example", err)
+	}
+
+	config.Functions["search"] = Function{
+		Description: "Search",
+		Access:      []string{"public"},
+		Inputs:      Object(map[string]Schema{"limit": Integer().Max(100).Example(50)}),
+		Outputs:     Object(map[string]Schema{}),
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("valid example rejected: %v", err)
+	}
+}
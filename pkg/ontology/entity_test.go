@@ -0,0 +1,106 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func entityConfig() *Config {
+	return &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities: map[string]Entity{
+			"User": {
+				Description: "A user",
+				Key:         "id",
+				Schema: Object(map[string]Schema{
+					"id":   String(),
+					"name": String(),
+				}),
+			},
+			"Team": {Description: "A team"},
+		},
+		Functions: map[string]Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Entities:    []string{"User"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Ref("User"),
+			},
+		},
+	}
+}
+
+func TestEntitySchema(t *testing.T) {
+	config := entityConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	output := config.Functions["getUser"].Outputs
+	if err := output.Validate(map[string]any{"id": "u1", "name": "Ada"}); err != nil {
+		t.Errorf("valid data rejected: %v", err)
+	}
+	if err := output.Validate(map[string]any{"id": 1, "name": "Ada"}); err == nil {
+		t.Error("entity schema should be validated")
+	}
+
+	defs, _ := config.JSONSchemaFor(output)["$defs"].(map[string]any)
+	if _, ok := defs["User"]; !ok {
+		t.Errorf("JSON Schema should define User, got %v", defs)
+	}
+}
+
+func TestEntitySchemaInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"key without schema", func(c *Config) {
+			c.Entities["Team"] = Entity{Description: "A team", Key: "id"}
+		}, "entity 'Team': key 'id' requires a schema"},
+		{"optional key", func(c *Config) {
+			c.Entities["User"] = Entity{Description: "A user", Key: "id", Schema: Object(map[string]Schema{"id": String()}).Optional("id")}
+		}, "entity 'User': key 'id' is not a required property"},
+		{"non-object key", func(c *Config) {
+			c.Entities["User"] = Entity{Description: "A user", Key: "id", Schema: String()}
+		}, "entity 'User': key 'id' requires an object schema"},
+		{"conflicting schema", func(c *Config) {
+			c.Schemas = map[string]Schema{"User": String()}
+		}, "entity 'User': schema conflicts with the named schema of the same name"},
+	}
+	for _, tt := range tests {
+		config := entityConfig()
+		tt.modify(config)
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate() = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestEntitySchemaLock(t *testing.T) {
+	config := entityConfig()
+	old := config.ExtractSnapshot()
+	if shape := old.EntityShapes["User"]; shape.Key != "id" || shape.Schema == nil {
+		t.Errorf("snapshot should record the User schema, got %+v", shape)
+	}
+	if _, ok := old.EntityShapes["Team"]; ok {
+		t.Error("entities without a schema should not be recorded")
+	}
+	oldHash := config.Hash()
+
+	config.Entities["User"] = Entity{
+		Description: "A user",
+		Key:         "id",
+		Schema:      Object(map[string]Schema{"id": String(), "email": String()}),
+	}
+	diff := DiffSnapshots(old, config.ExtractSnapshot())
+	if len(diff.ModifiedEntities) != 1 || diff.ModifiedEntities[0] != "User" {
+		t.Errorf("ModifiedEntities = %v, want [User]", diff.ModifiedEntities)
+	}
+	if config.Hash() == oldHash {
+		t.Error("changing an entity schema should change the hash")
+	}
+}
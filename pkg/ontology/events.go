@@ -0,0 +1,112 @@
+package ontology
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Event is a domain event, such as an order being created, that resolvers
+// publish with Context.Emit and callers subscribe to.
+type Event struct {
+	Description string `json:"description"`
+	// Payload is the schema of the data published with each event.
+	Payload Schema `json:"payload"`
+	// Access lists the access groups whose callers may subscribe.
+	Access []string `json:"access"`
+}
+
+// CheckAccess reports whether a caller with accessGroups may subscribe to
+// the event.
+func (e *Event) CheckAccess(accessGroups []string) bool {
+	for _, required := range e.Access {
+		if contains(accessGroups, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// EmitFunc publishes an event with its payload.
+type EmitFunc func(event string, payload any) error
+
+// ErrNoEmitter is returned by Context.Emit when events aren't published
+// from the context, e.g. one made with NewContext outside a server.
+var ErrNoEmitter = errors.New("events are not published from this context")
+
+// WithEmitter returns a context whose Emit publishes with emit. The server
+// gives resolvers such contexts; tests can use it to record events.
+func WithEmitter(ctx Context, emit EmitFunc) Context {
+	return &emitterContext{Context: ctx, emit: emit}
+}
+
+type emitterContext struct {
+	Context
+	emit EmitFunc
+}
+
+func (c *emitterContext) Emit(event string, payload any) error {
+	return c.emit(event, payload)
+}
+
+func (c *emitterContext) WithLogFields(keysAndValues ...any) Context {
+	return &emitterContext{Context: c.Context.WithLogFields(keysAndValues...), emit: c.emit}
+}
+
+// ValidateEvent checks that the named event exists and payload matches
+// its Payload schema.
+func (c *Config) ValidateEvent(name string, payload any) error {
+	event, ok := c.Events[name]
+	if !ok {
+		return fmt.Errorf("unknown event '%s'", name)
+	}
+	if err := ValidateAll(event.Payload, payload); err != nil {
+		return fmt.Errorf("event '%s': payload validation failed: %w", name, err)
+	}
+	return nil
+}
+
+// validateEvent checks an event's description, payload and access groups.
+func (c *Config) validateEvent(name string, event Event) error {
+	if event.Description == "" {
+		return fmt.Errorf("event '%s': description is required", name)
+	}
+	if event.Payload == nil {
+		return fmt.Errorf("event '%s' has nil payload schema", name)
+	}
+	if len(event.Access) == 0 {
+		return fmt.Errorf("event '%s': at least one access group is required", name)
+	}
+	for _, group := range event.Access {
+		if _, ok := c.AccessGroups[group]; !ok {
+			return fmt.Errorf("event '%s' references unknown access group '%s'", name, group)
+		}
+	}
+	if err := checkFormats(event.Payload); err != nil {
+		return fmt.Errorf("event '%s' payload: %w", name, err)
+	}
+	return nil
+}
+
+// EventShape represents a snapshot of an event for the lock file.
+type EventShape struct {
+	Description   string         `json:"description"`
+	Access        []string       `json:"access"`
+	PayloadSchema map[string]any `json:"payloadSchema"`
+}
+
+// eventShapes returns the lock file form of the config's events, or nil if
+// it has none.
+func (c *Config) eventShapes() map[string]EventShape {
+	if len(c.Events) == 0 {
+		return nil
+	}
+	shapes := make(map[string]EventShape, len(c.Events))
+	for name, event := range c.Events {
+		shapes[name] = EventShape{
+			Description:   event.Description,
+			Access:        sortedCopy(event.Access),
+			PayloadSchema: c.JSONSchemaFor(event.Payload),
+		}
+	}
+	return shapes
+}
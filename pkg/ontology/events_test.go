@@ -0,0 +1,126 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func eventConfig() *Config {
+	config := queryConfig()
+	config.Events = map[string]Event{
+		"customerDeleted": {
+			Description: "A customer was deleted",
+			Payload:     Object(map[string]Schema{"id": String()}),
+			Access:      []string{"admin"},
+		},
+	}
+	return config
+}
+
+func TestValidateEvents(t *testing.T) {
+	if err := eventConfig().Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(*Event)
+		wantErr string
+	}{
+		{"no description", func(e *Event) { e.Description = "" }, "event 'customerDeleted': description is required"},
+		{"no payload", func(e *Event) { e.Payload = nil }, "event 'customerDeleted' has nil payload schema"},
+		{"no access", func(e *Event) { e.Access = nil }, "at least one access group is required"},
+		{"unknown access group", func(e *Event) { e.Access = []string{"staff"} }, "references unknown access group 'staff'"},
+		{"unknown ref", func(e *Event) { e.Payload = Ref("Missing") }, "event 'customerDeleted'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := eventConfig()
+			event := config.Events["customerDeleted"]
+			tt.mutate(&event)
+			config.Events["customerDeleted"] = event
+			issues := config.ValidateAll()
+			err := issues.Err()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateEvent(t *testing.T) {
+	config := eventConfig()
+	if err := config.ValidateEvent("customerDeleted", map[string]any{"id": "c-1"}); err != nil {
+		t.Errorf("ValidateEvent failed: %v", err)
+	}
+	if err := config.ValidateEvent("customerDeleted", map[string]any{"id": 1}); err == nil || !strings.Contains(err.Error(), "payload validation failed") {
+		t.Errorf("expected payload error, got %v", err)
+	}
+	if err := config.ValidateEvent("orderCreated", map[string]any{}); err == nil || !strings.Contains(err.Error(), "unknown event 'orderCreated'") {
+		t.Errorf("expected unknown event error, got %v", err)
+	}
+}
+
+func TestEmit(t *testing.T) {
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+	if err := ctx.Emit("customerDeleted", nil); !errors.Is(err, ErrNoEmitter) {
+		t.Errorf("Emit() = %v, want ErrNoEmitter", err)
+	}
+
+	var emitted []string
+	ctx = WithEmitter(ctx, func(event string, payload any) error {
+		emitted = append(emitted, event)
+		return nil
+	})
+	ctx.WithLogFields("orderId", "o-1").Emit("customerDeleted", map[string]any{"id": "c-1"})
+	if !reflect.DeepEqual(emitted, []string{"customerDeleted"}) {
+		t.Errorf("emitted = %v", emitted)
+	}
+}
+
+func TestEventLock(t *testing.T) {
+	old := queryConfig()
+	config := eventConfig()
+	if config.Hash() == old.Hash() {
+		t.Error("hash should change when an event is added")
+	}
+
+	shape := config.ExtractSnapshot().Events["customerDeleted"]
+	if shape.Description != "A customer was deleted" || !reflect.DeepEqual(shape.Access, []string{"admin"}) || shape.PayloadSchema == nil {
+		t.Errorf("snapshot event = %+v", shape)
+	}
+
+	diff := DiffSnapshots(old.ExtractSnapshot(), config.ExtractSnapshot())
+	if !reflect.DeepEqual(diff.NewEvents, []string{"customerDeleted"}) || !diff.RequiresReview() {
+		t.Errorf("NewEvents = %v, RequiresReview = %v", diff.NewEvents, diff.RequiresReview())
+	}
+
+	changed := eventConfig()
+	event := changed.Events["customerDeleted"]
+	event.Access = []string{"admin", "support"}
+	changed.Events["customerDeleted"] = event
+	diff = DiffSnapshots(config.ExtractSnapshot(), changed.ExtractSnapshot())
+	if !reflect.DeepEqual(diff.ModifiedEvents, []string{"customerDeleted"}) || !strings.Contains(diff.String(), "Modified events: [customerDeleted]") {
+		t.Errorf("diff = %s", diff)
+	}
+
+	diff = DiffSnapshots(config.ExtractSnapshot(), old.ExtractSnapshot())
+	if !reflect.DeepEqual(diff.DeletedEvents, []string{"customerDeleted"}) {
+		t.Errorf("DeletedEvents = %v", diff.DeletedEvents)
+	}
+}
+
+func TestEventAccessGroupsAreUsed(t *testing.T) {
+	config := eventConfig()
+	config.AccessGroups["auditor"] = AccessGroup{Description: "Auditors"}
+	event := config.Events["customerDeleted"]
+	event.Access = append(event.Access, "auditor")
+	config.Events["customerDeleted"] = event
+	for _, issue := range config.ValidateAll().Warnings() {
+		if issue.Name == "auditor" {
+			t.Errorf("unexpected warning: %s", issue.Message)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package ontology
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Example is a documented call of a function: an input and the output it
+// returns, in their JSON form. Config.Validate checks examples against the
+// function's schemas, MCP tool descriptions and generated SDKs show them,
+// and RunExamples replays them as contract tests.
+type Example struct {
+	Description string `json:"description,omitempty"`
+	Input       any    `json:"input"`
+	// Output is optional. RunExamples checks that the actual output
+	// contains it: objects may have more properties than the example.
+	Output any `json:"output,omitempty"`
+}
+
+// Transcript returns the example as a transcript with sensitive values
+// redacted, for tool descriptions and the examples resource.
+func (e Example) Transcript(fn Function) Transcript {
+	return fn.RecordTranscript(e.Input, e.Output)
+}
+
+// Format renders the example as a line for a tool description, like
+// Transcript.Format with the description in parentheses:
+//
+//	Example (archived customer): getCustomer({"id":"7"}) returns null
+func (e Example) Format(fn Function, name string) string {
+	t := e.Transcript(fn)
+	if e.Description == "" {
+		return t.Format(name)
+	}
+	return fmt.Sprintf("Example (%s): %s(%s) returns %s", e.Description, name, transcriptJSON(t.Input), transcriptJSON(t.Output))
+}
+
+// validateExamples checks a function's examples against its schemas.
+func validateExamples(fn Function) error {
+	for i, example := range fn.Examples {
+		if err := fn.ValidateInput(toJSONValue(example.Input)); err != nil {
+			return fmt.Errorf("example %d: input: %w", i+1, err)
+		}
+		if example.Output != nil && fn.Outputs != nil {
+			if err := fn.Outputs.Validate(toJSONValue(example.Output)); err != nil {
+				return fmt.Errorf("example %d: output: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RunExamples calls each function with the inputs of its examples, as Call
+// does on behalf of the caller in ctx, and checks that the outputs contain
+// the examples' outputs. It returns an error per failed example, so the
+// examples double as contract tests:
+//
+//	func TestExamples(t *testing.T) {
+//		ctx := ont.NewContext(nil, ont.DefaultLogger(), []string{"admin"}, nil)
+//		if err := config.RunExamples(ctx); err != nil {
+//			t.Error(err)
+//		}
+//	}
+func (c *Config) RunExamples(ctx Context) error {
+	var failures []error
+	for _, name := range sortedKeys(c.Functions) {
+		for i, example := range c.Functions[name].Examples {
+			output, err := c.Call(ctx, name, toJSONValue(example.Input))
+			if err != nil {
+				failures = append(failures, fmt.Errorf("%s example %d: %w", name, i+1, err))
+				continue
+			}
+			if example.Output != nil && !containsJSON(toJSONValue(output), toJSONValue(example.Output)) {
+				failures = append(failures, fmt.Errorf("%s example %d: output %s doesn't match %s", name, i+1, transcriptJSON(output), transcriptJSON(example.Output)))
+			}
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// containsJSON reports whether actual matches expected, allowing objects in
+// actual to have properties expected doesn't mention.
+func containsJSON(actual, expected any) bool {
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			return false
+		}
+		for key, value := range e {
+			if _, ok := a[key]; !ok || !containsJSON(a[key], value) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		a, ok := actual.([]any)
+		if !ok || len(a) != len(e) {
+			return false
+		}
+		for i := range e {
+			if !containsJSON(a[i], e[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(actual, expected)
+}
@@ -0,0 +1,68 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateExamples(t *testing.T) {
+	fn := pipelineConfig(Function{}).Functions["getCustomer"]
+
+	tests := []struct {
+		name    string
+		example Example
+		wantErr string
+	}{
+		{"valid", Example{Input: map[string]any{"email": "ada@example.com"}, Output: map[string]any{"id": "c-1", "ownerId": nil, "visits": 3}}, ""},
+		{"input only", Example{Input: map[string]any{"email": "ada@example.com"}}, ""},
+		{"bad input", Example{Input: map[string]any{"email": "ada"}}, "example 1: input"},
+		{"bad output", Example{Input: map[string]any{"email": "ada@example.com"}, Output: map[string]any{"id": 1}}, "example 1: output"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn.Examples = []Example{tt.example}
+			err := validateExamples(fn)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateExamples() = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateExamples() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunExamples(t *testing.T) {
+	config := pipelineConfig(Function{})
+	fn := config.Functions["getCustomer"]
+	fn.Examples = []Example{
+		{Input: map[string]any{"email": "ada@example.com"}, Output: map[string]any{"id": "c-1"}},
+		{Description: "stale", Input: map[string]any{"email": "ada@example.com"}, Output: map[string]any{"id": "c-2"}},
+	}
+	config.Functions["getCustomer"] = fn
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	err := config.RunExamples(ctx)
+	if err == nil {
+		t.Fatal("RunExamples() should fail the stale example")
+	}
+	if msg := err.Error(); strings.Contains(msg, "example 1") || !strings.Contains(msg, "getCustomer example 2") {
+		t.Errorf("RunExamples() = %v, want only example 2 to fail", err)
+	}
+
+	fn.Examples = fn.Examples[:1]
+	config.Functions["getCustomer"] = fn
+	if err := config.RunExamples(ctx); err != nil {
+		t.Errorf("RunExamples() = %v", err)
+	}
+}
+
+func TestExampleFormat(t *testing.T) {
+	fn := Function{Inputs: Object(map[string]Schema{"email": String()})}
+	example := Example{Description: "unknown customer", Input: map[string]any{"email": "ada@example.com"}}
+	want := `Example (unknown customer): getCustomer({"email":"ada@example.com"}) returns null`
+	if got := example.Format(fn, "getCustomer"); got != want {
+		t.Errorf("Format() = %s, want %s", got, want)
+	}
+}
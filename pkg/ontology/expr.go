@@ -0,0 +1,705 @@
+package ontology
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr is a compiled expression over JSON values, such as a pipeline step
+// condition. Expressions are sandboxed: they can read the values they are
+// given and nothing else.
+//
+// The language has number, string ('...' or "..."), true, false, null and
+// list ([1, 2]) literals; dotted paths such as input.amount or
+// lookup.customer.tier, which evaluate to null when absent; the operators
+// ! && || == != < <= > >= in + - * / % and cond ? a : b; parentheses; and the
+// functions listed in exprFuncs, e.g. len(input.items) or
+// coalesce(input.limit, 20). && and || short-circuit and require booleans.
+type Expr struct {
+	source string
+	root   exprNode
+}
+
+// ParseExpr compiles an expression.
+func ParseExpr(source string) (*Expr, error) {
+	tokens, err := lexExpr(source)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseTernary()
+	if err == nil && p.peek().kind != tokEOF {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", source, err)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// String returns the expression's source.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against env, whose keys are the roots of
+// paths. Values should be in their JSON form (see Config.Call).
+func (e *Expr) Eval(env map[string]any) (any, error) {
+	v, err := e.root.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("expression %q: %w", e.source, err)
+	}
+	return v, nil
+}
+
+// EvalBool evaluates the expression and requires a boolean result.
+func (e *Expr) EvalBool(env map[string]any) (bool, error) {
+	v, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q: expected boolean, got %s", e.source, exprTypeName(v))
+	}
+	return b, nil
+}
+
+// Paths returns the dotted paths the expression reads, in order of appearance.
+func (e *Expr) Paths() []string {
+	var paths []string
+	e.root.walk(func(n exprNode) {
+		if p, ok := n.(*pathNode); ok {
+			paths = append(paths, strings.Join(p.parts, "."))
+		}
+	})
+	return paths
+}
+
+// IsPath reports whether the expression is a single dotted path.
+func (e *Expr) IsPath() bool {
+	_, ok := e.root.(*pathNode)
+	return ok
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type exprToken struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+func (t exprToken) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("'%s'", t.text)
+}
+
+// exprOperators lists operators longest first so "<=" wins over "<".
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", "[", "]", ".", ",", "?", ":"}
+
+func lexExpr(src string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' || src[i] == 'e' || src[i] == 'E') {
+				i++
+			}
+			num, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s'", src[start:i])
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: src[start:i], num: num})
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(src) {
+				if src[i] == '\\' && i+1 < len(src) {
+					b.WriteByte(src[i+1])
+					i += 2
+					continue
+				}
+				if rune(src[i]) == c {
+					closed = true
+					i++
+					break
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: b.String()})
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: src[start:i]})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(src[i:], op) {
+					tokens = append(tokens, exprToken{kind: tokOp, text: op})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character '%c'", c)
+			}
+		}
+	}
+	return append(tokens, exprToken{kind: tokEOF}), nil
+}
+
+// Parser
+
+type exprParser struct {
+	tokens []exprToken
+	pos    int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is one of the given operators.
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseTernary() (exprNode, error) {
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept(":"); !ok {
+		return nil, fmt.Errorf("expected ':', got %s", p.peek())
+	}
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+	return &ternaryNode{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">")
+	if !ok && p.peek().kind == tokIdent && p.peek().text == "in" {
+		p.next()
+		op, ok = "in", true
+	}
+	if ok {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	return p.parseBinary(p.parseUnary, "*", "/", "%")
+}
+
+// parseBinary parses a left-associative chain of the given operators.
+func (p *exprParser) parseBinary(operand func() (exprNode, error), ops ...string) (exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &literalNode{value: t.num}, nil
+	case tokString:
+		return &literalNode{value: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return &literalNode{value: true}, nil
+		case "false":
+			return &literalNode{value: false}, nil
+		case "null":
+			return &literalNode{value: nil}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t.text)
+		}
+		parts := []string{t.text}
+		for {
+			if _, ok := p.accept("."); !ok {
+				break
+			}
+			part := p.next()
+			if part.kind != tokIdent {
+				return nil, fmt.Errorf("expected field name after '.', got %s", part)
+			}
+			parts = append(parts, part.text)
+		}
+		return &pathNode{parts: parts}, nil
+	case tokOp:
+		if t.text == "[" {
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return &listNode{items: items}, nil
+		}
+		if t.text == "(" {
+			inner, err := p.parseTernary()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, fmt.Errorf("expected ')', got %s", p.peek())
+			}
+			return inner, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+// parseCall parses the arguments of a call to a built-in function.
+func (p *exprParser) parseCall(name string) (exprNode, error) {
+	fn, ok := exprFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", name)
+	}
+	args, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("wrong number of arguments to %s", name)
+	}
+	return &callNode{name: name, fn: fn.call, args: args}, nil
+}
+
+// parseList parses comma-separated expressions up to the closing token.
+func (p *exprParser) parseList(closing string) ([]exprNode, error) {
+	var items []exprNode
+	if _, ok := p.accept(closing); ok {
+		return items, nil
+	}
+	for {
+		item, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if _, ok := p.accept(closing); ok {
+			return items, nil
+		}
+		if _, ok := p.accept(","); !ok {
+			return nil, fmt.Errorf("expected ',' or '%s', got %s", closing, p.peek())
+		}
+	}
+}
+
+// Evaluation
+
+type exprNode interface {
+	eval(env map[string]any) (any, error)
+	walk(visit func(exprNode))
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n *literalNode) eval(env map[string]any) (any, error) {
+	return n.value, nil
+}
+
+func (n *literalNode) walk(visit func(exprNode)) {
+	visit(n)
+}
+
+type pathNode struct {
+	parts []string
+}
+
+func (n *pathNode) eval(env map[string]any) (any, error) {
+	var current any = env
+	for _, part := range n.parts {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		current = m[part]
+	}
+	return current, nil
+}
+
+func (n *pathNode) walk(visit func(exprNode)) {
+	visit(n)
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(env map[string]any) (any, error) {
+	v, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'!' expects a boolean, got %s", exprTypeName(v))
+		}
+		return !b, nil
+	default:
+		num, ok := exprNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("'-' expects a number, got %s", exprTypeName(v))
+		}
+		return -num, nil
+	}
+}
+
+func (n *unaryNode) walk(visit func(exprNode)) {
+	visit(n)
+	n.operand.walk(visit)
+}
+
+type binaryNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *binaryNode) eval(env map[string]any) (any, error) {
+	left, err := n.left.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	// Logical operators short-circuit
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'%s' expects booleans, got %s", n.op, exprTypeName(left))
+		}
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return l, nil
+		}
+		right, err := n.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("'%s' expects booleans, got %s", n.op, exprTypeName(right))
+		}
+		return r, nil
+	}
+
+	right, err := n.right.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "in":
+		return exprContains(right, left)
+	case "==":
+		return exprEqual(left, right), nil
+	case "!=":
+		return !exprEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		cmp, err := exprCompare(left, right)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' %w", n.op, err)
+		}
+		switch n.op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}
+
+	// Arithmetic; + also concatenates strings
+	if n.op == "+" {
+		if ls, ok := left.(string); ok {
+			if rs, ok := right.(string); ok {
+				return ls + rs, nil
+			}
+		}
+	}
+	l, lok := exprNumber(left)
+	r, rok := exprNumber(right)
+	if !lok || !rok {
+		return nil, fmt.Errorf("'%s' expects numbers, got %s and %s", n.op, exprTypeName(left), exprTypeName(right))
+	}
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	default:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(l, r), nil
+	}
+}
+
+func (n *binaryNode) walk(visit func(exprNode)) {
+	visit(n)
+	n.left.walk(visit)
+	n.right.walk(visit)
+}
+
+type ternaryNode struct {
+	cond, then, otherwise exprNode
+}
+
+func (n *ternaryNode) eval(env map[string]any) (any, error) {
+	v, err := n.cond.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	cond, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("'?' expects a boolean condition, got %s", exprTypeName(v))
+	}
+	if cond {
+		return n.then.eval(env)
+	}
+	return n.otherwise.eval(env)
+}
+
+func (n *ternaryNode) walk(visit func(exprNode)) {
+	visit(n)
+	n.cond.walk(visit)
+	n.then.walk(visit)
+	n.otherwise.walk(visit)
+}
+
+type listNode struct {
+	items []exprNode
+}
+
+func (n *listNode) eval(env map[string]any) (any, error) {
+	result := make([]any, len(n.items))
+	for i, item := range n.items {
+		v, err := item.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+func (n *listNode) walk(visit func(exprNode)) {
+	visit(n)
+	for _, item := range n.items {
+		item.walk(visit)
+	}
+}
+
+type callNode struct {
+	name string
+	fn   func(args []any) (any, error)
+	args []exprNode
+}
+
+func (n *callNode) eval(env map[string]any) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+	result, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return result, nil
+}
+
+func (n *callNode) walk(visit func(exprNode)) {
+	visit(n)
+	for _, arg := range n.args {
+		arg.walk(visit)
+	}
+}
+
+// exprNumber converts Go and JSON numbers to float64.
+func exprNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// exprEqual compares values, treating all numeric types alike.
+func exprEqual(a, b any) bool {
+	if an, ok := exprNumber(a); ok {
+		bn, ok := exprNumber(b)
+		return ok && an == bn
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// exprCompare orders two numbers or two strings.
+func exprCompare(a, b any) (int, error) {
+	if an, ok := exprNumber(a); ok {
+		if bn, ok := exprNumber(b); ok {
+			switch {
+			case an < bn:
+				return -1, nil
+			case an > bn:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs), nil
+		}
+	}
+	return 0, fmt.Errorf("expects two numbers or two strings, got %s and %s", exprTypeName(a), exprTypeName(b))
+}
+
+// exprTypeName names the JSON type of v for error messages.
+func exprTypeName(v any) string {
+	if v == nil {
+		return "null"
+	}
+	if _, ok := exprNumber(v); ok {
+		return "number"
+	}
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
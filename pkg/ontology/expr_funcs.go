@@ -0,0 +1,167 @@
+package ontology
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// exprFunc is a built-in expression function. maxArgs is -1 for variadic
+// functions.
+type exprFunc struct {
+	minArgs, maxArgs int
+	call             func(args []any) (any, error)
+}
+
+// exprFuncs are the functions expressions can call. They are pure, so
+// expressions stay sandboxed and deterministic.
+var exprFuncs = map[string]exprFunc{
+	// len returns the length of a string, array or object.
+	"len": {1, 1, func(args []any) (any, error) {
+		switch v := args[0].(type) {
+		case string:
+			return float64(len([]rune(v))), nil
+		case []any:
+			return float64(len(v)), nil
+		case map[string]any:
+			return float64(len(v)), nil
+		}
+		return nil, fmt.Errorf("expects a string, array or object, got %s", exprTypeName(args[0]))
+	}},
+	"lower": stringFunc(strings.ToLower),
+	"upper": stringFunc(strings.ToUpper),
+	"trim":  stringFunc(strings.TrimSpace),
+	// contains reports whether a string contains a substring or an array an element.
+	"contains": {2, 2, func(args []any) (any, error) {
+		return exprContains(args[0], args[1])
+	}},
+	"startsWith": stringPredicate(strings.HasPrefix),
+	"endsWith":   stringPredicate(strings.HasSuffix),
+	// matches reports whether a string matches a regular expression.
+	"matches": {2, 2, func(args []any) (any, error) {
+		s, sok := args[0].(string)
+		pattern, pok := args[1].(string)
+		if !sok || !pok {
+			return nil, fmt.Errorf("expects two strings, got %s and %s", exprTypeName(args[0]), exprTypeName(args[1]))
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString(s), nil
+	}},
+	"abs":   numberFunc(math.Abs),
+	"floor": numberFunc(math.Floor),
+	"ceil":  numberFunc(math.Ceil),
+	"round": numberFunc(math.Round),
+	"min":   {1, -1, numberFold(math.Min)},
+	"max":   {1, -1, numberFold(math.Max)},
+	// string formats a value as a string.
+	"string": {1, 1, func(args []any) (any, error) {
+		switch v := args[0].(type) {
+		case string:
+			return v, nil
+		case nil:
+			return "null", nil
+		}
+		if n, ok := exprNumber(args[0]); ok {
+			return strconv.FormatFloat(n, 'f', -1, 64), nil
+		}
+		return fmt.Sprint(args[0]), nil
+	}},
+	// number parses a string as a number.
+	"number": {1, 1, func(args []any) (any, error) {
+		if n, ok := exprNumber(args[0]); ok {
+			return n, nil
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expects a number or string, got %s", exprTypeName(args[0]))
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", s)
+		}
+		return n, nil
+	}},
+	// coalesce returns its first non-null argument, e.g. coalesce(input.limit, 20).
+	"coalesce": {1, -1, func(args []any) (any, error) {
+		for _, arg := range args {
+			if arg != nil {
+				return arg, nil
+			}
+		}
+		return nil, nil
+	}},
+}
+
+func stringFunc(f func(string) string) exprFunc {
+	return exprFunc{1, 1, func(args []any) (any, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expects a string, got %s", exprTypeName(args[0]))
+		}
+		return f(s), nil
+	}}
+}
+
+func stringPredicate(f func(s, affix string) bool) exprFunc {
+	return exprFunc{2, 2, func(args []any) (any, error) {
+		s, sok := args[0].(string)
+		affix, aok := args[1].(string)
+		if !sok || !aok {
+			return nil, fmt.Errorf("expects two strings, got %s and %s", exprTypeName(args[0]), exprTypeName(args[1]))
+		}
+		return f(s, affix), nil
+	}}
+}
+
+func numberFunc(f func(float64) float64) exprFunc {
+	return exprFunc{1, 1, func(args []any) (any, error) {
+		n, ok := exprNumber(args[0])
+		if !ok {
+			return nil, fmt.Errorf("expects a number, got %s", exprTypeName(args[0]))
+		}
+		return f(n), nil
+	}}
+}
+
+func numberFold(f func(a, b float64) float64) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		var result float64
+		for i, arg := range args {
+			n, ok := exprNumber(arg)
+			if !ok {
+				return nil, fmt.Errorf("expects numbers, got %s", exprTypeName(arg))
+			}
+			if i == 0 {
+				result = n
+			} else {
+				result = f(result, n)
+			}
+		}
+		return result, nil
+	}
+}
+
+// exprContains reports whether haystack, a string or array, contains needle.
+func exprContains(haystack, needle any) (bool, error) {
+	switch h := haystack.(type) {
+	case string:
+		s, ok := needle.(string)
+		if !ok {
+			return false, fmt.Errorf("can't look for %s in a string", exprTypeName(needle))
+		}
+		return strings.Contains(h, s), nil
+	case []any:
+		for _, item := range h {
+			if exprEqual(item, needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("expects a string or array, got %s", exprTypeName(haystack))
+}
@@ -0,0 +1,128 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExprEval(t *testing.T) {
+	env := map[string]any{
+		"input": map[string]any{
+			"amount": 1500.0,
+			"tier":   "gold",
+			"tags":   []any{"vip"},
+			"rush":   true,
+		},
+		"count": 3,
+	}
+
+	tests := []struct {
+		expr string
+		want any
+	}{
+		{"input.amount > 1000", true},
+		{"input.amount >= 1500 && input.tier == 'gold'", true},
+		{"input.tier != \"gold\" || !input.rush", false},
+		{"input.missing == null", true},
+		{"input.missing.deeper == null", true},
+		{"count == 3", true},
+		{"(count + 1) * 2", 8.0},
+		{"10 - 2 - 3", 5.0},
+		{"7 % 4", 3.0},
+		{"-count", -3.0},
+		{"'a' + 'b'", "ab"},
+		{"'apple' < 'banana'", true},
+		{"false && input.missing > 1", false},
+		{"input.tags == input.tags", true},
+		{"'vip' in input.tags", true},
+		{"input.tier in ['gold', 'platinum']", true},
+		{"[1, count]", []any{1.0, 3}},
+		{"input.rush ? 'express' : 'standard'", "express"},
+		{"input.amount > 2000 ? 'high' : input.amount > 1000 ? 'mid' : 'low'", "mid"},
+		{"len(input.tags) + len('abc')", 4.0},
+		{"upper(input.tier) + lower('X') + trim('  y ')", "GOLDxy"},
+		{"contains(input.tier, 'ol') && startsWith(input.tier, 'g') && endsWith(input.tier, 'd')", true},
+		{"matches(input.tier, '^g[a-z]+$')", true},
+		{"max(1, count, 2) - min(4, 5) + abs(-1) + round(1.6)", 2.0},
+		{"floor(1.5) + ceil(1.5)", 3.0},
+		{"coalesce(input.limit, 20)", 20.0},
+		{"string(count) + string(1.5)", "31.5"},
+		{"number('42') + 1", 43.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := ParseExpr(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseExpr failed: %v", err)
+			}
+			got, err := expr.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Eval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	parseErrors := []string{"", "a ==", "(a", "a b", "'open", "a.", "a # b", "nope(1)", "len()", "len(1, 2)", "[1, 2", "a ? b", "f(1"}
+	for _, src := range parseErrors {
+		if _, err := ParseExpr(src); err == nil {
+			t.Errorf("ParseExpr(%q) should fail", src)
+		}
+	}
+
+	env := map[string]any{"n": 1.0, "s": "x"}
+	evalErrors := map[string]string{
+		"n && true": "'&&' expects booleans, got number",
+		"s > 1":     "expects two numbers or two strings",
+		"n / 0":     "division by zero",
+		"!s":        "'!' expects a boolean",
+		"s * 2":     "'*' expects numbers, got string and number",
+		"len(n)":    "len: expects a string, array or object, got number",
+		"n in 1":    "expects a string or array",
+		"n ? 1 : 2": "'?' expects a boolean condition",
+		"number(s)": "number: 'x' is not a number",
+	}
+	for src, want := range evalErrors {
+		expr, err := ParseExpr(src)
+		if err != nil {
+			t.Fatalf("ParseExpr(%q) failed: %v", src, err)
+		}
+		if _, err := expr.Eval(env); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Eval(%q) error = %v, want %q", src, err, want)
+		}
+	}
+
+	expr, _ := ParseExpr("n + 1")
+	if _, err := expr.EvalBool(env); err == nil {
+		t.Error("EvalBool should reject non-boolean results")
+	}
+}
+
+func TestExprPaths(t *testing.T) {
+	expr, err := ParseExpr("input.amount > limit.max && !input.flags.rush && len(input.tags) > 0 ? a : [b]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"input.amount", "limit.max", "input.flags.rush", "input.tags", "a", "b"}
+	if got := expr.Paths(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths = %v, want %v", got, want)
+	}
+}
+
+func TestExprIsPath(t *testing.T) {
+	for src, want := range map[string]bool{"input.email": true, "customer": true, "len(input.tags)": false, "'x'": false} {
+		expr, err := ParseExpr(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expr.IsPath() != want {
+			t.Errorf("IsPath(%q) = %v, want %v", src, !want, want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ontology
+
+import (
+	"math"
+	"strings"
+)
+
+// Fake returns a placeholder value for schema, for examples and tests. It
+// uses the first example of a schema when there is one, and otherwise the
+// first enum value, a value of the string format or the minimum of a
+// number. Objects get every property, arrays their minimum number of items
+// (at least one) and unions their first alternative. The value satisfies
+// the schema except for patterns and refinements, which Fake can't invert;
+// validate it when that matters.
+func Fake(schema Schema) any {
+	return (&faker{refs: make(map[string]bool)}).fake(schema)
+}
+
+// faker tracks the named schemas being generated, so recursive schemas end
+// with null or an empty array.
+type faker struct {
+	refs map[string]bool
+}
+
+func (f *faker) fake(schema Schema) any {
+	if d, ok := schema.(Described); ok && len(d.Examples()) > 0 {
+		return d.Examples()[0]
+	}
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		result := make(map[string]any, len(s.properties))
+		for name, prop := range s.properties {
+			result[name] = f.fake(prop)
+		}
+		return result
+	case *StringSchema:
+		return fakeString(s)
+	case *NumberSchema:
+		return fakeNumber(s)
+	case *BooleanSchema:
+		return true
+	case *BytesSchema:
+		return "ZXhhbXBsZQ==" // "example"
+	case *ArraySchema:
+		count := 1
+		if s.minItems != nil {
+			count = *s.minItems
+		} else if ref, ok := s.items.(*RefSchema); ok && f.refs[ref.name] {
+			count = 0
+		}
+		if s.maxItems != nil && count > *s.maxItems {
+			count = *s.maxItems
+		}
+		items := make([]any, count)
+		for i := range items {
+			items[i] = f.fake(s.items)
+		}
+		return items
+	case *NullableSchema:
+		if ref, ok := s.inner.(*RefSchema); ok && f.refs[ref.name] {
+			return nil
+		}
+		return f.fake(s.inner)
+	case *RefSchema:
+		if f.refs[s.name] || s.target == nil {
+			return nil
+		}
+		f.refs[s.name] = true
+		defer delete(f.refs, s.name)
+		return f.fake(s.target)
+	case *OneOfSchema:
+		if len(s.alternatives) > 0 {
+			return f.fake(s.alternatives[0])
+		}
+	case *AllOfSchema:
+		result := map[string]any{}
+		for _, part := range s.schemas {
+			if m, ok := f.fake(part).(map[string]any); ok {
+				for key, val := range m {
+					result[key] = val
+				}
+			}
+		}
+		return result
+	case *DiscriminatedUnionSchema:
+		tags := s.Tags()
+		if len(tags) == 0 {
+			return nil
+		}
+		result, _ := f.fake(s.variants[tags[0]]).(map[string]any)
+		if result == nil {
+			result = map[string]any{}
+		}
+		result[s.discriminator] = tags[0]
+		return result
+	}
+	return nil
+}
+
+// fakeString returns a value of the string's format or enum, fitted to its
+// length bounds.
+func fakeString(s *StringSchema) string {
+	if len(s.enum) > 0 {
+		return s.enum[0]
+	}
+	switch s.format {
+	case "uuid":
+		return "3f2c8a4e-1b7d-4c9a-9e5f-0a6b2d8c4e71"
+	case "email":
+		return "ada@example.com"
+	case "date-time":
+		return "2024-05-01T10:30:00Z"
+	case "date":
+		return "2024-05-01"
+	case "uri":
+		return "https://example.com"
+	}
+	str := "example"
+	if s.minLength != nil && len(str) < *s.minLength {
+		str += strings.Repeat("x", *s.minLength-len(str))
+	}
+	if s.maxLength != nil && len(str) > *s.maxLength {
+		str = str[:*s.maxLength]
+	}
+	return str
+}
+
+// fakeNumber returns the number's first enum value, or its minimum (1 if it
+// has none) fitted to its other bounds and multipleOf.
+func fakeNumber(n *NumberSchema) any {
+	if len(n.enum) > 0 {
+		return n.enum[0]
+	}
+	step, num := 1.0, 1.0
+	if !n.isInteger && !n.isInt64 {
+		step = 0.5
+	}
+	if n.multipleOf != nil && *n.multipleOf > 0 {
+		step, num = *n.multipleOf, *n.multipleOf
+	}
+	switch {
+	case n.minimum != nil:
+		num = math.Ceil(*n.minimum/step) * step
+	case n.exclusiveMinimum != nil:
+		num = (math.Floor(*n.exclusiveMinimum/step) + 1) * step
+	}
+	if n.maximum != nil && num > *n.maximum {
+		num = math.Floor(*n.maximum/step) * step
+	}
+	if n.exclusiveMaximum != nil && num >= *n.exclusiveMaximum {
+		num = (math.Ceil(*n.exclusiveMaximum/step) - 1) * step
+	}
+	if n.isInteger || n.isInt64 {
+		return int64(num)
+	}
+	return num
+}
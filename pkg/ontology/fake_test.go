@@ -0,0 +1,102 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFake(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema Schema
+		want   any
+	}{
+		{"example", String().Example("Ada"), "Ada"},
+		{"enum", String().Enum("open", "closed"), "open"},
+		{"email", String().Email(), "ada@example.com"},
+		{"min length", String().Min(10), "examplexxx"},
+		{"max length", String().Max(3), "exa"},
+		{"number", Number(), 1.0},
+		{"number minimum", Number().Min(2.2), 2.5},
+		{"integer bounds", Integer().Min(5).Max(10), int64(5)},
+		{"exclusive maximum", Integer().ExclusiveMax(1), int64(0)},
+		{"multiple of", Integer().Min(7).MultipleOf(5), int64(10)},
+		{"boolean", Boolean(), true},
+		{"array", Array(Integer()), []any{int64(1)}},
+		{"array min items", Array(Boolean()).MinItems(2), []any{true, true}},
+		{"nullable", Nullable(String().Enum("a")), "a"},
+		{"union", OneOf(Boolean(), String()), true},
+		{"discriminated union", DiscriminatedUnion("kind", map[string]*ObjectSchema{
+			"circle": Object(map[string]Schema{"radius": Number().Min(3)}),
+		}), map[string]any{"kind": "circle", "radius": 3.0}},
+		{"all of", AllOf(
+			Object(map[string]Schema{"id": Integer()}),
+			Object(map[string]Schema{"name": String()}),
+		), map[string]any{"id": int64(1), "name": "example"}},
+	}
+	for _, tt := range tests {
+		got := Fake(tt.schema)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Fake() = %#v, want %#v", tt.name, got, tt.want)
+		}
+		if err := tt.schema.Validate(got); err != nil {
+			t.Errorf("%s: Fake() is invalid: %v", tt.name, err)
+		}
+	}
+}
+
+func TestFakeRecursive(t *testing.T) {
+	config := &Config{
+		Schemas: map[string]Schema{
+			"Comment": Object(map[string]Schema{
+				"text":    String(),
+				"parent":  Nullable(Ref("Comment")),
+				"replies": Array(Ref("Comment")),
+			}),
+		},
+	}
+	if err := config.resolveRefs(); err != nil {
+		t.Fatalf("resolveRefs() error = %v", err)
+	}
+	ref := Ref("Comment")
+	ref.target = config.Schemas["Comment"]
+	want := map[string]any{"text": "example", "parent": nil, "replies": []any{}}
+	if got := Fake(ref); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fake() = %#v, want %#v", got, want)
+	}
+}
+
+func TestFakeTranscript(t *testing.T) {
+	fn := Function{
+		Inputs: Object(map[string]Schema{"id": String().UUID()}),
+		Outputs: Object(map[string]Schema{
+			"name":  String().Example("Ada"),
+			"token": String().Sensitive(),
+		}),
+	}
+	transcript, err := fn.FakeTranscript()
+	if err != nil {
+		t.Fatalf("FakeTranscript() error = %v", err)
+	}
+	want := `Example: getUser({"id":"3f2c8a4e-1b7d-4c9a-9e5f-0a6b2d8c4e71"}) returns {"name":"Ada","token":"example"}`
+	if got := transcript.Format("getUser"); got != want {
+		t.Errorf("Format() = %s, want %s", got, want)
+	}
+
+	fn.Inputs = Object(map[string]Schema{"code": String().Pattern(`^[A-Z]{3}$`)})
+	if _, err := fn.FakeTranscript(); err == nil || !strings.Contains(err.Error(), "fake input is invalid") {
+		t.Errorf("FakeTranscript() error = %v", err)
+	}
+
+	recorded := fn.RecordTranscript(map[string]any{"code": "ABC"}, map[string]any{"name": "Ada", "token": "s3cret"})
+	if got := recorded.Format("getUser"); got != `Example: getUser({"code":"ABC"}) returns {"name":"Ada","token":"[REDACTED]"}` {
+		t.Errorf("recorded Format() = %s", got)
+	}
+
+	long := Transcript{Input: map[string]any{"text": strings.Repeat("é", 200)}}
+	if got := long.Format("f"); !strings.HasSuffix(got, "…) returns null") || !utf8.ValidString(got) {
+		t.Errorf("long Format() = %s", got)
+	}
+}
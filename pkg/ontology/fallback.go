@@ -0,0 +1,142 @@
+package ontology
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Fallback serves a call from a second resolver when the function's
+// resolver is slow or fails, for read paths backed by flaky upstreams.
+type Fallback struct {
+	// Resolver is the fallback implementation, e.g. reading a replica or a
+	// cache.
+	Resolver ResolverFunc `json:"-"`
+	// After hedges slow calls: if the function's resolver hasn't returned
+	// within After, Resolver is called too and the first successful output
+	// is used. Zero calls Resolver only when the function's resolver fails.
+	After time.Duration `json:"after,omitempty"`
+	// OnErrors limits failing over to errors matching one of these (with
+	// errors.Is). By default any error fails over.
+	OnErrors []error `json:"-"`
+}
+
+// FallbackShape is the reviewable part of a Fallback, for the lock file.
+type FallbackShape struct {
+	After    string   `json:"after,omitempty"`
+	OnErrors []string `json:"onErrors,omitempty"`
+}
+
+// shape returns the lock file form of f.
+func (f *Fallback) shape() *FallbackShape {
+	if f == nil {
+		return nil
+	}
+	shape := &FallbackShape{}
+	if f.After > 0 {
+		shape.After = f.After.String()
+	}
+	for _, err := range f.OnErrors {
+		shape.OnErrors = append(shape.OnErrors, err.Error())
+	}
+	return shape
+}
+
+// handles reports whether err fails over to the fallback.
+func (f *Fallback) handles(err error) bool {
+	if len(f.OnErrors) == 0 {
+		return true
+	}
+	for _, target := range f.OnErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveFallback calls primary, then Fallback.Resolver if primary is slower
+// than Fallback.After or fails with an error in Fallback.OnErrors. The first
+// successful output wins; if both fail, primary's error is returned. The
+// slower resolver isn't interrupted, and its result is dropped. A panic in
+// either resolver counts as its error.
+func (f *Function) resolveFallback(ctx Context, primary ResolverFunc, input any) (any, error) {
+	fallback := f.Fallback
+	type result struct {
+		output   any
+		err      error
+		fallback bool
+	}
+	results := make(chan result, 2)
+	go func() {
+		output, err := recoverResolver(ctx, input, primary)
+		results <- result{output, err, false}
+	}()
+
+	pending, started := 1, false
+	start := func() {
+		pending++
+		started = true
+		go func() {
+			output, err := recoverResolver(ctx, input, fallback.Resolver)
+			results <- result{output, err, true}
+		}()
+	}
+
+	var hedge <-chan time.Time
+	if fallback.After > 0 {
+		timer := time.NewTimer(fallback.After)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	var primaryErr, fallbackErr error
+	for {
+		select {
+		case <-hedge:
+			hedge = nil
+			if !started {
+				ctx.Logger().Info("Resolver is slow, calling fallback", "after", fallback.After)
+				start()
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				return r.output, nil
+			}
+			if r.fallback {
+				fallbackErr = r.err
+			} else {
+				primaryErr = r.err
+				if !started && fallback.handles(r.err) {
+					ctx.Logger().Warn("Resolver failed, calling fallback", "error", r.err)
+					start()
+				}
+			}
+			if pending == 0 {
+				if fallbackErr != nil {
+					ctx.Logger().Warn("Fallback failed", "error", fallbackErr)
+				}
+				return nil, primaryErr
+			}
+		}
+	}
+}
+
+// validateFallback checks a function's fallback settings.
+func validateFallback(fn Function) error {
+	fallback := fn.Fallback
+	if fallback.Resolver == nil {
+		return fmt.Errorf("fallback: resolver is required")
+	}
+	if fn.Resolver == nil {
+		return fmt.Errorf("fallback: the function needs a resolver to fall back from")
+	}
+	if fallback.After < 0 {
+		return fmt.Errorf("fallback: after must not be negative")
+	}
+	if !fn.IsReadOnly {
+		return fmt.Errorf("fallback: a call may run both resolvers, so it requires a read-only function")
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package ontology
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var errUpstream = errors.New("upstream unavailable")
+
+func fallbackFunction(primary ResolverFunc, fallback *Fallback) Function {
+	return Function{
+		Description: "Get a user",
+		Access:      []string{"admin"},
+		IsReadOnly:  true,
+		Inputs:      Object(map[string]Schema{}),
+		Outputs:     Object(map[string]Schema{"name": String()}),
+		Resolver:    primary,
+		Fallback:    fallback,
+	}
+}
+
+func named(name string, delay time.Duration, err error) ResolverFunc {
+	return func(ctx Context, input any) (any, error) {
+		time.Sleep(delay)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"name": name}, nil
+	}
+}
+
+func panics(ctx Context, input any) (any, error) {
+	var counts map[string]int
+	counts["calls"]++
+	return nil, nil
+}
+
+func TestFallback(t *testing.T) {
+	config := &Config{}
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+	other := errors.New("not found")
+
+	tests := []struct {
+		name     string
+		primary  ResolverFunc
+		fallback *Fallback
+		want     string
+		wantErr  error
+	}{
+		{"primary succeeds", named("primary", 0, nil), &Fallback{Resolver: named("fallback", 0, nil)}, "primary", nil},
+		{"primary fails", named("primary", 0, errUpstream), &Fallback{Resolver: named("fallback", 0, nil)}, "fallback", nil},
+		{"matching error", named("primary", 0, errUpstream), &Fallback{Resolver: named("fallback", 0, nil), OnErrors: []error{errUpstream}}, "fallback", nil},
+		{"other error", named("primary", 0, other), &Fallback{Resolver: named("fallback", 0, nil), OnErrors: []error{errUpstream}}, "", other},
+		{"both fail", named("primary", 0, errUpstream), &Fallback{Resolver: named("fallback", 0, other)}, "", errUpstream},
+		{"hedged", named("primary", time.Second, nil), &Fallback{Resolver: named("fallback", 0, nil), After: 10 * time.Millisecond}, "fallback", nil},
+		{"primary panics", panics, &Fallback{Resolver: named("fallback", 0, nil)}, "fallback", nil},
+		{"fallback panics", named("primary", 0, errUpstream), &Fallback{Resolver: panics}, "", errUpstream},
+		{"hedge loses", named("primary", 30*time.Millisecond, nil), &Fallback{Resolver: named("fallback", time.Second, nil), After: 10 * time.Millisecond}, "primary", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := config.Resolve(ctx, fallbackFunction(tt.primary, tt.fallback), map[string]any{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if got := output.(map[string]any)["name"]; got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackLock(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+		Entities:     map[string]Entity{},
+		Functions:    map[string]Function{"getUser": fallbackFunction(named("primary", 0, nil), nil)},
+	}
+	before := config.Hash()
+
+	fn := config.Functions["getUser"]
+	fn.Fallback = &Fallback{Resolver: named("fallback", 0, nil), After: 200 * time.Millisecond, OnErrors: []error{errUpstream}}
+	config.Functions["getUser"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if config.Hash() == before {
+		t.Error("hash should change when a fallback is added")
+	}
+	shape := config.ExtractSnapshot().Functions["getUser"].Fallback
+	if shape == nil || shape.After != "200ms" || len(shape.OnErrors) != 1 || shape.OnErrors[0] != "upstream unavailable" {
+		t.Errorf("snapshot fallback = %+v", shape)
+	}
+}
+
+func TestValidateFallback(t *testing.T) {
+	resolver := named("fallback", 0, nil)
+	tests := []struct {
+		name     string
+		fallback *Fallback
+		mutate   func(*Function)
+		wantErr  string
+	}{
+		{"no resolver", &Fallback{}, nil, "fallback: resolver is required"},
+		{"negative after", &Fallback{Resolver: resolver, After: -time.Second}, nil, "must not be negative"},
+		{"mutation", &Fallback{Resolver: resolver}, func(fn *Function) { fn.IsReadOnly = false }, "requires a read-only function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Name:         "test",
+				AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+				Entities:     map[string]Entity{},
+				Functions:    map[string]Function{"getUser": fallbackFunction(named("primary", 0, nil), tt.fallback)},
+			}
+			if tt.mutate != nil {
+				fn := config.Functions["getUser"]
+				tt.mutate(&fn)
+				config.Functions["getUser"] = fn
+			}
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field references let an input field take its allowed values from another
+// function, such as a status field whose options come from listStatuses.
+// The options function is called without input and returns an array of
+// values, or of objects with a "value" and an optional "label".
+
+// FieldReference returns the function the input field at path (a JSON
+// pointer, e.g. "/status") takes its options from.
+func (f Function) FieldReference(path string) (string, bool) {
+	for _, ref := range f.FieldReferences {
+		if ref.Path == path {
+			return ref.FunctionName, true
+		}
+	}
+	return "", false
+}
+
+// FieldOptions calls the options function of the input field at path of
+// the named function, on behalf of the caller in ctx, and returns the
+// values it allows.
+func (c *Config) FieldOptions(ctx Context, name, path string) ([]any, error) {
+	fn, ok := c.Functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function '%s'", name)
+	}
+	source, ok := fn.FieldReference(path)
+	if !ok {
+		return nil, fmt.Errorf("function '%s' has no field reference at '%s'", name, path)
+	}
+	output, err := c.Call(ctx, source, map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	items, _ := toJSONValue(output).([]any)
+	values := make([]any, 0, len(items))
+	for _, item := range items {
+		if option, ok := item.(map[string]any); ok {
+			item = option["value"]
+		}
+		values = append(values, item)
+	}
+	return values, nil
+}
+
+// validateFieldReferences checks that each reference names an input field
+// and a function callable without input that returns an array.
+func (c *Config) validateFieldReferences(fn Function) error {
+	seen := make(map[string]bool)
+	for _, ref := range fn.FieldReferences {
+		if seen[ref.Path] {
+			return fmt.Errorf("duplicate field reference '%s'", ref.Path)
+		}
+		seen[ref.Path] = true
+		if schemaAtPointer(fn.Inputs, ref.Path) == nil {
+			return fmt.Errorf("field reference '%s' is not an input field", ref.Path)
+		}
+		source, ok := c.Functions[ref.FunctionName]
+		if !ok {
+			return fmt.Errorf("field reference '%s' references unknown function '%s'", ref.Path, ref.FunctionName)
+		}
+		if err := source.Inputs.Validate(map[string]any{}); err != nil {
+			return fmt.Errorf("field reference '%s': function '%s' requires input", ref.Path, ref.FunctionName)
+		}
+		if output, _ := unwrapNullable(Deref(source.Outputs)); !isArray(output) {
+			return fmt.Errorf("field reference '%s': function '%s' must return an array", ref.Path, ref.FunctionName)
+		}
+	}
+	return nil
+}
+
+func isArray(schema Schema) bool {
+	_, ok := schema.(*ArraySchema)
+	return ok
+}
+
+// schemaAtPointer returns the schema of the field at a JSON pointer such as
+// "/filters/status", with "*" for array items, or nil if there is none.
+func schemaAtPointer(schema Schema, path string) Schema {
+	if !strings.HasPrefix(path, "/") {
+		return nil
+	}
+	for _, name := range strings.Split(path[1:], "/") {
+		schema, _ = unwrapNullable(Deref(schema))
+		switch s := schema.(type) {
+		case *ObjectSchema:
+			schema = s.properties[name]
+		case *ArraySchema:
+			if name != "*" {
+				return nil
+			}
+			schema = s.items
+		default:
+			return nil
+		}
+		if schema == nil {
+			return nil
+		}
+	}
+	return schema
+}
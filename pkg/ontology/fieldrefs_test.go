@@ -0,0 +1,86 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fieldRefConfig() *Config {
+	config := queryConfig()
+	config.Functions["listRecipients"] = Function{
+		Description: "List message recipients",
+		Access:      []string{"support"},
+		Inputs:      Object(map[string]Schema{}),
+		Outputs:     Array(Object(map[string]Schema{"value": String(), "label": String()})),
+		Resolver: func(ctx Context, input any) (any, error) {
+			return []any{
+				map[string]any{"value": "ada@example.com", "label": "Ada"},
+				map[string]any{"value": "alan@example.com", "label": "Alan"},
+			}, nil
+		},
+	}
+	fn := config.Functions["sendMessage"]
+	fn.FieldReferences = []FieldReference{{Path: "/to", FunctionName: "listRecipients"}}
+	config.Functions["sendMessage"] = fn
+	return config
+}
+
+func TestFieldReferences(t *testing.T) {
+	config := fieldRefConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if config.Hash() == queryConfig().Hash() {
+		t.Error("hash should change with field references")
+	}
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	values, err := config.FieldOptions(ctx, "sendMessage", "/to")
+	if err != nil {
+		t.Fatalf("FieldOptions() = %v", err)
+	}
+	if want := []any{"ada@example.com", "alan@example.com"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("FieldOptions() = %v, want %v", values, want)
+	}
+
+	edge := GraphEdge{From: "function:sendMessage", To: "function:listRecipients", Kind: GraphOptions, Label: "/to"}
+	found := false
+	for _, e := range config.Graph().Edges {
+		found = found || e == edge
+	}
+	if !found {
+		t.Errorf("Graph() is missing %v", edge)
+	}
+}
+
+func TestFieldReferencesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     FieldReference
+		outputs Schema
+		wantErr string
+	}{
+		{"unknown field", FieldReference{Path: "/recipient", FunctionName: "listRecipients"}, nil, "is not an input field"},
+		{"unknown function", FieldReference{Path: "/to", FunctionName: "listPeople"}, nil, "unknown function 'listPeople'"},
+		{"requires input", FieldReference{Path: "/to", FunctionName: "getCustomer"}, nil, "requires input"},
+		{"not an array", FieldReference{Path: "/to", FunctionName: "listRecipients"}, Object(map[string]Schema{"value": String()}), "must return an array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := fieldRefConfig()
+			if tt.outputs != nil {
+				source := config.Functions["listRecipients"]
+				source.Outputs = tt.outputs
+				config.Functions["listRecipients"] = source
+			}
+			fn := config.Functions["sendMessage"]
+			fn.FieldReferences = []FieldReference{tt.ref}
+			config.Functions["sendMessage"] = fn
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package ontology
+
+import (
+	"fmt"
+	"sync"
+)
+
+// builtinFormats are the string formats the package validates itself.
+var builtinFormats = []string{"uuid", "email", "date-time", "date", "uri"}
+
+// formats holds the string formats added with RegisterFormat.
+var formats = struct {
+	sync.RWMutex
+	checks map[string]func(string) error
+}{checks: make(map[string]func(string) error)}
+
+// RegisterFormat adds a named string format for domain values such as IBANs
+// or SKUs. Strings declared with String().Format(name) are valid when check
+// returns nil; its error is reported as the validation error. The name is
+// emitted as the JSON Schema "format" and in generated documentation.
+//
+// Register formats before validating a Config that uses them, typically in
+// an init function. RegisterFormat panics if name is empty, a built-in
+// format or already registered.
+func RegisterFormat(name string, check func(string) error) {
+	if name == "" || check == nil {
+		panic("ontology: RegisterFormat needs a name and a check")
+	}
+	if contains(builtinFormats, name) {
+		panic(fmt.Sprintf("ontology: format '%s' is built in", name))
+	}
+	formats.Lock()
+	defer formats.Unlock()
+	if _, ok := formats.checks[name]; ok {
+		panic(fmt.Sprintf("ontology: format '%s' is already registered", name))
+	}
+	formats.checks[name] = check
+}
+
+// lookupFormat returns the check of a registered format.
+func lookupFormat(name string) (func(string) error, bool) {
+	formats.RLock()
+	defer formats.RUnlock()
+	check, ok := formats.checks[name]
+	return check, ok
+}
+
+// knownFormat reports whether name is a built-in or registered format.
+func knownFormat(name string) bool {
+	if contains(builtinFormats, name) {
+		return true
+	}
+	_, ok := lookupFormat(name)
+	return ok
+}
+
+// Format constrains the string to a named format: one of the built-ins
+// (uuid, email, date-time, date, uri) or one added with RegisterFormat.
+func (s *StringSchema) Format(name string) *StringSchema {
+	s.constrain("format")
+	s.format = name
+	return s
+}
+
+// FormatName returns the string format constraint, if any.
+func (s *StringSchema) FormatName() string {
+	return s.format
+}
+
+// checkFormats checks that every string format in schema is known, so a
+// missing RegisterFormat call fails at startup rather than on each call.
+func checkFormats(schema Schema) error {
+	var err error
+	walkSchema(schema, func(s Schema) {
+		str, ok := s.(*StringSchema)
+		if !ok || err != nil || str.format == "" {
+			return
+		}
+		if !knownFormat(str.format) {
+			err = fmt.Errorf("unknown string format '%s'", str.format)
+		}
+	})
+	return err
+}
@@ -0,0 +1,74 @@
+package ontology
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterFormat("sku", func(s string) error {
+		if !strings.HasPrefix(s, "SKU-") {
+			return errors.New("must start with SKU-")
+		}
+		return nil
+	})
+}
+
+func TestRegisteredFormat(t *testing.T) {
+	schema := String().Format("sku")
+
+	if err := schema.Validate("SKU-1234"); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	err := schema.Validate("1234")
+	if err == nil || err.Error() != "string is not a valid sku: must start with SKU-" {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := schema.JSONSchema()["format"]; got != "sku" {
+		t.Errorf("JSON Schema format = %v", got)
+	}
+	if err := String().Format("email").Validate("nope"); err == nil {
+		t.Error("Format should accept built-in formats")
+	}
+
+	type item struct {
+		SKU string `json:"sku" ont:"format=sku"`
+	}
+	if got := FromStruct[item]().Properties()["sku"].(*StringSchema).FormatName(); got != "sku" {
+		t.Errorf("FromStruct format = %q", got)
+	}
+
+	imported, err := ParseJSONSchema([]byte(`{"type": "string", "format": "sku"}`))
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+	if imported.Validate("1234") == nil {
+		t.Error("imported schema should check the registered format")
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	config := refConfig()
+	fn := config.Functions["getUser"]
+	fn.Inputs = Object(map[string]Schema{"id": String().Format("iban")})
+	config.Functions["getUser"] = fn
+
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "unknown string format 'iban'") {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+}
+
+func TestRegisterFormatPanics(t *testing.T) {
+	for _, name := range []string{"", "email", "sku"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterFormat(%q) should panic", name)
+				}
+			}()
+			RegisterFormat(name, func(string) error { return nil })
+		}()
+	}
+}
@@ -0,0 +1,304 @@
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromStruct derives an object schema from the struct type T, so a
+// resolver's input or output struct doubles as the function's schema:
+//
+//	type CreateUser struct {
+//		Email string   `json:"email" ont:"email"`
+//		Name  string   `json:"name" ont:"min=1,max=100" description:"Display name"`
+//		Tags  []string `json:"tags,omitempty" ont:"max=10"`
+//		Age   *int     `json:"age"`
+//	}
+//
+//	Inputs: ont.FromStruct[CreateUser](),
+//
+// Properties are named by json tags and follow encoding/json: untagged
+// fields use the Go name, "-" and unexported fields are skipped, and
+// embedded structs are flattened. Fields are required unless tagged
+// omitempty. Go types map to schemas as follows: strings, bools and
+// numbers to String, Boolean, Number and Integer (Int64 for int64 and
+// uint64), time.Time to a date-time string, []byte to Bytes, slices to
+// Array, structs to Object, pointers to Nullable, and maps, interfaces and
+// json.RawMessage to Any.
+//
+// The ont tag adds constraints, separated by commas: min=N and max=N
+// (length, value, item count or byte count, depending on the type),
+// nonempty, email, uuid, uri, date, datetime, format=NAME (see
+// RegisterFormat), pattern=RE, enum=a|b|c, strict (for struct fields), and optional or required to
+// override omitempty. A description tag sets the description.
+//
+// FromStruct panics if T isn't a struct, a field has an unsupported type or
+// an ont tag is invalid, or the type is recursive (use Ref for those).
+func FromStruct[T any]() *ObjectSchema {
+	schema, err := structSchema(reflect.TypeFor[T](), nil)
+	if err != nil {
+		panic(fmt.Sprintf("ontology: FromStruct[%s]: %v", reflect.TypeFor[T](), err))
+	}
+	return schema
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// structSchema derives the object schema of a struct type. visiting holds
+// the struct types being derived, to detect recursion.
+func structSchema(t reflect.Type, visiting []reflect.Type) (*ObjectSchema, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", t)
+	}
+	for _, v := range visiting {
+		if v == t {
+			return nil, fmt.Errorf("%s is recursive", t)
+		}
+	}
+	visiting = append(visiting, t)
+
+	props := make(map[string]Schema)
+	var optional []string
+	if err := addStructFields(t, visiting, props, &optional); err != nil {
+		return nil, err
+	}
+	return Object(props).Optional(optional...), nil
+}
+
+// addStructFields adds the properties of t's fields to props, flattening
+// embedded structs.
+func addStructFields(t reflect.Type, visiting []reflect.Type, props map[string]Schema, optional *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addStructFields(embedded, visiting, props, optional); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema, isOptional, err := fieldSchema(field, visiting)
+		if err != nil {
+			return fmt.Errorf("field '%s': %w", name, err)
+		}
+		if tagOpts := strings.Split(opts, ","); contains(tagOpts, "omitempty") || contains(tagOpts, "omitzero") {
+			isOptional = isOptional || !hasTagOption(field, "required")
+		}
+		props[name] = schema
+		if isOptional {
+			*optional = append(*optional, name)
+		}
+	}
+	return nil
+}
+
+// fieldSchema derives the schema of a struct field from its type and its ont
+// and description tags. It reports whether the ont tag marks it optional.
+func fieldSchema(field reflect.StructField, visiting []reflect.Type) (Schema, bool, error) {
+	t := field.Type
+	nullable := t.Kind() == reflect.Pointer
+	if nullable {
+		t = t.Elem()
+	}
+
+	schema, err := typeSchema(t, visiting)
+	if err != nil {
+		return nil, false, err
+	}
+	optional := false
+	if tag := field.Tag.Get("ont"); tag != "" {
+		for _, opt := range strings.Split(tag, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+			switch key {
+			case "optional":
+				optional = true
+			case "required":
+			default:
+				if schema, err = applyConstraint(schema, key, value); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+	}
+	if nullable {
+		schema = Nullable(schema)
+	}
+	if description := field.Tag.Get("description"); description != "" {
+		schema.(interface{ setDescription(string) }).setDescription(description)
+	}
+	return schema, optional, nil
+}
+
+// typeSchema derives the schema of a Go type.
+func typeSchema(t reflect.Type, visiting []reflect.Type) (Schema, error) {
+	switch t {
+	case timeType:
+		return String().DateTime(), nil
+	case rawMessageType:
+		return Any(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return String(), nil
+	case reflect.Bool:
+		return Boolean(), nil
+	case reflect.Int64, reflect.Uint64:
+		return Int64(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Integer(), nil
+	case reflect.Float32, reflect.Float64:
+		return Number(), nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Bytes(), nil
+		}
+		items, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return Array(items), nil
+	case reflect.Pointer:
+		inner, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return Nullable(inner), nil
+	case reflect.Struct:
+		return structSchema(t, visiting)
+	case reflect.Map, reflect.Interface:
+		return Any(), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// applyConstraint applies one ont tag option to a schema.
+func applyConstraint(schema Schema, key, value string) (Schema, error) {
+	number := func() (float64, error) {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("ont tag %s: '%s' is not a number", key, value)
+		}
+		return n, nil
+	}
+
+	switch s := schema.(type) {
+	case *StringSchema:
+		switch key {
+		case "min", "max":
+			n, err := number()
+			if err != nil {
+				return nil, err
+			}
+			if key == "min" {
+				return s.Min(int(n)), nil
+			}
+			return s.Max(int(n)), nil
+		case "nonempty":
+			return s.Min(1), nil
+		case "email":
+			return s.Email(), nil
+		case "uuid":
+			return s.UUID(), nil
+		case "uri", "url":
+			return s.URI(), nil
+		case "date":
+			return s.Date(), nil
+		case "datetime":
+			return s.DateTime(), nil
+		case "format":
+			return s.Format(value), nil
+		case "pattern":
+			return s.Pattern(value), nil
+		case "enum":
+			return s.Enum(strings.Split(value, "|")...), nil
+		}
+	case *NumberSchema:
+		switch key {
+		case "min", "max":
+			n, err := number()
+			if err != nil {
+				return nil, err
+			}
+			if key == "min" {
+				return s.Min(n), nil
+			}
+			return s.Max(n), nil
+		}
+	case *ArraySchema:
+		switch key {
+		case "min", "max":
+			n, err := number()
+			if err != nil {
+				return nil, err
+			}
+			if key == "min" {
+				return s.MinItems(int(n)), nil
+			}
+			return s.MaxItems(int(n)), nil
+		case "nonempty":
+			return s.NonEmpty(), nil
+		}
+	case *BytesSchema:
+		if key == "max" {
+			n, err := number()
+			if err != nil {
+				return nil, err
+			}
+			return s.MaxBytes(int(n)), nil
+		}
+	case *ObjectSchema:
+		if key == "strict" {
+			return s.Strict(), nil
+		}
+	case *NullableSchema:
+		inner, err := applyConstraint(s.inner, key, value)
+		if err != nil {
+			return nil, err
+		}
+		s.inner = inner
+		return s, nil
+	}
+	return nil, fmt.Errorf("ont tag '%s' doesn't apply to %s", key, schema.TypeName())
+}
+
+// hasTagOption reports whether a field's ont tag contains option.
+func hasTagOption(field reflect.StructField, option string) bool {
+	for _, opt := range strings.Split(field.Tag.Get("ont"), ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// setDescription sets the description, for schemas built by reflection.
+func (a *annotations) setDescription(text string) {
+	a.description = text
+}
@@ -0,0 +1,118 @@
+package ontology
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type auditInfo struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type address struct {
+	City string `json:"city" ont:"nonempty"`
+}
+
+type createUser struct {
+	auditInfo
+	ID       int64           `json:"id"`
+	Email    string          `json:"email" ont:"email" description:"Login email"`
+	Name     string          `json:"name" ont:"min=1,max=100"`
+	Role     string          `json:"role" ont:"enum=admin|member"`
+	Tags     []string        `json:"tags,omitempty" ont:"max=3"`
+	Age      *int            `json:"age" ont:"min=0"`
+	Score    float64         `json:"score,omitempty" ont:"required"`
+	Avatar   []byte          `json:"avatar,omitempty" ont:"max=1024"`
+	Address  address         `json:"address" ont:"strict"`
+	Meta     map[string]any  `json:"meta" ont:"optional"`
+	Raw      json.RawMessage `json:"raw,omitempty"`
+	Nickname string          `json:"-"`
+	secret   string
+	Extra    map[string]string `json:"extra,omitempty"`
+}
+
+func TestFromStruct(t *testing.T) {
+	schema := FromStruct[createUser]()
+
+	var props []string
+	for name := range schema.Properties() {
+		props = append(props, name)
+	}
+	wantProps := []string{"address", "age", "avatar", "createdAt", "email", "extra", "id", "meta", "name", "raw", "role", "score", "tags"}
+	if got := sortedCopy(props); !reflect.DeepEqual(got, wantProps) {
+		t.Errorf("properties = %v, want %v", got, wantProps)
+	}
+	wantRequired := []string{"address", "age", "createdAt", "email", "id", "name", "role", "score"}
+	if got := sortedCopy(schema.Required()); !reflect.DeepEqual(got, wantRequired) {
+		t.Errorf("required = %v, want %v", got, wantRequired)
+	}
+
+	js := schema.JSONSchema()["properties"].(map[string]any)
+	checks := map[string]map[string]any{
+		"id":        {"type": "integer", "format": "int64"},
+		"email":     {"format": "email", "description": "Login email"},
+		"createdAt": {"format": "date-time"},
+		"avatar":    {"contentEncoding": "base64"},
+		"address":   {"additionalProperties": false},
+	}
+	for name, want := range checks {
+		for key, value := range want {
+			if got := js[name].(map[string]any)[key]; !reflect.DeepEqual(got, value) {
+				t.Errorf("%s.%s = %v, want %v", name, key, got, value)
+			}
+		}
+	}
+
+	valid := map[string]any{
+		"id": 1.0, "email": "a@b.co", "name": "Ann", "role": "admin", "age": nil, "score": 1.5,
+		"address": map[string]any{"city": "Paris"}, "createdAt": "2024-01-01T00:00:00Z",
+	}
+	if err := schema.Validate(valid); err != nil {
+		t.Errorf("expected valid input, got %v", err)
+	}
+	for field, value := range map[string]any{"role": "owner", "name": "", "tags": []any{"a", "b", "c", "d"}, "age": -1.0} {
+		invalid := make(map[string]any)
+		for k, v := range valid {
+			invalid[k] = v
+		}
+		invalid[field] = value
+		if err := schema.Validate(invalid); err == nil {
+			t.Errorf("expected %s=%v to be rejected", field, value)
+		}
+	}
+}
+
+type node struct {
+	Children []node `json:"children"`
+}
+
+func TestFromStructPanics(t *testing.T) {
+	tests := map[string]func(){
+		"recursive":    func() { FromStruct[node]() },
+		"not a struct": func() { FromStruct[string]() },
+		"bad tag": func() {
+			FromStruct[struct {
+				N int `ont:"email"`
+			}]()
+		},
+		"bad number": func() {
+			FromStruct[struct {
+				S string `ont:"max=ten"`
+			}]()
+		},
+		"unsupported": func() { FromStruct[struct{ C chan int }]() },
+	}
+	for name, build := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil || !strings.HasPrefix(r.(string), "ontology: FromStruct") {
+					t.Errorf("expected FromStruct panic, got %v", r)
+				}
+			}()
+			build()
+		})
+	}
+}
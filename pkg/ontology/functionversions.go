@@ -0,0 +1,113 @@
+package ontology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Functions are versioned side by side: the function under its plain name
+// (e.g. "getUser", with Version 2) is the current version, and older
+// versions stay served under versioned names ("getUser@1") until callers
+// have moved on.
+
+// FunctionIdentifier returns a function name usable as an identifier, for
+// MCP tool names and generated SDKs: versions such as "getUser@1" become
+// "getUserV1".
+func FunctionIdentifier(name string) string {
+	if base, version, ok := ParseVersionedName(name); ok {
+		return fmt.Sprintf("%sV%d", base, version)
+	}
+	return name
+}
+
+// FunctionVersion returns the version of a function: N for "getUser@N",
+// and its Version otherwise (0 if unversioned).
+func FunctionVersion(name string, fn Function) int {
+	if _, version, ok := ParseVersionedName(name); ok {
+		return version
+	}
+	return fn.Version
+}
+
+// VersionedRoute returns the path under /api a function version is also
+// served at, e.g. "v1/getUser" for "getUser@1" and "v2/getUser" for
+// getUser with Version 2. It returns false for unversioned functions.
+func VersionedRoute(name string, fn Function) (string, bool) {
+	version := FunctionVersion(name, fn)
+	if version == 0 {
+		return "", false
+	}
+	base, _, _ := ParseVersionedName(name)
+	return fmt.Sprintf("v%d/%s", version, base), true
+}
+
+// FunctionVersions returns the served versions of the logical function
+// name, in order: the current version's Version and every "name@N". It is
+// nil for unversioned functions.
+func (c *Config) FunctionVersions(name string) []int {
+	var versions []int
+	if fn, ok := c.Functions[name]; ok && fn.Version > 0 {
+		versions = append(versions, fn.Version)
+	}
+	for other := range c.Functions {
+		if base, version, ok := ParseVersionedName(other); ok && base == name {
+			versions = append(versions, version)
+		}
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// functionVersions returns the served versions of every versioned
+// function, by logical name, for the lock file.
+func (c *Config) functionVersions() map[string][]int {
+	var result map[string][]int
+	for name := range c.Functions {
+		if strings.Contains(name, "@") {
+			continue
+		}
+		if versions := c.FunctionVersions(name); len(versions) > 0 {
+			if result == nil {
+				result = make(map[string][]int)
+			}
+			result[name] = versions
+		}
+	}
+	return result
+}
+
+// validateFunctionVersion checks that a versioned name is an older version
+// of a current function, and that its identifier doesn't clash with
+// another function.
+func (c *Config) validateFunctionVersion(name string, fn Function) error {
+	if fn.Version < 0 {
+		return fmt.Errorf("version %d is negative", fn.Version)
+	}
+	if !strings.Contains(name, "@") {
+		return nil
+	}
+	base, version, ok := ParseVersionedName(name)
+	if !ok {
+		return fmt.Errorf("invalid versioned name, expected e.g. '%s@1'", strings.Split(name, "@")[0])
+	}
+	if fn.Version != 0 && fn.Version != version {
+		return fmt.Errorf("version %d doesn't match its name", fn.Version)
+	}
+	current, ok := c.Functions[base]
+	if !ok {
+		return fmt.Errorf("no current version '%s'", base)
+	}
+	if current.Version <= version {
+		return fmt.Errorf("current version of '%s' must have a Version above %d", base, version)
+	}
+	if id := FunctionIdentifier(name); id != name {
+		if _, ok := c.Functions[id]; ok {
+			return fmt.Errorf("conflicts with function '%s'", id)
+		}
+		if other, ok := c.ResolveAlias(id); ok {
+			return fmt.Errorf("conflicts with an alias of function '%s'", other)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func versionedConfig() *Config {
+	config := queryConfig()
+	current := config.Functions["getCustomer"]
+	current.Version = 2
+	config.Functions["getCustomer"] = current
+	old := current
+	old.Version = 0
+	old.Outputs = Object(map[string]Schema{"id": String()})
+	config.Functions["getCustomer@1"] = old
+	return config
+}
+
+func TestFunctionVersions(t *testing.T) {
+	config := versionedConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if got := config.FunctionVersions("getCustomer"); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("FunctionVersions = %v", got)
+	}
+	if got := config.FunctionVersions("sendMessage"); got != nil {
+		t.Errorf("FunctionVersions of an unversioned function = %v", got)
+	}
+	if got := FunctionIdentifier("getCustomer@1"); got != "getCustomerV1" {
+		t.Errorf("FunctionIdentifier = %q", got)
+	}
+
+	routes := map[string]string{"getCustomer@1": "v1/getCustomer", "getCustomer": "v2/getCustomer", "sendMessage": ""}
+	for name, want := range routes {
+		if got, _ := VersionedRoute(name, config.Functions[name]); got != want {
+			t.Errorf("VersionedRoute(%s) = %q, want %q", name, got, want)
+		}
+	}
+
+	if name, _, ok := NewRegistry(config).Lookup("getCustomerV1"); !ok || name != "getCustomer@1" {
+		t.Errorf("Lookup by identifier = %q, %v", name, ok)
+	}
+
+	snapshot := config.ExtractSnapshot()
+	if !reflect.DeepEqual(snapshot.Versions, map[string][]int{"getCustomer": {1, 2}}) {
+		t.Errorf("snapshot versions = %v", snapshot.Versions)
+	}
+	if snapshot.Functions["getCustomer"].Version != 2 {
+		t.Errorf("snapshot version = %d", snapshot.Functions["getCustomer"].Version)
+	}
+}
+
+func TestValidateFunctionVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(c *Config)
+		wantErr string
+	}{
+		{"invalid name", func(c *Config) {
+			c.Functions["getCustomer@latest"] = c.Functions["getCustomer@1"]
+		}, "invalid versioned name"},
+		{"no current version", func(c *Config) {
+			c.Functions["listCustomers@1"] = c.Functions["getCustomer@1"]
+		}, "no current version 'listCustomers'"},
+		{"not older", func(c *Config) {
+			c.Functions["getCustomer@3"] = c.Functions["getCustomer@1"]
+		}, "must have a Version above 3"},
+		{"mismatched version", func(c *Config) {
+			fn := c.Functions["getCustomer@1"]
+			fn.Version = 3
+			c.Functions["getCustomer@1"] = fn
+		}, "version 3 doesn't match its name"},
+		{"negative", func(c *Config) {
+			fn := c.Functions["sendMessage"]
+			fn.Version = -1
+			c.Functions["sendMessage"] = fn
+		}, "version -1 is negative"},
+		{"identifier conflict", func(c *Config) {
+			c.Functions["getCustomerV1"] = c.Functions["sendMessage"]
+		}, "conflicts with function 'getCustomerV1'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := versionedConfig()
+			tt.edit(config)
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Graph node kinds.
+const (
+	GraphFunction    = "function"
+	GraphEntity      = "entity"
+	GraphAccessGroup = "accessGroup"
+)
+
+// Graph edge kinds.
+const (
+	// GraphCanCall links an access group to a function it may call.
+	GraphCanCall = "canCall"
+	// GraphUses links a function to an entity it declares.
+	GraphUses = "uses"
+	// GraphCalls links a composed function (pipeline or report) to a function it calls.
+	GraphCalls = "calls"
+	// GraphOptions links a function to the function an input field takes
+	// its options from (see Function.FieldReferences); the edge's label is
+	// the field's path.
+	GraphOptions = "options"
+	// GraphRelates links an entity to another by one of its Relations; the
+	// edge's label is the relation's name.
+	GraphRelates = "relates"
+)
+
+// GraphNode is a function, entity or access group.
+type GraphNode struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	// Details is the LongDescription of entities and functions, in markdown.
+	Details string `json:"details,omitempty"`
+}
+
+// GraphEdge connects two nodes by ID.
+type GraphEdge struct {
+	From        string      `json:"from"`
+	To          string      `json:"to"`
+	Kind        string      `json:"kind"`
+	Label       string      `json:"label,omitempty"`
+	Cardinality Cardinality `json:"cardinality,omitempty"`
+}
+
+// Graph is the ontology as nodes and edges, for visualization.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// Graph returns the relationships between functions, entities and access
+// groups, including the calls made by pipelines and reports, the functions
+// field references take options from, and the relations between entities. Nodes and
+// edges are sorted so the output is stable.
+func (c *Config) Graph() *Graph {
+	g := &Graph{}
+	for _, name := range sortedKeys(c.AccessGroups) {
+		g.Nodes = append(g.Nodes, GraphNode{ID: graphID(GraphAccessGroup, name), Kind: GraphAccessGroup, Name: name, Label: c.AccessGroups[name].Description})
+	}
+	for _, name := range sortedKeys(c.Entities) {
+		g.Nodes = append(g.Nodes, GraphNode{ID: graphID(GraphEntity, name), Kind: GraphEntity, Name: name, Label: c.Entities[name].Description, Details: c.Entities[name].LongDescription})
+		for _, rel := range c.Entities[name].Relations {
+			g.Edges = append(g.Edges, GraphEdge{
+				From:        graphID(GraphEntity, name),
+				To:          graphID(GraphEntity, rel.To),
+				Kind:        GraphRelates,
+				Label:       rel.Name,
+				Cardinality: rel.Cardinality,
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(c.Functions) {
+		fn := c.Functions[name]
+		id := graphID(GraphFunction, name)
+		g.Nodes = append(g.Nodes, GraphNode{ID: id, Kind: GraphFunction, Name: name, Label: fn.Description, Details: fn.LongDescription})
+
+		for _, group := range sortedCopy(fn.Access) {
+			g.Edges = append(g.Edges, GraphEdge{From: graphID(GraphAccessGroup, group), To: id, Kind: GraphCanCall})
+		}
+		for _, entity := range sortedCopy(fn.Entities) {
+			g.Edges = append(g.Edges, GraphEdge{From: id, To: graphID(GraphEntity, entity), Kind: GraphUses})
+		}
+		for _, callee := range functionCallees(fn) {
+			if _, ok := c.Functions[callee]; !ok {
+				continue
+			}
+			g.Edges = append(g.Edges, GraphEdge{From: id, To: graphID(GraphFunction, callee), Kind: GraphCalls})
+		}
+		for _, ref := range fn.FieldReferences {
+			if _, ok := c.Functions[ref.FunctionName]; ok {
+				g.Edges = append(g.Edges, GraphEdge{From: id, To: graphID(GraphFunction, ref.FunctionName), Kind: GraphOptions, Label: ref.Path})
+			}
+		}
+	}
+	return g
+}
+
+// Filter returns the graph without the nodes keep rejects and the edges
+// touching them.
+func (g *Graph) Filter(keep func(GraphNode) bool) *Graph {
+	filtered := &Graph{}
+	kept := make(map[string]bool, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if keep(n) {
+			kept[n.ID] = true
+			filtered.Nodes = append(filtered.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if kept[e.From] && kept[e.To] {
+			filtered.Edges = append(filtered.Edges, e)
+		}
+	}
+	return filtered
+}
+
+// functionCallees returns the functions a pipeline or report calls, sorted
+// and without duplicates.
+func functionCallees(fn Function) []string {
+	seen := make(map[string]bool)
+	for _, callee := range fn.calls {
+		seen[callee] = true
+	}
+	if fn.Pipeline != nil {
+		for _, step := range fn.Pipeline.Steps {
+			seen[step.Function] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+func graphID(kind, name string) string {
+	return kind + ":" + name
+}
+
+// DOT renders the graph in Graphviz DOT format.
+func (g *Graph) DOT() string {
+	shapes := map[string]string{
+		GraphFunction:    "box",
+		GraphEntity:      "ellipse",
+		GraphAccessGroup: "hexagon",
+	}
+	styles := map[string]string{
+		GraphCanCall: "dashed",
+		GraphUses:    "solid",
+		GraphCalls:   "bold",
+		GraphOptions: "dotted",
+		GraphRelates: "dotted",
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph ontology {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s", dotQuote(n.ID), dotQuote(n.Name), shapes[n.Kind])
+		if n.Label != "" {
+			fmt.Fprintf(&b, ", tooltip=%s", dotQuote(n.Label))
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s, style=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.label()), styles[e.Kind])
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// label returns the edge's label for diagrams: the relation's name and
+// cardinality, or the edge's kind.
+func (e GraphEdge) label() string {
+	switch {
+	case e.Label != "" && e.Cardinality != "":
+		return e.Label + " (" + string(e.Cardinality) + ")"
+	case e.Label != "":
+		return e.Label
+	}
+	return e.Kind
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	// Mermaid IDs must be plain identifiers, so number the nodes
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+	shapes := map[string][2]string{
+		GraphFunction:    {"[", "]"},
+		GraphEntity:      {"([", "])"},
+		GraphAccessGroup: {"{{", "}}"},
+	}
+	arrows := map[string]string{
+		GraphCanCall: "-.->",
+		GraphUses:    "-->",
+		GraphCalls:   "==>",
+		GraphOptions: "-.->",
+		GraphRelates: "-.->",
+	}
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		shape := shapes[n.Kind]
+		fmt.Fprintf(&b, "  %s%s%s%s\n", ids[n.ID], shape[0], mermaidQuote(n.Name), shape[1])
+	}
+	for _, e := range g.Edges {
+		from, ok := ids[e.From]
+		to, ok2 := ids[e.To]
+		if !ok || !ok2 {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s %s|%s| %s\n", from, arrows[e.Kind], e.label(), to)
+	}
+
+	// Style node kinds so the diagram has a legend by color
+	classes := map[string]string{
+		GraphFunction:    "fill:#e8f0fe,stroke:#4285f4",
+		GraphEntity:      "fill:#e6f4ea,stroke:#34a853",
+		GraphAccessGroup: "fill:#fef7e0,stroke:#f9ab00",
+	}
+	byKind := make(map[string][]string)
+	for _, n := range g.Nodes {
+		byKind[n.Kind] = append(byKind[n.Kind], ids[n.ID])
+	}
+	for _, kind := range sortedKeys(byKind) {
+		fmt.Fprintf(&b, "  classDef %s %s\n", kind, classes[kind])
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(byKind[kind], ","), kind)
+	}
+	return b.String()
+}
+
+// WriteGraph writes the ontology graph to path in the format its extension
+// names: .dot or .gv for Graphviz, .mmd or .md for Mermaid (fenced in .md
+// files so it renders on GitHub), or .json.
+func (c *Config) WriteGraph(path string) error {
+	data, err := c.Graph().encode(strings.ToLower(filepath.Ext(path)))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	return nil
+}
+
+// encode renders the graph in the format of a file extension (see
+// WriteGraph).
+func (g *Graph) encode(ext string) ([]byte, error) {
+	switch ext {
+	case ".dot", ".gv":
+		return []byte(g.DOT()), nil
+	case ".mmd":
+		return []byte(g.Mermaid()), nil
+	case ".md":
+		return []byte("```mermaid\n" + g.Mermaid() + "```\n"), nil
+	case ".json":
+		data, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal graph: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+	return nil, fmt.Errorf("unknown graph format '%s'", ext)
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
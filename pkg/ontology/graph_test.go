@@ -0,0 +1,160 @@
+package ontology
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func graphConfig() *Config {
+	config := pipelineConfig(Function{
+		Inputs:   Object(map[string]Schema{"email": String()}),
+		Outputs:  Object(map[string]Schema{"sent": Boolean(), "to": String()}),
+		Entities: []string{"Customer"},
+		Pipeline: &Pipeline{Steps: []PipelineStep{
+			{Name: "customer", Function: "getCustomer", Input: map[string]string{"email": "input.email"}},
+			{Function: "sendMessage", Input: map[string]string{"to": "customer.id"}},
+		}},
+	})
+	config.Entities = map[string]Entity{"Customer": {Description: `A "paying" customer`}}
+	return config
+}
+
+func TestGraph(t *testing.T) {
+	g := graphConfig().Graph()
+
+	var nodes []string
+	for _, n := range g.Nodes {
+		nodes = append(nodes, n.ID)
+	}
+	wantNodes := []string{
+		"accessGroup:admin", "accessGroup:support", "entity:Customer",
+		"function:deleteCustomer", "function:getCustomer", "function:pipeline", "function:sendMessage",
+	}
+	if !reflect.DeepEqual(nodes, wantNodes) {
+		t.Errorf("nodes = %v, want %v", nodes, wantNodes)
+	}
+
+	wantEdges := []GraphEdge{
+		{From: "accessGroup:admin", To: "function:deleteCustomer", Kind: GraphCanCall},
+		{From: "accessGroup:support", To: "function:getCustomer", Kind: GraphCanCall},
+		{From: "accessGroup:support", To: "function:pipeline", Kind: GraphCanCall},
+		{From: "function:pipeline", To: "entity:Customer", Kind: GraphUses},
+		{From: "function:pipeline", To: "function:getCustomer", Kind: GraphCalls},
+		{From: "function:pipeline", To: "function:sendMessage", Kind: GraphCalls},
+		{From: "accessGroup:support", To: "function:sendMessage", Kind: GraphCanCall},
+	}
+	if !reflect.DeepEqual(g.Edges, wantEdges) {
+		t.Errorf("edges = %v, want %v", g.Edges, wantEdges)
+	}
+}
+
+func TestGraphFilter(t *testing.T) {
+	g := graphConfig().Graph().Filter(func(n GraphNode) bool {
+		return n.ID != "function:getCustomer"
+	})
+	for _, n := range g.Nodes {
+		if n.ID == "function:getCustomer" {
+			t.Error("Filter() should drop the rejected node")
+		}
+	}
+	for _, e := range g.Edges {
+		if e.From == "function:getCustomer" || e.To == "function:getCustomer" {
+			t.Errorf("Filter() should drop edges touching the rejected node, got %v", e)
+		}
+	}
+	if len(g.Edges) != 5 {
+		t.Errorf("Filter() kept %d edges, want 5", len(g.Edges))
+	}
+}
+
+func TestGraphFormats(t *testing.T) {
+	g := graphConfig().Graph()
+
+	dot := g.DOT()
+	for _, want := range []string{
+		"digraph ontology {",
+		`"entity:Customer" [label="Customer", shape=ellipse, tooltip="A \"paying\" customer"];`,
+		`"function:pipeline" -> "function:getCustomer" [label="calls", style=bold];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := g.Mermaid()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`n2(["Customer"])`,
+		"n5 ==>|calls| n4",
+		"n1 -.->|canCall| n5",
+		"class n3,n4,n5,n6 function",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestWriteGraph(t *testing.T) {
+	config := graphConfig()
+	dir := t.TempDir()
+
+	for _, name := range []string{"ontology.dot", "ontology.mmd", "ontology.md", "ontology.json"} {
+		path := filepath.Join(dir, name)
+		if err := config.WriteGraph(path); err != nil {
+			t.Fatalf("WriteGraph(%s) failed: %v", name, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || len(data) == 0 {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "ontology.md")); !strings.HasPrefix(string(data), "```mermaid\nflowchart LR") {
+		t.Errorf("markdown graph = %q", data)
+	}
+	if err := config.WriteGraph(filepath.Join(dir, "ontology.png")); err == nil {
+		t.Error("WriteGraph should reject unknown formats")
+	}
+}
+
+func TestGraphCommand(t *testing.T) {
+	run := func(args ...string) (int, string, string) {
+		var out, errOut strings.Builder
+		cmd := &GraphCommand{Config: graphConfig(), Out: &out, Err: &errOut}
+		return cmd.Run(args), out.String(), errOut.String()
+	}
+
+	if code, out, _ := run(); code != 0 || !strings.HasPrefix(out, "flowchart LR") {
+		t.Errorf("graph = %d, %q", code, out)
+	}
+	if code, out, _ := run("--format", "dot"); code != 0 || !strings.HasPrefix(out, "digraph") {
+		t.Errorf("graph --format dot = %d, %q", code, out)
+	}
+	if code, _, errOut := run("--format", "png"); code != 2 || !strings.Contains(errOut, "unknown format") {
+		t.Errorf("graph --format png = %d, %q", code, errOut)
+	}
+	if code, _, _ := run("extra"); code != 2 {
+		t.Errorf("graph extra = %d, want 2", code)
+	}
+
+	// -o takes the format from the extension unless --format is set
+	dir := t.TempDir()
+	if code, _, errOut := run("-o", filepath.Join(dir, "ontology.md")); code != 0 {
+		t.Fatalf("graph -o = %d: %s", code, errOut)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "ontology.md")); !strings.HasPrefix(string(data), "```mermaid\n") {
+		t.Errorf("ontology.md = %q", data)
+	}
+	if code, _, errOut := run("-o", filepath.Join(dir, "graph.txt"), "--format", "json"); code != 0 {
+		t.Fatalf("graph -o --format json = %d: %s", code, errOut)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "graph.txt")); !strings.HasPrefix(string(data), "{") {
+		t.Errorf("graph.txt = %q", data)
+	}
+	if code, _, _ := run("-o", filepath.Join(dir, "ontology.png")); code != 1 {
+		t.Errorf("graph -o ontology.png = %d, want 1", code)
+	}
+}
@@ -0,0 +1,93 @@
+package ontology
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// graphFormats maps the formats of GraphCommand to the extensions
+// WriteGraph takes them from.
+var graphFormats = map[string]string{
+	"mermaid":  ".mmd",
+	"markdown": ".md",
+	"dot":      ".dot",
+	"json":     ".json",
+}
+
+// GraphCommand prints the ontology graph from a terminal, for backends to
+// mount as a subcommand of their own binary, like cloud.ReviewCommand:
+//
+//	if len(os.Args) > 1 && os.Args[1] == "graph" {
+//		os.Exit(ont.NewGraphCommand(config).Run(os.Args[2:]))
+//	}
+//
+// Its usage is:
+//
+//	graph [--format mermaid|markdown|dot|json] [--lang locale] [-o path]
+//
+// The graph is printed as Mermaid by default. With -o it is written to path
+// instead, in the format of its extension unless --format is set.
+type GraphCommand struct {
+	Config *Config
+	Out    io.Writer
+	Err    io.Writer
+}
+
+// NewGraphCommand returns a graph command for config, using the terminal's
+// standard streams.
+func NewGraphCommand(config *Config) *GraphCommand {
+	return &GraphCommand{Config: config, Out: os.Stdout, Err: os.Stderr}
+}
+
+// Run runs the command with args and returns its exit code: 0 on success,
+// 1 on errors and 2 on usage mistakes.
+func (g *GraphCommand) Run(args []string) int {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.SetOutput(g.Err)
+	format := fs.String("format", "", "mermaid, markdown, dot or json")
+	lang := fs.String("lang", "", "locale of the labels, e.g. fr")
+	output := fs.String("o", "", "file to write instead of printing")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(g.Err, "graph: unexpected argument %q\n", fs.Arg(0))
+		return 2
+	}
+
+	ext, ok := graphFormats[*format]
+	if *format == "" {
+		ext, ok = ".mmd", true
+	}
+	if !ok {
+		fmt.Fprintf(g.Err, "graph: unknown format %q\n", *format)
+		return 2
+	}
+
+	config := g.Config
+	if *lang != "" {
+		config = config.Localized(*lang)
+	}
+	var err error
+	switch {
+	case *output != "" && *format == "":
+		err = config.WriteGraph(*output)
+	case *output != "":
+		var data []byte
+		if data, err = config.Graph().encode(ext); err == nil {
+			err = os.WriteFile(*output, data, 0644)
+		}
+	default:
+		var data []byte
+		if data, err = config.Graph().encode(ext); err == nil {
+			_, err = g.Out.Write(data)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(g.Err, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
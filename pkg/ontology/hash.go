@@ -22,8 +22,20 @@ func (c *Config) Hash() string {
 type normalizedConfig struct {
 	Name         string                     `json:"name"`
 	AccessGroups map[string]AccessGroup     `json:"accessGroups"`
-	Entities     map[string]Entity          `json:"entities"`
+	Entities     map[string]normalizedEntity `json:"entities"`
 	Functions    map[string]normalizedFunc  `json:"functions"`
+	Events       map[string]EventShape      `json:"events,omitempty"`
+}
+
+// normalizedEntity is a serializable representation of Entity for hashing.
+// Entities without a schema or relations hash as they did before entities
+// had them.
+type normalizedEntity struct {
+	Description  string            `json:"description"`
+	Descriptions map[string]string `json:"descriptions,omitempty"`
+	Key          string            `json:"key,omitempty"`
+	Schema       map[string]any    `json:"schema,omitempty"`
+	Relations    []Relation        `json:"relations,omitempty"`
 }
 
 // normalizedFunc is a serializable representation of Function for hashing.
@@ -31,9 +43,31 @@ type normalizedConfig struct {
 type normalizedFunc struct {
 	Description string         `json:"description"`
 	Access      []string       `json:"access"`
+	AccessRule  string         `json:"accessRule,omitempty"`
 	Entities    []string       `json:"entities,omitempty"`
 	Inputs      map[string]any `json:"inputs"`
 	Outputs     map[string]any `json:"outputs"`
+	Pipeline    *Pipeline      `json:"pipeline,omitempty"`
+	Owner       string         `json:"owner,omitempty"`
+	Team        string         `json:"team,omitempty"`
+	Runbook     string         `json:"runbook,omitempty"`
+	Stability   Stability      `json:"stability,omitempty"`
+	Internal    bool           `json:"internal,omitempty"`
+	Aliases     []string       `json:"aliases,omitempty"`
+	FieldReferences []FieldReference `json:"fieldReferences,omitempty"`
+	UsesUserContext bool `json:"usesUserContext,omitempty"`
+	UsesOrganizationContext bool `json:"usesOrganizationContext,omitempty"`
+	Version     int            `json:"version,omitempty"`
+	RateLimit   *RateLimit     `json:"rateLimit,omitempty"`
+	Headers     map[string]any `json:"headers,omitempty"`
+	Query       map[string]any `json:"query,omitempty"`
+	Deprecated  string         `json:"deprecated,omitempty"`
+	Fallback    *FallbackShape `json:"fallback,omitempty"`
+	Timeout     string         `json:"timeout,omitempty"`
+
+	OutputVersions map[string]any    `json:"outputVersions,omitempty"`
+	OutputProfile  string            `json:"outputProfile,omitempty"`
+	Descriptions   map[string]string `json:"descriptions,omitempty"`
 }
 
 // normalize creates a deterministic representation of the config for hashing.
@@ -41,8 +75,9 @@ func (c *Config) normalize() *normalizedConfig {
 	normalized := &normalizedConfig{
 		Name:         c.Name,
 		AccessGroups: make(map[string]AccessGroup),
-		Entities:     make(map[string]Entity),
+		Entities:     make(map[string]normalizedEntity),
 		Functions:    make(map[string]normalizedFunc),
+		Events:       c.eventShapes(),
 	}
 
 	// Copy access groups
@@ -52,7 +87,11 @@ func (c *Config) normalize() *normalizedConfig {
 
 	// Copy entities
 	for k, v := range c.Entities {
-		normalized.Entities[k] = v
+		entity := normalizedEntity{Description: v.Description, Descriptions: v.Descriptions, Key: v.Key, Relations: v.Relations}
+		if v.Schema != nil {
+			entity.Schema = c.JSONSchemaFor(v.Schema)
+		}
+		normalized.Entities[k] = entity
 	}
 
 	// Copy and normalize functions
@@ -60,9 +99,35 @@ func (c *Config) normalize() *normalizedConfig {
 		fn := normalizedFunc{
 			Description: v.Description,
 			Access:      sortedCopy(v.Access),
+			AccessRule:  v.AccessRule,
 			Entities:    sortedCopy(v.Entities),
-			Inputs:      v.Inputs.JSONSchema(),
-			Outputs:     v.Outputs.JSONSchema(),
+			Inputs:      c.JSONSchemaFor(v.Inputs),
+			Outputs:     c.JSONSchemaFor(v.Outputs),
+			Pipeline:    v.Pipeline,
+			Owner:       v.Owner,
+			Team:        v.Team,
+			Runbook:     v.Runbook,
+			Stability:   v.Stability,
+			Internal:    v.Internal,
+			Aliases:     sortedCopy(v.Aliases),
+			FieldReferences: v.FieldReferences,
+			UsesUserContext: v.UsesUserContext,
+			UsesOrganizationContext: v.UsesOrganizationContext,
+			Version:     v.Version,
+			RateLimit:   v.RateLimit,
+			Deprecated:  v.Deprecated,
+			Fallback:    v.Fallback.shape(),
+			Timeout:     formatTimeout(v.Timeout),
+
+			OutputVersions: c.outputVersionSchemas(v),
+			OutputProfile:  v.OutputProfile,
+			Descriptions:   v.Descriptions,
+		}
+		if v.Headers != nil {
+			fn.Headers = c.JSONSchemaFor(v.Headers)
+		}
+		if v.Query != nil {
+			fn.Query = c.JSONSchemaFor(v.Query)
 		}
 		normalized.Functions[k] = fn
 	}
@@ -82,9 +147,40 @@ func hashFunction(f Function) string {
 	normalized := normalizedFunc{
 		Description: f.Description,
 		Access:      sortedCopy(f.Access),
+		AccessRule:  f.AccessRule,
 		Entities:    sortedCopy(f.Entities),
 		Inputs:      f.Inputs.JSONSchema(),
 		Outputs:     f.Outputs.JSONSchema(),
+		Pipeline:    f.Pipeline,
+		Owner:       f.Owner,
+		Team:        f.Team,
+		Runbook:     f.Runbook,
+		Stability:   f.Stability,
+		Internal:    f.Internal,
+		Aliases:     sortedCopy(f.Aliases),
+		FieldReferences: f.FieldReferences,
+		UsesUserContext: f.UsesUserContext,
+		UsesOrganizationContext: f.UsesOrganizationContext,
+		Version:     f.Version,
+		RateLimit:   f.RateLimit,
+		Deprecated:  f.Deprecated,
+		Fallback:    f.Fallback.shape(),
+		Timeout:     formatTimeout(f.Timeout),
+
+		OutputProfile: f.OutputProfile,
+		Descriptions:  f.Descriptions,
+	}
+	for name, version := range f.OutputVersions {
+		if normalized.OutputVersions == nil {
+			normalized.OutputVersions = make(map[string]any)
+		}
+		normalized.OutputVersions[name] = version.Outputs.JSONSchema()
+	}
+	if f.Headers != nil {
+		normalized.Headers = f.Headers.JSONSchema()
+	}
+	if f.Query != nil {
+		normalized.Query = f.Query.JSONSchema()
 	}
 	return hashComponent(normalized)
 }
@@ -157,3 +157,43 @@ func TestAccessSortingForHash(t *testing.T) {
 		t.Errorf("Hashes should be equal regardless of access order: %s vs %s", hash1, hash2)
 	}
 }
+
+func TestOwnershipInLock(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+		Entities:     map[string]Entity{},
+		Functions: map[string]Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"admin"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Object(map[string]Schema{"name": String()}),
+			},
+		},
+	}
+	before := config.Hash()
+
+	fn := config.Functions["getUser"]
+	fn.Owner = "jane@example.com"
+	fn.Team = "identity"
+	fn.Runbook = "https://runbooks.example.com/get-user"
+	config.Functions["getUser"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if config.Hash() == before {
+		t.Error("hash should change when ownership changes")
+	}
+	shape := config.ExtractSnapshot().Functions["getUser"]
+	if shape.Owner != fn.Owner || shape.Team != fn.Team || shape.Runbook != fn.Runbook {
+		t.Errorf("snapshot should record ownership, got %+v", shape)
+	}
+
+	fn.Runbook = "wiki/get-user"
+	config.Functions["getUser"] = fn
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for a runbook that isn't an http(s) URL")
+	}
+}
@@ -0,0 +1,694 @@
+package ontology
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// importIgnored are keywords ParseJSONSchema accepts without effect: they
+// identify or document a schema but don't constrain values.
+var importIgnored = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$anchor": true,
+	"title": true, "default": true,
+	"readOnly": true, "writeOnly": true, "contentMediaType": true,
+	"x-ont-expression": true, "x-ont-refinements": true,
+}
+
+// ParseJSONSchema converts a draft 2020-12 JSON Schema document into a
+// Schema, so existing schema files (OpenAPI components, contracts from
+// other services) can be used as function inputs and outputs:
+//
+//	data, _ := os.ReadFile("schemas/order.json")
+//	order, err := ont.ParseJSONSchema(data)
+//
+// It reads the keywords ont itself emits: type (including lists of types,
+// where "null" makes the schema Nullable), properties, required,
+// additionalProperties: false, patternProperties, items, enum, const, the
+// string, number, array and property count bounds, uniqueItems, format
+// (the built-in string formats, those added with RegisterFormat, and
+// int64; others are annotations and ignored), contentEncoding: base64,
+// oneOf (a discriminator becomes a DiscriminatedUnion), allOf, anyOf with a
+// null alternative, description, examples, deprecated, x-ont-sensitive,
+// x-ont-rules and x-ont-uniqueBy.
+// References to "#/$defs/..." or "#/definitions/..." are inlined; recursive
+// references aren't supported, so model those with Config.Schemas and Ref.
+//
+// Any other keyword that constrains values (not, if, dependentRequired, ...)
+// is an error rather than being dropped, since the result would accept
+// values the document rejects. Errors name the JSON pointer of the subschema.
+func ParseJSONSchema(data []byte) (Schema, error) {
+	doc, err := DecodeJSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	p := &schemaImporter{defs: make(map[string]any)}
+	if root, ok := doc.(map[string]any); ok {
+		for _, keyword := range []string{"$defs", "definitions"} {
+			defs, _ := root[keyword].(map[string]any)
+			for name, def := range defs {
+				p.defs["#/"+keyword+"/"+name] = def
+			}
+		}
+	}
+	return p.parse(doc, "")
+}
+
+// schemaImporter converts JSON Schema documents; defs holds the local
+// definitions by reference.
+type schemaImporter struct {
+	defs     map[string]any
+	visiting []string
+}
+
+// importNode is a subschema being converted. It records the keywords read
+// so the rest can be reported as unsupported.
+type importNode struct {
+	raw  map[string]any
+	path string
+	used map[string]bool
+}
+
+func (n *importNode) get(keyword string) (any, bool) {
+	v, ok := n.raw[keyword]
+	if ok {
+		n.used[keyword] = true
+	}
+	return v, ok
+}
+
+func (n *importNode) errorf(format string, args ...any) error {
+	path := n.path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Errorf("at %s: %s", path, fmt.Sprintf(format, args...))
+}
+
+// unsupported reports the first keyword that wasn't read.
+func (n *importNode) unsupported() error {
+	for _, keyword := range sortedKeys(n.raw) {
+		if !n.used[keyword] && !importIgnored[keyword] && keyword != "$defs" && keyword != "definitions" {
+			return n.errorf("unsupported keyword '%s'", keyword)
+		}
+	}
+	return nil
+}
+
+func (p *schemaImporter) parse(doc any, path string) (Schema, error) {
+	switch v := doc.(type) {
+	case bool:
+		if v {
+			return Any(), nil
+		}
+		return nil, fmt.Errorf("at %s: schema 'false' accepts no values", orRoot(path))
+	case map[string]any:
+		n := &importNode{raw: v, path: path, used: make(map[string]bool)}
+		schema, err := p.parseNode(n)
+		if err != nil {
+			return nil, err
+		}
+		if schema, err = p.annotate(n, schema); err != nil {
+			return nil, err
+		}
+		if err := n.unsupported(); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	default:
+		return nil, fmt.Errorf("at %s: expected a schema object, got %T", orRoot(path), doc)
+	}
+}
+
+func orRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// annotate copies the description, examples, deprecation and sensitivity
+// onto schema.
+func (p *schemaImporter) annotate(n *importNode, schema Schema) (Schema, error) {
+	a, ok := schema.(interface {
+		setDescription(string)
+		addExamples([]any)
+		setDeprecated(string)
+		setSensitive()
+	})
+	if !ok {
+		return schema, nil
+	}
+	if v, ok := n.get("description"); ok {
+		text, ok := v.(string)
+		if !ok {
+			return nil, n.errorf("description must be a string")
+		}
+		a.setDescription(text)
+	}
+	if v, ok := n.get("examples"); ok {
+		examples, ok := v.([]any)
+		if !ok {
+			return nil, n.errorf("examples must be an array")
+		}
+		a.addExamples(examples)
+	}
+	message, _ := n.get("x-ont-deprecated")
+	if v, ok := n.get("deprecated"); ok && v == true {
+		text, _ := message.(string)
+		a.setDeprecated(text)
+	}
+	if v, ok := n.get("x-ont-sensitive"); ok && v == true {
+		a.setSensitive()
+	}
+	return schema, nil
+}
+
+func (p *schemaImporter) parseNode(n *importNode) (Schema, error) {
+	if ref, ok := n.get("$ref"); ok {
+		return p.parseRef(n, ref)
+	}
+	if v, ok := n.get("oneOf"); ok {
+		n.get("type") // ont emits "type": "object" beside oneOf for MCP
+		return p.parseOneOf(n, v)
+	}
+	if v, ok := n.get("anyOf"); ok {
+		return p.parseAnyOf(n, v)
+	}
+	if v, ok := n.get("allOf"); ok {
+		n.get("type") // ont emits "type": "object" beside allOf of objects
+		schemas, err := p.alternatives(n, "allOf", v)
+		if err != nil {
+			return nil, err
+		}
+		return AllOf(schemas...), nil
+	}
+
+	types, err := n.types()
+	if err != nil {
+		return nil, err
+	}
+	nullable := false
+	for i, t := range types {
+		if t == "null" {
+			nullable = true
+			types = append(types[:i:i], types[i+1:]...)
+			break
+		}
+	}
+
+	var schema Schema
+	switch len(types) {
+	case 0:
+		schema, err = p.parseUntyped(n)
+	case 1:
+		schema, err = p.parseType(n, types[0])
+	default:
+		alternatives := make([]Schema, len(types))
+		for i, t := range types {
+			if alternatives[i], err = p.parseType(n, t); err != nil {
+				return nil, err
+			}
+		}
+		schema = OneOf(alternatives...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if nullable {
+		if len(types) == 0 {
+			return nil, n.errorf("type 'null' alone is not supported")
+		}
+		schema = Nullable(schema)
+	}
+	return schema, nil
+}
+
+// types returns the names in the type keyword.
+func (n *importNode) types() ([]string, error) {
+	v, ok := n.get("type")
+	if !ok {
+		return nil, nil
+	}
+	switch t := v.(type) {
+	case string:
+		return []string{t}, nil
+	case []any:
+		types := make([]string, len(t))
+		for i, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return nil, n.errorf("type must be a string or an array of strings")
+			}
+			types[i] = name
+		}
+		return types, nil
+	}
+	return nil, n.errorf("type must be a string or an array of strings")
+}
+
+// parseUntyped infers the type of a schema without a type keyword from its
+// other keywords.
+func (p *schemaImporter) parseUntyped(n *importNode) (Schema, error) {
+	for _, keyword := range []string{"properties", "required", "additionalProperties"} {
+		if _, ok := n.raw[keyword]; ok {
+			return p.parseType(n, "object")
+		}
+	}
+	if _, ok := n.raw["items"]; ok {
+		return p.parseType(n, "array")
+	}
+	values, err := n.enumValues()
+	if err != nil || values == nil {
+		return Any(), err
+	}
+	// Infer the type from the allowed values
+	kind := ""
+	for _, value := range values {
+		switch value.(type) {
+		case string:
+			kind = mergeKind(kind, "string")
+		case float64, int64:
+			kind = mergeKind(kind, "number")
+		default:
+			kind = "mixed"
+		}
+	}
+	if kind == "mixed" || kind == "" {
+		return nil, n.errorf("enum values must all be strings or all be numbers")
+	}
+	return p.parseType(n, kind)
+}
+
+func mergeKind(kind, next string) string {
+	if kind == "" || kind == next {
+		return next
+	}
+	return "mixed"
+}
+
+func (p *schemaImporter) parseType(n *importNode, typ string) (Schema, error) {
+	switch typ {
+	case "object":
+		return p.parseObject(n)
+	case "array":
+		return p.parseArray(n)
+	case "string":
+		if v, ok := n.get("contentEncoding"); ok {
+			if v != "base64" {
+				return nil, n.errorf("unsupported contentEncoding '%v'", v)
+			}
+			return p.parseBytes(n)
+		}
+		return p.parseString(n)
+	case "number", "integer":
+		return p.parseNumber(n, typ == "integer")
+	case "boolean":
+		values, err := n.enumValues()
+		if err != nil {
+			return nil, err
+		}
+		if values != nil {
+			return nil, n.errorf("enum and const are not supported on booleans")
+		}
+		return Boolean(), nil
+	}
+	return nil, n.errorf("unknown type '%s'", typ)
+}
+
+func (p *schemaImporter) parseObject(n *importNode) (Schema, error) {
+	props := make(map[string]Schema)
+	if v, ok := n.get("properties"); ok {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, n.errorf("properties must be an object")
+		}
+		for _, name := range sortedKeys(m) {
+			prop, err := p.parse(m[name], pointer(n.path+"/properties", name))
+			if err != nil {
+				return nil, err
+			}
+			props[name] = prop
+		}
+	}
+
+	var required []string
+	if v, ok := n.get("required"); ok {
+		names, ok := v.([]any)
+		if !ok {
+			return nil, n.errorf("required must be an array of strings")
+		}
+		for _, item := range names {
+			name, ok := item.(string)
+			if !ok {
+				return nil, n.errorf("required must be an array of strings")
+			}
+			if _, declared := props[name]; !declared {
+				props[name] = Any()
+			}
+			required = append(required, name)
+		}
+	}
+
+	obj := Object(props)
+	obj.required = append([]string{}, required...)
+
+	if v, ok := n.get("additionalProperties"); ok {
+		switch v {
+		case false:
+			obj.strict = true
+		case true:
+		default:
+			if m, ok := v.(map[string]any); !ok || len(m) > 0 {
+				return nil, n.errorf("additionalProperties must be true or false")
+			}
+		}
+	}
+
+	var err error
+	if obj.minProperties, err = n.intKeyword("minProperties"); err != nil {
+		return nil, err
+	}
+	if obj.maxProperties, err = n.intKeyword("maxProperties"); err != nil {
+		return nil, err
+	}
+	if v, ok := n.get("patternProperties"); ok {
+		patterns, ok := v.(map[string]any)
+		if !ok {
+			return nil, n.errorf("patternProperties must be an object")
+		}
+		for _, pattern := range sortedKeys(patterns) {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, n.errorf("invalid pattern '%s': %v", pattern, err)
+			}
+			schema, err := p.parse(patterns[pattern], pointer(n.path+"/patternProperties", pattern))
+			if err != nil {
+				return nil, err
+			}
+			obj.patterns = append(obj.patterns, patternProperty{re, schema})
+		}
+	}
+
+	if v, ok := n.get("x-ont-rules"); ok {
+		rules, ok := v.([]any)
+		if !ok {
+			return nil, n.errorf("x-ont-rules must be an array")
+		}
+		for _, item := range rules {
+			rule, _ := item.(map[string]any)
+			expr, _ := rule["expr"].(string)
+			message, _ := rule["message"].(string)
+			compiled, err := ParseExpr(expr)
+			if err != nil {
+				return nil, n.errorf("x-ont-rules: %v", err)
+			}
+			obj.rules = append(obj.rules, objectRule{ExprRule{Expr: expr, Message: message}, compiled})
+		}
+	}
+	return obj, nil
+}
+
+func (p *schemaImporter) parseArray(n *importNode) (Schema, error) {
+	items := Schema(Any())
+	if v, ok := n.get("items"); ok {
+		var err error
+		if items, err = p.parse(v, n.path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+	arr := Array(items)
+	var err error
+	if arr.minItems, err = n.intKeyword("minItems"); err != nil {
+		return nil, err
+	}
+	if arr.maxItems, err = n.intKeyword("maxItems"); err != nil {
+		return nil, err
+	}
+	if v, ok := n.get("uniqueItems"); ok {
+		unique, ok := v.(bool)
+		if !ok {
+			return nil, n.errorf("uniqueItems must be a boolean")
+		}
+		arr.unique = unique
+	}
+	if v, ok := n.get("x-ont-uniqueBy"); ok {
+		key, ok := v.(string)
+		if !ok || !arr.unique {
+			return nil, n.errorf("x-ont-uniqueBy needs a string and uniqueItems: true")
+		}
+		arr.uniqueBy = key
+	}
+	return arr, nil
+}
+
+func (p *schemaImporter) parseString(n *importNode) (Schema, error) {
+	s := String()
+	var err error
+	if s.minLength, err = n.intKeyword("minLength"); err != nil {
+		return nil, err
+	}
+	if s.maxLength, err = n.intKeyword("maxLength"); err != nil {
+		return nil, err
+	}
+	if v, ok := n.get("pattern"); ok {
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, n.errorf("pattern must be a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, n.errorf("invalid pattern: %v", err)
+		}
+		s.pattern = re
+	}
+	if v, ok := n.get("format"); ok {
+		if name, ok := v.(string); ok && knownFormat(name) {
+			s.format = name
+		}
+	}
+
+	values, err := n.enumValues()
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			return nil, n.errorf("enum values of a string must be strings")
+		}
+		s.enum = append(s.enum, str)
+	}
+	return s, nil
+}
+
+func (p *schemaImporter) parseBytes(n *importNode) (Schema, error) {
+	b := Bytes()
+	n.get("maxLength") // implied by x-ont-maxBytes when ont emitted it
+	max, err := n.intKeyword("x-ont-maxBytes")
+	if err != nil {
+		return nil, err
+	}
+	b.maxBytes = max
+	return b, nil
+}
+
+func (p *schemaImporter) parseNumber(n *importNode, integer bool) (Schema, error) {
+	num := Number()
+	if integer {
+		num = Integer()
+		if v, ok := n.get("format"); ok && v == "int64" {
+			num = Int64()
+		}
+	} else {
+		n.get("format") // float and double are annotations
+	}
+
+	for keyword, dst := range map[string]**float64{
+		"minimum":          &num.minimum,
+		"maximum":          &num.maximum,
+		"exclusiveMinimum": &num.exclusiveMinimum,
+		"exclusiveMaximum": &num.exclusiveMaximum,
+		"multipleOf":       &num.multipleOf,
+	} {
+		v, ok := n.get(keyword)
+		if !ok {
+			continue
+		}
+		f, ok := jsonNumber(v)
+		if !ok {
+			return nil, n.errorf("%s must be a number", keyword)
+		}
+		*dst = &f
+	}
+
+	values, err := n.enumValues()
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		f, ok := jsonNumber(value)
+		if !ok {
+			return nil, n.errorf("enum values of a number must be numbers")
+		}
+		num.enum = append(num.enum, f)
+	}
+	return num, nil
+}
+
+// enumValues returns the values allowed by enum or const, or nil if
+// neither is set.
+func (n *importNode) enumValues() ([]any, error) {
+	if v, ok := n.get("const"); ok {
+		return []any{v}, nil
+	}
+	v, ok := n.get("enum")
+	if !ok {
+		return nil, nil
+	}
+	values, ok := v.([]any)
+	if !ok || len(values) == 0 {
+		return nil, n.errorf("enum must be a non-empty array")
+	}
+	return values, nil
+}
+
+// intKeyword reads a non-negative integer keyword such as minLength.
+func (n *importNode) intKeyword(keyword string) (*int, error) {
+	v, ok := n.get(keyword)
+	if !ok {
+		return nil, nil
+	}
+	f, ok := jsonNumber(v)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, n.errorf("%s must be a non-negative integer", keyword)
+	}
+	i := int(f)
+	return &i, nil
+}
+
+func jsonNumber(v any) (float64, bool) {
+	switch f := v.(type) {
+	case float64:
+		return f, true
+	case int64:
+		return float64(f), true
+	}
+	return 0, false
+}
+
+func (p *schemaImporter) parseRef(n *importNode, v any) (Schema, error) {
+	ref, ok := v.(string)
+	if !ok {
+		return nil, n.errorf("$ref must be a string")
+	}
+	def, ok := p.defs[ref]
+	if !ok {
+		return nil, n.errorf("unsupported $ref '%s': only local \"#/$defs/...\" references are supported", ref)
+	}
+	for _, visiting := range p.visiting {
+		if visiting == ref {
+			return nil, n.errorf("recursive $ref '%s' is not supported; register it in Config.Schemas and use Ref", ref)
+		}
+	}
+	p.visiting = append(p.visiting, ref)
+	defer func() { p.visiting = p.visiting[:len(p.visiting)-1] }()
+	return p.parse(def, strings.TrimPrefix(ref, "#"))
+}
+
+// alternatives parses the subschemas of a oneOf or allOf keyword.
+func (p *schemaImporter) alternatives(n *importNode, keyword string, v any) ([]Schema, error) {
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return nil, n.errorf("%s must be a non-empty array", keyword)
+	}
+	schemas := make([]Schema, len(items))
+	for i, item := range items {
+		schema, err := p.parse(item, fmt.Sprintf("%s/%s/%d", n.path, keyword, i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = schema
+	}
+	return schemas, nil
+}
+
+func (p *schemaImporter) parseOneOf(n *importNode, v any) (Schema, error) {
+	schemas, err := p.alternatives(n, "oneOf", v)
+	if err != nil {
+		return nil, err
+	}
+	disc, ok := n.get("discriminator")
+	if !ok {
+		return OneOf(schemas...), nil
+	}
+	m, _ := disc.(map[string]any)
+	property, _ := m["propertyName"].(string)
+	if property == "" {
+		return nil, n.errorf("discriminator must have a propertyName")
+	}
+
+	// Each variant pins the discriminator with a const
+	variants := make(map[string]*ObjectSchema, len(schemas))
+	for i, schema := range schemas {
+		obj, ok := schema.(*ObjectSchema)
+		tag, isString := obj.tagValue(property)
+		if !ok || !isString {
+			return nil, n.errorf("oneOf/%d: discriminated variants must be objects with a string const '%s'", i, property)
+		}
+		if _, dup := variants[tag]; dup {
+			return nil, n.errorf("oneOf/%d: duplicate discriminator value '%s'", i, tag)
+		}
+		delete(obj.properties, property)
+		obj.required = removeString(obj.required, property)
+		variants[tag] = obj
+	}
+	return DiscriminatedUnion(property, variants), nil
+}
+
+// tagValue returns the single allowed value of a string property.
+func (o *ObjectSchema) tagValue(name string) (string, bool) {
+	if o == nil {
+		return "", false
+	}
+	s, ok := o.properties[name].(*StringSchema)
+	if !ok || len(s.enum) != 1 {
+		return "", false
+	}
+	return s.enum[0], true
+}
+
+func removeString(list []string, s string) []string {
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// parseAnyOf supports the anyOf ont emits for Nullable: a schema and
+// {"type": "null"}.
+func (p *schemaImporter) parseAnyOf(n *importNode, v any) (Schema, error) {
+	items, _ := v.([]any)
+	var inner any
+	innerIndex, nulls := -1, 0
+	for i, item := range items {
+		if m, ok := item.(map[string]any); ok && len(m) == 1 && m["type"] == "null" {
+			nulls++
+			continue
+		}
+		inner, innerIndex = item, i
+	}
+	if nulls != 1 || len(items) != 2 {
+		return nil, n.errorf("anyOf is only supported as a schema or null; use oneOf for alternatives")
+	}
+	schema, err := p.parse(inner, fmt.Sprintf("%s/anyOf/%d", n.path, innerIndex))
+	if err != nil {
+		return nil, err
+	}
+	return Nullable(schema), nil
+}
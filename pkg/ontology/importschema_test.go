@@ -0,0 +1,101 @@
+package ontology
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONSchemaRoundTrip(t *testing.T) {
+	original := Object(map[string]Schema{
+		"id":      String().UUID(),
+		"email":   String().Email().Describe("Contact address"),
+		"name":    String().Min(1).Max(100).Pattern("^[A-Z]"),
+		"status":  String().Enum("active", "disabled"),
+		"age":     Nullable(Integer().Min(0)),
+		"balance": Int64(),
+		"score":   Number().ExclusiveMin(0).MultipleOf(0.5),
+		"tags":    Array(String()).MaxItems(10),
+		"avatar":  Bytes().MaxBytes(1024),
+		"payment": DiscriminatedUnion("type", map[string]*ObjectSchema{
+			"card": Object(map[string]Schema{"last4": String()}),
+			"bank": Object(map[string]Schema{"iban": String()}).Strict(),
+		}),
+		"start": String().Date(),
+		"end":   String().Date(),
+		"extra": Any(),
+	}).Optional("age", "extra").Rule("start <= end", "start must not be after end").Strict()
+	original.Example(map[string]any{"name": "Ada"})
+
+	want, _ := json.Marshal(original.JSONSchema())
+	imported, err := ParseJSONSchema(want)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+	got, _ := json.Marshal(imported.JSONSchema())
+	if string(got) != string(want) {
+		t.Errorf("round trip changed the schema:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestParseJSONSchema(t *testing.T) {
+	schema, err := ParseJSONSchema([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Order",
+		"type": "object",
+		"properties": {
+			"customer": {"$ref": "#/$defs/Customer"},
+			"note": {"type": ["string", "null"], "maxLength": 10},
+			"priority": {"enum": [1, 2, 3]},
+			"kind": {"const": "order"}
+		},
+		"required": ["customer", "kind"],
+		"$defs": {
+			"Customer": {"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+
+	valid := map[string]any{"customer": map[string]any{"id": 1.0}, "kind": "order", "note": nil, "priority": 2.0}
+	if err := schema.Validate(valid); err != nil {
+		t.Errorf("valid input rejected: %v", err)
+	}
+	for name, input := range map[string]map[string]any{
+		"ref":      {"customer": map[string]any{"id": "x"}, "kind": "order"},
+		"nullable": {"customer": map[string]any{"id": 1.0}, "kind": "order", "note": "far too long a note"},
+		"enum":     {"customer": map[string]any{"id": 1.0}, "kind": "order", "priority": 4.0},
+		"const":    {"customer": map[string]any{"id": 1.0}, "kind": "refund"},
+		"required": {"kind": "order"},
+	} {
+		if err := schema.Validate(input); err == nil {
+			t.Errorf("%s: invalid input accepted", name)
+		}
+	}
+}
+
+func TestParseJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"unsupported keyword", `{"type": "object", "properties": {"a": {"not": {}}}}`, "at /properties/a: unsupported keyword 'not'"},
+		{"remote ref", `{"$ref": "https://example.com/a.json"}`, "unsupported $ref"},
+		{"recursive ref", `{"$ref": "#/$defs/Node", "$defs": {"Node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/Node"}}}}}`, "recursive $ref"},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, "anyOf is only supported"},
+		{"additionalProperties schema", `{"type": "object", "additionalProperties": {"type": "string"}}`, "additionalProperties must be true or false"},
+		{"false", `{"type": "object", "properties": {"a": false}}`, "at /properties/a: schema 'false'"},
+		{"mixed enum", `{"enum": ["a", 1]}`, "enum values must all be strings or all be numbers"},
+		{"invalid JSON", `{`, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSONSchema([]byte(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
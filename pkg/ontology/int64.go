@@ -0,0 +1,183 @@
+package ontology
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// maxExactFloat is the largest integer magnitude float64 holds exactly (2^53).
+const maxExactFloat = 1 << 53
+
+// int64Value converts an integer in any Go or JSON representation to int64.
+// Floats are only accepted within ±2^53, since larger ones may already have
+// been rounded.
+func int64Value(data any) (int64, error) {
+	switch v := data.(type) {
+	case int:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return 0, fmt.Errorf("integer %d overflows int64", v)
+		}
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("integer %d overflows int64", v)
+		}
+		return int64(v), nil
+	case json.Number:
+		i, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected 64-bit integer, got %s", v)
+		}
+		return i, nil
+	case float32:
+		return int64Value(float64(v))
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("expected integer, got %v", v)
+		}
+		if math.Abs(v) > maxExactFloat {
+			return 0, fmt.Errorf("integer %v may have lost precision as a float; decode JSON with ont.DecodeJSON", v)
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("expected integer, got %T", data)
+}
+
+// DecodeJSON decodes a JSON value like encoding/json into any, except that
+// integers beyond ±2^53, which float64 can't hold exactly, decode as int64.
+// Servers use it for request bodies, then KeepInt64s, so Int64 fields keep
+// their precision.
+func DecodeJSON(r io.Reader) (any, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return preciseNumbers(v), nil
+}
+
+// preciseNumbers replaces json.Number values in decoded JSON with float64,
+// or int64 for integers float64 would round.
+func preciseNumbers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			t[k] = preciseNumbers(item)
+		}
+		return t
+	case []any:
+		for i, item := range t {
+			t[i] = preciseNumbers(item)
+		}
+		return t
+	case json.Number:
+		if i, err := strconv.ParseInt(string(t), 10, 64); err == nil && (i > maxExactFloat || i < -maxExactFloat) {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	}
+	return v
+}
+
+// KeepInt64s converts the int64 values in data, as decoded by DecodeJSON or
+// a codec, back to float64 except where schema expects an Int64, so other
+// number fields get float64 as with encoding/json. A value stays int64 if
+// any alternative of a union at its path is Int64. Maps and slices in data
+// are modified in place.
+func KeepInt64s(schema Schema, data any) any {
+	return keepInt64s(flattenAlternatives(schema, nil), data)
+}
+
+func keepInt64s(schemas []Schema, data any) any {
+	switch v := data.(type) {
+	case int64:
+		for _, s := range schemas {
+			if n, ok := s.(*NumberSchema); ok && n.isInt64 {
+				return v
+			}
+		}
+		return float64(v)
+	case map[string]any:
+		for key, item := range v {
+			var props []Schema
+			for _, s := range schemas {
+				if obj, ok := s.(*ObjectSchema); ok {
+					if prop, ok := obj.properties[key]; ok {
+						props = flattenAlternatives(prop, props)
+					}
+				}
+			}
+			v[key] = keepInt64s(props, item)
+		}
+	case []any:
+		var items []Schema
+		for _, s := range schemas {
+			if arr, ok := s.(*ArraySchema); ok {
+				items = flattenAlternatives(arr.items, items)
+			}
+		}
+		for i, item := range v {
+			v[i] = keepInt64s(items, item)
+		}
+	}
+	return data
+}
+
+// flattenAlternatives appends the schemas a value of schema may match,
+// looking through nullables, references and unions.
+func flattenAlternatives(schema Schema, into []Schema) []Schema {
+	switch s := schema.(type) {
+	case nil:
+		return into
+	case *NullableSchema:
+		return flattenAlternatives(s.inner, into)
+	case *RefSchema:
+		if s.target == nil {
+			return into
+		}
+		return flattenAlternatives(s.target, into)
+	case *OneOfSchema:
+		for _, alt := range s.alternatives {
+			into = flattenAlternatives(alt, into)
+		}
+		return into
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			into = flattenAlternatives(part, into)
+		}
+		return into
+	case *DiscriminatedUnionSchema:
+		for _, tag := range sortedKeys(s.variants) {
+			into = flattenAlternatives(s.variants[tag], into)
+		}
+		return into
+	}
+	return append(into, schema)
+}
+
+// unmarshalPrecise is json.Unmarshal into any with DecodeJSON's number handling.
+func unmarshalPrecise(data []byte) (any, error) {
+	return DecodeJSON(bytes.NewReader(data))
+}
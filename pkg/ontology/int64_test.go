@@ -0,0 +1,134 @@
+package ontology
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestInt64SchemaValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *NumberSchema
+		input   any
+		wantErr bool
+	}{
+		{"int64 above 2^53", Int64(), int64(9007199254740993), false},
+		{"json.Number", Int64(), json.Number("9223372036854775807"), false},
+		{"small float", Int64(), float64(42), false},
+		{"uint64", Int64(), uint64(7), false},
+		{"uint64 overflow", Int64(), uint64(1 << 63), true},
+		{"fractional float", Int64(), 1.5, true},
+		{"float beyond 2^53", Int64(), float64(1 << 60), true},
+		{"json.Number out of range", Int64(), json.Number("9223372036854775808"), true},
+		{"json.Number fraction", Int64(), json.Number("1.5"), true},
+		{"string", Int64(), "42", true},
+		{"within minimum", Int64().Min(10), int64(10), false},
+		{"below minimum", Int64().Min(10), int64(9), true},
+		{"multiple of", Int64().MultipleOf(3), int64(1<<62 + 2), false},
+		{"not a multiple of", Int64().MultipleOf(3), int64(1<<62 + 1), true},
+		{"number accepts json.Number", Number(), json.Number("1.5"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInt64JSONSchema(t *testing.T) {
+	schema := Int64().JSONSchema()
+	if schema["type"] != "integer" || schema["format"] != "int64" {
+		t.Errorf("unexpected JSON Schema: %v", schema)
+	}
+	if _, ok := Integer().JSONSchema()["format"]; ok {
+		t.Error("Integer should not have a format")
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	v, err := DecodeJSON(strings.NewReader(`{"id": 9007199254740993, "n": 42, "x": 1.5, "list": [-9223372036854775808]}`))
+	if err != nil {
+		t.Fatalf("DecodeJSON failed: %v", err)
+	}
+	m := v.(map[string]any)
+	if m["id"] != int64(9007199254740993) {
+		t.Errorf("expected exact int64 id, got %v (%T)", m["id"], m["id"])
+	}
+	if m["n"] != float64(42) || m["x"] != 1.5 {
+		t.Errorf("expected float64 for small numbers, got %T and %T", m["n"], m["x"])
+	}
+	if list := m["list"].([]any); list[0] != int64(-9223372036854775808) {
+		t.Errorf("expected exact int64 in array, got %v", list[0])
+	}
+
+	schema := Object(map[string]Schema{"id": Int64()})
+	if err := schema.Validate(v); err != nil {
+		t.Errorf("expected decoded input to validate: %v", err)
+	}
+
+	if _, err := DecodeJSON(strings.NewReader(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestInt64StructRoundTrip(t *testing.T) {
+	type row struct {
+		ID int64 `json:"id"`
+	}
+	schema := Object(map[string]Schema{"id": Int64()})
+	if err := schema.Validate(row{ID: 1<<62 + 1}); err != nil {
+		t.Errorf("expected struct with large int64 to validate: %v", err)
+	}
+	if got := toJSONValue(row{ID: 1<<62 + 1}).(map[string]any)["id"]; got != int64(1<<62+1) {
+		t.Errorf("expected exact int64 after conversion, got %v (%T)", got, got)
+	}
+}
+
+func TestCoerceInt64(t *testing.T) {
+	if got := Coerce(Int64(), "9007199254740993"); got != int64(9007199254740993) {
+		t.Errorf("expected exact int64, got %v (%T)", got, got)
+	}
+}
+
+func TestKeepInt64s(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"id":     Int64(),
+		"n":      Number(),
+		"ids":    Array(Int64()),
+		"counts": Array(Integer()),
+		"maybe":  Nullable(Int64()),
+		"either": OneOf(String(), Int64()),
+		"nested": Object(map[string]Schema{"n": Number()}),
+		"any":    Any(),
+	})
+	v, err := DecodeJSON(strings.NewReader(`{"id": 9007199254740993, "n": 9007199254740993, "ids": [9007199254740993], "counts": [9007199254740993], "maybe": 9007199254740993, "either": 9007199254740993, "nested": {"n": 9007199254740993}, "any": 9007199254740993, "extra": 9007199254740993}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := KeepInt64s(schema, v).(map[string]any)
+
+	for _, key := range []string{"id", "maybe", "either"} {
+		if m[key] != int64(9007199254740993) {
+			t.Errorf("%s = %v (%T), want int64", key, m[key], m[key])
+		}
+	}
+	if ids := m["ids"].([]any); ids[0] != int64(9007199254740993) {
+		t.Errorf("ids[0] = %v (%T), want int64", ids[0], ids[0])
+	}
+	for _, key := range []string{"n", "any", "extra"} {
+		if _, ok := m[key].(float64); !ok {
+			t.Errorf("%s = %v (%T), want float64", key, m[key], m[key])
+		}
+	}
+	if _, ok := m["counts"].([]any)[0].(float64); !ok {
+		t.Errorf("counts[0] = %T, want float64", m["counts"].([]any)[0])
+	}
+	if _, ok := m["nested"].(map[string]any)["n"].(float64); !ok {
+		t.Errorf("nested.n = %T, want float64", m["nested"].(map[string]any)["n"])
+	}
+}
@@ -0,0 +1,46 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPublicConfig(t *testing.T) {
+	config := graphConfig()
+	fn := config.Functions["sendMessage"]
+	fn.Internal = true
+	config.Functions["sendMessage"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	public := config.Public()
+	if _, ok := public.Functions["sendMessage"]; ok {
+		t.Error("Public should drop internal functions")
+	}
+	if _, ok := config.Functions["sendMessage"]; !ok {
+		t.Error("Public should not modify the config")
+	}
+	if got := public.Query(FunctionQuery{}); !reflect.DeepEqual(got, []string{"deleteCustomer", "getCustomer", "pipeline"}) {
+		t.Errorf("public Query = %v", got)
+	}
+
+	// The pipeline's call to the internal function is left out of the graph
+	for _, e := range public.Graph().Edges {
+		if e.To == "function:sendMessage" {
+			t.Errorf("public graph has edge to internal function: %v", e)
+		}
+	}
+}
+
+func TestInternalNotInMCP(t *testing.T) {
+	config := graphConfig()
+	fn := config.Functions["sendMessage"]
+	fn.Internal = true
+	fn.IncludeInMcpListTools = true
+	config.Functions["sendMessage"] = fn
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "internal functions can't be included in MCP tools") {
+		t.Errorf("expected MCP conflict error, got %v", err)
+	}
+}
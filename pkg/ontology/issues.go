@@ -0,0 +1,128 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateAll validates data like schema.Validate, but instead of stopping
+// at the first violation it collects all of them as ValidationErrors, each
+// with the JSON pointer of the value at fault. It returns nil if data is
+// valid.
+//
+// Objects, arrays, nullables, references and discriminated unions in JSON
+// form (map[string]any and []any) are checked field by field; other values,
+// such as structs, are checked as a whole. Object rules and refinements
+// only run once the fields they read are valid.
+func ValidateAll(schema Schema, data any) error {
+	var issues ValidationErrors
+	collectIssues(schema, data, "", &issues)
+	if len(issues) == 0 {
+		return nil
+	}
+	return issues
+}
+
+func collectIssues(schema Schema, data any, path string, issues *ValidationErrors) {
+	before := len(*issues)
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		if m, ok := data.(map[string]any); ok {
+			s.collectIssues(m, path, issues)
+			return
+		}
+	case *ArraySchema:
+		if arr, ok := data.([]any); ok && arr != nil {
+			issues.add(path, s.message(s.checkLength(len(arr))))
+			for i, item := range arr {
+				collectIssues(s.items, item, fmt.Sprintf("%s/%d", path, i), issues)
+			}
+			if len(*issues) == before {
+				issues.add(path, s.message(s.checkUnique(arr)))
+			}
+			if len(*issues) == before {
+				issues.add(path, s.refine(data))
+			}
+			return
+		}
+	case *NullableSchema:
+		if !isNull(data) {
+			collectIssues(s.inner, data, path, issues)
+		}
+		if len(*issues) == before {
+			issues.add(path, s.refine(data))
+		}
+		return
+	case *RefSchema:
+		if s.target != nil {
+			collectIssues(s.target, data, path, issues)
+			if len(*issues) == before {
+				issues.add(path, s.refine(data))
+			}
+			return
+		}
+	case *DiscriminatedUnionSchema:
+		if m, ok := data.(map[string]any); ok {
+			tag, _ := m[s.discriminator].(string)
+			if variant, ok := s.variants[tag]; ok {
+				variant.collectIssues(m, path, issues, s.discriminator)
+				if len(*issues) == before {
+					issues.add(path, s.refine(data))
+				}
+				return
+			}
+		}
+	}
+	issues.add(path, schema.Validate(data))
+}
+
+// collectIssues checks an object's fields one by one; strict objects also
+// accept the allowed extra keys.
+func (o *ObjectSchema) collectIssues(m map[string]any, path string, issues *ValidationErrors, allowed ...string) {
+	before := len(*issues)
+	if o.strict {
+		for _, key := range sortedKeys(m) {
+			if _, ok := o.properties[key]; !ok && !contains(allowed, key) && !o.matchesPattern(key) {
+				issues.add(pointer(path, key), o.message(fieldError("additionalProperties", key, "unknown field")))
+			}
+		}
+	}
+	for _, name := range sortedKeys(o.properties) {
+		val, ok := m[name]
+		if !ok {
+			if contains(o.required, name) {
+				issues.add(pointer(path, name), missingField(name, o.properties[name], "required field is missing"))
+			}
+			continue
+		}
+		collectIssues(o.properties[name], val, pointer(path, name), issues)
+	}
+	if o.minProperties != nil && len(m) < *o.minProperties {
+		issues.add(path, o.message(constraintError("minProperties", *o.minProperties, len(m), "object has %d properties, minimum is %d", len(m), *o.minProperties)))
+	}
+	if o.maxProperties != nil && len(m) > *o.maxProperties {
+		issues.add(path, o.message(constraintError("maxProperties", *o.maxProperties, len(m), "object has %d properties, maximum is %d", len(m), *o.maxProperties)))
+	}
+	for _, key := range sortedKeys(m) {
+		for _, p := range o.patterns {
+			if p.pattern.MatchString(key) {
+				collectIssues(p.schema, m[key], pointer(path, key), issues)
+			}
+		}
+	}
+	if len(*issues) == before {
+		issues.add(path, o.checkRules(m))
+	}
+}
+
+// add records err, if any, at path.
+func (e *ValidationErrors) add(path string, err error) {
+	if err != nil {
+		*e = append(*e, &ValidationError{Field: path, Message: err.Error()})
+	}
+}
+
+// pointer appends a property name to a JSON pointer, escaping it per RFC 6901.
+func pointer(path, name string) string {
+	return path + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
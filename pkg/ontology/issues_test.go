@@ -0,0 +1,87 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func usersSchema() Schema {
+	user := Object(map[string]Schema{
+		"name":  String().Min(1),
+		"email": String().Email(),
+		"age":   Integer().Min(0),
+	}).Optional("age")
+	return Object(map[string]Schema{
+		"users": Array(user),
+		"owner": Nullable(user),
+		"a/b":   Boolean(),
+	}).Optional("owner", "a/b")
+}
+
+func TestValidateAll(t *testing.T) {
+	err := ValidateAll(usersSchema(), map[string]any{
+		"users": []any{
+			map[string]any{"name": "Ann", "email": "ann@example.com"},
+			map[string]any{"name": "", "email": "nope", "age": -1.0},
+			map[string]any{"email": "bob@example.com"},
+		},
+		"owner": map[string]any{"name": "Cy", "email": "x"},
+		"a/b":   "yes",
+	})
+
+	var issues ValidationErrors
+	if !errors.As(err, &issues) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	var paths []string
+	for _, issue := range issues {
+		paths = append(paths, issue.Field)
+	}
+	want := []string{"/a~1b", "/owner/email", "/users/1/age", "/users/1/email", "/users/1/name", "/users/2/name"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("issue paths = %v, want %v", paths, want)
+	}
+	if issues[5].Message != "required field is missing" {
+		t.Errorf("missing field message = %q", issues[5].Message)
+	}
+}
+
+func TestValidateAllValid(t *testing.T) {
+	if err := ValidateAll(usersSchema(), map[string]any{"users": []any{}}); err != nil {
+		t.Errorf("expected valid, got %v", err)
+	}
+}
+
+func TestValidateAllRulesAfterFields(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"min": Number(),
+		"max": Number(),
+	}).Rule("min <= max", "min must not exceed max")
+
+	err := ValidateAll(schema, map[string]any{"min": 5.0, "max": 1.0})
+	if err == nil || err.Error() != "min must not exceed max" {
+		t.Errorf("expected rule issue at the root, got %v", err)
+	}
+
+	// Rules don't run on objects whose fields are invalid
+	err = ValidateAll(schema, map[string]any{"min": "5", "max": 1.0})
+	var issues ValidationErrors
+	if !errors.As(err, &issues) || len(issues) != 1 || issues[0].Field != "/min" {
+		t.Errorf("expected only the field issue, got %v", err)
+	}
+}
+
+func TestValidateAllUnion(t *testing.T) {
+	schema := DiscriminatedUnion("type", map[string]*ObjectSchema{
+		"card": Object(map[string]Schema{"number": String(), "cvc": String()}).Strict(),
+	})
+	err := ValidateAll(schema, map[string]any{"type": "card", "number": 4.0, "extra": true})
+	var issues ValidationErrors
+	if !errors.As(err, &issues) || len(issues) != 3 {
+		t.Fatalf("expected 3 issues, got %v", err)
+	}
+	if issues[0].Field != "/extra" || issues[1].Field != "/cvc" || issues[2].Field != "/number" {
+		t.Errorf("unexpected issues: %v", err)
+	}
+}
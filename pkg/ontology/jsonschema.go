@@ -24,6 +24,15 @@ func (c *Config) OntologyJSONSchema() ([]byte, error) {
 		"required": []string{"name", "accessGroups", "entities", "functions"},
 	}
 
+	// Named schemas referenced by function inputs and outputs
+	if named := c.NamedSchemas(); len(named) > 0 {
+		defs := make(map[string]any, len(named))
+		for name, s := range named {
+			defs[name] = s.JSONSchema()
+		}
+		schema["$defs"] = defs
+	}
+
 	return json.MarshalIndent(schema, "", "  ")
 }
 
@@ -123,8 +132,8 @@ func (c *Config) AllFunctionSchemas() map[string]FunctionSchemas {
 	result := make(map[string]FunctionSchemas)
 	for name, fn := range c.Functions {
 		result[name] = FunctionSchemas{
-			Input:  fn.Inputs.JSONSchema(),
-			Output: fn.Outputs.JSONSchema(),
+			Input:  c.JSONSchemaFor(fn.Inputs),
+			Output: c.JSONSchemaFor(fn.Outputs),
 		}
 	}
 	return result
@@ -0,0 +1,145 @@
+package ontology
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Localize returns the translation of description that best matches an
+// Accept-Language header value, e.g. "de-CH, de;q=0.9, en;q=0.8".
+// translations are keyed by locale ("de", "fr-CA"); a language tag also
+// matches the translation of its base language ("de-CH" uses "de"). It
+// returns description when nothing matches.
+func Localize(description string, translations map[string]string, acceptLanguage string) string {
+	if len(translations) == 0 || acceptLanguage == "" {
+		return description
+	}
+	for _, tag := range preferredLanguages(acceptLanguage) {
+		if tag == "*" {
+			return description
+		}
+		for locale, text := range translations {
+			if strings.EqualFold(locale, tag) {
+				return text
+			}
+		}
+		base, _, found := strings.Cut(tag, "-")
+		if !found {
+			continue
+		}
+		for locale, text := range translations {
+			if strings.EqualFold(locale, base) {
+				return text
+			}
+		}
+	}
+	return description
+}
+
+// preferredLanguages returns the language tags of an Accept-Language value
+// by descending quality, dropping those with q=0.
+func preferredLanguages(acceptLanguage string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if tag = strings.TrimSpace(tag); tag != "" && q > 0 {
+			tags = append(tags, weighted{tag, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
+
+// Localized returns a copy of c whose function, entity and access group
+// descriptions are translated for acceptLanguage (see Localize). The
+// functions are otherwise shared with c.
+func (c *Config) Localized(acceptLanguage string) *Config {
+	localized := *c
+	localized.AccessGroups = make(map[string]AccessGroup, len(c.AccessGroups))
+	for name, group := range c.AccessGroups {
+		group.Description = Localize(group.Description, group.Descriptions, acceptLanguage)
+		localized.AccessGroups[name] = group
+	}
+	localized.Entities = make(map[string]Entity, len(c.Entities))
+	for name, entity := range c.Entities {
+		entity.Description = Localize(entity.Description, entity.Descriptions, acceptLanguage)
+		localized.Entities[name] = entity
+	}
+	localized.Functions = make(map[string]Function, len(c.Functions))
+	for name, fn := range c.Functions {
+		fn.Description = Localize(fn.Description, fn.Descriptions, acceptLanguage)
+		localized.Functions[name] = fn
+	}
+	return &localized
+}
+
+// HasTranslations reports whether any description in c is translated.
+func (c *Config) HasTranslations() bool {
+	for _, group := range c.AccessGroups {
+		if len(group.Descriptions) > 0 {
+			return true
+		}
+	}
+	for _, entity := range c.Entities {
+		if len(entity.Descriptions) > 0 {
+			return true
+		}
+	}
+	for _, fn := range c.Functions {
+		if len(fn.Descriptions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTranslations checks that translations are keyed by language tags
+// and not empty.
+func validateTranslations(translations map[string]string) error {
+	for _, locale := range sortedKeys(translations) {
+		if !validLocale(locale) {
+			return fmt.Errorf("invalid locale '%s'", locale)
+		}
+		if strings.TrimSpace(translations[locale]) == "" {
+			return fmt.Errorf("description for locale '%s' is empty", locale)
+		}
+	}
+	return nil
+}
+
+// validLocale reports whether locale looks like a BCP 47 language tag:
+// alphanumeric subtags of 1 to 8 characters separated by hyphens, starting
+// with a language.
+func validLocale(locale string) bool {
+	subtags := strings.Split(locale, "-")
+	for i, subtag := range subtags {
+		if len(subtag) == 0 || len(subtag) > 8 {
+			return false
+		}
+		for _, r := range subtag {
+			letter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+			if !letter && (i == 0 || r < '0' || r > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
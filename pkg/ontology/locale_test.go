@@ -0,0 +1,84 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	translations := map[string]string{"de": "Kunde abrufen", "fr-CA": "Obtenir le client", "fr": "Obtenir un client"}
+	tests := []struct {
+		acceptLanguage string
+		want           string
+	}{
+		{"", "Get a customer"},
+		{"de", "Kunde abrufen"},
+		{"de-CH", "Kunde abrufen"},
+		{"FR-ca", "Obtenir le client"},
+		{"fr-BE", "Obtenir un client"},
+		{"es, de;q=0.8", "Kunde abrufen"},
+		{"de;q=0.5, fr", "Obtenir un client"},
+		{"en, de;q=0.9", "Kunde abrufen"},
+		{"*, de;q=0.5", "Get a customer"},
+		{"de;q=0", "Get a customer"},
+		{"ja", "Get a customer"},
+	}
+	for _, tt := range tests {
+		if got := Localize("Get a customer", translations, tt.acceptLanguage); got != tt.want {
+			t.Errorf("Localize(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+		}
+	}
+}
+
+func TestConfigLocalized(t *testing.T) {
+	config := queryConfig()
+	fn := config.Functions["getCustomer"]
+	fn.Descriptions = map[string]string{"de": "Kunde abrufen"}
+	config.Functions["getCustomer"] = fn
+	config.Entities["Customer"] = Entity{Description: "A customer", Descriptions: map[string]string{"de": "Ein Kunde"}}
+
+	if !config.HasTranslations() {
+		t.Error("HasTranslations() = false")
+	}
+	localized := config.Localized("de-DE")
+	if got := localized.Functions["getCustomer"].Description; got != "Kunde abrufen" {
+		t.Errorf("function description = %q", got)
+	}
+	if got := localized.Entities["Customer"].Description; got != "Ein Kunde" {
+		t.Errorf("entity description = %q", got)
+	}
+	if config.Functions["getCustomer"].Description == "Kunde abrufen" {
+		t.Error("Localized should not modify the config")
+	}
+	if got := config.ExtractSnapshot().Functions["getCustomer"].Descriptions["de"]; got != "Kunde abrufen" {
+		t.Errorf("snapshot descriptions = %q", got)
+	}
+}
+
+func TestValidateTranslations(t *testing.T) {
+	tests := []struct {
+		translations map[string]string
+		wantErr      string
+	}{
+		{map[string]string{"de_DE": "Kunde"}, "invalid locale 'de_DE'"},
+		{map[string]string{"1de": "Kunde"}, "invalid locale '1de'"},
+		{map[string]string{"de": " "}, "description for locale 'de' is empty"},
+	}
+	for _, tt := range tests {
+		config := queryConfig()
+		fn := config.Functions["getCustomer"]
+		fn.Descriptions = tt.translations
+		config.Functions["getCustomer"] = fn
+		err := config.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+		}
+	}
+	config := queryConfig()
+	fn := config.Functions["getCustomer"]
+	fn.Descriptions = map[string]string{"zh-Hant-TW": "取得客戶", "es-419": "Obtener cliente"}
+	config.Functions["getCustomer"] = fn
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+}
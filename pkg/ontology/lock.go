@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -19,12 +21,30 @@ type FieldReference struct {
 type FunctionShape struct {
 	Description              string                 `json:"description"`
 	Access                   []string               `json:"access"`
+	AccessRule               string                 `json:"accessRule,omitempty"`
 	Entities                 []string               `json:"entities"`
 	InputsSchema             map[string]interface{} `json:"inputsSchema"`
 	OutputsSchema            map[string]interface{} `json:"outputsSchema,omitempty"`
 	FieldReferences          []FieldReference       `json:"fieldReferences,omitempty"`
 	UsesUserContext          *bool                  `json:"usesUserContext,omitempty"`
 	UsesOrganizationContext  *bool                  `json:"usesOrganizationContext,omitempty"`
+	Pipeline                 *Pipeline              `json:"pipeline,omitempty"`
+	Owner                    string                 `json:"owner,omitempty"`
+	Team                     string                 `json:"team,omitempty"`
+	Runbook                  string                 `json:"runbook,omitempty"`
+	Stability                Stability              `json:"stability,omitempty"`
+	Internal                 bool                   `json:"internal,omitempty"`
+	Aliases                  []string               `json:"aliases,omitempty"`
+	Version                  int                    `json:"version,omitempty"`
+	RateLimit                *RateLimit             `json:"rateLimit,omitempty"`
+	HeadersSchema            map[string]interface{} `json:"headersSchema,omitempty"`
+	QuerySchema              map[string]interface{} `json:"querySchema,omitempty"`
+	Deprecated               string                 `json:"deprecated,omitempty"`
+	Fallback                 *FallbackShape         `json:"fallback,omitempty"`
+	Timeout                  string                 `json:"timeout,omitempty"`
+	OutputVersions           map[string]interface{} `json:"outputVersions,omitempty"`
+	OutputProfile            string                 `json:"outputProfile,omitempty"`
+	Descriptions             map[string]string      `json:"descriptions,omitempty"`
 }
 
 // OntologySnapshot represents a complete snapshot of the ontology.
@@ -32,7 +52,19 @@ type OntologySnapshot struct {
 	Name         string                    `json:"name"`
 	AccessGroups []string                  `json:"accessGroups"`
 	Entities     []string                  `json:"entities,omitempty"`
+	// EntityShapes records the entities that declare a schema or relations.
+	EntityShapes map[string]EntityShape    `json:"entityShapes,omitempty"`
 	Functions    map[string]FunctionShape  `json:"functions"`
+	// Versions lists the live versions of each versioned function.
+	Versions     map[string][]int          `json:"versions,omitempty"`
+	Events       map[string]EventShape     `json:"events,omitempty"`
+}
+
+// EntityShape represents a snapshot of an entity's schema and relations.
+type EntityShape struct {
+	Key       string         `json:"key,omitempty"`
+	Schema    map[string]any `json:"schema,omitempty"`
+	Relations []Relation     `json:"relations,omitempty"`
 }
 
 // LockFile represents the ont.lock file structure.
@@ -73,8 +105,20 @@ func (c *Config) ExtractSnapshot() OntologySnapshot {
 
 	// Collect and sort entities
 	entities := make([]string, 0, len(c.Entities))
-	for name := range c.Entities {
+	var entityShapes map[string]EntityShape
+	for name, entity := range c.Entities {
 		entities = append(entities, name)
+		if entity.Schema == nil && len(entity.Relations) == 0 {
+			continue
+		}
+		if entityShapes == nil {
+			entityShapes = make(map[string]EntityShape)
+		}
+		shape := EntityShape{Key: entity.Key, Relations: entity.Relations}
+		if entity.Schema != nil {
+			shape.Schema = c.JSONSchemaFor(entity.Schema)
+		}
+		entityShapes[name] = shape
 	}
 	sort.Strings(entities)
 
@@ -88,13 +132,38 @@ func (c *Config) ExtractSnapshot() OntologySnapshot {
 		shape := FunctionShape{
 			Description:   fn.Description,
 			Access:        access,
+			AccessRule:    fn.AccessRule,
 			Entities:      fnEntities,
-			InputsSchema:  fn.Inputs.JSONSchema(),
+			InputsSchema:  c.JSONSchemaFor(fn.Inputs),
+			Pipeline:      fn.Pipeline,
+			Owner:         fn.Owner,
+			Team:          fn.Team,
+			Runbook:       fn.Runbook,
+			Stability:     fn.Stability,
+			Internal:      fn.Internal,
+			Aliases:       sortedCopy(fn.Aliases),
+			FieldReferences: fn.FieldReferences,
+			UsesUserContext: optionalBool(fn.UsesUserContext),
+			UsesOrganizationContext: optionalBool(fn.UsesOrganizationContext),
+			Version:       fn.Version,
+			RateLimit:     fn.RateLimit,
+			Deprecated:    fn.Deprecated,
+			Fallback:      fn.Fallback.shape(),
+			Timeout:       formatTimeout(fn.Timeout),
+			OutputVersions: c.outputVersionSchemas(fn),
+			OutputProfile:  fn.OutputProfile,
+			Descriptions:   fn.Descriptions,
 		}
 
 		// Add outputs schema if present
 		if fn.Outputs != nil {
-			shape.OutputsSchema = fn.Outputs.JSONSchema()
+			shape.OutputsSchema = c.JSONSchemaFor(fn.Outputs)
+		}
+		if fn.Headers != nil {
+			shape.HeadersSchema = c.JSONSchemaFor(fn.Headers)
+		}
+		if fn.Query != nil {
+			shape.QuerySchema = c.JSONSchemaFor(fn.Query)
 		}
 
 		functions[name] = shape
@@ -104,7 +173,10 @@ func (c *Config) ExtractSnapshot() OntologySnapshot {
 		Name:         c.Name,
 		AccessGroups: accessGroups,
 		Entities:     entities,
+		EntityShapes: entityShapes,
 		Functions:    functions,
+		Versions:     c.functionVersions(),
+		Events:       c.eventShapes(),
 	}
 }
 
@@ -167,6 +239,45 @@ type LockDiff struct {
 	NewFunctions   []string
 	ModifiedFunctions []string
 	DeletedFunctions []string
+	NewEvents      []string
+	ModifiedEvents []string
+	DeletedEvents  []string
+	// ExperimentalFunctions lists the modified functions that are
+	// experimental in both the lock and the config and whose access didn't
+	// change. Their changes don't require review.
+	ExperimentalFunctions []string
+	// Changes details what changed in each modified function.
+	Changes map[string]*FunctionChanges
+}
+
+// FunctionChanges details what changed in a modified function.
+type FunctionChanges struct {
+	// Fields lists the other lock file properties that changed, such as
+	// "access" or "description".
+	Fields []string
+	// Inputs and Outputs are the schema changes, classified for callers
+	// sending inputs and reading outputs. They are nil when the locked
+	// schema can't be read back (see ParseJSONSchema), in which case
+	// "inputsSchema" or "outputsSchema" is in Fields.
+	Inputs  *SchemaDiff
+	Outputs *SchemaDiff
+}
+
+// Breaking reports whether the input or output changes are breaking.
+func (c *FunctionChanges) Breaking() bool {
+	return (c.Inputs != nil && c.Inputs.Breaking()) || (c.Outputs != nil && c.Outputs.Breaking())
+}
+
+// BreakingFunctions returns the modified functions with breaking input or
+// output changes, sorted.
+func (d *LockDiff) BreakingFunctions() []string {
+	var names []string
+	for _, name := range sortedKeys(d.Changes) {
+		if d.Changes[name].Breaking() {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 // HasChanges returns true if there are any changes.
@@ -174,7 +285,17 @@ func (d *LockDiff) HasChanges() bool {
 	return d.HashChanged ||
 		len(d.NewAccessGroups) > 0 || len(d.ModifiedAccessGroups) > 0 || len(d.DeletedAccessGroups) > 0 ||
 		len(d.NewEntities) > 0 || len(d.ModifiedEntities) > 0 || len(d.DeletedEntities) > 0 ||
-		len(d.NewFunctions) > 0 || len(d.ModifiedFunctions) > 0 || len(d.DeletedFunctions) > 0
+		len(d.NewFunctions) > 0 || len(d.ModifiedFunctions) > 0 || len(d.DeletedFunctions) > 0 ||
+		len(d.NewEvents) > 0 || len(d.ModifiedEvents) > 0 || len(d.DeletedEvents) > 0
+}
+
+// RequiresReview reports whether the diff has changes that need review,
+// which is every change except those in ExperimentalFunctions.
+func (d *LockDiff) RequiresReview() bool {
+	return len(d.NewAccessGroups) > 0 || len(d.ModifiedAccessGroups) > 0 || len(d.DeletedAccessGroups) > 0 ||
+		len(d.NewEntities) > 0 || len(d.ModifiedEntities) > 0 || len(d.DeletedEntities) > 0 ||
+		len(d.NewFunctions) > 0 || len(d.ModifiedFunctions) > len(d.ExperimentalFunctions) || len(d.DeletedFunctions) > 0 ||
+		len(d.NewEvents) > 0 || len(d.ModifiedEvents) > 0 || len(d.DeletedEvents) > 0
 }
 
 // DiffLock compares the current config against a lock file and returns the differences.
@@ -198,66 +319,140 @@ func (c *Config) DiffLock(path string) (*LockDiff, error) {
 		return nil, err
 	}
 
-	diff := &LockDiff{}
-
-	// Check overall hash
-	currentHash := c.Hash()
-	if currentHash != lock.Hash {
-		diff.HashChanged = true
-	}
-
-	// Build sets for comparison
-	lockAccessGroupSet := make(map[string]bool)
-	for _, name := range lock.Ontology.AccessGroups {
-		lockAccessGroupSet[name] = true
-	}
+	diff := DiffSnapshots(lock.Ontology, c.ExtractSnapshot())
+	diff.HashChanged = c.Hash() != lock.Hash
+	return diff, nil
+}
 
-	lockEntitySet := make(map[string]bool)
-	for _, name := range lock.Ontology.Entities {
-		lockEntitySet[name] = true
-	}
+// DiffSnapshots compares two ontology snapshots, such as a lock file's and
+// the current config's or two registered versions, and returns the
+// differences from old to new. HashChanged is left to the caller, since
+// snapshots don't carry their hash.
+func DiffSnapshots(old, new OntologySnapshot) *LockDiff {
+	diff := &LockDiff{}
 
 	// Compare access groups
-	for name := range c.AccessGroups {
-		if !lockAccessGroupSet[name] {
+	for _, name := range new.AccessGroups {
+		if !contains(old.AccessGroups, name) {
 			diff.NewAccessGroups = append(diff.NewAccessGroups, name)
 		}
 	}
-	for _, name := range lock.Ontology.AccessGroups {
-		if _, exists := c.AccessGroups[name]; !exists {
+	for _, name := range old.AccessGroups {
+		if !contains(new.AccessGroups, name) {
 			diff.DeletedAccessGroups = append(diff.DeletedAccessGroups, name)
 		}
 	}
 
 	// Compare entities
-	for name := range c.Entities {
-		if !lockEntitySet[name] {
+	for _, name := range new.Entities {
+		if !contains(old.Entities, name) {
 			diff.NewEntities = append(diff.NewEntities, name)
 		}
 	}
-	for _, name := range lock.Ontology.Entities {
-		if _, exists := c.Entities[name]; !exists {
+	for _, name := range old.Entities {
+		if !contains(new.Entities, name) {
 			diff.DeletedEntities = append(diff.DeletedEntities, name)
+		} else if !jsonEqual(old.EntityShapes[name], new.EntityShapes[name]) {
+			diff.ModifiedEntities = append(diff.ModifiedEntities, name)
 		}
 	}
 
 	// Compare functions by comparing their shapes
-	currentSnapshot := c.ExtractSnapshot()
-	for name, currentShape := range currentSnapshot.Functions {
-		lockShape, exists := lock.Ontology.Functions[name]
+	for name, newShape := range new.Functions {
+		oldShape, exists := old.Functions[name]
 		if !exists {
 			diff.NewFunctions = append(diff.NewFunctions, name)
-		} else if !functionsEqual(currentShape, lockShape) {
+		} else if !functionsEqual(newShape, oldShape) {
 			diff.ModifiedFunctions = append(diff.ModifiedFunctions, name)
+			if diff.Changes == nil {
+				diff.Changes = make(map[string]*FunctionChanges)
+			}
+			diff.Changes[name] = diffFunctionShapes(oldShape, newShape)
+			if experimentalChange(newShape, oldShape) {
+				diff.ExperimentalFunctions = append(diff.ExperimentalFunctions, name)
+			}
 		}
 	}
-	for name := range lock.Ontology.Functions {
-		if _, exists := c.Functions[name]; !exists {
+	for name := range old.Functions {
+		if _, exists := new.Functions[name]; !exists {
 			diff.DeletedFunctions = append(diff.DeletedFunctions, name)
 		}
 	}
 
-	return diff, nil
+	// Compare events
+	for _, name := range sortedKeys(new.Events) {
+		if oldShape, exists := old.Events[name]; !exists {
+			diff.NewEvents = append(diff.NewEvents, name)
+		} else if !jsonEqual(oldShape, new.Events[name]) {
+			diff.ModifiedEvents = append(diff.ModifiedEvents, name)
+		}
+	}
+	for _, name := range sortedKeys(old.Events) {
+		if _, exists := new.Events[name]; !exists {
+			diff.DeletedEvents = append(diff.DeletedEvents, name)
+		}
+	}
+
+	return diff
+}
+
+// experimentalChange reports whether a change to a function is exempt from
+// review: it was and still is experimental, and who may call it is unchanged.
+func experimentalChange(current, locked FunctionShape) bool {
+	return current.Stability == StabilityExperimental && locked.Stability == StabilityExperimental &&
+		reflect.DeepEqual(current.Access, locked.Access) && current.AccessRule == locked.AccessRule
+}
+
+// diffFunctionShapes compares a locked function with its current shape.
+func diffFunctionShapes(locked, current FunctionShape) *FunctionChanges {
+	changes := &FunctionChanges{}
+	schemas := map[string]bool{}
+	if diff, ok := diffShapeSchemas(locked.InputsSchema, current.InputsSchema, DiffInputSchemas); ok {
+		changes.Inputs = diff
+		schemas["inputsSchema"] = true
+	}
+	if diff, ok := diffShapeSchemas(locked.OutputsSchema, current.OutputsSchema, DiffOutputSchemas); ok {
+		changes.Outputs = diff
+		schemas["outputsSchema"] = true
+	}
+
+	var lockedFields, currentFields map[string]any
+	lockedJSON, _ := json.Marshal(locked)
+	currentJSON, _ := json.Marshal(current)
+	json.Unmarshal(lockedJSON, &lockedFields)
+	json.Unmarshal(currentJSON, &currentFields)
+	for _, key := range sortedKeys(unionKeys(lockedFields, currentFields)) {
+		if !schemas[key] && !jsonEqual(lockedFields[key], currentFields[key]) {
+			changes.Fields = append(changes.Fields, key)
+		}
+	}
+	return changes
+}
+
+// diffShapeSchemas diffs two JSON Schemas of a lock file. It returns false
+// if either can't be parsed.
+func diffShapeSchemas(locked, current map[string]any, diff func(old, new Schema) *SchemaDiff) (*SchemaDiff, bool) {
+	old, err := schemaFromJSON(locked)
+	if err != nil {
+		return nil, false
+	}
+	new, err := schemaFromJSON(current)
+	if err != nil {
+		return nil, false
+	}
+	return diff(old, new), true
+}
+
+// schemaFromJSON parses a JSON Schema held as a map; nil means no schema.
+func schemaFromJSON(schema map[string]any) (Schema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSONSchema(data)
 }
 
 // functionsEqual compares two function shapes for equality.
@@ -268,6 +463,26 @@ func functionsEqual(a, b FunctionShape) bool {
 	return string(aJSON) == string(bJSON)
 }
 
+// describe returns a line per change of the named function.
+func (c *FunctionChanges) describe(name string) string {
+	var result string
+	if len(c.Fields) > 0 {
+		result += fmt.Sprintf("  %s: changed %s\n", name, strings.Join(c.Fields, ", "))
+	}
+	for _, diff := range []struct {
+		side string
+		diff *SchemaDiff
+	}{{"input", c.Inputs}, {"output", c.Outputs}} {
+		if diff.diff == nil {
+			continue
+		}
+		for _, change := range diff.diff.Changes {
+			result += fmt.Sprintf("  %s %s %s\n", name, diff.side, change)
+		}
+	}
+	return result
+}
+
 // String returns a human-readable summary of the changes.
 func (d *LockDiff) String() string {
 	if !d.HasChanges() {
@@ -301,10 +516,26 @@ func (d *LockDiff) String() string {
 	}
 	if len(d.ModifiedFunctions) > 0 {
 		result += fmt.Sprintf("Modified functions: %v\n", d.ModifiedFunctions)
+		for _, name := range sortedKeys(d.Changes) {
+			result += d.Changes[name].describe(name)
+		}
+	}
+	if len(d.ExperimentalFunctions) > 0 {
+		result += fmt.Sprintf("Experimental changes (no review required): %v\n", d.ExperimentalFunctions)
 	}
 	if len(d.DeletedFunctions) > 0 {
 		result += fmt.Sprintf("Deleted functions: %v\n", d.DeletedFunctions)
 	}
 
+	if len(d.NewEvents) > 0 {
+		result += fmt.Sprintf("New events: %v\n", d.NewEvents)
+	}
+	if len(d.ModifiedEvents) > 0 {
+		result += fmt.Sprintf("Modified events: %v\n", d.ModifiedEvents)
+	}
+	if len(d.DeletedEvents) > 0 {
+		result += fmt.Sprintf("Deleted events: %v\n", d.DeletedEvents)
+	}
+
 	return result
 }
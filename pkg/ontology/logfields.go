@@ -0,0 +1,40 @@
+package ontology
+
+// fieldLogger adds fields to every entry of the logger it wraps.
+type fieldLogger struct {
+	logger Logger
+	fields []any
+}
+
+// WithLogFields returns a logger that adds keysAndValues to every entry
+// logged with logger. Context.WithLogFields uses it; custom Context
+// implementations can too.
+func WithLogFields(logger Logger, keysAndValues ...any) Logger {
+	if logger == nil || len(keysAndValues) == 0 {
+		return logger
+	}
+	if l, ok := logger.(*fieldLogger); ok {
+		return &fieldLogger{logger: l.logger, fields: append(append([]any(nil), l.fields...), keysAndValues...)}
+	}
+	return &fieldLogger{logger: logger, fields: append([]any(nil), keysAndValues...)}
+}
+
+func (l *fieldLogger) with(keysAndValues []any) []any {
+	return append(append([]any(nil), l.fields...), keysAndValues...)
+}
+
+func (l *fieldLogger) Info(msg string, keysAndValues ...any) {
+	l.logger.Info(msg, l.with(keysAndValues)...)
+}
+
+func (l *fieldLogger) Error(msg string, keysAndValues ...any) {
+	l.logger.Error(msg, l.with(keysAndValues)...)
+}
+
+func (l *fieldLogger) Debug(msg string, keysAndValues ...any) {
+	l.logger.Debug(msg, l.with(keysAndValues)...)
+}
+
+func (l *fieldLogger) Warn(msg string, keysAndValues ...any) {
+	l.logger.Warn(msg, l.with(keysAndValues)...)
+}
@@ -0,0 +1,58 @@
+package ontology
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fieldRecorder records the fields of info entries.
+type fieldRecorder struct {
+	Logger
+	fields [][]any
+}
+
+func (l *fieldRecorder) Info(msg string, keysAndValues ...any) {
+	l.fields = append(l.fields, keysAndValues)
+}
+
+func TestWithLogFields(t *testing.T) {
+	logger := &fieldRecorder{Logger: DefaultLogger()}
+	ctx := NewContext(nil, logger, nil, nil).WithLogFields("function", "getCustomer")
+	ctx.WithLogFields("orderId", "o-1").Logger().Info("cancelled", "refund", true)
+	ctx.Logger().Info("done")
+
+	want := [][]any{
+		{"function", "getCustomer", "orderId", "o-1", "refund", true},
+		{"function", "getCustomer"},
+	}
+	if !reflect.DeepEqual(logger.fields, want) {
+		t.Errorf("fields = %v, want %v", logger.fields, want)
+	}
+}
+
+func TestWithLogFieldsSharesWarnings(t *testing.T) {
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+	ctx.WithLogFields("orderId", "o-1").Warn("slow")
+	if got := ctx.Warnings(); len(got) != 1 || got[0] != "slow" {
+		t.Errorf("Warnings() = %q, want [slow]", got)
+	}
+}
+
+func TestWithLogFieldsKeepsContextTracking(t *testing.T) {
+	config := queryConfig()
+	fn := config.Functions["deleteCustomer"]
+	fn.Resolver = func(ctx Context, input any) (any, error) {
+		_ = ctx.WithLogFields("step", 1).UserContext()
+		return fn.Outputs.(*ObjectSchema).Properties(), nil
+	}
+	config.Functions["deleteCustomer"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &recordingLogger{Logger: DefaultLogger()}
+	config.Resolve(NewContext(nil, logger, []string{"admin"}, map[string]any{"id": "u-1"}), config.Functions["deleteCustomer"], map[string]any{})
+	if len(logger.warnings) != 1 {
+		t.Errorf("warnings = %q, want one about UsesUserContext", logger.warnings)
+	}
+}
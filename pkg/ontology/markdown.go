@@ -0,0 +1,61 @@
+package ontology
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// truncationMark ends truncated text.
+const truncationMark = " …"
+
+// TruncateMarkdown shortens markdown text to at most max bytes for
+// surfaces with a length budget, such as MCP tool descriptions. It cuts at
+// the last paragraph break, else the last sentence, line or word that
+// fits, marks the cut with "…", and closes a code fence left open.
+// Text that fits is returned as is.
+func TruncateMarkdown(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	const fence = "\n```"
+	budget := max - len(truncationMark) - len(fence)
+	if budget <= 0 {
+		return ""
+	}
+	for budget > 0 && !utf8.RuneStart(text[budget]) {
+		budget--
+	}
+	// Look one byte further, so a sentence ending right at the budget counts
+	cut := text[:budget]
+	if at := lastBoundary(text[:budget+1]); at > 0 {
+		cut = text[:at]
+	}
+	cut = strings.TrimRight(cut, " \n\t")
+
+	if strings.Count(cut, "```")%2 == 1 {
+		cut += fence
+	}
+	return cut + truncationMark
+}
+
+// lastBoundary returns where to cut text: after its last paragraph,
+// sentence or line if one ends in the second half, else at its last word
+// break, or -1.
+func lastBoundary(text string) int {
+	if at := strings.LastIndex(text, "\n\n"); at >= len(text)/2 {
+		return at
+	}
+	sentence := -1
+	for _, end := range []string{". ", ".\n", "! ", "? "} {
+		if i := strings.LastIndex(text, end); i >= 0 && i+1 > sentence {
+			sentence = i + 1
+		}
+	}
+	if sentence >= len(text)/2 {
+		return sentence
+	}
+	if at := strings.LastIndexByte(text, '\n'); at >= len(text)/2 {
+		return at
+	}
+	return strings.LastIndexByte(text, ' ')
+}
@@ -0,0 +1,51 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTruncateMarkdown(t *testing.T) {
+	paragraphs := "Returns the customer by id.\n\nArchived customers are only returned to admins. Use searchCustomers to find ids."
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want string
+	}{
+		{"fits", "Short.", 100, "Short."},
+		{"paragraph", paragraphs, 60, "Returns the customer by id. …"},
+		{"sentence", "A first sentence that is fairly long. Second one.", 45, "A first sentence that is fairly long. …"},
+		{"word", "one two three four five six seven eight", 25, "one two three …"},
+		{"open fence", "Example:\n\n```\ncall({ id: 1 })\ncall({ id: 2 })\ncall({ id: 3 })\n```", 50, "Example:\n\n```\ncall({ id: 1 })\n``` …"},
+		{"no room", "Anything at all", 3, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateMarkdown(tt.text, tt.max)
+			if got != tt.want {
+				t.Errorf("TruncateMarkdown = %q, want %q", got, tt.want)
+			}
+			if len(got) > tt.max {
+				t.Errorf("length %d exceeds %d", len(got), tt.max)
+			}
+		})
+	}
+}
+
+func TestLongDescription(t *testing.T) {
+	config := queryConfig()
+	before := config.Hash()
+	fn := config.Functions["getCustomer"]
+	fn.LongDescription = "Archived customers are only returned to admins."
+	config.Functions["getCustomer"] = fn
+
+	if config.Hash() != before {
+		t.Error("the long description should not change the hash")
+	}
+	for _, node := range config.Graph().Nodes {
+		if node.Name == "getCustomer" && !strings.Contains(node.Details, "Archived") {
+			t.Errorf("graph node details = %q", node.Details)
+		}
+	}
+}
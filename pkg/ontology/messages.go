@@ -0,0 +1,264 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ConstraintError is a validation failure of one declarative constraint,
+// identified by its JSON Schema keyword: "type", "required",
+// "additionalProperties", "minLength", "maxLength", "pattern", "format",
+// "enum", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum",
+// "multipleOf", "minItems", "maxItems", "uniqueItems", "minProperties",
+// "maxProperties" or "maxBytes".
+type ConstraintError struct {
+	Keyword string
+	// Limit is the constraint's bound or argument, e.g. 8 for a minLength
+	// of 8, the format name or the allowed values.
+	Limit any
+	// Value is the value at fault, or RedactedValue for sensitive schemas.
+	// It is nil for "required" and "additionalProperties".
+	Value any
+	// Field is the property name for "required" and "additionalProperties".
+	Field string
+	// Message is the error text: a message set with Message, one returned
+	// by the SetErrorMessages hook, or the default.
+	Message string
+}
+
+func (e *ConstraintError) Error() string {
+	return e.Message
+}
+
+// MessageFunc returns the message for a validation failure, or "" to keep
+// the default message (err.Message).
+type MessageFunc func(err *ConstraintError) string
+
+// messageFunc holds the hook set with SetErrorMessages.
+var messageFunc struct {
+	sync.RWMutex
+	fn MessageFunc
+}
+
+// SetErrorMessages sets a hook that rewrites the default messages of
+// validation failures, e.g. to translate them or phrase them for end
+// users:
+//
+//	ontology.SetErrorMessages(func(err *ontology.ConstraintError) string {
+//		if err.Keyword == "minLength" {
+//			return fmt.Sprintf("Enter at least %v characters", err.Limit)
+//		}
+//		return ""
+//	})
+//
+// Messages set on a schema with Message take precedence over the hook. Set
+// the hook before serving, typically in an init function; nil removes it.
+func SetErrorMessages(fn MessageFunc) {
+	messageFunc.Lock()
+	defer messageFunc.Unlock()
+	messageFunc.fn = fn
+}
+
+// constraintError returns the error for a failed constraint, with the
+// hook's message if it sets one.
+func constraintError(keyword string, limit, value any, format string, args ...any) *ConstraintError {
+	return hookMessage(&ConstraintError{Keyword: keyword, Limit: limit, Value: value, Message: fmt.Sprintf(format, args...)})
+}
+
+// fieldError returns the error for a missing or unknown property.
+func fieldError(keyword, field, message string) *ConstraintError {
+	return hookMessage(&ConstraintError{Keyword: keyword, Field: field, Message: message})
+}
+
+// hookMessage replaces the message of err with the hook's, if it sets one.
+func hookMessage(err *ConstraintError) *ConstraintError {
+	messageFunc.RLock()
+	fn := messageFunc.fn
+	messageFunc.RUnlock()
+	if fn != nil {
+		if message := fn(err); message != "" {
+			err.Message = message
+		}
+	}
+	return err
+}
+
+// messages holds the messages set with Message, by the keyword of the
+// constraint they replace; "" replaces every failure of the schema.
+type messages struct {
+	byKeyword map[string]string
+	// last is the keyword of the constraint set last, which Message
+	// applies to
+	last string
+}
+
+// constrain records that the constraint with keyword was set last.
+func (m *messages) constrain(keyword string) {
+	m.last = keyword
+}
+
+// setMessage sets the message of keyword.
+func (m *messages) setMessage(keyword, text string) {
+	if m.byKeyword == nil {
+		m.byKeyword = make(map[string]string)
+	}
+	m.byKeyword[keyword] = text
+}
+
+// Messages returns the messages set with Message and MessageFor, by
+// constraint keyword; "" is the message for any failure.
+func (m *messages) Messages() map[string]string {
+	return m.byKeyword
+}
+
+// messageFor returns the message set for keyword, if any.
+func (m *messages) messageFor(keyword string) (string, bool) {
+	if text, ok := m.byKeyword[keyword]; ok {
+		return text, true
+	}
+	text, ok := m.byKeyword[""]
+	return text, ok
+}
+
+// message replaces the text of the schema's own constraint errors with the
+// message set for them. Errors of nested schemas, which wrap theirs, are
+// returned unchanged.
+func (m *messages) message(err error) error {
+	ce, ok := err.(*ConstraintError)
+	if !ok {
+		return err
+	}
+	text, ok := m.messageFor(ce.Keyword)
+	if !ok {
+		return err
+	}
+	replaced := *ce
+	replaced.Message = expandMessage(text, ce)
+	return &replaced
+}
+
+// expandMessage fills in the {limit} and {field} placeholders of a
+// message.
+func expandMessage(text string, err *ConstraintError) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	return strings.NewReplacer(
+		"{limit}", fmt.Sprint(err.Limit),
+		"{field}", err.Field,
+	).Replace(text)
+}
+
+// messenger is a schema that supports Message.
+type messenger interface {
+	messageFor(keyword string) (string, bool)
+}
+
+// missingField returns the error for a missing required property, with
+// the property schema's "required" message if it has one:
+//
+//	Object(map[string]Schema{
+//		"email": String().Email().MessageFor("required", "Enter your email"),
+//	})
+func missingField(name string, schema Schema, message string) error {
+	err := fieldError("required", name, message)
+	if m, ok := schema.(messenger); ok {
+		if text, ok := m.messageFor("required"); ok {
+			err.Message = expandMessage(text, err)
+		}
+	}
+	return err
+}
+
+// Message replaces the error message of the constraint set just before it,
+// so end users see friendly errors:
+//
+//	String().Pattern(`@acme\.com$`).Message("Provide a valid work email")
+//
+// Before any constraint it replaces every failure of the schema, including
+// a wrong type or a missing required property. "{limit}" in text is
+// replaced by the constraint's bound. Messages don't change the JSON
+// Schema.
+func (s *StringSchema) Message(text string) *StringSchema {
+	s.setMessage(s.last, text)
+	return s
+}
+
+// MessageFor replaces the error message of the constraint with the given
+// keyword (see ConstraintError), or of every failure if keyword is "".
+func (s *StringSchema) MessageFor(keyword, text string) *StringSchema {
+	s.setMessage(keyword, text)
+	return s
+}
+
+// Message replaces the error message of the constraint set just before it
+// (see StringSchema.Message).
+func (n *NumberSchema) Message(text string) *NumberSchema {
+	n.setMessage(n.last, text)
+	return n
+}
+
+// MessageFor replaces the error message of the constraint with the given
+// keyword, or of every failure if keyword is "".
+func (n *NumberSchema) MessageFor(keyword, text string) *NumberSchema {
+	n.setMessage(keyword, text)
+	return n
+}
+
+// Message replaces the error message of the schema's failures (see
+// StringSchema.Message).
+func (b *BooleanSchema) Message(text string) *BooleanSchema {
+	b.setMessage(b.last, text)
+	return b
+}
+
+// MessageFor replaces the error message of the constraint with the given
+// keyword, or of every failure if keyword is "".
+func (b *BooleanSchema) MessageFor(keyword, text string) *BooleanSchema {
+	b.setMessage(keyword, text)
+	return b
+}
+
+// Message replaces the error message of the constraint set just before it
+// (see StringSchema.Message).
+func (b *BytesSchema) Message(text string) *BytesSchema {
+	b.setMessage(b.last, text)
+	return b
+}
+
+// MessageFor replaces the error message of the constraint with the given
+// keyword, or of every failure if keyword is "".
+func (b *BytesSchema) MessageFor(keyword, text string) *BytesSchema {
+	b.setMessage(keyword, text)
+	return b
+}
+
+// Message replaces the error message of the constraint set just before it
+// (see StringSchema.Message). Failures of items keep their own messages.
+func (a *ArraySchema) Message(text string) *ArraySchema {
+	a.setMessage(a.last, text)
+	return a
+}
+
+// MessageFor replaces the error message of the constraint with the given
+// keyword, or of every failure if keyword is "".
+func (a *ArraySchema) MessageFor(keyword, text string) *ArraySchema {
+	a.setMessage(keyword, text)
+	return a
+}
+
+// Message replaces the error message of the constraint set just before it
+// (see StringSchema.Message). Failures of properties keep their own
+// messages; a missing property uses its schema's "required" message.
+func (o *ObjectSchema) Message(text string) *ObjectSchema {
+	o.setMessage(o.last, text)
+	return o
+}
+
+// MessageFor replaces the error message of the constraint with the given
+// keyword, or of every failure if keyword is "".
+func (o *ObjectSchema) MessageFor(keyword, text string) *ObjectSchema {
+	o.setMessage(keyword, text)
+	return o
+}
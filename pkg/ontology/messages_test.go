@@ -0,0 +1,101 @@
+package ontology
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"email": String().Message("Enter your email").
+			Email().Message("Provide a valid work email").
+			Max(20),
+		"password": String().Min(8).Message("Use at least {limit} characters"),
+		"age":      Integer().Min(18).Message("You must be an adult").Max(150),
+		"tags":     Array(String()).MaxItems(2).Message("Pick at most {limit} tags"),
+	}).Optional("age", "tags")
+
+	tests := []struct {
+		data any
+		want string
+	}{
+		{map[string]any{"password": "secret123"}, "Enter your email"},
+		{map[string]any{"email": 42, "password": "secret123"}, "field 'email': Enter your email"},
+		{map[string]any{"email": "ada", "password": "secret123"}, "field 'email': Provide a valid work email"},
+		{map[string]any{"email": "ada@example-company.com", "password": "secret123"}, "field 'email': Enter your email"},
+		{map[string]any{"email": "ada@acme.com", "password": "short"}, "field 'password': Use at least 8 characters"},
+		{map[string]any{"email": "ada@acme.com", "password": "secret123", "age": 17}, "field 'age': You must be an adult"},
+		{map[string]any{"email": "ada@acme.com", "password": "secret123", "age": 200}, "field 'age': number 200 exceeds maximum 150"},
+		{map[string]any{"email": "ada@acme.com", "password": "secret123", "tags": []any{"a", "b", "c"}}, "field 'tags': Pick at most 2 tags"},
+	}
+	validate := Compile(schema)
+	for _, tt := range tests {
+		for name, fn := range map[string]ValidateFunc{"Validate": schema.Validate, "Compile": validate} {
+			err := fn(tt.data)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("%s(%v) = %v, want %s", name, tt.data, err, tt.want)
+			}
+		}
+	}
+
+	err := ValidateAll(schema, map[string]any{"email": "ada", "tags": []any{"a", "b", "c"}})
+	issues, _ := err.(ValidationErrors)
+	want := []string{"Provide a valid work email", "required field is missing", "Pick at most 2 tags"}
+	if len(issues) != len(want) {
+		t.Fatalf("ValidateAll() = %v", err)
+	}
+	for i, issue := range issues {
+		if issue.Message != want[i] {
+			t.Errorf("ValidateAll() issue %s = %s, want %s", issue.Field, issue.Message, want[i])
+		}
+	}
+}
+
+func TestConstraintError(t *testing.T) {
+	err := String().Sensitive().Pattern(`^\d+$`).Validate("s3cret")
+	var ce *ConstraintError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Validate() error = %T, want *ConstraintError", err)
+	}
+	if ce.Keyword != "pattern" || ce.Limit != `^\d+$` || ce.Value != RedactedValue {
+		t.Errorf("ConstraintError = %+v", ce)
+	}
+
+	err = Object(map[string]Schema{"n": Number().Max(3)}).Validate(map[string]any{"n": 5})
+	if !errors.As(err, &ce) || ce.Keyword != "maximum" || ce.Limit != 3.0 || ce.Value != 5.0 {
+		t.Errorf("nested ConstraintError = %+v", ce)
+	}
+}
+
+func TestSetErrorMessages(t *testing.T) {
+	SetErrorMessages(func(err *ConstraintError) string {
+		switch err.Keyword {
+		case "minLength":
+			return fmt.Sprintf("Enter at least %v characters", err.Limit)
+		case "required":
+			return fmt.Sprintf("%s is required", err.Field)
+		}
+		return ""
+	})
+	defer SetErrorMessages(nil)
+
+	schema := Object(map[string]Schema{
+		"name": String().Min(2),
+		"code": String().Min(3).Message("Codes have {limit} letters"),
+	})
+	tests := []struct {
+		data any
+		want string
+	}{
+		{map[string]any{"code": "abc"}, "name is required"},
+		{map[string]any{"name": "A", "code": "abc"}, "field 'name': Enter at least 2 characters"},
+		{map[string]any{"name": "Ada", "code": "ab"}, "field 'code': Codes have 3 letters"},
+		{map[string]any{"name": 1, "code": "abc"}, "field 'name': expected string, got int"},
+	}
+	for _, tt := range tests {
+		if err := schema.Validate(tt.data); err == nil || err.Error() != tt.want {
+			t.Errorf("Validate(%v) = %v, want %s", tt.data, err, tt.want)
+		}
+	}
+}
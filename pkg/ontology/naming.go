@@ -0,0 +1,193 @@
+package ontology
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+// FieldNaming is the JSON field naming strategy used on the wire.
+// Ontologies are authored with camelCase property names; other strategies
+// rename properties at the server boundary and in generated clients.
+type FieldNaming string
+
+const (
+	// CamelCase sends property names exactly as authored (the default).
+	CamelCase FieldNaming = "camelCase"
+	// SnakeCase sends property names as snake_case (e.g. pageSize -> page_size).
+	SnakeCase FieldNaming = "snake_case"
+)
+
+// Apply converts an authored property name to its wire name.
+func (n FieldNaming) Apply(name string) string {
+	switch n {
+	case SnakeCase:
+		return toSnakeCase(name)
+	default:
+		return name
+	}
+}
+
+// IsIdentity reports whether the strategy leaves names unchanged.
+func (n FieldNaming) IsIdentity() bool {
+	return n == "" || n == CamelCase
+}
+
+// toSnakeCase converts camelCase or PascalCase to snake_case.
+// Acronyms are kept together: "userID" -> "user_id", "HTTPServer" -> "http_server".
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+					b.WriteByte('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ToWireNames renames the properties in data from authored names to wire
+// names, guided by schema. Keys not declared in the schema are left as-is.
+// Structs are converted to their JSON representation first.
+func ToWireNames(schema Schema, data any, naming FieldNaming) any {
+	if naming.IsIdentity() {
+		return data
+	}
+	return renameKeys(schema, toJSONValue(data), naming, false)
+}
+
+// FromWireNames renames the properties in data from wire names back to
+// authored names, guided by schema. Keys not declared in the schema are left as-is.
+func FromWireNames(schema Schema, data any, naming FieldNaming) any {
+	if naming.IsIdentity() {
+		return data
+	}
+	return renameKeys(schema, data, naming, true)
+}
+
+// renameKeys walks data alongside schema. By default it renames authored
+// names to wire names; reverse selects the wire -> authored direction.
+func renameKeys(schema Schema, data any, naming FieldNaming, reverse bool) any {
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		// Map from the key found in data to the authored property name
+		lookup := make(map[string]string, len(s.properties))
+		for name := range s.properties {
+			if reverse {
+				lookup[naming.Apply(name)] = name
+			} else {
+				lookup[name] = name
+			}
+		}
+		result := make(map[string]any, len(m))
+		for key, val := range m {
+			name, known := lookup[key]
+			if !known {
+				result[key] = val
+				continue
+			}
+			newKey := name
+			if !reverse {
+				newKey = naming.Apply(name)
+			}
+			result[newKey] = renameKeys(s.properties[name], val, naming, reverse)
+		}
+		return result
+	case *ArraySchema:
+		arr, ok := data.([]any)
+		if !ok {
+			return data
+		}
+		result := make([]any, len(arr))
+		for i, item := range arr {
+			result[i] = renameKeys(s.items, item, naming, reverse)
+		}
+		return result
+	case *NullableSchema:
+		if data == nil {
+			return nil
+		}
+		return renameKeys(s.inner, data, naming, reverse)
+	case *DiscriminatedUnionSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		tagKey := s.discriminator
+		if reverse {
+			tagKey = naming.Apply(s.discriminator)
+		}
+		tag, _ := m[tagKey].(string)
+		variant, ok := s.variants[tag]
+		if !ok {
+			return data
+		}
+		renamed, ok := renameKeys(variant, data, naming, reverse).(map[string]any)
+		if !ok {
+			return data
+		}
+		delete(renamed, tagKey)
+		if reverse {
+			renamed[s.discriminator] = tag
+		} else {
+			renamed[naming.Apply(s.discriminator)] = tag
+		}
+		return renamed
+	case *RefSchema:
+		if s.target == nil {
+			return data
+		}
+		return renameKeys(s.target, data, naming, reverse)
+	case *OneOfSchema:
+		// Use the first alternative the authored form of the data matches
+		for _, alt := range s.alternatives {
+			renamed := renameKeys(alt, data, naming, reverse)
+			authored := data
+			if reverse {
+				authored = renamed
+			}
+			if alt.Validate(authored) == nil {
+				return renamed
+			}
+		}
+		return data
+	case *AllOfSchema:
+		// Each schema renames the keys it declares
+		for _, part := range s.schemas {
+			data = renameKeys(part, data, naming, reverse)
+		}
+		return data
+	default:
+		return data
+	}
+}
+
+// toJSONValue converts arbitrary Go values (structs, typed slices) into the
+// generic map[string]any / []any form produced by encoding/json.
+func toJSONValue(data any) any {
+	if data == nil {
+		return nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	result, err := unmarshalPrecise(raw)
+	if err != nil {
+		return data
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package ontology
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldNamingApply(t *testing.T) {
+	tests := []struct {
+		naming FieldNaming
+		input  string
+		want   string
+	}{
+		{CamelCase, "pageSize", "pageSize"},
+		{SnakeCase, "pageSize", "page_size"},
+		{SnakeCase, "id", "id"},
+		{SnakeCase, "userID", "user_id"},
+		{SnakeCase, "HTTPServer", "http_server"},
+		{SnakeCase, "address2Line", "address2_line"},
+		{SnakeCase, "already_snake", "already_snake"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.naming.Apply(tt.input); got != tt.want {
+			t.Errorf("%s.Apply(%q) = %q, want %q", tt.naming, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestWireNamesRoundTrip(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"pageSize": Integer(),
+		"owner": Nullable(Object(map[string]Schema{
+			"firstName": String(),
+		})),
+		"lineItems": Array(Object(map[string]Schema{
+			"unitPrice": Number(),
+		})),
+		"metadata": Any(),
+	})
+
+	wire := map[string]any{
+		"page_size":  float64(10),
+		"owner":      map[string]any{"first_name": "Ada"},
+		"line_items": []any{map[string]any{"unit_price": 1.5}},
+		"metadata":   map[string]any{"freeFormKey": true},
+	}
+
+	authored := FromWireNames(schema, wire, SnakeCase)
+	want := map[string]any{
+		"pageSize":  float64(10),
+		"owner":     map[string]any{"firstName": "Ada"},
+		"lineItems": []any{map[string]any{"unitPrice": 1.5}},
+		"metadata":  map[string]any{"freeFormKey": true},
+	}
+	if !reflect.DeepEqual(authored, want) {
+		t.Fatalf("FromWireNames = %#v, want %#v", authored, want)
+	}
+
+	if back := ToWireNames(schema, authored, SnakeCase); !reflect.DeepEqual(back, wire) {
+		t.Errorf("ToWireNames = %#v, want %#v", back, wire)
+	}
+}
+
+func TestToWireNamesStruct(t *testing.T) {
+	type output struct {
+		PageSize int `json:"pageSize"`
+	}
+
+	schema := Object(map[string]Schema{"pageSize": Integer()})
+	got := ToWireNames(schema, output{PageSize: 5}, SnakeCase)
+
+	want := map[string]any{"page_size": float64(5)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToWireNames = %#v, want %#v", got, want)
+	}
+}
+
+func TestCamelCaseIsPassthrough(t *testing.T) {
+	data := map[string]any{"pageSize": 1}
+	schema := Object(map[string]Schema{"pageSize": Integer()})
+
+	if got := FromWireNames(schema, data, CamelCase); !reflect.DeepEqual(got, data) {
+		t.Errorf("FromWireNames with CamelCase should not modify data, got %#v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package ontology
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// patternProperty validates the values of the keys matching a pattern.
+type patternProperty struct {
+	pattern *regexp.Regexp
+	schema  Schema
+}
+
+// MinProperties sets the minimum number of properties the object has,
+// declared or not, e.g. for config-style maps. Pick, Omit and Partial drop
+// the property counts, since they change the declared properties.
+func (o *ObjectSchema) MinProperties(min int) *ObjectSchema {
+	o.constrain("minProperties")
+	o.minProperties = &min
+	return o
+}
+
+// MaxProperties sets the maximum number of properties the object has.
+func (o *ObjectSchema) MaxProperties(max int) *ObjectSchema {
+	o.constrain("maxProperties")
+	o.maxProperties = &max
+	return o
+}
+
+// PatternProperties validates the value of every key matching pattern (a
+// regular expression, unanchored as in JSON Schema) against schema, e.g.
+// PatternProperties("^x-", String()) for extension fields. Declared
+// properties whose names match must satisfy both schemas. Keys matching a
+// pattern are not unknown to Strict objects. It panics if pattern doesn't
+// compile.
+func (o *ObjectSchema) PatternProperties(pattern string, schema Schema) *ObjectSchema {
+	o.patterns = append(o.patterns, patternProperty{regexp.MustCompile(pattern), schema})
+	return o
+}
+
+// PropertyCounts returns the minimum and maximum number of properties, if
+// set.
+func (o *ObjectSchema) PropertyCounts() (min, max *int) {
+	return o.minProperties, o.maxProperties
+}
+
+// PatternSchemas returns the schemas of the PatternProperties by pattern.
+func (o *ObjectSchema) PatternSchemas() map[string]Schema {
+	if len(o.patterns) == 0 {
+		return nil
+	}
+	schemas := make(map[string]Schema, len(o.patterns))
+	for _, p := range o.patterns {
+		schemas[p.pattern.String()] = p.schema
+	}
+	return schemas
+}
+
+// matchesPattern reports whether key matches one of the PatternProperties.
+func (o *ObjectSchema) matchesPattern(key string) bool {
+	for _, p := range o.patterns {
+		if p.pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKeyConstraints reports whether the object constrains its keys beyond
+// the declared properties.
+func (o *ObjectSchema) hasKeyConstraints() bool {
+	return o.minProperties != nil || o.maxProperties != nil || len(o.patterns) > 0
+}
+
+// checkKeys checks the property counts and pattern properties of m.
+func (o *ObjectSchema) checkKeys(m map[string]any) error {
+	if o.minProperties != nil && len(m) < *o.minProperties {
+		return o.message(constraintError("minProperties", *o.minProperties, len(m), "object has %d properties, minimum is %d", len(m), *o.minProperties))
+	}
+	if o.maxProperties != nil && len(m) > *o.maxProperties {
+		return o.message(constraintError("maxProperties", *o.maxProperties, len(m), "object has %d properties, maximum is %d", len(m), *o.maxProperties))
+	}
+	if len(o.patterns) == 0 {
+		return nil
+	}
+	for _, key := range sortedKeys(m) {
+		for _, p := range o.patterns {
+			if !p.pattern.MatchString(key) {
+				continue
+			}
+			if err := p.schema.Validate(m[key]); err != nil {
+				return fmt.Errorf("field '%s': %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkStructKeys checks the property counts and pattern properties of a
+// struct against its JSON form.
+func (o *ObjectSchema) checkStructKeys(data any) error {
+	if !o.hasKeyConstraints() {
+		return nil
+	}
+	m, ok := toJSONValue(data).(map[string]any)
+	if !ok {
+		return nil
+	}
+	return o.checkKeys(m)
+}
+
+// patternsJSONSchema returns the patternProperties keyword value.
+func (o *ObjectSchema) patternsJSONSchema() map[string]any {
+	patterns := make(map[string]any, len(o.patterns))
+	for _, p := range o.patterns {
+		patterns[p.pattern.String()] = p.schema.JSONSchema()
+	}
+	return patterns
+}
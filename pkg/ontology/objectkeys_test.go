@@ -0,0 +1,80 @@
+package ontology
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func labelsSchema() *ObjectSchema {
+	return Object(map[string]Schema{"name": String()}).
+		MinProperties(2).
+		MaxProperties(3).
+		PatternProperties("^x-", String().Max(5)).
+		Strict()
+}
+
+func TestObjectKeyConstraints(t *testing.T) {
+	schema := labelsSchema()
+	validate := Compile(schema)
+
+	tests := []struct {
+		name    string
+		data    map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"name": "a", "x-env": "prod"}, ""},
+		{"too few", map[string]any{"name": "a"}, "object has 1 properties, minimum is 2"},
+		{"too many", map[string]any{"name": "a", "x-a": "1", "x-b": "2", "x-c": "3"}, "object has 4 properties, maximum is 3"},
+		{"pattern value", map[string]any{"name": "a", "x-env": "production"}, "field 'x-env': string length 10"},
+		{"unknown", map[string]any{"name": "a", "env": "prod"}, "unknown field 'env'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, err := range []error{schema.Validate(tt.data), validate(tt.data)} {
+				if tt.wantErr == "" && err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+			}
+		})
+	}
+
+	type labels struct {
+		Name string `json:"name"`
+	}
+	if err := schema.Validate(labels{Name: "a"}); err == nil || !strings.Contains(err.Error(), "minimum is 2") {
+		t.Errorf("structs should be counted by their JSON form, got %v", err)
+	}
+	err := ValidateAll(schema, map[string]any{"name": "a", "x-a": "too long", "x-b": "ok", "x-c": 1})
+	if issues, ok := err.(ValidationErrors); !ok || len(issues) != 3 || issues[0].Field != "" || issues[1].Field != "/x-a" || issues[2].Field != "/x-c" {
+		t.Errorf("ValidateAll() = %v", err)
+	}
+	if err := RejectUnknownFields(Object(map[string]Schema{}).PatternProperties("^x-", Object(map[string]Schema{})),
+		map[string]any{"x-a": map[string]any{"b": 1}}); err == nil {
+		t.Error("RejectUnknownFields should check pattern property values")
+	}
+}
+
+func TestObjectKeyConstraintsJSONSchema(t *testing.T) {
+	js := labelsSchema().JSONSchema()
+	if js["minProperties"] != 2 || js["maxProperties"] != 3 {
+		t.Errorf("property counts = %v, %v", js["minProperties"], js["maxProperties"])
+	}
+	patterns := js["patternProperties"].(map[string]any)
+	if !reflect.DeepEqual(patterns["^x-"], map[string]any{"type": "string", "maxLength": 5}) {
+		t.Errorf("patternProperties = %v", patterns)
+	}
+
+	data, _ := json.Marshal(js)
+	imported, err := ParseJSONSchema(data)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+	if again, _ := json.Marshal(imported.JSONSchema()); string(again) != string(data) {
+		t.Errorf("round trip = %s, want %s", again, data)
+	}
+}
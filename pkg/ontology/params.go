@@ -0,0 +1,141 @@
+package ontology
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CallInputs returns the schema of the input a resolver receives: Inputs
+// with the Headers and Query properties added. It is Inputs when the
+// function declares neither.
+func (f *Function) CallInputs() Schema {
+	if f.Headers == nil && f.Query == nil {
+		return f.Inputs
+	}
+	inputs, ok := f.Inputs.(*ObjectSchema)
+	if !ok {
+		// Rejected by Config.Validate
+		return f.Inputs
+	}
+	if f.Headers != nil {
+		inputs = inputs.Extend(f.Headers)
+	}
+	if f.Query != nil {
+		inputs = inputs.Extend(f.Query)
+	}
+	return inputs
+}
+
+// IsParam reports whether name is a Headers or Query property, which REST
+// callers must send outside the body.
+func (f *Function) IsParam(name string) bool {
+	_, header := paramProperties(f.Headers)[name]
+	_, query := paramProperties(f.Query)[name]
+	return header || query
+}
+
+// paramProperties returns the properties of a Headers or Query schema,
+// which may be nil.
+func paramProperties(o *ObjectSchema) map[string]Schema {
+	if o == nil {
+		return nil
+	}
+	return o.properties
+}
+
+// validateParams checks a function's Headers and Query: inputs must be an
+// object, names may not be shared with it or each other, and values must
+// have a plain text form (query parameters may also repeat, as arrays).
+func validateParams(fn Function) error {
+	if fn.Headers == nil && fn.Query == nil {
+		return nil
+	}
+	inputs, ok := fn.Inputs.(*ObjectSchema)
+	if !ok {
+		return fmt.Errorf("headers and query need object inputs")
+	}
+	headers, query := paramProperties(fn.Headers), paramProperties(fn.Query)
+	for _, name := range sortedKeys(headers) {
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name '%s'", name)
+		}
+		if _, ok := inputs.properties[name]; ok {
+			return fmt.Errorf("header '%s' is also an input field", name)
+		}
+		if !textParam(headers[name]) {
+			return fmt.Errorf("header '%s' must be a string, number, integer or boolean", name)
+		}
+	}
+	for _, name := range sortedKeys(query) {
+		if _, ok := inputs.properties[name]; ok {
+			return fmt.Errorf("query parameter '%s' is also an input field", name)
+		}
+		if _, ok := headers[name]; ok {
+			return fmt.Errorf("query parameter '%s' is also a header", name)
+		}
+		schema := query[name]
+		if arr, ok := schema.(*ArraySchema); ok {
+			schema = arr.items
+		}
+		if !textParam(schema) {
+			return fmt.Errorf("query parameter '%s' must be a string, number, integer or boolean, or an array of them", name)
+		}
+	}
+	return nil
+}
+
+// textParam reports whether a header or query value can have schema.
+func textParam(schema Schema) bool {
+	switch schema.(type) {
+	case *StringSchema, *NumberSchema, *BooleanSchema:
+		return true
+	}
+	return false
+}
+
+// headerNameChars are the characters of an HTTP header name (a token).
+const headerNameChars = "!#$%&'*+-.^_`|~0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// validHeaderName reports whether name is a valid HTTP header name.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !strings.ContainsRune(headerNameChars, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestParams reads a function's Headers and Query properties from r.
+// Values are converted to the declared types where they parse (see
+// Coerce); repeated query parameters become arrays. Missing values are
+// left out, so validation reports required ones as missing.
+func (f *Function) RequestParams(r *http.Request) map[string]any {
+	params := make(map[string]any)
+	for name := range paramProperties(f.Headers) {
+		if values := r.Header.Values(name); len(values) > 0 {
+			params[name] = values[0]
+		}
+	}
+	query := r.URL.Query()
+	for name, schema := range paramProperties(f.Query) {
+		values, ok := query[name]
+		if !ok {
+			continue
+		}
+		if _, isArray := schema.(*ArraySchema); isArray {
+			items := make([]any, len(values))
+			for i, v := range values {
+				items[i] = v
+			}
+			params[name] = items
+		} else {
+			params[name] = values[0]
+		}
+	}
+	return Coerce(f.CallInputs(), params).(map[string]any)
+}
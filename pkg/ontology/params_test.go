@@ -0,0 +1,115 @@
+package ontology
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func paramsFunction() Function {
+	return Function{
+		Description: "List orders",
+		Access:      []string{"public"},
+		Inputs:      Object(map[string]Schema{"status": String()}),
+		Outputs:     Object(map[string]Schema{}),
+		Headers:     Object(map[string]Schema{"X-Tenant-Id": String().UUID()}),
+		Query: Object(map[string]Schema{
+			"page": Integer().Min(1),
+			"tags": Array(String()),
+		}).Optional("page", "tags"),
+	}
+}
+
+func TestRequestParams(t *testing.T) {
+	fn := paramsFunction()
+	r := httptest.NewRequest("POST", "/api/listOrders?page=2&tags=a&tags=b", nil)
+	r.Header.Set("X-Tenant-Id", "123e4567-e89b-12d3-a456-426614174000")
+
+	params := fn.RequestParams(r)
+	want := map[string]any{
+		"X-Tenant-Id": "123e4567-e89b-12d3-a456-426614174000",
+		"page":        float64(2),
+		"tags":        []any{"a", "b"},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("RequestParams = %v, want %v", params, want)
+	}
+
+	params["status"] = "open"
+	if err := fn.ValidateInput(params); err != nil {
+		t.Errorf("ValidateInput failed: %v", err)
+	}
+	delete(params, "X-Tenant-Id")
+	if err := fn.ValidateInput(params); err == nil || !strings.Contains(err.Error(), "X-Tenant-Id") {
+		t.Errorf("expected missing header error, got %v", err)
+	}
+
+	if !fn.IsParam("page") || !fn.IsParam("X-Tenant-Id") || fn.IsParam("status") {
+		t.Error("IsParam should match header and query properties only")
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(fn *Function)
+		wantErr string
+	}{
+		{"valid", func(fn *Function) {}, ""},
+		{"input collision", func(fn *Function) {
+			fn.Query = Object(map[string]Schema{"status": String()})
+		}, "query parameter 'status' is also an input field"},
+		{"header and query", func(fn *Function) {
+			fn.Query = Object(map[string]Schema{"X-Tenant-Id": String()})
+		}, "is also a header"},
+		{"header name", func(fn *Function) {
+			fn.Headers = Object(map[string]Schema{"tenant id": String()})
+		}, "invalid header name"},
+		{"object header", func(fn *Function) {
+			fn.Headers = Object(map[string]Schema{"X-Filter": Object(map[string]Schema{})})
+		}, "must be a string, number, integer or boolean"},
+		{"array header", func(fn *Function) {
+			fn.Headers = Object(map[string]Schema{"X-Tags": Array(String())})
+		}, "must be a string, number, integer or boolean"},
+		{"non-object inputs", func(fn *Function) {
+			fn.Inputs = String()
+		}, "headers and query need object inputs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := refConfig()
+			fn := paramsFunction()
+			tt.modify(&fn)
+			config.Functions["listOrders"] = fn
+
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestParamsInLock(t *testing.T) {
+	config := refConfig()
+	config.Functions["listOrders"] = paramsFunction()
+	before := config.Hash()
+
+	fn := config.Functions["listOrders"]
+	fn.Headers = Object(map[string]Schema{"X-Tenant-Id": String()})
+	config.Functions["listOrders"] = fn
+	if config.Hash() == before {
+		t.Error("hash should change when headers change")
+	}
+	shape := config.ExtractSnapshot().Functions["listOrders"]
+	if shape.HeadersSchema == nil || shape.QuerySchema == nil {
+		t.Errorf("snapshot should record headers and query: %+v", shape)
+	}
+}
@@ -0,0 +1,421 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pipelineInputRoot names the pipeline's own input in step mappings.
+const pipelineInputRoot = "input"
+
+// Pipeline chains existing functions so common multi-step operations need
+// no resolver code. Each step's input is mapped from the pipeline's input
+// and the outputs of earlier steps; the output of the last step that ran is
+// the pipeline's output. Mappings are checked for schema compatibility by
+// Config.Validate and pipelines are recorded in the lock file.
+type Pipeline struct {
+	Steps []PipelineStep `json:"steps"`
+}
+
+// PipelineStep calls one function.
+type PipelineStep struct {
+	// Name refers to this step's output in later mappings. Defaults to Function.
+	Name     string `json:"name,omitempty"`
+	Function string `json:"function"`
+	// Input maps the step's input fields to dotted paths rooted at "input"
+	// (the pipeline's input) or an earlier step's name, e.g.
+	// {"customerId": "lookup.customer.id"}, or to expressions over them
+	// (see ParseExpr), e.g. {"limit": "coalesce(input.limit, 20)"}. Mappings
+	// that evaluate to null leave the field unset. Plain paths are checked
+	// for schema compatibility; other expressions are checked at runtime by
+	// the step's input validation.
+	Input map[string]string `json:"input,omitempty"`
+	// When makes the step conditional on an expression (see ParseExpr) over
+	// the pipeline's input and earlier outputs, e.g. "input.amount > 1000".
+	// Skipped steps have no output, so later mappings from them may be missing.
+	When string `json:"when,omitempty"`
+}
+
+// StepName returns the name later steps use to refer to this step's output.
+func (s PipelineStep) StepName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Function
+}
+
+// runPipeline calls each step in order through Call, so every step's access
+// groups and validation apply to the caller.
+func (c *Config) runPipeline(ctx Context, p *Pipeline, input any) (any, error) {
+	values := map[string]any{pipelineInputRoot: toJSONValue(input)}
+
+	var output any
+	ran := false
+	for _, step := range p.Steps {
+		if step.When != "" {
+			when, err := ParseExpr(step.When)
+			if err != nil {
+				return nil, fmt.Errorf("step '%s': %w", step.StepName(), err)
+			}
+			run, err := when.EvalBool(values)
+			if err != nil {
+				return nil, fmt.Errorf("step '%s': %w", step.StepName(), err)
+			}
+			if !run {
+				continue
+			}
+		}
+
+		stepInput := make(map[string]any, len(step.Input))
+		for _, field := range sortedKeys(step.Input) {
+			mapping, err := ParseExpr(step.Input[field])
+			if err != nil {
+				return nil, fmt.Errorf("step '%s' input '%s': %w", step.StepName(), field, err)
+			}
+			v, err := mapping.Eval(values)
+			if err != nil {
+				return nil, fmt.Errorf("step '%s' input '%s': %w", step.StepName(), field, err)
+			}
+			if v != nil {
+				stepInput[field] = v
+			}
+		}
+
+		result, err := c.Call(ctx, step.Function, stepInput)
+		if err != nil {
+			return nil, fmt.Errorf("step '%s': %w", step.StepName(), err)
+		}
+		output = toJSONValue(result)
+		values[step.StepName()] = output
+		ran = true
+	}
+	if !ran {
+		return nil, fmt.Errorf("no pipeline step ran")
+	}
+	return output, nil
+}
+
+// validatePipeline checks that every step calls a known function and that
+// each mapping feeds a step input a value of a compatible shape.
+func (c *Config) validatePipeline(name string, fn Function) error {
+	p := fn.Pipeline
+	if fn.Resolver != nil {
+		return fmt.Errorf("function '%s': a pipeline can't also have a resolver", name)
+	}
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("function '%s': pipeline has no steps", name)
+	}
+
+	scope := &exprScope{
+		schemas:     map[string]Schema{pipelineInputRoot: fn.Inputs},
+		conditional: map[string]bool{},
+		roots:       "the pipeline input or an earlier step",
+	}
+	for i, step := range p.Steps {
+		stepName := step.StepName()
+		target, ok := c.Functions[step.Function]
+		if !ok {
+			return fmt.Errorf("function '%s' step %d calls unknown function '%s'", name, i, step.Function)
+		}
+		if c.pipelineReaches(step.Function, name, map[string]bool{}) {
+			return fmt.Errorf("function '%s' step '%s' calls back into '%s'", name, stepName, name)
+		}
+		if _, dup := scope.schemas[stepName]; dup {
+			return fmt.Errorf("function '%s': step name '%s' is already in use", name, stepName)
+		}
+		if err := scope.checkCondition(step.When); err != nil {
+			return fmt.Errorf("function '%s' step '%s': %w", name, stepName, err)
+		}
+		if err := scope.checkStepInput(step, target); err != nil {
+			return fmt.Errorf("function '%s' step '%s': %w", name, stepName, err)
+		}
+		scope.schemas[stepName] = target.Outputs
+		scope.conditional[stepName] = step.When != ""
+	}
+
+	// Any step from the last unconditional one onwards may produce the output
+	for i := len(p.Steps) - 1; i >= 0; i-- {
+		step := p.Steps[i]
+		if err := assignable(c.Functions[step.Function].Outputs, fn.Outputs); err != nil {
+			return fmt.Errorf("function '%s': output of step '%s' doesn't match the pipeline's outputs: %w", name, step.StepName(), err)
+		}
+		if step.When == "" {
+			break
+		}
+	}
+	return nil
+}
+
+// exprScope tracks the values an expression can refer to, such as a
+// pipeline's input and earlier step outputs.
+type exprScope struct {
+	schemas map[string]Schema
+	// conditional marks roots that may be absent, e.g. steps that may not have run
+	conditional map[string]bool
+	// roots describes the valid roots for error messages
+	roots string
+}
+
+// checkCondition checks that a step condition parses and only reads known fields.
+func (s *exprScope) checkCondition(when string) error {
+	if when == "" {
+		return nil
+	}
+	if err := s.checkExpr(when); err != nil {
+		return fmt.Errorf("condition: %w", err)
+	}
+	return nil
+}
+
+// checkExpr checks that an expression parses and only reads known fields.
+func (s *exprScope) checkExpr(source string) error {
+	expr, err := ParseExpr(source)
+	if err != nil {
+		return err
+	}
+	for _, path := range expr.Paths() {
+		if _, _, err := s.lookup(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pipelineReaches reports whether calling from can lead back to target
+// through nested pipelines.
+func (c *Config) pipelineReaches(from, target string, visited map[string]bool) bool {
+	if from == target {
+		return true
+	}
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	fn := c.Functions[from]
+	if fn.Pipeline == nil {
+		return false
+	}
+	for _, step := range fn.Pipeline.Steps {
+		if c.pipelineReaches(step.Function, target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStepInput checks a step's mappings against its function's inputs.
+func (s *exprScope) checkStepInput(step PipelineStep, target Function) error {
+	obj, ok := Deref(target.Inputs).(*ObjectSchema)
+	if !ok {
+		return fmt.Errorf("function '%s' inputs must be an object", step.Function)
+	}
+
+	for _, field := range sortedKeys(step.Input) {
+		dst, ok := obj.properties[field]
+		if !ok {
+			return fmt.Errorf("function '%s' has no input '%s'", step.Function, field)
+		}
+		mapping, err := ParseExpr(step.Input[field])
+		if err != nil {
+			return fmt.Errorf("input '%s': %w", field, err)
+		}
+		if !mapping.IsPath() {
+			if err := s.checkExpr(step.Input[field]); err != nil {
+				return fmt.Errorf("input '%s': %w", field, err)
+			}
+			continue
+		}
+		src, optional, err := s.lookup(step.Input[field])
+		if err != nil {
+			return fmt.Errorf("input '%s': %w", field, err)
+		}
+		if optional && contains(obj.required, field) {
+			return fmt.Errorf("input '%s' is required but '%s' may be missing", field, step.Input[field])
+		}
+		if err := assignable(src, dst); err != nil {
+			return fmt.Errorf("input '%s': %w", field, err)
+		}
+	}
+
+	for _, field := range obj.required {
+		if _, mapped := step.Input[field]; !mapped {
+			return fmt.Errorf("required input '%s' is not mapped", field)
+		}
+	}
+	return nil
+}
+
+// lookup resolves the schema of a path. optional reports whether the value
+// may be absent because the path starts at a conditional root or crosses an
+// optional or nullable field. Paths into Any values are unchecked.
+func (s *exprScope) lookup(path string) (schema Schema, optional bool, err error) {
+	parts := strings.Split(path, ".")
+	schema, ok := s.schemas[parts[0]]
+	if !ok {
+		return nil, false, fmt.Errorf("'%s' is not %s", parts[0], s.roots)
+	}
+	optional = s.conditional[parts[0]]
+
+	for _, part := range parts[1:] {
+		schema = Deref(schema)
+		if _, ok := schema.(*AnySchema); ok {
+			return schema, true, nil
+		}
+		if n, ok := schema.(*NullableSchema); ok {
+			optional = true
+			schema = Deref(n.inner)
+		}
+		obj, ok := schema.(*ObjectSchema)
+		if !ok {
+			return nil, false, fmt.Errorf("'%s': %s has no fields", path, schema.TypeName())
+		}
+		prop, ok := obj.properties[part]
+		if !ok {
+			return nil, false, fmt.Errorf("'%s': unknown field '%s'", path, part)
+		}
+		if !contains(obj.required, part) {
+			optional = true
+		}
+		schema = prop
+	}
+	return schema, optional, nil
+}
+
+// assignable reports whether every value of schema from also has the shape
+// of schema to. Constraints such as lengths, ranges and formats are left to
+// runtime validation.
+func assignable(from, to Schema) error {
+	return checkAssignable(from, to, map[[2]Schema]bool{})
+}
+
+func checkAssignable(from, to Schema, seen map[[2]Schema]bool) error {
+	from, to = Deref(from), Deref(to)
+	if from == to {
+		return nil
+	}
+	// Recursive schemas: assume compatibility while comparing a pair again
+	key := [2]Schema{from, to}
+	if seen[key] {
+		return nil
+	}
+	seen[key] = true
+
+	switch t := to.(type) {
+	case *AnySchema:
+		return nil
+	case *NullableSchema:
+		if f, ok := from.(*NullableSchema); ok {
+			return checkAssignable(f.inner, t.inner, seen)
+		}
+		return checkAssignable(from, t.inner, seen)
+	case *OneOfSchema:
+		for _, alt := range t.alternatives {
+			if checkAssignable(from, alt, seen) == nil {
+				return nil
+			}
+		}
+	case *AllOfSchema:
+		for i, part := range t.schemas {
+			if err := checkAssignable(from, part, seen); err != nil {
+				return fmt.Errorf("schema %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+
+	switch f := from.(type) {
+	case *NullableSchema:
+		return fmt.Errorf("%s may be null", f.TypeName())
+	case *OneOfSchema:
+		for i, alt := range f.alternatives {
+			if err := checkAssignable(alt, to, seen); err != nil {
+				return fmt.Errorf("alternative %d: %w", i, err)
+			}
+		}
+		return nil
+	case *AllOfSchema:
+		// Objects combine their fields; otherwise one schema must fit
+		if merged, ok := f.mergedObject(); ok {
+			return checkAssignable(merged, to, seen)
+		}
+		for _, part := range f.schemas {
+			if checkAssignable(part, to, seen) == nil {
+				return nil
+			}
+		}
+	case *DiscriminatedUnionSchema:
+		t, ok := to.(*DiscriminatedUnionSchema)
+		if !ok || t.discriminator != f.discriminator {
+			break
+		}
+		for _, tag := range f.Tags() {
+			variant, ok := t.variants[tag]
+			if !ok {
+				return fmt.Errorf("variant '%s' is not accepted", tag)
+			}
+			if err := checkAssignable(f.variants[tag], variant, seen); err != nil {
+				return fmt.Errorf("variant '%s': %w", tag, err)
+			}
+		}
+		return nil
+	}
+
+	switch t := to.(type) {
+	case *StringSchema:
+		if _, ok := from.(*StringSchema); ok {
+			return nil
+		}
+	case *NumberSchema:
+		if f, ok := from.(*NumberSchema); ok && (f.isInteger || !t.isInteger) {
+			return nil
+		}
+	case *BooleanSchema:
+		if _, ok := from.(*BooleanSchema); ok {
+			return nil
+		}
+	case *BytesSchema:
+		if _, ok := from.(*BytesSchema); ok {
+			return nil
+		}
+	case *ArraySchema:
+		if f, ok := from.(*ArraySchema); ok {
+			if err := checkAssignable(f.items, t.items, seen); err != nil {
+				return fmt.Errorf("items: %w", err)
+			}
+			return nil
+		}
+	case *ObjectSchema:
+		if f, ok := from.(*ObjectSchema); ok {
+			return checkObjectAssignable(f, t, seen)
+		}
+	}
+	return fmt.Errorf("%s is not compatible with %s", from.TypeName(), to.TypeName())
+}
+
+func checkObjectAssignable(from, to *ObjectSchema, seen map[[2]Schema]bool) error {
+	if to.strict {
+		for _, name := range sortedKeys(from.properties) {
+			if _, ok := to.properties[name]; !ok {
+				return fmt.Errorf("field '%s' is not accepted", name)
+			}
+		}
+	}
+	for _, name := range sortedKeys(to.properties) {
+		required := contains(to.required, name)
+		fromProp, ok := from.properties[name]
+		if !ok {
+			if required {
+				return fmt.Errorf("field '%s' is missing", name)
+			}
+			continue
+		}
+		if required && !contains(from.required, name) {
+			return fmt.Errorf("field '%s' is required but may be missing", name)
+		}
+		if err := checkAssignable(fromProp, to.properties[name], seen); err != nil {
+			return fmt.Errorf("field '%s': %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func pipelineConfig(pipeline Function) *Config {
+	pipeline.Description = "Notify a customer's owner"
+	pipeline.Access = []string{"support"}
+	return &Config{
+		Name: "test",
+		AccessGroups: map[string]AccessGroup{
+			"support": {Description: "Support"},
+			"admin":   {Description: "Admins"},
+		},
+		Entities: map[string]Entity{},
+		Functions: map[string]Function{
+			"getCustomer": {
+				Description: "Get a customer",
+				Access:      []string{"support"},
+				Inputs:      Object(map[string]Schema{"email": String().Email()}),
+				Outputs: Object(map[string]Schema{
+					"id":      String(),
+					"ownerId": Nullable(String()),
+					"visits":  Integer(),
+					"note":    String(),
+				}).Optional("note"),
+				Resolver: func(ctx Context, input any) (any, error) {
+					return map[string]any{"id": "c-1", "ownerId": "u-7", "visits": 3}, nil
+				},
+			},
+			"sendMessage": {
+				Description: "Send a message",
+				Access:      []string{"support"},
+				Inputs: Object(map[string]Schema{
+					"to":   String(),
+					"body": String(),
+				}).Optional("body"),
+				Outputs: Object(map[string]Schema{"sent": Boolean(), "to": String()}),
+				Resolver: func(ctx Context, input any) (any, error) {
+					return map[string]any{"sent": true, "to": input.(map[string]any)["to"]}, nil
+				},
+			},
+			"deleteCustomer": {
+				Description: "Delete a customer",
+				Access:      []string{"admin"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Object(map[string]Schema{"sent": Boolean()}),
+				Resolver: func(ctx Context, input any) (any, error) {
+					return map[string]any{"sent": false}, nil
+				},
+			},
+			"pipeline": pipeline,
+		},
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	config := pipelineConfig(Function{
+		Inputs:  Object(map[string]Schema{"email": String(), "message": String()}),
+		Outputs: Object(map[string]Schema{"sent": Boolean()}),
+		Pipeline: &Pipeline{Steps: []PipelineStep{
+			{Name: "customer", Function: "getCustomer", Input: map[string]string{"email": "input.email"}},
+			{Function: "sendMessage", Input: map[string]string{"to": "customer.id", "body": "input.message"}},
+		}},
+	})
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	output, err := config.Call(ctx, "pipeline", map[string]any{"email": "ada@example.com", "message": "hi"})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if m := output.(map[string]any); m["sent"] != true || m["to"] != "c-1" {
+		t.Errorf("output = %v", output)
+	}
+
+	if shape := config.ExtractSnapshot().Functions["pipeline"]; shape.Pipeline == nil || len(shape.Pipeline.Steps) != 2 {
+		t.Error("lock snapshot should record the pipeline")
+	}
+}
+
+func TestPipelineStepAccess(t *testing.T) {
+	config := pipelineConfig(Function{
+		Inputs:  Object(map[string]Schema{"email": String()}),
+		Outputs: Object(map[string]Schema{"sent": Boolean()}),
+		Pipeline: &Pipeline{Steps: []PipelineStep{
+			{Name: "customer", Function: "getCustomer", Input: map[string]string{"email": "input.email"}},
+			{Function: "deleteCustomer", Input: map[string]string{"id": "customer.id"}},
+		}},
+	})
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	_, err := config.Call(ctx, "pipeline", map[string]any{"email": "ada@example.com"})
+	if err == nil || !strings.Contains(err.Error(), "step 'deleteCustomer': function 'deleteCustomer': access denied") {
+		t.Errorf("Call error = %v, want step access denied", err)
+	}
+}
+
+func TestPipelineValidation(t *testing.T) {
+	inputs := Object(map[string]Schema{"email": String()})
+	outputs := Object(map[string]Schema{"sent": Boolean()})
+	customer := PipelineStep{Name: "customer", Function: "getCustomer", Input: map[string]string{"email": "input.email"}}
+
+	tests := []struct {
+		name    string
+		fn      Function
+		wantErr string
+	}{
+		{
+			name:    "unknown function",
+			fn:      Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{{Function: "missing"}}}},
+			wantErr: "step 0 calls unknown function 'missing'",
+		},
+		{
+			name: "unmapped required input",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				customer, {Function: "sendMessage"},
+			}}},
+			wantErr: "step 'sendMessage': required input 'to' is not mapped",
+		},
+		{
+			name: "incompatible types",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				customer, {Function: "sendMessage", Input: map[string]string{"to": "customer.visits"}},
+			}}},
+			wantErr: "input 'to': integer is not compatible with string",
+		},
+		{
+			name: "nullable into required",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				customer, {Function: "sendMessage", Input: map[string]string{"to": "customer.ownerId"}},
+			}}},
+			wantErr: "input 'to': string | null may be null",
+		},
+		{
+			name: "optional into required",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				customer, {Function: "sendMessage", Input: map[string]string{"to": "customer.note"}},
+			}}},
+			wantErr: "input 'to' is required but 'customer.note' may be missing",
+		},
+		{
+			name: "later step",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				{Function: "sendMessage", Input: map[string]string{"to": "customer.id"}}, customer,
+			}}},
+			wantErr: "'customer' is not the pipeline input or an earlier step",
+		},
+		{
+			name: "output mismatch",
+			fn: Function{Inputs: inputs, Outputs: Object(map[string]Schema{"count": Integer()}), Pipeline: &Pipeline{Steps: []PipelineStep{
+				customer,
+			}}},
+			wantErr: "output of step 'customer' doesn't match the pipeline's outputs: field 'count' is missing",
+		},
+		{
+			name: "self reference",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				{Function: "pipeline", Input: map[string]string{"email": "input.email"}},
+			}}},
+			wantErr: "calls back into 'pipeline'",
+		},
+		{
+			name: "condition on unknown field",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				customer,
+				{Function: "sendMessage", When: "customer.tier == 'gold'", Input: map[string]string{"to": "customer.id"}},
+			}}},
+			wantErr: "condition: 'customer.tier': unknown field 'tier'",
+		},
+		{
+			name: "mapping from conditional step",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				{Name: "customer", Function: "getCustomer", When: "input.email != ''", Input: map[string]string{"email": "input.email"}},
+				{Function: "sendMessage", Input: map[string]string{"to": "customer.id"}},
+			}}},
+			wantErr: "input 'to' is required but 'customer.id' may be missing",
+		},
+		{
+			name: "conditional last step output",
+			fn: Function{Inputs: inputs, Outputs: outputs, Pipeline: &Pipeline{Steps: []PipelineStep{
+				{Name: "notify", Function: "sendMessage", Input: map[string]string{"to": "input.email"}},
+				{Name: "customer", Function: "getCustomer", When: "input.email == 'x'", Input: map[string]string{"email": "input.email"}},
+			}}},
+			wantErr: "output of step 'customer' doesn't match",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pipelineConfig(tt.fn).Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssignableRecursive(t *testing.T) {
+	config := &Config{Schemas: map[string]Schema{
+		"A": Object(map[string]Schema{"children": Array(Ref("A"))}),
+		"B": Object(map[string]Schema{"children": Array(Ref("B"))}),
+	}}
+	if err := config.resolveRefs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := assignable(config.Schemas["A"], config.Schemas["B"]); err != nil {
+		t.Errorf("structurally equal recursive schemas should be assignable: %v", err)
+	}
+}
+
+func TestPipelineBranching(t *testing.T) {
+	config := pipelineConfig(Function{
+		Inputs: Object(map[string]Schema{"email": String(), "urgent": Boolean()}),
+		// The customer lookup's output is the result if neither branch runs
+		Outputs: Object(map[string]Schema{"sent": Boolean(), "to": String()}).Optional("sent", "to"),
+		Pipeline: &Pipeline{Steps: []PipelineStep{
+			{Name: "customer", Function: "getCustomer", Input: map[string]string{"email": "input.email"}},
+			{Name: "owner", Function: "sendMessage", When: "input.urgent && customer.ownerId != null", Input: map[string]string{"to": "customer.ownerId"}},
+			{Name: "queue", Function: "sendMessage", When: "!input.urgent", Input: map[string]string{"to": "customer.id"}},
+		}},
+	})
+	// ownerId is nullable, so mapping it into a required input is rejected
+	if err := config.Validate(); err == nil {
+		t.Fatal("nullable mapping into a required input should be rejected")
+	}
+
+	fn := config.Functions["pipeline"]
+	fn.Pipeline.Steps[1].Input = map[string]string{"to": "input.email"}
+	config.Functions["pipeline"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	tests := []struct {
+		urgent bool
+		wantTo string
+	}{
+		{urgent: true, wantTo: "ada@example.com"},
+		{urgent: false, wantTo: "c-1"},
+	}
+	for _, tt := range tests {
+		output, err := config.Call(ctx, "pipeline", map[string]any{"email": "ada@example.com", "urgent": tt.urgent})
+		if err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if got := output.(map[string]any)["to"]; got != tt.wantTo {
+			t.Errorf("urgent=%v: message sent to %v, want %v", tt.urgent, got, tt.wantTo)
+		}
+	}
+}
+
+func TestPipelineExpressionMappings(t *testing.T) {
+	config := pipelineConfig(Function{
+		Inputs:  Object(map[string]Schema{"email": String(), "name": String()}).Optional("name"),
+		Outputs: Object(map[string]Schema{"sent": Boolean(), "to": String()}),
+		Pipeline: &Pipeline{Steps: []PipelineStep{
+			{Name: "customer", Function: "getCustomer", Input: map[string]string{"email": "lower(input.email)"}},
+			{Function: "sendMessage", Input: map[string]string{
+				"to":   "customer.id",
+				"body": "'Hello ' + coalesce(input.name, 'there') + ', visits: ' + string(customer.visits)",
+			}},
+		}},
+	})
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	var gotBody any
+	send := config.Functions["sendMessage"]
+	send.Resolver = func(ctx Context, input any) (any, error) {
+		gotBody = input.(map[string]any)["body"]
+		return map[string]any{"sent": true, "to": "c-1"}, nil
+	}
+	config.Functions["sendMessage"] = send
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	if _, err := config.Call(ctx, "pipeline", map[string]any{"email": "ADA@example.com"}); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if gotBody != "Hello there, visits: 3" {
+		t.Errorf("body = %v", gotBody)
+	}
+
+	// Expressions are checked for unknown paths
+	fn := config.Functions["pipeline"]
+	fn.Pipeline.Steps[1].Input["body"] = "upper(customer.nickname)"
+	config.Functions["pipeline"] = fn
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "unknown field 'nickname'") {
+		t.Errorf("Validate error = %v, want unknown field", err)
+	}
+}
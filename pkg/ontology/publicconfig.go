@@ -0,0 +1,51 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// secretWords mark public config keys that look like they hold secrets.
+var secretWords = []string{"secret", "password", "privatekey", "private_key"}
+
+// validatePublicConfig checks that the public config holds only plain JSON
+// values (strings, numbers, booleans, null, and lists and maps of them) and
+// no setting named like a secret, since it is served to anyone.
+func validatePublicConfig(values map[string]any) error {
+	for _, key := range sortedKeys(values) {
+		lower := strings.ToLower(key)
+		for _, word := range secretWords {
+			if strings.Contains(lower, word) {
+				return fmt.Errorf("public config '%s' looks like a secret; it is served to every browser", key)
+			}
+		}
+		if err := validatePublicValue(values[key]); err != nil {
+			return fmt.Errorf("public config '%s': %w", key, err)
+		}
+	}
+	return nil
+}
+
+func validatePublicValue(v any) error {
+	switch v := v.(type) {
+	case nil, string, bool, float64, float32, int, int32, int64, uint, uint32, uint64:
+		return nil
+	case []string:
+		return nil
+	case []any:
+		for i, item := range v {
+			if err := validatePublicValue(item); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		return nil
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			if err := validatePublicValue(v[key]); err != nil {
+				return fmt.Errorf("'%s': %w", key, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported value of type %T", v)
+}
@@ -0,0 +1,43 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePublicConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]any
+		wantErr string
+	}{
+		{"plain values", map[string]any{
+			"apiBasePath":  "/api",
+			"authClientId": "spa-client",
+			"features":     map[string]any{"newEditor": true, "maxUploads": 3},
+			"locales":      []any{"en", "de"},
+		}, ""},
+		{"secret", map[string]any{"clientSecret": "x"}, "public config 'clientSecret' looks like a secret"},
+		{"function", map[string]any{"features": map[string]any{"f": func() {}}}, "public config 'features': 'f': unsupported value of type func()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := queryConfig()
+			before := config.Hash()
+			config.PublicConfig = tt.config
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate failed: %v", err)
+				}
+				if config.Hash() != before {
+					t.Error("the public config should not change the hash")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
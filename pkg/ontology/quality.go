@@ -0,0 +1,215 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minDescriptionWords is the length below which a description is too short.
+// Agents pick tools by their descriptions, so a description should say what
+// the function does in a sentence.
+const minDescriptionWords = 4
+
+// vagueWords are words that tell an agent little about what a function does.
+var vagueWords = []string{"etc", "handle", "handles", "misc", "process", "processes", "stuff", "tbd", "thing", "things", "todo", "various"}
+
+// DescriptionFinding is a problem with a description. Path names the
+// described element: "functions/getUser", "functions/getUser/inputs/id",
+// "entities/User" or "accessGroups/admin".
+type DescriptionFinding struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// DescriptionSuggestion proposes a new description for the element at Path.
+type DescriptionSuggestion struct {
+	Path     string `json:"path"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+}
+
+// DescriptionReport is the result of AnalyzeDescriptions.
+type DescriptionReport struct {
+	// Score is the average of Scores, from 0 to 100.
+	Score int `json:"score"`
+	// Scores rates each function, entity and access group from 0 to 100.
+	// Findings on a function's inputs count against the function.
+	Scores      map[string]int          `json:"scores"`
+	Findings    []DescriptionFinding    `json:"findings,omitempty"`
+	Suggestions []DescriptionSuggestion `json:"suggestions,omitempty"`
+}
+
+// AnalyzeDescriptions checks the descriptions of the access groups,
+// entities, functions and function inputs for problems that make them hard
+// for agents to use: missing or very short descriptions, vague wording,
+// descriptions that only restate the name and undocumented inputs. It
+// proposes descriptions derived from names where they are missing; better
+// rewrites need a language model (see cloud.SuggestDescriptions).
+func (c *Config) AnalyzeDescriptions() *DescriptionReport {
+	report := &DescriptionReport{Scores: make(map[string]int)}
+	for _, name := range sortedKeys(c.AccessGroups) {
+		report.rate("accessGroups/"+name, report.check("accessGroups/"+name, name, c.AccessGroups[name].Description))
+	}
+	for _, name := range sortedKeys(c.Entities) {
+		report.rate("entities/"+name, report.check("entities/"+name, name, c.Entities[name].Description))
+	}
+	for _, name := range sortedKeys(c.Functions) {
+		fn := c.Functions[name]
+		path := "functions/" + name
+		penalty := report.check(path, name, fn.Description)
+		documented, total := report.checkInputs(path+"/inputs", fn.Inputs)
+		if total > 0 && documented < total {
+			penalty += 40 * (total - documented) / total
+		}
+		report.rate(path, penalty)
+	}
+	if len(report.Scores) == 0 {
+		report.Score = 100
+		return report
+	}
+	sum := 0
+	for _, score := range report.Scores {
+		sum += score
+	}
+	report.Score = sum / len(report.Scores)
+	return report
+}
+
+// check records the findings for one description and returns the score
+// penalty.
+func (r *DescriptionReport) check(path, name, description string) int {
+	text := strings.TrimSpace(description)
+	if text == "" {
+		r.find(path, "description is missing")
+		r.suggest(path, description, sentenceFromName(name))
+		return 60
+	}
+	penalty := 0
+	words := descriptionWords(text)
+	if len(words) < minDescriptionWords {
+		r.find(path, fmt.Sprintf("description is too short (%d words); say what it does and returns", len(words)))
+		penalty += 20
+	}
+	if strings.Join(words, " ") == strings.Join(descriptionWords(humanizeName(name)), " ") {
+		r.find(path, "description only restates the name")
+		penalty += 20
+	}
+	for _, vague := range vagueWords {
+		if contains(words, vague) {
+			r.find(path, fmt.Sprintf("description uses the vague word '%s'", vague))
+			penalty += 15
+		}
+	}
+	return penalty
+}
+
+// checkInputs records the undocumented properties of an input schema and
+// returns how many of its properties are documented, out of the total.
+func (r *DescriptionReport) checkInputs(path string, schema Schema) (documented, total int) {
+	switch s := schema.(type) {
+	case *NullableSchema:
+		return r.checkInputs(path, s.InnerSchema())
+	case *ArraySchema:
+		return r.checkInputs(path, s.ItemSchema())
+	case *ObjectSchema:
+		for _, name := range sortedKeys(s.properties) {
+			prop := s.properties[name]
+			propPath := path + "/" + name
+			total++
+			if d, ok := prop.(Described); ok && strings.TrimSpace(d.Description()) != "" {
+				documented++
+			} else if _, ok := prop.(*RefSchema); ok {
+				// Shared schemas are documented where they are declared.
+				documented++
+			} else {
+				r.find(propPath, "input is not documented; use Describe")
+				r.suggest(propPath, "", "The "+humanizeName(name)+".")
+			}
+			d, t := r.checkInputs(propPath, prop)
+			documented, total = documented+d, total+t
+		}
+	}
+	return documented, total
+}
+
+func (r *DescriptionReport) find(path, message string) {
+	r.Findings = append(r.Findings, DescriptionFinding{Path: path, Message: message})
+}
+
+func (r *DescriptionReport) suggest(path, current, proposed string) {
+	r.Suggestions = append(r.Suggestions, DescriptionSuggestion{Path: path, Current: current, Proposed: proposed})
+}
+
+func (r *DescriptionReport) rate(path string, penalty int) {
+	r.Scores[path] = max(100-penalty, 0)
+}
+
+// Suggest adds suggestions, such as those of a language model, replacing
+// any earlier suggestion for the same path.
+func (r *DescriptionReport) Suggest(suggestions ...DescriptionSuggestion) {
+	for _, s := range suggestions {
+		replaced := false
+		for i := range r.Suggestions {
+			if r.Suggestions[i].Path == s.Path {
+				r.Suggestions[i] = s
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.Suggestions = append(r.Suggestions, s)
+		}
+	}
+}
+
+// Diff returns the suggestions as a diff, one hunk per path:
+//
+//	@@ functions/getUser @@
+//	-Gets user
+//	+Returns the user with the given ID, including their email address.
+func (r *DescriptionReport) Diff() string {
+	var b strings.Builder
+	for _, s := range r.Suggestions {
+		if s.Proposed == s.Current {
+			continue
+		}
+		fmt.Fprintf(&b, "@@ %s @@\n", s.Path)
+		if s.Current != "" {
+			fmt.Fprintf(&b, "-%s\n", s.Current)
+		}
+		fmt.Fprintf(&b, "+%s\n", s.Proposed)
+	}
+	return b.String()
+}
+
+// String returns the score and findings, one per line.
+func (r *DescriptionReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Description score: %d/100\n", r.Score)
+	for _, f := range r.Findings {
+		fmt.Fprintf(&b, "%s: %s\n", f.Path, f.Message)
+	}
+	return b.String()
+}
+
+// descriptionWords returns the lower-cased words of text.
+func descriptionWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	})
+}
+
+// humanizeName turns an identifier into words: "getUserByID" -> "get user
+// by id".
+func humanizeName(name string) string {
+	return strings.Join(descriptionWords(strings.ReplaceAll(toSnakeCase(name), "_", " ")), " ")
+}
+
+// sentenceFromName turns an identifier into a sentence: "getUser" -> "Get
+// user.".
+func sentenceFromName(name string) string {
+	words := humanizeName(name)
+	if words == "" {
+		return ""
+	}
+	return strings.ToUpper(words[:1]) + words[1:] + "."
+}
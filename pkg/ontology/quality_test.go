@@ -0,0 +1,106 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeDescriptions(t *testing.T) {
+	config := &Config{
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Administrators who manage the account"}},
+		Entities:     map[string]Entity{"Customer": {Description: ""}},
+		Functions: map[string]Function{
+			"getCustomer": {
+				Description: "Returns a customer by ID with their contact details",
+				Inputs:      Object(map[string]Schema{"id": String().Describe("The customer ID")}),
+			},
+			"syncOrders": {
+				Description: "Sync orders",
+				Inputs: Object(map[string]Schema{
+					"since":  String(),
+					"filter": Object(map[string]Schema{"status": String().Describe("Order status")}),
+				}),
+			},
+			"cleanup": {
+				Description: "Handles various cleanup stuff for the account",
+				Inputs:      Object(map[string]Schema{}),
+			},
+		},
+	}
+	report := config.AnalyzeDescriptions()
+
+	wantScores := map[string]int{
+		"accessGroups/admin":    100,
+		"entities/Customer":     40,
+		"functions/getCustomer": 100,
+		"functions/syncOrders":  34,
+		"functions/cleanup":     55,
+	}
+	for path, want := range wantScores {
+		if got := report.Scores[path]; got != want {
+			t.Errorf("Scores[%s] = %d, want %d", path, got, want)
+		}
+	}
+	if report.Score != (100+40+100+34+55)/5 {
+		t.Errorf("Score = %d", report.Score)
+	}
+
+	got := report.String()
+	for _, want := range []string{
+		"entities/Customer: description is missing",
+		"functions/syncOrders: description is too short (2 words)",
+		"functions/syncOrders: description only restates the name",
+		"functions/syncOrders/inputs/filter: input is not documented",
+		"functions/syncOrders/inputs/since: input is not documented",
+		"functions/cleanup: description uses the vague word 'handles'",
+		"functions/cleanup: description uses the vague word 'stuff'",
+		"functions/cleanup: description uses the vague word 'various'",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("String() = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "getCustomer") || strings.Contains(got, "filter/status") {
+		t.Errorf("String() = %q, has findings for documented elements", got)
+	}
+
+	report.Suggest(DescriptionSuggestion{
+		Path:     "functions/syncOrders",
+		Current:  "Sync orders",
+		Proposed: "Copies orders changed since a date from the shop into the warehouse.",
+	}, DescriptionSuggestion{Path: "entities/Customer", Proposed: "A person or company that buys from the shop."})
+	wantDiff := "@@ entities/Customer @@\n" +
+		"+A person or company that buys from the shop.\n" +
+		"@@ functions/syncOrders/inputs/filter @@\n" +
+		"+The filter.\n" +
+		"@@ functions/syncOrders/inputs/since @@\n" +
+		"+The since.\n" +
+		"@@ functions/syncOrders @@\n" +
+		"-Sync orders\n" +
+		"+Copies orders changed since a date from the shop into the warehouse.\n"
+	if got := report.Diff(); got != wantDiff {
+		t.Errorf("Diff() = %q, want %q", got, wantDiff)
+	}
+}
+
+func TestAnalyzeDescriptionsEmpty(t *testing.T) {
+	if report := (&Config{}).AnalyzeDescriptions(); report.Score != 100 || len(report.Findings) != 0 {
+		t.Errorf("AnalyzeDescriptions() = %+v", report)
+	}
+}
+
+func TestHumanizeName(t *testing.T) {
+	tests := map[string]string{
+		"getUserByID": "get user by id",
+		"list_orders": "list orders",
+		"Customer":    "customer",
+	}
+	for name, want := range tests {
+		if got := humanizeName(name); got != want {
+			t.Errorf("humanizeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if got := sentenceFromName("getUser"); got != "Get user." {
+		t.Errorf("sentenceFromName() = %q", got)
+	}
+}
@@ -0,0 +1,116 @@
+package ontology
+
+import "strings"
+
+// FunctionQuery selects functions by what they touch. Empty fields match
+// every function; set fields must all match.
+type FunctionQuery struct {
+	// Entity matches functions that declare the entity.
+	Entity string `json:"entity,omitempty"`
+	// AccessGroup matches functions the group may call.
+	AccessGroup string `json:"accessGroup,omitempty"`
+	// InputField matches functions whose inputs have a property with this
+	// name at any depth.
+	InputField string `json:"inputField,omitempty"`
+	// OutputField matches functions whose outputs have a property with this
+	// name at any depth.
+	OutputField string `json:"outputField,omitempty"`
+	// Tag matches functions with the tag.
+	Tag string `json:"tag,omitempty"`
+	// Text matches the function name or description, ignoring case.
+	Text string `json:"text,omitempty"`
+}
+
+// Query returns the sorted names of the functions matching q.
+func (c *Config) Query(q FunctionQuery) []string {
+	text := strings.ToLower(q.Text)
+	var names []string
+	for _, name := range sortedKeys(c.Functions) {
+		fn := c.Functions[name]
+		if q.Entity != "" && !contains(fn.Entities, q.Entity) {
+			continue
+		}
+		if q.AccessGroup != "" && !contains(fn.Access, q.AccessGroup) {
+			continue
+		}
+		if q.Tag != "" && !contains(fn.Tags, q.Tag) {
+			continue
+		}
+		if q.InputField != "" && !hasField(fn.Inputs, q.InputField, map[Schema]bool{}) {
+			continue
+		}
+		if q.OutputField != "" && !hasField(fn.Outputs, q.OutputField, map[Schema]bool{}) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(name), text) && !strings.Contains(strings.ToLower(fn.Description), text) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// FunctionsForEntity returns the functions that declare entity.
+func (c *Config) FunctionsForEntity(entity string) []string {
+	return c.Query(FunctionQuery{Entity: entity})
+}
+
+// FunctionsForAccessGroup returns the functions group may call.
+func (c *Config) FunctionsForAccessGroup(group string) []string {
+	return c.Query(FunctionQuery{AccessGroup: group})
+}
+
+// FunctionsWithOutputField returns the functions whose outputs contain a
+// property named field at any depth.
+func (c *Config) FunctionsWithOutputField(field string) []string {
+	return c.Query(FunctionQuery{OutputField: field})
+}
+
+// hasField reports whether schema has a property named field at any depth,
+// following named schema references.
+func hasField(schema Schema, field string, seen map[Schema]bool) bool {
+	if schema == nil || seen[schema] {
+		return false
+	}
+	seen[schema] = true
+
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		if _, ok := s.properties[field]; ok {
+			return true
+		}
+		for _, name := range sortedKeys(s.properties) {
+			if hasField(s.properties[name], field, seen) {
+				return true
+			}
+		}
+	case *ArraySchema:
+		return hasField(s.items, field, seen)
+	case *NullableSchema:
+		return hasField(s.inner, field, seen)
+	case *RefSchema:
+		return hasField(s.target, field, seen)
+	case *OneOfSchema:
+		for _, alt := range s.alternatives {
+			if hasField(alt, field, seen) {
+				return true
+			}
+		}
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			if hasField(part, field, seen) {
+				return true
+			}
+		}
+	case *DiscriminatedUnionSchema:
+		if s.discriminator == field {
+			return true
+		}
+		for _, tag := range s.Tags() {
+			if hasField(s.variants[tag], field, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package ontology
+
+import (
+	"reflect"
+	"testing"
+)
+
+func queryConfig() *Config {
+	config := graphConfig()
+	config.Schemas = map[string]Schema{
+		"Owner": Object(map[string]Schema{"ownerEmail": String(), "manager": Nullable(Ref("Owner"))}),
+	}
+	fn := config.Functions["getCustomer"]
+	fn.Entities = []string{"Customer"}
+	fn.Outputs = Object(map[string]Schema{
+		"id":    String(),
+		"owner": Ref("Owner"),
+	})
+	config.Functions["getCustomer"] = fn
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	return config
+}
+
+func TestQuery(t *testing.T) {
+	config := queryConfig()
+
+	tests := []struct {
+		name  string
+		query FunctionQuery
+		want  []string
+	}{
+		{"everything", FunctionQuery{}, []string{"deleteCustomer", "getCustomer", "pipeline", "sendMessage"}},
+		{"entity", FunctionQuery{Entity: "Customer"}, []string{"getCustomer", "pipeline"}},
+		{"access group", FunctionQuery{AccessGroup: "admin"}, []string{"deleteCustomer"}},
+		{"input field", FunctionQuery{InputField: "to"}, []string{"sendMessage"}},
+		{"output field through ref", FunctionQuery{OutputField: "ownerEmail"}, []string{"getCustomer"}},
+		{"text", FunctionQuery{Text: "DELETE"}, []string{"deleteCustomer"}},
+		{"combined", FunctionQuery{Entity: "Customer", OutputField: "sent"}, []string{"pipeline"}},
+		{"no match", FunctionQuery{OutputField: "nope"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.Query(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Query = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryHelpers(t *testing.T) {
+	config := queryConfig()
+	if got := config.FunctionsForEntity("Customer"); !reflect.DeepEqual(got, []string{"getCustomer", "pipeline"}) {
+		t.Errorf("FunctionsForEntity = %v", got)
+	}
+	if got := config.FunctionsForAccessGroup("support"); len(got) != 3 {
+		t.Errorf("FunctionsForAccessGroup = %v", got)
+	}
+	if got := config.FunctionsWithOutputField("manager"); !reflect.DeepEqual(got, []string{"getCustomer"}) {
+		t.Errorf("FunctionsWithOutputField = %v", got)
+	}
+}
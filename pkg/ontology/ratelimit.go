@@ -0,0 +1,54 @@
+package ontology
+
+import "fmt"
+
+// RateLimit caps how often a function is called. Calls are counted with a
+// token bucket per function, shared by its callers, that refills at
+// RequestsPerMinute and holds up to Burst calls.
+type RateLimit struct {
+	RequestsPerMinute int `json:"requestsPerMinute"`
+	// Burst is how many calls may arrive at once. It defaults to the
+	// bucket's requests per minute.
+	Burst int `json:"burst,omitempty"`
+	// PerAccessGroup gives access groups a budget of their own, in requests
+	// per minute: callers in one of these groups share a bucket per group
+	// instead of the function's. Callers in several use the highest.
+	PerAccessGroup map[string]int `json:"perAccessGroup,omitempty"`
+}
+
+// Limit returns the budget callers with accessGroups draw from: the group
+// whose bucket they use ("" for the function's) and its requests per
+// minute and burst.
+func (r *RateLimit) Limit(accessGroups []string) (group string, perMinute, burst int) {
+	perMinute = r.RequestsPerMinute
+	for _, g := range accessGroups {
+		if limit, ok := r.PerAccessGroup[g]; ok && (group == "" || limit > perMinute || (limit == perMinute && g < group)) {
+			group, perMinute = g, limit
+		}
+	}
+	burst = r.Burst
+	if burst == 0 {
+		burst = perMinute
+	}
+	return group, perMinute, burst
+}
+
+// validateRateLimit checks that a rate limit's budgets are positive and its
+// access groups exist.
+func (c *Config) validateRateLimit(limit *RateLimit) error {
+	if limit.RequestsPerMinute <= 0 {
+		return fmt.Errorf("rate limit: requestsPerMinute must be positive")
+	}
+	if limit.Burst < 0 {
+		return fmt.Errorf("rate limit: burst must not be negative")
+	}
+	for _, group := range sortedKeys(limit.PerAccessGroup) {
+		if _, ok := c.AccessGroups[group]; !ok {
+			return fmt.Errorf("rate limit: unknown access group '%s'", group)
+		}
+		if limit.PerAccessGroup[group] <= 0 {
+			return fmt.Errorf("rate limit: requests per minute for '%s' must be positive", group)
+		}
+	}
+	return nil
+}
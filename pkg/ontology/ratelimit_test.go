@@ -0,0 +1,72 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRateLimitLimit(t *testing.T) {
+	limit := &RateLimit{RequestsPerMinute: 60, PerAccessGroup: map[string]int{"admin": 600, "support": 120}}
+	tests := []struct {
+		groups    []string
+		wantGroup string
+		wantRate  int
+	}{
+		{[]string{"public"}, "", 60},
+		{[]string{"support"}, "support", 120},
+		{[]string{"support", "admin"}, "admin", 600},
+	}
+	for _, tt := range tests {
+		group, perMinute, burst := limit.Limit(tt.groups)
+		if group != tt.wantGroup || perMinute != tt.wantRate || burst != tt.wantRate {
+			t.Errorf("Limit(%v) = %q, %d, %d", tt.groups, group, perMinute, burst)
+		}
+	}
+
+	limit.Burst = 5
+	if _, _, burst := limit.Limit(nil); burst != 5 {
+		t.Errorf("burst = %d, want 5", burst)
+	}
+}
+
+func TestRateLimitLock(t *testing.T) {
+	config := queryConfig()
+	before := config.Hash()
+	fn := config.Functions["getCustomer"]
+	fn.RateLimit = &RateLimit{RequestsPerMinute: 60, PerAccessGroup: map[string]int{"admin": 600}}
+	config.Functions["getCustomer"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if config.Hash() == before {
+		t.Error("hash should change when a rate limit is added")
+	}
+	if shape := config.ExtractSnapshot().Functions["getCustomer"]; shape.RateLimit == nil || shape.RateLimit.RequestsPerMinute != 60 {
+		t.Errorf("snapshot rate limit = %+v", shape.RateLimit)
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   RateLimit
+		wantErr string
+	}{
+		{"no rate", RateLimit{}, "requestsPerMinute must be positive"},
+		{"negative burst", RateLimit{RequestsPerMinute: 10, Burst: -1}, "burst must not be negative"},
+		{"unknown group", RateLimit{RequestsPerMinute: 10, PerAccessGroup: map[string]int{"nobody": 5}}, "unknown access group 'nobody'"},
+		{"zero group rate", RateLimit{RequestsPerMinute: 10, PerAccessGroup: map[string]int{"admin": 0}}, "for 'admin' must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := queryConfig()
+			fn := config.Functions["getCustomer"]
+			fn.RateLimit = &tt.limit
+			config.Functions["getCustomer"] = fn
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
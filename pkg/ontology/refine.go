@@ -0,0 +1,148 @@
+package ontology
+
+import "fmt"
+
+// RefineFunc checks a value that passed its schema's declarative
+// validation. The value is in its JSON form: map[string]any for objects,
+// []any for arrays, float64 for numbers (int64 beyond 2^53) and so on.
+type RefineFunc func(v any) error
+
+// refinement is a check added with Refine.
+type refinement struct {
+	check   RefineFunc
+	message string
+}
+
+// refinements holds the custom checks shared by every schema type.
+type refinements struct {
+	refines []refinement
+}
+
+// addRefinement appends a check.
+func (r *refinements) addRefinement(check RefineFunc, message string) {
+	r.refines = append(r.refines, refinement{check: check, message: message})
+}
+
+// Refinements returns the messages of the checks added with Refine.
+func (r *refinements) Refinements() []string {
+	messages := make([]string, len(r.refines))
+	for i, ref := range r.refines {
+		messages[i] = ref.message
+	}
+	return messages
+}
+
+// refine runs the checks in order and fails with the message of the first
+// that returns an error, or with the error itself if the message is empty.
+func (r *refinements) refine(data any) error {
+	if len(r.refines) == 0 {
+		return nil
+	}
+	value := toJSONValue(data)
+	for _, ref := range r.refines {
+		if err := ref.check(value); err != nil {
+			if ref.message != "" {
+				return fmt.Errorf("%s", ref.message)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// annotateRefinements lists the refinement messages in a JSON Schema as
+// "x-ont-refinements", so the lock file records that a check exists even
+// though its logic lives in Go.
+func (r *refinements) annotateRefinements(result map[string]any) map[string]any {
+	if len(r.refines) > 0 {
+		result["x-ont-refinements"] = r.Refinements()
+	}
+	return result
+}
+
+// Refine adds a custom check that runs after the object's fields and rules
+// validate, for constraints JSON Schema can't express, e.g.
+//
+//	Refine(func(v any) error {
+//		m := v.(map[string]any)
+//		if m["start"].(string) >= m["end"].(string) {
+//			return errors.New("start must be before end")
+//		}
+//		return nil
+//	}, "start must be before end")
+//
+// Validation fails with message, or with the check's error if message is
+// empty. Refinements don't change generated types; prefer Rule when the
+// check can be written as an expression, since rules are declarative.
+func (o *ObjectSchema) Refine(check RefineFunc, message string) *ObjectSchema {
+	o.addRefinement(check, message)
+	return o
+}
+
+// Refine adds a custom check that runs after the string validates.
+func (s *StringSchema) Refine(check RefineFunc, message string) *StringSchema {
+	s.addRefinement(check, message)
+	return s
+}
+
+// Refine adds a custom check that runs after the number validates.
+func (n *NumberSchema) Refine(check RefineFunc, message string) *NumberSchema {
+	n.addRefinement(check, message)
+	return n
+}
+
+// Refine adds a custom check that runs after the boolean validates.
+func (b *BooleanSchema) Refine(check RefineFunc, message string) *BooleanSchema {
+	b.addRefinement(check, message)
+	return b
+}
+
+// Refine adds a custom check that runs after the binary data validates. The
+// check receives the base64 string.
+func (b *BytesSchema) Refine(check RefineFunc, message string) *BytesSchema {
+	b.addRefinement(check, message)
+	return b
+}
+
+// Refine adds a custom check that runs after the array and its items validate.
+func (a *ArraySchema) Refine(check RefineFunc, message string) *ArraySchema {
+	a.addRefinement(check, message)
+	return a
+}
+
+// Refine adds a custom check that runs after the value validates, including
+// when it is null.
+func (n *NullableSchema) Refine(check RefineFunc, message string) *NullableSchema {
+	n.addRefinement(check, message)
+	return n
+}
+
+// Refine adds a custom check that runs after the union validates.
+func (o *OneOfSchema) Refine(check RefineFunc, message string) *OneOfSchema {
+	o.addRefinement(check, message)
+	return o
+}
+
+// Refine adds a custom check that runs after every schema validates.
+func (a *AllOfSchema) Refine(check RefineFunc, message string) *AllOfSchema {
+	a.addRefinement(check, message)
+	return a
+}
+
+// Refine adds a custom check that runs after the union validates.
+func (d *DiscriminatedUnionSchema) Refine(check RefineFunc, message string) *DiscriminatedUnionSchema {
+	d.addRefinement(check, message)
+	return d
+}
+
+// Refine adds a custom check on the value.
+func (a *AnySchema) Refine(check RefineFunc, message string) *AnySchema {
+	a.addRefinement(check, message)
+	return a
+}
+
+// Refine adds a custom check that runs after the referenced schema validates.
+func (r *RefSchema) Refine(check RefineFunc, message string) *RefSchema {
+	r.addRefinement(check, message)
+	return r
+}
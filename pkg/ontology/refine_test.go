@@ -0,0 +1,102 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func dateRange() *ObjectSchema {
+	return Object(map[string]Schema{
+		"start": String().Date(),
+		"end":   String().Date(),
+	}).Refine(func(v any) error {
+		m := v.(map[string]any)
+		if m["start"].(string) > m["end"].(string) {
+			return errors.New("bad range")
+		}
+		return nil
+	}, "start must not be after end")
+}
+
+func TestRefineObject(t *testing.T) {
+	schema := dateRange()
+	if err := schema.Validate(map[string]any{"start": "2024-01-01", "end": "2024-02-01"}); err != nil {
+		t.Errorf("expected valid range, got %v", err)
+	}
+	err := schema.Validate(map[string]any{"start": "2024-03-01", "end": "2024-02-01"})
+	if err == nil || err.Error() != "start must not be after end" {
+		t.Errorf("expected refinement message, got %v", err)
+	}
+
+	// Structs are refined in their JSON form
+	type period struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+	if err := schema.Validate(period{Start: "2024-03-01", End: "2024-02-01"}); err == nil {
+		t.Error("expected refinement to reject struct")
+	}
+
+	// Refinements only run once the declarative checks pass
+	err = schema.Validate(map[string]any{"start": "2024-03-01"})
+	if err == nil || !strings.Contains(err.Error(), "required field 'end'") {
+		t.Errorf("expected missing field error, got %v", err)
+	}
+}
+
+func TestRefinePrimitives(t *testing.T) {
+	even := Integer().Refine(func(v any) error {
+		if int(v.(float64))%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	}, "")
+	if err := even.Validate(4); err != nil {
+		t.Errorf("expected 4 to be valid, got %v", err)
+	}
+	if err := even.Validate(3); err == nil || err.Error() != "must be even" {
+		t.Errorf("expected the check's error without a message, got %v", err)
+	}
+
+	noSpaces := String().Refine(func(v any) error {
+		if strings.Contains(v.(string), " ") {
+			return errors.New("space")
+		}
+		return nil
+	}, "must not contain spaces")
+	if err := Array(noSpaces).Validate([]any{"a", "b c"}); err == nil || !strings.Contains(err.Error(), "must not contain spaces") {
+		t.Errorf("expected item refinement error, got %v", err)
+	}
+
+	called := false
+	nullable := Nullable(String()).Refine(func(v any) error {
+		called = true
+		return nil
+	}, "")
+	if err := nullable.Validate(nil); err != nil || !called {
+		t.Errorf("expected refinement to run on null, got %v", err)
+	}
+}
+
+func TestRefineJSONSchema(t *testing.T) {
+	got := dateRange().JSONSchema()["x-ont-refinements"]
+	if !reflect.DeepEqual(got, []string{"start must not be after end"}) {
+		t.Errorf("x-ont-refinements = %v", got)
+	}
+	if _, ok := String().JSONSchema()["x-ont-refinements"]; ok {
+		t.Error("schemas without refinements should not list them")
+	}
+}
+
+func TestRefineCompose(t *testing.T) {
+	extended := dateRange().Extend(Object(map[string]Schema{"label": String()}).Optional("label"))
+	if err := extended.Validate(map[string]any{"start": "2024-03-01", "end": "2024-02-01"}); err == nil {
+		t.Error("Extend should keep refinements")
+	}
+	partial := dateRange().Partial()
+	if err := partial.Validate(map[string]any{"start": "2024-03-01"}); err != nil {
+		t.Errorf("Partial should drop refinements, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package ontology
+
+import "fmt"
+
+// Deref follows references until it reaches a schema that is not a Ref.
+// Unresolved references are returned as-is.
+func Deref(schema Schema) Schema {
+	for i := 0; i < 64; i++ {
+		ref, ok := schema.(*RefSchema)
+		if !ok || ref.target == nil {
+			return schema
+		}
+		schema = ref.target
+	}
+	return schema
+}
+
+// NamedSchemas returns the schemas a Ref can name: Config.Schemas and the
+// schemas of entities that declare one.
+func (c *Config) NamedSchemas() map[string]Schema {
+	named := make(map[string]Schema, len(c.Schemas))
+	for name, schema := range c.Schemas {
+		named[name] = schema
+	}
+	for name, entity := range c.Entities {
+		if entity.Schema != nil {
+			named[name] = entity.Schema
+		}
+	}
+	return named
+}
+
+// resolveRefs links every Ref used by the named schemas and functions to its
+// target in c.NamedSchemas().
+func (c *Config) resolveRefs() error {
+	named := c.NamedSchemas()
+	for _, name := range sortedKeys(named) {
+		if err := c.resolveSchemaRefs(named[name]); err != nil {
+			return fmt.Errorf("schema '%s' %w", name, err)
+		}
+	}
+
+	// Recursion must pass through an object or array, otherwise validating
+	// a value would never terminate (e.g. A = Nullable(Ref("A")))
+	for _, name := range sortedKeys(named) {
+		if c.reachesUnguarded(name, named[name], map[string]bool{}) {
+			return fmt.Errorf("schema '%s' is a circular reference; recursion must go through an object or array", name)
+		}
+	}
+
+	for _, name := range sortedKeys(c.Functions) {
+		fn := c.Functions[name]
+		schemas := []Schema{fn.Inputs, fn.Outputs}
+		for _, version := range sortedKeys(fn.OutputVersions) {
+			schemas = append(schemas, fn.OutputVersions[version].Outputs)
+		}
+		for _, schema := range schemas {
+			if schema == nil {
+				continue
+			}
+			if err := c.resolveSchemaRefs(schema); err != nil {
+				return fmt.Errorf("function '%s' %w", name, err)
+			}
+		}
+	}
+	for _, name := range sortedKeys(c.Events) {
+		if payload := c.Events[name].Payload; payload != nil {
+			if err := c.resolveSchemaRefs(payload); err != nil {
+				return fmt.Errorf("event '%s' %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Config) resolveSchemaRefs(schema Schema) error {
+	var err error
+	walkSchema(schema, func(s Schema) {
+		ref, ok := s.(*RefSchema)
+		if !ok || err != nil {
+			return
+		}
+		target, exists := c.namedSchema(ref.name)
+		if !exists {
+			err = fmt.Errorf("references unknown schema '%s'", ref.name)
+			return
+		}
+		ref.target = target
+	})
+	return err
+}
+
+// namedSchema looks up a schema a Ref can name.
+func (c *Config) namedSchema(name string) (Schema, bool) {
+	if schema, ok := c.Schemas[name]; ok {
+		return schema, true
+	}
+	if entity, ok := c.Entities[name]; ok && entity.Schema != nil {
+		return entity.Schema, true
+	}
+	return nil, false
+}
+
+// reachesUnguarded reports whether schema can reach the named schema through
+// references, nullables and unions alone, without consuming any structure.
+func (c *Config) reachesUnguarded(name string, schema Schema, visited map[string]bool) bool {
+	switch s := schema.(type) {
+	case *RefSchema:
+		if s.name == name {
+			return true
+		}
+		if visited[s.name] {
+			return false
+		}
+		visited[s.name] = true
+		target, ok := c.namedSchema(s.name)
+		return ok && c.reachesUnguarded(name, target, visited)
+	case *NullableSchema:
+		return c.reachesUnguarded(name, s.inner, visited)
+	case *OneOfSchema:
+		for _, alt := range s.alternatives {
+			if c.reachesUnguarded(name, alt, visited) {
+				return true
+			}
+		}
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			if c.reachesUnguarded(name, part, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JSONSchemaFor returns the JSON Schema of schema with the named schemas it
+// references (directly or transitively) under "$defs". A top-level Ref is
+// inlined so the result keeps the target's "type".
+func (c *Config) JSONSchemaFor(schema Schema) map[string]any {
+	result := Deref(schema).JSONSchema()
+
+	refs := make(map[string]*RefSchema)
+	collectRefs(schema, refs)
+	if len(refs) == 0 {
+		return result
+	}
+
+	defs := make(map[string]any, len(refs))
+	for name, ref := range refs {
+		if ref.target != nil {
+			defs[name] = ref.target.JSONSchema()
+		}
+	}
+
+	withDefs := make(map[string]any, len(result)+1)
+	for k, v := range result {
+		withDefs[k] = v
+	}
+	withDefs["$defs"] = defs
+	return withDefs
+}
+
+// collectRefs records every reference reachable from schema, following
+// resolved references into their targets once.
+func collectRefs(schema Schema, refs map[string]*RefSchema) {
+	walkSchema(schema, func(s Schema) {
+		ref, ok := s.(*RefSchema)
+		if !ok {
+			return
+		}
+		if _, seen := refs[ref.name]; seen {
+			return
+		}
+		refs[ref.name] = ref
+		if ref.target != nil {
+			collectRefs(ref.target, refs)
+		}
+	})
+}
+
+// walkSchema calls visit for schema and each schema nested in it. It does
+// not follow references, so recursive schemas terminate.
+func walkSchema(schema Schema, visit func(Schema)) {
+	visit(schema)
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		for _, name := range sortedKeys(s.properties) {
+			walkSchema(s.properties[name], visit)
+		}
+		for _, p := range s.patterns {
+			walkSchema(p.schema, visit)
+		}
+	case *ArraySchema:
+		walkSchema(s.items, visit)
+	case *NullableSchema:
+		walkSchema(s.inner, visit)
+	case *OneOfSchema:
+		for _, alt := range s.alternatives {
+			walkSchema(alt, visit)
+		}
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			walkSchema(part, visit)
+		}
+	case *DiscriminatedUnionSchema:
+		for _, tag := range s.Tags() {
+			walkSchema(s.variants[tag], visit)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func refConfig() *Config {
+	return &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]Entity{},
+		Schemas: map[string]Schema{
+			"Address": Object(map[string]Schema{
+				"city": String(),
+			}),
+			"User": Object(map[string]Schema{
+				"name":    String(),
+				"address": Ref("Address"),
+			}),
+		},
+		Functions: map[string]Function{
+			"listUsers": {
+				Description: "List users",
+				Access:      []string{"public"},
+				Inputs:      Object(map[string]Schema{}),
+				Outputs: Object(map[string]Schema{
+					"users": Array(Ref("User")),
+				}),
+			},
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Ref("User"),
+			},
+		},
+	}
+}
+
+func TestRefResolution(t *testing.T) {
+	config := refConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	output := config.Functions["getUser"].Outputs
+	if err := output.Validate(map[string]any{"name": "Ada", "address": map[string]any{"city": "London"}}); err != nil {
+		t.Errorf("valid data rejected: %v", err)
+	}
+	if err := output.Validate(map[string]any{"name": "Ada", "address": map[string]any{"city": 42}}); err == nil {
+		t.Error("nested referenced schema should be validated")
+	}
+}
+
+func TestRefUnresolved(t *testing.T) {
+	if err := Ref("User").Validate(map[string]any{}); err == nil {
+		t.Error("unresolved reference should fail validation")
+	}
+
+	config := refConfig()
+	config.Functions["getUser"] = Function{
+		Description: "Get a user",
+		Access:      []string{"public"},
+		Inputs:      Object(map[string]Schema{}),
+		Outputs:     Ref("Missing"),
+	}
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "function 'getUser' references unknown schema 'Missing'") {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	config = refConfig()
+	config.Schemas["A"] = Ref("B")
+	config.Schemas["B"] = Ref("A")
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "circular reference") {
+		t.Errorf("Validate() error = %v, want circular reference", err)
+	}
+}
+
+func TestJSONSchemaForDefs(t *testing.T) {
+	config := refConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	list := config.JSONSchemaFor(config.Functions["listUsers"].Outputs)
+	users := list["properties"].(map[string]any)["users"].(map[string]any)
+	if ref := users["items"].(map[string]any)["$ref"]; ref != "#/$defs/User" {
+		t.Errorf("items $ref = %v", ref)
+	}
+	defs, ok := list["$defs"].(map[string]any)
+	if !ok || defs["User"] == nil || defs["Address"] == nil {
+		t.Errorf("$defs should include transitively referenced schemas, got %v", list["$defs"])
+	}
+
+	// A top-level reference is inlined so MCP still sees type "object"
+	get := config.JSONSchemaFor(config.Functions["getUser"].Outputs)
+	if get["type"] != "object" {
+		t.Errorf("top-level ref type = %v, want object", get["type"])
+	}
+
+	// Schemas without references are unchanged
+	plain := config.JSONSchemaFor(config.Functions["getUser"].Inputs)
+	if _, ok := plain["$defs"]; ok {
+		t.Error("schemas without references should not have $defs")
+	}
+}
+
+func TestRefChangesHash(t *testing.T) {
+	config := refConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	before := config.Hash()
+
+	config.Schemas["Address"] = Object(map[string]Schema{"city": String(), "zip": String()})
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	after := config.Hash()
+
+	if before == after {
+		t.Error("changing a named schema should change the ontology hash")
+	}
+}
+
+func commentConfig() *Config {
+	return &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]Entity{},
+		Schemas: map[string]Schema{
+			"Comment": Object(map[string]Schema{
+				"authorId": String(),
+				"body":     String(),
+				"replies":  Array(Ref("Comment")),
+			}),
+		},
+		Functions: map[string]Function{
+			"getThread": {
+				Description: "Get a comment thread",
+				Access:      []string{"public"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Ref("Comment"),
+			},
+		},
+	}
+}
+
+func TestRecursiveSchema(t *testing.T) {
+	config := commentConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	thread := map[string]any{
+		"authorId": "a",
+		"body":     "root",
+		"replies": []any{
+			map[string]any{
+				"authorId": "b",
+				"body":     "reply",
+				"replies": []any{
+					map[string]any{"authorId": "c", "body": "nested", "replies": []any{}},
+				},
+			},
+		},
+	}
+
+	output := config.Functions["getThread"].Outputs
+	if err := output.Validate(thread); err != nil {
+		t.Errorf("valid thread rejected: %v", err)
+	}
+
+	thread["replies"].([]any)[0].(map[string]any)["replies"].([]any)[0].(map[string]any)["body"] = 42
+	if err := output.Validate(thread); err == nil {
+		t.Error("invalid deeply nested reply should be rejected")
+	}
+
+	schema := config.JSONSchemaFor(output)
+	replies := schema["properties"].(map[string]any)["replies"].(map[string]any)
+	if ref := replies["items"].(map[string]any)["$ref"]; ref != "#/$defs/Comment" {
+		t.Errorf("replies items $ref = %v", ref)
+	}
+	defs := schema["$defs"].(map[string]any)
+	if len(defs) != 1 || defs["Comment"] == nil {
+		t.Errorf("$defs = %v, want only Comment", defs)
+	}
+}
+
+func TestRecursiveSchemaWireNames(t *testing.T) {
+	config := commentConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	thread := map[string]any{
+		"authorId": "a",
+		"replies":  []any{map[string]any{"authorId": "b", "replies": []any{}}},
+	}
+	wire := ToWireNames(config.Functions["getThread"].Outputs, thread, SnakeCase).(map[string]any)
+	reply := wire["replies"].([]any)[0].(map[string]any)
+	if reply["author_id"] != "b" {
+		t.Errorf("nested reply keys = %v, want author_id", reply)
+	}
+}
+
+func TestUnguardedRecursion(t *testing.T) {
+	tests := map[string]Schema{
+		"self":     Ref("Loop"),
+		"nullable": Nullable(Ref("Loop")),
+		"oneOf":    OneOf(String(), Ref("Loop")),
+	}
+	for name, schema := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := commentConfig()
+			config.Schemas["Loop"] = schema
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), "schema 'Loop' is a circular reference") {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+
+	// Mutual recursion through objects is fine
+	config := commentConfig()
+	config.Schemas["Folder"] = Object(map[string]Schema{"files": Array(Ref("File"))})
+	config.Schemas["File"] = Object(map[string]Schema{"parent": Nullable(Ref("Folder"))})
+	if err := config.Validate(); err != nil {
+		t.Errorf("guarded mutual recursion rejected: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package ontology
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Registry is an immutable index of a config's functions, built once for
+// servers with many functions. Lookups by name or alias are O(1), and the
+// functions a set of access groups may call is computed on first use and
+// reused. Changes to the config after NewRegistry aren't reflected.
+type Registry struct {
+	functions map[string]Function
+	aliases   map[string]string
+	names     []string
+	groups    []string
+	// granting holds the groups that some function's Access lists.
+	granting map[string]bool
+
+	// callable caches Callable by the key of the granting groups, so it
+	// holds at most one entry per combination of the config's groups.
+	callable sync.Map
+}
+
+// NewRegistry indexes the functions of a validated config.
+func NewRegistry(c *Config) *Registry {
+	r := &Registry{
+		functions: make(map[string]Function, len(c.Functions)),
+		aliases:   make(map[string]string),
+		names:     sortedKeys(c.Functions),
+		groups:    sortedKeys(c.AccessGroups),
+		granting:  make(map[string]bool),
+	}
+	for name, fn := range c.Functions {
+		r.functions[name] = fn
+		for _, group := range fn.Access {
+			r.granting[group] = true
+		}
+		for _, alias := range fn.Aliases {
+			r.aliases[alias] = name
+		}
+		if id := FunctionIdentifier(name); id != name {
+			r.aliases[id] = name
+		}
+	}
+	return r
+}
+
+// Lookup returns the function with the given name, alias or identifier
+// (see FunctionIdentifier), and its name.
+func (r *Registry) Lookup(name string) (string, Function, bool) {
+	if target, ok := r.aliases[name]; ok {
+		name = target
+	}
+	fn, ok := r.functions[name]
+	return name, fn, ok
+}
+
+// Names returns the function names in sorted order. The slice is shared
+// and must not be modified.
+func (r *Registry) Names() []string {
+	return r.names
+}
+
+// AccessGroups returns the access group names in sorted order. The slice
+// is shared and must not be modified.
+func (r *Registry) AccessGroups() []string {
+	return r.groups
+}
+
+// Callable returns the sorted names of the functions callers with the
+// given access groups pass CheckAccess for. Groups no function grants
+// access to don't change the result and are ignored, so callers sending
+// arbitrary groups can't grow the cache. The result for each set of groups
+// is computed once; the slice is shared and must not be modified.
+func (r *Registry) Callable(accessGroups []string) []string {
+	var granted []string
+	for _, group := range accessGroups {
+		if r.granting[group] {
+			granted = append(granted, group)
+		}
+	}
+	key := groupsKey(granted)
+	if names, ok := r.callable.Load(key); ok {
+		return names.([]string)
+	}
+	var names []string
+	for _, name := range r.names {
+		fn := r.functions[name]
+		if fn.CheckAccess(granted) {
+			names = append(names, name)
+		}
+	}
+	actual, _ := r.callable.LoadOrStore(key, names)
+	return actual.([]string)
+}
+
+// groupsKey identifies a set of access groups regardless of order.
+func groupsKey(groups []string) string {
+	sorted := slices.Clone(groups)
+	slices.Sort(sorted)
+	return strings.Join(slices.Compact(sorted), "\x00")
+}
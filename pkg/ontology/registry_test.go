@@ -0,0 +1,113 @@
+package ontology
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	config := queryConfig()
+	fn := config.Functions["getCustomer"]
+	fn.Aliases = []string{"fetchCustomer"}
+	config.Functions["getCustomer"] = fn
+	registry := NewRegistry(config)
+
+	if name, fn, ok := registry.Lookup("fetchCustomer"); !ok || name != "getCustomer" || fn.Description != config.Functions["getCustomer"].Description {
+		t.Errorf("Lookup(alias) = %q, %v", name, ok)
+	}
+	if _, _, ok := registry.Lookup("nope"); ok {
+		t.Error("Lookup should miss unknown names")
+	}
+	if !reflect.DeepEqual(registry.AccessGroups(), []string{"admin", "support"}) {
+		t.Errorf("AccessGroups = %v", registry.AccessGroups())
+	}
+
+	want := []string{"getCustomer", "pipeline", "sendMessage"}
+	if got := registry.Callable([]string{"support"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Callable(support) = %v, want %v", got, want)
+	}
+	all := registry.Callable([]string{"support", "admin"})
+	if !reflect.DeepEqual(all, registry.Names()) {
+		t.Errorf("Callable(admin, support) = %v", all)
+	}
+	// The same groups in another order share the cached list
+	again := registry.Callable([]string{"admin", "support", "admin"})
+	if &again[0] != &all[0] {
+		t.Error("Callable should reuse the list for the same groups")
+	}
+	// Groups no function grants access to don't add cache entries
+	for i := range 100 {
+		registry.Callable([]string{"support", "admin", fmt.Sprintf("unknown%d", i)})
+	}
+	entries := 0
+	registry.callable.Range(func(key, value any) bool {
+		entries++
+		return true
+	})
+	if entries != 2 {
+		t.Errorf("cache has %d entries, want 2", entries)
+	}
+
+	// Later changes to the config aren't reflected
+	delete(config.Functions, "getCustomer")
+	if _, _, ok := registry.Lookup("getCustomer"); !ok {
+		t.Error("registry should be a snapshot")
+	}
+}
+
+// largeConfig returns a config with n functions spread over 10 access groups.
+func largeConfig(n int) *Config {
+	config := &Config{
+		Name:         "large",
+		AccessGroups: map[string]AccessGroup{},
+		Entities:     map[string]Entity{},
+		Functions:    map[string]Function{},
+	}
+	for g := 0; g < 10; g++ {
+		config.AccessGroups[fmt.Sprintf("group%d", g)] = AccessGroup{Description: "Group"}
+	}
+	for i := 0; i < n; i++ {
+		config.Functions[fmt.Sprintf("fn%d", i)] = Function{
+			Description: "Function",
+			Access:      []string{fmt.Sprintf("group%d", i%10)},
+			Inputs:      Object(map[string]Schema{}),
+			Outputs:     Object(map[string]Schema{}),
+			Aliases:     []string{fmt.Sprintf("oldFn%d", i)},
+		}
+	}
+	return config
+}
+
+func BenchmarkResolveAlias(b *testing.B) {
+	config := largeConfig(2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config.ResolveAlias("oldFn1999")
+	}
+}
+
+func BenchmarkRegistryLookup(b *testing.B) {
+	registry := NewRegistry(largeConfig(2000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		registry.Lookup("oldFn1999")
+	}
+}
+
+func BenchmarkRegistryCallable(b *testing.B) {
+	registry := NewRegistry(largeConfig(2000))
+	groups := []string{"group3", "group1"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		registry.Callable(groups)
+	}
+}
+
+func BenchmarkNewRegistry(b *testing.B) {
+	config := largeConfig(2000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewRegistry(config)
+	}
+}
@@ -0,0 +1,48 @@
+package ontology
+
+import "fmt"
+
+// Cardinality describes how many entities a relation links on each side.
+type Cardinality string
+
+// Cardinalities, read from the entity declaring the relation to its target.
+const (
+	OneToOne   Cardinality = "one-to-one"
+	OneToMany  Cardinality = "one-to-many"
+	ManyToOne  Cardinality = "many-to-one"
+	ManyToMany Cardinality = "many-to-many"
+)
+
+// Relation links an entity to another, e.g. User -> owns -> Order.
+type Relation struct {
+	// Name is the verb, e.g. "owns". It is unique among the entity's relations.
+	Name string `json:"name"`
+	// To is the related entity.
+	To          string      `json:"to"`
+	Cardinality Cardinality `json:"cardinality,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
+
+// validateRelations checks that an entity's relations are named once each
+// and point to defined entities.
+func (c *Config) validateRelations(entity Entity) error {
+	seen := make(map[string]bool, len(entity.Relations))
+	for _, rel := range entity.Relations {
+		if rel.Name == "" {
+			return fmt.Errorf("relation to '%s' has no name", rel.To)
+		}
+		if seen[rel.Name] {
+			return fmt.Errorf("relation '%s' is declared twice", rel.Name)
+		}
+		seen[rel.Name] = true
+		if _, ok := c.Entities[rel.To]; !ok {
+			return fmt.Errorf("relation '%s' references unknown entity '%s'", rel.Name, rel.To)
+		}
+		switch rel.Cardinality {
+		case "", OneToOne, OneToMany, ManyToOne, ManyToMany:
+		default:
+			return fmt.Errorf("relation '%s' has unknown cardinality '%s'", rel.Name, rel.Cardinality)
+		}
+	}
+	return nil
+}
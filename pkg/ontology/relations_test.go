@@ -0,0 +1,92 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func relationsConfig() *Config {
+	return &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities: map[string]Entity{
+			"User": {
+				Description: "A user",
+				Relations: []Relation{
+					{Name: "owns", To: "Order", Cardinality: OneToMany},
+					{Name: "manages", To: "User"},
+				},
+			},
+			"Order": {Description: "An order"},
+		},
+		Functions: map[string]Function{},
+	}
+}
+
+func TestRelations(t *testing.T) {
+	config := relationsConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	var relates []GraphEdge
+	for _, e := range config.Graph().Edges {
+		if e.Kind == GraphRelates {
+			relates = append(relates, e)
+		}
+	}
+	want := []GraphEdge{
+		{From: "entity:User", To: "entity:Order", Kind: GraphRelates, Label: "owns", Cardinality: OneToMany},
+		{From: "entity:User", To: "entity:User", Kind: GraphRelates, Label: "manages"},
+	}
+	if !reflect.DeepEqual(relates, want) {
+		t.Errorf("relation edges = %v, want %v", relates, want)
+	}
+	if dot := config.Graph().DOT(); !strings.Contains(dot, `"entity:User" -> "entity:Order" [label="owns (one-to-many)", style=dotted];`) {
+		t.Errorf("DOT() should label the relation, got:\n%s", dot)
+	}
+}
+
+func TestRelationsInvalid(t *testing.T) {
+	tests := []struct {
+		relation Relation
+		want     string
+	}{
+		{Relation{Name: "owns", To: "Invoice"}, "entity 'Order': relation 'owns' references unknown entity 'Invoice'"},
+		{Relation{To: "User"}, "entity 'Order': relation to 'User' has no name"},
+		{Relation{Name: "owns", To: "User", Cardinality: "some"}, "entity 'Order': relation 'owns' has unknown cardinality 'some'"},
+	}
+	for _, tt := range tests {
+		config := relationsConfig()
+		config.Entities["Order"] = Entity{Description: "An order", Relations: []Relation{tt.relation}}
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate() = %v, want %q", err, tt.want)
+		}
+	}
+
+	config := relationsConfig()
+	config.Entities["Order"] = Entity{Description: "An order", Relations: []Relation{{Name: "for", To: "User"}, {Name: "for", To: "Order"}}}
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "relation 'for' is declared twice") {
+		t.Errorf("Validate() = %v, want duplicate relation error", err)
+	}
+}
+
+func TestRelationsLock(t *testing.T) {
+	config := relationsConfig()
+	old := config.ExtractSnapshot()
+	oldHash := config.Hash()
+	if got := old.EntityShapes["User"].Relations; len(got) != 2 {
+		t.Errorf("snapshot relations = %v", got)
+	}
+
+	user := config.Entities["User"]
+	user.Relations = []Relation{{Name: "owns", To: "Order", Cardinality: ManyToMany}}
+	config.Entities["User"] = user
+	if diff := DiffSnapshots(old, config.ExtractSnapshot()); !reflect.DeepEqual(diff.ModifiedEntities, []string{"User"}) {
+		t.Errorf("ModifiedEntities = %v, want [User]", diff.ModifiedEntities)
+	}
+	if config.Hash() == oldHash {
+		t.Error("changing relations should change the hash")
+	}
+}
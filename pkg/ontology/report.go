@@ -0,0 +1,246 @@
+package ontology
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Report formats.
+const (
+	ReportMarkdown = "markdown"
+	ReportHTML     = "html"
+)
+
+// reportInputKey is the template name of the report's own input.
+const reportInputKey = "input"
+
+// ReportSource is a function whose output feeds a report.
+type ReportSource struct {
+	// Function is the name of the function to call.
+	Function string
+	// Input builds the function's input from the report's input. When nil,
+	// the report's input is passed through unchanged.
+	Input func(input map[string]any) map[string]any
+}
+
+// ReportConfig defines a report function.
+type ReportConfig struct {
+	Description string
+	Access      []string
+	Entities    []string
+	// Inputs is the report's input schema. Defaults to an empty object.
+	Inputs Schema
+	// Sources are called in name order when the report runs. The template
+	// sees each output in its JSON form under the source's name, and the
+	// report's input as .input.
+	Sources map[string]ReportSource
+	// Template is a text/template for markdown reports or an html/template
+	// for HTML reports.
+	Template string
+	// Format is ReportMarkdown (the default) or ReportHTML.
+	Format string
+	// Funcs adds template functions to the built-in "json" and "table".
+	Funcs map[string]any
+}
+
+// Report creates a read-only function that calls its sources and renders
+// their outputs with a template, so report functions can be composed from
+// existing data functions without resolver code. The output is
+// {"data": <rendered report>, "format": <format>}; markdown reports use the
+// markdown UI so MCP clients render them. Report panics if the template
+// doesn't parse. Sources are checked by Config.Validate and each source call
+// enforces that function's access groups.
+func (c *Config) Report(r ReportConfig) Function {
+	format := r.Format
+	if format == "" {
+		format = ReportMarkdown
+	}
+	inputs := r.Inputs
+	if inputs == nil {
+		inputs = Object(map[string]Schema{})
+	}
+
+	render := parseReportTemplate(format, r.Template, r.Funcs)
+	sourceNames := sortedKeys(r.Sources)
+
+	fn := Function{
+		Description: r.Description,
+		Access:      r.Access,
+		Entities:    r.Entities,
+		Inputs:      inputs,
+		Outputs: Object(map[string]Schema{
+			"data":   String().Describe("The rendered report"),
+			"format": String().Enum(ReportMarkdown, ReportHTML),
+		}),
+		IsReadOnly:            true,
+		IncludeInMcpListTools: true,
+		Resolver: func(ctx Context, input any) (any, error) {
+			reportInput, _ := toJSONValue(input).(map[string]any)
+			if reportInput == nil {
+				reportInput = map[string]any{}
+			}
+
+			data := map[string]any{reportInputKey: reportInput}
+			for _, name := range sourceNames {
+				source := r.Sources[name]
+				sourceInput := reportInput
+				if source.Input != nil {
+					sourceInput = source.Input(reportInput)
+				}
+				output, err := c.Call(ctx, source.Function, sourceInput)
+				if err != nil {
+					return nil, fmt.Errorf("report source '%s': %w", name, err)
+				}
+				data[name] = toJSONValue(output)
+			}
+
+			var buf bytes.Buffer
+			if err := render(&buf, data); err != nil {
+				return nil, fmt.Errorf("failed to render report: %w", err)
+			}
+			return map[string]any{"data": buf.String(), "format": format}, nil
+		},
+	}
+	if format == ReportMarkdown {
+		fn.UI = &UiConfig{Type: "markdown"}
+	}
+	for _, name := range sourceNames {
+		fn.calls = append(fn.calls, r.Sources[name].Function)
+	}
+	return fn
+}
+
+// parseReportTemplate parses a report template for the given format and
+// returns a function that executes it.
+func parseReportTemplate(format, text string, funcs map[string]any) func(*bytes.Buffer, any) error {
+	switch format {
+	case ReportMarkdown:
+		tmpl := template.New("report").Funcs(template.FuncMap{
+			"json":  reportJSON,
+			"table": markdownTable,
+		})
+		tmpl = template.Must(tmpl.Funcs(funcs).Parse(text))
+		return func(buf *bytes.Buffer, data any) error {
+			return tmpl.Execute(buf, data)
+		}
+	case ReportHTML:
+		tmpl := htmltemplate.New("report").Funcs(htmltemplate.FuncMap{
+			"json":  reportJSON,
+			"table": htmlTable,
+		})
+		tmpl = htmltemplate.Must(tmpl.Funcs(funcs).Parse(text))
+		return func(buf *bytes.Buffer, data any) error {
+			return tmpl.Execute(buf, data)
+		}
+	default:
+		panic(fmt.Sprintf("ontology: unknown report format '%s'", format))
+	}
+}
+
+// reportJSON formats a value as indented JSON.
+func reportJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	return string(data), err
+}
+
+// tableRows reads the rows of a table helper argument (an array of objects
+// in JSON form) and the columns to show, defaulting to the first row's
+// keys in sorted order.
+func tableRows(rows any, columns []string) ([]map[string]any, []string, error) {
+	items, ok := rows.([]any)
+	if !ok {
+		return nil, nil, fmt.Errorf("table: expected an array, got %T", rows)
+	}
+	result := make([]map[string]any, len(items))
+	for i, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("table: row %d is not an object", i)
+		}
+		result[i] = m
+	}
+	if len(columns) == 0 && len(result) > 0 {
+		columns = sortedKeys(result[0])
+	}
+	return result, columns, nil
+}
+
+// markdownTable renders an array of objects as a markdown table, e.g.
+// {{ table .orders.items "id" "total" }}.
+func markdownTable(rows any, columns ...string) (string, error) {
+	items, columns, err := tableRows(rows, columns)
+	if err != nil || len(columns) == 0 {
+		return "", err
+	}
+
+	cell := func(v any) string {
+		if v == nil {
+			return ""
+		}
+		s := fmt.Sprint(v)
+		return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+	}
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, item := range items {
+		cells := make([]string, len(columns))
+		for i, col := range columns {
+			cells[i] = cell(item[col])
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return b.String(), nil
+}
+
+// htmlTable renders an array of objects as an HTML table.
+func htmlTable(rows any, columns ...string) (htmltemplate.HTML, error) {
+	items, columns, err := tableRows(rows, columns)
+	if err != nil || len(columns) == 0 {
+		return "", err
+	}
+
+	cell := func(tag string, v any) string {
+		if v == nil {
+			v = ""
+		}
+		return "<" + tag + ">" + htmltemplate.HTMLEscapeString(fmt.Sprint(v)) + "</" + tag + ">"
+	}
+
+	var b strings.Builder
+	b.WriteString("<table>\n<thead><tr>")
+	for _, col := range columns {
+		b.WriteString(cell("th", col))
+	}
+	b.WriteString("</tr></thead>\n<tbody>\n")
+	for _, item := range items {
+		b.WriteString("<tr>")
+		for _, col := range columns {
+			b.WriteString(cell("td", item[col]))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>")
+	return htmltemplate.HTML(b.String()), nil
+}
+
+// validateCalls checks that the functions a composed function calls exist.
+func (c *Config) validateCalls(name string, fn Function) error {
+	calls := append([]string(nil), fn.calls...)
+	sort.Strings(calls)
+	for _, callee := range calls {
+		if callee == name {
+			return fmt.Errorf("function '%s' calls itself", name)
+		}
+		if _, ok := c.Functions[callee]; !ok {
+			return fmt.Errorf("function '%s' calls unknown function '%s'", name, callee)
+		}
+	}
+	return nil
+}
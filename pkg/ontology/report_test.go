@@ -0,0 +1,136 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func reportConfig() *Config {
+	type order struct {
+		ID    string  `json:"id"`
+		Total float64 `json:"total"`
+	}
+	return &Config{
+		Name: "test",
+		AccessGroups: map[string]AccessGroup{
+			"support": {Description: "Support"},
+			"admin":   {Description: "Admins"},
+		},
+		Entities: map[string]Entity{},
+		Functions: map[string]Function{
+			"listOrders": {
+				Description: "List orders",
+				Access:      []string{"support"},
+				Inputs:      Object(map[string]Schema{"status": String()}),
+				Outputs: Object(map[string]Schema{
+					"orders": Array(Object(map[string]Schema{"id": String(), "total": Number()})),
+				}),
+				Resolver: func(ctx Context, input any) (any, error) {
+					return map[string]any{"orders": []order{{ID: "A-1", Total: 12.5}, {ID: "A|2", Total: 3}}}, nil
+				},
+			},
+			"revenue": {
+				Description: "Total revenue",
+				Access:      []string{"admin"},
+				Inputs:      Object(map[string]Schema{}),
+				Outputs:     Object(map[string]Schema{"total": Number()}),
+				Resolver: func(ctx Context, input any) (any, error) {
+					return map[string]any{"total": 15.5}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestReport(t *testing.T) {
+	config := reportConfig()
+	config.Functions["openOrdersReport"] = config.Report(ReportConfig{
+		Description: "Open orders report",
+		Access:      []string{"support"},
+		Inputs:      Object(map[string]Schema{"title": String()}),
+		Sources: map[string]ReportSource{
+			"open": {
+				Function: "listOrders",
+				Input: func(input map[string]any) map[string]any {
+					return map[string]any{"status": "open"}
+				},
+			},
+		},
+		Template: "# {{ .input.title }}\n\n{{ table .open.orders \"id\" \"total\" }}",
+	})
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	fn := config.Functions["openOrdersReport"]
+	if fn.UI == nil || fn.UI.Type != "markdown" || !fn.IsReadOnly {
+		t.Errorf("markdown reports should be read-only and use the markdown UI")
+	}
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	output, err := config.Call(ctx, "openOrdersReport", map[string]any{"title": "Open orders"})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if err := fn.ValidateOutput(output); err != nil {
+		t.Errorf("report output invalid: %v", err)
+	}
+
+	want := "# Open orders\n\n| id | total |\n| --- | --- |\n| A-1 | 12.5 |\n| A\\|2 | 3 |\n"
+	if got := output.(map[string]any)["data"]; got != want {
+		t.Errorf("report =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestReportHTML(t *testing.T) {
+	config := reportConfig()
+	config.Functions["ordersPage"] = config.Report(ReportConfig{
+		Description: "Orders page",
+		Access:      []string{"support"},
+		Inputs:      Object(map[string]Schema{"status": String()}),
+		Sources:     map[string]ReportSource{"all": {Function: "listOrders"}},
+		Template:    `<h1>Orders</h1>{{ table .all.orders "id" }}`,
+		Format:      ReportHTML,
+	})
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	output, err := config.Call(ctx, "ordersPage", map[string]any{"status": "open"})
+	if err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	data := output.(map[string]any)["data"].(string)
+	if !strings.Contains(data, "<td>A|2</td>") || output.(map[string]any)["format"] != ReportHTML {
+		t.Errorf("unexpected HTML report: %s", data)
+	}
+}
+
+func TestReportSourceAccess(t *testing.T) {
+	config := reportConfig()
+	config.Functions["revenueReport"] = config.Report(ReportConfig{
+		Description: "Revenue",
+		Access:      []string{"support"},
+		Sources:     map[string]ReportSource{"revenue": {Function: "revenue"}},
+		Template:    "{{ .revenue.total }}",
+	})
+
+	ctx := NewContext(nil, DefaultLogger(), []string{"support"}, nil)
+	_, err := config.Call(ctx, "revenueReport", map[string]any{})
+	if err == nil || !strings.Contains(err.Error(), "function 'revenue': access denied") {
+		t.Errorf("Call error = %v, want source access denied", err)
+	}
+}
+
+func TestReportUnknownSource(t *testing.T) {
+	config := reportConfig()
+	config.Functions["broken"] = config.Report(ReportConfig{
+		Description: "Broken",
+		Access:      []string{"support"},
+		Sources:     map[string]ReportSource{"x": {Function: "missing"}},
+		Template:    "{{ .x }}",
+	})
+
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "function 'broken' calls unknown function 'missing'") {
+		t.Errorf("Validate error = %v, want unknown source", err)
+	}
+}
@@ -0,0 +1,167 @@
+package ontology
+
+import "fmt"
+
+// ExprRule is a validation rule written as an expression (see ParseExpr).
+type ExprRule struct {
+	Expr    string `json:"expr"`
+	Message string `json:"message,omitempty"`
+}
+
+// objectRule is a compiled ExprRule.
+type objectRule struct {
+	ExprRule
+	expr *Expr
+}
+
+// Rule adds a validation rule over the object's fields, e.g.
+// Rule("start <= end", "start must not be after end"). The expression reads
+// fields by name, must evaluate to true, and fails with message otherwise.
+// Rules are emitted as "x-ont-rules" in JSON Schema, so they are recorded in
+// the lock file. Rule panics if expr doesn't parse; Config.Validate checks
+// that it only reads declared fields.
+func (o *ObjectSchema) Rule(expr, message string) *ObjectSchema {
+	compiled, err := ParseExpr(expr)
+	if err != nil {
+		panic(fmt.Sprintf("ontology: %v", err))
+	}
+	o.rules = append(o.rules, objectRule{ExprRule{Expr: expr, Message: message}, compiled})
+	return o
+}
+
+// Rules returns the rules added with Rule.
+func (o *ObjectSchema) Rules() []ExprRule {
+	rules := make([]ExprRule, len(o.rules))
+	for i, rule := range o.rules {
+		rules[i] = rule.ExprRule
+	}
+	return rules
+}
+
+// checkRules evaluates the object's rules and refinements against data
+// whose fields have already been validated.
+func (o *ObjectSchema) checkRules(data any) error {
+	if len(o.rules) == 0 {
+		return o.refine(data)
+	}
+	env, _ := toJSONValue(data).(map[string]any)
+	for _, rule := range o.rules {
+		ok, err := rule.expr.EvalBool(env)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if rule.Message != "" {
+				return fmt.Errorf("%s", rule.Message)
+			}
+			return fmt.Errorf("rule %q failed", rule.Expr)
+		}
+	}
+	return o.refine(data)
+}
+
+// ComputedExpr declares a computed property whose value is an expression
+// over the object's other fields, e.g. ComputedExpr("total", Number(),
+// "price * quantity"). Unlike Computed, the logic is part of the schema:
+// the expression is emitted as "x-ont-expression" and recorded in the lock
+// file. ComputedExpr panics if expr doesn't parse.
+func (o *ObjectSchema) ComputedExpr(name string, schema Schema, expr string) *ObjectSchema {
+	compiled, err := ParseExpr(expr)
+	if err != nil {
+		panic(fmt.Sprintf("ontology: computed field '%s': %v", name, err))
+	}
+	if o.computedExprs == nil {
+		o.computedExprs = make(map[string]string)
+	}
+	o.computedExprs[name] = expr
+	return o.Computed(name, schema, func(obj map[string]any) (any, error) {
+		return compiled.Eval(obj)
+	})
+}
+
+// objectExprs returns every rule and computed expression declared on an
+// object, for checking and auditing.
+func (o *ObjectSchema) objectExprs() []string {
+	var exprs []string
+	for _, rule := range o.rules {
+		exprs = append(exprs, rule.Expr)
+	}
+	for _, name := range sortedKeys(o.computedExprs) {
+		exprs = append(exprs, o.computedExprs[name])
+	}
+	return exprs
+}
+
+// checkSchemaExprs checks that the rules and computed expressions of every
+// object in schema only read fields that object declares.
+func checkSchemaExprs(schema Schema) error {
+	var err error
+	walkSchema(schema, func(s Schema) {
+		obj, ok := s.(*ObjectSchema)
+		if !ok || err != nil {
+			return
+		}
+		scope := &exprScope{schemas: obj.properties, roots: "a field of the object"}
+		for _, source := range obj.objectExprs() {
+			if serr := scope.checkExpr(source); serr != nil {
+				err = serr
+				return
+			}
+		}
+	})
+	return err
+}
+
+// Roots of access rule expressions.
+const (
+	accessRuleInput  = "input"
+	accessRuleUser   = "user"
+	accessRuleGroups = "accessGroups"
+)
+
+// CheckAccessRule evaluates the function's AccessRule, if any, for a caller
+// with the given access groups and user context and a validated input. It
+// reports false when the rule evaluates to false or can't be evaluated.
+func (f *Function) CheckAccessRule(accessGroups []string, userContext map[string]any, input any) (bool, error) {
+	if f.AccessRule == "" {
+		return true, nil
+	}
+	expr, err := ParseExpr(f.AccessRule)
+	if err != nil {
+		return false, err
+	}
+	groups := make([]any, len(accessGroups))
+	for i, group := range accessGroups {
+		groups[i] = group
+	}
+	user, _ := toJSONValue(userContext).(map[string]any)
+	ok, err := expr.EvalBool(map[string]any{
+		accessRuleInput:  toJSONValue(input),
+		accessRuleUser:   user,
+		accessRuleGroups: groups,
+	})
+	if err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// validateAccessRule checks that an access rule parses and only reads the
+// function's declared inputs, the user context and the access groups.
+func validateAccessRule(fn Function) error {
+	if fn.AccessRule == "" {
+		return nil
+	}
+	scope := &exprScope{
+		schemas: map[string]Schema{
+			accessRuleInput:  fn.Inputs,
+			accessRuleUser:   Any(),
+			accessRuleGroups: Array(String()),
+		},
+		roots: "input, user or accessGroups",
+	}
+	if err := scope.checkExpr(fn.AccessRule); err != nil {
+		return fmt.Errorf("access rule: %w", err)
+	}
+	return nil
+}
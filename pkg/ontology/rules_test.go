@@ -0,0 +1,200 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestObjectRule(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"start": Integer(),
+		"end":   Integer(),
+		"code":  String(),
+	}).Optional("code").
+		Rule("start <= end", "start must not be after end").
+		Rule("code == null || len(code) == 3", "")
+
+	if err := schema.Validate(map[string]any{"start": 1, "end": 2}); err != nil {
+		t.Errorf("valid data rejected: %v", err)
+	}
+	if err := schema.Validate(map[string]any{"start": 3, "end": 2}); err == nil || err.Error() != "start must not be after end" {
+		t.Errorf("error = %v, want rule message", err)
+	}
+	err := schema.Validate(map[string]any{"start": 1, "end": 2, "code": "ab"})
+	if err == nil || !strings.Contains(err.Error(), `rule "code == null || len(code) == 3" failed`) {
+		t.Errorf("error = %v, want rule failure", err)
+	}
+
+	type window struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	}
+	if err := schema.Validate(window{Start: 5, End: 1}); err == nil {
+		t.Error("rules should apply to structs")
+	}
+
+	// Rules are part of the JSON Schema and so of the lock file
+	want := []ExprRule{
+		{Expr: "start <= end", Message: "start must not be after end"},
+		{Expr: "code == null || len(code) == 3"},
+	}
+	if got := schema.JSONSchema()["x-ont-rules"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("x-ont-rules = %v, want %v", got, want)
+	}
+}
+
+func TestObjectRulePanicsOnBadExpression(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Rule should panic on an invalid expression")
+		}
+	}()
+	Object(map[string]Schema{}).Rule("a ==", "")
+}
+
+func TestComputedExpr(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"price":    Number(),
+		"quantity": Integer(),
+	}).ComputedExpr("total", Number(), "price * quantity")
+
+	output, err := ApplyComputed(schema, map[string]any{"price": 2.5, "quantity": 4})
+	if err != nil {
+		t.Fatalf("ApplyComputed failed: %v", err)
+	}
+	if total := output.(map[string]any)["total"]; total != 10.0 {
+		t.Errorf("total = %v, want 10", total)
+	}
+
+	total := schema.JSONSchema()["properties"].(map[string]any)["total"].(map[string]any)
+	if total["x-ont-expression"] != "price * quantity" || total["readOnly"] != true {
+		t.Errorf("total schema = %v", total)
+	}
+}
+
+func TestSchemaExpressionsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs Schema
+		wantErr string
+	}{
+		{
+			name:    "rule reads unknown field",
+			outputs: Object(map[string]Schema{"a": Integer()}).Rule("a < b", ""),
+			wantErr: "function 'get' outputs: 'b' is not a field of the object",
+		},
+		{
+			name:    "computed expression reads unknown nested field",
+			outputs: Object(map[string]Schema{"a": Object(map[string]Schema{"x": Integer()})}).ComputedExpr("y", Integer(), "a.y + 1"),
+			wantErr: "'a.y': unknown field 'y'",
+		},
+		{
+			name:    "nested object rule",
+			outputs: Object(map[string]Schema{"items": Array(Object(map[string]Schema{"qty": Integer()}).Rule("qty > 0", ""))}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := accessRuleConfig(Function{
+				Inputs:  Object(map[string]Schema{}),
+				Outputs: tt.outputs,
+			})
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func accessRuleConfig(fn Function) *Config {
+	fn.Description = "Get a document"
+	fn.Access = []string{"user", "admin"}
+	if fn.Resolver == nil {
+		fn.Resolver = func(ctx Context, input any) (any, error) {
+			return map[string]any{"ok": true}, nil
+		}
+	}
+	return &Config{
+		Name: "test",
+		AccessGroups: map[string]AccessGroup{
+			"user":  {Description: "Users"},
+			"admin": {Description: "Admins"},
+		},
+		Entities:  map[string]Entity{},
+		Functions: map[string]Function{"get": fn},
+	}
+}
+
+func TestAccessRule(t *testing.T) {
+	config := accessRuleConfig(Function{
+		Inputs:     Object(map[string]Schema{"orgId": String()}),
+		Outputs:    Object(map[string]Schema{"ok": Boolean()}),
+		AccessRule: "input.orgId == user.orgId || 'admin' in accessGroups",
+	})
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		groups  []string
+		user    map[string]any
+		allowed bool
+	}{
+		{"same org", []string{"user"}, map[string]any{"orgId": "o-1"}, true},
+		{"other org", []string{"user"}, map[string]any{"orgId": "o-2"}, false},
+		{"no user context", []string{"user"}, nil, false},
+		{"admin", []string{"admin"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(nil, DefaultLogger(), tt.groups, tt.user)
+			_, err := config.Call(ctx, "get", map[string]any{"orgId": "o-1"})
+			if tt.allowed && err != nil {
+				t.Errorf("Call failed: %v", err)
+			}
+			if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "access denied")) {
+				t.Errorf("Call error = %v, want access denied", err)
+			}
+		})
+	}
+
+	// The rule is audited through the lock file and the hash
+	if got := config.ExtractSnapshot().Functions["get"].AccessRule; got != config.Functions["get"].AccessRule {
+		t.Errorf("snapshot access rule = %q", got)
+	}
+	before := config.Hash()
+	fn := config.Functions["get"]
+	fn.AccessRule = "'admin' in accessGroups"
+	config.Functions["get"] = fn
+	if config.Hash() == before {
+		t.Error("changing the access rule should change the hash")
+	}
+}
+
+func TestAccessRuleValidation(t *testing.T) {
+	tests := map[string]string{
+		"input.org == user.orgId": "access rule: 'input.org': unknown field 'org'",
+		"caller.admin":            "access rule: 'caller' is not input, user or accessGroups",
+		"input.orgId ==":          "access rule: expression",
+	}
+	for rule, want := range tests {
+		config := accessRuleConfig(Function{
+			Inputs:     Object(map[string]Schema{"orgId": String()}),
+			Outputs:    Object(map[string]Schema{"ok": Boolean()}),
+			AccessRule: rule,
+		})
+		if err := config.Validate(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate(%q) error = %v, want %q", rule, err, want)
+		}
+	}
+}
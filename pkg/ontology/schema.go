@@ -1,9 +1,13 @@
 package ontology
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
+	"strings"
+	"time"
 )
 
 // Schema is the interface that all schema types must implement.
@@ -14,12 +18,29 @@ type Schema interface {
 	JSONSchema() map[string]any
 	// TypeName returns the name of this schema type for error messages.
 	TypeName() string
+	// Constraints returns the schema's own constraints by JSON Schema keyword.
+	Constraints() map[string]any
 }
 
 // ObjectSchema represents an object with named properties.
 type ObjectSchema struct {
+	annotations
+	refinements
+	messages
+	transforms
 	properties map[string]Schema
 	required   []string
+	computed   map[string]ComputeFunc
+	batchItem  Schema
+	strict     bool
+	rules      []objectRule
+	// computedExprs holds the expressions of ComputedExpr properties
+	computedExprs map[string]string
+	// minProperties, maxProperties and patterns constrain the keys of the
+	// object beyond the declared properties
+	minProperties *int
+	maxProperties *int
+	patterns      []patternProperty
 }
 
 // Object creates a new object schema with the given properties.
@@ -81,16 +102,26 @@ func (o *ObjectSchema) Validate(data any) error {
 		return o.validateStruct(val)
 	}
 
-	return fmt.Errorf("expected object, got %v", val.Kind())
+	return o.message(constraintError("type", "object", o.shown(data), "expected object, got %v", val.Kind()))
 }
 
 func (o *ObjectSchema) validateMap(val reflect.Value) error {
-	mapData := val.Interface().(map[string]any)
+	return o.validateMapAllowing(val.Interface().(map[string]any))
+}
+
+// validateMapAllowing validates map data; strict objects also accept the
+// allowed extra keys (e.g. a discriminated union's tag).
+func (o *ObjectSchema) validateMapAllowing(mapData map[string]any, allowed ...string) error {
+	if o.strict {
+		if err := o.unknownField(mapData, allowed...); err != nil {
+			return err
+		}
+	}
 
 	// Check required fields
 	for _, reqName := range o.required {
 		if _, ok := mapData[reqName]; !ok {
-			return fmt.Errorf("required field '%s' is missing", reqName)
+			return missingField(reqName, o.properties[reqName], fmt.Sprintf("required field '%s' is missing", reqName))
 		}
 	}
 
@@ -102,61 +133,85 @@ func (o *ObjectSchema) validateMap(val reflect.Value) error {
 			}
 		}
 	}
+	if err := o.checkKeys(mapData); err != nil {
+		return err
+	}
 
-	return nil
+	return o.checkRules(mapData)
 }
 
 func (o *ObjectSchema) validateStruct(val reflect.Value) error {
-	typ := val.Type()
-
-	// Build a map of JSON tag names to field indices
-	fieldMap := make(map[string]int)
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			fieldMap[field.Name] = i
-		} else {
-			// Handle "name,omitempty" format
-			name := jsonTag
-			for j := 0; j < len(jsonTag); j++ {
-				if jsonTag[j] == ',' {
-					name = jsonTag[:j]
-					break
-				}
-			}
-			fieldMap[name] = i
-		}
-	}
-
-	// Validate each property
+	// Validate each property against its field as encoding/json encodes it
 	for propName, propSchema := range o.properties {
-		fieldIdx, ok := fieldMap[propName]
-		if !ok {
-			// Try capitalized version
-			fieldIdx, ok = fieldMap[capitalize(propName)]
+		field, fieldVal, ok := lookupField(val, propName)
+
+		// Fields encoding/json omits are missing, as they are on the wire
+		if ok && omitted(field.field, fieldVal) {
+			ok = false
 		}
 
 		if !ok {
 			if contains(o.required, propName) {
-				return fmt.Errorf("required field '%s' is missing", propName)
+				return missingField(propName, propSchema, fmt.Sprintf("required field '%s' is missing", propName))
 			}
 			continue
 		}
 
-		fieldVal := val.Field(fieldIdx)
-		if err := propSchema.Validate(fieldVal.Interface()); err != nil {
+		if err := propSchema.Validate(fieldValue(fieldVal)); err != nil {
 			return fmt.Errorf("field '%s': %w", propName, err)
 		}
 	}
+	if err := o.checkStructKeys(val.Interface()); err != nil {
+		return err
+	}
 
-	return nil
+	return o.checkRules(val.Interface())
+}
+
+// omitted reports whether encoding/json leaves the struct field out: it is
+// tagged omitempty and empty, or omitzero and zero.
+func omitted(field reflect.StructField, val reflect.Value) bool {
+	_, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "omitempty":
+			switch val.Kind() {
+			case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+				if val.Len() == 0 {
+					return true
+				}
+			case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+				if val.IsZero() {
+					return true
+				}
+			}
+		case "omitzero":
+			// Like encoding/json, prefer the type's own IsZero method
+			if z, ok := val.Interface().(interface{ IsZero() bool }); ok && !(val.Kind() == reflect.Pointer && val.IsNil()) {
+				if z.IsZero() {
+					return true
+				}
+			} else if val.IsZero() {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func (o *ObjectSchema) JSONSchema() map[string]any {
 	props := make(map[string]any)
 	for name, schema := range o.properties {
-		props[name] = schema.JSONSchema()
+		propSchema := schema.JSONSchema()
+		if _, ok := o.computed[name]; ok {
+			propSchema = withReadOnly(propSchema)
+		}
+		if expr, ok := o.computedExprs[name]; ok {
+			propSchema["x-ont-expression"] = expr
+		}
+		props[name] = propSchema
 	}
 
 	result := map[string]any{
@@ -167,12 +222,31 @@ func (o *ObjectSchema) JSONSchema() map[string]any {
 	if len(o.required) > 0 {
 		result["required"] = o.required
 	}
+	if o.strict {
+		result["additionalProperties"] = false
+	}
+	if o.minProperties != nil {
+		result["minProperties"] = *o.minProperties
+	}
+	if o.maxProperties != nil {
+		result["maxProperties"] = *o.maxProperties
+	}
+	if len(o.patterns) > 0 {
+		result["patternProperties"] = o.patternsJSONSchema()
+	}
+	if len(o.rules) > 0 {
+		result["x-ont-rules"] = o.Rules()
+	}
 
-	return result
+	return o.annotateRefinements(o.annotate(result))
 }
 
 // StringSchema represents a string value with optional constraints.
 type StringSchema struct {
+	annotations
+	refinements
+	messages
+	transforms
 	format    string
 	minLength *int
 	maxLength *int
@@ -187,61 +261,72 @@ func String() *StringSchema {
 
 // UUID constrains the string to UUID format.
 func (s *StringSchema) UUID() *StringSchema {
+	s.constrain("format")
 	s.format = "uuid"
 	return s
 }
 
 // Email constrains the string to email format.
 func (s *StringSchema) Email() *StringSchema {
+	s.constrain("format")
 	s.format = "email"
 	return s
 }
 
-// DateTime constrains the string to date-time format.
+// DateTime constrains the string to an RFC 3339 date-time such as
+// "2024-05-01T10:30:00Z". Resolvers may return time.Time (or *time.Time)
+// values for date-time fields; they validate and marshal as RFC 3339.
 func (s *StringSchema) DateTime() *StringSchema {
+	s.constrain("format")
 	s.format = "date-time"
 	return s
 }
 
 // Date constrains the string to date format.
 func (s *StringSchema) Date() *StringSchema {
+	s.constrain("format")
 	s.format = "date"
 	return s
 }
 
 // URI constrains the string to URI format.
 func (s *StringSchema) URI() *StringSchema {
+	s.constrain("format")
 	s.format = "uri"
 	return s
 }
 
 // Min sets the minimum string length.
 func (s *StringSchema) Min(min int) *StringSchema {
+	s.constrain("minLength")
 	s.minLength = &min
 	return s
 }
 
 // Max sets the maximum string length.
 func (s *StringSchema) Max(max int) *StringSchema {
+	s.constrain("maxLength")
 	s.maxLength = &max
 	return s
 }
 
 // Pattern constrains the string to match a regex pattern.
 func (s *StringSchema) Pattern(pattern string) *StringSchema {
+	s.constrain("pattern")
 	s.pattern = regexp.MustCompile(pattern)
 	return s
 }
 
 // Enum constrains the string to a set of allowed values.
 func (s *StringSchema) Enum(values ...string) *StringSchema {
+	s.constrain("enum")
 	s.enum = values
 	return s
 }
 
-// Format returns the string format constraint.
-func (s *StringSchema) Format() string {
-	return s.format
+// EnumValues returns the allowed values, if constrained.
+func (s *StringSchema) EnumValues() []string {
+	return s.enum
 }
 
 func (s *StringSchema) TypeName() string {
@@ -249,21 +334,35 @@ func (s *StringSchema) TypeName() string {
 }
 
 func (s *StringSchema) Validate(data any) error {
+	if err := s.validate(data); err != nil {
+		return s.message(err)
+	}
+	return s.refine(data)
+}
+
+func (s *StringSchema) validate(data any) error {
+	// Resolvers may return time.Time for date-times; it marshals as RFC 3339
+	if s.format == "date-time" {
+		if t, ok := timeValue(data); ok {
+			data = t.Format(time.RFC3339Nano)
+		}
+	}
+
 	str, ok := data.(string)
 	if !ok {
-		return fmt.Errorf("expected string, got %T", data)
+		return constraintError("type", "string", s.shown(data), "expected string, got %T", data)
 	}
 
 	if s.minLength != nil && len(str) < *s.minLength {
-		return fmt.Errorf("string length %d is less than minimum %d", len(str), *s.minLength)
+		return constraintError("minLength", *s.minLength, s.shown(str), "string length %d is less than minimum %d", len(str), *s.minLength)
 	}
 
 	if s.maxLength != nil && len(str) > *s.maxLength {
-		return fmt.Errorf("string length %d exceeds maximum %d", len(str), *s.maxLength)
+		return constraintError("maxLength", *s.maxLength, s.shown(str), "string length %d exceeds maximum %d", len(str), *s.maxLength)
 	}
 
 	if s.pattern != nil && !s.pattern.MatchString(str) {
-		return fmt.Errorf("string does not match pattern")
+		return constraintError("pattern", s.pattern.String(), s.shown(str), "string does not match pattern")
 	}
 
 	if len(s.enum) > 0 {
@@ -275,7 +374,7 @@ func (s *StringSchema) Validate(data any) error {
 			}
 		}
 		if !found {
-			return fmt.Errorf("string '%s' is not one of the allowed values: %v", str, s.enum)
+			return constraintError("enum", s.enum, s.shown(str), "string '%v' is not one of the allowed values: %v", s.shown(str), s.enum)
 		}
 	}
 
@@ -289,33 +388,42 @@ func (s *StringSchema) Validate(data any) error {
 	return nil
 }
 
+// Patterns of the string formats, compiled once.
+var (
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	emailPattern = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	uriPattern   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
 func (s *StringSchema) validateFormat(str string) error {
 	switch s.format {
 	case "uuid":
-		uuidPattern := regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
 		if !uuidPattern.MatchString(str) {
-			return fmt.Errorf("string is not a valid UUID")
+			return constraintError("format", s.format, s.shown(str), "string is not a valid UUID")
 		}
 	case "email":
-		emailPattern := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 		if !emailPattern.MatchString(str) {
-			return fmt.Errorf("string is not a valid email")
+			return constraintError("format", s.format, s.shown(str), "string is not a valid email")
 		}
 	case "date-time":
-		// Basic ISO 8601 check
-		dateTimePattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}`)
-		if !dateTimePattern.MatchString(str) {
-			return fmt.Errorf("string is not a valid date-time")
+		if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+			return constraintError("format", s.format, s.shown(str), "string is not a valid RFC 3339 date-time")
 		}
 	case "date":
-		datePattern := regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
-		if !datePattern.MatchString(str) {
-			return fmt.Errorf("string is not a valid date")
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			return constraintError("format", s.format, s.shown(str), "string is not a valid date")
 		}
 	case "uri":
-		uriPattern := regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
 		if !uriPattern.MatchString(str) {
-			return fmt.Errorf("string is not a valid URI")
+			return constraintError("format", s.format, s.shown(str), "string is not a valid URI")
+		}
+	default:
+		check, ok := lookupFormat(s.format)
+		if !ok {
+			return fmt.Errorf("unknown string format '%s'", s.format)
+		}
+		if err := check(str); err != nil {
+			return constraintError("format", s.format, s.shown(str), "string is not a valid %s: %v", s.format, err)
 		}
 	}
 	return nil
@@ -342,17 +450,23 @@ func (s *StringSchema) JSONSchema() map[string]any {
 		result["enum"] = s.enum
 	}
 
-	return result
+	return s.annotateRefinements(s.annotate(result))
 }
 
 // NumberSchema represents a numeric value with optional constraints.
 type NumberSchema struct {
+	annotations
+	refinements
+	messages
+	transforms
 	minimum          *float64
 	maximum          *float64
 	exclusiveMinimum *float64
 	exclusiveMaximum *float64
 	multipleOf       *float64
 	isInteger        bool
+	isInt64          bool
+	enum             []float64
 }
 
 // Number creates a new number schema.
@@ -365,32 +479,45 @@ func Integer() *NumberSchema {
 	return &NumberSchema{isInteger: true}
 }
 
+// Int64 creates an integer schema for the full 64-bit range, such as IDs
+// above 2^53 that float64 can't hold exactly. It accepts Go integers and
+// json.Number, and rejects floats that may already have lost precision.
+// The JSON Schema has "format": "int64".
+func Int64() *NumberSchema {
+	return &NumberSchema{isInteger: true, isInt64: true}
+}
+
 // Min sets the minimum value.
 func (n *NumberSchema) Min(min float64) *NumberSchema {
+	n.constrain("minimum")
 	n.minimum = &min
 	return n
 }
 
 // Max sets the maximum value.
 func (n *NumberSchema) Max(max float64) *NumberSchema {
+	n.constrain("maximum")
 	n.maximum = &max
 	return n
 }
 
 // ExclusiveMin sets an exclusive minimum value.
 func (n *NumberSchema) ExclusiveMin(min float64) *NumberSchema {
+	n.constrain("exclusiveMinimum")
 	n.exclusiveMinimum = &min
 	return n
 }
 
 // ExclusiveMax sets an exclusive maximum value.
 func (n *NumberSchema) ExclusiveMax(max float64) *NumberSchema {
+	n.constrain("exclusiveMaximum")
 	n.exclusiveMaximum = &max
 	return n
 }
 
 // MultipleOf constrains the number to be a multiple of the given value.
 func (n *NumberSchema) MultipleOf(val float64) *NumberSchema {
+	n.constrain("multipleOf")
 	n.multipleOf = &val
 	return n
 }
@@ -398,6 +525,7 @@ func (n *NumberSchema) MultipleOf(val float64) *NumberSchema {
 // Positive constrains the number to be positive (> 0).
 func (n *NumberSchema) Positive() *NumberSchema {
 	zero := 0.0
+	n.constrain("exclusiveMinimum")
 	n.exclusiveMinimum = &zero
 	return n
 }
@@ -405,6 +533,7 @@ func (n *NumberSchema) Positive() *NumberSchema {
 // Negative constrains the number to be negative (< 0).
 func (n *NumberSchema) Negative() *NumberSchema {
 	zero := 0.0
+	n.constrain("exclusiveMaximum")
 	n.exclusiveMaximum = &zero
 	return n
 }
@@ -412,10 +541,33 @@ func (n *NumberSchema) Negative() *NumberSchema {
 // NonNegative constrains the number to be non-negative (>= 0).
 func (n *NumberSchema) NonNegative() *NumberSchema {
 	zero := 0.0
+	n.constrain("minimum")
 	n.minimum = &zero
 	return n
 }
 
+// Enum constrains the number to a set of allowed values.
+func (n *NumberSchema) Enum(values ...float64) *NumberSchema {
+	n.constrain("enum")
+	n.enum = values
+	return n
+}
+
+// EnumValues returns the allowed values, if constrained.
+func (n *NumberSchema) EnumValues() []float64 {
+	return n.enum
+}
+
+// IsInteger reports whether the schema only accepts integers.
+func (n *NumberSchema) IsInteger() bool {
+	return n.isInteger
+}
+
+// IsInt64 reports whether the schema was created with Int64.
+func (n *NumberSchema) IsInt64() bool {
+	return n.isInt64
+}
+
 func (n *NumberSchema) TypeName() string {
 	if n.isInteger {
 		return "integer"
@@ -424,6 +576,13 @@ func (n *NumberSchema) TypeName() string {
 }
 
 func (n *NumberSchema) Validate(data any) error {
+	if err := n.validate(data); err != nil {
+		return n.message(err)
+	}
+	return n.refine(data)
+}
+
+func (n *NumberSchema) validate(data any) error {
 	var num float64
 
 	switch v := data.(type) {
@@ -437,36 +596,75 @@ func (n *NumberSchema) Validate(data any) error {
 		num = float64(v)
 	case int32:
 		num = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return constraintError("type", n.TypeName(), n.shown(v), "expected number, got '%v'", n.shown(v))
+		}
+		num = f
 	default:
-		return fmt.Errorf("expected number, got %T", data)
+		if !n.isInt64 {
+			return constraintError("type", n.TypeName(), n.shown(data), "expected number, got %T", data)
+		}
 	}
 
-	if n.isInteger {
+	// Bounds are compared as float64, which is exact enough for them, but
+	// int64Value has already checked the value is a whole number
+	var exact int64
+	if n.isInt64 {
+		i, err := int64Value(data)
+		if err != nil {
+			if n.sensitive {
+				return constraintError("type", n.TypeName(), RedactedValue, "expected 64-bit integer")
+			}
+			return constraintError("type", n.TypeName(), data, "%v", err)
+		}
+		exact, num = i, float64(i)
+	}
+
+	if n.isInteger && !n.isInt64 {
 		if num != float64(int64(num)) {
-			return fmt.Errorf("expected integer, got %v", num)
+			return constraintError("type", n.TypeName(), n.shown(num), "expected integer, got %v", n.shown(num))
 		}
 	}
 
 	if n.minimum != nil && num < *n.minimum {
-		return fmt.Errorf("number %v is less than minimum %v", num, *n.minimum)
+		return constraintError("minimum", *n.minimum, n.shown(num), "number %v is less than minimum %v", n.shown(num), *n.minimum)
 	}
 
 	if n.maximum != nil && num > *n.maximum {
-		return fmt.Errorf("number %v exceeds maximum %v", num, *n.maximum)
+		return constraintError("maximum", *n.maximum, n.shown(num), "number %v exceeds maximum %v", n.shown(num), *n.maximum)
 	}
 
 	if n.exclusiveMinimum != nil && num <= *n.exclusiveMinimum {
-		return fmt.Errorf("number %v must be greater than %v", num, *n.exclusiveMinimum)
+		return constraintError("exclusiveMinimum", *n.exclusiveMinimum, n.shown(num), "number %v must be greater than %v", n.shown(num), *n.exclusiveMinimum)
 	}
 
 	if n.exclusiveMaximum != nil && num >= *n.exclusiveMaximum {
-		return fmt.Errorf("number %v must be less than %v", num, *n.exclusiveMaximum)
+		return constraintError("exclusiveMaximum", *n.exclusiveMaximum, n.shown(num), "number %v must be less than %v", n.shown(num), *n.exclusiveMaximum)
 	}
 
-	if n.multipleOf != nil && num != 0 {
+	if n.isInt64 && n.multipleOf != nil && *n.multipleOf == math.Trunc(*n.multipleOf) && *n.multipleOf != 0 {
+		if exact%int64(*n.multipleOf) != 0 {
+			return constraintError("multipleOf", *n.multipleOf, n.shown(exact), "number %v is not a multiple of %v", n.shown(exact), *n.multipleOf)
+		}
+	} else if n.multipleOf != nil && num != 0 {
 		remainder := num / *n.multipleOf
 		if remainder != float64(int64(remainder)) {
-			return fmt.Errorf("number %v is not a multiple of %v", num, *n.multipleOf)
+			return constraintError("multipleOf", *n.multipleOf, n.shown(num), "number %v is not a multiple of %v", n.shown(num), *n.multipleOf)
+		}
+	}
+
+	if len(n.enum) > 0 {
+		found := false
+		for _, v := range n.enum {
+			if v == num {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return constraintError("enum", n.enum, n.shown(num), "number %v is not one of the allowed values: %v", n.shown(num), n.enum)
 		}
 	}
 
@@ -480,6 +678,9 @@ func (n *NumberSchema) JSONSchema() map[string]any {
 	} else {
 		result = map[string]any{"type": "number"}
 	}
+	if n.isInt64 {
+		result["format"] = "int64"
+	}
 
 	if n.minimum != nil {
 		result["minimum"] = *n.minimum
@@ -496,12 +697,20 @@ func (n *NumberSchema) JSONSchema() map[string]any {
 	if n.multipleOf != nil {
 		result["multipleOf"] = *n.multipleOf
 	}
+	if len(n.enum) > 0 {
+		result["enum"] = n.enum
+	}
 
-	return result
+	return n.annotateRefinements(n.annotate(result))
 }
 
 // BooleanSchema represents a boolean value.
-type BooleanSchema struct{}
+type BooleanSchema struct {
+	annotations
+	refinements
+	messages
+	transforms
+}
 
 // Boolean creates a new boolean schema.
 func Boolean() *BooleanSchema {
@@ -513,21 +722,35 @@ func (b *BooleanSchema) TypeName() string {
 }
 
 func (b *BooleanSchema) Validate(data any) error {
+	if err := b.validate(data); err != nil {
+		return b.message(err)
+	}
+	return b.refine(data)
+}
+
+func (b *BooleanSchema) validate(data any) error {
 	if _, ok := data.(bool); !ok {
-		return fmt.Errorf("expected boolean, got %T", data)
+		return constraintError("type", "boolean", b.shown(data), "expected boolean, got %T", data)
 	}
 	return nil
 }
 
 func (b *BooleanSchema) JSONSchema() map[string]any {
-	return map[string]any{"type": "boolean"}
+	return b.annotateRefinements(b.annotate(map[string]any{"type": "boolean"}))
 }
 
 // ArraySchema represents an array of items.
 type ArraySchema struct {
+	annotations
+	refinements
+	messages
+	transforms
 	items    Schema
 	minItems *int
 	maxItems *int
+	// unique requires distinct items, compared by uniqueBy if set
+	unique   bool
+	uniqueBy string
 }
 
 // Array creates a new array schema with the given item schema.
@@ -537,12 +760,14 @@ func Array(items Schema) *ArraySchema {
 
 // MinItems sets the minimum number of items.
 func (a *ArraySchema) MinItems(min int) *ArraySchema {
+	a.constrain("minItems")
 	a.minItems = &min
 	return a
 }
 
 // MaxItems sets the maximum number of items.
 func (a *ArraySchema) MaxItems(max int) *ArraySchema {
+	a.constrain("maxItems")
 	a.maxItems = &max
 	return a
 }
@@ -550,6 +775,7 @@ func (a *ArraySchema) MaxItems(max int) *ArraySchema {
 // NonEmpty ensures the array has at least one item.
 func (a *ArraySchema) NonEmpty() *ArraySchema {
 	one := 1
+	a.constrain("minItems")
 	a.minItems = &one
 	return a
 }
@@ -564,25 +790,27 @@ func (a *ArraySchema) TypeName() string {
 }
 
 func (a *ArraySchema) Validate(data any) error {
+	if err := a.validate(data); err != nil {
+		return a.message(err)
+	}
+	return a.refine(data)
+}
+
+func (a *ArraySchema) validate(data any) error {
 	val := reflect.ValueOf(data)
 
 	// Critical: nil slices are invalid (prevents JSON null)
 	if val.Kind() == reflect.Slice && val.IsNil() {
-		return fmt.Errorf("array cannot be nil - use empty slice []T{} instead")
+		return constraintError("type", "array", nil, "array cannot be nil - use empty slice []T{} instead")
 	}
 
 	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
-		return fmt.Errorf("expected array, got %v", val.Kind())
+		return constraintError("type", "array", a.shown(data), "expected array, got %v", val.Kind())
 	}
 
 	length := val.Len()
-
-	if a.minItems != nil && length < *a.minItems {
-		return fmt.Errorf("array has %d items, minimum is %d", length, *a.minItems)
-	}
-
-	if a.maxItems != nil && length > *a.maxItems {
-		return fmt.Errorf("array has %d items, maximum is %d", length, *a.maxItems)
+	if err := a.checkLength(length); err != nil {
+		return err
 	}
 
 	// Validate each item
@@ -592,6 +820,25 @@ func (a *ArraySchema) Validate(data any) error {
 		}
 	}
 
+	if a.unique {
+		items := make([]any, length)
+		for i := range items {
+			items[i] = val.Index(i).Interface()
+		}
+		return a.checkUnique(items)
+	}
+
+	return nil
+}
+
+// checkLength checks an array's item count against minItems and maxItems.
+func (a *ArraySchema) checkLength(length int) error {
+	if a.minItems != nil && length < *a.minItems {
+		return constraintError("minItems", *a.minItems, length, "array has %d items, minimum is %d", length, *a.minItems)
+	}
+	if a.maxItems != nil && length > *a.maxItems {
+		return constraintError("maxItems", *a.maxItems, length, "array has %d items, maximum is %d", length, *a.maxItems)
+	}
 	return nil
 }
 
@@ -607,12 +854,21 @@ func (a *ArraySchema) JSONSchema() map[string]any {
 	if a.maxItems != nil {
 		result["maxItems"] = *a.maxItems
 	}
+	if a.unique {
+		result["uniqueItems"] = true
+	}
+	if a.uniqueBy != "" {
+		result["x-ont-uniqueBy"] = a.uniqueBy
+	}
 
-	return result
+	return a.annotateRefinements(a.annotate(result))
 }
 
 // NullableSchema wraps another schema to allow null values.
 type NullableSchema struct {
+	annotations
+	refinements
+	transforms
 	inner Schema
 }
 
@@ -631,7 +887,14 @@ func (n *NullableSchema) TypeName() string {
 }
 
 func (n *NullableSchema) Validate(data any) error {
-	if data == nil {
+	if err := n.validate(data); err != nil {
+		return err
+	}
+	return n.refine(data)
+}
+
+func (n *NullableSchema) validate(data any) error {
+	if isNull(data) {
 		return nil
 	}
 	return n.inner.Validate(data)
@@ -640,16 +903,317 @@ func (n *NullableSchema) Validate(data any) error {
 func (n *NullableSchema) JSONSchema() map[string]any {
 	innerSchema := n.inner.JSONSchema()
 	// Use anyOf to allow null
-	return map[string]any{
+	return n.annotateRefinements(n.annotate(map[string]any{
 		"anyOf": []any{
 			innerSchema,
 			map[string]any{"type": "null"},
 		},
+	}))
+}
+
+// OneOfSchema matches exactly one of several alternative schemas.
+type OneOfSchema struct {
+	annotations
+	refinements
+	transforms
+	alternatives []Schema
+}
+
+// OneOf creates a schema that matches exactly one of the given alternatives,
+// mirroring JSON Schema's oneOf.
+func OneOf(schemas ...Schema) *OneOfSchema {
+	return &OneOfSchema{alternatives: schemas}
+}
+
+// Alternatives returns the alternative schemas.
+func (o *OneOfSchema) Alternatives() []Schema {
+	return o.alternatives
+}
+
+func (o *OneOfSchema) TypeName() string {
+	names := make([]string, len(o.alternatives))
+	for i, alt := range o.alternatives {
+		names[i] = alt.TypeName()
+	}
+	return strings.Join(names, " | ")
+}
+
+func (o *OneOfSchema) Validate(data any) error {
+	if err := o.validate(data); err != nil {
+		return err
+	}
+	return o.refine(data)
+}
+
+func (o *OneOfSchema) validate(data any) error {
+	matches := 0
+	var errs []string
+	for i, alt := range o.alternatives {
+		if err := alt.Validate(data); err != nil {
+			errs = append(errs, fmt.Sprintf("alternative %d: %v", i, err))
+			continue
+		}
+		matches++
+	}
+
+	switch matches {
+	case 1:
+		return nil
+	case 0:
+		return fmt.Errorf("value does not match any alternative (%s)", strings.Join(errs, "; "))
+	default:
+		return fmt.Errorf("value matches %d alternatives, expected exactly one", matches)
 	}
 }
 
+func (o *OneOfSchema) JSONSchema() map[string]any {
+	alternatives := make([]any, len(o.alternatives))
+	allObjects := len(o.alternatives) > 0
+	for i, alt := range o.alternatives {
+		altSchema := alt.JSONSchema()
+		if altSchema["type"] != "object" {
+			allObjects = false
+		}
+		alternatives[i] = altSchema
+	}
+
+	result := map[string]any{
+		"oneOf": alternatives,
+	}
+	// MCP requires tool input and output schemas to declare type "object"
+	if allObjects {
+		result["type"] = "object"
+	}
+	return o.annotateRefinements(o.annotate(result))
+}
+
+// AllOfSchema matches data that satisfies every one of several schemas.
+type AllOfSchema struct {
+	annotations
+	refinements
+	transforms
+	schemas []Schema
+}
+
+// AllOf creates a schema that requires data to match all of the given
+// schemas, mirroring JSON Schema's allOf, e.g. a base entity mixed with
+// extra constraints or fields:
+//
+//	ont.AllOf(ont.Ref("Customer"), ont.Object(map[string]ont.Schema{
+//		"segment": ont.String(),
+//	}))
+//
+// Each object keeps its own properties, so a Strict object among the schemas
+// rejects the fields the others add.
+func AllOf(schemas ...Schema) *AllOfSchema {
+	return &AllOfSchema{schemas: schemas}
+}
+
+// Schemas returns the schemas data must match.
+func (a *AllOfSchema) Schemas() []Schema {
+	return a.schemas
+}
+
+func (a *AllOfSchema) TypeName() string {
+	names := make([]string, len(a.schemas))
+	for i, schema := range a.schemas {
+		names[i] = schema.TypeName()
+	}
+	return strings.Join(names, " & ")
+}
+
+func (a *AllOfSchema) Validate(data any) error {
+	if err := a.validate(data); err != nil {
+		return err
+	}
+	return a.refine(data)
+}
+
+func (a *AllOfSchema) validate(data any) error {
+	for i, schema := range a.schemas {
+		if err := schema.Validate(data); err != nil {
+			return fmt.Errorf("schema %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (a *AllOfSchema) JSONSchema() map[string]any {
+	schemas := make([]any, len(a.schemas))
+	anyObject := false
+	for i, schema := range a.schemas {
+		js := schema.JSONSchema()
+		if js["type"] == "object" {
+			anyObject = true
+		}
+		schemas[i] = js
+	}
+
+	result := map[string]any{
+		"allOf": schemas,
+	}
+	// Data matching an object schema is an object; MCP needs the type
+	if anyObject {
+		result["type"] = "object"
+	}
+	return a.annotateRefinements(a.annotate(result))
+}
+
+// propertyNames returns the properties of the objects among the schemas,
+// looking through references.
+func (a *AllOfSchema) propertyNames() []string {
+	var names []string
+	for _, schema := range a.schemas {
+		if o, ok := Deref(schema).(*ObjectSchema); ok {
+			names = append(names, sortedKeys(o.properties)...)
+		}
+	}
+	return names
+}
+
+// mergedObject returns one object with the properties and required fields
+// of all the schemas, if they are all objects. The first schema declaring a
+// property provides its schema.
+func (a *AllOfSchema) mergedObject() (*ObjectSchema, bool) {
+	merged := &ObjectSchema{properties: make(map[string]Schema), required: []string{}}
+	for _, schema := range a.schemas {
+		o, ok := Deref(schema).(*ObjectSchema)
+		if !ok {
+			return nil, false
+		}
+		for _, name := range sortedKeys(o.properties) {
+			if _, ok := merged.properties[name]; !ok {
+				merged.properties[name] = o.properties[name]
+			}
+		}
+		for _, name := range o.required {
+			if !contains(merged.required, name) {
+				merged.required = append(merged.required, name)
+			}
+		}
+	}
+	return merged, len(a.schemas) > 0
+}
+
+// DiscriminatedUnionSchema matches one of several object variants, selected
+// by the value of a tag property (e.g. {"type": "card", ...}).
+type DiscriminatedUnionSchema struct {
+	annotations
+	refinements
+	transforms
+	discriminator string
+	variants      map[string]*ObjectSchema
+}
+
+// DiscriminatedUnion creates a schema whose variant is chosen by the string
+// value of the discriminator property. Variants don't need to declare the
+// discriminator themselves.
+func DiscriminatedUnion(discriminator string, variants map[string]*ObjectSchema) *DiscriminatedUnionSchema {
+	return &DiscriminatedUnionSchema{
+		discriminator: discriminator,
+		variants:      variants,
+	}
+}
+
+// Discriminator returns the name of the tag property.
+func (d *DiscriminatedUnionSchema) Discriminator() string {
+	return d.discriminator
+}
+
+// Variants returns the variant schemas keyed by tag value.
+func (d *DiscriminatedUnionSchema) Variants() map[string]*ObjectSchema {
+	return d.variants
+}
+
+// Tags returns the tag values in sorted order.
+func (d *DiscriminatedUnionSchema) Tags() []string {
+	return sortedKeys(d.variants)
+}
+
+func (d *DiscriminatedUnionSchema) TypeName() string {
+	return "object"
+}
+
+func (d *DiscriminatedUnionSchema) Validate(data any) error {
+	if err := d.validate(data); err != nil {
+		return err
+	}
+	return d.refine(data)
+}
+
+func (d *DiscriminatedUnionSchema) validate(data any) error {
+	tagVal, err := objectField(data, d.discriminator)
+	if err != nil {
+		return err
+	}
+
+	tag, ok := tagVal.(string)
+	if !ok {
+		return fmt.Errorf("field '%s': expected string, got %T", d.discriminator, tagVal)
+	}
+
+	variant, ok := d.variants[tag]
+	if !ok {
+		return fmt.Errorf("field '%s': '%s' is not one of the allowed values: %v", d.discriminator, tag, d.Tags())
+	}
+
+	// The tag isn't one of the variant's own properties
+	if m, ok := data.(map[string]any); ok {
+		if err := variant.validateMapAllowing(m, d.discriminator); err != nil {
+			return fmt.Errorf("variant '%s': %w", tag, err)
+		}
+		return nil
+	}
+	if err := variant.Validate(data); err != nil {
+		return fmt.Errorf("variant '%s': %w", tag, err)
+	}
+	return nil
+}
+
+func (d *DiscriminatedUnionSchema) JSONSchema() map[string]any {
+	tags := d.Tags()
+	alternatives := make([]any, 0, len(tags))
+	for _, tag := range tags {
+		variant := d.variants[tag].JSONSchema()
+
+		// Pin the discriminator to this variant's tag
+		props := make(map[string]any)
+		if existing, ok := variant["properties"].(map[string]any); ok {
+			for k, v := range existing {
+				props[k] = v
+			}
+		}
+		props[d.discriminator] = map[string]any{"type": "string", "const": tag}
+		variant["properties"] = props
+
+		required := []string{d.discriminator}
+		if existing, ok := variant["required"].([]string); ok {
+			for _, name := range existing {
+				if name != d.discriminator {
+					required = append(required, name)
+				}
+			}
+		}
+		variant["required"] = required
+
+		alternatives = append(alternatives, variant)
+	}
+
+	return d.annotateRefinements(d.annotate(map[string]any{
+		"type":  "object",
+		"oneOf": alternatives,
+		"discriminator": map[string]any{
+			"propertyName": d.discriminator,
+		},
+	}))
+}
+
 // AnySchema allows any value.
-type AnySchema struct{}
+type AnySchema struct {
+	annotations
+	refinements
+	transforms
+}
 
 // Any creates a schema that allows any value.
 func Any() *AnySchema {
@@ -661,15 +1225,103 @@ func (a *AnySchema) TypeName() string {
 }
 
 func (a *AnySchema) Validate(data any) error {
+	if err := a.validate(data); err != nil {
+		return err
+	}
+	return a.refine(data)
+}
+
+func (a *AnySchema) validate(data any) error {
 	return nil
 }
 
 func (a *AnySchema) JSONSchema() map[string]any {
-	return map[string]any{}
+	return a.annotateRefinements(a.annotate(map[string]any{}))
+}
+
+// RefSchema refers to a named schema registered in Config.Schemas.
+// References are resolved by Config.Validate.
+type RefSchema struct {
+	annotations
+	refinements
+	transforms
+	name   string
+	target Schema
+}
+
+// Ref creates a reference to the named schema in Config.Schemas. JSON Schema
+// output uses "$ref" and generated TypeScript uses a shared type of the same name.
+// Named schemas may reference themselves to model trees, e.g. comments whose
+// "replies" are Array(Ref("Comment")).
+func Ref(name string) *RefSchema {
+	return &RefSchema{name: name}
+}
+
+// Name returns the name of the referenced schema.
+func (r *RefSchema) Name() string {
+	return r.name
+}
+
+// Target returns the referenced schema, or nil if the reference is unresolved.
+func (r *RefSchema) Target() Schema {
+	return r.target
+}
+
+func (r *RefSchema) TypeName() string {
+	return r.name
+}
+
+func (r *RefSchema) Validate(data any) error {
+	if err := r.validate(data); err != nil {
+		return err
+	}
+	return r.refine(data)
+}
+
+func (r *RefSchema) validate(data any) error {
+	if r.target == nil {
+		return fmt.Errorf("unresolved schema reference '%s'", r.name)
+	}
+	return r.target.Validate(data)
+}
+
+func (r *RefSchema) JSONSchema() map[string]any {
+	return r.annotateRefinements(r.annotate(map[string]any{
+		"$ref": "#/$defs/" + r.name,
+	}))
 }
 
 // Helper functions
 
+// objectField reads a property from a map or a struct (by JSON tag or
+// field name), returning an error if data is not an object or the property is missing.
+func objectField(data any, name string) (any, error) {
+	val := reflect.ValueOf(data)
+	if val.Kind() == reflect.Ptr {
+		val = val.Elem()
+	}
+
+	switch val.Kind() {
+	case reflect.Map:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected object, got %T", data)
+		}
+		v, ok := m[name]
+		if !ok {
+			return nil, fmt.Errorf("required field '%s' is missing", name)
+		}
+		return v, nil
+	case reflect.Struct:
+		if _, fieldVal, ok := lookupField(val, name); ok {
+			return fieldValue(fieldVal), nil
+		}
+		return nil, fmt.Errorf("required field '%s' is missing", name)
+	default:
+		return nil, fmt.Errorf("expected object, got %v", val.Kind())
+	}
+}
+
 func capitalize(s string) string {
 	if len(s) == 0 {
 		return s
@@ -689,3 +1341,16 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+// timeValue returns the time in a time.Time or non-nil *time.Time.
+func timeValue(data any) (time.Time, bool) {
+	switch t := data.(type) {
+	case time.Time:
+		return t, true
+	case *time.Time:
+		if t != nil {
+			return *t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -1,7 +1,9 @@
 package ontology
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestStringSchemaValidation(t *testing.T) {
@@ -83,6 +85,60 @@ func TestStringSchemaValidation(t *testing.T) {
 			input:   "d",
 			wantErr: true,
 		},
+		{
+			name:    "valid date-time",
+			schema:  String().DateTime(),
+			input:   "2024-05-01T10:30:00.123+02:00",
+			wantErr: false,
+		},
+		{
+			name:    "date-time without offset",
+			schema:  String().DateTime(),
+			input:   "2024-05-01T10:30:00",
+			wantErr: true,
+		},
+		{
+			name:    "date-time with trailing text",
+			schema:  String().DateTime(),
+			input:   "2024-05-01T10:30:00Zgarbage",
+			wantErr: true,
+		},
+		{
+			name:    "impossible date-time",
+			schema:  String().DateTime(),
+			input:   "2024-13-01T10:30:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "time.Time date-time",
+			schema:  String().DateTime(),
+			input:   time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+			wantErr: false,
+		},
+		{
+			name:    "*time.Time date-time",
+			schema:  String().DateTime(),
+			input:   &time.Time{},
+			wantErr: false,
+		},
+		{
+			name:    "time.Time for plain string",
+			schema:  String(),
+			input:   time.Now(),
+			wantErr: true,
+		},
+		{
+			name:    "valid date",
+			schema:  String().Date(),
+			input:   "2024-02-29",
+			wantErr: false,
+		},
+		{
+			name:    "impossible date",
+			schema:  String().Date(),
+			input:   "2023-02-29",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -186,6 +242,24 @@ func TestNumberSchemaValidation(t *testing.T) {
 			input:   -5.0,
 			wantErr: true,
 		},
+		{
+			name:    "valid enum",
+			schema:  Integer().Enum(1, 2, 3),
+			input:   2,
+			wantErr: false,
+		},
+		{
+			name:    "valid enum (JSON number)",
+			schema:  Integer().Enum(1, 2, 3),
+			input:   3.0,
+			wantErr: false,
+		},
+		{
+			name:    "invalid enum",
+			schema:  Number().Enum(0.5, 1.5),
+			input:   1.0,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -515,3 +589,307 @@ func TestJSONSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestOneOfSchemaValidation(t *testing.T) {
+	lookup := OneOf(
+		Object(map[string]Schema{"byId": String().UUID()}),
+		Object(map[string]Schema{"byEmail": String().Email()}),
+	)
+
+	tests := []struct {
+		name    string
+		schema  *OneOfSchema
+		input   any
+		wantErr bool
+	}{
+		{
+			name:    "matches first alternative",
+			schema:  lookup,
+			input:   map[string]any{"byId": "550e8400-e29b-41d4-a716-446655440000"},
+			wantErr: false,
+		},
+		{
+			name:    "matches second alternative",
+			schema:  lookup,
+			input:   map[string]any{"byEmail": "test@example.com"},
+			wantErr: false,
+		},
+		{
+			name:    "matches no alternative",
+			schema:  lookup,
+			input:   map[string]any{"byName": "test"},
+			wantErr: true,
+		},
+		{
+			name:   "matches both alternatives",
+			schema: lookup,
+			input: map[string]any{
+				"byId":    "550e8400-e29b-41d4-a716-446655440000",
+				"byEmail": "test@example.com",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "primitive alternatives",
+			schema:  OneOf(String(), Boolean()),
+			input:   true,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOneOfJSONSchema(t *testing.T) {
+	objects := OneOf(
+		Object(map[string]Schema{"byId": String()}),
+		Object(map[string]Schema{"byEmail": String()}),
+	).JSONSchema()
+
+	if alts, ok := objects["oneOf"].([]any); !ok || len(alts) != 2 {
+		t.Fatalf("Expected oneOf with 2 alternatives, got %v", objects["oneOf"])
+	}
+	if objects["type"] != "object" {
+		t.Errorf("Expected type object when all alternatives are objects, got %v", objects["type"])
+	}
+
+	mixed := OneOf(String(), Integer()).JSONSchema()
+	if _, ok := mixed["type"]; ok {
+		t.Errorf("Expected no type for mixed alternatives, got %v", mixed["type"])
+	}
+}
+
+func paymentSchema() *DiscriminatedUnionSchema {
+	return DiscriminatedUnion("type", map[string]*ObjectSchema{
+		"card": Object(map[string]Schema{
+			"number": String().Pattern(`^\d{16}$`),
+		}),
+		"bank": Object(map[string]Schema{
+			"iban": String(),
+		}),
+	})
+}
+
+func TestDiscriminatedUnionValidation(t *testing.T) {
+	type cardPayment struct {
+		Type   string `json:"type"`
+		Number string `json:"number"`
+	}
+
+	tests := []struct {
+		name    string
+		input   any
+		wantErr string
+	}{
+		{
+			name:  "valid card",
+			input: map[string]any{"type": "card", "number": "4242424242424242"},
+		},
+		{
+			name:  "valid bank",
+			input: map[string]any{"type": "bank", "iban": "DE89370400440532013000"},
+		},
+		{
+			name:  "valid struct",
+			input: cardPayment{Type: "card", Number: "4242424242424242"},
+		},
+		{
+			name:    "missing discriminator",
+			input:   map[string]any{"number": "4242424242424242"},
+			wantErr: "required field 'type' is missing",
+		},
+		{
+			name:    "unknown tag",
+			input:   map[string]any{"type": "cash"},
+			wantErr: "'cash' is not one of the allowed values: [bank card]",
+		},
+		{
+			name:    "non-string tag",
+			input:   map[string]any{"type": 1},
+			wantErr: "field 'type': expected string",
+		},
+		{
+			name:    "invalid variant field",
+			input:   map[string]any{"type": "card", "number": "123"},
+			wantErr: "variant 'card': field 'number'",
+		},
+		{
+			name:    "not an object",
+			input:   "card",
+			wantErr: "expected object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := paymentSchema().Validate(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiscriminatedUnionJSONSchema(t *testing.T) {
+	result := paymentSchema().JSONSchema()
+
+	if result["type"] != "object" {
+		t.Errorf("Expected type object, got %v", result["type"])
+	}
+
+	alts := result["oneOf"].([]any)
+	if len(alts) != 2 {
+		t.Fatalf("Expected 2 alternatives, got %d", len(alts))
+	}
+
+	// Sorted by tag: bank, card
+	card := alts[1].(map[string]any)
+	tag := card["properties"].(map[string]any)["type"].(map[string]any)
+	if tag["const"] != "card" {
+		t.Errorf("Expected card variant to pin type to 'card', got %v", tag["const"])
+	}
+	if required := card["required"].([]string); required[0] != "type" {
+		t.Errorf("Expected discriminator to be required, got %v", required)
+	}
+}
+
+func TestDateTimeStructOutput(t *testing.T) {
+	type event struct {
+		Name string    `json:"name"`
+		At   time.Time `json:"at"`
+	}
+	schema := Object(map[string]Schema{
+		"name": String(),
+		"at":   String().DateTime(),
+	})
+	output := event{Name: "launch", At: time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)}
+	if err := schema.Validate(output); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if got := toJSONValue(output).(map[string]any)["at"]; got != "2024-05-01T10:30:00Z" {
+		t.Errorf("at marshals as %v", got)
+	}
+}
+
+func TestObjectSchemaStructOmitempty(t *testing.T) {
+	type profile struct {
+		Name     string   `json:"name"`
+		Nickname string   `json:"nickname,omitempty"`
+		Age      *int     `json:"age,omitempty"`
+		Tags     []string `json:"tags,omitzero"`
+	}
+	schema := Object(map[string]Schema{
+		"name":     String(),
+		"nickname": String().Min(2),
+		"age":      Integer(),
+		"tags":     Array(String()),
+	}).Optional("nickname", "age", "tags")
+
+	// Empty omitempty/omitzero fields are absent on the wire, so they are
+	// skipped rather than validated as "" or nil
+	if err := schema.Validate(profile{Name: "Ada"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	age := 36
+	if err := schema.Validate(profile{Name: "Ada", Nickname: "A", Age: &age}); err == nil {
+		t.Error("present optional fields should still be validated")
+	}
+
+	required := schema.Extend(Object(map[string]Schema{"nickname": String()}))
+	err := required.Validate(profile{Name: "Ada"})
+	if err == nil || !strings.Contains(err.Error(), "required field 'nickname' is missing") {
+		t.Errorf("expected missing nickname, got %v", err)
+	}
+}
+
+func TestObjectSchemaStructFields(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `json:"createdBy"`
+		Note      string `json:"note"`
+	}
+	type Address struct {
+		City string `json:"city"`
+	}
+	type customer struct {
+		Audit
+		*Address
+		ID       string  `json:"id"`
+		Note     string  `json:"note"`
+		Email    *string `json:"email"`
+		Manager  *Audit  `json:"manager"`
+		Password string  `json:"-"`
+	}
+	schema := Object(map[string]Schema{
+		"id":        String(),
+		"createdBy": String().Min(1),
+		"city":      String(),
+		"note":      String().Max(5),
+		"email":     Nullable(String().Email()),
+		"manager":   Nullable(Object(map[string]Schema{"createdBy": String()})),
+		"Password":  String(),
+	}).Optional("city", "Password")
+
+	email := "ada@example.com"
+	bad := "ada"
+	tests := []struct {
+		name string
+		data any
+		want string
+	}{
+		{"embedded", customer{Audit: Audit{CreatedBy: "ops"}, ID: "c1"}, ""},
+		{"pointer", &customer{Audit: Audit{CreatedBy: "ops"}, ID: "c1", Email: &email, Manager: &Audit{CreatedBy: "bob"}}, ""},
+		{"embedded pointer", customer{Audit: Audit{CreatedBy: "ops"}, Address: &Address{City: "Oslo"}, ID: "c1"}, ""},
+		{"embedded field invalid", customer{ID: "c1"}, "field 'createdBy': string length 0 is less than minimum 1"},
+		{"shadowed field", customer{Audit: Audit{CreatedBy: "ops", Note: "too long"}, ID: "c1", Note: "ok"}, ""},
+		{"pointer field invalid", customer{Audit: Audit{CreatedBy: "ops"}, ID: "c1", Email: &bad}, "field 'email': string is not a valid email"},
+		{"typed nil pointer", customer{Audit: Audit{CreatedBy: "ops"}, ID: "c1", Manager: (*Audit)(nil)}, ""},
+		{"ignored field", customer{Audit: Audit{CreatedBy: "ops"}, ID: "c1", Password: "secret"}, ""},
+	}
+	validate := Compile(schema)
+	for _, tt := range tests {
+		for name, fn := range map[string]ValidateFunc{"Validate": schema.Validate, "Compile": validate} {
+			if got := errorText(fn(tt.data)); got != tt.want {
+				t.Errorf("%s: %s() = %q, want %q", tt.name, name, got, tt.want)
+			}
+		}
+	}
+
+	// Required fields of a nil embedded pointer are missing, as on the wire
+	required := schema.Extend(Object(map[string]Schema{"city": String()}))
+	err := required.Validate(customer{Audit: Audit{CreatedBy: "ops"}, ID: "c1"})
+	if got := errorText(err); got != "required field 'city' is missing" {
+		t.Errorf("nil embedded pointer: Validate() = %q", got)
+	}
+}
+
+func TestNullableTypedNil(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+	schema := Nullable(Object(map[string]Schema{"name": String()}))
+	for _, data := range []any{(*item)(nil), map[string]any(nil)} {
+		if err := schema.Validate(data); err != nil {
+			t.Errorf("Validate(%#v) error = %v", data, err)
+		}
+		if err := Compile(schema)(data); err != nil {
+			t.Errorf("Compile()(%#v) error = %v", data, err)
+		}
+	}
+	if err := schema.Validate(&item{}); err != nil {
+		t.Errorf("Validate(&item{}) error = %v", err)
+	}
+}
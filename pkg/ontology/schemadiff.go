@@ -0,0 +1,350 @@
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaChangeKind is the kind of a SchemaChange.
+type SchemaChangeKind string
+
+// Kinds of schema changes.
+const (
+	SchemaFieldAdded        SchemaChangeKind = "added"
+	SchemaFieldRemoved      SchemaChangeKind = "removed"
+	SchemaRetyped           SchemaChangeKind = "retyped"
+	SchemaMadeRequired      SchemaChangeKind = "required"
+	SchemaMadeOptional      SchemaChangeKind = "optional"
+	SchemaMadeNullable      SchemaChangeKind = "nullable"
+	SchemaMadeNonNullable   SchemaChangeKind = "non-nullable"
+	SchemaEnumChanged       SchemaChangeKind = "enum"
+	SchemaConstraintChanged SchemaChangeKind = "constraints"
+	SchemaDocsChanged       SchemaChangeKind = "docs"
+)
+
+// SchemaChange is one difference between two schemas.
+type SchemaChange struct {
+	// Path is the JSON pointer of the changed value, "" for the root.
+	// Array items are "*", e.g. "/lines/*/price".
+	Path string           `json:"path"`
+	Kind SchemaChangeKind `json:"kind"`
+	// Detail describes the change, e.g. "string -> integer".
+	Detail string `json:"detail,omitempty"`
+	// Breaking reports whether existing callers may break (see DiffSchemas).
+	Breaking bool `json:"breaking"`
+}
+
+// String describes the change, e.g. "/email: added (breaking)".
+func (c SchemaChange) String() string {
+	path := c.Path
+	if path == "" {
+		path = "/"
+	}
+	s := fmt.Sprintf("%s: %s", path, c.Kind)
+	if c.Detail != "" {
+		s += " " + c.Detail
+	}
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+// SchemaDiff lists the differences between two schemas, in path order.
+type SchemaDiff struct {
+	Changes []SchemaChange `json:"changes"`
+}
+
+// HasChanges reports whether the schemas differ.
+func (d *SchemaDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// Breaking reports whether any change is breaking.
+func (d *SchemaDiff) Breaking() bool {
+	for _, c := range d.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the changes, one per line.
+func (d *SchemaDiff) String() string {
+	lines := make([]string, len(d.Changes))
+	for i, c := range d.Changes {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// schemaDirection is the side of a call a schema describes, which decides
+// whether a change breaks callers.
+type schemaDirection int
+
+const (
+	// eitherDirection breaks on changes that break either side.
+	eitherDirection schemaDirection = iota
+	// inputDirection: callers write values, so narrowing breaks them.
+	inputDirection
+	// outputDirection: callers read values, so widening breaks them.
+	outputDirection
+)
+
+// DiffSchemas reports the fields added, removed and retyped between old and
+// new, and changes to requiredness, nullability, enums, constraints and
+// documentation. It doesn't know whether the schema is read or written by
+// callers, so a change is breaking if it breaks either: adding a required
+// field breaks writers, making a field optional breaks readers. Use
+// DiffInputSchemas and DiffOutputSchemas for function inputs and outputs.
+func DiffSchemas(old, new Schema) *SchemaDiff {
+	return diffSchemas(old, new, eitherDirection)
+}
+
+// DiffInputSchemas is DiffSchemas for values callers send, such as function
+// inputs: only changes that reject values accepted before are breaking.
+func DiffInputSchemas(old, new Schema) *SchemaDiff {
+	return diffSchemas(old, new, inputDirection)
+}
+
+// DiffOutputSchemas is DiffSchemas for values callers receive, such as
+// function outputs: only changes that produce values callers didn't expect
+// are breaking.
+func DiffOutputSchemas(old, new Schema) *SchemaDiff {
+	return diffSchemas(old, new, outputDirection)
+}
+
+func diffSchemas(old, new Schema, dir schemaDirection) *SchemaDiff {
+	d := &schemaDiffer{dir: dir, seen: make(map[[2]Schema]bool), diff: &SchemaDiff{}}
+	d.compare("", old, new)
+	return d.diff
+}
+
+type schemaDiffer struct {
+	dir  schemaDirection
+	seen map[[2]Schema]bool
+	diff *SchemaDiff
+}
+
+// add records a change, breaking if it breaks callers in d's direction.
+func (d *schemaDiffer) add(path string, kind SchemaChangeKind, detail string, breaksInput, breaksOutput bool) {
+	breaking := breaksInput || breaksOutput
+	switch d.dir {
+	case inputDirection:
+		breaking = breaksInput
+	case outputDirection:
+		breaking = breaksOutput
+	}
+	d.diff.Changes = append(d.diff.Changes, SchemaChange{Path: path, Kind: kind, Detail: detail, Breaking: breaking})
+}
+
+func (d *schemaDiffer) compare(path string, old, new Schema) {
+	old, new = Deref(old), Deref(new)
+	if old == nil || new == nil {
+		if old != new {
+			d.add(path, SchemaRetyped, typeNameOrNone(old)+" -> "+typeNameOrNone(new), true, true)
+		}
+		return
+	}
+	if d.seen[[2]Schema{old, new}] {
+		return
+	}
+	d.seen[[2]Schema{old, new}] = true
+
+	oldInner, oldNullable := unwrapNullable(old)
+	newInner, newNullable := unwrapNullable(new)
+	switch {
+	case !oldNullable && newNullable:
+		d.add(path, SchemaMadeNullable, "", false, true)
+	case oldNullable && !newNullable:
+		d.add(path, SchemaMadeNonNullable, "", true, false)
+	}
+	old, new = oldInner, newInner
+
+	if reflect.TypeOf(old) != reflect.TypeOf(new) || old.TypeName() != new.TypeName() && !isContainer(old) {
+		d.add(path, SchemaRetyped, old.TypeName()+" -> "+new.TypeName(), true, true)
+		return
+	}
+	switch o := old.(type) {
+	case *ObjectSchema:
+		d.compareObjects(path, o, new.(*ObjectSchema))
+		d.compareKeywords(path, old, new, "properties", "required")
+	case *ArraySchema:
+		d.compare(path+"/*", o.items, new.(*ArraySchema).items)
+		d.compareKeywords(path, old, new, "items")
+	default:
+		d.compareKeywords(path, old, new)
+	}
+}
+
+// compareObjects compares the properties of two objects.
+func (d *schemaDiffer) compareObjects(path string, old, new *ObjectSchema) {
+	names := make(map[string]bool)
+	for name := range old.properties {
+		names[name] = true
+	}
+	for name := range new.properties {
+		names[name] = true
+	}
+	for _, name := range sortedKeys(names) {
+		fieldPath := pointer(path, name)
+		oldProp, inOld := old.properties[name]
+		newProp, inNew := new.properties[name]
+		oldRequired, newRequired := contains(old.required, name), contains(new.required, name)
+		switch {
+		case !inOld:
+			if newRequired {
+				d.add(fieldPath, SchemaFieldAdded, "as required", true, false)
+			} else {
+				d.add(fieldPath, SchemaFieldAdded, "", false, false)
+			}
+		case !inNew:
+			d.add(fieldPath, SchemaFieldRemoved, removedDetail(oldProp), true, true)
+		default:
+			switch {
+			case !oldRequired && newRequired:
+				d.add(fieldPath, SchemaMadeRequired, "", true, false)
+			case oldRequired && !newRequired:
+				d.add(fieldPath, SchemaMadeOptional, "", false, true)
+			}
+			d.compare(fieldPath, oldProp, newProp)
+		}
+	}
+}
+
+// removedDetail notes that a removed field had been deprecated, so reviews
+// can tell planned removals from accidental ones.
+func removedDetail(prop Schema) string {
+	for _, schema := range []Schema{prop, Deref(prop)} {
+		if d, ok := schema.(Deprecatable); ok {
+			if message, deprecated := d.Deprecation(); deprecated && message != "" {
+				return "after deprecation: " + message
+			} else if deprecated {
+				return "after deprecation"
+			}
+		}
+	}
+	return ""
+}
+
+// compareKeywords compares the JSON Schema keywords of two schemas of the
+// same type, except the skipped ones and those of nested schemas.
+func (d *schemaDiffer) compareKeywords(path string, old, new Schema, skip ...string) {
+	oldJSON, newJSON := old.JSONSchema(), new.JSONSchema()
+	var docs, constraints []string
+	for _, key := range sortedKeys(unionKeys(oldJSON, newJSON)) {
+		if contains(skip, key) || jsonEqual(oldJSON[key], newJSON[key]) {
+			continue
+		}
+		switch key {
+		case "description", "examples", "deprecated", "x-ont-deprecated", "x-ont-sensitive":
+			docs = append(docs, key)
+		case "enum":
+			d.compareEnums(path, oldJSON[key], newJSON[key])
+		default:
+			constraints = append(constraints, key)
+		}
+	}
+	if len(constraints) > 0 {
+		d.add(path, SchemaConstraintChanged, strings.Join(constraints, ", "), true, true)
+	}
+	if len(docs) > 0 {
+		d.add(path, SchemaDocsChanged, strings.Join(docs, ", "), false, false)
+	}
+}
+
+// compareEnums records added and removed enum values. Values a schema
+// without an enum allows are unbounded.
+func (d *schemaDiffer) compareEnums(path string, old, new any) {
+	oldValues, newValues := enumValues(old), enumValues(new)
+	var added, removed []string
+	for _, v := range newValues {
+		if old != nil && !contains(oldValues, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range oldValues {
+		if new != nil && !contains(newValues, v) {
+			removed = append(removed, v)
+		}
+	}
+	switch {
+	case old == nil:
+		d.add(path, SchemaEnumChanged, "restricted to "+strings.Join(newValues, ", "), true, false)
+	case new == nil:
+		d.add(path, SchemaEnumChanged, "no longer restricted", false, true)
+	default:
+		if len(added) > 0 {
+			d.add(path, SchemaEnumChanged, "added "+strings.Join(added, ", "), false, true)
+		}
+		if len(removed) > 0 {
+			d.add(path, SchemaEnumChanged, "removed "+strings.Join(removed, ", "), true, false)
+		}
+	}
+}
+
+// unwrapNullable returns the schema a Nullable wraps and whether it did.
+func unwrapNullable(schema Schema) (Schema, bool) {
+	if n, ok := schema.(*NullableSchema); ok {
+		return Deref(n.inner), true
+	}
+	return schema, false
+}
+
+// typeNameOrNone returns the TypeName of schema, or "none" for a missing
+// schema such as an undeclared output.
+func typeNameOrNone(schema Schema) string {
+	if schema == nil {
+		return "none"
+	}
+	return schema.TypeName()
+}
+
+// isContainer reports whether schema's TypeName includes its nested
+// schemas, which are compared separately.
+func isContainer(schema Schema) bool {
+	_, ok := schema.(*ArraySchema)
+	return ok
+}
+
+// enumValues returns the JSON of each value of an enum keyword.
+func enumValues(enum any) []string {
+	values, _ := enum.([]any)
+	if strs, ok := enum.([]string); ok {
+		for _, s := range strs {
+			values = append(values, s)
+		}
+	}
+	if nums, ok := enum.([]float64); ok {
+		for _, n := range nums {
+			values = append(values, n)
+		}
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		data, _ := json.Marshal(v)
+		result[i] = string(data)
+	}
+	return result
+}
+
+func unionKeys(a, b map[string]any) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// jsonEqual reports whether a and b have the same JSON form.
+func jsonEqual(a, b any) bool {
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,165 @@
+package ontology
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	old := Object(map[string]Schema{
+		"id":      String(),
+		"name":    String().Max(50),
+		"email":   String(),
+		"age":     Integer(),
+		"status":  String().Enum("open", "closed"),
+		"tags":    Array(String()),
+		"note":    String(),
+		"address": Object(map[string]Schema{"city": String(), "zip": String()}),
+	}).Optional("note", "email")
+	new := Object(map[string]Schema{
+		"id":      String().Describe("The ID"),
+		"name":    String().Max(100),
+		"email":   String(),
+		"age":     Nullable(String()),
+		"status":  String().Enum("open", "closed", "archived"),
+		"tags":    Array(Integer()),
+		"note":    String(),
+		"address": Object(map[string]Schema{"city": String(), "country": String()}),
+		"phone":   String(),
+		"fax":     String(),
+	}).Optional("fax", "note")
+
+	want := []string{
+		"/address/country: added as required (breaking)",
+		"/address/zip: removed (breaking)",
+		"/age: nullable (breaking)",
+		"/age: retyped integer -> string (breaking)",
+		"/email: required (breaking)",
+		"/fax: added",
+		"/id: docs description",
+		"/name: constraints maxLength (breaking)",
+		"/phone: added as required (breaking)",
+		"/status: enum added \"archived\" (breaking)",
+		"/tags/*: retyped string -> integer (breaking)",
+	}
+	if got := DiffSchemas(old, new).String(); got != strings.Join(want, "\n") {
+		t.Errorf("DiffSchemas() =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+
+	input := DiffInputSchemas(old, new)
+	for _, c := range input.Changes {
+		wantBreaking := map[string]bool{
+			"/address/country": true, "/address/zip": true, "/age": c.Kind == SchemaRetyped,
+			"/email": true, "/name": true, "/phone": true, "/tags/*": true,
+		}[c.Path]
+		if c.Breaking != wantBreaking {
+			t.Errorf("DiffInputSchemas() %s: breaking = %v", c, c.Breaking)
+		}
+	}
+	output := DiffOutputSchemas(old, new)
+	for _, c := range output.Changes {
+		wantBreaking := map[string]bool{
+			"/address/zip": true, "/age": true, "/name": true, "/status": true, "/tags/*": true,
+		}[c.Path]
+		if c.Breaking != wantBreaking {
+			t.Errorf("DiffOutputSchemas() %s: breaking = %v", c, c.Breaking)
+		}
+	}
+
+	if diff := DiffSchemas(old, old); diff.HasChanges() || diff.Breaking() {
+		t.Errorf("DiffSchemas(old, old) = %s", diff)
+	}
+	if got := DiffSchemas(nil, String()).String(); got != "/: retyped none -> string (breaking)" {
+		t.Errorf("DiffSchemas(nil, String()) = %s", got)
+	}
+}
+
+func TestDiffSchemasRecursive(t *testing.T) {
+	config := &Config{Schemas: map[string]Schema{
+		"Node": Object(map[string]Schema{"children": Array(Ref("Node"))}),
+	}}
+	if err := config.resolveRefs(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := DiffSchemas(config.Schemas["Node"], config.Schemas["Node"]); diff.HasChanges() {
+		t.Errorf("DiffSchemas() = %s", diff)
+	}
+}
+
+func TestLockDiffChanges(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}, "support": {Description: "Support"}},
+		Entities:     map[string]Entity{},
+		Functions: map[string]Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"admin"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Object(map[string]Schema{"name": String(), "email": String()}),
+			},
+		},
+	}
+	lockPath := filepath.Join(t.TempDir(), "ont.lock")
+	if err := config.WriteLock(lockPath); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+
+	fn := config.Functions["getUser"]
+	fn.Access = []string{"admin", "support"}
+	fn.Inputs = Object(map[string]Schema{"id": String(), "expand": Boolean()}).Optional("expand")
+	fn.Outputs = Object(map[string]Schema{"name": String()})
+	config.Functions["getUser"] = fn
+
+	diff, err := config.DiffLock(lockPath)
+	if err != nil {
+		t.Fatalf("DiffLock failed: %v", err)
+	}
+	changes := diff.Changes["getUser"]
+	if changes == nil || len(changes.Fields) != 1 || changes.Fields[0] != "access" {
+		t.Fatalf("Changes = %+v", changes)
+	}
+	if changes.Inputs.Breaking() || !changes.Outputs.Breaking() {
+		t.Errorf("Inputs = %s, Outputs = %s", changes.Inputs, changes.Outputs)
+	}
+	if got := diff.BreakingFunctions(); len(got) != 1 || got[0] != "getUser" {
+		t.Errorf("BreakingFunctions() = %v", got)
+	}
+	want := "Modified functions: [getUser]\n" +
+		"  getUser: changed access\n" +
+		"  getUser input /expand: added\n" +
+		"  getUser output /email: removed (breaking)\n"
+	if got := diff.String(); !strings.Contains(got, want) {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	old := OntologySnapshot{
+		AccessGroups: []string{"admin", "support"},
+		Entities:     []string{"User"},
+		Functions: map[string]FunctionShape{
+			"getUser":    {Description: "Get a user", Access: []string{"admin"}, InputsSchema: String().JSONSchema()},
+			"deleteUser": {Description: "Delete a user", Access: []string{"admin"}},
+		},
+	}
+	new := OntologySnapshot{
+		AccessGroups: []string{"admin"},
+		Entities:     []string{"User", "Order"},
+		Functions: map[string]FunctionShape{
+			"getUser":  {Description: "Get a user", Access: []string{"admin"}, InputsSchema: Integer().JSONSchema()},
+			"getOrder": {Description: "Get an order", Access: []string{"admin"}},
+		},
+	}
+	diff := DiffSnapshots(old, new)
+	if len(diff.DeletedAccessGroups) != 1 || len(diff.NewEntities) != 1 || len(diff.NewFunctions) != 1 || len(diff.DeletedFunctions) != 1 {
+		t.Errorf("DiffSnapshots() = %+v", diff)
+	}
+	if got := diff.BreakingFunctions(); len(got) != 1 || got[0] != "getUser" {
+		t.Errorf("BreakingFunctions() = %v", got)
+	}
+	if diff := DiffSnapshots(old, old); diff.HasChanges() {
+		t.Errorf("DiffSnapshots(old, old) = %s", diff)
+	}
+}
@@ -0,0 +1,114 @@
+package ontology
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaRegistry holds numbered versions of named schemas, so a shared
+// shape such as "User" can evolve without changing every function that
+// uses it at once. Functions pin a version with Ref("User@2"), or follow
+// the latest with Ref("User"). Register the versions on Config.Schemas:
+//
+//	users := ont.NewSchemaRegistry().
+//		Register("User", 1, userV1).
+//		Register("User", 2, userV2)
+//	config.Schemas = users.Schemas()
+type SchemaRegistry struct {
+	versions map[string]map[int]Schema
+}
+
+// NewSchemaRegistry returns an empty registry.
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{versions: make(map[string]map[int]Schema)}
+}
+
+// Register adds version of the named schema. Versions start at 1; a new
+// version is expected for every breaking change. It panics if the name
+// contains "@", the version is below 1 or already registered, so mistakes
+// surface at startup.
+func (r *SchemaRegistry) Register(name string, version int, schema Schema) *SchemaRegistry {
+	if name == "" || strings.Contains(name, "@") {
+		panic(fmt.Sprintf("ontology: invalid schema name %q", name))
+	}
+	if version < 1 {
+		panic(fmt.Sprintf("ontology: schema '%s' version %d is below 1", name, version))
+	}
+	if r.versions[name] == nil {
+		r.versions[name] = make(map[int]Schema)
+	}
+	if _, exists := r.versions[name][version]; exists {
+		panic(fmt.Sprintf("ontology: schema '%s' version %d is already registered", name, version))
+	}
+	r.versions[name][version] = schema
+	return r
+}
+
+// Versions returns the registered versions of the named schema, in order.
+func (r *SchemaRegistry) Versions(name string) []int {
+	versions := make([]int, 0, len(r.versions[name]))
+	for v := range r.versions[name] {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Latest returns the highest version of the named schema.
+func (r *SchemaRegistry) Latest(name string) (int, Schema, bool) {
+	versions := r.Versions(name)
+	if len(versions) == 0 {
+		return 0, nil, false
+	}
+	latest := versions[len(versions)-1]
+	return latest, r.versions[name][latest], true
+}
+
+// Lookup returns the schema a reference names: "User@2" for a version, or
+// "User" for the latest.
+func (r *SchemaRegistry) Lookup(ref string) (Schema, bool) {
+	name, version, pinned := ParseVersionedName(ref)
+	if !pinned {
+		_, schema, ok := r.Latest(ref)
+		return schema, ok
+	}
+	schema, ok := r.versions[name][version]
+	return schema, ok
+}
+
+// Schemas returns the registered schemas for Config.Schemas: each version
+// as "Name@N", and "Name" as a reference to the latest version. JSON
+// Schemas therefore name the version under "$defs", and the lock file
+// records which version each function uses.
+func (r *SchemaRegistry) Schemas() map[string]Schema {
+	schemas := make(map[string]Schema)
+	for name, versions := range r.versions {
+		for v, schema := range versions {
+			schemas[VersionedName(name, v)] = schema
+		}
+		latest, _, _ := r.Latest(name)
+		schemas[name] = Ref(VersionedName(name, latest))
+	}
+	return schemas
+}
+
+// VersionedName returns the name of a schema version, e.g. "User@2".
+func VersionedName(name string, version int) string {
+	return name + "@" + strconv.Itoa(version)
+}
+
+// ParseVersionedName splits a versioned schema name such as "User@2". It
+// returns false for names without a version.
+func ParseVersionedName(ref string) (string, int, bool) {
+	name, v, found := strings.Cut(ref, "@")
+	if !found {
+		return ref, 0, false
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil || version < 1 {
+		return ref, 0, false
+	}
+	return name, version, true
+}
@@ -0,0 +1,93 @@
+package ontology
+
+import "testing"
+
+func TestSchemaRegistry(t *testing.T) {
+	users := NewSchemaRegistry().
+		Register("User", 1, Object(map[string]Schema{"name": String()})).
+		Register("User", 2, Object(map[string]Schema{"firstName": String(), "lastName": String()}))
+
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]Entity{},
+		Schemas:      users.Schemas(),
+		Functions: map[string]Function{
+			"getUserV1": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      Object(map[string]Schema{}),
+				Outputs:     Ref("User@1"),
+			},
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"public"},
+				Inputs:      Object(map[string]Schema{}),
+				Outputs:     Ref("User"),
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// Pinned functions keep their version; others follow the latest
+	v1, latest := config.Functions["getUserV1"], config.Functions["getUser"]
+	if err := v1.ValidateOutput(map[string]any{"name": "Ada"}); err != nil {
+		t.Errorf("User@1 output error = %v", err)
+	}
+	if err := latest.ValidateOutput(map[string]any{"name": "Ada"}); err == nil {
+		t.Error("User output validated against version 1")
+	}
+	if err := latest.ValidateOutput(map[string]any{"firstName": "Ada", "lastName": "Lovelace"}); err != nil {
+		t.Errorf("User output error = %v", err)
+	}
+
+	// The lock file records the version each function uses
+	snapshot := config.ExtractSnapshot()
+	defs, _ := snapshot.Functions["getUserV1"].OutputsSchema["$defs"].(map[string]any)
+	if _, ok := defs["User@1"]; !ok || len(defs) != 1 {
+		t.Errorf("getUserV1 $defs = %v", defs)
+	}
+	defs, _ = snapshot.Functions["getUser"].OutputsSchema["$defs"].(map[string]any)
+	if _, ok := defs["User@2"]; !ok {
+		t.Errorf("getUser $defs = %v", defs)
+	}
+
+	if got := users.Versions("User"); len(got) != 2 || got[1] != 2 {
+		t.Errorf("Versions() = %v", got)
+	}
+	if _, ok := users.Lookup("User@3"); ok {
+		t.Error("Lookup(User@3) found an unregistered version")
+	}
+	if schema, ok := users.Lookup("User"); !ok || schema != users.versions["User"][2] {
+		t.Error("Lookup(User) should return the latest version")
+	}
+
+	config.Functions["getUserV3"] = Function{
+		Description: "Get a user",
+		Access:      []string{"public"},
+		Inputs:      Object(map[string]Schema{}),
+		Outputs:     Ref("User@3"),
+	}
+	if err := config.Validate(); err == nil {
+		t.Error("Validate() accepted an unregistered version")
+	}
+}
+
+func TestSchemaRegistryRegisterPanics(t *testing.T) {
+	for name, register := range map[string]func(){
+		"duplicate": func() { NewSchemaRegistry().Register("User", 1, String()).Register("User", 1, String()) },
+		"version 0": func() { NewSchemaRegistry().Register("User", 0, String()) },
+		"@ in name": func() { NewSchemaRegistry().Register("User@1", 1, String()) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: Register() didn't panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
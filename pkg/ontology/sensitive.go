@@ -0,0 +1,247 @@
+package ontology
+
+import "sort"
+
+// RedactedValue replaces sensitive values in redacted data.
+const RedactedValue = "[REDACTED]"
+
+// Redactable is implemented by schemas that can be marked sensitive.
+// Sensitive values, such as tokens and passwords, are replaced by
+// RedactedValue in Redact and in validation errors, and their examples are
+// left out of the JSON Schema, so they don't end up in logs or in the
+// snapshot sent to ont-run.com. Sensitive schemas are emitted with
+// "x-ont-sensitive".
+type Redactable interface {
+	IsSensitive() bool
+}
+
+// IsSensitive reports whether the schema is marked Sensitive.
+func (a *annotations) IsSensitive() bool {
+	return a.sensitive
+}
+
+// setSensitive marks the schema sensitive, for schemas built from other
+// sources.
+func (a *annotations) setSensitive() {
+	a.sensitive = true
+}
+
+// shown returns v for error messages, or RedactedValue if the schema is
+// sensitive.
+func (a *annotations) shown(v any) any {
+	if a.sensitive {
+		return RedactedValue
+	}
+	return v
+}
+
+// Redact returns the JSON form of data (see ToWireNames) with the values of
+// sensitive schemas replaced by RedactedValue, for logging. Null values are
+// kept. Unions redact a value if any of their alternatives is sensitive.
+func Redact(schema Schema, data any) any {
+	return redact(schema, toJSONValue(data))
+}
+
+func redact(schema Schema, data any) any {
+	if data == nil {
+		return nil
+	}
+	if r, ok := schema.(Redactable); ok && r.IsSensitive() {
+		return RedactedValue
+	}
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return data
+		}
+		result := make(map[string]any, len(m))
+		for key, val := range m {
+			if prop, ok := s.properties[key]; ok {
+				val = redact(prop, val)
+			}
+			for _, p := range s.patterns {
+				if p.pattern.MatchString(key) {
+					val = redact(p.schema, val)
+				}
+			}
+			result[key] = val
+		}
+		return result
+	case *ArraySchema:
+		items, ok := data.([]any)
+		if !ok {
+			return data
+		}
+		result := make([]any, len(items))
+		for i, item := range items {
+			result[i] = redact(s.items, item)
+		}
+		return result
+	case *NullableSchema:
+		return redact(s.inner, data)
+	case *RefSchema:
+		return redact(s.Target(), data)
+	case *OneOfSchema:
+		for _, alt := range s.alternatives {
+			data = redact(alt, data)
+		}
+	case *AllOfSchema:
+		for _, part := range s.schemas {
+			data = redact(part, data)
+		}
+	case *DiscriminatedUnionSchema:
+		if m, ok := data.(map[string]any); ok {
+			if tag, ok := m[s.discriminator].(string); ok && s.variants[tag] != nil {
+				return redact(s.variants[tag], data)
+			}
+		}
+	}
+	return data
+}
+
+// SensitiveFields returns the JSON pointers of the sensitive fields in
+// schema, with "*" for array items (e.g. "/cards/*/number"), in order. A
+// sensitive schema's fields aren't listed separately.
+func SensitiveFields(schema Schema) []string {
+	var fields []string
+	collectSensitive(schema, "", map[string]bool{}, &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+// collectSensitive appends the sensitive fields of schema at path, following
+// each reference once.
+func collectSensitive(schema Schema, path string, refs map[string]bool, fields *[]string) {
+	if r, ok := schema.(Redactable); ok && r.IsSensitive() {
+		*fields = append(*fields, path)
+		return
+	}
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		for _, name := range sortedKeys(s.properties) {
+			collectSensitive(s.properties[name], pointer(path, name), refs, fields)
+		}
+	case *ArraySchema:
+		collectSensitive(s.items, path+"/*", refs, fields)
+	case *NullableSchema:
+		collectSensitive(s.inner, path, refs, fields)
+	case *RefSchema:
+		if s.target != nil && !refs[s.name] {
+			refs[s.name] = true
+			collectSensitive(s.target, path, refs, fields)
+			delete(refs, s.name)
+		}
+	case *OneOfSchema:
+		collectSensitiveParts(s.alternatives, path, refs, fields)
+	case *AllOfSchema:
+		collectSensitiveParts(s.schemas, path, refs, fields)
+	case *DiscriminatedUnionSchema:
+		variants := make([]Schema, 0, len(s.variants))
+		for _, tag := range s.Tags() {
+			variants = append(variants, s.variants[tag])
+		}
+		collectSensitiveParts(variants, path, refs, fields)
+	}
+}
+
+// collectSensitiveParts appends the sensitive fields of a union's parts,
+// listing fields shared by several parts once.
+func collectSensitiveParts(parts []Schema, path string, refs map[string]bool, fields *[]string) {
+	var found []string
+	for _, part := range parts {
+		collectSensitive(part, path, refs, &found)
+	}
+	for _, field := range found {
+		if !contains(*fields, field) {
+			*fields = append(*fields, field)
+		}
+	}
+}
+
+// RedactInput returns input with the values of sensitive input fields
+// replaced (see Redact).
+func (f *Function) RedactInput(input any) any {
+	return Redact(f.Inputs, input)
+}
+
+// RedactOutput returns output with the values of sensitive output fields
+// replaced (see Redact).
+func (f *Function) RedactOutput(output any) any {
+	if f.Outputs == nil {
+		return toJSONValue(output)
+	}
+	return Redact(f.Outputs, output)
+}
+
+// Sensitive marks the object sensitive: the whole value is redacted.
+func (o *ObjectSchema) Sensitive() *ObjectSchema {
+	o.setSensitive()
+	return o
+}
+
+// Sensitive marks the string sensitive, e.g. for tokens and passwords.
+func (s *StringSchema) Sensitive() *StringSchema {
+	s.setSensitive()
+	return s
+}
+
+// Sensitive marks the number sensitive.
+func (n *NumberSchema) Sensitive() *NumberSchema {
+	n.setSensitive()
+	return n
+}
+
+// Sensitive marks the boolean sensitive.
+func (b *BooleanSchema) Sensitive() *BooleanSchema {
+	b.setSensitive()
+	return b
+}
+
+// Sensitive marks the binary data sensitive.
+func (b *BytesSchema) Sensitive() *BytesSchema {
+	b.setSensitive()
+	return b
+}
+
+// Sensitive marks the array sensitive.
+func (a *ArraySchema) Sensitive() *ArraySchema {
+	a.setSensitive()
+	return a
+}
+
+// Sensitive marks the value sensitive.
+func (n *NullableSchema) Sensitive() *NullableSchema {
+	n.setSensitive()
+	return n
+}
+
+// Sensitive marks the union sensitive.
+func (o *OneOfSchema) Sensitive() *OneOfSchema {
+	o.setSensitive()
+	return o
+}
+
+// Sensitive marks the intersection sensitive.
+func (a *AllOfSchema) Sensitive() *AllOfSchema {
+	a.setSensitive()
+	return a
+}
+
+// Sensitive marks the union sensitive.
+func (d *DiscriminatedUnionSchema) Sensitive() *DiscriminatedUnionSchema {
+	d.setSensitive()
+	return d
+}
+
+// Sensitive marks the value sensitive.
+func (a *AnySchema) Sensitive() *AnySchema {
+	a.setSensitive()
+	return a
+}
+
+// Sensitive marks this use of the shared schema sensitive.
+func (r *RefSchema) Sensitive() *RefSchema {
+	r.setSensitive()
+	return r
+}
@@ -0,0 +1,112 @@
+package ontology
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSensitiveSchema(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"user":  String(),
+		"token": String().Enum("alpha", "beta").Example("alpha").Sensitive(),
+		"pin":   Integer().Min(1000).Sensitive(),
+	})
+
+	token := schema.JSONSchema()["properties"].(map[string]any)["token"].(map[string]any)
+	if token["x-ont-sensitive"] != true {
+		t.Errorf("token JSON Schema = %v", token)
+	}
+	if _, ok := token["examples"]; ok {
+		t.Errorf("token JSON Schema has examples: %v", token)
+	}
+
+	err := schema.Validate(map[string]any{"user": "ada", "token": "s3cret", "pin": 12})
+	if err == nil || strings.Contains(err.Error(), "s3cret") || !strings.Contains(err.Error(), RedactedValue) {
+		t.Errorf("Validate() error = %v", err)
+	}
+	err = ValidateAll(schema, map[string]any{"user": "ada", "token": "alpha", "pin": 12})
+	if err == nil || strings.Contains(err.Error(), "12") {
+		t.Errorf("ValidateAll() error = %v", err)
+	}
+	if err := Object(map[string]Schema{"pin": Int64().Sensitive()}).Validate(map[string]any{"pin": 1.5}); err == nil || strings.Contains(err.Error(), "1.5") {
+		t.Errorf("Validate() int64 error = %v", err)
+	}
+
+	data, _ := json.Marshal(schema.JSONSchema())
+	imported, err := ParseJSONSchema(data)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema failed: %v", err)
+	}
+	if !imported.(*ObjectSchema).Properties()["token"].(Redactable).IsSensitive() {
+		t.Error("imported token should be sensitive")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	type credentials struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	credentialsSchema := Object(map[string]Schema{"user": String(), "password": String().Sensitive()})
+	schema := Object(map[string]Schema{
+		"login":  credentialsSchema,
+		"keys":   Array(String().Sensitive()),
+		"backup": Nullable(credentialsSchema),
+		"payment": DiscriminatedUnion("kind", map[string]*ObjectSchema{
+			"card": Object(map[string]Schema{"number": String().Sensitive()}),
+			"bank": Object(map[string]Schema{"iban": String()}),
+		}),
+	}).PatternProperties("^x-secret-", String().Sensitive())
+
+	got := Redact(schema, map[string]any{
+		"login":        credentials{User: "ada", Password: "hunter2"},
+		"keys":         []string{"k1", "k2"},
+		"backup":       nil,
+		"payment":      map[string]any{"kind": "card", "number": "4111"},
+		"x-secret-api": "abc",
+		"extra":        "kept",
+	})
+	want := map[string]any{
+		"login":        map[string]any{"user": "ada", "password": RedactedValue},
+		"keys":         []any{RedactedValue, RedactedValue},
+		"backup":       nil,
+		"payment":      map[string]any{"kind": "card", "number": RedactedValue},
+		"x-secret-api": RedactedValue,
+		"extra":        "kept",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %v, want %v", got, want)
+	}
+
+	fn := Function{Inputs: schema, Outputs: credentialsSchema}
+	if got := fn.RedactOutput(credentials{User: "ada", Password: "x"}); !reflect.DeepEqual(got, map[string]any{"user": "ada", "password": RedactedValue}) {
+		t.Errorf("RedactOutput() = %v", got)
+	}
+	if got := fn.RedactInput(map[string]any{"keys": []any{"a"}}); !reflect.DeepEqual(got, map[string]any{"keys": []any{RedactedValue}}) {
+		t.Errorf("RedactInput() = %v", got)
+	}
+}
+
+func TestSensitiveFields(t *testing.T) {
+	card := Object(map[string]Schema{
+		"number": String().Sensitive(),
+		"brand":  String(),
+	})
+	schema := Object(map[string]Schema{
+		"password": String().Sensitive(),
+		"cards":    Array(card),
+		"backup":   Nullable(card),
+		"secret":   Object(map[string]Schema{"key": String().Sensitive()}).Sensitive(),
+		"payment":  OneOf(card, Object(map[string]Schema{"number": String().Sensitive(), "iban": String().Sensitive()})),
+		"a/b":      String().Sensitive(),
+	})
+	want := []string{"/a~1b", "/backup/number", "/cards/*/number", "/password", "/payment/iban", "/payment/number", "/secret"}
+	if got := SensitiveFields(schema); !reflect.DeepEqual(got, want) {
+		t.Errorf("SensitiveFields() = %v, want %v", got, want)
+	}
+	if got := SensitiveFields(String()); len(got) != 0 {
+		t.Errorf("SensitiveFields(String()) = %v", got)
+	}
+}
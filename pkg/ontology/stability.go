@@ -0,0 +1,43 @@
+package ontology
+
+import "fmt"
+
+// Stability says how settled a function's contract is.
+type Stability string
+
+// Stability levels. Functions without one are stable.
+const (
+	// StabilityExperimental functions may change or disappear at any time.
+	// Servers only serve them to callers that opt in, and lock diffs don't
+	// require review for their changes unless access changes.
+	StabilityExperimental Stability = "experimental"
+	// StabilityBeta functions are nearly settled but may still change.
+	StabilityBeta Stability = "beta"
+	// StabilityStable functions only change with review.
+	StabilityStable Stability = "stable"
+)
+
+// IsExperimental reports whether the function is experimental.
+func (f *Function) IsExperimental() bool {
+	return f.Stability == StabilityExperimental
+}
+
+// Badge returns a label for documentation and tool descriptions, e.g.
+// "[Experimental]", or "" for stable functions.
+func (s Stability) Badge() string {
+	switch s {
+	case StabilityExperimental:
+		return "[Experimental]"
+	case StabilityBeta:
+		return "[Beta]"
+	}
+	return ""
+}
+
+func validateStability(s Stability) error {
+	switch s {
+	case "", StabilityExperimental, StabilityBeta, StabilityStable:
+		return nil
+	}
+	return fmt.Errorf("unknown stability '%s' (expected experimental, beta or stable)", s)
+}
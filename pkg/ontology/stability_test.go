@@ -0,0 +1,104 @@
+package ontology
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func stabilityConfig() *Config {
+	return &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}, "support": {Description: "Support"}},
+		Entities:     map[string]Entity{},
+		Functions: map[string]Function{
+			"getUser": {
+				Description: "Get a user",
+				Access:      []string{"admin"},
+				Inputs:      Object(map[string]Schema{"id": String()}),
+				Outputs:     Object(map[string]Schema{"name": String()}),
+			},
+			"searchUsers": {
+				Description: "Search users",
+				Access:      []string{"admin"},
+				Stability:   StabilityExperimental,
+				Inputs:      Object(map[string]Schema{"q": String()}),
+				Outputs:     Object(map[string]Schema{"ids": Array(String())}),
+			},
+		},
+	}
+}
+
+func TestStabilityValidation(t *testing.T) {
+	config := stabilityConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	fn := config.Functions["getUser"]
+	fn.Stability = "alpha"
+	config.Functions["getUser"] = fn
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "unknown stability 'alpha'") {
+		t.Errorf("expected unknown stability error, got %v", err)
+	}
+}
+
+func TestStabilityBadge(t *testing.T) {
+	for stability, want := range map[Stability]string{
+		StabilityExperimental: "[Experimental]",
+		StabilityBeta:         "[Beta]",
+		StabilityStable:       "",
+		"":                    "",
+	} {
+		if got := stability.Badge(); got != want {
+			t.Errorf("Badge(%q) = %q, want %q", stability, got, want)
+		}
+	}
+}
+
+func TestLockDiffExperimental(t *testing.T) {
+	config := stabilityConfig()
+	lockPath := filepath.Join(t.TempDir(), "ont.lock")
+	if err := config.WriteLock(lockPath); err != nil {
+		t.Fatalf("Failed to write lock: %v", err)
+	}
+
+	// Changing an experimental function's shape doesn't require review
+	fn := config.Functions["searchUsers"]
+	fn.Outputs = Object(map[string]Schema{"ids": Array(String()), "total": Integer()})
+	config.Functions["searchUsers"] = fn
+	diff, err := config.DiffLock(lockPath)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !diff.HasChanges() || diff.RequiresReview() {
+		t.Errorf("expected an unreviewed experimental change, got %+v", diff)
+	}
+	if len(diff.ExperimentalFunctions) != 1 || diff.ExperimentalFunctions[0] != "searchUsers" {
+		t.Errorf("ExperimentalFunctions = %v", diff.ExperimentalFunctions)
+	}
+
+	// Changing who may call it does
+	fn.Access = []string{"admin", "support"}
+	config.Functions["searchUsers"] = fn
+	if diff, _ = config.DiffLock(lockPath); !diff.RequiresReview() {
+		t.Error("access changes to experimental functions should require review")
+	}
+
+	// So does promoting it to stable
+	fn.Access = []string{"admin"}
+	fn.Stability = StabilityStable
+	config.Functions["searchUsers"] = fn
+	if diff, _ = config.DiffLock(lockPath); !diff.RequiresReview() {
+		t.Error("promoting a function should require review")
+	}
+
+	// And any change to a stable function
+	config = stabilityConfig()
+	fn = config.Functions["getUser"]
+	fn.Description = "Get a user by ID"
+	config.Functions["getUser"] = fn
+	if diff, _ = config.DiffLock(lockPath); !diff.RequiresReview() {
+		t.Error("changes to stable functions should require review")
+	}
+}
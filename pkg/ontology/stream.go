@@ -0,0 +1,153 @@
+package ontology
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// StreamEncoder writes values as JSON while validating them against a
+// schema. Arrays are written item by item, each item validated just before
+// it is encoded, so a large result is walked once rather than validated in
+// full before its first byte is sent. A validation failure doesn't stop
+// encoding; the first one is reported by Invalid.
+type StreamEncoder struct {
+	w          io.Writer
+	schema     Schema
+	naming     FieldNaming
+	validators map[Schema]ValidateFunc
+	invalid    error
+}
+
+// NewStreamEncoder returns an encoder that writes to w, validating against
+// schema and renaming fields to their wire names with naming.
+func NewStreamEncoder(w io.Writer, schema Schema, naming FieldNaming) *StreamEncoder {
+	return &StreamEncoder{w: w, schema: schema, naming: naming, validators: map[Schema]ValidateFunc{}}
+}
+
+// Encode writes data followed by a newline, like json.Encoder. It returns
+// an error only if data can't be encoded or written.
+func (e *StreamEncoder) Encode(data any) error {
+	w := bufio.NewWriter(e.w)
+	if err := e.encode(w, e.schema, data, ""); err != nil {
+		return err
+	}
+	if err := w.WriteByte('\n'); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// Invalid returns the first validation error of the values encoded so far,
+// in the form Schema.Validate reports it.
+func (e *StreamEncoder) Invalid() error {
+	return e.invalid
+}
+
+func (e *StreamEncoder) encode(w *bufio.Writer, schema Schema, data any, prefix string) error {
+	switch s := Deref(schema).(type) {
+	case *NullableSchema:
+		if data == nil {
+			_, err := w.WriteString("null")
+			return err
+		}
+		if len(s.refines) == 0 {
+			return e.encode(w, s.inner, data, prefix)
+		}
+	case *ArraySchema:
+		if val, ok := streamable(data); ok {
+			return e.encodeArray(w, s, val, prefix)
+		}
+	}
+
+	e.check(prefix, e.validator(schema)(data))
+	return e.write(w, ToWireNames(schema, data, e.naming))
+}
+
+// encodeArray writes an array item by item, then checks the constraints
+// that need every item.
+func (e *StreamEncoder) encodeArray(w *bufio.Writer, a *ArraySchema, val reflect.Value, prefix string) error {
+	length := val.Len()
+	e.check(prefix, a.message(a.checkLength(length)))
+
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+	for i := 0; i < length; i++ {
+		if i > 0 {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if err := e.encode(w, a.items, val.Index(i).Interface(), fmt.Sprintf("%sitem %d: ", prefix, i)); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteByte(']'); err != nil {
+		return err
+	}
+
+	if a.unique {
+		items := make([]any, length)
+		for i := range items {
+			items[i] = val.Index(i).Interface()
+		}
+		e.check(prefix, a.message(a.checkUnique(items)))
+	}
+	e.check(prefix, a.refine(val.Interface()))
+	return nil
+}
+
+// streamable reports whether data is a slice or array that can be written
+// item by item: not nil, not bytes and without its own MarshalJSON.
+func streamable(data any) (reflect.Value, bool) {
+	val := reflect.ValueOf(data)
+	switch val.Kind() {
+	case reflect.Slice:
+		if val.IsNil() {
+			return val, false
+		}
+	case reflect.Array:
+	default:
+		return val, false
+	}
+	if val.Type().Elem().Kind() == reflect.Uint8 {
+		return val, false
+	}
+	if _, ok := data.(json.Marshaler); ok {
+		return val, false
+	}
+	return val, true
+}
+
+// validator returns the compiled validator for schema, compiling it once.
+func (e *StreamEncoder) validator(schema Schema) ValidateFunc {
+	validate, ok := e.validators[schema]
+	if !ok {
+		validate = Compile(schema)
+		e.validators[schema] = validate
+	}
+	return validate
+}
+
+// check records err as the first validation error, if it is one.
+func (e *StreamEncoder) check(prefix string, err error) {
+	if err == nil || e.invalid != nil {
+		return
+	}
+	if prefix != "" {
+		err = fmt.Errorf("%s%w", prefix, err)
+	}
+	e.invalid = err
+}
+
+func (e *StreamEncoder) write(w *bufio.Writer, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
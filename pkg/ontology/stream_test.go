@@ -0,0 +1,62 @@
+package ontology
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestStreamEncoder(t *testing.T) {
+	type row struct {
+		ID   string `json:"id"`
+		Size int    `json:"size"`
+	}
+	item := Object(map[string]Schema{"id": String(), "size": Integer().Min(0)})
+	schema := Array(item).MaxItems(3).UniqueBy("id")
+
+	tests := []struct {
+		name    string
+		data    any
+		invalid string
+	}{
+		{"valid", []row{{"a", 1}, {"b", 2}}, ""},
+		{"any items", []any{map[string]any{"id": "a", "size": 1}}, ""},
+		{"invalid item", []row{{"a", 1}, {"b", -1}}, "item 1: field 'size': number -1 is less than minimum 0"},
+		{"too many", []row{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}}, "array has 4 items, maximum is 3"},
+		{"duplicate", []row{{"a", 1}, {"a", 2}}, "items 0 and 1 have the same id"},
+		{"nil", []row(nil), "array cannot be nil - use empty slice []T{} instead"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		encoder := NewStreamEncoder(&buf, schema, CamelCase)
+		if err := encoder.Encode(tt.data); err != nil {
+			t.Fatalf("%s: Encode() error = %v", tt.name, err)
+		}
+		want, _ := json.Marshal(tt.data)
+		if buf.String() != string(want)+"\n" {
+			t.Errorf("%s: Encode() wrote %s, want %s", tt.name, buf.String(), want)
+		}
+		if got := errorText(encoder.Invalid()); got != tt.invalid {
+			t.Errorf("%s: Invalid() = %q, want %q", tt.name, got, tt.invalid)
+		}
+		if want := errorText(schema.Validate(tt.data)); want != tt.invalid {
+			t.Errorf("%s: Validate() = %q, want %q", tt.name, want, tt.invalid)
+		}
+	}
+}
+
+func TestStreamEncoderNaming(t *testing.T) {
+	schema := Nullable(Array(Object(map[string]Schema{"userId": String()})))
+
+	var buf bytes.Buffer
+	encoder := NewStreamEncoder(&buf, schema, SnakeCase)
+	if err := encoder.Encode([]any{map[string]any{"userId": "u1"}, map[string]any{"userId": 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `[{"user_id":"u1"},{"user_id":2}]`+"\n"; got != want {
+		t.Errorf("Encode() wrote %s, want %s", got, want)
+	}
+	if got, want := errorText(encoder.Invalid()), "item 1: field 'userId': expected string, got int"; got != want {
+		t.Errorf("Invalid() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package ontology
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Strict rejects properties the object doesn't declare, instead of
+// silently ignoring them. JSON Schema output gets "additionalProperties": false.
+// Strictness applies to map data such as decoded JSON; structs are checked
+// against the declared properties only.
+func (o *ObjectSchema) Strict() *ObjectSchema {
+	o.constrain("additionalProperties")
+	o.strict = true
+	return o
+}
+
+// IsStrict reports whether the object rejects unknown properties.
+func (o *ObjectSchema) IsStrict() bool {
+	return o.strict
+}
+
+// unknownField returns an error for the first key of m, in sorted order,
+// that isn't a declared property or one of the allowed extra keys.
+func (o *ObjectSchema) unknownField(m map[string]any, allowed ...string) error {
+	for _, key := range sortedKeys(m) {
+		if _, ok := o.properties[key]; !ok && !contains(allowed, key) && !o.matchesPattern(key) {
+			return o.message(fieldError("additionalProperties", key, fmt.Sprintf("unknown field '%s'", key)))
+		}
+	}
+	return nil
+}
+
+// RejectUnknownFields checks data as if every object in schema were
+// Strict, returning an error for the first undeclared property at any depth.
+// It lets servers validate inputs strictly without changing shared schemas.
+func RejectUnknownFields(schema Schema, data any) error {
+	return rejectUnknownFields(schema, data, nil)
+}
+
+func rejectUnknownFields(schema Schema, data any, allowed []string) error {
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if err := s.unknownField(m, allowed...); err != nil {
+			return err
+		}
+		for _, name := range sortedKeys(s.properties) {
+			val, ok := m[name]
+			if !ok {
+				continue
+			}
+			if err := rejectUnknownFields(s.properties[name], val, nil); err != nil {
+				return fmt.Errorf("field '%s': %w", name, err)
+			}
+		}
+		for _, key := range sortedKeys(m) {
+			for _, p := range s.patterns {
+				if !p.pattern.MatchString(key) {
+					continue
+				}
+				if err := rejectUnknownFields(p.schema, m[key], nil); err != nil {
+					return fmt.Errorf("field '%s': %w", key, err)
+				}
+			}
+		}
+	case *ArraySchema:
+		items, ok := data.([]any)
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			if err := rejectUnknownFields(s.items, item, nil); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+	case *NullableSchema:
+		if data != nil {
+			return rejectUnknownFields(s.inner, data, allowed)
+		}
+	case *RefSchema:
+		if s.target != nil {
+			return rejectUnknownFields(s.target, data, allowed)
+		}
+	case *DiscriminatedUnionSchema:
+		m, ok := data.(map[string]any)
+		if !ok {
+			return nil
+		}
+		tag, _ := m[s.discriminator].(string)
+		if variant, ok := s.variants[tag]; ok {
+			if err := rejectUnknownFields(variant, data, []string{s.discriminator}); err != nil {
+				return fmt.Errorf("variant '%s': %w", tag, err)
+			}
+		}
+	case *AllOfSchema:
+		// A field declared by any of the schemas is known to all of them
+		known := append(slices.Clone(allowed), s.propertyNames()...)
+		for _, part := range s.schemas {
+			if err := rejectUnknownFields(part, data, known); err != nil {
+				return err
+			}
+		}
+	case *OneOfSchema:
+		// Check against the alternative the data matches
+		for _, alt := range s.alternatives {
+			if alt.Validate(data) == nil {
+				return rejectUnknownFields(alt, data, allowed)
+			}
+		}
+	}
+	return nil
+}
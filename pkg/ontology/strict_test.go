@@ -0,0 +1,95 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStrictObject(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"name": String(),
+		"tags": Array(String()),
+	}).Strict()
+
+	if err := schema.Validate(map[string]any{"name": "a", "tags": []any{}}); err != nil {
+		t.Errorf("declared properties rejected: %v", err)
+	}
+
+	err := schema.Validate(map[string]any{"name": "a", "tags": []any{}, "nmae": "typo"})
+	if err == nil || err.Error() != "unknown field 'nmae'" {
+		t.Errorf("Validate error = %v, want unknown field", err)
+	}
+
+	if schema.JSONSchema()["additionalProperties"] != false {
+		t.Error("strict objects should set additionalProperties: false")
+	}
+	if _, ok := Object(map[string]Schema{}).JSONSchema()["additionalProperties"]; ok {
+		t.Error("non-strict objects should not set additionalProperties")
+	}
+}
+
+func TestStrictDiscriminatedUnionVariant(t *testing.T) {
+	schema := DiscriminatedUnion("type", map[string]*ObjectSchema{
+		"card": Object(map[string]Schema{"number": String()}).Strict(),
+	})
+
+	if err := schema.Validate(map[string]any{"type": "card", "number": "4242"}); err != nil {
+		t.Errorf("discriminator rejected by strict variant: %v", err)
+	}
+	if err := schema.Validate(map[string]any{"type": "card", "number": "4242", "cvv": "123"}); err == nil {
+		t.Error("unknown variant field should be rejected")
+	}
+}
+
+func TestRejectUnknownFields(t *testing.T) {
+	config := &Config{Schemas: map[string]Schema{
+		"Address": Object(map[string]Schema{"city": String()}),
+	}}
+	schema := Object(map[string]Schema{
+		"address": Ref("Address"),
+		"items":   Array(Object(map[string]Schema{"sku": String()})),
+		"payment": DiscriminatedUnion("type", map[string]*ObjectSchema{
+			"card": Object(map[string]Schema{"number": String()}),
+		}),
+		"note": Nullable(Object(map[string]Schema{"text": String()})),
+	})
+	config.Functions = map[string]Function{"f": {Inputs: schema, Outputs: Object(map[string]Schema{})}}
+	if err := config.resolveRefs(); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]any{
+		"address": map[string]any{"city": "Paris"},
+		"items":   []any{map[string]any{"sku": "A"}},
+		"payment": map[string]any{"type": "card", "number": "4242"},
+		"note":    nil,
+	}
+	if err := RejectUnknownFields(schema, valid); err != nil {
+		t.Errorf("valid data rejected: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(m map[string]any)
+		wantErr string
+	}{
+		{"top level", func(m map[string]any) { m["extra"] = 1 }, "unknown field 'extra'"},
+		{"through ref", func(m map[string]any) { m["address"] = map[string]any{"city": "Paris", "zip": "75001"} }, "field 'address': unknown field 'zip'"},
+		{"array item", func(m map[string]any) { m["items"] = []any{map[string]any{"sku": "A", "qty": 1}} }, "field 'items': item 0: unknown field 'qty'"},
+		{"variant", func(m map[string]any) { m["payment"] = map[string]any{"type": "card", "number": "1", "cvv": "1"} }, "variant 'card': unknown field 'cvv'"},
+		{"nullable", func(m map[string]any) { m["note"] = map[string]any{"text": "x", "by": "y"} }, "field 'note': unknown field 'by'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]any{}
+			for k, v := range valid {
+				data[k] = v
+			}
+			tt.mutate(data)
+			err := RejectUnknownFields(schema, data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RejectUnknownFields error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a struct field as encoding/json encodes it.
+type structField struct {
+	field reflect.StructField
+	// index is the field's path through embedded structs (see FieldByIndex).
+	index []int
+}
+
+// value returns the field's value in val, or false if an embedded pointer
+// on the way to it is nil, in which case encoding/json leaves it out.
+func (f structField) value(val reflect.Value) (reflect.Value, bool) {
+	for i, x := range f.index {
+		if i > 0 && val.Kind() == reflect.Pointer {
+			if val.IsNil() {
+				return reflect.Value{}, false
+			}
+			val = val.Elem()
+		}
+		val = val.Field(x)
+	}
+	return val, true
+}
+
+// structFieldCache maps struct types to their fields by JSON name.
+var structFieldCache sync.Map
+
+// structFields returns the fields encoding/json encodes for a struct type,
+// by JSON name. Fields of embedded structs are promoted, and when names
+// collide the shallowest field wins, then the tagged one; otherwise the
+// name is dropped, as encoding/json does.
+func structFields(typ reflect.Type) map[string]structField {
+	if fields, ok := structFieldCache.Load(typ); ok {
+		return fields.(map[string]structField)
+	}
+
+	type candidate struct {
+		structField
+		depth  int
+		tagged bool
+	}
+	byName := map[string][]candidate{}
+	var walk func(t reflect.Type, index []int, visited []reflect.Type)
+	walk = func(t reflect.Type, index []int, visited []reflect.Type) {
+		for _, v := range visited {
+			if v == t {
+				return
+			}
+		}
+		visited = append(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			fieldIndex := append(append([]int(nil), index...), i)
+
+			if field.Anonymous {
+				embedded := field.Type
+				if embedded.Kind() == reflect.Pointer {
+					embedded = embedded.Elem()
+				}
+				if name == "" && embedded.Kind() == reflect.Struct {
+					walk(embedded, fieldIndex, visited)
+					continue
+				}
+			}
+			if !field.IsExported() {
+				continue
+			}
+
+			tagged := name != ""
+			if !tagged {
+				name = field.Name
+			}
+			byName[name] = append(byName[name], candidate{structField{field, fieldIndex}, len(index), tagged})
+		}
+	}
+	walk(typ, nil, nil)
+
+	fields := make(map[string]structField, len(byName))
+	for name, candidates := range byName {
+		var dominant []candidate
+		for _, c := range candidates {
+			switch {
+			case len(dominant) == 0 || c.depth < dominant[0].depth:
+				dominant = []candidate{c}
+			case c.depth == dominant[0].depth:
+				dominant = append(dominant, c)
+			}
+		}
+		var tagged []candidate
+		for _, c := range dominant {
+			if c.tagged {
+				tagged = append(tagged, c)
+			}
+		}
+		switch {
+		case len(tagged) == 1:
+			fields[name] = tagged[0].structField
+		case len(dominant) == 1:
+			fields[name] = dominant[0].structField
+		}
+	}
+
+	actual, _ := structFieldCache.LoadOrStore(typ, fields)
+	return actual.(map[string]structField)
+}
+
+// lookupField finds a property's struct field by its JSON name, or by its
+// capitalized name for untagged fields.
+func lookupField(val reflect.Value, name string) (structField, reflect.Value, bool) {
+	fields := structFields(val.Type())
+	f, ok := fields[name]
+	if !ok {
+		f, ok = fields[capitalize(name)]
+	}
+	if !ok {
+		return structField{}, reflect.Value{}, false
+	}
+	fieldVal, ok := f.value(val)
+	return f, fieldVal, ok
+}
+
+// fieldValue returns a field's value as encoding/json sees it: pointers are
+// followed, and nil pointers are null.
+func fieldValue(val reflect.Value) any {
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	return val.Interface()
+}
+
+// isNull reports whether data encodes as JSON null: nil, or a nil pointer,
+// map, slice or interface.
+func isNull(data any) bool {
+	if data == nil {
+		return true
+	}
+	val := reflect.ValueOf(data)
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return val.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package ontology
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tagPattern restricts tags to names that make readable SDK namespaces.
+var tagPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Tags returns every tag used by a function, sorted.
+func (c *Config) Tags() []string {
+	seen := make(map[string]bool)
+	for _, fn := range c.Functions {
+		for _, tag := range fn.Tags {
+			seen[tag] = true
+		}
+	}
+	return sortedKeys(seen)
+}
+
+// FunctionsWithTag returns the functions tagged with tag.
+func (c *Config) FunctionsWithTag(tag string) []string {
+	return c.Query(FunctionQuery{Tag: tag})
+}
+
+// Tagged returns the config with only the functions tagged with one of
+// tags, e.g. to generate an SDK for one area of a large ontology.
+func (c *Config) Tagged(tags ...string) *Config {
+	tagged := *c
+	tagged.Functions = make(map[string]Function)
+	for name, fn := range c.Functions {
+		for _, tag := range fn.Tags {
+			if contains(tags, tag) {
+				tagged.Functions[name] = fn
+				break
+			}
+		}
+	}
+	return &tagged
+}
+
+// TagNamespace returns the identifier generated SDKs use for a tag's
+// namespace: the tag in lower camel case ("data-platform" -> "dataPlatform").
+func TagNamespace(tag string) string {
+	parts := strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })
+	for i, part := range parts {
+		if i == 0 {
+			parts[i] = strings.ToLower(part[:1]) + part[1:]
+		} else {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// validateTags checks that a function's tags are well-formed and that their
+// SDK namespaces don't collide with function names.
+func (c *Config) validateTags(fn Function) error {
+	seen := make(map[string]bool)
+	for _, tag := range fn.Tags {
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("invalid tag '%s': tags must start with a letter and contain only letters, digits, '-' and '_'", tag)
+		}
+		if seen[tag] {
+			return fmt.Errorf("duplicate tag '%s'", tag)
+		}
+		seen[tag] = true
+		if _, ok := c.Functions[TagNamespace(tag)]; ok {
+			return fmt.Errorf("tag '%s' conflicts with function '%s' in generated SDKs", tag, TagNamespace(tag))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func tagsConfig() *Config {
+	config := queryConfig()
+	for name, tags := range map[string][]string{
+		"getCustomer":    {"crm"},
+		"deleteCustomer": {"crm", "admin-tools"},
+		"sendMessage":    {"messaging"},
+	} {
+		fn := config.Functions[name]
+		fn.Tags = tags
+		config.Functions[name] = fn
+	}
+	return config
+}
+
+func TestTags(t *testing.T) {
+	config := tagsConfig()
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if got := config.Tags(); !reflect.DeepEqual(got, []string{"admin-tools", "crm", "messaging"}) {
+		t.Errorf("Tags = %v", got)
+	}
+	if got := config.FunctionsWithTag("crm"); !reflect.DeepEqual(got, []string{"deleteCustomer", "getCustomer"}) {
+		t.Errorf("FunctionsWithTag = %v", got)
+	}
+	if got := config.Query(FunctionQuery{Tag: "crm", AccessGroup: "admin"}); !reflect.DeepEqual(got, []string{"deleteCustomer"}) {
+		t.Errorf("Query by tag = %v", got)
+	}
+	if got := sortedKeys(config.Tagged("admin-tools", "messaging").Functions); !reflect.DeepEqual(got, []string{"deleteCustomer", "sendMessage"}) {
+		t.Errorf("Tagged = %v", got)
+	}
+}
+
+func TestTagNamespace(t *testing.T) {
+	for tag, want := range map[string]string{
+		"billing":       "billing",
+		"data-platform": "dataPlatform",
+		"Admin_tools":   "adminTools",
+	} {
+		if got := TagNamespace(tag); got != want {
+			t.Errorf("TagNamespace(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		wantErr string
+	}{
+		{"invalid characters", []string{"bad tag"}, "invalid tag"},
+		{"leading digit", []string{"1st"}, "invalid tag"},
+		{"duplicate", []string{"crm", "crm"}, "duplicate tag"},
+		{"conflicts with function", []string{"send-message"}, "conflicts with function 'sendMessage'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tagsConfig()
+			fn := config.Functions["getCustomer"]
+			fn.Tags = tt.tags
+			config.Functions["getCustomer"] = fn
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
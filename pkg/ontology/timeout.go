@@ -0,0 +1,92 @@
+package ontology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// ErrTimeout is returned when a function runs longer than its Timeout. The
+// server answers REST calls that fail with it with 504.
+var ErrTimeout = errors.New("function timed out")
+
+// deadlineContext gives a resolver a request whose context has the
+// function's deadline, so resolvers passing ctx.Request().Context() to
+// upstream calls stop when the function times out.
+type deadlineContext struct {
+	Context
+	request *http.Request
+}
+
+func (c *deadlineContext) Request() *http.Request {
+	return c.request
+}
+
+func (c *deadlineContext) WithLogFields(keysAndValues ...any) Context {
+	return &deadlineContext{Context: c.Context.WithLogFields(keysAndValues...), request: c.request}
+}
+
+// resolveWithTimeout resolves fn, giving up with ErrTimeout after
+// fn.Timeout. As with Fallback, a resolver that ignores its deadline isn't
+// interrupted, and its result is dropped.
+func (c *Config) resolveWithTimeout(ctx Context, fn Function, input any) (any, error) {
+	timeout := fn.Timeout
+	fn.Timeout = 0
+
+	parent := context.Background()
+	if r := ctx.Request(); r != nil {
+		parent = r.Context()
+	}
+	deadline, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	if r := ctx.Request(); r != nil {
+		ctx = &deadlineContext{Context: ctx, request: r.WithContext(deadline)}
+	}
+
+	type result struct {
+		output any
+		err    error
+	}
+	results := make(chan result, 1)
+	go func() {
+		output, err := recoverResolver(ctx, input, func(ctx Context, input any) (any, error) {
+			return c.Resolve(ctx, fn, input)
+		})
+		results <- result{output, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.output, r.err
+	case <-deadline.Done():
+		if errors.Is(deadline.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		return nil, deadline.Err()
+	}
+}
+
+// recoverResolver calls resolve, returning a panic in it as an error.
+// Resolvers run in goroutines of their own use it: net/http only recovers
+// panics in the goroutine serving the request, so a panic elsewhere would
+// crash the server.
+func recoverResolver(ctx Context, input any, resolve ResolverFunc) (output any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			ctx.Logger().Error("Resolver panicked", "panic", r, "stack", string(debug.Stack()))
+			output, err = nil, fmt.Errorf("resolver panicked: %v", r)
+		}
+	}()
+	return resolve(ctx, input)
+}
+
+// formatTimeout returns the lock file form of a timeout.
+func formatTimeout(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,75 @@
+package ontology
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	config := &Config{}
+	ctx := NewContext(httptest.NewRequest("POST", "/api/getUser", nil), DefaultLogger(), nil, nil)
+
+	fn := fallbackFunction(named("primary", 0, nil), nil)
+	fn.Timeout = time.Second
+	if output, err := config.Resolve(ctx, fn, map[string]any{}); err != nil || output.(map[string]any)["name"] != "primary" {
+		t.Fatalf("Resolve() = %v, %v", output, err)
+	}
+
+	cancelled := make(chan struct{})
+	fn.Resolver = func(ctx Context, input any) (any, error) {
+		<-ctx.Request().Context().Done()
+		close(cancelled)
+		return nil, ctx.Request().Context().Err()
+	}
+	fn.Timeout = 10 * time.Millisecond
+	_, err := config.Resolve(ctx, fn, map[string]any{})
+	if !errors.Is(err, ErrTimeout) || !strings.Contains(err.Error(), "10ms") {
+		t.Errorf("Resolve() error = %v, want ErrTimeout after 10ms", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("resolver's request context was not cancelled")
+	}
+}
+
+func TestTimeoutWithoutRequest(t *testing.T) {
+	fn := fallbackFunction(named("primary", time.Second, nil), nil)
+	fn.Timeout = 10 * time.Millisecond
+	_, err := (&Config{}).Resolve(NewContext(nil, DefaultLogger(), nil, nil), fn, map[string]any{})
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Resolve() error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestTimeoutLock(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+		Entities:     map[string]Entity{},
+		Functions:    map[string]Function{"getUser": fallbackFunction(named("primary", 0, nil), nil)},
+	}
+	before := config.Hash()
+
+	fn := config.Functions["getUser"]
+	fn.Timeout = 5 * time.Second
+	config.Functions["getUser"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if config.Hash() == before {
+		t.Error("hash should change when a timeout is set")
+	}
+	if got := config.ExtractSnapshot().Functions["getUser"].Timeout; got != "5s" {
+		t.Errorf("snapshot timeout = %q, want 5s", got)
+	}
+
+	fn.Timeout = -time.Second
+	config.Functions["getUser"] = fn
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "timeout must not be negative") {
+		t.Errorf("expected negative timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// maxTranscriptJSON bounds the input and output JSON of a formatted
+// transcript, so examples don't crowd out the tool description.
+const maxTranscriptJSON = 300
+
+// Transcript is an example call of a function: an input and the output the
+// function returns for it, in their JSON form.
+type Transcript struct {
+	Input  any `json:"input"`
+	Output any `json:"output"`
+}
+
+// FakeTranscript returns a transcript of made-up input and output (see
+// Fake), validated against the function's schemas. It fails for functions
+// whose schemas Fake can't satisfy, such as those with patterns.
+func (f *Function) FakeTranscript() (Transcript, error) {
+	input := Fake(f.CallInputs())
+	if err := f.CallInputs().Validate(input); err != nil {
+		return Transcript{}, fmt.Errorf("fake input is invalid: %w", err)
+	}
+	var output any
+	if f.Outputs != nil {
+		output = Fake(f.Outputs)
+		if err := f.Outputs.Validate(output); err != nil {
+			return Transcript{}, fmt.Errorf("fake output is invalid: %w", err)
+		}
+	}
+	return Transcript{Input: input, Output: output}, nil
+}
+
+// RecordTranscript returns the transcript of a call, with sensitive values
+// redacted (see Redact), for collecting examples from real traffic.
+func (f *Function) RecordTranscript(input, output any) Transcript {
+	return Transcript{Input: f.RedactInput(input), Output: f.RedactOutput(output)}
+}
+
+// Format renders the transcript as a line for a tool description:
+//
+//	Example: getUser({"id":"42"}) returns {"name":"Ada"}
+//
+// Long inputs and outputs are shortened.
+func (t Transcript) Format(name string) string {
+	return fmt.Sprintf("Example: %s(%s) returns %s", name, transcriptJSON(t.Input), transcriptJSON(t.Output))
+}
+
+// transcriptJSON returns v as compact JSON of at most maxTranscriptJSON
+// bytes.
+func transcriptJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "?"
+	}
+	if len(data) > maxTranscriptJSON {
+		n := maxTranscriptJSON
+		for n > 0 && !utf8.RuneStart(data[n]) {
+			n--
+		}
+		return string(data[:n]) + "…"
+	}
+	return string(data)
+}
@@ -0,0 +1,239 @@
+package ontology
+
+import "fmt"
+
+// ValueTransformFunc normalizes a value that passed validation. The value
+// is in its JSON form, like for RefineFunc.
+type ValueTransformFunc func(v any) (any, error)
+
+// transforms holds the normalization steps shared by every schema type.
+type transforms struct {
+	transformFns []ValueTransformFunc
+}
+
+// transform runs the steps in order, each on the result of the previous.
+func (t *transforms) transform(data any) (any, error) {
+	for _, fn := range t.transformFns {
+		var err error
+		if data, err = fn(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// transformer is implemented by every schema type.
+type transformer interface {
+	transform(data any) (any, error)
+	transformCount() int
+}
+
+// ApplyTransforms runs the transforms declared anywhere in schema on data
+// that has already been validated, innermost values first. Data without
+// transforms in its schema is returned unchanged; otherwise it is converted
+// to its JSON form first. The result is not validated again.
+func ApplyTransforms(schema Schema, data any) (any, error) {
+	if !hasTransforms(schema) {
+		return data, nil
+	}
+	return applyTransforms(schema, toJSONValue(data), "")
+}
+
+func applyTransforms(schema Schema, data any, path string) (any, error) {
+	switch s := schema.(type) {
+	case *ObjectSchema:
+		if m, ok := data.(map[string]any); ok {
+			for _, name := range sortedKeys(s.properties) {
+				val, exists := m[name]
+				if !exists {
+					continue
+				}
+				updated, err := applyTransforms(s.properties[name], val, joinPath(path, name))
+				if err != nil {
+					return nil, err
+				}
+				m[name] = updated
+			}
+		}
+	case *ArraySchema:
+		if arr, ok := data.([]any); ok {
+			for i, item := range arr {
+				updated, err := applyTransforms(s.items, item, fmt.Sprintf("%s[%d]", path, i))
+				if err != nil {
+					return nil, err
+				}
+				arr[i] = updated
+			}
+		}
+	case *NullableSchema:
+		if data != nil {
+			updated, err := applyTransforms(s.inner, data, path)
+			if err != nil {
+				return nil, err
+			}
+			data = updated
+		}
+	case *RefSchema:
+		if s.target != nil {
+			updated, err := applyTransforms(s.target, data, path)
+			if err != nil {
+				return nil, err
+			}
+			data = updated
+		}
+	case *DiscriminatedUnionSchema:
+		if m, ok := data.(map[string]any); ok {
+			tag, _ := m[s.discriminator].(string)
+			if variant, ok := s.variants[tag]; ok {
+				updated, err := applyTransforms(variant, data, path)
+				if err != nil {
+					return nil, err
+				}
+				data = updated
+			}
+		}
+	case *OneOfSchema:
+		// Transform with the first alternative the data matches
+		for _, alt := range s.alternatives {
+			if alt.Validate(data) == nil {
+				updated, err := applyTransforms(alt, data, path)
+				if err != nil {
+					return nil, err
+				}
+				data = updated
+				break
+			}
+		}
+	case *AllOfSchema:
+		// The data matches every schema, so each transforms it in turn
+		for _, part := range s.schemas {
+			updated, err := applyTransforms(part, data, path)
+			if err != nil {
+				return nil, err
+			}
+			data = updated
+		}
+	}
+
+	t, ok := schema.(transformer)
+	if !ok {
+		return data, nil
+	}
+	result, err := t.transform(data)
+	if err != nil {
+		if path == "" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("field '%s': %w", path, err)
+	}
+	return result, nil
+}
+
+// hasTransforms reports whether schema declares transforms at any depth.
+func hasTransforms(schema Schema) bool {
+	refs := make(map[string]*RefSchema)
+	collectRefs(schema, refs)
+	schemas := []Schema{schema}
+	for _, ref := range refs {
+		if ref.target != nil {
+			schemas = append(schemas, ref.target)
+		}
+	}
+
+	found := false
+	for _, s := range schemas {
+		walkSchema(s, func(s Schema) {
+			if t, ok := s.(transformer); ok && t.transformCount() > 0 {
+				found = true
+			}
+		})
+	}
+	return found
+}
+
+// transformCount returns the number of transforms added with Transform.
+func (t *transforms) transformCount() int {
+	return len(t.transformFns)
+}
+
+// Transform adds a step that normalizes the object after it validates, e.g.
+// to fill in defaults that depend on several fields. Transforms of the
+// object's properties run first. Transforms apply to function inputs,
+// before TransformInput and the resolver.
+func (o *ObjectSchema) Transform(fn ValueTransformFunc) *ObjectSchema {
+	o.transformFns = append(o.transformFns, fn)
+	return o
+}
+
+// Transform adds a step that normalizes the string after it validates, e.g.
+// trimming it or lowercasing an email address.
+func (s *StringSchema) Transform(fn ValueTransformFunc) *StringSchema {
+	s.transformFns = append(s.transformFns, fn)
+	return s
+}
+
+// Transform adds a step that normalizes the number after it validates.
+func (n *NumberSchema) Transform(fn ValueTransformFunc) *NumberSchema {
+	n.transformFns = append(n.transformFns, fn)
+	return n
+}
+
+// Transform adds a step that normalizes the boolean after it validates.
+func (b *BooleanSchema) Transform(fn ValueTransformFunc) *BooleanSchema {
+	b.transformFns = append(b.transformFns, fn)
+	return b
+}
+
+// Transform adds a step that normalizes the binary data after it
+// validates. The step receives the base64 string.
+func (b *BytesSchema) Transform(fn ValueTransformFunc) *BytesSchema {
+	b.transformFns = append(b.transformFns, fn)
+	return b
+}
+
+// Transform adds a step that normalizes the array after its items are
+// transformed.
+func (a *ArraySchema) Transform(fn ValueTransformFunc) *ArraySchema {
+	a.transformFns = append(a.transformFns, fn)
+	return a
+}
+
+// Transform adds a step that normalizes the value, including when it is null.
+func (n *NullableSchema) Transform(fn ValueTransformFunc) *NullableSchema {
+	n.transformFns = append(n.transformFns, fn)
+	return n
+}
+
+// Transform adds a step that normalizes the value after the matching
+// alternative's transforms.
+func (o *OneOfSchema) Transform(fn ValueTransformFunc) *OneOfSchema {
+	o.transformFns = append(o.transformFns, fn)
+	return o
+}
+
+// Transform adds a step that normalizes the value after the transforms of
+// every schema.
+func (a *AllOfSchema) Transform(fn ValueTransformFunc) *AllOfSchema {
+	a.transformFns = append(a.transformFns, fn)
+	return a
+}
+
+// Transform adds a step that normalizes the value after the matching
+// variant's transforms.
+func (d *DiscriminatedUnionSchema) Transform(fn ValueTransformFunc) *DiscriminatedUnionSchema {
+	d.transformFns = append(d.transformFns, fn)
+	return d
+}
+
+// Transform adds a step that normalizes the value.
+func (a *AnySchema) Transform(fn ValueTransformFunc) *AnySchema {
+	a.transformFns = append(a.transformFns, fn)
+	return a
+}
+
+// Transform adds a step that normalizes the value after the referenced
+// schema's transforms.
+func (r *RefSchema) Transform(fn ValueTransformFunc) *RefSchema {
+	r.transformFns = append(r.transformFns, fn)
+	return r
+}
@@ -0,0 +1,89 @@
+package ontology
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func trimmed(v any) (any, error) {
+	return strings.TrimSpace(v.(string)), nil
+}
+
+func lowercased(v any) (any, error) {
+	return strings.ToLower(v.(string)), nil
+}
+
+func TestApplySchemaTransforms(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"email": String().Transform(trimmed).Transform(lowercased),
+		"tags":  Array(String().Transform(trimmed)),
+		"note":  Nullable(String().Transform(trimmed)),
+	}).Transform(func(v any) (any, error) {
+		m := v.(map[string]any)
+		m["domain"] = m["email"].(string)[strings.Index(m["email"].(string), "@")+1:]
+		return m, nil
+	})
+
+	got, err := ApplyTransforms(schema, map[string]any{
+		"email": "  Jane@Example.COM ",
+		"tags":  []any{" a", "b "},
+		"note":  nil,
+	})
+	if err != nil {
+		t.Fatalf("ApplyTransforms failed: %v", err)
+	}
+	want := map[string]any{
+		"email":  "jane@example.com",
+		"tags":   []any{"a", "b"},
+		"note":   nil,
+		"domain": "example.com",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyTransforms = %v, want %v", got, want)
+	}
+}
+
+func TestApplyTransformsUnchanged(t *testing.T) {
+	type input struct {
+		Name string `json:"name"`
+	}
+	in := input{Name: " x "}
+	got, err := ApplyTransforms(Object(map[string]Schema{"name": String()}), in)
+	if err != nil || got != in {
+		t.Errorf("schemas without transforms should return data as-is, got %v, %v", got, err)
+	}
+}
+
+func TestApplyTransformsError(t *testing.T) {
+	schema := Object(map[string]Schema{
+		"items": Array(Object(map[string]Schema{
+			"when": String().Transform(func(v any) (any, error) {
+				return nil, errors.New("unparseable date")
+			}),
+		})),
+	})
+	_, err := ApplyTransforms(schema, map[string]any{"items": []any{map[string]any{"when": "soon"}}})
+	if err == nil || err.Error() != "field 'items[0].when': unparseable date" {
+		t.Errorf("expected error with path, got %v", err)
+	}
+}
+
+func TestApplyInputTransformRunsSchemaTransforms(t *testing.T) {
+	fn := Function{
+		Inputs: Object(map[string]Schema{"email": String().Transform(lowercased)}),
+		TransformInput: func(ctx Context, v any) (any, error) {
+			m := v.(map[string]any)
+			m["seen"] = m["email"]
+			return m, nil
+		},
+	}
+	got, err := fn.ApplyInputTransform(nil, map[string]any{"email": "A@B.C"})
+	if err != nil {
+		t.Fatalf("ApplyInputTransform failed: %v", err)
+	}
+	if want := map[string]any{"email": "a@b.c", "seen": "a@b.c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyInputTransform = %v, want %v", got, want)
+	}
+}
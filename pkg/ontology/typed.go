@@ -0,0 +1,51 @@
+package ontology
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Typed adapts a resolver that takes and returns Go types, so it needn't
+// assert its way through map[string]any:
+//
+//	type GetUserInput struct {
+//		ID string `json:"id" ont:"uuid"`
+//	}
+//
+//	"getUser": {
+//		Inputs:   ont.FromStruct[GetUserInput](),
+//		Outputs:  ont.FromStruct[User](),
+//		Resolver: ont.Typed(func(ctx ont.Context, in GetUserInput) (User, error) { ... }),
+//	},
+//
+// The input has been validated against the function's Inputs by the time
+// the resolver runs; Typed decodes it into In like encoding/json, and the
+// returned Out is validated against Outputs like any other output. Input
+// that already has type In (e.g. from TransformInput) is passed through.
+// A decoding error means In doesn't match Inputs and is returned as the
+// resolver's error.
+func Typed[In, Out any](resolver func(ctx Context, in In) (Out, error)) ResolverFunc {
+	return func(ctx Context, input any) (any, error) {
+		in, err := decodeTyped[In](input)
+		if err != nil {
+			return nil, err
+		}
+		return resolver(ctx, in)
+	}
+}
+
+// decodeTyped converts input in its JSON form to T.
+func decodeTyped[T any](input any) (T, error) {
+	if in, ok := input.(T); ok {
+		return in, nil
+	}
+	var in T
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return in, fmt.Errorf("failed to decode input as %T: %w", in, err)
+	}
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return in, fmt.Errorf("failed to decode input as %T: %w", in, err)
+	}
+	return in, nil
+}
@@ -0,0 +1,59 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type typedInput struct {
+	ID    int64     `json:"id"`
+	Since time.Time `json:"since"`
+	Tags  []string  `json:"tags,omitempty"`
+}
+
+type typedOutput struct {
+	Name string `json:"name"`
+}
+
+func TestTyped(t *testing.T) {
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+	var got typedInput
+	resolver := Typed(func(ctx Context, in typedInput) (typedOutput, error) {
+		got = in
+		return typedOutput{Name: "Ada"}, nil
+	})
+
+	fn := Function{Inputs: FromStruct[typedInput](), Outputs: FromStruct[typedOutput]()}
+	input := map[string]any{"id": int64(9007199254740993), "since": "2024-05-01T10:00:00Z", "tags": []any{"a"}}
+	if err := fn.ValidateInput(input); err != nil {
+		t.Fatalf("ValidateInput failed: %v", err)
+	}
+
+	output, err := resolver(ctx, input)
+	if err != nil {
+		t.Fatalf("resolver failed: %v", err)
+	}
+	if got.ID != 9007199254740993 || !got.Since.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) || len(got.Tags) != 1 {
+		t.Errorf("decoded input = %+v", got)
+	}
+	if err := fn.ValidateOutput(output); err != nil {
+		t.Errorf("ValidateOutput failed: %v", err)
+	}
+
+	// Input that is already In is passed through
+	if _, err := resolver(ctx, typedInput{ID: 1}); err != nil || got.ID != 1 {
+		t.Errorf("typed input: got %+v, err %v", got, err)
+	}
+}
+
+func TestTypedDecodeError(t *testing.T) {
+	resolver := Typed(func(ctx Context, in typedInput) (typedOutput, error) {
+		t.Error("resolver should not run")
+		return typedOutput{}, nil
+	})
+	_, err := resolver(NewContext(nil, DefaultLogger(), nil, nil), map[string]any{"id": "not a number"})
+	if err == nil || !strings.Contains(err.Error(), "failed to decode input as ontology.typedInput") {
+		t.Errorf("expected decode error, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package ontology
+
+import "encoding/json"
+
+// Unique requires the items of the array to be distinct, compared by their
+// JSON form. It is emitted as "uniqueItems".
+func (a *ArraySchema) Unique() *ArraySchema {
+	a.constrain("uniqueItems")
+	a.unique = true
+	a.uniqueBy = ""
+	return a
+}
+
+// UniqueBy requires the items of an array of objects to have distinct
+// values of the property key, e.g. UniqueBy("sku") for order lines. Items
+// without the property are not compared. It is emitted as "uniqueItems"
+// with the key under "x-ont-uniqueBy".
+func (a *ArraySchema) UniqueBy(key string) *ArraySchema {
+	a.constrain("uniqueItems")
+	a.unique = true
+	a.uniqueBy = key
+	return a
+}
+
+// IsUnique reports whether the items must be distinct.
+func (a *ArraySchema) IsUnique() bool {
+	return a.unique
+}
+
+// UniqueKey returns the property set with UniqueBy, if any.
+func (a *ArraySchema) UniqueKey() string {
+	return a.uniqueBy
+}
+
+// checkUnique returns an error for the first item that repeats an earlier
+// one, or its UniqueBy property.
+func (a *ArraySchema) checkUnique(items []any) error {
+	if !a.unique {
+		return nil
+	}
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		value := toJSONValue(item)
+		if a.uniqueBy != "" {
+			m, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+			if value, ok = m[a.uniqueBy]; !ok {
+				continue
+			}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		if first, ok := seen[string(data)]; ok {
+			if a.uniqueBy != "" {
+				return constraintError("uniqueItems", a.uniqueBy, nil, "items %d and %d have the same %s", first, i, a.uniqueBy)
+			}
+			return constraintError("uniqueItems", true, nil, "items %d and %d are equal", first, i)
+		}
+		seen[string(data)] = i
+	}
+	return nil
+}
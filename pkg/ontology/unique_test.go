@@ -0,0 +1,69 @@
+package ontology
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnique(t *testing.T) {
+	type line struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	tags := Array(String()).Unique()
+	lines := Array(Object(map[string]Schema{"sku": String(), "qty": Integer()})).UniqueBy("sku")
+	tests := []struct {
+		name   string
+		schema *ArraySchema
+		data   any
+		want   string
+	}{
+		{"distinct", tags, []any{"a", "b"}, ""},
+		{"duplicate", tags, []any{"a", "b", "a"}, "items 0 and 2 are equal"},
+		{"typed duplicate", tags, []string{"x", "x"}, "items 0 and 1 are equal"},
+		{"numbers", Array(Number()).Unique(), []any{1, 1.0}, "items 0 and 1 are equal"},
+		{"objects", Array(Any()).Unique(), []any{map[string]any{"a": 1, "b": 2}, map[string]any{"b": 2, "a": 1}}, "items 0 and 1 are equal"},
+		{"distinct keys", lines, []any{map[string]any{"sku": "A", "qty": 1}, map[string]any{"sku": "B", "qty": 1}}, ""},
+		{"duplicate key", lines, []any{map[string]any{"sku": "A", "qty": 1}, map[string]any{"sku": "A", "qty": 2}}, "items 0 and 1 have the same sku"},
+		{"struct key", lines, []line{{"A", 1}, {"A", 2}}, "items 0 and 1 have the same sku"},
+	}
+	for _, tt := range tests {
+		for name, validate := range map[string]ValidateFunc{"Validate": tt.schema.Validate, "Compile": Compile(tt.schema)} {
+			err := validate(tt.data)
+			if got := errorText(err); got != tt.want {
+				t.Errorf("%s: %s() = %q, want %q", tt.name, name, got, tt.want)
+			}
+		}
+	}
+
+	err := ValidateAll(Object(map[string]Schema{"tags": tags}), map[string]any{"tags": []any{"a", "a"}})
+	if issues, ok := err.(ValidationErrors); !ok || len(issues) != 1 || issues[0].Field != "/tags" {
+		t.Errorf("ValidateAll() = %v", err)
+	}
+	if err := Array(String()).Unique().Message("Tags must be different").Validate([]any{"a", "a"}); errorText(err) != "Tags must be different" {
+		t.Errorf("Message() = %v", err)
+	}
+}
+
+func TestUniqueJSONSchema(t *testing.T) {
+	schema := Array(Object(map[string]Schema{"sku": String()})).UniqueBy("sku")
+	json := schema.JSONSchema()
+	if json["uniqueItems"] != true || json["x-ont-uniqueBy"] != "sku" {
+		t.Errorf("JSONSchema() = %v", json)
+	}
+	imported, err := schemaFromJSON(json)
+	if err != nil {
+		t.Fatalf("ParseJSONSchema() error = %v", err)
+	}
+	if !reflect.DeepEqual(imported.JSONSchema(), json) {
+		t.Errorf("round trip = %v, want %v", imported.JSONSchema(), json)
+	}
+}
+
+// errorText returns the message of err, or "" if it is nil.
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,81 @@
+package ontology
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OrganizationKey is the user context entry auth functions set to the
+// caller's organization, for resolvers that read it with
+// OrganizationContext.
+const OrganizationKey = "organization"
+
+// OrganizationContext returns the caller's organization: the
+// OrganizationKey entry of its user context, or nil if there is none.
+// Functions whose resolvers call it should set UsesOrganizationContext.
+func OrganizationContext(ctx Context) map[string]any {
+	for {
+		tracked, ok := ctx.(*trackedContext)
+		if !ok {
+			break
+		}
+		tracked.reads.organization.Store(true)
+		ctx = tracked.Context
+	}
+	organization, _ := ctx.UserContext()[OrganizationKey].(map[string]any)
+	return organization
+}
+
+// trackedContext records whether a resolver reads the user context or the
+// organization, including through contexts derived with WithLogFields.
+type trackedContext struct {
+	Context
+	reads *contextReads
+}
+
+type contextReads struct {
+	user, organization atomic.Bool
+}
+
+func (c *trackedContext) UserContext() map[string]any {
+	c.reads.user.Store(true)
+	return c.Context.UserContext()
+}
+
+func (c *trackedContext) WithLogFields(keysAndValues ...any) Context {
+	return &trackedContext{Context: c.Context.WithLogFields(keysAndValues...), reads: c.reads}
+}
+
+// contextUse logs undeclared context use by a function's resolver, once per
+// function.
+type contextUse struct {
+	name               string
+	user, organization sync.Once
+}
+
+// check logs the context ctx's resolver read without fn declaring it.
+func (u *contextUse) check(fn Function, ctx *trackedContext) {
+	logger := ctx.Logger()
+	if logger == nil {
+		return
+	}
+	if ctx.reads.user.Load() && !fn.UsesUserContext {
+		u.user.Do(func() {
+			logger.Warn("Resolver reads the user context but the function doesn't set UsesUserContext", "function", u.name)
+		})
+	}
+	if ctx.reads.organization.Load() && !fn.UsesOrganizationContext {
+		u.organization.Do(func() {
+			logger.Warn("Resolver reads the organization but the function doesn't set UsesOrganizationContext", "function", u.name)
+		})
+	}
+}
+
+// optionalBool returns a pointer to true, or nil for false, for lock file
+// flags that are omitted when unset.
+func optionalBool(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return &b
+}
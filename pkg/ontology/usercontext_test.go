@@ -0,0 +1,65 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+// recordingLogger records warnings.
+type recordingLogger struct {
+	Logger
+	warnings []string
+}
+
+func (l *recordingLogger) Warn(msg string, keysAndValues ...any) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func TestUndeclaredContextUse(t *testing.T) {
+	config := queryConfig()
+	fn := config.Functions["deleteCustomer"]
+	fn.Resolver = func(ctx Context, input any) (any, error) {
+		_ = ctx.UserContext()["id"]
+		_ = OrganizationContext(ctx)
+		return fn.Outputs.(*ObjectSchema).Properties(), nil
+	}
+	fn.UsesOrganizationContext = true
+	config.Functions["deleteCustomer"] = fn
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &recordingLogger{Logger: DefaultLogger()}
+	ctx := NewContext(nil, logger, []string{"admin"}, map[string]any{"id": "u-1", OrganizationKey: map[string]any{"id": "o-1"}})
+	for range 2 {
+		config.Resolve(ctx, config.Functions["deleteCustomer"], map[string]any{})
+	}
+	if len(logger.warnings) != 1 || !strings.Contains(logger.warnings[0], "UsesUserContext") {
+		t.Errorf("warnings = %q, want one about UsesUserContext", logger.warnings)
+	}
+}
+
+func TestOrganizationContext(t *testing.T) {
+	ctx := NewContext(nil, DefaultLogger(), nil, map[string]any{OrganizationKey: map[string]any{"id": "o-1"}})
+	if got := OrganizationContext(ctx); got["id"] != "o-1" {
+		t.Errorf("OrganizationContext() = %v", got)
+	}
+	if got := OrganizationContext(NewContext(nil, DefaultLogger(), nil, nil)); got != nil {
+		t.Errorf("OrganizationContext() = %v, want nil", got)
+	}
+}
+
+func TestContextFlagsInLock(t *testing.T) {
+	config := queryConfig()
+	before := config.Hash()
+	fn := config.Functions["getCustomer"]
+	fn.UsesUserContext = true
+	config.Functions["getCustomer"] = fn
+	if config.Hash() == before {
+		t.Error("hash should change when a function uses the user context")
+	}
+	shape := config.ExtractSnapshot().Functions["getCustomer"]
+	if shape.UsesUserContext == nil || !*shape.UsesUserContext || shape.UsesOrganizationContext != nil {
+		t.Errorf("shape = %v, %v", shape.UsesUserContext, shape.UsesOrganizationContext)
+	}
+}
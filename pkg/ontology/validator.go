@@ -2,89 +2,277 @@ package ontology
 
 import (
 	"fmt"
+	"net/url"
 	"reflect"
 )
 
 // Validate checks if the ontology configuration is valid.
-// It validates required fields and semantic rules.
+// It validates required fields and semantic rules, and reports every error
+// ValidateAll finds as ConfigIssues. Warnings don't fail it.
 func (c *Config) Validate() error {
+	if err := c.ValidateAll().Err(); err != nil {
+		return err
+	}
+	c.compileValidators()
+	return nil
+}
+
+// ValidateAll checks the configuration like Validate, but returns every
+// error and warning at once, so a large ontology can be fixed in one pass.
+// Each access group, entity, schema and function is reported at its first
+// error. If schema references can't be resolved, the checks that depend on
+// them are skipped.
+func (c *Config) ValidateAll() ConfigIssues {
+	var issues ConfigIssues
+
 	// Validate required fields
 	if c.Name == "" {
-		return fmt.Errorf("config validation failed: name is required")
+		issues.add(IssueConfig, "", fmt.Errorf("config validation failed: name is required"))
 	}
 	if c.AccessGroups == nil {
-		return fmt.Errorf("config validation failed: accessGroups is required")
+		issues.add(IssueConfig, "", fmt.Errorf("config validation failed: accessGroups is required"))
 	}
 	if c.Entities == nil {
-		return fmt.Errorf("config validation failed: entities is required")
+		issues.add(IssueConfig, "", fmt.Errorf("config validation failed: entities is required"))
 	}
 	if c.Functions == nil {
-		return fmt.Errorf("config validation failed: functions is required")
+		issues.add(IssueConfig, "", fmt.Errorf("config validation failed: functions is required"))
 	}
+	issues.add(IssueConfig, "", validatePublicConfig(c.PublicConfig))
 
-	// Validate access groups
-	for name, group := range c.AccessGroups {
-		if group.Description == "" {
-			return fmt.Errorf("access group '%s': description is required", name)
-		}
+	for _, name := range sortedKeys(c.AccessGroups) {
+		issues.add(IssueAccessGroup, name, validateAccessGroup(name, c.AccessGroups[name]))
+	}
+	for _, name := range sortedKeys(c.Entities) {
+		issues.add(IssueEntity, name, c.validateEntity(name, c.Entities[name]))
 	}
 
-	// Validate entities
-	for name, entity := range c.Entities {
-		if entity.Description == "" {
-			return fmt.Errorf("entity '%s': description is required", name)
-		}
+	// Link schema references to Config.Schemas
+	if err := c.resolveRefs(); err != nil {
+		issues.add(IssueSchema, "", err)
+		return issues
 	}
 
-	// Validate functions and semantic rules
-	if err := c.validateSemantics(); err != nil {
-		return err
+	named := c.NamedSchemas()
+	for _, name := range sortedKeys(named) {
+		issues.add(IssueSchema, name, validateNamedSchema(name, named[name]))
+	}
+	for _, name := range sortedKeys(c.Functions) {
+		issues.add(IssueFunction, name, c.validateFunction(name, c.Functions[name]))
 	}
+	for _, name := range sortedKeys(c.Events) {
+		issues.add(IssueEvent, name, c.validateEvent(name, c.Events[name]))
+	}
+	for _, err := range c.nameConflicts() {
+		issues.add(IssueNames, "", err)
+	}
+
+	c.collectWarnings(&issues)
+	return issues
+}
 
+// validateAccessGroup checks an access group's description.
+func validateAccessGroup(name string, group AccessGroup) error {
+	if group.Description == "" {
+		return fmt.Errorf("access group '%s': description is required", name)
+	}
+	if err := validateTranslations(group.Descriptions); err != nil {
+		return fmt.Errorf("access group '%s': %w", name, err)
+	}
+	return nil
+}
+
+// validateEntity checks an entity's description, schema and relations.
+func (c *Config) validateEntity(name string, entity Entity) error {
+	if entity.Description == "" {
+		return fmt.Errorf("entity '%s': description is required", name)
+	}
+	if err := validateTranslations(entity.Descriptions); err != nil {
+		return fmt.Errorf("entity '%s': %w", name, err)
+	}
+	if err := c.validateEntitySchema(name, entity); err != nil {
+		return fmt.Errorf("entity '%s': %w", name, err)
+	}
+	if err := c.validateRelations(entity); err != nil {
+		return fmt.Errorf("entity '%s': %w", name, err)
+	}
 	return nil
 }
 
-// validateSemantics checks semantic rules that can't be expressed in struct tags.
-func (c *Config) validateSemantics() error {
-	// Validate each function
-	for name, fn := range c.Functions {
-		// Check required fields
-		if fn.Description == "" {
-			return fmt.Errorf("function '%s': description is required", name)
+// validateEntitySchema checks an entity's schema and key. The schema's
+// references are resolved later, with the other named schemas.
+func (c *Config) validateEntitySchema(name string, entity Entity) error {
+	if entity.Schema == nil {
+		if entity.Key != "" {
+			return fmt.Errorf("key '%s' requires a schema", entity.Key)
 		}
-		if len(fn.Access) == 0 {
-			return fmt.Errorf("function '%s': at least one access group is required", name)
+		return nil
+	}
+	if _, ok := c.Schemas[name]; ok {
+		return fmt.Errorf("schema conflicts with the named schema of the same name")
+	}
+	if entity.Key == "" {
+		return nil
+	}
+	obj, ok := entity.Schema.(*ObjectSchema)
+	if !ok {
+		return fmt.Errorf("key '%s' requires an object schema", entity.Key)
+	}
+	if _, ok := obj.properties[entity.Key]; !ok || !contains(obj.required, entity.Key) {
+		return fmt.Errorf("key '%s' is not a required property", entity.Key)
+	}
+	return nil
+}
+
+// validateNamedSchema checks the formats, examples and expressions of a
+// named schema.
+func validateNamedSchema(name string, schema Schema) error {
+	if err := checkFormats(schema); err != nil {
+		return fmt.Errorf("schema '%s': %w", name, err)
+	}
+	if err := checkExamples(schema); err != nil {
+		return fmt.Errorf("schema '%s': %w", name, err)
+	}
+	if err := checkSchemaExprs(schema); err != nil {
+		return fmt.Errorf("schema '%s': %w", name, err)
+	}
+	return nil
+}
+
+// validateFunction checks semantic rules of a function that can't be
+// expressed in struct tags.
+func (c *Config) validateFunction(name string, fn Function) error {
+	// Check required fields
+	if fn.Description == "" {
+		return fmt.Errorf("function '%s': description is required", name)
+	}
+	if err := validateTranslations(fn.Descriptions); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if len(fn.Access) == 0 {
+		return fmt.Errorf("function '%s': at least one access group is required", name)
+	}
+
+	// Check that all access groups referenced exist
+	for _, accessGroup := range fn.Access {
+		if _, exists := c.AccessGroups[accessGroup]; !exists {
+			return fmt.Errorf("function '%s' references unknown access group '%s'", name, accessGroup)
 		}
+	}
 
-		// Check that all access groups referenced exist
-		for _, accessGroup := range fn.Access {
-			if _, exists := c.AccessGroups[accessGroup]; !exists {
-				return fmt.Errorf("function '%s' references unknown access group '%s'", name, accessGroup)
-			}
+	// Check that all entities referenced exist
+	for _, entity := range fn.Entities {
+		if _, exists := c.Entities[entity]; !exists {
+			return fmt.Errorf("function '%s' references unknown entity '%s'", name, entity)
 		}
+	}
 
-		// Check that all entities referenced exist
-		for _, entity := range fn.Entities {
-			if _, exists := c.Entities[entity]; !exists {
-				return fmt.Errorf("function '%s' references unknown entity '%s'", name, entity)
-			}
+	// Validate that inputs and outputs are valid schemas
+	if fn.Inputs == nil {
+		return fmt.Errorf("function '%s' has nil inputs schema", name)
+	}
+	if fn.Outputs == nil {
+		return fmt.Errorf("function '%s' has nil outputs schema", name)
+	}
+
+	if err := c.validateCalls(name, fn); err != nil {
+		return err
+	}
+	if fn.Pipeline != nil {
+		if err := c.validatePipeline(name, fn); err != nil {
+			return err
 		}
+	}
 
-		// Validate that inputs and outputs are valid schemas
-		if fn.Inputs == nil {
-			return fmt.Errorf("function '%s' has nil inputs schema", name)
+	if err := validateParams(fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if err := checkFormats(fn.CallInputs()); err != nil {
+		return fmt.Errorf("function '%s' inputs: %w", name, err)
+	}
+	if err := checkFormats(fn.Outputs); err != nil {
+		return fmt.Errorf("function '%s' outputs: %w", name, err)
+	}
+	if err := checkExamples(fn.CallInputs()); err != nil {
+		return fmt.Errorf("function '%s' inputs: %w", name, err)
+	}
+	if err := checkExamples(fn.Outputs); err != nil {
+		return fmt.Errorf("function '%s' outputs: %w", name, err)
+	}
+	if err := checkSchemaExprs(fn.Inputs); err != nil {
+		return fmt.Errorf("function '%s' inputs: %w", name, err)
+	}
+	if err := checkSchemaExprs(fn.Outputs); err != nil {
+		return fmt.Errorf("function '%s' outputs: %w", name, err)
+	}
+	if err := validateAccessRule(fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if err := c.validateTags(fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if fn.Canary != nil {
+		if err := validateCanary(fn); err != nil {
+			return fmt.Errorf("function '%s': %w", name, err)
 		}
-		if fn.Outputs == nil {
-			return fmt.Errorf("function '%s' has nil outputs schema", name)
+	}
+	if err := validateOutputVersions(fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if fn.Fallback != nil {
+		if err := validateFallback(fn); err != nil {
+			return fmt.Errorf("function '%s': %w", name, err)
+		}
+	}
+	if fn.Timeout < 0 {
+		return fmt.Errorf("function '%s': timeout must not be negative", name)
+	}
+	if err := c.validateAliases(name, fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if err := c.validateFunctionVersion(name, fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if err := c.validateFieldReferences(fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if err := validateExamples(fn); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if fn.RateLimit != nil {
+		if err := c.validateRateLimit(fn.RateLimit); err != nil {
+			return fmt.Errorf("function '%s': %w", name, err)
+		}
+	}
+	if fn.Internal && fn.IncludeInMcpListTools {
+		return fmt.Errorf("function '%s': internal functions can't be included in MCP tools", name)
+	}
+	if err := validateStability(fn.Stability); err != nil {
+		return fmt.Errorf("function '%s': %w", name, err)
+	}
+	if fn.Runbook != "" {
+		if u, err := url.Parse(fn.Runbook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("function '%s': runbook must be an http(s) URL", name)
 		}
 	}
 
+	if fn.Watchable {
+		if err := fn.validateWatchable(); err != nil {
+			return fmt.Errorf("function '%s': %w", name, err)
+		}
+	}
 	return nil
 }
 
-// ValidateInput validates input data against a function's input schema.
+// ValidateInput validates input data against a function's input schema,
+// including its Headers and Query (see CallInputs), reporting every
+// violation (see ValidateAll).
 func (f *Function) ValidateInput(input any) error {
-	if err := f.Inputs.Validate(input); err != nil {
+	// Most inputs are valid; only collect every issue when one isn't
+	if f.validators != nil && f.validators.input(input) == nil {
+		return nil
+	}
+	if err := ValidateAll(f.CallInputs(), input); err != nil {
 		return fmt.Errorf("input validation failed: %w", err)
 	}
 	return nil
@@ -93,12 +281,45 @@ func (f *Function) ValidateInput(input any) error {
 // ValidateOutput validates output data against a function's output schema.
 // This also checks for nil slices which would serialize to JSON null.
 func (f *Function) ValidateOutput(output any) error {
-	if err := f.Outputs.Validate(output); err != nil {
+	validate := f.Outputs.Validate
+	if f.validators != nil {
+		validate = f.validators.output
+	}
+	if err := validate(output); err != nil {
 		return fmt.Errorf("output validation failed: %w", err)
 	}
 	return nil
 }
 
+// ApplyInputTransform runs the transforms declared on the input schema (see
+// ApplyTransforms), then the function's TransformInput hook, if any.
+func (f *Function) ApplyInputTransform(ctx Context, input any) (any, error) {
+	input, err := ApplyTransforms(f.Inputs, input)
+	if err != nil {
+		return nil, fmt.Errorf("input transform failed: %w", err)
+	}
+	if f.TransformInput == nil {
+		return input, nil
+	}
+	transformed, err := f.TransformInput(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("input transform failed: %w", err)
+	}
+	return transformed, nil
+}
+
+// ApplyOutputTransform runs the function's TransformOutput hook, if any.
+func (f *Function) ApplyOutputTransform(ctx Context, output any) (any, error) {
+	if f.TransformOutput == nil {
+		return output, nil
+	}
+	transformed, err := f.TransformOutput(ctx, output)
+	if err != nil {
+		return nil, fmt.Errorf("output transform failed: %w", err)
+	}
+	return transformed, nil
+}
+
 // InitializeNilSlices ensures all nil slices in a struct become empty slices.
 // This prevents Go's nil -> JSON null -> TypeScript runtime errors.
 // It modifies the struct in place if possible.
@@ -167,10 +388,12 @@ func (f *Function) CheckAccess(userAccessGroups []string) bool {
 	return false
 }
 
-// ValidationError represents a validation error with context.
+// ValidationError represents a validation error with context. Field is
+// the JSON pointer of the value at fault (e.g. "/users/3/email"), or empty
+// for the value itself.
 type ValidationError struct {
-	Field   string
-	Message string
+	Field   string `json:"path"`
+	Message string `json:"message"`
 }
 
 func (e *ValidationError) Error() string {
@@ -1,6 +1,8 @@
 package ontology
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -323,3 +325,77 @@ func TestInitializeNilSlicesNested(t *testing.T) {
 		t.Error("Nested Values should not be nil after initialization")
 	}
 }
+
+func TestApplyTransforms(t *testing.T) {
+	fn := Function{
+		TransformInput: func(ctx Context, value any) (any, error) {
+			m := value.(map[string]any)
+			m["name"] = strings.TrimSpace(m["name"].(string))
+			return m, nil
+		},
+		TransformOutput: func(ctx Context, value any) (any, error) {
+			return map[string]any{"wrapped": value}, nil
+		},
+	}
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+
+	input, err := fn.ApplyInputTransform(ctx, map[string]any{"name": "  Ada  "})
+	if err != nil {
+		t.Fatalf("ApplyInputTransform() error = %v", err)
+	}
+	if got := input.(map[string]any)["name"]; got != "Ada" {
+		t.Errorf("transformed name = %q, want %q", got, "Ada")
+	}
+
+	output, err := fn.ApplyOutputTransform(ctx, "result")
+	if err != nil {
+		t.Fatalf("ApplyOutputTransform() error = %v", err)
+	}
+	if got := output.(map[string]any)["wrapped"]; got != "result" {
+		t.Errorf("transformed output = %v, want wrapped result", output)
+	}
+}
+
+func TestApplyTransformsNilAndErrors(t *testing.T) {
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+
+	// No hooks: values pass through unchanged
+	fn := Function{}
+	if got, err := fn.ApplyInputTransform(ctx, "in"); err != nil || got != "in" {
+		t.Errorf("ApplyInputTransform() = %v, %v; want passthrough", got, err)
+	}
+	if got, err := fn.ApplyOutputTransform(ctx, "out"); err != nil || got != "out" {
+		t.Errorf("ApplyOutputTransform() = %v, %v; want passthrough", got, err)
+	}
+
+	// Hook errors are wrapped
+	fn.TransformInput = func(ctx Context, value any) (any, error) {
+		return nil, errors.New("bad phone number")
+	}
+	_, err := fn.ApplyInputTransform(ctx, "in")
+	if err == nil || !strings.Contains(err.Error(), "bad phone number") {
+		t.Errorf("ApplyInputTransform() error = %v, want wrapped hook error", err)
+	}
+}
+
+func TestContextWarnings(t *testing.T) {
+	ctx := NewContext(nil, DefaultLogger(), nil, nil)
+
+	if len(ctx.Warnings()) != 0 {
+		t.Error("new context should have no warnings")
+	}
+
+	ctx.Warn("parameter 'limit' is deprecated")
+	ctx.Warn("using cached data")
+
+	warnings := ctx.Warnings()
+	if len(warnings) != 2 || warnings[0] != "parameter 'limit' is deprecated" {
+		t.Errorf("Warnings() = %v, want both warnings in order", warnings)
+	}
+
+	// Returned slice is a copy
+	warnings[0] = "changed"
+	if ctx.Warnings()[0] == "changed" {
+		t.Error("Warnings() should return a copy")
+	}
+}
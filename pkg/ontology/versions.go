@@ -0,0 +1,93 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputVersion is an older shape of a function's output, kept for callers
+// that can't upgrade yet. REST callers ask for it with the profile
+// parameter of the Accept header, e.g. `Accept: application/json;
+// profile="v1"`.
+type OutputVersion struct {
+	// Outputs is the schema of this version.
+	Outputs Schema `json:"outputs"`
+	// Downgrade converts the current output, in its JSON form
+	// (map[string]any, []any, ...), to this version's shape.
+	Downgrade ValueTransformFunc `json:"-"`
+}
+
+// DowngradeOutput converts output from the current Outputs to the named
+// version and validates the result against the version's schema. A
+// version equal to OutputProfile returns output unchanged.
+func (f *Function) DowngradeOutput(version string, output any) (any, error) {
+	if version == f.OutputProfile {
+		return output, nil
+	}
+	v, ok := f.OutputVersions[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown output version '%s'", version)
+	}
+	downgraded, err := v.Downgrade(toJSONValue(output))
+	if err != nil {
+		return nil, fmt.Errorf("output version '%s': %w", version, err)
+	}
+	if err := v.Outputs.Validate(downgraded); err != nil {
+		return nil, fmt.Errorf("output version '%s' validation failed: %w", version, err)
+	}
+	return downgraded, nil
+}
+
+// HasOutputVersion reports whether the function serves the named output
+// version.
+func (f *Function) HasOutputVersion(version string) bool {
+	_, ok := f.OutputVersions[version]
+	return ok || (version != "" && version == f.OutputProfile)
+}
+
+// OutputSchemaFor returns the output schema of the named version, or
+// Outputs for OutputProfile and unknown versions.
+func (f *Function) OutputSchemaFor(version string) Schema {
+	if v, ok := f.OutputVersions[version]; ok {
+		return v.Outputs
+	}
+	return f.Outputs
+}
+
+// validateOutputVersions checks a function's output versions.
+func validateOutputVersions(fn Function) error {
+	names := sortedKeys(fn.OutputVersions)
+	if fn.OutputProfile != "" {
+		names = append(names, fn.OutputProfile)
+	}
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, " \t\",;=") {
+			return fmt.Errorf("invalid output version '%s'", name)
+		}
+	}
+	if _, ok := fn.OutputVersions[fn.OutputProfile]; ok {
+		return fmt.Errorf("output version '%s' is the current output profile", fn.OutputProfile)
+	}
+	for _, name := range sortedKeys(fn.OutputVersions) {
+		v := fn.OutputVersions[name]
+		if v.Outputs == nil {
+			return fmt.Errorf("output version '%s': outputs are required", name)
+		}
+		if v.Downgrade == nil {
+			return fmt.Errorf("output version '%s': downgrade is required", name)
+		}
+	}
+	return nil
+}
+
+// outputVersionSchemas returns the JSON Schema of each output version.
+func (c *Config) outputVersionSchemas(fn Function) map[string]any {
+	if len(fn.OutputVersions) == 0 {
+		return nil
+	}
+	schemas := make(map[string]any, len(fn.OutputVersions))
+	for name, v := range fn.OutputVersions {
+		schemas[name] = c.JSONSchemaFor(v.Outputs)
+	}
+	return schemas
+}
@@ -0,0 +1,118 @@
+package ontology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func versionedFunction() Function {
+	return Function{
+		Description: "Get a user",
+		Access:      []string{"admin"},
+		Inputs:      Object(map[string]Schema{}),
+		Outputs:     Object(map[string]Schema{"name": String()}),
+		Resolver: func(ctx Context, input any) (any, error) {
+			return map[string]any{"name": "Ada"}, nil
+		},
+		OutputProfile: "v2",
+		OutputVersions: map[string]OutputVersion{
+			"v1": {
+				Outputs: Object(map[string]Schema{"fullName": String()}),
+				Downgrade: func(v any) (any, error) {
+					return map[string]any{"fullName": v.(map[string]any)["name"]}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestDowngradeOutput(t *testing.T) {
+	fn := versionedFunction()
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	got, err := fn.DowngradeOutput("v1", user{Name: "Ada"})
+	if err != nil {
+		t.Fatalf("DowngradeOutput failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]any{"fullName": "Ada"}) {
+		t.Errorf("DowngradeOutput = %v", got)
+	}
+	if got, _ := fn.DowngradeOutput("v2", user{Name: "Ada"}); got != (user{Name: "Ada"}) {
+		t.Errorf("current profile should be unchanged, got %v", got)
+	}
+	if _, err := fn.DowngradeOutput("v0", nil); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+	if !fn.HasOutputVersion("v1") || !fn.HasOutputVersion("v2") || fn.HasOutputVersion("") {
+		t.Error("HasOutputVersion is wrong")
+	}
+
+	// The downgraded output must match the version's schema
+	fn.OutputVersions["v1"] = OutputVersion{
+		Outputs:   fn.OutputVersions["v1"].Outputs,
+		Downgrade: func(v any) (any, error) { return v, nil },
+	}
+	if _, err := fn.DowngradeOutput("v1", map[string]any{}); err == nil || !strings.Contains(err.Error(), "output version 'v1' validation failed") {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
+
+func TestOutputVersionsLock(t *testing.T) {
+	config := &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+		Entities:     map[string]Entity{},
+		Functions:    map[string]Function{"getUser": versionedFunction()},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	shape := config.ExtractSnapshot().Functions["getUser"]
+	if shape.OutputProfile != "v2" || shape.OutputVersions["v1"] == nil {
+		t.Errorf("snapshot = %+v", shape)
+	}
+
+	before := config.Hash()
+	fn := config.Functions["getUser"]
+	fn.OutputVersions = nil
+	config.Functions["getUser"] = fn
+	if config.Hash() == before {
+		t.Error("hash should change when an output version is dropped")
+	}
+}
+
+func TestValidateOutputVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Function)
+		wantErr string
+	}{
+		{"invalid name", func(fn *Function) { fn.OutputProfile = "v 2" }, "invalid output version 'v 2'"},
+		{"profile conflict", func(fn *Function) { fn.OutputProfile = "v1" }, "is the current output profile"},
+		{"no downgrade", func(fn *Function) {
+			fn.OutputVersions["v1"] = OutputVersion{Outputs: Object(map[string]Schema{})}
+		}, "downgrade is required"},
+		{"no outputs", func(fn *Function) {
+			fn.OutputVersions["v1"] = OutputVersion{Downgrade: func(v any) (any, error) { return v, nil }}
+		}, "outputs are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := versionedFunction()
+			tt.mutate(&fn)
+			config := &Config{
+				Name:         "test",
+				AccessGroups: map[string]AccessGroup{"admin": {Description: "Admins"}},
+				Entities:     map[string]Entity{},
+				Functions:    map[string]Function{"getUser": fn},
+			}
+			err := config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package ontology
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field names used by watchable functions.
+const (
+	// WatchSinceField is the optional input holding the last cursor the caller saw.
+	WatchSinceField = "since"
+	// WatchCursorField is the required output identifying the current state.
+	WatchCursorField = "cursor"
+)
+
+// validateWatchable checks that a watchable function follows the change feed
+// convention: an optional string "since" input and a required string "cursor" output.
+func (f *Function) validateWatchable() error {
+	inputs, ok := Deref(f.Inputs).(*ObjectSchema)
+	if !ok {
+		return fmt.Errorf("watchable functions must have object inputs")
+	}
+	since, ok := inputs.properties[WatchSinceField]
+	if !ok {
+		return fmt.Errorf("watchable functions must accept a '%s' input", WatchSinceField)
+	}
+	if _, ok := since.(*StringSchema); !ok {
+		return fmt.Errorf("'%s' input must be a string", WatchSinceField)
+	}
+	if contains(inputs.required, WatchSinceField) {
+		return fmt.Errorf("'%s' input must be optional", WatchSinceField)
+	}
+
+	outputs, ok := Deref(f.Outputs).(*ObjectSchema)
+	if !ok {
+		return fmt.Errorf("watchable functions must have object outputs")
+	}
+	cursor, ok := outputs.properties[WatchCursorField]
+	if !ok {
+		return fmt.Errorf("watchable functions must return a '%s' output", WatchCursorField)
+	}
+	if _, ok := cursor.(*StringSchema); !ok {
+		return fmt.Errorf("'%s' output must be a string", WatchCursorField)
+	}
+	if !contains(outputs.required, WatchCursorField) {
+		return fmt.Errorf("'%s' output must be required", WatchCursorField)
+	}
+	return nil
+}
+
+// WatchCursor returns the cursor of a watchable function's output.
+func WatchCursor(output any) (string, bool) {
+	value, err := objectField(output, WatchCursorField)
+	if err != nil {
+		return "", false
+	}
+	cursor, ok := value.(string)
+	return cursor, ok
+}
+
+// ParseETag extracts the cursor from an ETag or If-None-Match header value.
+// Weak validators are accepted; the wildcard "*" and lists are not.
+func ParseETag(header string) string {
+	tag := strings.TrimSpace(header)
+	tag = strings.TrimPrefix(tag, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return ""
+	}
+	return tag[1 : len(tag)-1]
+}
+
+// FormatETag formats a cursor as a strong ETag header value.
+func FormatETag(cursor string) string {
+	return `"` + cursor + `"`
+}
@@ -0,0 +1,117 @@
+package ontology
+
+import (
+	"strings"
+	"testing"
+)
+
+func watchConfig(fn Function) *Config {
+	fn.Description = "List changes"
+	fn.Access = []string{"public"}
+	fn.Watchable = true
+	return &Config{
+		Name:         "test",
+		AccessGroups: map[string]AccessGroup{"public": {Description: "Public"}},
+		Entities:     map[string]Entity{},
+		Functions:    map[string]Function{"listChanges": fn},
+	}
+}
+
+func TestWatchableValidation(t *testing.T) {
+	valid := Function{
+		Inputs: Object(map[string]Schema{
+			"since": String(),
+		}).Optional("since"),
+		Outputs: Object(map[string]Schema{
+			"cursor": String(),
+			"items":  Array(String()),
+		}),
+	}
+	if err := watchConfig(valid).Validate(); err != nil {
+		t.Errorf("valid watchable function rejected: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		fn      Function
+		wantErr string
+	}{
+		{
+			name: "missing since",
+			fn: Function{
+				Inputs:  Object(map[string]Schema{}),
+				Outputs: Object(map[string]Schema{"cursor": String()}),
+			},
+			wantErr: "must accept a 'since' input",
+		},
+		{
+			name: "required since",
+			fn: Function{
+				Inputs:  Object(map[string]Schema{"since": String()}),
+				Outputs: Object(map[string]Schema{"cursor": String()}),
+			},
+			wantErr: "'since' input must be optional",
+		},
+		{
+			name: "missing cursor",
+			fn: Function{
+				Inputs:  Object(map[string]Schema{"since": String()}).Optional("since"),
+				Outputs: Object(map[string]Schema{"items": Array(String())}),
+			},
+			wantErr: "must return a 'cursor' output",
+		},
+		{
+			name: "numeric cursor",
+			fn: Function{
+				Inputs:  Object(map[string]Schema{"since": String()}).Optional("since"),
+				Outputs: Object(map[string]Schema{"cursor": Integer()}),
+			},
+			wantErr: "'cursor' output must be a string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := watchConfig(tt.fn).Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatchCursor(t *testing.T) {
+	if cursor, ok := WatchCursor(map[string]any{"cursor": "42"}); !ok || cursor != "42" {
+		t.Errorf("WatchCursor(map) = %q, %v", cursor, ok)
+	}
+
+	type feed struct {
+		Cursor string `json:"cursor"`
+	}
+	if cursor, ok := WatchCursor(feed{Cursor: "7"}); !ok || cursor != "7" {
+		t.Errorf("WatchCursor(struct) = %q, %v", cursor, ok)
+	}
+
+	if _, ok := WatchCursor(map[string]any{"items": []any{}}); ok {
+		t.Error("WatchCursor should fail without a cursor")
+	}
+}
+
+func TestParseETag(t *testing.T) {
+	tests := map[string]string{
+		`"abc"`:   "abc",
+		`W/"abc"`: "abc",
+		` "a b" `: "a b",
+		`abc`:     "",
+		`*`:       "",
+		``:        "",
+	}
+	for header, want := range tests {
+		if got := ParseETag(header); got != want {
+			t.Errorf("ParseETag(%q) = %q, want %q", header, got, want)
+		}
+	}
+	if got := ParseETag(FormatETag("v1")); got != "v1" {
+		t.Errorf("round trip = %q", got)
+	}
+}
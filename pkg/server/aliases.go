@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// aliasKey stores the alias a function was called by in the request context.
+const aliasKey contextKey = "alias"
+
+// handleAlias serves a function under one of its former names. Responses
+// carry a Deprecation header and a Link to the new route, and resolvers'
+// warnings include a deprecation notice.
+func (s *Server) handleAlias(alias, name string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", fmt.Sprintf("</api/%s>; rel=\"successor-version\"", name))
+		handler(w, r.WithContext(context.WithValue(r.Context(), aliasKey, alias)))
+	}
+}
+
+// warnDeprecated adds a deprecation warning to rc if the call used an
+// alias, the function is deprecated or the input sets deprecated fields.
+func warnDeprecated(ctx context.Context, rc ont.Context, name string, fn ont.Function, input any) {
+	if alias, ok := ctx.Value(aliasKey).(string); ok {
+		rc.Warn(ont.AliasWarning(alias, name))
+	}
+	if fn.Deprecated != "" {
+		rc.Warn(ont.DeprecationWarning(name, fn.Deprecated))
+	}
+	for _, warning := range ont.DeprecatedFields(fn.Inputs, input) {
+		rc.Warn(warning)
+	}
+}
+
+// handleDeprecated marks the responses of a deprecated function with a
+// Deprecation header.
+func handleDeprecated(fn ont.Function, handler http.HandlerFunc) http.HandlerFunc {
+	if fn.Deprecated == "" {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		handler(w, r)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// approvalCheck is the configuration of WithRequireApprovedLock.
+type approvalCheck struct {
+	lockPath      string
+	approvalsPath string
+	approvers     map[string]ed25519.PublicKey
+}
+
+// WithRequireApprovedLock refuses to serve a config unless it matches the
+// lock file at lockPath and one of approvers signed it in the approvals
+// file at approvalsPath (see ont.VerifyApproved). This requires review of
+// every change without ont-run.com. The check is the first warm-up phase:
+// calls answer 503 until it passes (see WarmUp), Serve stops with its
+// error, and Reload returns it and keeps serving the current config.
+func WithRequireApprovedLock(lockPath, approvalsPath string, approvers map[string]ed25519.PublicKey) ServerOption {
+	return func(s *Server) {
+		s.approval = &approvalCheck{lockPath: lockPath, approvalsPath: approvalsPath, approvers: approvers}
+		phase := warmUpPhase{"approved lock", func(ctx context.Context) error {
+			return s.verifyApproved(s.currentConfig())
+		}}
+		s.warmUp.phases = append([]warmUpPhase{phase}, s.warmUp.phases...)
+	}
+}
+
+// verifyApproved checks config's approval, if WithRequireApprovedLock is set.
+func (s *Server) verifyApproved(config *ont.Config) error {
+	if s.approval == nil {
+		return nil
+	}
+	if err := config.VerifyApproved(s.approval.lockPath, s.approval.approvalsPath, s.approval.approvers); err != nil {
+		return fmt.Errorf("unapproved config: %w", err)
+	}
+	return nil
+}
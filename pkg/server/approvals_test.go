@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestUnapprovedConfigIsNotServed(t *testing.T) {
+	alice, _, _ := ed25519.GenerateKey(nil)
+	srv := New(testConfig(), WithRequireApprovedLock("/nonexistent/ont.lock", "/nonexistent/approvals.json", map[string]ed25519.PublicKey{"alice": alice}))
+	h := srv.Handler()
+
+	// Refused before the check has finished, and after it failed
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("call: status %d, want 503", resp.StatusCode)
+	}
+	if err := srv.WarmUp(context.Background()); err == nil {
+		t.Fatal("expected an unapproved config error")
+	}
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("call: status %d, want 503", resp.StatusCode)
+	}
+	if resp, _ := do(t, h, "POST", "/mcp", `{}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("MCP: status %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestApprovedConfigIsServed(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, "ont.lock")
+	approvalsPath := filepath.Join(dir, "approvals.json")
+	config := testConfig()
+	if err := config.WriteLock(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	alicePub, alice, _ := ed25519.GenerateKey(nil)
+	if err := ont.AddApproval(approvalsPath, ont.SignApproval(config.Hash(), "alice", alice)); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := New(config, WithRequireApprovedLock(lockPath, approvalsPath, map[string]ed25519.PublicKey{"alice": alicePub}))
+	h := srv.Handler()
+	if err := srv.WarmUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("call: status %d: %s", resp.StatusCode, body)
+	}
+}
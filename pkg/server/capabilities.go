@@ -0,0 +1,251 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"runtime/debug"
+	"slices"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// modulePath is this module's path, for finding its version in the build.
+const modulePath = "github.com/vanna-ai/ont-run"
+
+// Features a server may advertise in Capabilities.
+const (
+	// FeatureBatch: some functions return ont.MultiStatus batch results,
+	// answered with 207 when items fail.
+	FeatureBatch = "batch"
+	// FeatureWatch: some functions can be long-polled at /api/{name}/watch.
+	FeatureWatch = "watch"
+	// FeatureSSE: the MCP endpoint streams responses as server-sent events.
+	FeatureSSE = "sse"
+	// FeatureOutputStreaming: JSON array outputs are written as they are
+	// encoded rather than buffered.
+	FeatureOutputStreaming = "outputStreaming"
+	// FeatureOutputVersions: some functions serve older output shapes by
+	// profile (see Function.OutputVersions).
+	FeatureOutputVersions = "outputVersions"
+	// FeatureGet: read-only functions can be called with GET and query
+	// parameters.
+	FeatureGet = "get"
+	// FeatureAliases: some functions are also served under former names.
+	FeatureAliases = "aliases"
+	// FeatureFunctionVersions: some functions are served in several
+	// versions at /api/v{N}/{name} (see Function.Version).
+	FeatureFunctionVersions = "functionVersions"
+)
+
+// Capabilities advertises what a server supports, so generated clients and
+// gateways can detect features instead of assuming them. GET /api and
+// GET /health/info include it.
+type Capabilities struct {
+	// ServerVersion is the version of this module the server was built
+	// with, or "devel".
+	ServerVersion string `json:"serverVersion"`
+	// OntologyVersion is Config.Version.
+	OntologyVersion string `json:"ontologyVersion,omitempty"`
+	// SchemaDialect is the JSON Schema dialect of published schemas.
+	SchemaDialect string `json:"schemaDialect"`
+	// Formats are the request and response media types, JSON first.
+	Formats     []string        `json:"formats"`
+	FieldNaming ont.FieldNaming `json:"fieldNaming"`
+	Envelope    bool            `json:"envelope"`
+	// AuthSchemes are the schemes set with WithAuthSchemes, e.g. "bearer".
+	AuthSchemes []string `json:"authSchemes,omitempty"`
+	// Features lists the Feature constants that apply, sorted.
+	Features []string `json:"features"`
+	// Extensions are custom capabilities set with WithCapability.
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// WithAuthSchemes advertises how callers authenticate, e.g. "bearer" or
+// "apiKey", in Capabilities. It doesn't change authentication; see WithAuth.
+func WithAuthSchemes(schemes ...string) ServerOption {
+	return func(s *Server) {
+		s.authSchemes = append(s.authSchemes, schemes...)
+	}
+}
+
+// WithCapability advertises a custom capability under Capabilities'
+// extensions, e.g. a gateway feature the server relies on.
+func WithCapability(name string, value any) ServerOption {
+	return func(s *Server) {
+		if s.extensions == nil {
+			s.extensions = make(map[string]any)
+		}
+		s.extensions[name] = value
+	}
+}
+
+// Capabilities returns the capabilities of the config being served.
+func (s *Server) Capabilities() Capabilities {
+	return s.liveServer().capabilities()
+}
+
+func (s *Server) capabilities() Capabilities {
+	caps := Capabilities{
+		ServerVersion:   serverVersion(),
+		OntologyVersion: s.config.Version,
+		SchemaDialect:   "https://json-schema.org/draft/2020-12/schema",
+		Formats:         []string{"application/json"},
+		FieldNaming:     s.fieldNaming,
+		Envelope:        s.envelope,
+		AuthSchemes:     s.authSchemes,
+		Extensions:      s.extensions,
+	}
+	if caps.FieldNaming == "" {
+		caps.FieldNaming = ont.CamelCase
+	}
+	for _, c := range s.codecs {
+		caps.Formats = append(caps.Formats, c.ContentType())
+	}
+
+	features := map[string]bool{FeatureSSE: true, FeatureOutputStreaming: s.writesPlainJSON()}
+	for name, fn := range s.config.Functions {
+		if obj, ok := ont.Deref(fn.Outputs).(*ont.ObjectSchema); ok && obj.BatchItem() != nil {
+			features[FeatureBatch] = true
+		}
+		features[FeatureWatch] = features[FeatureWatch] || fn.Watchable
+		features[FeatureGet] = features[FeatureGet] || fn.IsReadOnly
+		features[FeatureOutputVersions] = features[FeatureOutputVersions] || len(fn.OutputVersions) > 0
+		features[FeatureAliases] = features[FeatureAliases] || len(fn.Aliases) > 0
+		features[FeatureFunctionVersions] = features[FeatureFunctionVersions] || ont.FunctionVersion(name, fn) > 0
+	}
+	caps.Features = []string{}
+	for _, feature := range []string{FeatureAliases, FeatureBatch, FeatureFunctionVersions, FeatureGet, FeatureOutputStreaming, FeatureOutputVersions, FeatureSSE, FeatureWatch} {
+		if features[feature] {
+			caps.Features = append(caps.Features, feature)
+		}
+	}
+	return caps
+}
+
+// serverVersion returns the version of this module in the running binary.
+func serverVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return dep.Version
+		}
+	}
+	return "devel"
+}
+
+// introspectedFunction describes a function the caller may call in the
+// GET /api response.
+type introspectedFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Version is the function's version (see ont.FunctionVersion), or 0.
+	Version int `json:"version,omitempty"`
+	// Paths are the REST routes the function is served at.
+	Paths      []string `json:"paths"`
+	IsReadOnly bool     `json:"isReadOnly"`
+	Stability  string   `json:"stability,omitempty"`
+	Deprecated string   `json:"deprecated,omitempty"`
+	// InputSchema and OutputSchema are JSON Schemas of the request and
+	// response bodies, with field names as on the wire.
+	InputSchema  map[string]any `json:"inputSchema"`
+	OutputSchema map[string]any `json:"outputSchema"`
+}
+
+// handleIntrospection serves GET /api: the ontology's name, the server's
+// capabilities and the non-internal functions the caller may call, with
+// their versions and schemas.
+func (s *Server) handleIntrospection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authResult, err := s.authFunc(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	lang := r.Header.Get("Accept-Language")
+	functions := []introspectedFunction{}
+	for _, name := range slices.Sorted(maps.Keys(s.config.Functions)) {
+		fn := s.config.Functions[name]
+		if fn.Internal || !fn.CheckAccess(authResult.AccessGroups) || !s.checkStability(r, fn, authResult.AccessGroups) {
+			continue
+		}
+		paths := []string{"/api/" + name}
+		if route, ok := ont.VersionedRoute(name, fn); ok {
+			paths = append(paths, "/api/"+route)
+		}
+		outputSchema := wireJSONSchema(s.config.JSONSchemaFor(fn.Outputs), s.fieldNaming)
+		if s.envelope {
+			outputSchema = envelopeJSONSchema(outputSchema)
+		}
+		inputSchema := wireJSONSchema(s.config.JSONSchemaFor(fn.CallInputs()), s.fieldNaming)
+		if s.strictInputs {
+			inputSchema = strictJSONSchema(inputSchema)
+		}
+		functions = append(functions, introspectedFunction{
+			Name:         name,
+			Description:  ont.Localize(fn.Description, fn.Descriptions, lang),
+			Version:      ont.FunctionVersion(name, fn),
+			Paths:        paths,
+			IsReadOnly:   fn.IsReadOnly,
+			Stability:    string(fn.Stability),
+			Deprecated:   fn.Deprecated,
+			InputSchema:  inputSchema,
+			OutputSchema: outputSchema,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(map[string]any{
+		"name":         s.config.Name,
+		"capabilities": s.capabilities(),
+		"functions":    functions,
+	})
+}
+
+// wireJSONSchema returns a copy of a JSON Schema with property names
+// converted to wire names.
+func wireJSONSchema(schema map[string]any, naming ont.FieldNaming) map[string]any {
+	if naming.IsIdentity() {
+		return schema
+	}
+	return rewriteJSONSchema(schema, func(sub map[string]any) {
+		if properties, ok := sub["properties"].(map[string]any); ok {
+			renamed := make(map[string]any, len(properties))
+			for name, property := range properties {
+				renamed[naming.Apply(name)] = property
+			}
+			sub["properties"] = renamed
+		}
+		switch required := sub["required"].(type) {
+		case []string:
+			renamed := make([]string, len(required))
+			for i, name := range required {
+				renamed[i] = naming.Apply(name)
+			}
+			sub["required"] = renamed
+		case []any:
+			renamed := make([]any, len(required))
+			for i, name := range required {
+				if name, ok := name.(string); ok {
+					renamed[i] = naming.Apply(name)
+				} else {
+					renamed[i] = name
+				}
+			}
+			sub["required"] = renamed
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/codec"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/store/localfs"
+)
+
+func TestIntrospectionListsCallableFunctions(t *testing.T) {
+	config := testConfig()
+	config.Functions["deleteItem"] = ont.Function{
+		Description: "Delete an item",
+		Access:      []string{"admin"},
+		Inputs:      ont.Object(map[string]ont.Schema{"itemId": ont.String()}),
+		Outputs:     ont.Object(map[string]ont.Schema{}),
+		Resolver:    func(ctx ont.Context, input any) (any, error) { return map[string]any{}, nil },
+	}
+	fn := config.Functions["getItem"]
+	fn.Version = 2
+	config.Functions["getItem"] = fn
+
+	for _, tc := range []struct {
+		groups []string
+		want   []string
+	}{
+		{[]string{"public"}, []string{"echo", "getItem"}},
+		{[]string{"admin"}, []string{"deleteItem", "echo", "getItem"}},
+	} {
+		h := New(config, groups(tc.groups...), WithFieldNaming(ont.SnakeCase)).Handler()
+		resp, body := do(t, h, "GET", "/api", "")
+		if resp.StatusCode != 200 {
+			t.Fatalf("status %d: %s", resp.StatusCode, body)
+		}
+		var result struct {
+			Name      string                 `json:"name"`
+			Functions []introspectedFunction `json:"functions"`
+		}
+		if err := json.Unmarshal([]byte(body), &result); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, fn := range result.Functions {
+			names = append(names, fn.Name)
+			if fn.Name == "getItem" {
+				if fn.Version != 2 || !reflect.DeepEqual(fn.Paths, []string{"/api/getItem", "/api/v2/getItem"}) || !fn.IsReadOnly {
+					t.Errorf("getItem = %+v", fn)
+				}
+				if fn.OutputSchema["properties"] == nil {
+					t.Errorf("getItem has no output schema: %v", fn.OutputSchema)
+				}
+			}
+			if fn.Name == "deleteItem" {
+				properties, _ := fn.InputSchema["properties"].(map[string]any)
+				if _, ok := properties["item_id"]; !ok {
+					t.Errorf("deleteItem input properties = %v, want wire names", properties)
+				}
+			}
+		}
+		if result.Name != "test" || !reflect.DeepEqual(names, tc.want) {
+			t.Errorf("groups %v: name %q, functions %v, want %v", tc.groups, result.Name, names, tc.want)
+		}
+	}
+}
+
+func TestOutputStreamingFeature(t *testing.T) {
+	objects, err := localfs.New(t.TempDir(), "http://localhost/objects", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		opts []ServerOption
+		want bool
+	}{
+		"plain":    {nil, true},
+		"envelope": {[]ServerOption{WithResponseEnvelope()}, false},
+		"codecs":   {[]ServerOption{WithCodecs(codec.MessagePack())}, false},
+		"offload":  {[]ServerOption{WithResultOffload(objects, 1024, time.Minute)}, false},
+	} {
+		features := New(testConfig(), tc.opts...).Capabilities().Features
+		if got := slices.Contains(features, FeatureOutputStreaming); got != tc.want {
+			t.Errorf("%s: features %v, outputStreaming %v, want %v", name, features, got, tc.want)
+		}
+	}
+}
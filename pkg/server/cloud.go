@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vanna-ai/ont-run/pkg/cloud"
+)
+
+// WithCloudDisabled keeps the server from registering with ont-run.com,
+// whatever Config.Cloud and Config.UUID say. It only affects this server;
+// to stop every cloud.Client in the process from sending requests, set
+// ONT_OFFLINE=1 or call cloud.SetOffline.
+func WithCloudDisabled() ServerOption {
+	return func(s *Server) {
+		s.cloudDisabled = true
+	}
+}
+
+// registerWithCloud registers the config with ont-run.com in the
+// background if it enables the cloud and offline mode is off.
+func (s *Server) registerWithCloud() {
+	if s.cloudDisabled || cloud.Offline() || !s.config.Cloud || s.config.UUID == "" {
+		return
+	}
+	cloud.TryRegisterWithCloud(s.config.UUID, s.config)
+}
+
+// handleHealth reports that the server is up, with the status of its last
+// cloud registration if it registers.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	health := map[string]any{"status": "ok"}
+	if s.config.Cloud && s.config.UUID != "" {
+		if status, ok := cloud.LastRegistration(s.config.UUID); ok {
+			health["cloud"] = status
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/cloud"
+)
+
+func TestWithCloudDisabledOnlyAffectsServer(t *testing.T) {
+	t.Setenv(cloud.OfflineEnvVar, "")
+	config := testConfig()
+	config.Cloud, config.UUID = true, "cloud-disabled-uuid"
+	srv := New(config, WithCloudDisabled())
+	srv.registerWithCloud()
+
+	if got := srv.cloudStatus(); got != "disabled" {
+		t.Errorf("cloudStatus() = %q, want disabled", got)
+	}
+	if _, ok := cloud.LastRegistration(config.UUID); ok {
+		t.Error("disabled server registered with the cloud")
+	}
+	if cloud.Offline() {
+		t.Error("WithCloudDisabled turned on offline mode for the process")
+	}
+}
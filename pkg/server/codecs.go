@@ -0,0 +1,117 @@
+package server
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vanna-ai/ont-run/pkg/codec"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// WithCodecs lets REST callers send and receive function calls in other
+// formats than JSON, e.g. WithCodecs(codec.MessagePack(), codec.CBOR()).
+// Request bodies are decoded by their Content-Type and responses encoded by
+// the Accept header, and both are validated against the same schemas as
+// JSON. Errors, MCP and the other endpoints stay JSON.
+func WithCodecs(codecs ...codec.Codec) ServerOption {
+	return func(s *Server) {
+		s.codecs = append(s.codecs, codecs...)
+	}
+}
+
+// decodeBody decodes a function call's request body in the format named by
+// its Content-Type, or JSON. It also returns the format's name for errors.
+func (s *Server) decodeBody(r *http.Request) (any, string, error) {
+	c := s.codecFor(r.Header.Get("Content-Type"))
+	if c == nil {
+		body, err := ont.DecodeJSON(r.Body)
+		return body, "JSON", err
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, c.ContentType(), err
+	}
+	body, err := c.Unmarshal(data)
+	return body, c.ContentType(), err
+}
+
+// codecFor returns the codec for a media type, or nil for JSON and types
+// without a codec.
+func (s *Server) codecFor(contentType string) codec.Codec {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	for _, c := range s.codecs {
+		if strings.EqualFold(c.ContentType(), mediaType) {
+			return c
+		}
+	}
+	return nil
+}
+
+// responseCodec picks the response format from the Accept header. It
+// returns nil for JSON, which is preferred when the caller accepts it as
+// much as another format.
+func (s *Server) responseCodec(r *http.Request) codec.Codec {
+	accept := r.Header.Get("Accept")
+	if len(s.codecs) == 0 || accept == "" {
+		return nil
+	}
+
+	type mediaRange struct {
+		codec codec.Codec
+		json  bool
+		q     float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		switch {
+		case mediaType == "application/json" || mediaType == "*/*" || mediaType == "application/*":
+			ranges = append(ranges, mediaRange{json: true, q: q})
+		case s.codecFor(mediaType) != nil:
+			ranges = append(ranges, mediaRange{codec: s.codecFor(mediaType), q: q})
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+		return ranges[i].json && !ranges[j].json
+	})
+	return ranges[0].codec
+}
+
+// writeEncoded writes a response body with a codec. It reports whether the
+// response was written.
+func (s *Server) writeEncoded(w http.ResponseWriter, c codec.Codec, status int, body any) bool {
+	data, err := c.Marshal(body)
+	if err != nil {
+		s.logger.Error("Failed to encode response", "contentType", c.ContentType(), "error", err)
+		return false
+	}
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(status)
+	w.Write(data)
+	return true
+}
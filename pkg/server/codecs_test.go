@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/codec"
+)
+
+func TestCodecs(t *testing.T) {
+	codecs := []codec.Codec{codec.MessagePack(), codec.CBOR()}
+	h := New(testConfig(), WithCodecs(codecs...)).Handler()
+	for _, c := range codecs {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			encode := func(v any) string {
+				data, err := c.Marshal(v)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return string(data)
+			}
+			item := map[string]any{"id": "a", "count": float64(3)}
+
+			// Encoded in, encoded out
+			resp, body := do(t, h, "POST", "/api/getItem", encode(item), "Content-Type", c.ContentType(), "Accept", c.ContentType())
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status %d: %q", resp.StatusCode, body)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != c.ContentType() {
+				t.Errorf("Content-Type %q, want %q", ct, c.ContentType())
+			}
+			if got, err := c.Unmarshal([]byte(body)); err != nil || !reflect.DeepEqual(got, item) {
+				t.Errorf("response = %v, %v, want %v", got, err, item)
+			}
+
+			// Encoded in, JSON out when the caller prefers it
+			resp, body = do(t, h, "POST", "/api/getItem", encode(item), "Content-Type", c.ContentType(), "Accept", "application/json, "+c.ContentType()+";q=0.5")
+			if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "application/json" {
+				t.Fatalf("JSON response: status %d, Content-Type %q", resp.StatusCode, ct)
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(body), &got); err != nil || !reflect.DeepEqual(got, item) {
+				t.Errorf("JSON response = %v, %v, want %v", got, err, item)
+			}
+
+			// Decoded input is validated against the schema, and the
+			// issues are reported as JSON
+			resp, body = do(t, h, "POST", "/api/getItem", encode(map[string]any{"id": float64(1)}), "Content-Type", c.ContentType(), "Accept", c.ContentType())
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("invalid input: status %d: %q", resp.StatusCode, body)
+			}
+			var invalid InvalidInputResponse
+			if err := json.Unmarshal([]byte(body), &invalid); err != nil || len(invalid.Issues) != 1 || invalid.Issues[0].Field != "/id" {
+				t.Errorf("invalid input response = %q, want an issue for /id", body)
+			}
+
+			// Malformed bodies are rejected before validation
+			if resp, body := do(t, h, "POST", "/api/getItem", "\xc1", "Content-Type", c.ContentType()); resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("malformed body: status %d: %q", resp.StatusCode, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"strconv"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// WithInputCoercion converts loosely typed input values before validation,
+// as ont.Coerce does: "42" becomes 42, "true" becomes true, numbers become
+// strings and ISO 8601 date-times are normalized to RFC 3339. MCP tool input
+// schemas also accept strings for numbers and booleans, so clients that
+// stringify arguments aren't rejected before coercion.
+func WithInputCoercion() ServerOption {
+	return func(s *Server) {
+		s.coerceInputs = true
+	}
+}
+
+// coerceInput coerces input if the server is configured to.
+func (s *Server) coerceInput(fn ont.Function, input map[string]any) map[string]any {
+	if !s.coerceInputs {
+		return input
+	}
+	if coerced, ok := ont.Coerce(fn.CallInputs(), input).(map[string]any); ok {
+		return coerced
+	}
+	return input
+}
+
+// coercibleJSONSchema returns a copy of a JSON Schema whose numbers and
+// booleans also accept the string forms coercion converts.
+func coercibleJSONSchema(schema map[string]any) map[string]any {
+	return rewriteJSONSchema(schema, func(sub map[string]any) {
+		switch sub["type"] {
+		case "integer", "number":
+			sub["type"] = []any{sub["type"], "string"}
+			if enum, ok := sub["enum"].([]float64); ok {
+				values := make([]any, 0, 2*len(enum))
+				for _, v := range enum {
+					values = append(values, v, strconv.FormatFloat(v, 'f', -1, 64))
+				}
+				sub["enum"] = values
+			}
+		case "boolean":
+			sub["type"] = []any{"boolean", "string"}
+		case "string":
+			_, hasEnum := sub["enum"]
+			_, hasFormat := sub["format"]
+			_, hasEncoding := sub["contentEncoding"]
+			if !hasEnum && !hasFormat && !hasEncoding {
+				sub["type"] = []any{"string", "number"}
+			}
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	// RequestIDHeader is the header used to propagate request IDs.
+	RequestIDHeader = "X-Request-ID"
+
+	// WarningsHeader carries resolver warnings as a JSON array of strings
+	// on responses that are not enveloped.
+	WarningsHeader = "X-Ont-Warnings"
+)
+
+// ResponseMeta is the metadata included in enveloped responses.
+type ResponseMeta struct {
+	RequestID  string   `json:"requestId"`
+	DurationMs int64    `json:"durationMs"`
+	Warnings   []string `json:"warnings"`
+}
+
+// ResponseEnvelope wraps function output when WithResponseEnvelope is enabled.
+type ResponseEnvelope struct {
+	Data any          `json:"data"`
+	Meta ResponseMeta `json:"meta"`
+}
+
+// WithResponseEnvelope wraps every successful function response in
+// {"data": ..., "meta": {"requestId", "durationMs", "warnings"}}, both for
+// REST responses and MCP structured content. Pair it with
+// typescript.WithResponseEnvelope so the generated client unwraps it.
+func WithResponseEnvelope() ServerOption {
+	return func(s *Server) {
+		s.envelope = true
+	}
+}
+
+// requestID returns the caller-supplied request ID or generates a new one.
+func requestID(r *http.Request) string {
+	if r != nil {
+		if id := r.Header.Get(RequestIDHeader); id != "" {
+			return id
+		}
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newEnvelope wraps output with metadata for a call that started at start.
+func newEnvelope(output any, id string, start time.Time, warnings []string) ResponseEnvelope {
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return ResponseEnvelope{
+		Data: output,
+		Meta: ResponseMeta{
+			RequestID:  id,
+			DurationMs: time.Since(start).Milliseconds(),
+			Warnings:   warnings,
+		},
+	}
+}
+
+// setWarningsHeader adds resolver warnings to a non-enveloped REST response.
+func setWarningsHeader(w http.ResponseWriter, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	encoded, err := json.Marshal(warnings)
+	if err != nil {
+		return
+	}
+	w.Header().Set(WarningsHeader, string(encoded))
+}
+
+// envelopeJSONSchema returns the JSON Schema of an envelope around data.
+func envelopeJSONSchema(data map[string]any) map[string]any {
+	// "$ref"s point at the document root, so named schemas move up with them
+	var defs any
+	if d, ok := data["$defs"]; ok {
+		inner := make(map[string]any, len(data))
+		for k, v := range data {
+			if k != "$defs" {
+				inner[k] = v
+			}
+		}
+		data = inner
+		defs = d
+	}
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"data": data,
+			"meta": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"requestId":  map[string]any{"type": "string"},
+					"durationMs": map[string]any{"type": "integer"},
+					"warnings": map[string]any{
+						"type":  "array",
+						"items": map[string]any{"type": "string"},
+					},
+				},
+				"required": []string{"requestId", "durationMs", "warnings"},
+			},
+		},
+		"required": []string{"data", "meta"},
+	}
+	if defs != nil {
+		schema["$defs"] = defs
+	}
+	return schema
+}
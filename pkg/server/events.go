@@ -0,0 +1,131 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// Reasons an AccessDeniedEvent reports.
+const (
+	DeniedAccessGroups = "accessGroups"
+	DeniedAccessRule   = "accessRule"
+	DeniedExperimental = "experimental"
+)
+
+// ConfigReloadEvent is emitted after Reload swaps in a new config.
+type ConfigReloadEvent struct {
+	Previous *ont.Config
+	Config   *ont.Config
+}
+
+// FunctionErrorEvent is emitted when a resolver, transform or computed
+// field fails.
+type FunctionErrorEvent struct {
+	Function string
+	Err      error
+	Request  *http.Request
+}
+
+// AccessDeniedEvent is emitted when a caller is refused a function, for
+// the Reason given by one of the Denied constants.
+type AccessDeniedEvent struct {
+	Function     string
+	Reason       string
+	AccessGroups []string
+	Request      *http.Request
+}
+
+// LockMismatchEvent is emitted when the served config doesn't match the
+// lock file set with WithLockFile.
+type LockMismatchEvent struct {
+	Path string
+	Diff *ont.LockDiff
+}
+
+// eventBus holds the handlers subscribed with the Server's On methods.
+// Handlers run synchronously, in the order they were added.
+type eventBus struct {
+	mu            sync.RWMutex
+	configReload  []func(ConfigReloadEvent)
+	functionError []func(FunctionErrorEvent)
+	accessDenied  []func(AccessDeniedEvent)
+	lockMismatch  []func(LockMismatchEvent)
+}
+
+// OnConfigReload calls handler after every successful Reload, e.g. to bust
+// caches derived from the config. Handlers run synchronously and should
+// return quickly, as should those of the other On methods.
+func (s *Server) OnConfigReload(handler func(ConfigReloadEvent)) {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	s.events.configReload = append(s.events.configReload, handler)
+}
+
+// OnFunctionError calls handler when a function call fails on the server
+// side. Invalid input is not a function error.
+func (s *Server) OnFunctionError(handler func(FunctionErrorEvent)) {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	s.events.functionError = append(s.events.functionError, handler)
+}
+
+// OnAccessDenied calls handler when a REST or MCP caller is refused a
+// function by its access groups, access rule or stability.
+func (s *Server) OnAccessDenied(handler func(AccessDeniedEvent)) {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	s.events.accessDenied = append(s.events.accessDenied, handler)
+}
+
+// OnLockMismatch calls handler when the config the server is about to
+// serve doesn't match the lock file (see WithLockFile).
+func (s *Server) OnLockMismatch(handler func(LockMismatchEvent)) {
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	s.events.lockMismatch = append(s.events.lockMismatch, handler)
+}
+
+func (b *eventBus) emitConfigReload(e ConfigReloadEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.configReload {
+		handler(e)
+	}
+}
+
+func (b *eventBus) emitFunctionError(e FunctionErrorEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.functionError {
+		handler(e)
+	}
+}
+
+func (b *eventBus) emitAccessDenied(e AccessDeniedEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.accessDenied {
+		handler(e)
+	}
+}
+
+func (b *eventBus) emitLockMismatch(e LockMismatchEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, handler := range b.lockMismatch {
+		handler(e)
+	}
+}
+
+// functionFailed alerts and notifies subscribers that a call failed.
+func (s *Server) functionFailed(ctx ont.Context, name string, fn ont.Function, err error) {
+	s.alertFailure(name, fn, err)
+	s.events.emitFunctionError(FunctionErrorEvent{Function: name, Err: err, Request: ctx.Request()})
+}
+
+// accessDenied notifies subscribers that a caller was refused a function.
+func (s *Server) accessDenied(r *http.Request, name, reason string, auth *AuthResult) {
+	s.events.emitAccessDenied(AccessDeniedEvent{Function: name, Reason: reason, AccessGroups: auth.AccessGroups, Request: r})
+}
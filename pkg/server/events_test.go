@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestConfigReloadEvent(t *testing.T) {
+	previous, config := testConfig(), testConfig()
+	srv := New(previous)
+	srv.Handler()
+	var events []ConfigReloadEvent
+	srv.OnConfigReload(func(e ConfigReloadEvent) { events = append(events, e) })
+
+	if err := srv.Reload(config); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Previous != previous || events[0].Config != config {
+		t.Errorf("events = %+v, want one from the previous config to the new one", events)
+	}
+
+	// Invalid configs aren't served, so nothing is reloaded
+	invalid := testConfig()
+	invalid.Functions["echo"] = ont.Function{}
+	if err := srv.Reload(invalid); err == nil {
+		t.Fatal("Reload accepted an invalid config")
+	}
+	if len(events) != 1 {
+		t.Errorf("%d events after a failed reload, want 1", len(events))
+	}
+}
+
+func TestFunctionErrorEvent(t *testing.T) {
+	failure := errors.New("database unavailable")
+	config := testConfig()
+	fn := config.Functions["echo"]
+	fn.Resolver = func(ctx ont.Context, input any) (any, error) {
+		return nil, failure
+	}
+	config.Functions["echo"] = fn
+	srv := New(config)
+	var events []FunctionErrorEvent
+	srv.OnFunctionError(func(e FunctionErrorEvent) { events = append(events, e) })
+	h := srv.Handler()
+
+	// Invalid input is the caller's error, not the function's
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":1}`); resp.StatusCode != 400 {
+		t.Fatalf("invalid input: status %d, want 400", resp.StatusCode)
+	}
+	if len(events) != 0 {
+		t.Fatalf("events after invalid input = %+v, want none", events)
+	}
+
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != 500 {
+		t.Fatalf("failing call: status %d, want 500", resp.StatusCode)
+	}
+	if len(events) != 1 {
+		t.Fatalf("%d events, want 1", len(events))
+	}
+	if e := events[0]; e.Function != "echo" || !errors.Is(e.Err, failure) || e.Request == nil || e.Request.URL.Path != "/api/echo" {
+		t.Errorf("event = %+v, want echo's error and request", e)
+	}
+}
+
+func TestAccessDeniedEvent(t *testing.T) {
+	tests := map[string]*ont.Config{
+		DeniedAccessGroups: func() *ont.Config {
+			config := testConfig()
+			fn := config.Functions["echo"]
+			fn.Access = []string{"admin"}
+			config.Functions["echo"] = fn
+			return config
+		}(),
+		DeniedAccessRule: func() *ont.Config {
+			config := testConfig()
+			fn := config.Functions["echo"]
+			fn.AccessRule = "input.message == 'please'"
+			config.Functions["echo"] = fn
+			return config
+		}(),
+		DeniedExperimental: experimentalConfig(),
+	}
+	for reason, config := range tests {
+		t.Run(reason, func(t *testing.T) {
+			srv := New(config, groups("public"))
+			var events []AccessDeniedEvent
+			srv.OnAccessDenied(func(e AccessDeniedEvent) { events = append(events, e) })
+			h := srv.Handler()
+
+			if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != 403 {
+				t.Fatalf("status %d, want 403: %s", resp.StatusCode, body)
+			}
+			if len(events) != 1 {
+				t.Fatalf("%d events, want 1", len(events))
+			}
+			e := events[0]
+			if e.Function != "echo" || e.Reason != reason || !slices.Equal(e.AccessGroups, []string{"public"}) || e.Request == nil {
+				t.Errorf("event = %+v, want echo denied for %s to public", e, reason)
+			}
+
+			// MCP calls report the same reason
+			result, err := connectMCP(t, h, nil).CallTool(context.Background(), &mcp.CallToolParams{
+				Name:      "echo",
+				Arguments: map[string]any{"message": "hi"},
+			})
+			if err == nil && !result.IsError {
+				t.Fatalf("MCP call succeeded: %+v", result)
+			}
+			if len(events) != 2 || events[1].Reason != reason {
+				t.Errorf("events after the MCP call = %+v, want a second for %s", events, reason)
+			}
+		})
+	}
+
+	// Allowed calls emit nothing
+	srv := New(testConfig(), groups("public"))
+	srv.OnAccessDenied(func(e AccessDeniedEvent) { t.Errorf("allowed call denied: %+v", e) })
+	if resp, _ := do(t, srv.Handler(), "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != 200 {
+		t.Errorf("allowed call: status %d", resp.StatusCode)
+	}
+}
+
+func TestLockMismatchEvent(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "ont.lock")
+	if err := testConfig().WriteLock(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	changed := testConfig()
+	fn := changed.Functions["echo"]
+	fn.Access = []string{"admin"}
+	changed.Functions["echo"] = fn
+
+	srv := New(testConfig(), WithLockFile(lockPath))
+	var events []LockMismatchEvent
+	srv.OnLockMismatch(func(e LockMismatchEvent) { events = append(events, e) })
+
+	// The locked config matches
+	srv.Handler()
+	if len(events) != 0 {
+		t.Fatalf("events for the locked config = %+v, want none", events)
+	}
+
+	// Reloading a changed one doesn't
+	if err := srv.Reload(changed); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("%d events after reloading a changed config, want 1", len(events))
+	}
+	if e := events[0]; e.Path != lockPath || e.Diff == nil || !e.Diff.RequiresReview() {
+		t.Errorf("event = %+v, want a diff of %s requiring review", e, lockPath)
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/notify"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// FailureEvent is the notify.Message event of failure alerts.
+const FailureEvent = "function.failed"
+
+// alertTimeout bounds how long delivering a failure alert may take.
+const alertTimeout = 10 * time.Second
+
+// ErrorResponse is the JSON body of a failed REST call when
+// WithInternalErrors is enabled.
+type ErrorResponse struct {
+	Error    string `json:"error"`
+	Function string `json:"function"`
+	Owner    string `json:"owner,omitempty"`
+	Team     string `json:"team,omitempty"`
+	Runbook  string `json:"runbook,omitempty"`
+}
+
+// WithInternalErrors responds to failed calls with a JSON ErrorResponse
+// naming the function's owner, team and runbook instead of plain text. Use
+// it for internal deployments, where callers should know who to contact.
+func WithInternalErrors() ServerOption {
+	return func(s *Server) {
+		s.internalErrors = true
+	}
+}
+
+// WithFailureAlerts notifies when a resolver fails, at most once per
+// function per interval so a failing function doesn't flood the channel.
+// Alerts carry the function's owner, team and runbook, and are addressed to
+// the owner when it is an email address. Delivery happens in the background
+// and failures to deliver are logged.
+func WithFailureAlerts(notifier notify.Notifier, interval time.Duration) ServerOption {
+	return func(s *Server) {
+		s.alerts = &failureAlerts{notifier: notifier, interval: interval, last: make(map[string]time.Time)}
+	}
+}
+
+// failureAlerts throttles failure notifications per function.
+type failureAlerts struct {
+	notifier notify.Notifier
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+// due reports whether an alert for the function may be sent now, and if so
+// records it as sent.
+func (a *failureAlerts) due(name string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if last, ok := a.last[name]; ok && now.Sub(last) < a.interval {
+		return false
+	}
+	a.last[name] = now
+	return true
+}
+
+// alertFailure sends a failure alert for the function if alerts are enabled
+// and one wasn't sent recently.
+func (s *Server) alertFailure(name string, fn ont.Function, err error) {
+	if s.alerts == nil || !s.alerts.due(name, time.Now()) {
+		return
+	}
+
+	body := fmt.Sprintf("%s failed: %v\n", name, err)
+	for _, field := range [][2]string{{"Owner", fn.Owner}, {"Team", fn.Team}, {"Runbook", fn.Runbook}} {
+		if field[1] != "" {
+			body += fmt.Sprintf("\n%s: %s", field[0], field[1])
+		}
+	}
+	msg := notify.Message{
+		Event:   FailureEvent,
+		Subject: "Function failed: " + name,
+		Body:    body,
+		Data: map[string]any{
+			"function": name,
+			"error":    err.Error(),
+			"owner":    fn.Owner,
+			"team":     fn.Team,
+			"runbook":  fn.Runbook,
+		},
+	}
+	if strings.Contains(fn.Owner, "@") {
+		msg.To = []string{fn.Owner}
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+		defer cancel()
+		if err := s.alerts.notifier.Notify(ctx, msg); err != nil {
+			s.logger.Error("Failed to send failure alert", "function", name, "error", err)
+		}
+	}()
+}
+
+// writeFailure writes a failed call's error response: plain text, or an
+// ErrorResponse with the function's ownership in internal mode.
+func (s *Server) writeFailure(w http.ResponseWriter, name string, fn ont.Function, message string, status int) {
+	if !s.internalErrors {
+		http.Error(w, message, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Error:    message,
+		Function: name,
+		Owner:    fn.Owner,
+		Team:     fn.Team,
+		Runbook:  fn.Runbook,
+	})
+}
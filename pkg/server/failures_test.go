@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/notify"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// chanNotifier delivers messages to a channel.
+type chanNotifier chan notify.Message
+
+func (c chanNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	c <- msg
+	return nil
+}
+
+// failingConfig is testConfig with both functions failing, and echo owned.
+func failingConfig() *ont.Config {
+	config := testConfig()
+	for name, fn := range config.Functions {
+		fn.Resolver = func(ctx ont.Context, input any) (any, error) {
+			return nil, errors.New("database unavailable")
+		}
+		if name == "echo" {
+			fn.Owner, fn.Team, fn.Runbook = "ada@example.com", "payments", "https://runbooks.example.com/echo"
+		}
+		config.Functions[name] = fn
+	}
+	return config
+}
+
+func TestInternalErrors(t *testing.T) {
+	resp, body := do(t, New(failingConfig(), WithInternalErrors()).Handler(), "POST", "/api/echo", `{"message":"hi"}`)
+	if resp.StatusCode != http.StatusInternalServerError || resp.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("status %d, Content-Type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	var got ErrorResponse
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("body %q: %v", body, err)
+	}
+	want := ErrorResponse{
+		Error:    "database unavailable",
+		Function: "echo",
+		Owner:    "ada@example.com",
+		Team:     "payments",
+		Runbook:  "https://runbooks.example.com/echo",
+	}
+	if got != want {
+		t.Errorf("response = %+v, want %+v", got, want)
+	}
+
+	// Without the option, failures stay plain text
+	resp, body = do(t, New(failingConfig()).Handler(), "POST", "/api/echo", `{"message":"hi"}`)
+	if resp.StatusCode != http.StatusInternalServerError || body != "database unavailable\n" {
+		t.Errorf("plain failure: status %d, body %q", resp.StatusCode, body)
+	}
+}
+
+func TestFailureAlerts(t *testing.T) {
+	notifier := make(chanNotifier, 10)
+	h := New(failingConfig(), WithFailureAlerts(notifier, time.Hour)).Handler()
+	call := func(name, body string) {
+		t.Helper()
+		if resp, _ := do(t, h, "POST", "/api/"+name, body); resp.StatusCode != http.StatusInternalServerError {
+			t.Fatalf("%s: status %d, want 500", name, resp.StatusCode)
+		}
+	}
+
+	call("echo", `{"message":"hi"}`)
+	msg := receive(t, chan notify.Message(notifier))
+	if msg.Event != FailureEvent || msg.Subject != "Function failed: echo" || !slices.Equal(msg.To, []string{"ada@example.com"}) {
+		t.Errorf("alert = %+v, want one for echo addressed to its owner", msg)
+	}
+	if msg.Data["error"] != "database unavailable" || msg.Data["runbook"] != "https://runbooks.example.com/echo" {
+		t.Errorf("alert data = %v", msg.Data)
+	}
+
+	// Failing again within the interval doesn't alert, but other functions
+	// have their own interval
+	call("echo", `{"message":"hi"}`)
+	call("getItem", `{"id":"1"}`)
+	if msg := receive(t, chan notify.Message(notifier)); msg.Subject != "Function failed: getItem" || msg.To != nil {
+		t.Errorf("alert = %+v, want one for getItem with no recipients", msg)
+	}
+	select {
+	case msg := <-notifier:
+		t.Errorf("unexpected alert %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFailureAlertsInterval(t *testing.T) {
+	alerts := &failureAlerts{interval: time.Minute, last: make(map[string]time.Time)}
+	now := time.Now()
+	for _, step := range []struct {
+		after time.Duration
+		want  bool
+	}{
+		{0, true},
+		{30 * time.Second, false},
+		{time.Minute, true},
+		{90 * time.Second, false},
+	} {
+		if got := alerts.due("echo", now.Add(step.after)); got != step.want {
+			t.Errorf("due after %v = %v, want %v", step.after, got, step.want)
+		}
+	}
+}
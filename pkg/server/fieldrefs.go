@@ -0,0 +1,18 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// withFieldReferences tells agents where to find the valid values of input
+// fields with a field reference: MCP completion only covers prompts and
+// resources, so the tool description names the tool to call instead.
+func withFieldReferences(fn ont.Function, description string) string {
+	for _, ref := range fn.FieldReferences {
+		description += fmt.Sprintf("\n\nValid values for %s come from the %s tool.", strings.TrimPrefix(ref.Path, "/"), ont.FunctionIdentifier(ref.FunctionName))
+	}
+	return description
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// WithGraphViewer serves a diagram of the ontology's functions, entities
+// and access groups: an HTML viewer at /graph, and the raw graph at
+// /graph.dot (Graphviz), /graph.mmd (Mermaid) and /graph.json. Callers are
+// authenticated, and the graph shows only the non-internal functions they
+// may call. It still names functions hidden from MCP, so enable it for
+// development or internal servers.
+func WithGraphViewer() ServerOption {
+	return func(s *Server) {
+		s.graphViewer = true
+	}
+}
+
+// graphViewerTemplate renders the Mermaid diagram client-side, and shows
+// the Mermaid source if the script can't load.
+var graphViewerTemplate = template.Must(template.New("graph").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}} ontology</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; }
+nav a { margin-right: 1rem; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<nav><a href="graph.dot">DOT</a><a href="graph.mmd">Mermaid</a><a href="graph.json">JSON</a></nav>
+<pre class="mermaid">{{.Mermaid}}</pre>
+<script type="module">
+import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@11/dist/mermaid.esm.min.mjs";
+mermaid.initialize({ startOnLoad: true });
+</script>
+</body>
+</html>
+`))
+
+// handleOntologyGraph serves GET /ontology/graph, the domain graph as JSON
+// for agents: entities and their relations, access groups, and the
+// non-internal functions the caller may call.
+func (s *Server) handleOntologyGraph(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authResult, err := s.authFunc(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	public := s.config.Public().Localized(r.Header.Get("Accept-Language"))
+	graph := public.Graph().Filter(func(n ont.GraphNode) bool {
+		if n.Kind != ont.GraphFunction {
+			return true
+		}
+		fn := public.Functions[n.Name]
+		return fn.CheckAccess(authResult.AccessGroups)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(graph)
+}
+
+// registerGraphViewer adds the graph endpoints to mux. Like
+// /ontology/graph, they authenticate the caller and only show the functions
+// it may call. Labels are translated for the caller's Accept-Language.
+func (s *Server) registerGraphViewer(mux *http.ServeMux) {
+	public := s.config.Public()
+	defaultGraph := public.Graph()
+	translated := s.config.HasTranslations()
+	graphFor := func(w http.ResponseWriter, r *http.Request) (*ont.Graph, bool) {
+		authResult, err := s.authFunc(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+			return nil, false
+		}
+		graph := defaultGraph
+		if lang := r.Header.Get("Accept-Language"); translated && lang != "" {
+			graph = public.Localized(lang).Graph()
+		}
+		w.Header().Set("Vary", "Accept-Language")
+		return graph.Filter(func(n ont.GraphNode) bool {
+			if n.Kind != ont.GraphFunction {
+				return true
+			}
+			fn := public.Functions[n.Name]
+			return fn.CheckAccess(authResult.AccessGroups)
+		}), true
+	}
+
+	mux.HandleFunc("/graph", func(w http.ResponseWriter, r *http.Request) {
+		graph, ok := graphFor(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		graphViewerTemplate.Execute(w, map[string]string{
+			"Name":    s.config.Name,
+			"Mermaid": graph.Mermaid(),
+		})
+	})
+	mux.HandleFunc("/graph.dot", func(w http.ResponseWriter, r *http.Request) {
+		graph, ok := graphFor(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		w.Write([]byte(graph.DOT()))
+	})
+	mux.HandleFunc("/graph.mmd", func(w http.ResponseWriter, r *http.Request) {
+		graph, ok := graphFor(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(graph.Mermaid()))
+	})
+	mux.HandleFunc("/graph.json", func(w http.ResponseWriter, r *http.Request) {
+		graph, ok := graphFor(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(graph)
+	})
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestGraphViewerChecksAccess(t *testing.T) {
+	config := testConfig()
+	config.Functions["deleteItem"] = ont.Function{
+		Description: "Delete an item",
+		Access:      []string{"admin"},
+		Inputs:      ont.Object(map[string]ont.Schema{"id": ont.String()}),
+		Outputs:     ont.Object(map[string]ont.Schema{}),
+		Resolver:    func(ctx ont.Context, input any) (any, error) { return map[string]any{}, nil },
+	}
+	auth := WithAuth(func(r *http.Request) (*AuthResult, error) {
+		switch r.Header.Get("Authorization") {
+		case "admin":
+			return &AuthResult{AccessGroups: []string{"admin"}}, nil
+		case "public":
+			return &AuthResult{AccessGroups: []string{"public"}}, nil
+		}
+		return nil, errors.New("no credentials")
+	})
+	h := New(config, auth, WithGraphViewer()).Handler()
+
+	for _, path := range []string{"/graph", "/graph.dot", "/graph.mmd", "/graph.json"} {
+		if resp, _ := do(t, h, "GET", path, ""); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s without credentials: status %d, want 401", path, resp.StatusCode)
+		}
+		resp, body := do(t, h, "GET", path, "", "Authorization", "public")
+		if resp.StatusCode != http.StatusOK || !strings.Contains(body, "echo") || strings.Contains(body, "deleteItem") {
+			t.Errorf("%s for public: status %d, want echo without deleteItem: %s", path, resp.StatusCode, body)
+		}
+		if _, body := do(t, h, "GET", path, "", "Authorization", "admin"); !strings.Contains(body, "deleteItem") {
+			t.Errorf("%s for admin is missing deleteItem: %s", path, body)
+		}
+	}
+}
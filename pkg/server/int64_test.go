@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// int64Config has a store function that records the Go types its resolver
+// receives for a Number and an Int64 field.
+func int64Config(got map[string]any) *ont.Config {
+	config := testConfig()
+	config.Functions["store"] = ont.Function{
+		Description:           "Store numbers",
+		Access:                []string{"admin"},
+		IncludeInMcpListTools: true,
+		Inputs:                ont.Object(map[string]ont.Schema{"n": ont.Number(), "id": ont.Int64()}),
+		Outputs:               ont.Object(map[string]ont.Schema{"ok": ont.Boolean()}),
+		Resolver: func(ctx ont.Context, input any) (any, error) {
+			for k, v := range input.(map[string]any) {
+				got[k] = v
+			}
+			return map[string]any{"ok": true}, nil
+		},
+	}
+	return config
+}
+
+func TestLargeIntegersKeepNumberFieldsFloat(t *testing.T) {
+	got := map[string]any{}
+	h := New(int64Config(got), groups("admin")).Handler()
+
+	check := func(transport string) {
+		t.Helper()
+		if _, ok := got["n"].(float64); !ok {
+			t.Errorf("%s: Number field = %v (%T), want float64", transport, got["n"], got["n"])
+		}
+		if got["id"] != int64(9007199254740993) {
+			t.Errorf("%s: Int64 field = %v (%T), want exact int64", transport, got["id"], got["id"])
+		}
+	}
+
+	body := `{"n": 9007199254740993, "id": 9007199254740993}`
+	if resp, text := do(t, h, "POST", "/api/store", body); resp.StatusCode != http.StatusOK {
+		t.Fatalf("REST call: status %d: %s", resp.StatusCode, text)
+	}
+	check("REST")
+
+	clear(got)
+	session := connectMCP(t, h, nil)
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "store",
+		Arguments: map[string]any{"n": int64(9007199254740993), "id": int64(9007199254740993)},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("MCP call: %v %+v", err, result)
+	}
+	check("MCP")
+}
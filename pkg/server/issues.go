@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// InvalidInputResponse is the JSON body of a 400 response to invalid input.
+// Issues lists every violation with the JSON pointer of the field at fault,
+// so frontends can highlight fields.
+type InvalidInputResponse struct {
+	Error  string               `json:"error"`
+	Issues ont.ValidationErrors `json:"issues"`
+}
+
+// inputIssues returns the violations behind an input validation error. Errors
+// that don't list them, such as strict mode's unknown fields, become a single
+// issue about the whole input.
+func inputIssues(err error) ont.ValidationErrors {
+	var issues ont.ValidationErrors
+	if errors.As(err, &issues) {
+		return issues
+	}
+	return ont.ValidationErrors{{Message: err.Error()}}
+}
+
+// writeInvalidInput responds 400 with an InvalidInputResponse. Issue paths
+// use the server's wire field names.
+func (s *Server) writeInvalidInput(w http.ResponseWriter, err error) {
+	issues := inputIssues(err)
+	if !s.fieldNaming.IsIdentity() {
+		wire := make(ont.ValidationErrors, len(issues))
+		for i, issue := range issues {
+			wire[i] = &ont.ValidationError{Field: wirePointer(issue.Field, s.fieldNaming), Message: issue.Message}
+		}
+		issues = wire
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(InvalidInputResponse{
+		Error:  fmt.Sprintf("Invalid input: %v", err),
+		Issues: issues,
+	})
+}
+
+// wirePointer renames the property names in a JSON pointer to wire names.
+func wirePointer(path string, naming ont.FieldNaming) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = naming.Apply(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// invalidInputResult is the MCP tool result for invalid input: an error
+// whose structured content is an InvalidInputResponse.
+func invalidInputResult(err error) *mcp.CallToolResult {
+	message := fmt.Sprintf("invalid input: %v", err)
+	return &mcp.CallToolResult{
+		IsError: true,
+		Content: []mcp.Content{&mcp.TextContent{Text: message}},
+		StructuredContent: InvalidInputResponse{
+			Error:  message,
+			Issues: inputIssues(err),
+		},
+	}
+}
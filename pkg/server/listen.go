@@ -0,0 +1,94 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket
+// activation (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// ServeListener serves on l instead of binding a TCP address, e.g. a unix
+// socket from UnixListener or a systemd socket from ActivationListeners.
+// It closes l when it returns.
+func (s *Server) ServeListener(l net.Listener) error {
+	s.registerWithCloud()
+
+	s.reportStartup(l)
+	return s.serveWarm(s.HTTPServer(""), l)
+}
+
+// ServeListener creates a server and serves on l.
+func ServeListener(config *ont.Config, l net.Listener, opts ...ServerOption) error {
+	server := New(config, opts...)
+	return server.ServeListener(l)
+}
+
+// UnixListener listens on a unix domain socket at path, for a reverse proxy
+// on the same host. A stale socket left by a previous run is removed first;
+// any other file at path is an error. The socket is removed when the
+// listener is closed.
+func UnixListener(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	return l, nil
+}
+
+// ActivationListeners returns the sockets passed by systemd socket
+// activation, in the order of the ListenStream= lines in the .socket unit.
+// It returns nil when the process wasn't socket activated. The LISTEN_*
+// variables are unset so child processes don't inherit the sockets.
+func ActivationListeners() ([]net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	if pid == "" || fds == "" {
+		return nil, nil
+	}
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+	if pid != strconv.Itoa(os.Getpid()) {
+		// Meant for another process
+		return nil, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+
+	listeners := make([]net.Listener, 0, n)
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		file := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		l, err := net.FileListener(file)
+		// FileListener dups the descriptor
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("socket %d is not a listener: %w", fd, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeListenerUnix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ont.sock")
+	l, err := UnixListener(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- New(testConfig()).ServeListener(l) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Post("http://ont/api/echo", "application/json", strings.NewReader(`{"message":"hi"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status %d over the unix socket", resp.StatusCode)
+	}
+	client.CloseIdleConnections()
+
+	l.Close()
+	if err := receive(t, served); err == nil {
+		t.Error("ServeListener returned nil after its listener closed")
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left behind after closing: %v", err)
+	}
+}
+
+func TestUnixListenerStale(t *testing.T) {
+	dir := t.TempDir()
+
+	// A socket left by a crashed process is replaced
+	stale := filepath.Join(dir, "stale.sock")
+	l, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	if l, err = UnixListener(stale); err != nil {
+		t.Fatalf("stale socket: %v", err)
+	}
+	defer l.Close()
+
+	// A socket in use is not
+	if _, err := UnixListener(stale); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("socket in use: err = %v", err)
+	}
+
+	// Nor is any other file
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UnixListener(file); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("regular file: err = %v", err)
+	}
+}
+
+func TestActivationListenersEnv(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+	for _, tc := range []struct {
+		name, pid, fds string
+		wantErr        bool
+	}{
+		{"not activated", "", "", false},
+		{"another process", "1", "1", false},
+		{"no sockets", pid, "0", false},
+		{"invalid count", pid, "two", true},
+		{"negative count", pid, "-1", true},
+	} {
+		t.Setenv("LISTEN_PID", tc.pid)
+		t.Setenv("LISTEN_FDS", tc.fds)
+		listeners, err := ActivationListeners()
+		if (err != nil) != tc.wantErr || len(listeners) != 0 {
+			t.Errorf("%s: %d listeners, err %v; want none and error %v", tc.name, len(listeners), err, tc.wantErr)
+		}
+		if tc.pid != "" && (os.Getenv("LISTEN_PID") != "" || os.Getenv("LISTEN_FDS") != "") {
+			t.Errorf("%s: LISTEN_* not unset", tc.name)
+		}
+	}
+}
+
+// activationChildEnv makes the test binary run TestActivationListeners as
+// the socket-activated child.
+const activationChildEnv = "ONT_TEST_ACTIVATION_CHILD"
+
+func TestActivationListeners(t *testing.T) {
+	if addr := os.Getenv(activationChildEnv); addr != "" {
+		// systemd sets LISTEN_PID to the pid it execs, which only the child
+		// knows
+		os.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		listeners, err := ActivationListeners()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listeners) != 1 || listeners[0].Addr().String() != addr {
+			t.Fatalf("listeners = %v, want the one on %s", listeners, addr)
+		}
+		if os.Getenv("LISTEN_FDS") != "" {
+			t.Error("LISTEN_FDS not unset")
+		}
+		return
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	file, err := l.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^TestActivationListeners$", "-test.count=1")
+	cmd.Env = append(os.Environ(), activationChildEnv+"="+l.Addr().String(), "LISTEN_FDS=1")
+	// The first extra file is descriptor 3, where systemd passes sockets
+	cmd.ExtraFiles = []*os.File{file}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("socket-activated child failed: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net/http"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// callContext returns the context a call of the named function runs with.
+// Its logger includes the function name, the request ID and, for MCP tool
+// calls, the session ID, and it publishes events with Publish.
+func (s *Server) callContext(r *http.Request, name string, authResult *AuthResult, id, sessionID string) ont.Context {
+	fields := []any{"function", name, "requestId", id}
+	if sessionID != "" {
+		fields = append(fields, "sessionId", sessionID)
+	}
+	ctx := ont.NewContext(r, s.logger, authResult.AccessGroups, authResult.UserContext).WithLogFields(fields...)
+	return ont.WithEmitter(ctx, s.Publish)
+}
+
+// ensureRequestID returns the request's ID, first giving a REST request
+// without one a generated ID, so its logs and response envelope agree.
+func ensureRequestID(r *http.Request) string {
+	id := requestID(r)
+	r.Header.Set(RequestIDHeader, id)
+	return id
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// DefaultToolDescriptionLimit is the default length budget of MCP tool
+// descriptions, in bytes. Some MCP clients cut or reject longer ones.
+const DefaultToolDescriptionLimit = 1024
+
+// WithToolDescriptionLimit sets the length budget of MCP tool descriptions.
+// Functions' LongDescription is truncated at a paragraph or sentence to fit
+// (see ont.TruncateMarkdown); the summary and badges are always kept.
+func WithToolDescriptionLimit(n int) ServerOption {
+	return func(s *Server) {
+		s.toolDescriptionLimit = n
+	}
+}
+
+// withLongDescription appends fn's long description to a tool
+// description, truncated to the server's limit.
+func (s *Server) withLongDescription(fn ont.Function, description string) string {
+	if fn.LongDescription == "" {
+		return description
+	}
+	long := ont.TruncateMarkdown(fn.LongDescription, s.toolDescriptionLimit-len(description)-len("\n\n"))
+	if long == "" {
+		return description
+	}
+	return description + "\n\n" + long
+}
@@ -2,17 +2,22 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"mime"
+	"net"
 	"net/http"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/vanna-ai/ont-run/pkg/cloud"
+	"github.com/vanna-ai/ont-run/pkg/codec"
 	ont "github.com/vanna-ai/ont-run/pkg/ontology"
 )
 
@@ -23,6 +28,39 @@ type Server struct {
 	authFunc      AuthFunc
 	staticFS      http.FileSystem
 	visualizerHTML string
+	fieldNaming   ont.FieldNaming
+	envelope      bool
+	changes       *changeHub
+	watchTimeout  time.Duration
+	watchPoll     time.Duration
+	offload       *offloadConfig
+	strictInputs  bool
+	coerceInputs  bool
+	graphViewer   bool
+	internalErrors bool
+	alerts        *failureAlerts
+	experimentalGroups []string
+	shadow        *shadowConfig
+	shadowHeaders func(http.Header)
+	events        *eventBus
+	subscriptions *subscriptionHub
+	live          *liveHandler
+	lockFile      string
+	approval      *approvalCheck
+	codecs        []codec.Codec
+	transport     transportConfig
+	proxy         *proxyConfig
+	registry      *ont.Registry
+	warmUp        *warmUp
+	inits         functionInits
+	transcripts   *exampleTranscripts
+	cloudDisabled bool
+	customAuth    bool
+	authSchemes   []string
+	extensions    map[string]any
+	startup       *startupInfo
+	limiter       *rateLimiter
+	toolDescriptionLimit int
 }
 
 // AuthFunc is a function that authenticates a request and returns access groups.
@@ -48,6 +86,7 @@ func WithLogger(logger ont.Logger) ServerOption {
 func WithAuth(authFunc AuthFunc) ServerOption {
 	return func(s *Server) {
 		s.authFunc = authFunc
+		s.customAuth = true
 	}
 }
 
@@ -67,19 +106,36 @@ func WithVisualizerHTML(html string) ServerOption {
 	}
 }
 
+// WithFieldNaming sets the JSON field naming strategy for the REST API.
+// Input keys are translated to the authored names before validation and
+// output keys are translated to wire names before encoding. MCP tools keep
+// the authored names since their schemas are published as-is.
+func WithFieldNaming(naming ont.FieldNaming) ServerOption {
+	return func(s *Server) {
+		s.fieldNaming = naming
+	}
+}
+
 // New creates a new server with the given configuration.
 func New(config *ont.Config, opts ...ServerOption) *Server {
 	s := &Server{
-		config: config,
-		logger: ont.DefaultLogger(),
-		authFunc: func(r *http.Request) (*AuthResult, error) {
-			// Default: allow all access groups
-			groups := make([]string, 0, len(config.AccessGroups))
-			for name := range config.AccessGroups {
-				groups = append(groups, name)
-			}
-			return &AuthResult{AccessGroups: groups}, nil
-		},
+		config:       config,
+		logger:       ont.DefaultLogger(),
+		changes:      newChangeHub(),
+		watchTimeout: DefaultWatchTimeout,
+		watchPoll:    DefaultWatchPollInterval,
+		events:       &eventBus{},
+		subscriptions: newSubscriptionHub(),
+		live:         &liveHandler{},
+		warmUp:       newWarmUp(),
+		startup:      &startupInfo{},
+		limiter:      &rateLimiter{},
+		toolDescriptionLimit: DefaultToolDescriptionLimit,
+	}
+	s.authFunc = func(r *http.Request) (*AuthResult, error) {
+		// Default: allow all access groups
+		groups := s.currentRegistry().AccessGroups()
+		return &AuthResult{AccessGroups: slices.Clone(groups)}, nil
 	}
 
 	for _, opt := range opts {
@@ -89,26 +145,86 @@ func New(config *ont.Config, opts ...ServerOption) *Server {
 	return s
 }
 
-// Handler returns an http.Handler that serves the API.
+// Handler returns an http.Handler that serves the API, and the configs
+// passed to Reload after it. It starts the warm-up (see WarmUp); calls are
+// answered with 503 until it succeeds.
 func (s *Server) Handler() http.Handler {
+	s.liveServer()
+	s.checkLock()
+	s.startWarmUp()
+	return s.live
+}
+
+// liveServer returns the server of the config being served, building the
+// routes of s if nothing is served yet.
+func (s *Server) liveServer() *Server {
+	s.live.mu.Lock()
+	defer s.live.mu.Unlock()
+	if s.live.handler == nil {
+		s.live.server = s
+		s.live.handler = s.routes()
+	}
+	return s.live.server
+}
+
+// routes builds the handler for the server's config.
+func (s *Server) routes() http.Handler {
+	s.registry = ont.NewRegistry(s.config)
+	s.inits = newFunctionInits(s.config)
 	mux := http.NewServeMux()
 
-	// Register API endpoints for each function
-	for name, fn := range s.config.Functions {
-		funcName := name // capture for closure
-		funcDef := fn
-		mux.HandleFunc("/api/"+funcName, s.handleFunction(funcName, funcDef))
+	// API endpoints for each function, looked up in a table built once
+	functions := make(routeTable, len(s.config.Functions))
+	for _, funcName := range s.registry.Names() {
+		_, funcDef, _ := s.registry.Lookup(funcName)
+		functions["/api/"+funcName] = handleDeprecated(funcDef, s.shadowed(funcName, s.handleFunction(funcName, funcDef)))
+		if funcDef.Watchable {
+			functions["/api/"+funcName+"/watch"] = handleDeprecated(funcDef, s.handleWatch(funcName, funcDef))
+		}
+
+		// Versions are also served at /api/v{N}/{name}
+		if route, ok := ont.VersionedRoute(funcName, funcDef); ok {
+			functions["/api/"+route] = functions["/api/"+funcName]
+			if funcDef.Watchable {
+				functions["/api/"+route+"/watch"] = functions["/api/"+funcName+"/watch"]
+			}
+		}
+
+		// Keep serving the function under its former names
+		for _, alias := range funcDef.Aliases {
+			functions["/api/"+alias] = s.handleAlias(alias, funcName, s.shadowed(funcName, s.handleFunction(funcName, funcDef)))
+			if funcDef.Watchable {
+				functions["/api/"+alias+"/watch"] = s.handleAlias(alias, funcName, s.handleWatch(funcName, funcDef))
+			}
+		}
+	}
+
+	// Calls wait for the warm-up
+	for path, handler := range functions {
+		functions[path] = s.whenWarm(handler)
 	}
 
 	// MCP endpoint using official SDK
 	mcpHandler := s.createMCPHandler()
-	mux.Handle("/mcp", mcpHandler)
+	mux.Handle("/mcp", s.whenWarm(mcpHandler))
+
+	if s.graphViewer {
+		s.registerGraphViewer(mux)
+	}
+	mux.HandleFunc("/api", s.handleIntrospection)
+	mux.HandleFunc("/ontology/query", s.handleOntologyQuery)
+	mux.HandleFunc("/ontology/graph", s.handleOntologyGraph)
+	if len(s.config.Events) > 0 {
+		mux.Handle(EventsPath, s.whenWarm(http.HandlerFunc(s.handleEvents)))
+	}
+	if len(s.config.PublicConfig) > 0 {
+		mux.HandleFunc("/config.json", s.handlePublicConfig)
+	}
 
 	// Health check
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
-	})
+	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("/health/ready", s.handleReady)
+	mux.HandleFunc("/health/info", s.handleHealthInfo)
 
 	// Static file serving (for production builds with embedded frontend)
 	if s.staticFS != nil {
@@ -139,83 +255,327 @@ func (s *Server) Handler() http.Handler {
 				return
 			}
 
-			// Serve index.html
+			// Serve index.html, with the public config if there is one. It
+			// has no modification time then, as the config can change.
+			if len(s.config.PublicConfig) > 0 {
+				html, err := io.ReadAll(f)
+				if err != nil {
+					http.Error(w, "Failed to read index.html", http.StatusInternalServerError)
+					return
+				}
+				http.ServeContent(w, r, "index.html", time.Time{}, bytes.NewReader(s.injectPublicConfig(html)))
+				return
+			}
 			http.ServeContent(w, r, "index.html", stat.ModTime(), f.(io.ReadSeeker))
 		}))
 	}
 
-	return mux
+	return s.proxied(functions.with(mux))
 }
 
 // Serve starts the server on the given address.
 func (s *Server) Serve(addr string) error {
-	// Cloud registration (if enabled)
-	if s.config.Cloud && s.config.UUID != "" {
-		cloud.TryRegisterWithCloud(s.config.UUID, s.config)
-	}
+	s.registerWithCloud()
 
-	log.Printf("Starting server on %s", addr)
-	return http.ListenAndServe(addr, s.Handler())
+	if addr == "" {
+		addr = ":http"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.reportStartup(l)
+	return s.serveWarm(s.HTTPServer(addr), l)
 }
 
 func (s *Server) handleFunction(name string, fn ont.Function) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Only allow POST
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		start := time.Now()
+
+		authResult, input, ok := s.readCall(w, r, name, fn)
+		if !ok {
 			return
 		}
 
-		// Authenticate
-		authResult, err := s.authFunc(r)
+		// Call resolver
+		ctx := s.callContext(r, name, authResult, ensureRequestID(r), "")
+		warnDeprecated(r.Context(), ctx, name, fn, input)
+		output, err := s.execute(ctx, name, fn, input, s.streamsOutput(r, fn))
 		if err != nil {
-			http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+			s.writeExecuteError(w, name, fn, err)
 			return
 		}
 
-		// Check access
-		if !fn.CheckAccess(authResult.AccessGroups) {
-			http.Error(w, "Access denied", http.StatusForbidden)
-			return
+		// Watchable functions support conditional requests on their cursor
+		if fn.Watchable {
+			if cursor, ok := ont.WatchCursor(output); ok {
+				if ont.ParseETag(r.Header.Get("If-None-Match")) == cursor {
+					w.Header().Set("ETag", ont.FormatETag(cursor))
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			}
 		}
 
-		// Parse input
-		var input map[string]any
-		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
-			return
+		s.writeOutput(w, r, name, fn, ctx, output, start)
+	}
+}
+
+// readCall authenticates a REST call, checks access and decodes and
+// validates its input. It writes the error response and returns false on failure.
+func (s *Server) readCall(w http.ResponseWriter, r *http.Request, name string, fn ont.Function) (*AuthResult, map[string]any, bool) {
+	// Only allow POST, and GET for read-only functions
+	if !allowsMethod(w, r, fn) {
+		return nil, nil, false
+	}
+	noStore(w, fn)
+
+	// Authenticate
+	authResult, err := s.authFunc(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return nil, nil, false
+	}
+
+	// Check access
+	if !fn.CheckAccess(authResult.AccessGroups) {
+		s.accessDenied(r, name, DeniedAccessGroups, authResult)
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return nil, nil, false
+	}
+	if !s.checkStability(r, fn, authResult.AccessGroups) {
+		s.accessDenied(r, name, DeniedExperimental, authResult)
+		http.Error(w, fmt.Sprintf("Experimental function: send %s: true to opt in", ExperimentalHeader), http.StatusForbidden)
+		return nil, nil, false
+	}
+	if !checkOutputVersion(w, r, fn) {
+		return nil, nil, false
+	}
+	if ok, wait := s.checkRateLimit(r.Context(), name, fn, authResult.AccessGroups); !ok {
+		writeRateLimited(w, wait)
+		return nil, nil, false
+	}
+
+	// Parse input, keeping integers beyond 2^53 exact for Int64 fields.
+	// GET calls send it as query parameters.
+	var input map[string]any
+	if r.Method == http.MethodGet {
+		input = queryInput(r, fn, s.fieldNaming)
+	} else {
+		body, format, err := s.decodeBody(r)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid %s: %v", format, err), http.StatusBadRequest)
+			return nil, nil, false
+		}
+		var ok bool
+		input, ok = body.(map[string]any)
+		if body != nil && !ok {
+			http.Error(w, fmt.Sprintf("Invalid %s: expected an object", format), http.StatusBadRequest)
+			return nil, nil, false
 		}
+	}
 
-		// Validate input
-		if err := fn.ValidateInput(input); err != nil {
-			http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
-			return
+	// Translate wire field names to authored names
+	if translated, ok := ont.FromWireNames(fn.Inputs, input, s.fieldNaming).(map[string]any); ok {
+		input = translated
+	}
+	ont.KeepInt64s(fn.CallInputs(), input)
+	if r.Method == http.MethodGet {
+		if coerced, ok := ont.Coerce(fn.Inputs, input).(map[string]any); ok {
+			input = coerced
 		}
+	}
+	input, ok := mergeParams(w, r, fn, input)
+	if !ok {
+		return nil, nil, false
+	}
+	input = s.coerceInput(fn, input)
 
-		// Call resolver
-		ctx := ont.NewContext(r, s.logger, authResult.AccessGroups, authResult.UserContext)
-		output, err := fn.Resolver(ctx, input)
+	// Validate input
+	if err := s.validateInput(fn, input); err != nil {
+		s.writeInvalidInput(w, err)
+		return nil, nil, false
+	}
+
+	// Check the access rule, which may depend on the input
+	if ok, err := fn.CheckAccessRule(authResult.AccessGroups, authResult.UserContext, input); !ok {
+		if err != nil {
+			s.logger.Warn("Access rule failed", "rule", fn.AccessRule, "error", err)
+		}
+		s.accessDenied(r, name, DeniedAccessRule, authResult)
+		http.Error(w, "Access denied", http.StatusForbidden)
+		return nil, nil, false
+	}
+
+	return authResult, input, true
+}
+
+// writeExecuteError maps an error from execute to a REST response.
+func (s *Server) writeExecuteError(w http.ResponseWriter, name string, fn ont.Function, err error) {
+	var inErr *inputError
+	if errors.As(err, &inErr) {
+		http.Error(w, fmt.Sprintf("Invalid input: %v", err), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, ont.ErrTimeout) {
+		s.writeFailure(w, name, fn, err.Error(), http.StatusGatewayTimeout)
+		return
+	}
+	s.writeFailure(w, name, fn, err.Error(), http.StatusInternalServerError)
+}
+
+// writeOutput encodes a resolver's output as the REST response.
+func (s *Server) writeOutput(w http.ResponseWriter, r *http.Request, name string, fn ont.Function, ctx ont.Context, output any, start time.Time) {
+	// Batches with failed items respond 207 Multi-Status
+	status := http.StatusOK
+	if ont.BatchHasFailures(output) {
+		status = http.StatusMultiStatus
+	}
+
+	if fn.Watchable {
+		if cursor, ok := ont.WatchCursor(output); ok {
+			w.Header().Set("ETag", ont.FormatETag(cursor))
+		}
+	}
+
+	// Convert to the output version the caller asked for
+	schema, contentType := fn.Outputs, "application/json"
+	if versioned(fn) && len(s.codecs) == 0 {
+		w.Header().Add("Vary", "Accept")
+	}
+	if version := outputVersion(r); version != "" && fn.HasOutputVersion(version) {
+		downgraded, err := fn.DowngradeOutput(version, output)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			s.logger.Error("Output version conversion failed", "version", version, "error", err)
+			http.Error(w, "Failed to convert output to the requested version", http.StatusInternalServerError)
 			return
 		}
+		output, schema = downgraded, fn.OutputSchemaFor(version)
+		contentType = mime.FormatMediaType("application/json", map[string]string{"profile": version})
+	}
 
-		// Validate output
-		if err := fn.ValidateOutput(output); err != nil {
-			s.logger.Error("Output validation failed", "function", name, "error", err)
-			// In development, you might want to return this error
-			// In production, just log it and continue
+	// Translate authored field names to wire names
+	authored := output
+	output = ont.ToWireNames(schema, output, s.fieldNaming)
+
+	var body any = output
+	if s.envelope {
+		id := requestID(r)
+		w.Header().Set(RequestIDHeader, id)
+		body = newEnvelope(output, id, start, ctx.Warnings())
+	} else {
+		setWarningsHeader(w, ctx.Warnings())
+	}
+
+	if len(s.codecs) > 0 {
+		w.Header().Add("Vary", "Accept")
+		if c := s.responseCodec(r); c != nil && s.writeEncoded(w, c, status, body) {
+			return
 		}
+	}
 
-		// Initialize nil slices to prevent JSON null
-		output = ont.InitializeNilSlices(output)
+	// Large results are served from object storage
+	if s.offload != nil {
+		s.writeOffloaded(r.Context(), w, status, contentType, body)
+		return
+	}
 
-		// Send response
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(output); err != nil {
+	// Send response
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if !s.streamsOutput(r, fn) || fn.SkipOutputValidation {
+		if err := json.NewEncoder(w).Encode(body); err != nil {
 			s.logger.Error("Failed to encode response", "error", err)
 		}
+		return
 	}
+
+	// Validate array items as they are encoded
+	encoder := ont.NewStreamEncoder(w, schema, s.fieldNaming)
+	if err := encoder.Encode(authored); err != nil {
+		s.logger.Error("Failed to encode response", "error", err)
+	}
+	if err := encoder.Invalid(); err != nil {
+		s.logger.Error("Output validation failed", "function", name, "error", err)
+	}
+}
+
+// writesPlainJSON reports whether the server's options let writeOutput
+// stream outputs: no envelope, codecs or offload.
+func (s *Server) writesPlainJSON() bool {
+	return !s.envelope && len(s.codecs) == 0 && s.offload == nil
+}
+
+// streamsOutput reports whether writeOutput encodes the output as plain
+// JSON, validating it as it goes, so execute needn't validate it first.
+func (s *Server) streamsOutput(r *http.Request, fn ont.Function) bool {
+	if !s.writesPlainJSON() {
+		return false
+	}
+	version := outputVersion(r)
+	return version == "" || !fn.HasOutputVersion(version)
+}
+
+// inputError marks failures caused by the caller's input rather than the resolver.
+type inputError struct {
+	err error
+}
+
+func (e *inputError) Error() string {
+	return e.err.Error()
+}
+
+func (e *inputError) Unwrap() error {
+	return e.err
+}
+
+// execute runs a function on validated input: input transform, resolver,
+// output transform, computed fields and output validation. The returned output has its nil
+// slices initialized and is ready to encode. Streamed skips output
+// validation because the caller validates while encoding (see streamsOutput).
+func (s *Server) execute(ctx ont.Context, name string, fn ont.Function, callInput any, streamed bool) (any, error) {
+	if err := s.initialize(ctx, name); err != nil {
+		return nil, err
+	}
+
+	input, err := fn.ApplyInputTransform(ctx, callInput)
+	if err != nil {
+		return nil, &inputError{err: err}
+	}
+
+	output, err := s.config.Resolve(ctx, fn, input)
+	if err != nil {
+		s.functionFailed(ctx, name, fn, err)
+		return nil, err
+	}
+
+	output, err = fn.ApplyOutputTransform(ctx, output)
+	if err != nil {
+		s.logger.Error("Output transform failed", "function", name, "error", err)
+		s.functionFailed(ctx, name, fn, err)
+		return nil, err
+	}
+
+	output, err = ont.ApplyComputed(fn.Outputs, output)
+	if err != nil {
+		s.logger.Error("Computing output fields failed", "function", name, "error", err)
+		s.functionFailed(ctx, name, fn, err)
+		return nil, err
+	}
+
+	// Validate output, unless it is trusted or validated while encoding
+	if !fn.SkipOutputValidation && !streamed {
+		if err := fn.ValidateOutput(output); err != nil {
+			s.logger.Error("Output validation failed", "function", name, "error", err)
+			// In development, you might want to return this error
+			// In production, just log it and continue
+		}
+	}
+
+	// Initialize nil slices to prevent JSON null
+	output = ont.InitializeNilSlices(output)
+	s.recordTranscript(name, fn, callInput, output)
+	return output, nil
 }
 
 // contextKey is a type for context keys in this package.
@@ -238,6 +598,7 @@ func (s *Server) createMCPHandler() http.Handler {
 			Instructions: s.config.Instructions,
 		}
 	}
+	opts = s.withEventSubscriptions(opts)
 
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    s.config.Name,
@@ -245,25 +606,42 @@ func (s *Server) createMCPHandler() http.Handler {
 		Version: version,
 	}, opts)
 
+	// Callers only see the tools their access groups may call
+	mcpServer.AddReceivingMiddleware(s.listCallableTools(&toolIndex{}))
+
 	// Track whether any tools have UI enabled
 	hasUITools := false
 
 	// Add tools for each function
 	for name, fn := range s.config.Functions {
 		// Skip functions that should not be included in MCP listTools
-		if !fn.IncludeInMcpListTools {
+		if !fn.IncludeInMcpListTools || fn.Internal {
 			continue
 		}
 
-		toolName := name
+		// Older versions are named like getUserV1
+		toolName := ont.FunctionIdentifier(name)
 		funcDef := fn
 
 		// Create tool with JSON Schema
+		outputSchema := s.config.JSONSchemaFor(funcDef.Outputs)
+		if s.envelope {
+			outputSchema = envelopeJSONSchema(outputSchema)
+		}
+		inputSchema := s.config.JSONSchemaFor(funcDef.CallInputs())
+		if s.strictInputs {
+			inputSchema = strictJSONSchema(inputSchema)
+		}
+		if s.coerceInputs {
+			inputSchema = coercibleJSONSchema(inputSchema)
+		}
+		description := s.describeTool(name, funcDef, funcDef.Description)
 		tool := &mcp.Tool{
 			Name:         toolName,
-			Description:  funcDef.Description,
-			InputSchema:  funcDef.Inputs.JSONSchema(),
-			OutputSchema: funcDef.Outputs.JSONSchema(),
+			Description:  description,
+			InputSchema:  inputSchema,
+			OutputSchema: outputSchema,
+			Annotations:  toolAnnotations(funcDef),
 		}
 
 		// Add UI metadata if enabled
@@ -278,8 +656,39 @@ func (s *Server) createMCPHandler() http.Handler {
 			}
 		}
 
+		// Add tags so clients can group tools
+		if len(funcDef.Tags) > 0 {
+			if tool.Meta == nil {
+				tool.Meta = mcp.Meta{}
+			}
+			tool.Meta["tags"] = funcDef.Tags
+		}
+
 		// Add the tool with a handler
-		mcp.AddTool(mcpServer, tool, s.createMCPToolHandler(toolName, funcDef))
+		handler := s.createMCPToolHandler(name, funcDef)
+		mcp.AddTool(mcpServer, tool, handler)
+
+		// Former names stay available as deprecated tools
+		for _, alias := range funcDef.Aliases {
+			aliasTool := *tool
+			aliasTool.Name = alias
+			aliasTool.Description = aliasDescription(toolName, description)
+			aliasName := alias
+			mcp.AddTool(mcpServer, &aliasTool, func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+				return handler(context.WithValue(ctx, aliasKey, aliasName), req, args)
+			})
+		}
+	}
+
+	s.addEventResources(mcpServer)
+
+	if s.transcripts != nil {
+		mcpServer.AddResource(&mcp.Resource{
+			URI:         ExamplesResourceURI,
+			Name:        "Tool examples",
+			Description: "Example calls of the tools, with their input and output",
+			MIMEType:    "application/json",
+		}, s.readExamples)
 	}
 
 	// Register MCP resources for UI-enabled tools
@@ -312,6 +721,7 @@ func (s *Server) createMCPHandler() http.Handler {
 		// Add individual resources for each UI-enabled tool
 		for name, fn := range s.config.Functions {
 			if fn.UI != nil {
+				name := ont.FunctionIdentifier(name)
 				mcpServer.AddResource(&mcp.Resource{
 					URI:         "ui://ont-visualizer/" + name,
 					Name:        name + " Visualizer",
@@ -334,9 +744,29 @@ func (s *Server) createMCPHandler() http.Handler {
 	})
 }
 
+// toolDescription returns the MCP tool description of fn, with its
+// stability badge and deprecation notice ahead of description.
+func toolDescription(fn ont.Function, description string) string {
+	if badge := fn.Stability.Badge(); badge != "" {
+		description = badge + " " + description
+	}
+	if fn.Deprecated != "" {
+		description = fmt.Sprintf("Deprecated: %s. %s", strings.TrimSuffix(fn.Deprecated, "."), description)
+	}
+	return description
+}
+
+// aliasDescription returns the description of the tool for a former name
+// of function name.
+func aliasDescription(name, description string) string {
+	return fmt.Sprintf("Deprecated: renamed to %s. %s", name, description)
+}
+
 // createMCPToolHandler creates an MCP tool handler for a given function.
 func (s *Server) createMCPToolHandler(name string, fn ont.Function) func(context.Context, *mcp.CallToolRequest, map[string]any) (*mcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+		start := time.Now()
+
 		// Extract real HTTP request from context (injected by createMCPHandler wrapper)
 		httpReq, _ := ctx.Value(httpRequestKey).(*http.Request)
 		if httpReq == nil {
@@ -351,29 +781,55 @@ func (s *Server) createMCPToolHandler(name string, fn ont.Function) func(context
 
 		// Check access
 		if !fn.CheckAccess(authResult.AccessGroups) {
+			s.accessDenied(httpReq, name, DeniedAccessGroups, authResult)
 			return nil, nil, fmt.Errorf("access denied")
 		}
+		if !s.checkStability(httpReq, fn, authResult.AccessGroups) {
+			s.accessDenied(httpReq, name, DeniedExperimental, authResult)
+			return nil, nil, fmt.Errorf("experimental function: send %s: true to opt in", ExperimentalHeader)
+		}
+		if ok, wait := s.checkRateLimit(ctx, name, fn, authResult.AccessGroups); !ok {
+			return nil, nil, fmt.Errorf("rate limit exceeded: retry in %ds", retryAfter(wait))
+		}
+
+		// The SDK decodes arguments as float64; decode the raw arguments
+		// again so Int64 fields keep their precision
+		if len(req.Params.Arguments) > 0 {
+			if raw, err := ont.DecodeJSON(bytes.NewReader(req.Params.Arguments)); err == nil {
+				if precise, ok := ont.KeepInt64s(fn.CallInputs(), raw).(map[string]any); ok {
+					args = precise
+				}
+			}
+		}
 
 		// Validate input
-		if err := fn.ValidateInput(args); err != nil {
-			return nil, nil, fmt.Errorf("invalid input: %v", err)
+		args = s.coerceInput(fn, args)
+		if err := s.validateInput(fn, args); err != nil {
+			return invalidInputResult(err), nil, nil
+		}
+
+		// Check the access rule, which may depend on the input
+		if ok, err := fn.CheckAccessRule(authResult.AccessGroups, authResult.UserContext, args); !ok {
+			if err != nil {
+				s.logger.Warn("Access rule failed", "rule", fn.AccessRule, "error", err)
+			}
+			s.accessDenied(httpReq, name, DeniedAccessRule, authResult)
+			return nil, nil, fmt.Errorf("access denied")
 		}
 
 		// Call resolver
-		resolverCtx := ont.NewContext(httpReq, s.logger, authResult.AccessGroups, authResult.UserContext)
-		output, err := fn.Resolver(resolverCtx, args)
+		var sessionID string
+		if req.Session != nil {
+			sessionID = req.Session.ID()
+		}
+		id := requestID(httpReq)
+		resolverCtx := s.callContext(httpReq, name, authResult, id, sessionID)
+		warnDeprecated(ctx, resolverCtx, name, fn, args)
+		output, err := s.execute(resolverCtx, name, fn, args, false)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		// Validate output
-		if err := fn.ValidateOutput(output); err != nil {
-			s.logger.Error("Output validation failed", "function", name, "error", err)
-		}
-
-		// Initialize nil slices
-		output = ont.InitializeNilSlices(output)
-
 		// Return result as text content
 		outputJSON, err := json.Marshal(output)
 		if err != nil {
@@ -382,7 +838,16 @@ func (s *Server) createMCPToolHandler(name string, fn ont.Function) func(context
 
 		// Build structured content for UI-enabled tools
 		var structuredOutput any = output
-		if fn.UI != nil {
+		if s.envelope {
+			env := newEnvelope(output, id, start, resolverCtx.Warnings())
+			structured := map[string]any{"data": env.Data, "meta": env.Meta}
+			if fn.UI != nil {
+				if uiConfig := uiConfigMap(fn.UI); uiConfig != nil {
+					structured["_uiConfig"] = uiConfig
+				}
+			}
+			structuredOutput = structured
+		} else if fn.UI != nil {
 			structured := make(map[string]any)
 
 			// If the result is a slice, wrap it in {data: ...}
@@ -397,29 +862,21 @@ func (s *Server) createMCPToolHandler(name string, fn ont.Function) func(context
 			}
 
 			// Include UI config for the visualizer app
-			if fn.UI.Type != "" || fn.UI.ChartType != "" || fn.UI.XAxis != "" || len(fn.UI.LeftYAxis) > 0 || len(fn.UI.RightYAxis) > 0 {
-				uiConfig := map[string]any{}
-				if fn.UI.Type != "" {
-					uiConfig["type"] = fn.UI.Type
-				}
-				if fn.UI.ChartType != "" {
-					uiConfig["chartType"] = fn.UI.ChartType
-				}
-				if fn.UI.XAxis != "" {
-					uiConfig["xAxis"] = fn.UI.XAxis
-				}
-				if len(fn.UI.LeftYAxis) > 0 {
-					uiConfig["leftYAxis"] = fn.UI.LeftYAxis
-				}
-				if len(fn.UI.RightYAxis) > 0 {
-					uiConfig["rightYAxis"] = fn.UI.RightYAxis
-				}
+			if uiConfig := uiConfigMap(fn.UI); uiConfig != nil {
 				structured["_uiConfig"] = uiConfig
 			}
 
 			structuredOutput = structured
 		}
 
+		// Surface resolver warnings next to the result, like _uiConfig
+		if warnings := resolverCtx.Warnings(); len(warnings) > 0 && !s.envelope {
+			if structured := toObject(structuredOutput); structured != nil {
+				structured["_warnings"] = warnings
+				structuredOutput = structured
+			}
+		}
+
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
 				&mcp.TextContent{Text: string(outputJSON)},
@@ -434,6 +891,44 @@ func Serve(config *ont.Config, addr string, opts ...ServerOption) error {
 	return server.Serve(addr)
 }
 
+// uiConfigMap converts a UiConfig to the map sent to the visualizer app.
+// Returns nil if no settings are configured.
+func uiConfigMap(ui *ont.UiConfig) map[string]any {
+	if ui.Type == "" && ui.ChartType == "" && ui.XAxis == "" && len(ui.LeftYAxis) == 0 && len(ui.RightYAxis) == 0 {
+		return nil
+	}
+	uiConfig := map[string]any{}
+	if ui.Type != "" {
+		uiConfig["type"] = ui.Type
+	}
+	if ui.ChartType != "" {
+		uiConfig["chartType"] = ui.ChartType
+	}
+	if ui.XAxis != "" {
+		uiConfig["xAxis"] = ui.XAxis
+	}
+	if len(ui.LeftYAxis) > 0 {
+		uiConfig["leftYAxis"] = ui.LeftYAxis
+	}
+	if len(ui.RightYAxis) > 0 {
+		uiConfig["rightYAxis"] = ui.RightYAxis
+	}
+	return uiConfig
+}
+
+// toObject converts a value to its JSON object form, or nil if it isn't an object.
+func toObject(v any) map[string]any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+	return result
+}
+
 // isSlice checks if a value is a slice type.
 func isSlice(v any) bool {
 	if v == nil {
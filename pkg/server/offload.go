@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// offloadConfig moves large REST responses to object storage.
+type offloadConfig struct {
+	objects   store.ObjectStore
+	threshold int
+	ttl       time.Duration
+}
+
+// WithResultOffload stores REST responses larger than threshold bytes in
+// objects and answers 303 See Other with a signed URL valid for ttl, instead
+// of inlining megabytes of JSON. fetch follows the redirect, so generated
+// clients are unaffected. MCP responses are always inlined.
+//
+// Results are stored under results/ and deleted once their URL expires.
+// Results outliving the process that stored them are not, so also give
+// the bucket a lifecycle rule expiring results/ after ttl.
+func WithResultOffload(objects store.ObjectStore, threshold int, ttl time.Duration) ServerOption {
+	return func(s *Server) {
+		s.offload = &offloadConfig{objects: objects, threshold: threshold, ttl: ttl}
+	}
+}
+
+// writeOffloaded encodes body once and, if it exceeds the threshold,
+// uploads it and redirects to it. Smaller bodies, and bodies that fail to
+// upload, are written inline with status and contentType.
+func (s *Server) writeOffloaded(ctx context.Context, w http.ResponseWriter, status int, contentType string, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		s.logger.Error("Failed to encode response", "error", err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	if len(data) > s.offload.threshold {
+		if url, ok := s.offloadResult(ctx, data); ok {
+			w.Header().Set("Location", url)
+			w.WriteHeader(http.StatusSeeOther)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+// offloadResult uploads data and returns a signed URL to it, scheduling
+// its deletion when the URL expires.
+func (s *Server) offloadResult(ctx context.Context, data []byte) (string, bool) {
+	key := "results/" + store.NewID() + ".json"
+	if err := s.offload.objects.Put(ctx, key, bytes.NewReader(data), "application/json"); err != nil {
+		s.logger.Error("Failed to offload result", "error", err)
+		return "", false
+	}
+	url, err := s.offload.objects.SignedURL(ctx, key, s.offload.ttl)
+	if err != nil {
+		s.logger.Error("Failed to sign offloaded result", "error", err)
+		s.deleteOffloaded(key)
+		return "", false
+	}
+
+	time.AfterFunc(s.offload.ttl, func() { s.deleteOffloaded(key) })
+	return url, true
+}
+
+// deleteOffloaded removes an offloaded result.
+func (s *Server) deleteOffloaded(key string) {
+	if err := s.offload.objects.Delete(context.Background(), key); err != nil {
+		s.logger.Error("Failed to delete offloaded result", "key", key, "error", err)
+	}
+}
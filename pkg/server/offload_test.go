@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+	"github.com/vanna-ai/ont-run/pkg/store/localfs"
+)
+
+// offloadedKey returns the object key a 303 from WithResultOffload points
+// at, served by localfs under /objects.
+func offloadedKey(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	if resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("status %d, want 303", resp.StatusCode)
+	}
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimPrefix(location.Path, "/objects/")
+}
+
+func TestResultOffload(t *testing.T) {
+	objects, err := localfs.New(t.TempDir(), "http://localhost/objects", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(testConfig(), WithResultOffload(objects, 64, time.Minute)).Handler()
+
+	// Small responses are left alone
+	resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Location") != "" {
+		t.Fatalf("small response: status %d, Location %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("small response: Content-Type %q", ct)
+	}
+	if body != "{\"message\":\"hi\"}\n" {
+		t.Errorf("small response body = %q", body)
+	}
+
+	// Large ones redirect to a signed URL serving the same JSON
+	message := strings.Repeat("x", 100)
+	resp, _ = do(t, h, "POST", "/api/echo", `{"message":"`+message+`"}`)
+	key := offloadedKey(t, resp)
+	if !strings.HasPrefix(key, "results/") {
+		t.Errorf("offloaded key %q, want it under results/", key)
+	}
+	files := http.StripPrefix("/objects", objects.Handler())
+	resp, body = do(t, files, "GET", resp.Header.Get("Location"), "")
+	if resp.StatusCode != http.StatusOK || body != `{"message":"`+message+`"}` {
+		t.Errorf("signed URL: status %d, body %q", resp.StatusCode, body)
+	}
+}
+
+func TestResultOffloadExpires(t *testing.T) {
+	objects, err := localfs.New(t.TempDir(), "http://localhost/objects", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New(testConfig(), WithResultOffload(objects, 64, 10*time.Millisecond)).Handler()
+
+	resp, _ := do(t, h, "POST", "/api/echo", `{"message":"`+strings.Repeat("x", 100)+`"}`)
+	key := offloadedKey(t, resp)
+	for deadline := time.Now().Add(2 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		r, err := objects.Get(context.Background(), key)
+		if errors.Is(err, store.ErrNotFound) {
+			return
+		}
+		if err == nil {
+			r.Close()
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("offloaded result %s not deleted after its URL expired", key)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// mergeParams adds a function's Headers and Query values from r to the
+// body input. Body fields with those names are rejected, so a caller can't
+// bypass a header by sending it in the body.
+func mergeParams(w http.ResponseWriter, r *http.Request, fn ont.Function, input map[string]any) (map[string]any, bool) {
+	if fn.Headers == nil && fn.Query == nil {
+		return input, true
+	}
+	for key := range input {
+		if fn.IsParam(key) {
+			http.Error(w, fmt.Sprintf("Invalid input: '%s' must be sent as a header or query parameter", key), http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	if input == nil {
+		input = make(map[string]any)
+	}
+	for name, value := range fn.RequestParams(r) {
+		input[name] = value
+	}
+	return input, true
+}
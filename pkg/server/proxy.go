@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// proxyConfig holds the reverse proxies whose X-Forwarded-* headers are
+// trusted.
+type proxyConfig struct {
+	trusted []netip.Prefix
+}
+
+// forwarded is what a request's trusted proxy headers say about the client.
+type forwarded struct {
+	clientIP string
+	scheme   string
+}
+
+type forwardedKey struct{}
+
+// WithProxyHeaders trusts the X-Forwarded-For, X-Forwarded-Proto and
+// X-Forwarded-Host headers of requests from the given proxies, as CIDRs
+// ("10.0.0.0/8") or addresses ("127.0.0.1"). ClientIP, Scheme and BaseURL
+// then describe the original client request rather than the proxy's, and
+// r.Host is the forwarded host. Headers from other peers are ignored, so
+// clients can't spoof them. WithProxyHeaders panics on an invalid CIDR.
+func WithProxyHeaders(trustedCIDRs ...string) ServerOption {
+	proxy := &proxyConfig{}
+	for _, cidr := range trustedCIDRs {
+		prefix, err := parsePrefix(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("server: WithProxyHeaders: %v", err))
+		}
+		proxy.trusted = append(proxy.trusted, prefix)
+	}
+	return func(s *Server) {
+		s.proxy = proxy
+	}
+}
+
+// parsePrefix parses a CIDR or a single address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", s)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid address %q", s)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func (p *proxyConfig) trusts(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxied applies trusted proxy headers before next sees the request.
+func (s *Server) proxied(next http.Handler) http.Handler {
+	if s.proxy == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer := remoteIP(r)
+		if !s.proxy.trusts(peer) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		fwd := forwarded{clientIP: peer}
+		// The client is the last hop not added by a trusted proxy
+		hops := headerList(r.Header.Values("X-Forwarded-For"))
+		for i := len(hops) - 1; i >= 0; i-- {
+			if _, err := netip.ParseAddr(hops[i]); err != nil {
+				break
+			}
+			fwd.clientIP = hops[i]
+			if !s.proxy.trusts(hops[i]) {
+				break
+			}
+		}
+		if proto := strings.ToLower(firstValue(r.Header.Get("X-Forwarded-Proto"))); proto == "http" || proto == "https" {
+			fwd.scheme = proto
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), forwardedKey{}, fwd))
+		if host := firstValue(r.Header.Get("X-Forwarded-Host")); host != "" {
+			r.Host = host
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// headerList splits comma-separated header values into trimmed items.
+func headerList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// firstValue returns the first item of a comma-separated header, which
+// the proxy nearest the client set.
+func firstValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(first)
+}
+
+// remoteIP returns the IP of the request's peer.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ClientIP returns the IP address of the client that made r: the peer's
+// address, or the forwarded address if the peer is a proxy trusted with
+// WithProxyHeaders. Use it for logging, rate limiting and IP policies, e.g.
+// ClientIP(ctx.Request()) in a resolver.
+func ClientIP(r *http.Request) string {
+	if fwd, ok := r.Context().Value(forwardedKey{}).(forwarded); ok {
+		return fwd.clientIP
+	}
+	return remoteIP(r)
+}
+
+// Scheme returns "https" or "http" for the client's request, taking a
+// trusted X-Forwarded-Proto into account.
+func Scheme(r *http.Request) string {
+	if fwd, ok := r.Context().Value(forwardedKey{}).(forwarded); ok && fwd.scheme != "" {
+		return fwd.scheme
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// BaseURL returns the scheme and host the client used, e.g.
+// "https://api.example.com", for building absolute URLs such as links in
+// responses.
+func BaseURL(r *http.Request) string {
+	return Scheme(r) + "://" + r.Host
+}
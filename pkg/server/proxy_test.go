@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// clientServer returns a server whose echo function answers with what
+// ClientIP and BaseURL report for the call.
+func clientServer(opts ...ServerOption) *Server {
+	config := testConfig()
+	fn := config.Functions["echo"]
+	fn.Resolver = func(ctx ont.Context, input any) (any, error) {
+		r := ctx.Request()
+		return map[string]any{"message": ClientIP(r) + " " + BaseURL(r)}, nil
+	}
+	config.Functions["echo"] = fn
+	return New(config, opts...)
+}
+
+// callFrom calls echo from the peer address with the given forwarded
+// headers and returns its message.
+func callFrom(t *testing.T, h http.Handler, peer string, headers ...string) string {
+	t.Helper()
+	r := httptest.NewRequest("POST", "http://api.internal/api/echo", strings.NewReader(`{"message":""}`))
+	r.RemoteAddr = peer
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Add(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	message, _, _ := strings.Cut(strings.TrimPrefix(w.Body.String(), `{"message":"`), `"`)
+	return message
+}
+
+var forwardedHeaders = []string{
+	"X-Forwarded-For", "203.0.113.7",
+	"X-Forwarded-Proto", "https",
+	"X-Forwarded-Host", "api.example.com",
+}
+
+func TestProxyHeadersFromTrustedPeers(t *testing.T) {
+	h := clientServer(WithProxyHeaders("10.0.0.0/8", "192.168.1.1")).Handler()
+	for _, peer := range []string{"10.1.2.3:4000", "192.168.1.1:4000", "[::ffff:10.0.0.1]:4000"} {
+		if got := callFrom(t, h, peer, forwardedHeaders...); got != "203.0.113.7 https://api.example.com" {
+			t.Errorf("from %s: %q, want the forwarded client", peer, got)
+		}
+	}
+
+	// The client is the last hop that isn't a trusted proxy
+	got := callFrom(t, h, "10.1.2.3:4000", "X-Forwarded-For", "198.51.100.1, 203.0.113.7", "X-Forwarded-For", "10.9.9.9")
+	if got != "203.0.113.7 http://api.internal" {
+		t.Errorf("chained proxies: %q", got)
+	}
+	// Unsupported schemes are ignored
+	if got := callFrom(t, h, "10.1.2.3:4000", "X-Forwarded-Proto", "gopher"); got != "10.1.2.3 http://api.internal" {
+		t.Errorf("unsupported scheme: %q", got)
+	}
+}
+
+func TestProxyHeadersFromUntrustedPeers(t *testing.T) {
+	h := clientServer(WithProxyHeaders("10.0.0.0/8")).Handler()
+	if got := callFrom(t, h, "203.0.113.9:4000", forwardedHeaders...); got != "203.0.113.9 http://api.internal" {
+		t.Errorf("untrusted peer: %q, want its own address", got)
+	}
+
+	// Without WithProxyHeaders no peer is trusted
+	h = clientServer().Handler()
+	if got := callFrom(t, h, "10.1.2.3:4000", forwardedHeaders...); got != "10.1.2.3 http://api.internal" {
+		t.Errorf("no trusted proxies: %q", got)
+	}
+}
+
+func TestWithProxyHeadersPanicsOnInvalidCIDR(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithProxyHeaders accepted an invalid CIDR")
+		}
+	}()
+	WithProxyHeaders("10.0.0.0/99")
+}
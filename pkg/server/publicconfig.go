@@ -0,0 +1,46 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// PublicConfigGlobal is the global the SPA's index.html gets the public
+// config in, so frontends can read it without fetching /config.json.
+const PublicConfigGlobal = "__ONT_CONFIG__"
+
+// handlePublicConfig serves GET /config.json, Config.PublicConfig. It
+// doesn't require authentication.
+func (s *Server) handlePublicConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	json.NewEncoder(w).Encode(s.config.PublicConfig)
+}
+
+// injectPublicConfig adds a script setting window.__ONT_CONFIG__ to the
+// head of an HTML page. The JSON encoder escapes "<", ">" and "&", so the
+// values can't close the script.
+func (s *Server) injectPublicConfig(html []byte) []byte {
+	if len(s.config.PublicConfig) == 0 {
+		return html
+	}
+	data, err := json.Marshal(s.config.PublicConfig)
+	if err != nil {
+		return html
+	}
+	script := []byte("<script>window." + PublicConfigGlobal + " = " + string(data) + ";</script>")
+
+	at := bytes.Index(bytes.ToLower(html), []byte("</head>"))
+	if at < 0 {
+		return append(script, html...)
+	}
+	result := make([]byte, 0, len(html)+len(script))
+	result = append(result, html[:at]...)
+	result = append(result, script...)
+	return append(result, html[at:]...)
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// functionSummary describes a function in an ontology query response.
+type functionSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Access      []string `json:"access"`
+	Entities    []string `json:"entities,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Team        string   `json:"team,omitempty"`
+	Runbook     string   `json:"runbook,omitempty"`
+	Stability   string   `json:"stability,omitempty"`
+	Deprecated  string   `json:"deprecated,omitempty"`
+	IsReadOnly  bool     `json:"isReadOnly"`
+
+	RateLimit *ont.RateLimit `json:"rateLimit,omitempty"`
+	// FieldReferences name the functions input fields take options from.
+	FieldReferences []ont.FieldReference `json:"fieldReferences,omitempty"`
+	// LongDescription is the function's markdown documentation.
+	LongDescription string `json:"longDescription,omitempty"`
+}
+
+// handleOntologyQuery serves GET /ontology/query, which searches the
+// non-internal functions the caller may call. Query parameters mirror ont.FunctionQuery:
+// entity, accessGroup, inputField, outputField, tag and q (text).
+func (s *Server) handleOntologyQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authResult, err := s.authFunc(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	params := r.URL.Query()
+	names := s.config.Public().Query(ont.FunctionQuery{
+		Entity:      params.Get("entity"),
+		AccessGroup: params.Get("accessGroup"),
+		InputField:  params.Get("inputField"),
+		OutputField: params.Get("outputField"),
+		Tag:         params.Get("tag"),
+		Text:        params.Get("q"),
+	})
+
+	// Descriptions in the caller's language
+	lang := r.Header.Get("Accept-Language")
+	functions := make([]functionSummary, 0, len(names))
+	for _, name := range names {
+		fn := s.config.Functions[name]
+		if !fn.CheckAccess(authResult.AccessGroups) {
+			continue
+		}
+		functions = append(functions, functionSummary{
+			Name:        name,
+			Description: ont.Localize(fn.Description, fn.Descriptions, lang),
+			Access:      fn.Access,
+			Entities:    fn.Entities,
+			Tags:        fn.Tags,
+			Owner:       fn.Owner,
+			Team:        fn.Team,
+			Runbook:     fn.Runbook,
+			Stability:   string(fn.Stability),
+			Deprecated:  fn.Deprecated,
+			IsReadOnly:  fn.IsReadOnly,
+			RateLimit:   fn.RateLimit,
+
+			LongDescription: fn.LongDescription,
+			FieldReferences: fn.FieldReferences,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(map[string]any{"functions": functions})
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// rateLimitKeyPrefix starts the keys of the rate limit counters kept in a
+// store.Counter.
+const rateLimitKeyPrefix = "ratelimit:"
+
+// WithRateLimitCounter counts the calls of Function.RateLimit in counter
+// instead of in memory, so replicas sharing it (e.g. a Redis or Postgres
+// counter from pkg/store) share the budgets. Shared budgets use fixed
+// windows rather than token buckets: a window lasts Burst calls' worth of
+// the rate and allows Burst calls, so both the rate and the burst hold. If
+// the counter fails, calls are allowed and the error is logged.
+func WithRateLimitCounter(counter store.Counter) ServerOption {
+	return func(s *Server) {
+		s.limiter.counter = counter
+	}
+}
+
+// rateLimiter holds the token buckets of Function.RateLimit, by function
+// and access group, or counts calls in a shared counter. It is shared by
+// the servers Reload creates, so reloading doesn't reset the budgets.
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	counter store.Counter
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take spends a token of the bucket for key, refilled at perMinute and
+// holding up to burst. If none is left it returns how long until one is.
+func (l *rateLimiter) take(key string, perMinute, burst int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+
+	rate := float64(perMinute) / 60 // tokens per second
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// count spends a call of the shared counter for key, in fixed windows
+// allowing burst calls at perMinute. If none is left it returns how long
+// until the next window.
+func (l *rateLimiter) count(ctx context.Context, key string, perMinute, burst int, now time.Time) (bool, time.Duration, error) {
+	window := time.Duration(burst) * time.Minute / time.Duration(perMinute)
+	start := now.Truncate(window)
+	key = rateLimitKeyPrefix + key + "\x00" + strconv.FormatInt(start.UnixMilli(), 10)
+	n, err := l.counter.Incr(ctx, key, 1, window)
+	if err != nil {
+		return true, 0, err
+	}
+	if n > int64(burst) {
+		return false, start.Add(window).Sub(now), nil
+	}
+	return true, 0, nil
+}
+
+// checkRateLimit spends a call of fn's rate limit for callers with
+// accessGroups. It returns false and how long to wait when over the limit.
+func (s *Server) checkRateLimit(ctx context.Context, name string, fn ont.Function, accessGroups []string) (bool, time.Duration) {
+	if fn.RateLimit == nil {
+		return true, 0
+	}
+	group, perMinute, burst := fn.RateLimit.Limit(accessGroups)
+	key := name + "\x00" + group
+	if s.limiter.counter == nil {
+		return s.limiter.take(key, perMinute, burst, time.Now())
+	}
+	ok, wait, err := s.limiter.count(ctx, key, perMinute, burst, time.Now())
+	if err != nil {
+		s.logger.Warn("Rate limit counter failed, allowing the call", "function", name, "error", err)
+	}
+	return ok, wait
+}
+
+// retryAfter rounds a wait up to whole seconds.
+func retryAfter(wait time.Duration) int {
+	return int(math.Ceil(wait.Seconds()))
+}
+
+// writeRateLimited answers a call over its rate limit with 429 and a
+// Retry-After header.
+func writeRateLimited(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter(wait)))
+	http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+	"github.com/vanna-ai/ont-run/pkg/store/memstore"
+)
+
+// rateLimitedServer returns a server whose echo function allows two calls
+// a minute, or one for admins, authenticating callers with the groups in
+// the X-Groups header.
+func rateLimitedServer(opts ...ServerOption) *Server {
+	config := testConfig()
+	fn := config.Functions["echo"]
+	fn.RateLimit = &ont.RateLimit{RequestsPerMinute: 2, PerAccessGroup: map[string]int{"admin": 1}}
+	config.Functions["echo"] = fn
+	return New(config, append([]ServerOption{WithAuth(func(r *http.Request) (*AuthResult, error) {
+		return &AuthResult{AccessGroups: strings.Split(r.Header.Get("X-Groups"), ",")}, nil
+	})}, opts...)...)
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	h := rateLimitedServer().Handler()
+	for i := range 2 {
+		if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`, "X-Groups", "public"); resp.StatusCode != http.StatusOK {
+			t.Fatalf("call %d: status %d: %s", i+1, resp.StatusCode, body)
+		}
+	}
+	resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`, "X-Groups", "public")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("call over the limit: status %d, want 429", resp.StatusCode)
+	}
+	// The next token is half a minute away at two calls a minute
+	if got := resp.Header.Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After = %q, want 30", got)
+	}
+}
+
+func TestRateLimitBuckets(t *testing.T) {
+	h := rateLimitedServer().Handler()
+	call := func(target, groups string) int {
+		resp, _ := do(t, h, "POST", target, `{"message":"hi","id":"1"}`, "X-Groups", groups)
+		return resp.StatusCode
+	}
+
+	// Admins have a bucket of their own, holding one call
+	if got := call("/api/echo", "admin"); got != http.StatusOK {
+		t.Fatalf("admin call: status %d", got)
+	}
+	if got := call("/api/echo", "admin,public"); got != http.StatusTooManyRequests {
+		t.Errorf("second admin call: status %d, want 429", got)
+	}
+	// Other callers still have the function's budget
+	if got := call("/api/echo", "public"); got != http.StatusOK {
+		t.Errorf("public call after admins ran out: status %d", got)
+	}
+	// Functions without a rate limit are unaffected
+	for range 5 {
+		if got := call("/api/getItem", "admin"); got != http.StatusOK {
+			t.Fatalf("getItem: status %d", got)
+		}
+	}
+}
+
+func TestRateLimitRefills(t *testing.T) {
+	var limiter rateLimiter
+	now := time.Now()
+	if ok, _ := limiter.take("echo", 60, 1, now); !ok {
+		t.Fatal("first take failed")
+	}
+	if ok, wait := limiter.take("echo", 60, 1, now); ok || wait != time.Second {
+		t.Errorf("take with an empty bucket = %v, %v, want false, 1s", ok, wait)
+	}
+	if ok, _ := limiter.take("echo", 60, 1, now.Add(time.Second)); !ok {
+		t.Error("take after a second failed")
+	}
+}
+
+func TestRateLimitCounter(t *testing.T) {
+	// Two replicas sharing a counter share the budget
+	counter := memstore.NewCounter()
+	replicas := []http.Handler{
+		rateLimitedServer(WithRateLimitCounter(counter)).Handler(),
+		rateLimitedServer(WithRateLimitCounter(counter)).Handler(),
+	}
+	for i, h := range replicas {
+		if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`, "X-Groups", "public"); resp.StatusCode != http.StatusOK {
+			t.Fatalf("call on replica %d: status %d: %s", i+1, resp.StatusCode, body)
+		}
+	}
+	for i, h := range replicas {
+		resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`, "X-Groups", "public")
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("call over the limit on replica %d: status %d, want 429", i+1, resp.StatusCode)
+		}
+		if resp.Header.Get("Retry-After") == "" {
+			t.Errorf("call over the limit on replica %d: no Retry-After", i+1)
+		}
+	}
+	// Admins count apart
+	if resp, _ := do(t, replicas[0], "POST", "/api/echo", `{"message":"hi"}`, "X-Groups", "admin"); resp.StatusCode != http.StatusOK {
+		t.Errorf("admin call: status %d", resp.StatusCode)
+	}
+}
+
+func TestRateLimitCounterWindows(t *testing.T) {
+	limiter := rateLimiter{counter: memstore.NewCounter()}
+	ctx := context.Background()
+	// Two calls a minute with a burst of two make one-minute windows
+	start := time.Now().Truncate(time.Minute)
+	for i := range 2 {
+		if ok, _, err := limiter.count(ctx, "echo", 2, 2, start.Add(time.Duration(i)*time.Second)); !ok || err != nil {
+			t.Fatalf("call %d = %v, %v", i+1, ok, err)
+		}
+	}
+	if ok, wait, _ := limiter.count(ctx, "echo", 2, 2, start.Add(20*time.Second)); ok || wait != 40*time.Second {
+		t.Errorf("call over the limit = %v, %v, want false, 40s", ok, wait)
+	}
+	if ok, _, _ := limiter.count(ctx, "echo", 2, 2, start.Add(time.Minute)); !ok {
+		t.Error("call in the next window failed")
+	}
+}
+
+func TestRateLimitMCP(t *testing.T) {
+	session := connectMCP(t, rateLimitedServer().Handler(), nil, "X-Groups", "admin")
+	params := &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "hi"}}
+	if result, err := session.CallTool(context.Background(), params); err != nil || result.IsError {
+		t.Fatalf("first call: %v, %+v", err, result)
+	}
+	result, err := session.CallTool(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "rate limit exceeded: retry in 60s") {
+		t.Errorf("call over the limit = %+v, want a rate limit error", result.Content[0])
+	}
+}
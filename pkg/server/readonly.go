@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// allowsMethod reports whether a REST call may use the request's method:
+// POST, or GET for read-only functions.
+func allowsMethod(w http.ResponseWriter, r *http.Request, fn ont.Function) bool {
+	if r.Method == http.MethodPost || (r.Method == http.MethodGet && fn.IsReadOnly) {
+		return true
+	}
+	if fn.IsReadOnly {
+		w.Header().Set("Allow", "GET, POST")
+	} else {
+		w.Header().Set("Allow", "POST")
+	}
+	http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	return false
+}
+
+// queryInput reads the input of a GET call from its URL query, by wire
+// name. Parameters declared in Function.Query are left to mergeParams;
+// repeated parameters and parameters of array fields become arrays. Values
+// are strings until coerced.
+func queryInput(r *http.Request, fn ont.Function, naming ont.FieldNaming) map[string]any {
+	arrays := make(map[string]bool)
+	if obj, ok := ont.Deref(fn.Inputs).(*ont.ObjectSchema); ok {
+		for name, prop := range obj.Properties() {
+			if _, ok := ont.Deref(prop).(*ont.ArraySchema); ok {
+				arrays[naming.Apply(name)] = true
+			}
+		}
+	}
+
+	input := make(map[string]any)
+	for key, values := range r.URL.Query() {
+		if fn.IsParam(key) {
+			continue
+		}
+		if len(values) == 1 && !arrays[key] {
+			input[key] = values[0]
+			continue
+		}
+		items := make([]any, len(values))
+		for i, v := range values {
+			items[i] = v
+		}
+		input[key] = items
+	}
+	return input
+}
+
+// noStore keeps caches from storing the responses of functions that
+// aren't read-only, so caching layers only ever serve read-only calls.
+func noStore(w http.ResponseWriter, fn ont.Function) {
+	if !fn.IsReadOnly {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+}
+
+// toolAnnotations returns the MCP hints for fn: read-only functions are
+// readOnlyHint, other functions may be destructive.
+func toolAnnotations(fn ont.Function) *mcp.ToolAnnotations {
+	if fn.IsReadOnly {
+		return &mcp.ToolAnnotations{ReadOnlyHint: true}
+	}
+	destructive := true
+	return &mcp.ToolAnnotations{DestructiveHint: &destructive}
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// searchServer returns a server with a read-only search function that
+// echoes its input, serving field names as snake_case.
+func searchServer() *Server {
+	config := testConfig()
+	config.Functions["search"] = ont.Function{
+		Description: "Search items",
+		Access:      []string{"admin", "public"},
+		IsReadOnly:  true,
+		Inputs: ont.Object(map[string]ont.Schema{
+			"tags":     ont.Array(ont.String()),
+			"pageSize": ont.Integer(),
+			"exact":    ont.Boolean(),
+			"text":     ont.String(),
+		}).Optional("tags", "pageSize", "exact", "text"),
+		Outputs: ont.Object(map[string]ont.Schema{
+			"tags":     ont.Array(ont.String()),
+			"pageSize": ont.Integer(),
+			"exact":    ont.Boolean(),
+			"text":     ont.String(),
+		}).Optional("tags", "pageSize", "exact", "text"),
+		Resolver: func(ctx ont.Context, input any) (any, error) {
+			return input, nil
+		},
+	}
+	return New(config, WithFieldNaming(ont.SnakeCase))
+}
+
+func TestGetReadOnlyFunction(t *testing.T) {
+	h := searchServer().Handler()
+	for target, want := range map[string]map[string]any{
+		// Numbers and booleans are coerced from their string forms
+		"/api/search?page_size=20&exact=true&text=42": {"pageSize": 20.0, "exact": true, "text": "42"},
+		// Array fields are arrays even with one value
+		"/api/search?tags=a":        {"tags": []any{"a"}},
+		"/api/search?tags=a&tags=b": {"tags": []any{"a", "b"}},
+		"/api/search":               {},
+	} {
+		resp, body := do(t, h, "GET", target, "")
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s: status %d: %s", target, resp.StatusCode, body)
+			continue
+		}
+		if resp.Header.Get("Cache-Control") == "no-store" {
+			t.Errorf("GET %s: read-only response marked no-store", target)
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(body), &got); err != nil {
+			t.Fatal(err)
+		}
+		wire := make(map[string]any, len(want))
+		for name, value := range want {
+			wire[ont.SnakeCase.Apply(name)] = value
+		}
+		if !reflect.DeepEqual(got, wire) {
+			t.Errorf("GET %s = %v, want %v", target, got, wire)
+		}
+	}
+}
+
+func TestGetRejectsInvalidQuery(t *testing.T) {
+	h := searchServer().Handler()
+	resp, body := do(t, h, "GET", "/api/search?page_size=many", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status %d: %s, want 400", resp.StatusCode, body)
+	}
+	var invalid InvalidInputResponse
+	if err := json.Unmarshal([]byte(body), &invalid); err != nil {
+		t.Fatal(err)
+	}
+	if len(invalid.Issues) != 1 || invalid.Issues[0].Field != "/page_size" {
+		t.Errorf("issues = %v, want one at /page_size", invalid.Issues)
+	}
+}
+
+func TestGetOnlyForReadOnlyFunctions(t *testing.T) {
+	h := searchServer().Handler()
+	resp, _ := do(t, h, "GET", "/api/echo?message=hi", "")
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "POST" {
+		t.Errorf("GET on echo: status %d, Allow %q, want 405 and POST", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+	resp, _ = do(t, h, "DELETE", "/api/search", "")
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "GET, POST" {
+		t.Errorf("DELETE on search: status %d, Allow %q, want 405 and GET, POST", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+	resp, _ = do(t, h, "POST", "/api/echo", `{"message":"hi"}`)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Cache-Control") != "no-store" {
+		t.Errorf("POST on echo: status %d, Cache-Control %q, want 200 and no-store", resp.StatusCode, resp.Header.Get("Cache-Control"))
+	}
+}
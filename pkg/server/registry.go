@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// routeTable maps the paths of function endpoints to their handlers. It is
+// built once per config, so a call costs one map lookup however many
+// functions there are.
+type routeTable map[string]http.Handler
+
+// with serves the table's paths and passes other requests to fallback.
+func (t routeTable) with(fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler, ok := t[r.URL.Path]; ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		fallback.ServeHTTP(w, r)
+	})
+}
+
+// currentRegistry returns the registry of the config being served.
+func (s *Server) currentRegistry() *ont.Registry {
+	s.live.mu.RLock()
+	defer s.live.mu.RUnlock()
+	if s.live.server != nil && s.live.server.registry != nil {
+		return s.live.server.registry
+	}
+	return ont.NewRegistry(s.config)
+}
+
+// toolIndex holds the MCP tools of a config by function name, aliases
+// included, as the SDK lists them. It is filled on the first tools/list.
+type toolIndex struct {
+	once  sync.Once
+	tools map[string][]*mcp.Tool
+	err   error
+}
+
+// listCallableTools answers tools/list with the tools the caller's access
+// groups may call, in name order, leaving out experimental tools unless the
+// caller opted in. The tools each set of groups may call is computed once
+// (see Registry.Callable).
+func (s *Server) listCallableTools(index *toolIndex) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/list" {
+				return next(ctx, method, req)
+			}
+			index.once.Do(func() {
+				index.tools, index.err = s.indexTools(ctx, next, req)
+			})
+			if index.err != nil {
+				return nil, index.err
+			}
+
+			httpReq, _ := ctx.Value(httpRequestKey).(*http.Request)
+			if httpReq == nil {
+				httpReq = &http.Request{Header: http.Header{}}
+			}
+			authResult, err := s.authFunc(httpReq)
+			if err != nil {
+				return nil, fmt.Errorf("authentication failed: %v", err)
+			}
+
+			lang := httpReq.Header.Get("Accept-Language")
+			tools := []*mcp.Tool{}
+			for _, name := range s.registry.Callable(authResult.AccessGroups) {
+				_, fn, _ := s.registry.Lookup(name)
+				if !s.checkStability(httpReq, fn, authResult.AccessGroups) {
+					continue
+				}
+				for _, tool := range index.tools[name] {
+					tools = append(tools, s.localizeTool(tool, name, fn, lang))
+				}
+			}
+			return &mcp.ListToolsResult{Tools: tools}, nil
+		}
+	}
+}
+
+// localizeTool returns tool with the description of fn translated for
+// lang, or tool itself if there's no translation.
+func (s *Server) localizeTool(tool *mcp.Tool, name string, fn ont.Function, lang string) *mcp.Tool {
+	description := ont.Localize(fn.Description, fn.Descriptions, lang)
+	if description == fn.Description {
+		return tool
+	}
+	localized := *tool
+	localized.Description = s.describeTool(name, fn, description)
+	if tool.Name != ont.FunctionIdentifier(name) {
+		localized.Description = aliasDescription(name, localized.Description)
+	}
+	return &localized
+}
+
+// indexTools lists every page of the SDK's tools and groups them by the
+// function they call.
+func (s *Server) indexTools(ctx context.Context, next mcp.MethodHandler, req mcp.Request) (map[string][]*mcp.Tool, error) {
+	tools := make(map[string][]*mcp.Tool)
+	params := &mcp.ListToolsParams{}
+	for {
+		page := &mcp.ListToolsRequest{Session: req.GetSession().(*mcp.ServerSession), Params: params}
+		result, err := next(ctx, "tools/list", page)
+		if err != nil {
+			return nil, err
+		}
+		list := result.(*mcp.ListToolsResult)
+		for _, tool := range list.Tools {
+			name, _, _ := s.registry.Lookup(tool.Name)
+			tools[name] = append(tools[name], tool)
+		}
+		if list.NextCursor == "" {
+			return tools, nil
+		}
+		params = &mcp.ListToolsParams{Cursor: list.NextCursor}
+	}
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// liveHandler serves the routes of the current config. Reload replaces
+// them; calls in flight finish with the config they started with.
+type liveHandler struct {
+	mu      sync.RWMutex
+	server  *Server
+	handler http.Handler
+}
+
+func (l *liveHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	l.mu.RLock()
+	handler := l.handler
+	l.mu.RUnlock()
+	handler.ServeHTTP(w, r)
+}
+
+// WithLockFile checks the config against the lock file at path whenever the
+// server starts serving it (Handler and Reload). Mismatches are logged and
+// reported to OnLockMismatch handlers; the config is served regardless.
+func WithLockFile(path string) ServerOption {
+	return func(s *Server) {
+		s.lockFile = path
+	}
+}
+
+// Reload validates config and serves it in place of the current one,
+// rebuilding the REST routes and MCP tools. Calls in flight finish with
+// the previous config. OnConfigReload handlers run after the swap.
+func (s *Server) Reload(config *ont.Config) error {
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if err := s.verifyApproved(config); err != nil {
+		return err
+	}
+
+	s.live.mu.Lock()
+	current := s.live.server
+	if current == nil {
+		current = s
+	}
+	next := *current
+	next.config = config
+	s.live.server = &next
+	s.live.handler = next.routes()
+	s.live.mu.Unlock()
+
+	s.logger.Info("Config reloaded", "functions", len(config.Functions))
+	s.events.emitConfigReload(ConfigReloadEvent{Previous: current.config, Config: config})
+	next.checkLock()
+	next.registerWithCloud()
+	return nil
+}
+
+// currentConfig returns the config being served.
+func (s *Server) currentConfig() *ont.Config {
+	s.live.mu.RLock()
+	defer s.live.mu.RUnlock()
+	if s.live.server != nil {
+		return s.live.server.config
+	}
+	return s.config
+}
+
+// checkLock reports differences between the config and the lock file, if
+// one is set.
+func (s *Server) checkLock() {
+	if s.lockFile == "" {
+		return
+	}
+	diff, err := s.config.DiffLock(s.lockFile)
+	if err != nil {
+		s.logger.Error("Failed to check lock file", "path", s.lockFile, "error", err)
+		return
+	}
+	if !diff.RequiresReview() {
+		if diff.HasChanges() {
+			s.logger.Info("Config has experimental changes not in the lock file", "path", s.lockFile, "functions", diff.ExperimentalFunctions)
+		}
+		return
+	}
+	s.logger.Warn("Config doesn't match lock file", "path", s.lockFile, "changes", diff.String())
+	s.events.emitLockMismatch(LockMismatchEvent{Path: s.lockFile, Diff: diff})
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// testConfig returns a valid config with an echo function, which returns
+// its message, and a read-only getItem function.
+func testConfig() *ont.Config {
+	return &ont.Config{
+		Name: "test",
+		AccessGroups: map[string]ont.AccessGroup{
+			"admin":  {Description: "Administrators"},
+			"public": {Description: "Everyone"},
+		},
+		Entities: map[string]ont.Entity{},
+		Functions: map[string]ont.Function{
+			"echo": {
+				Description:           "Echo a message",
+				Access:                []string{"admin", "public"},
+				IncludeInMcpListTools: true,
+				Inputs:                ont.Object(map[string]ont.Schema{"message": ont.String()}),
+				Outputs:               ont.Object(map[string]ont.Schema{"message": ont.String()}),
+				Resolver: func(ctx ont.Context, input any) (any, error) {
+					return map[string]any{"message": input.(map[string]any)["message"]}, nil
+				},
+			},
+			"getItem": {
+				Description:           "Get an item",
+				Access:                []string{"admin", "public"},
+				IsReadOnly:            true,
+				IncludeInMcpListTools: true,
+				Inputs:                ont.Object(map[string]ont.Schema{"id": ont.String(), "count": ont.Integer()}).Optional("count"),
+				Outputs:               ont.Object(map[string]ont.Schema{"id": ont.String(), "count": ont.Integer()}).Optional("count"),
+				Resolver: func(ctx ont.Context, input any) (any, error) {
+					return input, nil
+				},
+			},
+		},
+	}
+}
+
+// do sends a request to h and returns the response with its body read.
+func do(t *testing.T, h http.Handler, method, target, body string, headers ...string) (*http.Response, string) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	r := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	resp := w.Result()
+	data, _ := io.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+// groups authenticates every caller with the given access groups.
+func groups(accessGroups ...string) ServerOption {
+	return WithAuth(func(r *http.Request) (*AuthResult, error) {
+		return &AuthResult{AccessGroups: accessGroups}, nil
+	})
+}
+
+// connectMCP connects an MCP client to h, sending headers with every
+// request. The session is closed when the test ends.
+func connectMCP(t *testing.T, h http.Handler, opts *mcp.ClientOptions, headers ...string) *mcp.ClientSession {
+	t.Helper()
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test", Version: "1.0.0"}, opts)
+	transport := &mcp.StreamableClientTransport{
+		Endpoint:   ts.URL + "/mcp",
+		HTTPClient: &http.Client{Transport: headerTransport(headers)},
+		MaxRetries: -1,
+	}
+	session, err := client.Connect(context.Background(), transport, nil)
+	if err != nil {
+		t.Fatalf("connecting MCP client: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// headerTransport adds header name, value pairs to every request.
+type headerTransport []string
+
+func (h headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	for i := 0; i+1 < len(h); i += 2 {
+		r.Header.Set(h[i], h[i+1])
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// ShadowHeader marks requests replayed by WithShadowing, so the target can
+// tell them apart from real traffic.
+const ShadowHeader = "X-Ont-Shadow"
+
+const (
+	// shadowTimeout bounds how long a replayed request may take.
+	shadowTimeout = 30 * time.Second
+
+	// shadowMaxInFlight bounds the replays running at once. Sampled calls
+	// beyond it aren't replayed, so a slow target can't pile up goroutines.
+	shadowMaxInFlight = 16
+)
+
+// shadowCredentialHeaders are removed from replayed requests, so production
+// credentials aren't sent to another environment.
+var shadowCredentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"}
+
+// shadowConfig mirrors sampled function calls to another server.
+type shadowConfig struct {
+	target   string
+	percent  float64
+	client   *http.Client
+	inFlight chan struct{}
+}
+
+// WithShadowing replays percent (0-100) of REST function calls against the
+// server at targetURL (e.g. a staging deployment), with the same path, body
+// and headers plus ShadowHeader. Credential headers (Authorization,
+// Proxy-Authorization, Cookie and X-Api-Key) aren't replayed; use
+// WithShadowHeaders to authenticate with the target. Replays happen in the
+// background after the caller has been answered, at most 16 at a time, and
+// responses whose status or JSON body differ are logged as warnings with
+// the JSON pointers of the differences. Mutations are replayed too, so the
+// target should have its own data.
+func WithShadowing(targetURL string, percent float64) ServerOption {
+	return func(s *Server) {
+		s.shadow = &shadowConfig{
+			target:   strings.TrimSuffix(targetURL, "/"),
+			percent:  percent,
+			client:   &http.Client{Timeout: shadowTimeout},
+			inFlight: make(chan struct{}, shadowMaxInFlight),
+		}
+	}
+}
+
+// WithShadowHeaders rewrites the headers of requests replayed by
+// WithShadowing, after credential headers are removed, e.g. to set the
+// target's own credentials or remove a custom API key header:
+//
+//	server.WithShadowHeaders(func(h http.Header) {
+//		h.Set("Authorization", "Bearer "+stagingToken)
+//	})
+func WithShadowHeaders(rewrite func(header http.Header)) ServerOption {
+	return func(s *Server) {
+		s.shadowHeaders = rewrite
+	}
+}
+
+// shadowRecorder captures the response written to the caller.
+type shadowRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *shadowRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *shadowRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+// shadowed wraps a function handler to mirror a sample of its calls.
+func (s *Server) shadowed(name string, handler http.HandlerFunc) http.HandlerFunc {
+	if s.shadow == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rand.Float64()*100 >= s.shadow.percent {
+			handler(w, r)
+			return
+		}
+		select {
+		case s.shadow.inFlight <- struct{}{}:
+		default:
+			s.logger.Debug("Shadow replay dropped, too many in flight", "function", name)
+			handler(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			<-s.shadow.inFlight
+			http.Error(w, "Failed to read request", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec := &shadowRecorder{ResponseWriter: w}
+		handler(rec, r)
+
+		header := r.Header.Clone()
+		for _, key := range shadowCredentialHeaders {
+			header.Del(key)
+		}
+		if s.shadowHeaders != nil {
+			s.shadowHeaders(header)
+		}
+		go func() {
+			defer func() { <-s.shadow.inFlight }()
+			s.replay(name, r.Method, r.URL.RequestURI(), header, body, rec.status, rec.body.Bytes())
+		}()
+	}
+}
+
+// replay sends a recorded call to the shadow target and logs differences
+// from the response the caller got.
+func (s *Server) replay(name, method, uri string, header http.Header, body []byte, status int, response []byte) {
+	req, err := http.NewRequest(method, s.shadow.target+uri, bytes.NewReader(body))
+	if err != nil {
+		s.logger.Warn("Shadow request failed", "function", name, "error", err)
+		return
+	}
+	req.Header = header
+	req.Header.Set(ShadowHeader, "true")
+
+	resp, err := s.shadow.client.Do(req)
+	if err != nil {
+		s.logger.Warn("Shadow request failed", "function", name, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+	shadowResponse, err := io.ReadAll(resp.Body)
+	if err != nil {
+		s.logger.Warn("Shadow request failed", "function", name, "error", err)
+		return
+	}
+
+	paths := s.responseDiff(response, shadowResponse)
+	if resp.StatusCode != status || len(paths) > 0 {
+		s.logger.Warn("Shadow response differs", "function", name,
+			"status", status, "shadowStatus", resp.StatusCode, "paths", paths)
+	}
+}
+
+// responseDiff returns the JSON pointers at which two response bodies
+// differ, ignoring the per-request envelope metadata. Bodies that aren't
+// JSON are compared as a whole.
+func (s *Server) responseDiff(a, b []byte) []string {
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		if bytes.Equal(a, b) {
+			return nil
+		}
+		return []string{""}
+	}
+
+	var paths []string
+	for _, path := range ont.DiffJSON(av, bv) {
+		if s.envelope && (path == "/meta/requestId" || path == "/meta/durationMs") {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// shadowedCall is a request received by a shadow target.
+type shadowedCall struct {
+	header http.Header
+	path   string
+	body   string
+}
+
+// warnLogger sends the warnings logged by a server to a channel.
+type warnLogger struct {
+	warnings chan []any
+}
+
+func (l *warnLogger) Info(msg string, keysAndValues ...any)  {}
+func (l *warnLogger) Error(msg string, keysAndValues ...any) {}
+func (l *warnLogger) Debug(msg string, keysAndValues ...any) {}
+func (l *warnLogger) Warn(msg string, keysAndValues ...any) {
+	l.warnings <- append([]any{msg}, keysAndValues...)
+}
+
+// shadowTarget returns a target recording the calls it receives, which
+// answers every call with a different message.
+func shadowTarget(t *testing.T) (*httptest.Server, chan shadowedCall) {
+	calls := make(chan shadowedCall, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls <- shadowedCall{header: r.Header, path: r.URL.Path, body: string(body)}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"message":"from staging"}`)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, calls
+}
+
+func receive[T any](t *testing.T, ch chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+	var zero T
+	return zero
+}
+
+func TestShadowing(t *testing.T) {
+	ts, calls := shadowTarget(t)
+	logger := &warnLogger{warnings: make(chan []any, 10)}
+	h := New(testConfig(), groups("admin"), WithLogger(logger),
+		WithShadowing(ts.URL, 100),
+		WithShadowHeaders(func(h http.Header) { h.Set("Authorization", "Bearer staging") }),
+	).Handler()
+
+	resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`,
+		"Authorization", "Bearer production", "Cookie", "session=secret", "Accept-Language", "fr")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, body)
+	}
+
+	call := receive(t, calls)
+	if call.path != "/api/echo" || call.body != `{"message":"hi"}` {
+		t.Errorf("replayed %s with body %s", call.path, call.body)
+	}
+	if call.header.Get(ShadowHeader) != "true" || call.header.Get("Accept-Language") != "fr" {
+		t.Errorf("replayed headers %v, want %s and the caller's headers", call.header, ShadowHeader)
+	}
+	if call.header.Get("Authorization") != "Bearer staging" || call.header.Get("Cookie") != "" {
+		t.Errorf("replayed credentials: Authorization %q, Cookie %q", call.header.Get("Authorization"), call.header.Get("Cookie"))
+	}
+
+	warning := receive(t, logger.warnings)
+	if warning[0] != "Shadow response differs" {
+		t.Fatalf("warning = %v", warning)
+	}
+	for i := 1; i+1 < len(warning); i += 2 {
+		if warning[i] == "paths" && !reflect.DeepEqual(warning[i+1], []string{"/message"}) {
+			t.Errorf("paths = %v, want [/message]", warning[i+1])
+		}
+	}
+}
+
+func TestShadowingDropsReplaysWhenBusy(t *testing.T) {
+	ts, calls := shadowTarget(t)
+	srv := New(testConfig(), groups("admin"), WithShadowing(ts.URL, 100))
+	h := srv.Handler()
+
+	// With every slot taken, the call is answered without being replayed
+	for range shadowMaxInFlight {
+		srv.shadow.inFlight <- struct{}{}
+	}
+	if resp, body := do(t, h, "POST", "/api/echo", `{"message":"dropped"}`); resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, body)
+	}
+
+	<-srv.shadow.inFlight
+	do(t, h, "POST", "/api/echo", `{"message":"replayed"}`)
+	if call := receive(t, calls); call.body != `{"message":"replayed"}` {
+		t.Errorf("first replay = %s, want the call made with a free slot", call.body)
+	}
+}
+
+func TestShadowingSamples(t *testing.T) {
+	ts, calls := shadowTarget(t)
+	h := New(testConfig(), groups("admin"), WithShadowing(ts.URL, 0)).Handler()
+
+	do(t, h, "POST", "/api/echo", `{"message":"hi"}`)
+	select {
+	case call := <-calls:
+		t.Errorf("replayed %s at 0%%", call.body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
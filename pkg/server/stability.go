@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// ExperimentalHeader opts a request in to experimental functions when set
+// to "true".
+const ExperimentalHeader = "X-Ont-Experimental"
+
+// WithExperimentalGroups lets callers in any of the access groups call
+// experimental functions without sending ExperimentalHeader, e.g. for
+// internal testers.
+func WithExperimentalGroups(groups ...string) ServerOption {
+	return func(s *Server) {
+		s.experimentalGroups = append(s.experimentalGroups, groups...)
+	}
+}
+
+// allowsExperimental reports whether a caller has opted in to experimental
+// functions, by header or access group.
+func (s *Server) allowsExperimental(r *http.Request, accessGroups []string) bool {
+	if optIn, err := strconv.ParseBool(r.Header.Get(ExperimentalHeader)); err == nil && optIn {
+		return true
+	}
+	for _, group := range accessGroups {
+		for _, allowed := range s.experimentalGroups {
+			if group == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkStability reports whether the caller may call fn given its stability.
+func (s *Server) checkStability(r *http.Request, fn ont.Function, accessGroups []string) bool {
+	return !fn.IsExperimental() || s.allowsExperimental(r, accessGroups)
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// experimentalConfig is testConfig with echo experimental.
+func experimentalConfig() *ont.Config {
+	config := testConfig()
+	fn := config.Functions["echo"]
+	fn.Stability = ont.StabilityExperimental
+	config.Functions["echo"] = fn
+	return config
+}
+
+func TestExperimentalChangesDontRequireReview(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "ont.lock")
+	if err := experimentalConfig().WriteLock(lockPath); err != nil {
+		t.Fatal(err)
+	}
+
+	serve := func(mutate func(*ont.Config)) (bool, string) {
+		config := experimentalConfig()
+		mutate(config)
+		srv := New(config, WithLockFile(lockPath))
+		mismatch := false
+		srv.OnLockMismatch(func(LockMismatchEvent) { mismatch = true })
+		srv.Handler()
+		return mismatch, srv.StartupReport().Lock.State
+	}
+
+	mismatch, state := serve(func(c *ont.Config) {
+		fn := c.Functions["echo"]
+		fn.Description = "Echo a message back"
+		c.Functions["echo"] = fn
+	})
+	if mismatch || state != LockMatches {
+		t.Errorf("experimental change: mismatch %v, lock state %q; want no mismatch and %q", mismatch, state, LockMatches)
+	}
+
+	mismatch, state = serve(func(c *ont.Config) {
+		fn := c.Functions["getItem"]
+		fn.Description = "Get an item by ID"
+		c.Functions["getItem"] = fn
+	})
+	if !mismatch || state != LockChanged {
+		t.Errorf("stable change: mismatch %v, lock state %q; want a mismatch and %q", mismatch, state, LockChanged)
+	}
+}
+
+func TestExperimentalToolsListedOnOptIn(t *testing.T) {
+	h := New(experimentalConfig()).Handler()
+	list := func(headers ...string) []string {
+		result, err := connectMCP(t, h, nil, headers...).ListTools(context.Background(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	if names := list(); slices.Contains(names, "echo") || !slices.Contains(names, "getItem") {
+		t.Errorf("tools without opt-in = %v, want getItem only", names)
+	}
+	if names := list(ExperimentalHeader, "true"); !slices.Contains(names, "echo") {
+		t.Errorf("tools with opt-in = %v, want echo included", names)
+	}
+}
+
+func TestExperimentalCallsRequireOptIn(t *testing.T) {
+	h := New(experimentalConfig()).Handler()
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != 403 {
+		t.Errorf("call without opt-in: status %d, want 403", resp.StatusCode)
+	}
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`, ExperimentalHeader, "true"); resp.StatusCode != 200 {
+		t.Errorf("call with opt-in: status %d, want 200", resp.StatusCode)
+	}
+}
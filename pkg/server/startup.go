@@ -0,0 +1,206 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/cloud"
+)
+
+// Lock states in a StartupReport.
+const (
+	LockUnchecked = "unchecked"
+	LockMatches   = "matches"
+	LockChanged   = "changed"
+	LockMissing   = "missing"
+	LockError     = "error"
+)
+
+// StartupReport describes what a server serves. Serve and ServeListener
+// log it when they start listening, and GET /health/info returns it.
+type StartupReport struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+	// Functions counts the functions served; Internal counts those among
+	// them left out of MCP and the SDK.
+	Functions int `json:"functions"`
+	Internal  int `json:"internal,omitempty"`
+	// Transports lists what the server speaks: "rest", "mcp", and "h2c",
+	// "graph" and "static" when enabled.
+	Transports []string `json:"transports"`
+	// Auth is "custom" with WithAuth, or "open" when every caller gets
+	// every access group.
+	Auth string     `json:"auth"`
+	Lock LockStatus `json:"lock"`
+	// Cloud is "disabled" in offline mode, "off" when the config doesn't
+	// register, or the state of the last registration.
+	Cloud     string    `json:"cloud"`
+	Addresses []string  `json:"addresses,omitempty"`
+	StartedAt time.Time `json:"startedAt,omitzero"`
+	// Capabilities is only in the JSON report and /health/info.
+	Capabilities Capabilities `json:"capabilities"`
+}
+
+// LockStatus is the served config's standing against its lock file.
+type LockStatus struct {
+	Path  string `json:"path,omitempty"`
+	State string `json:"state"`
+	// Approval is "approved" or "unapproved" with WithRequireApprovedLock.
+	Approval string `json:"approval,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// startupInfo records where the server listens. It is shared by the
+// servers Reload creates.
+type startupInfo struct {
+	mu        sync.Mutex
+	addresses []string
+	startedAt time.Time
+	// json receives the report as JSON instead of the log banner.
+	json io.Writer
+}
+
+// WithStartupReport writes the startup report to w as a line of JSON,
+// e.g. os.Stdout for orchestration tooling, instead of logging the banner.
+func WithStartupReport(w io.Writer) ServerOption {
+	return func(s *Server) {
+		s.startup.json = w
+	}
+}
+
+// StartupReport returns the report for the config being served.
+func (s *Server) StartupReport() StartupReport {
+	live := s.liveServer()
+	config := live.config
+
+	report := StartupReport{
+		Name:       config.Name,
+		Hash:       config.Hash(),
+		Functions:  len(config.Functions),
+		Internal:   len(config.Functions) - len(config.Public().Functions),
+		Transports: []string{"rest", "mcp"},
+		Auth:       "open",
+		Lock:       live.lockStatus(),
+		Cloud:      live.cloudStatus(),
+
+		Capabilities: live.capabilities(),
+	}
+	if live.transport.h2c {
+		report.Transports = append(report.Transports, "h2c")
+	}
+	if live.graphViewer {
+		report.Transports = append(report.Transports, "graph")
+	}
+	if live.staticFS != nil {
+		report.Transports = append(report.Transports, "static")
+	}
+	if live.customAuth {
+		report.Auth = "custom"
+	}
+
+	s.startup.mu.Lock()
+	report.Addresses = append([]string(nil), s.startup.addresses...)
+	report.StartedAt = s.startup.startedAt
+	s.startup.mu.Unlock()
+	return report
+}
+
+// lockStatus checks the config against WithLockFile and
+// WithRequireApprovedLock.
+func (s *Server) lockStatus() LockStatus {
+	path := s.lockFile
+	if path == "" && s.approval != nil {
+		path = s.approval.lockPath
+	}
+	if path == "" {
+		return LockStatus{State: LockUnchecked}
+	}
+
+	status := LockStatus{Path: path, State: LockMatches}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		status.State = LockMissing
+	} else if diff, err := s.config.DiffLock(path); err != nil {
+		status.State, status.Error = LockError, err.Error()
+	} else if diff.RequiresReview() {
+		status.State = LockChanged
+	}
+	if s.approval != nil {
+		status.Approval = "approved"
+		if err := s.verifyApproved(s.config); err != nil {
+			status.Approval = "unapproved"
+			if status.Error == "" {
+				status.Error = err.Error()
+			}
+		}
+	}
+	return status
+}
+
+// cloudStatus reports whether and how the config registers with ont-run.com.
+func (s *Server) cloudStatus() string {
+	switch {
+	case s.cloudDisabled || cloud.Offline():
+		return "disabled"
+	case !s.config.Cloud || s.config.UUID == "":
+		return "off"
+	}
+	if status, ok := cloud.LastRegistration(s.config.UUID); ok {
+		return status.State
+	}
+	return cloud.RegistrationPending
+}
+
+// reportStartup records that the server listens on l and logs the report.
+func (s *Server) reportStartup(l net.Listener) {
+	address := l.Addr().Network() + "://" + l.Addr().String()
+	s.startup.mu.Lock()
+	s.startup.addresses = append(s.startup.addresses, address)
+	if s.startup.startedAt.IsZero() {
+		s.startup.startedAt = time.Now().UTC()
+	}
+	s.startup.mu.Unlock()
+
+	report := s.StartupReport()
+	if s.startup.json != nil {
+		json.NewEncoder(s.startup.json).Encode(report)
+		return
+	}
+	log.Print(report.banner(address))
+}
+
+// banner renders the report for the log.
+func (r StartupReport) banner(address string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Starting server on %s\n", address)
+	fmt.Fprintf(&b, "  Ontology:   %s (%s), %d functions", r.Name, r.Hash, r.Functions)
+	if r.Internal > 0 {
+		fmt.Fprintf(&b, ", %d internal", r.Internal)
+	}
+	fmt.Fprintf(&b, "\n  Transports: %s\n", strings.Join(r.Transports, ", "))
+	fmt.Fprintf(&b, "  Auth:       %s\n", r.Auth)
+	fmt.Fprintf(&b, "  Lock:       %s", r.Lock.State)
+	if r.Lock.Path != "" {
+		fmt.Fprintf(&b, " (%s)", r.Lock.Path)
+	}
+	if r.Lock.Approval != "" {
+		fmt.Fprintf(&b, ", %s", r.Lock.Approval)
+	}
+	fmt.Fprintf(&b, "\n  Cloud:      %s", r.Cloud)
+	return b.String()
+}
+
+// handleHealthInfo serves GET /health/info, the startup report with the
+// current lock and cloud status.
+func (s *Server) handleHealthInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.StartupReport())
+}
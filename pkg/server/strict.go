@@ -0,0 +1,81 @@
+package server
+
+import (
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// WithStrictInputs rejects input properties that a function's input schema
+// doesn't declare, at any depth, as if every input object were
+// ont.ObjectSchema.Strict. MCP tool input schemas advertise
+// "additionalProperties": false accordingly. Outputs are unaffected.
+func WithStrictInputs() ServerOption {
+	return func(s *Server) {
+		s.strictInputs = true
+	}
+}
+
+// validateInput validates input against fn's input schema, strictly if
+// the server is configured to.
+func (s *Server) validateInput(fn ont.Function, input map[string]any) error {
+	if err := fn.ValidateInput(input); err != nil {
+		return err
+	}
+	if s.strictInputs {
+		return ont.RejectUnknownFields(fn.CallInputs(), input)
+	}
+	return nil
+}
+
+// strictJSONSchema returns a copy of a JSON Schema with
+// "additionalProperties": false on every object that declares properties.
+func strictJSONSchema(schema map[string]any) map[string]any {
+	return rewriteJSONSchema(schema, func(sub map[string]any) {
+		if _, ok := sub["properties"]; ok {
+			if _, set := sub["additionalProperties"]; !set {
+				sub["additionalProperties"] = false
+			}
+		}
+	})
+}
+
+// rewriteJSONSchema returns a copy of a JSON Schema with rewrite applied to
+// it and to every subschema. Only subschemas are visited, so values such as
+// examples are left alone.
+func rewriteJSONSchema(schema map[string]any, rewrite func(map[string]any)) map[string]any {
+	result := make(map[string]any, len(schema)+1)
+	for k, v := range schema {
+		switch k {
+		case "items", "not":
+			if sub, ok := v.(map[string]any); ok {
+				v = rewriteJSONSchema(sub, rewrite)
+			}
+		case "anyOf", "oneOf", "allOf":
+			if subs, ok := v.([]any); ok {
+				rewritten := make([]any, len(subs))
+				for i, sub := range subs {
+					if m, ok := sub.(map[string]any); ok {
+						rewritten[i] = rewriteJSONSchema(m, rewrite)
+					} else {
+						rewritten[i] = sub
+					}
+				}
+				v = rewritten
+			}
+		case "properties", "$defs":
+			if subs, ok := v.(map[string]any); ok {
+				rewritten := make(map[string]any, len(subs))
+				for name, sub := range subs {
+					if m, ok := sub.(map[string]any); ok {
+						rewritten[name] = rewriteJSONSchema(m, rewrite)
+					} else {
+						rewritten[name] = sub
+					}
+				}
+				v = rewritten
+			}
+		}
+		result[k] = v
+	}
+	rewrite(result)
+	return result
+}
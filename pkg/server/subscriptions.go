@@ -0,0 +1,327 @@
+package server
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+const (
+	// EventsPath streams the events of Config.Events as server-sent events.
+	EventsPath = "/events"
+
+	// EventResourcePrefix starts the URIs of the MCP resources of events,
+	// e.g. "ont://events/orderCreated". Clients subscribe to them to be
+	// notified when the event is published, and read them for the latest
+	// payloads.
+	EventResourcePrefix = "ont://events/"
+
+	// recentEventCount is how many events of each name are kept for MCP
+	// resources and for SSE clients reconnecting with Last-Event-ID.
+	recentEventCount = 20
+
+	// eventKeepAlive is how often an idle event stream sends a comment, so
+	// proxies don't close it.
+	eventKeepAlive = 30 * time.Second
+)
+
+// PublishedEvent is an event as delivered to subscribers.
+type PublishedEvent struct {
+	ID      uint64    `json:"id"`
+	Event   string    `json:"event"`
+	Payload any       `json:"payload"`
+	Time    time.Time `json:"time"`
+}
+
+// Publish validates payload against the named event of Config.Events and
+// delivers it to the event's subscribers. Resolvers publish with ctx.Emit;
+// use Publish for events raised elsewhere, e.g. by background jobs.
+func (s *Server) Publish(event string, payload any) error {
+	if err := s.currentConfig().ValidateEvent(event, payload); err != nil {
+		return err
+	}
+	if dropped := s.subscriptions.publish(event, payload); dropped > 0 {
+		s.logger.Warn("Dropped event for slow subscribers", "event", event, "subscribers", dropped)
+	}
+	return nil
+}
+
+// subscriptionHub delivers published events to SSE streams and MCP
+// sessions. It is shared by the configs served through Reload.
+type subscriptionHub struct {
+	mu          sync.Mutex
+	lastID      uint64
+	subscribers map[*eventSubscriber]struct{}
+	recent      map[string][]PublishedEvent
+	mcp         *mcp.Server
+}
+
+// eventSubscriber is an SSE stream subscribed to some events.
+type eventSubscriber struct {
+	events map[string]bool
+	ch     chan PublishedEvent
+}
+
+func newSubscriptionHub() *subscriptionHub {
+	return &subscriptionHub{
+		subscribers: make(map[*eventSubscriber]struct{}),
+		recent:      make(map[string][]PublishedEvent),
+	}
+}
+
+// publish records the event and delivers it, returning how many
+// subscribers were too far behind to receive it.
+func (h *subscriptionHub) publish(event string, payload any) int {
+	h.mu.Lock()
+	h.lastID++
+	published := PublishedEvent{ID: h.lastID, Event: event, Payload: payload, Time: time.Now().UTC()}
+	recent := append(h.recent[event], published)
+	if len(recent) > recentEventCount {
+		recent = recent[len(recent)-recentEventCount:]
+	}
+	h.recent[event] = recent
+
+	dropped := 0
+	for sub := range h.subscribers {
+		if !sub.events[event] {
+			continue
+		}
+		select {
+		case sub.ch <- published:
+		default:
+			dropped++
+		}
+	}
+	mcpServer := h.mcp
+	h.mu.Unlock()
+
+	if mcpServer != nil {
+		mcpServer.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: EventResourcePrefix + event})
+	}
+	return dropped
+}
+
+// subscribe registers a subscriber to events. It returns the recent events
+// published after lastID, and a function that unsubscribes.
+func (h *subscriptionHub) subscribe(events []string, lastID uint64) (*eventSubscriber, []PublishedEvent, func()) {
+	sub := &eventSubscriber{events: make(map[string]bool), ch: make(chan PublishedEvent, recentEventCount)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var missed []PublishedEvent
+	for _, event := range events {
+		sub.events[event] = true
+		if lastID == 0 {
+			continue
+		}
+		for _, published := range h.recent[event] {
+			if published.ID > lastID {
+				missed = append(missed, published)
+			}
+		}
+	}
+	slices.SortFunc(missed, func(a, b PublishedEvent) int { return cmp.Compare(a.ID, b.ID) })
+
+	h.subscribers[sub] = struct{}{}
+	return sub, missed, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers, sub)
+	}
+}
+
+// recentEvents returns the recent events of the named event, oldest first.
+func (h *subscriptionHub) recentEvents(event string) []PublishedEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.recent[event])
+}
+
+// handleEvents serves GET /events as a stream of server-sent events. The
+// "event" query parameter selects events (repeat it for several); by
+// default the stream has every event the caller may subscribe to.
+// Reconnecting clients send Last-Event-ID to receive recent events they
+// missed.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authResult, err := s.authFunc(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Authentication failed: %v", err), http.StatusUnauthorized)
+		return
+	}
+
+	names := r.URL.Query()["event"]
+	if len(names) == 0 {
+		for _, name := range slices.Sorted(maps.Keys(s.config.Events)) {
+			event := s.config.Events[name]
+			if event.CheckAccess(authResult.AccessGroups) {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, name := range names {
+		event, ok := s.config.Events[name]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Unknown event '%s'", name), http.StatusNotFound)
+			return
+		}
+		if !event.CheckAccess(authResult.AccessGroups) {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	lastID, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	sub, missed, unsubscribe := s.subscriptions.subscribe(names, lastID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": subscribed\n\n")
+	for _, published := range missed {
+		s.writeEvent(w, published)
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case published := <-sub.ch:
+			if published.ID <= lastID {
+				continue
+			}
+			s.writeEvent(w, published)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// encodeEvent returns published as delivered to subscribers: sensitive
+// payload values are redacted and field names are as on the REST API. SSE
+// streams and MCP resources both deliver events through it, so an event
+// has the same shape on either.
+func (s *Server) encodeEvent(published PublishedEvent) PublishedEvent {
+	if event, ok := s.config.Events[published.Event]; ok {
+		published.Payload = ont.ToWireNames(event.Payload, ont.Redact(event.Payload, published.Payload), s.fieldNaming)
+	}
+	return published
+}
+
+// writeEvent writes an event to an SSE stream.
+func (s *Server) writeEvent(w http.ResponseWriter, published PublishedEvent) {
+	data, err := json.Marshal(s.encodeEvent(published).Payload)
+	if err != nil {
+		s.logger.Error("Failed to encode event", "event", published.Event, "error", err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", published.ID, published.Event, data)
+}
+
+// withEventSubscriptions lets MCP clients subscribe to event resources.
+func (s *Server) withEventSubscriptions(opts *mcp.ServerOptions) *mcp.ServerOptions {
+	if len(s.config.Events) == 0 {
+		return opts
+	}
+	if opts == nil {
+		opts = &mcp.ServerOptions{}
+	}
+	opts.SubscribeHandler = func(ctx context.Context, req *mcp.SubscribeRequest) error {
+		_, err := s.eventResourceAccess(ctx, req.Params.URI)
+		return err
+	}
+	opts.UnsubscribeHandler = func(context.Context, *mcp.UnsubscribeRequest) error {
+		return nil
+	}
+	return opts
+}
+
+// addEventResources adds an MCP resource for each event, listing its
+// recent payloads, and sends resource updates to mcpServer's subscribers.
+func (s *Server) addEventResources(mcpServer *mcp.Server) {
+	if len(s.config.Events) == 0 {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.config.Events)) {
+		mcpServer.AddResource(&mcp.Resource{
+			URI:         EventResourcePrefix + name,
+			Name:        name + " events",
+			Description: s.config.Events[name].Description,
+			MIMEType:    "application/json",
+		}, s.readEventResource)
+	}
+	s.subscriptions.mu.Lock()
+	s.subscriptions.mcp = mcpServer
+	s.subscriptions.mu.Unlock()
+}
+
+func (s *Server) readEventResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	name, err := s.eventResourceAccess(ctx, req.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	recent := []PublishedEvent{}
+	for _, published := range s.subscriptions.recentEvents(name) {
+		recent = append(recent, s.encodeEvent(published))
+	}
+	data, err := json.Marshal(recent)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      req.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}},
+	}, nil
+}
+
+// eventResourceAccess returns the event named by an event resource URI,
+// if the caller may subscribe to it.
+func (s *Server) eventResourceAccess(ctx context.Context, uri string) (string, error) {
+	name, ok := strings.CutPrefix(uri, EventResourcePrefix)
+	event, exists := s.config.Events[name]
+	if !ok || !exists {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	httpReq, _ := ctx.Value(httpRequestKey).(*http.Request)
+	if httpReq == nil {
+		httpReq = &http.Request{Header: http.Header{}}
+	}
+	authResult, err := s.authFunc(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("authentication failed: %v", err)
+	}
+	if !event.CheckAccess(authResult.AccessGroups) {
+		return "", fmt.Errorf("access denied")
+	}
+	return name, nil
+}
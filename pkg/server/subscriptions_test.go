@@ -0,0 +1,181 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// eventServer returns a server with an itemUpdated event, whose apiKey is
+// sensitive, serving field names as snake_case.
+func eventServer(opts ...ServerOption) *Server {
+	config := testConfig()
+	config.Events = map[string]ont.Event{
+		"itemUpdated": {
+			Description: "An item was updated",
+			Payload:     ont.Object(map[string]ont.Schema{"itemId": ont.String(), "apiKey": ont.String().Sensitive()}),
+			Access:      []string{"admin"},
+		},
+	}
+	return New(config, append([]ServerOption{WithFieldNaming(ont.SnakeCase)}, opts...)...)
+}
+
+// wantEventPayload is the itemUpdated payload as delivered to subscribers.
+var wantEventPayload = map[string]any{"item_id": "i-1", "api_key": ont.RedactedValue}
+
+// openEvents opens an SSE stream from ts and reads up to the subscription
+// comment, so events published afterwards are delivered to it.
+func openEvents(t *testing.T, ts *httptest.Server, target string, headers ...string) *bufio.Reader {
+	t.Helper()
+	req, _ := http.NewRequest("GET", ts.URL+target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status %d, Content-Type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	stream := bufio.NewReader(resp.Body)
+	if line, err := stream.ReadString('\n'); err != nil || line != ": subscribed\n" {
+		t.Fatalf("first line %q, %v", line, err)
+	}
+	stream.ReadString('\n')
+	return stream
+}
+
+// readEvent reads the next event of an SSE stream as its field lines.
+func readEvent(t *testing.T, stream *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		if line == "\n" {
+			return lines
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+// eventData decodes the data line of an SSE event.
+func eventData(t *testing.T, lines []string) any {
+	t.Helper()
+	var payload any
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &payload); err != nil {
+		t.Fatalf("decoding %q: %v", lines[2], err)
+	}
+	return payload
+}
+
+func TestEventStream(t *testing.T) {
+	srv := eventServer(groups("admin"))
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	stream := openEvents(t, ts, "/events?event=itemUpdated")
+	if err := srv.Publish("itemUpdated", map[string]any{"itemId": "i-1", "apiKey": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	lines := readEvent(t, stream)
+	if len(lines) != 3 || lines[0] != "id: 1" || lines[1] != "event: itemUpdated" {
+		t.Fatalf("event = %q", lines)
+	}
+	if payload := eventData(t, lines); !reflect.DeepEqual(payload, wantEventPayload) {
+		t.Errorf("payload = %v, want %v", payload, wantEventPayload)
+	}
+}
+
+func TestEventStreamReplaysMissedEvents(t *testing.T) {
+	srv := eventServer(groups("admin"))
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	for _, id := range []string{"i-1", "i-2"} {
+		if err := srv.Publish("itemUpdated", map[string]any{"itemId": id, "apiKey": "secret"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream := openEvents(t, ts, "/events", "Last-Event-ID", "1")
+	lines := readEvent(t, stream)
+	if lines[0] != "id: 2" {
+		t.Fatalf("replayed %q, want only event 2", lines)
+	}
+	if payload := eventData(t, lines).(map[string]any); payload["item_id"] != "i-2" {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestEventStreamChecksEvents(t *testing.T) {
+	h := eventServer(groups("public")).Handler()
+	if resp, _ := do(t, h, "GET", "/events?event=itemUpdated", ""); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("event without access: status %d, want 403", resp.StatusCode)
+	}
+	if resp, _ := do(t, h, "GET", "/events?event=nope", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown event: status %d, want 404", resp.StatusCode)
+	}
+	if resp, _ := do(t, h, "POST", "/events", ""); resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestMCPEventSubscription(t *testing.T) {
+	srv := eventServer(groups("admin"))
+	updated := make(chan string, 1)
+	session := connectMCP(t, srv.Handler(), &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+	ctx := context.Background()
+	uri := EventResourcePrefix + "itemUpdated"
+	if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Publish("itemUpdated", map[string]any{"itemId": "i-1", "apiKey": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-updated:
+		if got != uri {
+			t.Errorf("updated %q, want %q", got, uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no resource update notification")
+	}
+
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recent []PublishedEvent
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &recent); err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || !reflect.DeepEqual(recent[0].Payload, wantEventPayload) {
+		t.Errorf("recent = %+v, want one event with payload %v", recent, wantEventPayload)
+	}
+}
+
+func TestMCPEventSubscriptionChecksAccess(t *testing.T) {
+	session := connectMCP(t, eventServer(groups("public")).Handler(), nil)
+	err := session.Subscribe(context.Background(), &mcp.SubscribeParams{URI: EventResourcePrefix + "itemUpdated"})
+	if err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("Subscribe() = %v, want access denied", err)
+	}
+}
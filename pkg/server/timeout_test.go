@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+func TestFunctionTimeout(t *testing.T) {
+	cancelled := make(chan error, 1)
+	config := testConfig()
+	fn := config.Functions["echo"]
+	fn.Timeout = 20 * time.Millisecond
+	fn.Resolver = func(ctx ont.Context, input any) (any, error) {
+		// A resolver passing the request's context upstream sees the deadline
+		requestCtx := ctx.Request().Context()
+		select {
+		case <-requestCtx.Done():
+			cancelled <- requestCtx.Err()
+		case <-time.After(5 * time.Second):
+			cancelled <- nil
+		}
+		return nil, requestCtx.Err()
+	}
+	config.Functions["echo"] = fn
+	fast := config.Functions["getItem"]
+	fast.Timeout = 5 * time.Second
+	config.Functions["getItem"] = fast
+	h := New(config).Handler()
+
+	resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`)
+	if resp.StatusCode != http.StatusGatewayTimeout || !strings.Contains(body, "function timed out after 20ms") {
+		t.Errorf("status %d: %s, want 504", resp.StatusCode, body)
+	}
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("resolver context error = %v, want DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("resolver context wasn't cancelled")
+	}
+
+	// Calls that finish in time are unaffected
+	if resp, body := do(t, h, "POST", "/api/getItem", `{"id":"1"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("getItem: status %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestFunctionTimeoutRecoversPanics(t *testing.T) {
+	config := testConfig()
+	fn := config.Functions["echo"]
+	fn.Timeout = 5 * time.Second
+	fn.Resolver = func(ctx ont.Context, input any) (any, error) {
+		var counts map[string]int
+		counts["calls"]++
+		return input, nil
+	}
+	config.Functions["echo"] = fn
+	h := New(config).Handler()
+
+	if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("status %d: %s, want 500", resp.StatusCode, body)
+	}
+	// The server keeps serving
+	if resp, body := do(t, h, "POST", "/api/getItem", `{"id":"1"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("getItem: status %d: %s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// ExamplesResourceURI is the MCP resource listing example calls of the
+// tools the caller may call.
+const ExamplesResourceURI = "resources://examples"
+
+// exampleTranscripts holds the calls recorded for the examples resource.
+// It is shared by the servers Reload creates.
+type exampleTranscripts struct {
+	record int
+
+	mu       sync.Mutex
+	recorded map[string][]ont.Transcript
+}
+
+// exampleTranscripts returns the server's transcripts, enabling them.
+func (s *Server) exampleTranscripts() *exampleTranscripts {
+	if s.transcripts == nil {
+		s.transcripts = &exampleTranscripts{recorded: make(map[string][]ont.Transcript)}
+	}
+	return s.transcripts
+}
+
+// WithExampleTranscripts adds an example call, made up from the function's
+// schemas and examples (see Function.FakeTranscript), to each MCP tool
+// description, and serves the examples as the ExamplesResourceURI
+// resource. Agents pick and fill in tools more reliably when they see a
+// call. Functions whose schemas ont can't make up a valid call for, such as
+// those with patterns, are left without an example.
+func WithExampleTranscripts() ServerOption {
+	return func(s *Server) {
+		s.exampleTranscripts()
+	}
+}
+
+// WithRecordedTranscripts enables WithExampleTranscripts and adds the last
+// n successful calls of each function to the examples resource, with
+// sensitive values redacted. Recorded calls are kept in memory only.
+func WithRecordedTranscripts(n int) ServerOption {
+	return func(s *Server) {
+		s.exampleTranscripts().record = n
+	}
+}
+
+// add records a successful call, keeping the last t.record of each function.
+func (t *exampleTranscripts) add(name string, transcript ont.Transcript) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	calls := append(t.recorded[name], transcript)
+	if len(calls) > t.record {
+		calls = calls[len(calls)-t.record:]
+	}
+	t.recorded[name] = calls
+}
+
+// calls returns the recorded calls of a function.
+func (t *exampleTranscripts) calls(name string) []ont.Transcript {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]ont.Transcript(nil), t.recorded[name]...)
+}
+
+// recordTranscript records a successful call if WithRecordedTranscripts is
+// enabled.
+func (s *Server) recordTranscript(name string, fn ont.Function, input, output any) {
+	if s.transcripts == nil || s.transcripts.record <= 0 {
+		return
+	}
+	s.transcripts.add(name, fn.RecordTranscript(input, output))
+}
+
+// describeTool returns the MCP tool description of fn for description,
+// with its long description and its examples, or a made-up example call
+// if it has none and WithExampleTranscripts is enabled.
+func (s *Server) describeTool(name string, fn ont.Function, description string) string {
+	description = withFieldReferences(fn, s.withLongDescription(fn, toolDescription(fn, description)))
+	if len(fn.Examples) > 0 {
+		for _, example := range fn.Examples {
+			description += "\n\n" + example.Format(fn, ont.FunctionIdentifier(name))
+		}
+		return description
+	}
+	if s.transcripts == nil {
+		return description
+	}
+	transcript, err := fn.FakeTranscript()
+	if err != nil {
+		s.logger.Debug("No example transcript", "function", name, "error", err)
+		return description
+	}
+	return description + "\n\n" + transcript.Format(name)
+}
+
+// readExamples serves ExamplesResourceURI: the example calls of the tools
+// the caller may call, by function name: declared examples, or else a
+// made-up call, and then recorded calls.
+func (s *Server) readExamples(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	httpReq, _ := ctx.Value(httpRequestKey).(*http.Request)
+	if httpReq == nil {
+		httpReq = &http.Request{Header: http.Header{}}
+	}
+	authResult, err := s.authFunc(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %v", err)
+	}
+
+	examples := make(map[string][]ont.Transcript)
+	for _, name := range s.registry.Callable(authResult.AccessGroups) {
+		_, fn, _ := s.registry.Lookup(name)
+		if !fn.IncludeInMcpListTools || fn.Internal {
+			continue
+		}
+		var transcripts []ont.Transcript
+		for _, example := range fn.Examples {
+			transcripts = append(transcripts, example.Transcript(fn))
+		}
+		if len(transcripts) == 0 {
+			if transcript, err := fn.FakeTranscript(); err == nil {
+				transcripts = append(transcripts, transcript)
+			}
+		}
+		transcripts = append(transcripts, s.transcripts.calls(name)...)
+		if len(transcripts) > 0 {
+			examples[name] = transcripts
+		}
+	}
+	data, err := json.Marshal(examples)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      req.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}},
+	}, nil
+}
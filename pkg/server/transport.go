@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// transportConfig tunes the HTTP server started by Serve.
+type transportConfig struct {
+	h2c                  bool
+	idleTimeout          time.Duration
+	noKeepAlives         bool
+	maxConcurrentStreams int
+}
+
+// WithH2C serves HTTP/2 without TLS alongside HTTP/1.1, for meshes and load
+// balancers that terminate TLS in front of the server. Clients must use
+// HTTP/2 with prior knowledge; the Upgrade: h2c handshake isn't supported.
+// The MCP streamable transport benefits most, since its concurrent requests
+// and event streams share one connection.
+func WithH2C() ServerOption {
+	return func(s *Server) {
+		s.transport.h2c = true
+	}
+}
+
+// WithIdleTimeout sets how long an idle keep-alive connection is kept open.
+// The default is no limit.
+func WithIdleTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.transport.idleTimeout = d
+	}
+}
+
+// WithoutKeepAlives closes HTTP/1.1 connections after each response, e.g.
+// so a load balancer rebalances every request.
+func WithoutKeepAlives() ServerOption {
+	return func(s *Server) {
+		s.transport.noKeepAlives = true
+	}
+}
+
+// WithMaxConcurrentStreams limits the concurrent requests a client may make
+// on one HTTP/2 connection. The default is 250.
+func WithMaxConcurrentStreams(n int) ServerOption {
+	return func(s *Server) {
+		s.transport.maxConcurrentStreams = n
+	}
+}
+
+// HTTPServer returns the http.Server Serve runs, configured with the
+// transport options, for applications that start it themselves (e.g. to
+// shut it down gracefully or serve TLS).
+func (s *Server) HTTPServer(addr string) *http.Server {
+	srv := &http.Server{
+		Addr:        addr,
+		Handler:     s.Handler(),
+		IdleTimeout: s.transport.idleTimeout,
+	}
+	srv.SetKeepAlivesEnabled(!s.transport.noKeepAlives)
+	s.configureHTTP2(srv)
+	return srv
+}
@@ -0,0 +1,13 @@
+//go:build !go1.24
+
+package server
+
+import "net/http"
+
+// configureHTTP2 logs that the HTTP/2 options are ignored: the standard
+// library only supports h2c and HTTP/2 settings from Go 1.24.
+func (s *Server) configureHTTP2(srv *http.Server) {
+	if s.transport.h2c || s.transport.maxConcurrentStreams > 0 {
+		s.logger.Warn("WithH2C and WithMaxConcurrentStreams need Go 1.24; using the default HTTP/2 settings")
+	}
+}
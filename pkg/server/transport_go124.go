@@ -0,0 +1,19 @@
+//go:build go1.24
+
+package server
+
+import "net/http"
+
+// configureHTTP2 applies the HTTP/2 options to srv. http.Protocols and
+// http.HTTP2Config are new in Go 1.24.
+func (s *Server) configureHTTP2(srv *http.Server) {
+	if s.transport.h2c {
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetHTTP2(true)
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
+	if s.transport.maxConcurrentStreams > 0 {
+		srv.HTTP2 = &http.HTTP2Config{MaxConcurrentStreams: s.transport.maxConcurrentStreams}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build go1.24
+
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestH2C(t *testing.T) {
+	srv := New(testConfig(), WithH2C(), WithMaxConcurrentStreams(10)).HTTPServer("")
+	if srv.HTTP2 == nil || srv.HTTP2.MaxConcurrentStreams != 10 {
+		t.Errorf("HTTP2 config = %+v, want 10 concurrent streams", srv.HTTP2)
+	}
+	url := serveHTTP(t, srv)
+
+	// HTTP/2 with prior knowledge, and HTTP/1.1 alongside it
+	h2c := &http.Transport{Protocols: new(http.Protocols)}
+	h2c.Protocols.SetUnencryptedHTTP2(true)
+	for proto, client := range map[string]*http.Client{
+		"HTTP/2.0": {Transport: h2c},
+		"HTTP/1.1": {},
+	} {
+		resp, err := client.Post(url+"/api/echo", "application/json", strings.NewReader(`{"message":"hi"}`))
+		if err != nil {
+			t.Fatalf("%s: %v", proto, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Proto != proto {
+			t.Errorf("status %d over %s, want 200 over %s", resp.StatusCode, resp.Proto, proto)
+		}
+	}
+	h2c.CloseIdleConnections()
+}
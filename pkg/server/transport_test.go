@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveHTTP serves srv on a local port until the test ends, and returns
+// its URL.
+func serveHTTP(t *testing.T, srv *http.Server) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return "http://" + l.Addr().String()
+}
+
+func TestHTTPServerOptions(t *testing.T) {
+	srv := New(testConfig(), WithIdleTimeout(time.Minute)).HTTPServer(":8080")
+	if srv.Addr != ":8080" || srv.IdleTimeout != time.Minute || srv.Handler == nil {
+		t.Errorf("server = addr %q, idle timeout %v, handler %v", srv.Addr, srv.IdleTimeout, srv.Handler)
+	}
+}
+
+func TestWithoutKeepAlives(t *testing.T) {
+	for _, tc := range []struct {
+		opts      []ServerOption
+		wantClose bool
+	}{
+		{nil, false},
+		{[]ServerOption{WithoutKeepAlives()}, true},
+	} {
+		url := serveHTTP(t, New(testConfig(), tc.opts...).HTTPServer(""))
+		resp, err := http.Post(url+"/api/echo", "application/json", strings.NewReader(`{"message":"hi"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Close != tc.wantClose {
+			t.Errorf("options %d: status %d, connection closed %v, want %v", len(tc.opts), resp.StatusCode, resp.Close, tc.wantClose)
+		}
+	}
+}
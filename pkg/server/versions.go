@@ -0,0 +1,39 @@
+package server
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// outputVersion returns the profile parameter of the Accept header, e.g.
+// "v1" for `application/json; profile="v1"`, or "" if there is none.
+func outputVersion(r *http.Request) string {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && params["profile"] != "" {
+			return params["profile"]
+		}
+	}
+	return ""
+}
+
+// versioned reports whether fn declares output versions.
+func versioned(fn ont.Function) bool {
+	return len(fn.OutputVersions) > 0 || fn.OutputProfile != ""
+}
+
+// checkOutputVersion answers 406 Not Acceptable if the call asks for an
+// output version fn doesn't serve. Functions without versions ignore the
+// profile. It returns false if the response was written.
+func checkOutputVersion(w http.ResponseWriter, r *http.Request, fn ont.Function) bool {
+	version := outputVersion(r)
+	if version == "" || !versioned(fn) || fn.HasOutputVersion(version) {
+		return true
+	}
+	http.Error(w, fmt.Sprintf("Unknown output version '%s'", version), http.StatusNotAcceptable)
+	return false
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// warmUpPhase is a named step of the server's warm-up.
+type warmUpPhase struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// warmUp runs the warm-up phases once and records the outcome for
+// /health/ready. It is shared by the servers Reload creates.
+type warmUp struct {
+	phases []warmUpPhase
+	once   sync.Once
+	done   chan struct{}
+	err    error
+}
+
+func newWarmUp() *warmUp {
+	return &warmUp{done: make(chan struct{})}
+}
+
+// state reports whether the warm-up has finished and how.
+func (w *warmUp) state() (bool, error) {
+	select {
+	case <-w.done:
+		return true, w.err
+	default:
+		return false, nil
+	}
+}
+
+// WithWarmUp adds a phase to the server's warm-up, such as loading a model
+// or filling a cache. Phases run once, in the order they were added, before
+// the Init of each function (see WarmUp).
+func WithWarmUp(name string, run func(ctx context.Context) error) ServerOption {
+	return func(s *Server) {
+		s.warmUp.phases = append(s.warmUp.phases, warmUpPhase{name, run})
+	}
+}
+
+// WarmUp runs the warm-up phases and then the Init of every function, once,
+// logging how long each took. Function calls over REST and MCP, event
+// streams and /health/ready answer 503 until it succeeds. Handler starts
+// it; call it to wait for the result. Later calls return the first result.
+func (s *Server) WarmUp(ctx context.Context) error {
+	w := s.warmUp
+	w.once.Do(func() {
+		defer close(w.done)
+		start := time.Now()
+		for _, phase := range w.phases {
+			phaseStart := time.Now()
+			if err := phase.run(ctx); err != nil {
+				w.err = fmt.Errorf("warm-up phase '%s' failed: %w", phase.name, err)
+				s.logger.Error("Warm-up failed", "phase", phase.name, "error", err)
+				return
+			}
+			s.logger.Info("Warm-up phase finished", "phase", phase.name, "duration", time.Since(phaseStart))
+		}
+		inits := s.liveServer().inits
+		for _, name := range inits.names() {
+			initStart := time.Now()
+			if err := inits[name].run(ctx); err != nil {
+				w.err = fmt.Errorf("initializing function '%s' failed: %w", name, err)
+				s.logger.Error("Warm-up failed", "function", name, "error", err)
+				return
+			}
+			s.logger.Info("Function initialized", "function", name, "duration", time.Since(initStart))
+		}
+		s.logger.Info("Warm-up complete", "phases", len(w.phases), "functions", len(inits), "duration", time.Since(start))
+	})
+	<-w.done
+	return w.err
+}
+
+// startWarmUp runs the warm-up in the background, or right away if there is
+// nothing to wait for, so a server without phases or Init functions serves
+// calls as soon as Handler returns.
+func (s *Server) startWarmUp() {
+	if len(s.warmUp.phases) == 0 && len(s.liveServer().inits) == 0 {
+		s.WarmUp(context.Background())
+		return
+	}
+	go s.WarmUp(context.Background())
+}
+
+// whenWarm serves calls with next once the warm-up has succeeded. Before,
+// or if it failed, it answers 503, so no call reaches a function that isn't
+// initialized or a config that isn't approved.
+func (s *Server) whenWarm(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		done, err := s.warmUp.state()
+		switch {
+		case !done:
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Server is warming up", http.StatusServiceUnavailable)
+		case err != nil:
+			http.Error(w, "Server failed to warm up", http.StatusServiceUnavailable)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// handleReady answers /health/ready: 200 once the warm-up has succeeded and
+// 503 before, or if it failed.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	done, err := s.warmUp.state()
+	status, code := "ready", http.StatusOK
+	body := map[string]string{}
+	switch {
+	case !done:
+		status, code = "warming up", http.StatusServiceUnavailable
+	case err != nil:
+		status, code = "failed", http.StatusServiceUnavailable
+		body["error"] = err.Error()
+	}
+	body["status"] = status
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+// functionInit runs a function's Init until it succeeds. A failed Init is
+// retried by the next call rather than failing every later one.
+type functionInit struct {
+	mu   sync.Mutex
+	done bool
+	init ont.InitFunc
+}
+
+func (i *functionInit) run(ctx context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.done {
+		return nil
+	}
+	if err := i.init(ctx); err != nil {
+		return err
+	}
+	i.done = true
+	return nil
+}
+
+// functionInits holds the Init of each function of a config that has one.
+// It is built with the routes, so a reloaded config initializes its
+// functions again.
+type functionInits map[string]*functionInit
+
+func newFunctionInits(config *ont.Config) functionInits {
+	inits := make(functionInits)
+	for name, fn := range config.Functions {
+		if fn.Init != nil {
+			inits[name] = &functionInit{init: fn.Init}
+		}
+	}
+	return inits
+}
+
+// names returns the function names in order.
+func (f functionInits) names() []string {
+	return slices.Sorted(maps.Keys(f))
+}
+
+// initialize runs the Init of the named function if it hasn't succeeded yet.
+func (s *Server) initialize(ctx ont.Context, name string) error {
+	i, ok := s.inits[name]
+	if !ok {
+		return nil
+	}
+	rc := context.Background()
+	if r := ctx.Request(); r != nil {
+		rc = r.Context()
+	}
+	if err := i.run(rc); err != nil {
+		s.logger.Error("Function initialization failed", "function", name, "error", err)
+		return fmt.Errorf("initializing function '%s' failed: %w", name, err)
+	}
+	return nil
+}
+
+// serveWarm serves srv on l while the warm-up runs, so /health answers
+// from the start, and /health/ready and calls once the server is warm. If
+// the warm-up fails, the server is closed and the error returned.
+func (s *Server) serveWarm(srv *http.Server, l net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(l)
+	}()
+	if err := s.WarmUp(context.Background()); err != nil {
+		srv.Close()
+		<-served
+		return err
+	}
+	return <-served
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCallsWaitForWarmUp(t *testing.T) {
+	release := make(chan struct{})
+	srv := New(testConfig(), WithWarmUp("cache", func(ctx context.Context) error {
+		<-release
+		return nil
+	}))
+	h := srv.Handler()
+
+	resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`)
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("call while warming up: status %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	if resp, _ := do(t, h, "POST", "/mcp", `{}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("MCP while warming up: status %d, want 503", resp.StatusCode)
+	}
+	if resp, _ := do(t, h, "GET", "/health", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("/health while warming up: status %d, want 200", resp.StatusCode)
+	}
+
+	close(release)
+	if err := srv.WarmUp(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("call after warm-up: status %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestCallsRefusedAfterFailedWarmUp(t *testing.T) {
+	srv := New(testConfig(), WithWarmUp("cache", func(ctx context.Context) error {
+		return errors.New("cache unavailable")
+	}))
+	h := srv.Handler()
+	if err := srv.WarmUp(context.Background()); err == nil {
+		t.Fatal("expected warm-up error")
+	}
+	if resp, _ := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("call after failed warm-up: status %d, want 503", resp.StatusCode)
+	}
+	if resp, _ := do(t, h, "GET", "/health/ready", ""); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("/health/ready after failed warm-up: status %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestHandlerWithoutWarmUpServesImmediately(t *testing.T) {
+	h := New(testConfig()).Handler()
+	if resp, body := do(t, h, "POST", "/api/echo", `{"message":"hi"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+const (
+	// DefaultWatchTimeout is how long a watch request waits for a change
+	// before responding 304 Not Modified.
+	DefaultWatchTimeout = 30 * time.Second
+
+	// DefaultWatchPollInterval is how often a waiting watch request re-runs
+	// the resolver to pick up changes that were not announced with NotifyChange.
+	DefaultWatchPollInterval = 5 * time.Second
+)
+
+// WithWatchTimeout sets the longest a watch request waits for a change.
+// Clients may ask for less with the "timeout" query parameter (in seconds).
+func WithWatchTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.watchTimeout = d
+	}
+}
+
+// WithWatchPollInterval sets how often waiting watch requests re-run the
+// resolver. Set it to 0 to rely on NotifyChange alone.
+func WithWatchPollInterval(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.watchPoll = d
+	}
+}
+
+// NotifyChange wakes the watch requests waiting on a watchable function so
+// they re-run its resolver immediately. Call it after writing data the
+// function reports.
+func (s *Server) NotifyChange(function string) {
+	s.changes.notify(function)
+}
+
+// changeHub broadcasts change notifications to waiting watch requests.
+type changeHub struct {
+	mu      sync.Mutex
+	waiters map[string]chan struct{}
+}
+
+func newChangeHub() *changeHub {
+	return &changeHub{waiters: make(map[string]chan struct{})}
+}
+
+// wait returns a channel that is closed on the next notify for function.
+func (h *changeHub) wait(function string) <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch, ok := h.waiters[function]
+	if !ok {
+		ch = make(chan struct{})
+		h.waiters[function] = ch
+	}
+	return ch
+}
+
+func (h *changeHub) notify(function string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ch, ok := h.waiters[function]; ok {
+		close(ch)
+		delete(h.waiters, function)
+	}
+}
+
+// handleWatch serves POST /api/{name}/watch. It runs the resolver until the
+// returned cursor differs from the caller's "since" cursor (taken from the
+// input or the If-None-Match header), then responds like the function
+// endpoint. If nothing changes before the timeout it responds 304 Not Modified.
+func (s *Server) handleWatch(name string, fn ont.Function) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		authResult, input, ok := s.readCall(w, r, name, fn)
+		if !ok {
+			return
+		}
+
+		since, _ := input[ont.WatchSinceField].(string)
+		if since == "" {
+			since = ont.ParseETag(r.Header.Get("If-None-Match"))
+			if since != "" {
+				input[ont.WatchSinceField] = since
+			}
+		}
+
+		timeout := s.watchTimeout
+		if v := r.URL.Query().Get("timeout"); v != "" {
+			seconds, err := strconv.Atoi(v)
+			if err != nil || seconds < 0 {
+				http.Error(w, "Invalid timeout", http.StatusBadRequest)
+				return
+			}
+			if requested := time.Duration(seconds) * time.Second; requested < timeout {
+				timeout = requested
+			}
+		}
+		deadline := time.NewTimer(timeout)
+		defer deadline.Stop()
+
+		for {
+			// Subscribe before running the resolver so no notification is missed
+			changed := s.changes.wait(name)
+
+			ctx := s.callContext(r, name, authResult, ensureRequestID(r), "")
+			warnDeprecated(r.Context(), ctx, name, fn, input)
+			output, err := s.execute(ctx, name, fn, input, s.streamsOutput(r, fn))
+			if err != nil {
+				s.writeExecuteError(w, name, fn, err)
+				return
+			}
+
+			cursor, _ := ont.WatchCursor(output)
+			if since == "" || cursor != since {
+				s.writeOutput(w, r, name, fn, ctx, output, start)
+				return
+			}
+
+			var poll <-chan time.Time
+			if s.watchPoll > 0 {
+				poll = time.After(s.watchPoll)
+			}
+
+			select {
+			case <-changed:
+			case <-poll:
+			case <-deadline.C:
+				w.Header().Set("ETag", ont.FormatETag(since))
+				w.WriteHeader(http.StatusNotModified)
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+// watchServer returns a server with a watchable listItems function whose
+// cursor is "v" followed by version, and a channel that receives each time
+// the resolver runs.
+func watchServer(version *atomic.Int32, opts ...ServerOption) (*Server, <-chan struct{}) {
+	resolved := make(chan struct{}, 10)
+	config := testConfig()
+	config.Functions["listItems"] = ont.Function{
+		Description: "List items",
+		Access:      []string{"admin", "public"},
+		IsReadOnly:  true,
+		Watchable:   true,
+		Inputs:      ont.Object(map[string]ont.Schema{"since": ont.String()}).Optional("since"),
+		Outputs:     ont.Object(map[string]ont.Schema{"cursor": ont.String()}),
+		Resolver: func(ctx ont.Context, input any) (any, error) {
+			select {
+			case resolved <- struct{}{}:
+			default:
+			}
+			return map[string]any{"cursor": fmt.Sprintf("v%d", version.Load())}, nil
+		},
+	}
+	return New(config, opts...), resolved
+}
+
+func TestWatchTimesOut(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	srv, _ := watchServer(&version, WithWatchTimeout(20*time.Millisecond), WithWatchPollInterval(0))
+	h := srv.Handler()
+
+	for _, tc := range []struct {
+		name, body string
+		headers    []string
+	}{
+		{"since input", `{"since":"v1"}`, nil},
+		{"If-None-Match", `{}`, []string{"If-None-Match", ont.FormatETag("v1")}},
+	} {
+		resp, body := do(t, h, "POST", "/api/listItems/watch", tc.body, tc.headers...)
+		if resp.StatusCode != http.StatusNotModified || resp.Header.Get("ETag") != ont.FormatETag("v1") {
+			t.Errorf("%s: status %d, ETag %q: %s", tc.name, resp.StatusCode, resp.Header.Get("ETag"), body)
+		}
+	}
+
+	// A stale cursor returns at once
+	if resp, body := do(t, h, "POST", "/api/listItems/watch", `{"since":"v0"}`); resp.StatusCode != http.StatusOK {
+		t.Errorf("stale cursor: status %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestWatchTimeoutParameter(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	srv, _ := watchServer(&version, WithWatchPollInterval(0))
+	h := srv.Handler()
+
+	// The server's default would wait 30 seconds
+	start := time.Now()
+	if resp, _ := do(t, h, "POST", "/api/listItems/watch?timeout=0", `{"since":"v1"}`); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("timeout=0: status %d, want 304", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("timeout=0 waited %s", elapsed)
+	}
+	if resp, _ := do(t, h, "POST", "/api/listItems/watch?timeout=soon", `{"since":"v1"}`); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid timeout: status %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestWatchWakesOnNotifyChange(t *testing.T) {
+	var version atomic.Int32
+	version.Store(1)
+	srv, resolved := watchServer(&version, WithWatchTimeout(10*time.Second), WithWatchPollInterval(0))
+	h := srv.Handler()
+
+	type response struct {
+		status int
+		body   string
+	}
+	done := make(chan response, 1)
+	go func() {
+		resp, body := do(t, h, "POST", "/api/listItems/watch", `{"since":"v1"}`)
+		done <- response{resp.StatusCode, body}
+	}()
+
+	// Once the resolver has run the request is waiting for a change
+	<-resolved
+	version.Store(2)
+	srv.NotifyChange("listItems")
+
+	select {
+	case got := <-done:
+		var output map[string]any
+		json.Unmarshal([]byte(got.body), &output)
+		if got.status != http.StatusOK || output["cursor"] != "v2" {
+			t.Errorf("status %d: %s, want the v2 output", got.status, got.body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch didn't wake on NotifyChange")
+	}
+}
@@ -0,0 +1,185 @@
+// Package localfs provides a store.ObjectStore backed by a local directory,
+// for development and single-node deployments. Signed URLs point at the
+// store's own Handler, which must be mounted at the configured base URL.
+package localfs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// Store keeps objects as files under a root directory.
+type Store struct {
+	root    string
+	baseURL string
+	secret  []byte
+	now     func() time.Time
+}
+
+// New creates a store rooted at dir. Signed URLs are baseURL + "/" + key
+// (e.g. "https://api.example.com/files") and are authenticated with an
+// HMAC of secret.
+func New(dir, baseURL string, secret []byte) (*Store, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("localfs: a signing secret is required")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("localfs: failed to create %s: %w", dir, err)
+	}
+	return &Store{
+		root:    dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+		now:     time.Now,
+	}, nil
+}
+
+var _ store.ObjectStore = (*Store)(nil)
+
+// Put writes the object atomically via a temporary file. The content type is
+// not stored; Handler infers it from the key's extension.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("localfs: failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// Get opens an object, or returns store.ErrNotFound.
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("object '%s': %w", key, store.ErrNotFound)
+	}
+	return f, err
+}
+
+// Delete removes an object.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL returns a URL served by Handler that is valid until expires elapses.
+func (s *Store) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	deadline := strconv.FormatInt(s.now().Add(expires).Unix(), 10)
+	query := url.Values{
+		"expires": {deadline},
+		"sig":     {s.sign(key, deadline)},
+	}
+	return s.baseURL + "/" + escapeKey(key) + "?" + query.Encode(), nil
+}
+
+// Handler serves objects for valid, unexpired signed URLs. Mount it with the
+// base URL's path stripped, e.g.
+//
+//	mux.Handle("/files/", http.StripPrefix("/files", objects.Handler()))
+func (s *Store) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		deadline := r.URL.Query().Get("expires")
+		sig := r.URL.Query().Get("sig")
+		if !hmac.Equal([]byte(sig), []byte(s.sign(key, deadline))) {
+			http.Error(w, "Invalid signature", http.StatusForbidden)
+			return
+		}
+		unix, err := strconv.ParseInt(deadline, 10, 64)
+		if err != nil || s.now().Unix() > unix {
+			http.Error(w, "URL expired", http.StatusForbidden)
+			return
+		}
+
+		file, err := s.path(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.ServeContent(w, r, path.Base(key), info.ModTime(), f)
+	})
+}
+
+func (s *Store) sign(key, deadline string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + deadline))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key to a file under root, rejecting keys that escape it.
+func (s *Store) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean != "/"+key {
+		return "", fmt.Errorf("localfs: invalid object key %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(clean)), nil
+}
+
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
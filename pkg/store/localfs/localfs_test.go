@@ -0,0 +1,98 @@
+package localfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+func TestPutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(t.TempDir(), "http://localhost/files", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put(ctx, "exports/report.json", strings.NewReader(`{"ok":true}`), "application/json"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	r, err := s.Get(ctx, "exports/report.json")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	body, _ := io.ReadAll(r)
+	r.Close()
+	if string(body) != `{"ok":true}` {
+		t.Errorf("Get = %q", body)
+	}
+
+	if err := s.Delete(ctx, "exports/report.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "exports/report.json"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "exports/report.json"); err != nil {
+		t.Errorf("deleting a missing object should succeed: %v", err)
+	}
+}
+
+func TestInvalidKeys(t *testing.T) {
+	s, err := New(t.TempDir(), "http://localhost/files", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "../escape", "a/../../b", "/abs", "a//b"} {
+		if err := s.Put(context.Background(), key, strings.NewReader("x"), ""); err == nil {
+			t.Errorf("Put(%q) should fail", key)
+		}
+	}
+}
+
+func TestSignedURLHandler(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(t.TempDir(), "/files", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	if err := s.Put(ctx, "results/a b.json", strings.NewReader(`[1,2]`), "application/json"); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := s.SignedURL(ctx, "results/a b.json", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/files/", http.StripPrefix("/files", s.Handler()))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	if rec := get(signed); rec.Code != http.StatusOK || rec.Body.String() != `[1,2]` {
+		t.Errorf("signed GET = %d %q", rec.Code, rec.Body.String())
+	}
+
+	if rec := get(strings.Replace(signed, "sig=", "sig=0", 1)); rec.Code != http.StatusForbidden {
+		t.Errorf("tampered signature = %d, want 403", rec.Code)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if rec := get(signed); rec.Code != http.StatusForbidden {
+		t.Errorf("expired URL = %d, want 403", rec.Code)
+	}
+}
@@ -0,0 +1,166 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// entry is a value with an optional expiry (zero means none).
+type entry[T any] struct {
+	value     T
+	expiresAt time.Time
+}
+
+func (e entry[T]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// Counter is an in-memory store.Counter. Counts are local to the process,
+// so limits are per replica.
+type Counter struct {
+	mu       sync.Mutex
+	counters map[string]entry[int64]
+	now      func() time.Time
+}
+
+// NewCounter creates an empty counter.
+func NewCounter() *Counter {
+	return &Counter{counters: make(map[string]entry[int64]), now: time.Now}
+}
+
+var _ store.Counter = (*Counter)(nil)
+
+// Incr adds delta to the counter for key in the current window.
+func (c *Counter) Incr(ctx context.Context, key string, delta int64, window time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	e, ok := c.counters[key]
+	if !ok || e.expired(now) {
+		e = entry[int64]{expiresAt: expiry(now, window)}
+	}
+	e.value += delta
+	c.counters[key] = e
+	return e.value, nil
+}
+
+// Cache is an in-memory store.Cache.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[string]entry[[]byte]
+	now     func() time.Time
+}
+
+// NewCache creates an empty cache.
+func NewCache() *Cache {
+	return &Cache{entries: make(map[string]entry[[]byte]), now: time.Now}
+}
+
+var _ store.Cache = (*Cache)(nil)
+
+// Get returns the value for key and whether it was found.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || e.expired(c.now()) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	return append([]byte(nil), e.value...), true, nil
+}
+
+// Set stores value for key.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry[[]byte]{value: append([]byte(nil), value...), expiresAt: expiry(c.now(), ttl)}
+	return nil
+}
+
+// SetNX stores value only if key is absent.
+func (c *Cache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if e, ok := c.entries[key]; ok && !e.expired(now) {
+		return false, nil
+	}
+	c.entries[key] = entry[[]byte]{value: append([]byte(nil), value...), expiresAt: expiry(now, ttl)}
+	return true, nil
+}
+
+// Delete removes key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	return nil
+}
+
+// Sessions is an in-memory store.SessionStore.
+type Sessions struct {
+	mu       sync.Mutex
+	sessions map[string]*store.Session
+	now      func() time.Time
+}
+
+// NewSessions creates an empty session store.
+func NewSessions() *Sessions {
+	return &Sessions{sessions: make(map[string]*store.Session), now: time.Now}
+}
+
+var _ store.SessionStore = (*Sessions)(nil)
+
+// GetSession returns a session, or store.ErrNotFound.
+func (s *Sessions) GetSession(ctx context.Context, id string) (*store.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || !s.now().Before(session.ExpiresAt) {
+		delete(s.sessions, id)
+		return nil, fmt.Errorf("session '%s': %w", id, store.ErrNotFound)
+	}
+	return cloneSession(session), nil
+}
+
+// SaveSession stores a session until its ExpiresAt.
+func (s *Sessions) SaveSession(ctx context.Context, session *store.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[session.ID] = cloneSession(session)
+	return nil
+}
+
+// DeleteSession removes a session.
+func (s *Sessions) DeleteSession(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+	return nil
+}
+
+func cloneSession(session *store.Session) *store.Session {
+	clone := *session
+	clone.Data = cloneMap(session.Data)
+	return &clone
+}
@@ -0,0 +1,100 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// fakeClock is a controllable time source.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func TestCounterWindow(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := NewCounter()
+	c.now = clock.now
+
+	for i := int64(1); i <= 3; i++ {
+		got, err := c.Incr(ctx, "user:1", 1, time.Minute)
+		if err != nil || got != i {
+			t.Fatalf("Incr #%d = %d, %v", i, got, err)
+		}
+	}
+
+	clock.t = clock.t.Add(time.Minute)
+	if got, _ := c.Incr(ctx, "user:1", 1, time.Minute); got != 1 {
+		t.Errorf("Incr after window = %d, want 1", got)
+	}
+	if got, _ := c.Incr(ctx, "user:2", 5, time.Minute); got != 5 {
+		t.Errorf("Incr on other key = %d, want 5", got)
+	}
+}
+
+func TestCacheExpiryAndSetNX(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	c := NewCache()
+	c.now = clock.now
+
+	if err := c.Set(ctx, "k", []byte("v1"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if val, ok, _ := c.Get(ctx, "k"); !ok || string(val) != "v1" {
+		t.Errorf("Get = %q, %v", val, ok)
+	}
+
+	if stored, _ := c.SetNX(ctx, "k", []byte("v2"), time.Second); stored {
+		t.Error("SetNX should not replace a live key")
+	}
+
+	clock.t = clock.t.Add(time.Second)
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Error("expired key should not be found")
+	}
+	if stored, _ := c.SetNX(ctx, "k", []byte("v2"), 0); !stored {
+		t.Error("SetNX should claim an expired key")
+	}
+
+	if err := c.Delete(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := c.Get(ctx, "k"); ok {
+		t.Error("deleted key should not be found")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	s := NewSessions()
+	s.now = clock.now
+
+	session := &store.Session{
+		ID:        "s1",
+		Data:      map[string]any{"userId": "u1"},
+		ExpiresAt: clock.t.Add(time.Hour),
+	}
+	if err := s.SaveSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetSession(ctx, "s1")
+	if err != nil || got.Data["userId"] != "u1" {
+		t.Fatalf("GetSession = %+v, %v", got, err)
+	}
+
+	clock.t = clock.t.Add(time.Hour)
+	if _, err := s.GetSession(ctx, "s1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expired GetSession error = %v, want ErrNotFound", err)
+	}
+}
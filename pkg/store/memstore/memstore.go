@@ -0,0 +1,185 @@
+// Package memstore provides an in-memory store.Store for examples, tests and
+// prototypes. It enforces the same versioning rules as the database-backed
+// stores, so optimistic concurrency can be exercised before one is wired in.
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// Store is an in-memory store.Store. It is safe for concurrent use.
+// Records are deep-copied on the way in and out, so callers can't mutate stored data.
+type Store struct {
+	mu          sync.RWMutex
+	collections map[string]map[string]*store.Record
+	now         func() time.Time
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithClock sets the clock used for CreatedAt and UpdatedAt.
+func WithClock(now func() time.Time) Option {
+	return func(s *Store) {
+		s.now = now
+	}
+}
+
+// New creates an empty store.
+func New(opts ...Option) *Store {
+	s := &Store{
+		collections: make(map[string]map[string]*store.Record),
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ store.Store = (*Store)(nil)
+
+// Get returns a record, or store.ErrNotFound.
+func (s *Store) Get(ctx context.Context, collection, id string) (*store.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.collections[collection][id]
+	if !ok {
+		return nil, notFound(collection, id)
+	}
+	return cloneRecord(rec), nil
+}
+
+// List returns all records in a collection ordered by ID.
+func (s *Store) List(ctx context.Context, collection string) ([]*store.Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]*store.Record, 0, len(s.collections[collection]))
+	for _, rec := range s.collections[collection] {
+		records = append(records, cloneRecord(rec))
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+// Create stores a new record at version 1.
+func (s *Store) Create(ctx context.Context, collection, id string, data map[string]any) (*store.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, ok := s.collections[collection]
+	if !ok {
+		records = make(map[string]*store.Record)
+		s.collections[collection] = records
+	}
+
+	if id == "" {
+		id = store.NewID()
+	}
+	if _, exists := records[id]; exists {
+		return nil, fmt.Errorf("%s '%s': %w", collection, id, store.ErrExists)
+	}
+
+	now := s.now()
+	rec := &store.Record{
+		ID:        id,
+		Version:   1,
+		Data:      cloneMap(data),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	records[id] = rec
+	return cloneRecord(rec), nil
+}
+
+// Update replaces a record's data if expectedVersion matches.
+func (s *Store) Update(ctx context.Context, collection, id string, data map[string]any, expectedVersion int64) (*store.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.checkVersion(collection, id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Data = cloneMap(data)
+	rec.Version++
+	rec.UpdatedAt = s.now()
+	return cloneRecord(rec), nil
+}
+
+// Delete removes a record if expectedVersion matches.
+func (s *Store) Delete(ctx context.Context, collection, id string, expectedVersion int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.checkVersion(collection, id, expectedVersion); err != nil {
+		return err
+	}
+	delete(s.collections[collection], id)
+	return nil
+}
+
+// checkVersion returns the stored record if it exists at expectedVersion.
+// The caller must hold the write lock.
+func (s *Store) checkVersion(collection, id string, expectedVersion int64) (*store.Record, error) {
+	rec, ok := s.collections[collection][id]
+	if !ok {
+		return nil, notFound(collection, id)
+	}
+	if expectedVersion != store.AnyVersion && rec.Version != expectedVersion {
+		return nil, &store.ConflictError{
+			Collection: collection,
+			ID:         id,
+			Expected:   expectedVersion,
+			Actual:     rec.Version,
+		}
+	}
+	return rec, nil
+}
+
+func notFound(collection, id string) error {
+	return fmt.Errorf("%s '%s': %w", collection, id, store.ErrNotFound)
+}
+
+func cloneRecord(rec *store.Record) *store.Record {
+	clone := *rec
+	clone.Data = cloneMap(rec.Data)
+	return &clone
+}
+
+func cloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return map[string]any{}
+	}
+	clone := make(map[string]any, len(m))
+	for k, v := range m {
+		clone[k] = cloneValue(v)
+	}
+	return clone
+}
+
+func cloneValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return cloneMap(val)
+	case []any:
+		clone := make([]any, len(val))
+		for i, item := range val {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return v
+	}
+}
@@ -0,0 +1,122 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+func TestCRUD(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	created, err := s.Create(ctx, "users", "u1", map[string]any{"name": "Ada"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if created.Version != 1 {
+		t.Errorf("new record version = %d, want 1", created.Version)
+	}
+
+	if _, err := s.Create(ctx, "users", "u1", map[string]any{}); !errors.Is(err, store.ErrExists) {
+		t.Errorf("duplicate Create error = %v, want ErrExists", err)
+	}
+
+	generated, err := s.Create(ctx, "users", "", map[string]any{"name": "Grace"})
+	if err != nil || generated.ID == "" {
+		t.Fatalf("Create with empty id = %+v, %v", generated, err)
+	}
+
+	updated, err := s.Update(ctx, "users", "u1", map[string]any{"name": "Ada L."}, 1)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if updated.Version != 2 || updated.Data["name"] != "Ada L." {
+		t.Errorf("updated record = %+v", updated)
+	}
+
+	records, err := s.List(ctx, "users")
+	if err != nil || len(records) != 2 {
+		t.Fatalf("List = %d records, %v", len(records), err)
+	}
+
+	if err := s.Delete(ctx, "users", "u1", 2); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := s.Get(ctx, "users", "u1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	if _, err := s.Create(ctx, "orders", "o1", map[string]any{"status": "new"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Update(ctx, "orders", "o1", map[string]any{"status": "paid"}, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second writer still holding version 1 loses
+	_, err := s.Update(ctx, "orders", "o1", map[string]any{"status": "cancelled"}, 1)
+	if !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("stale Update error = %v, want ErrConflict", err)
+	}
+	var conflict *store.ConflictError
+	if !errors.As(err, &conflict) || conflict.Expected != 1 || conflict.Actual != 2 {
+		t.Errorf("conflict = %+v", conflict)
+	}
+
+	if err := s.Delete(ctx, "orders", "o1", 1); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("stale Delete error = %v, want ErrConflict", err)
+	}
+
+	// AnyVersion skips the check
+	if _, err := s.Update(ctx, "orders", "o1", map[string]any{"status": "shipped"}, store.AnyVersion); err != nil {
+		t.Errorf("unconditional Update failed: %v", err)
+	}
+}
+
+func TestRecordsAreCopied(t *testing.T) {
+	ctx := context.Background()
+	s := New()
+
+	data := map[string]any{"tags": []any{"a"}}
+	if _, err := s.Create(ctx, "posts", "p1", data); err != nil {
+		t.Fatal(err)
+	}
+	data["tags"].([]any)[0] = "mutated"
+
+	rec, _ := s.Get(ctx, "posts", "p1")
+	rec.Data["title"] = "mutated"
+
+	again, _ := s.Get(ctx, "posts", "p1")
+	if again.Data["tags"].([]any)[0] != "a" || again.Data["title"] != nil {
+		t.Errorf("stored data was mutated: %+v", again.Data)
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64
+		wantErr bool
+	}{
+		{"", store.AnyVersion, false},
+		{"*", store.AnyVersion, false},
+		{store.ETag(3), 3, false},
+		{`W/"4"`, 4, false},
+		{`"abc"`, 0, true},
+		{`"0"`, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := store.IfMatchVersion(tt.header)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("IfMatchVersion(%q) = %d, %v", tt.header, got, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is a database/sql driver that checks the statements a store runs
+// against a script of expectations, in order, and answers them with the
+// scripted rows. It stands in for PostgreSQL so the SQL paths run without
+// a server.
+type fakeDB struct {
+	t            *testing.T
+	mu           sync.Mutex
+	expectations []*expectation
+}
+
+// expectation is a statement the store should run next.
+type expectation struct {
+	query    string
+	args     []driver.Value
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// returns answers the statement with rows of columns.
+func (e *expectation) returns(columns []string, rows ...[]driver.Value) *expectation {
+	e.columns, e.rows = columns, rows
+	return e
+}
+
+// fails answers the statement with err.
+func (e *expectation) fails(err error) *expectation {
+	e.err = err
+	return e
+}
+
+// affects answers an Exec with n affected rows.
+func (e *expectation) affects(n int64) *expectation {
+	e.affected = n
+	return e
+}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("postgres-fake", fakeDriver{})
+}
+
+// newFakeDB returns a *sql.DB whose statements are checked by the returned
+// fakeDB. The test fails if expectations are left when it ends.
+func newFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	f := &fakeDB{t: t}
+	fakeDBsMu.Lock()
+	fakeDBs[t.Name()] = f
+	fakeDBsMu.Unlock()
+
+	db, err := sql.Open("postgres-fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+		fakeDBsMu.Lock()
+		delete(fakeDBs, t.Name())
+		fakeDBsMu.Unlock()
+		for _, e := range f.expectations {
+			t.Errorf("statement not run: %s", e.query)
+		}
+	})
+	return db, f
+}
+
+// expect adds a statement containing query, run with args.
+func (f *fakeDB) expect(query string, args ...driver.Value) *expectation {
+	e := &expectation{query: query, args: args}
+	f.expectations = append(f.expectations, e)
+	return e
+}
+
+// next checks a statement against the next expectation and returns it, or
+// an error if the statement isn't the expected one.
+func (f *fakeDB) next(query string, args []driver.NamedValue) (*expectation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.expectations) == 0 {
+		return nil, fmt.Errorf("unexpected statement: %s", query)
+	}
+	e := f.expectations[0]
+	f.expectations = f.expectations[1:]
+	if !strings.Contains(strings.Join(strings.Fields(query), " "), e.query) {
+		return nil, fmt.Errorf("statement %q doesn't contain %q", query, e.query)
+	}
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	if len(e.args) > 0 && !reflect.DeepEqual(values, e.args) {
+		return nil, fmt.Errorf("statement %q: args %v, want %v", e.query, values, e.args)
+	}
+	return e, nil
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+	f, ok := fakeDBs[name]
+	if !ok {
+		return nil, fmt.Errorf("no fake database %s", name)
+	}
+	return &fakeConn{db: f}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements aren't supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.db.next(query, args)
+	if err != nil {
+		c.db.t.Error(err)
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return driver.RowsAffected(e.affected), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.db.next(query, args)
+	if err != nil {
+		c.db.t.Error(err)
+		return nil, err
+	}
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &fakeRows{columns: e.columns, rows: e.rows}, nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
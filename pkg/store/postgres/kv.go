@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// expiresAt is the SQL expiry of a TTL in milliseconds passed as parameter
+// n, computed with the database's clock so replicas agree on it. A TTL of 0
+// never expires.
+func expiresAt(n int) string {
+	return fmt.Sprintf(`CASE WHEN $%[1]d::bigint > 0 THEN now() + $%[1]d::bigint * interval '1 millisecond' END`, n)
+}
+
+// Counter is a store.Counter backed by the ont_counters table, shared by
+// every replica using the same database.
+type Counter struct {
+	db *sql.DB
+}
+
+// NewCounter creates a counter using db. Run Migrate before first use.
+func NewCounter(db *sql.DB) *Counter {
+	return &Counter{db: db}
+}
+
+var _ store.Counter = (*Counter)(nil)
+
+// Incr adds delta to the counter for key in the current window. The upsert
+// resets counters whose window has ended, so concurrent increments are
+// atomic.
+func (c *Counter) Incr(ctx context.Context, key string, delta int64, window time.Duration) (int64, error) {
+	var n int64
+	err := c.db.QueryRowContext(ctx,
+		`INSERT INTO ont_counters AS c (key, value, expires_at)
+		VALUES ($1, $2, `+expiresAt(3)+`)
+		ON CONFLICT (key) DO UPDATE SET
+			value = CASE WHEN c.expires_at <= now() THEN EXCLUDED.value ELSE c.value + EXCLUDED.value END,
+			expires_at = CASE WHEN c.expires_at <= now() THEN EXCLUDED.expires_at ELSE c.expires_at END
+		RETURNING value`,
+		key, delta, window.Milliseconds()).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("postgres counter %s: %w", key, err)
+	}
+	return n, nil
+}
+
+// Cache is a store.Cache backed by the ont_cache table. Expired values are
+// ignored and replaced on write; DeleteExpired removes them.
+type Cache struct {
+	db *sql.DB
+}
+
+// NewCache creates a cache using db. Run Migrate before first use.
+func NewCache(db *sql.DB) *Cache {
+	return &Cache{db: db}
+}
+
+var _ store.Cache = (*Cache)(nil)
+
+// Get returns the value for key and whether it was found.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := c.db.QueryRowContext(ctx,
+		`SELECT value FROM ont_cache WHERE key = $1 AND (expires_at IS NULL OR expires_at > now())`,
+		key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("postgres cache get %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Set stores value for key.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO ont_cache (key, value, expires_at)
+		VALUES ($1, $2, `+expiresAt(3)+`)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`,
+		key, value, ttl.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("postgres cache set %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetNX stores value only if key is absent or expired.
+func (c *Cache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	result, err := c.db.ExecContext(ctx,
+		`INSERT INTO ont_cache AS c (key, value, expires_at)
+		VALUES ($1, $2, `+expiresAt(3)+`)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+		WHERE c.expires_at <= now()`,
+		key, value, ttl.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("postgres cache setnx %s: %w", key, err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("postgres cache setnx %s: %w", key, err)
+	}
+	return affected == 1, nil
+}
+
+// Delete removes key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if _, err := c.db.ExecContext(ctx, `DELETE FROM ont_cache WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("postgres cache delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Sessions is a store.SessionStore backed by the ont_sessions table.
+type Sessions struct {
+	db *sql.DB
+}
+
+// NewSessions creates a session store using db. Run Migrate before first use.
+func NewSessions(db *sql.DB) *Sessions {
+	return &Sessions{db: db}
+}
+
+var _ store.SessionStore = (*Sessions)(nil)
+
+// GetSession returns a session, or store.ErrNotFound.
+func (s *Sessions) GetSession(ctx context.Context, id string) (*store.Session, error) {
+	session := store.Session{ID: id}
+	var raw []byte
+	err := s.db.QueryRowContext(ctx,
+		`SELECT data, expires_at FROM ont_sessions WHERE id = $1 AND expires_at > now()`,
+		id).Scan(&raw, &session.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("session '%s': %w", id, store.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("postgres session get %s: %w", id, err)
+	}
+	if err := json.Unmarshal(raw, &session.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode session %s: %w", id, err)
+	}
+	return &session, nil
+}
+
+// SaveSession stores a session until its ExpiresAt.
+func (s *Sessions) SaveSession(ctx context.Context, session *store.Session) error {
+	raw, err := marshalData(session.Data)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO ont_sessions (id, data, expires_at)
+		VALUES ($1, $2::jsonb, $3)
+		ON CONFLICT (id) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at`,
+		session.ID, raw, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("postgres session save %s: %w", session.ID, err)
+	}
+	return nil
+}
+
+// DeleteSession removes a session.
+func (s *Sessions) DeleteSession(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM ont_sessions WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("postgres session delete %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteExpired removes expired counters, cache values and sessions, which
+// the stores otherwise only ignore. Run it periodically, e.g. hourly.
+func DeleteExpired(ctx context.Context, db *sql.DB) error {
+	for _, table := range []string{"ont_counters", "ont_cache", "ont_sessions"} {
+		if _, err := db.ExecContext(ctx, `DELETE FROM `+table+` WHERE expires_at <= now()`); err != nil {
+			return fmt.Errorf("failed to delete expired rows from %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+func TestCounter(t *testing.T) {
+	db, f := newFakeDB(t)
+	c := NewCounter(db)
+
+	f.expect("INSERT INTO ont_counters", "calls:admin", int64(1), int64(60000)).
+		returns([]string{"value"}, []driver.Value{int64(3)})
+	if n, err := c.Incr(context.Background(), "calls:admin", 1, time.Minute); err != nil || n != 3 {
+		t.Errorf("Incr() = %d, %v, want 3", n, err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	db, f := newFakeDB(t)
+	c := NewCache(db)
+	ctx := context.Background()
+
+	// SetNX claims a key once; the upsert only replaces expired values
+	f.expect("WHERE c.expires_at <= now()", "idem:1", []byte("pending"), int64(60000)).affects(1)
+	f.expect("WHERE c.expires_at <= now()", "idem:1", []byte("pending"), int64(60000)).affects(0)
+	for i, want := range []bool{true, false} {
+		if ok, err := c.SetNX(ctx, "idem:1", []byte("pending"), time.Minute); err != nil || ok != want {
+			t.Errorf("SetNX() #%d = %v, %v, want %v", i+1, ok, err, want)
+		}
+	}
+
+	f.expect("SELECT value FROM ont_cache", "idem:1").returns([]string{"value"}, []driver.Value{[]byte("done")})
+	if value, ok, err := c.Get(ctx, "idem:1"); err != nil || !ok || string(value) != "done" {
+		t.Errorf("Get() = %q, %v, %v", value, ok, err)
+	}
+	f.expect("SELECT value FROM ont_cache", "idem:2").returns([]string{"value"})
+	if _, ok, err := c.Get(ctx, "idem:2"); err != nil || ok {
+		t.Errorf("Get() of a missing key = %v, %v", ok, err)
+	}
+
+	f.expect("ON CONFLICT (key) DO UPDATE", "k", []byte("v"), int64(0)).affects(1)
+	if err := c.Set(ctx, "k", []byte("v"), 0); err != nil {
+		t.Errorf("Set() = %v", err)
+	}
+	f.expect("DELETE FROM ont_cache", "k").fails(errors.New("connection reset"))
+	if err := c.Delete(ctx, "k"); err == nil {
+		t.Error("Delete() should fail when the database does")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := NewSessions(db)
+	ctx := context.Background()
+	expires := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f.expect("INSERT INTO ont_sessions", "s-1", `{"userId":"u-1"}`, expires).affects(1)
+	if err := s.SaveSession(ctx, &store.Session{ID: "s-1", Data: map[string]any{"userId": "u-1"}, ExpiresAt: expires}); err != nil {
+		t.Fatalf("SaveSession() = %v", err)
+	}
+
+	f.expect("expires_at > now()", "s-1").
+		returns([]string{"data", "expires_at"}, []driver.Value{[]byte(`{"userId":"u-1"}`), expires})
+	session, err := s.GetSession(ctx, "s-1")
+	want := &store.Session{ID: "s-1", Data: map[string]any{"userId": "u-1"}, ExpiresAt: expires}
+	if err != nil || !reflect.DeepEqual(session, want) {
+		t.Errorf("GetSession() = %+v, %v, want %+v", session, err, want)
+	}
+
+	f.expect("expires_at > now()", "s-2").returns([]string{"data", "expires_at"})
+	if _, err := s.GetSession(ctx, "s-2"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetSession() of a missing session = %v, want ErrNotFound", err)
+	}
+
+	f.expect("DELETE FROM ont_sessions", "s-1").affects(1)
+	if err := s.DeleteSession(ctx, "s-1"); err != nil {
+		t.Errorf("DeleteSession() = %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migration is an embedded SQL file, applied in version order.
+type migration struct {
+	version string
+	sql     string
+}
+
+// migrations returns the embedded migrations sorted by file name
+// (e.g. "0001_records").
+func migrations() ([]migration, error) {
+	files, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	result := make([]migration, 0, len(files))
+	for _, file := range files {
+		content, err := migrationsFS.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+		version := strings.TrimSuffix(strings.TrimPrefix(file, "migrations/"), ".sql")
+		result = append(result, migration{version: version, sql: string(content)})
+	}
+	return result, nil
+}
+
+// Migrate applies any embedded migrations that have not been applied yet,
+// creating the tables of every store in the package. See Migrate.
+func (s *Store) Migrate(ctx context.Context) error {
+	return Migrate(ctx, s.db)
+}
+
+// Migrate applies any embedded migrations to db that have not been applied
+// yet. Each migration runs in its own transaction under an advisory lock,
+// so concurrent instances starting up don't race.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS ont_schema_migrations (
+    version    TEXT        PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	pending, err := migrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("migration %s: %w", m.version, err)
+		}
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('ont_schema_migrations'))`); err != nil {
+		return err
+	}
+
+	var applied bool
+	err = tx.QueryRowContext(ctx, `SELECT true FROM ont_schema_migrations WHERE version = $1`, m.version).Scan(&applied)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO ont_schema_migrations (version) VALUES ($1)`, m.version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	ms, err := migrations()
+	if err != nil {
+		t.Fatalf("migrations() failed: %v", err)
+	}
+	if len(ms) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	if ms[0].version != "0001_records" {
+		t.Errorf("first migration = %q, want 0001_records", ms[0].version)
+	}
+	if !strings.Contains(ms[0].sql, "CREATE TABLE IF NOT EXISTS ont_records") {
+		t.Error("first migration should create ont_records")
+	}
+
+	for i := 1; i < len(ms); i++ {
+		if ms[i-1].version >= ms[i].version {
+			t.Errorf("migrations out of order: %s before %s", ms[i-1].version, ms[i].version)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	db, f := newFakeDB(t)
+	ms, err := migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first migration was applied by another instance; the rest run
+	f.expect("CREATE TABLE IF NOT EXISTS ont_schema_migrations")
+	for i, m := range ms {
+		f.expect("pg_advisory_xact_lock")
+		applied := f.expect("SELECT true FROM ont_schema_migrations", m.version)
+		if i == 0 {
+			applied.returns([]string{"bool"}, []driver.Value{true})
+			continue
+		}
+		applied.returns([]string{"bool"})
+		f.expect(strings.Join(strings.Fields(m.sql), " "))
+		f.expect("INSERT INTO ont_schema_migrations", m.version)
+	}
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate() = %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+-- Versioned records backing store.Store.
+CREATE TABLE IF NOT EXISTS ont_records (
+    collection  TEXT        NOT NULL,
+    id          TEXT        NOT NULL,
+    version     BIGINT      NOT NULL,
+    data        JSONB       NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (collection, id)
+);
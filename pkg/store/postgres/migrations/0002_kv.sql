@@ -0,0 +1,13 @@
+-- Fixed-window counters backing store.Counter, and values with a TTL
+-- backing store.Cache. A NULL expires_at never expires.
+CREATE TABLE IF NOT EXISTS ont_counters (
+    key         TEXT        PRIMARY KEY,
+    value       BIGINT      NOT NULL,
+    expires_at  TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS ont_cache (
+    key         TEXT        PRIMARY KEY,
+    value       BYTEA       NOT NULL,
+    expires_at  TIMESTAMPTZ
+);
@@ -0,0 +1,8 @@
+-- Sessions backing store.SessionStore.
+CREATE TABLE IF NOT EXISTS ont_sessions (
+    id          TEXT        PRIMARY KEY,
+    data        JSONB       NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ont_sessions_expires_at ON ont_sessions (expires_at);
@@ -0,0 +1,187 @@
+// Package postgres provides PostgreSQL-backed stores: a store.Store for
+// records, and a store.Counter, store.Cache and store.SessionStore for rate
+// limits, idempotency keys and sessions shared across replicas.
+//
+// It uses database/sql and does not import a driver; open the *sql.DB with
+// the driver of your choice (e.g. github.com/jackc/pgx/v5/stdlib or
+// github.com/lib/pq) and call Migrate once at startup:
+//
+//	db, err := sql.Open("pgx", os.Getenv("DATABASE_URL"))
+//	if err := postgres.Migrate(ctx, db); err != nil {
+//		log.Fatal(err)
+//	}
+//	records, sessions := postgres.New(db), postgres.NewSessions(db)
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// Store is a store.Store backed by the ont_records table.
+type Store struct {
+	db *sql.DB
+}
+
+// New creates a store using db. Run Migrate before first use.
+func New(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+var _ store.Store = (*Store)(nil)
+
+const recordColumns = `id, version, data, created_at, updated_at`
+
+// Get returns a record, or store.ErrNotFound.
+func (s *Store) Get(ctx context.Context, collection, id string) (*store.Record, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+recordColumns+` FROM ont_records WHERE collection = $1 AND id = $2`,
+		collection, id)
+	rec, err := scanRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound(collection, id)
+	}
+	return rec, err
+}
+
+// List returns all records in a collection ordered by ID.
+func (s *Store) List(ctx context.Context, collection string) ([]*store.Record, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+recordColumns+` FROM ont_records WHERE collection = $1 ORDER BY id`,
+		collection)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*store.Record{}
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
+// Create stores a new record at version 1.
+func (s *Store) Create(ctx context.Context, collection, id string, data map[string]any) (*store.Record, error) {
+	if id == "" {
+		id = store.NewID()
+	}
+	raw, err := marshalData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	row := s.db.QueryRowContext(ctx,
+		`INSERT INTO ont_records (collection, id, version, data)
+		VALUES ($1, $2, 1, $3::jsonb)
+		ON CONFLICT (collection, id) DO NOTHING
+		RETURNING `+recordColumns,
+		collection, id, raw)
+	rec, err := scanRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s '%s': %w", collection, id, store.ErrExists)
+	}
+	return rec, err
+}
+
+// Update replaces a record's data if expectedVersion matches.
+func (s *Store) Update(ctx context.Context, collection, id string, data map[string]any, expectedVersion int64) (*store.Record, error) {
+	raw, err := marshalData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	row := s.db.QueryRowContext(ctx,
+		`UPDATE ont_records
+		SET data = $3::jsonb, version = version + 1, updated_at = now()
+		WHERE collection = $1 AND id = $2 AND ($4::bigint = 0 OR version = $4::bigint)
+		RETURNING `+recordColumns,
+		collection, id, raw, expectedVersion)
+	rec, err := scanRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, s.writeFailed(ctx, collection, id, expectedVersion)
+	}
+	return rec, err
+}
+
+// Delete removes a record if expectedVersion matches.
+func (s *Store) Delete(ctx context.Context, collection, id string, expectedVersion int64) error {
+	result, err := s.db.ExecContext(ctx,
+		`DELETE FROM ont_records
+		WHERE collection = $1 AND id = $2 AND ($3::bigint = 0 OR version = $3::bigint)`,
+		collection, id, expectedVersion)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return s.writeFailed(ctx, collection, id, expectedVersion)
+	}
+	return nil
+}
+
+// writeFailed explains why a conditional write matched no rows: the record
+// is missing or its version has moved on.
+func (s *Store) writeFailed(ctx context.Context, collection, id string, expectedVersion int64) error {
+	var actual int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version FROM ont_records WHERE collection = $1 AND id = $2`,
+		collection, id).Scan(&actual)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound(collection, id)
+	}
+	if err != nil {
+		return err
+	}
+	return &store.ConflictError{
+		Collection: collection,
+		ID:         id,
+		Expected:   expectedVersion,
+		Actual:     actual,
+	}
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRecord(row scanner) (*store.Record, error) {
+	var rec store.Record
+	var raw []byte
+	if err := row.Scan(&rec.ID, &rec.Version, &raw, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &rec.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode record %s: %w", rec.ID, err)
+	}
+	return &rec, nil
+}
+
+// marshalData encodes data as a JSON string; drivers send []byte as bytea.
+func marshalData(data map[string]any) (string, error) {
+	if data == nil {
+		data = map[string]any{}
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode record data: %w", err)
+	}
+	return string(raw), nil
+}
+
+func notFound(collection, id string) error {
+	return fmt.Errorf("%s '%s': %w", collection, id, store.ErrNotFound)
+}
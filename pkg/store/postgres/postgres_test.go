@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+var recordColumnNames = []string{"id", "version", "data", "created_at", "updated_at"}
+
+func recordRow(id string, version int64, data string) []driver.Value {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []driver.Value{id, version, []byte(data), now, now}
+}
+
+func TestStoreCreateAndGet(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := New(db)
+	ctx := context.Background()
+
+	f.expect("INSERT INTO ont_records", "orders", "o-1", `{"total":5}`).
+		returns(recordColumnNames, recordRow("o-1", 1, `{"total":5}`))
+	rec, err := s.Create(ctx, "orders", "o-1", map[string]any{"total": 5})
+	if err != nil || rec.Version != 1 || !reflect.DeepEqual(rec.Data, map[string]any{"total": 5.0}) {
+		t.Fatalf("Create() = %+v, %v", rec, err)
+	}
+
+	// ON CONFLICT DO NOTHING returns no row for a taken ID
+	f.expect("INSERT INTO ont_records").returns(recordColumnNames)
+	if _, err := s.Create(ctx, "orders", "o-1", nil); !errors.Is(err, store.ErrExists) {
+		t.Errorf("Create() of a taken ID = %v, want ErrExists", err)
+	}
+
+	f.expect("FROM ont_records WHERE collection = $1 AND id = $2", "orders", "o-2").returns(recordColumnNames)
+	if _, err := s.Get(ctx, "orders", "o-2"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Get() of a missing record = %v, want ErrNotFound", err)
+	}
+
+	f.expect("ORDER BY id", "orders").
+		returns(recordColumnNames, recordRow("o-1", 1, `{}`), recordRow("o-3", 2, `{}`))
+	records, err := s.List(ctx, "orders")
+	if err != nil || len(records) != 2 || records[1].ID != "o-3" {
+		t.Errorf("List() = %v, %v", records, err)
+	}
+}
+
+func TestStoreConditionalWrites(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := New(db)
+	ctx := context.Background()
+
+	f.expect("UPDATE ont_records", "orders", "o-1", `{"total":6}`, int64(1)).
+		returns(recordColumnNames, recordRow("o-1", 2, `{"total":6}`))
+	if rec, err := s.Update(ctx, "orders", "o-1", map[string]any{"total": 6}, 1); err != nil || rec.Version != 2 {
+		t.Fatalf("Update() = %+v, %v", rec, err)
+	}
+
+	// A stale version matches no row; the current version explains why
+	f.expect("UPDATE ont_records").returns(recordColumnNames)
+	f.expect("SELECT version FROM ont_records", "orders", "o-1").returns([]string{"version"}, []driver.Value{int64(2)})
+	_, err := s.Update(ctx, "orders", "o-1", nil, 1)
+	var conflict *store.ConflictError
+	if !errors.As(err, &conflict) || conflict.Expected != 1 || conflict.Actual != 2 {
+		t.Errorf("Update() with a stale version = %v, want a conflict at version 2", err)
+	}
+
+	f.expect("DELETE FROM ont_records", "orders", "o-9", int64(0)).affects(0)
+	f.expect("SELECT version FROM ont_records").returns([]string{"version"})
+	if err := s.Delete(ctx, "orders", "o-9", store.AnyVersion); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("Delete() of a missing record = %v, want ErrNotFound", err)
+	}
+
+	f.expect("DELETE FROM ont_records", "orders", "o-1", int64(2)).affects(1)
+	if err := s.Delete(ctx, "orders", "o-1", 2); err != nil {
+		t.Errorf("Delete() = %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package redis provides Redis-backed counters, caches and sessions, so
+// rate limits, quotas and idempotency keys are shared across replicas.
+//
+// Every operation runs as a Lua script, which keeps compound updates atomic
+// and means the package only needs a client that can EVAL. It does not import
+// a Redis library; wrap the client you already use, e.g. for go-redis:
+//
+//	type goRedis struct{ *redis.Client }
+//
+//	func (c goRedis) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		v, err := c.Client.Eval(ctx, script, keys, args...).Result()
+//		if errors.Is(err, redis.Nil) {
+//			return nil, nil
+//		}
+//		return v, err
+//	}
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// Client runs a Lua script. A nil reply must be returned as (nil, nil),
+// integers as int64 and bulk strings as string or []byte.
+type Client interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// Option configures the Redis-backed stores.
+type Option func(*options)
+
+type options struct {
+	prefix string
+}
+
+// WithPrefix sets the prefix for every key (default "ont:").
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{prefix: "ont:"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+const (
+	// incrScript adds ARGV[1] and starts the window on the first increment.
+	incrScript = `local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return v`
+
+	getScript = `return redis.call('GET', KEYS[1])`
+
+	// setScript sets KEYS[1] with an optional expiry in milliseconds (0 = none).
+	setScript = `if tonumber(ARGV[2]) > 0 then
+  return redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+end
+return redis.call('SET', KEYS[1], ARGV[1])`
+
+	setNXScript = `if tonumber(ARGV[2]) > 0 then
+  return redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2])
+end
+return redis.call('SET', KEYS[1], ARGV[1], 'NX')`
+
+	delScript = `return redis.call('DEL', KEYS[1])`
+)
+
+// Counter is a store.Counter shared by every replica using the same Redis.
+type Counter struct {
+	client Client
+	prefix string
+}
+
+// NewCounter creates a counter. Keys are stored as "<prefix>counter:<key>".
+func NewCounter(client Client, opts ...Option) *Counter {
+	return &Counter{client: client, prefix: newOptions(opts).prefix + "counter:"}
+}
+
+var _ store.Counter = (*Counter)(nil)
+
+// Incr adds delta to the counter for key in the current window.
+func (c *Counter) Incr(ctx context.Context, key string, delta int64, window time.Duration) (int64, error) {
+	reply, err := c.client.Eval(ctx, incrScript, []string{c.prefix + key}, delta, window.Milliseconds())
+	if err != nil {
+		return 0, fmt.Errorf("redis counter %s: %w", key, err)
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis counter %s: unexpected reply %T", key, reply)
+	}
+	return n, nil
+}
+
+// Cache is a store.Cache backed by Redis strings.
+type Cache struct {
+	client Client
+	prefix string
+}
+
+// NewCache creates a cache. Keys are stored as "<prefix>cache:<key>".
+func NewCache(client Client, opts ...Option) *Cache {
+	return &Cache{client: client, prefix: newOptions(opts).prefix + "cache:"}
+}
+
+var _ store.Cache = (*Cache)(nil)
+
+// Get returns the value for key and whether it was found.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.client.Eval(ctx, getScript, []string{c.prefix + key})
+	if err != nil {
+		return nil, false, fmt.Errorf("redis cache get %s: %w", key, err)
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, err := replyBytes(reply)
+	if err != nil {
+		return nil, false, fmt.Errorf("redis cache get %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Set stores value for key.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if _, err := c.client.Eval(ctx, setScript, []string{c.prefix + key}, value, ttl.Milliseconds()); err != nil {
+		return fmt.Errorf("redis cache set %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetNX stores value only if key is absent.
+func (c *Cache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	reply, err := c.client.Eval(ctx, setNXScript, []string{c.prefix + key}, value, ttl.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("redis cache setnx %s: %w", key, err)
+	}
+	return reply != nil, nil
+}
+
+// Delete removes key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if _, err := c.client.Eval(ctx, delScript, []string{c.prefix + key}); err != nil {
+		return fmt.Errorf("redis cache delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Sessions is a store.SessionStore that keeps each session as JSON under a
+// key expiring at the session's ExpiresAt.
+type Sessions struct {
+	client Client
+	prefix string
+	now    func() time.Time
+}
+
+// NewSessions creates a session store. Keys are stored as "<prefix>session:<id>".
+func NewSessions(client Client, opts ...Option) *Sessions {
+	return &Sessions{client: client, prefix: newOptions(opts).prefix + "session:", now: time.Now}
+}
+
+var _ store.SessionStore = (*Sessions)(nil)
+
+// GetSession returns a session, or store.ErrNotFound.
+func (s *Sessions) GetSession(ctx context.Context, id string) (*store.Session, error) {
+	reply, err := s.client.Eval(ctx, getScript, []string{s.prefix + id})
+	if err != nil {
+		return nil, fmt.Errorf("redis session get %s: %w", id, err)
+	}
+	if reply == nil {
+		return nil, fmt.Errorf("session '%s': %w", id, store.ErrNotFound)
+	}
+	raw, err := replyBytes(reply)
+	if err != nil {
+		return nil, fmt.Errorf("redis session get %s: %w", id, err)
+	}
+	var session store.Session
+	if err := json.Unmarshal(raw, &session); err != nil {
+		return nil, fmt.Errorf("failed to decode session %s: %w", id, err)
+	}
+	return &session, nil
+}
+
+// SaveSession stores a session until its ExpiresAt. Sessions that have
+// already expired are deleted instead.
+func (s *Sessions) SaveSession(ctx context.Context, session *store.Session) error {
+	ttl := session.ExpiresAt.Sub(s.now())
+	if ttl.Milliseconds() <= 0 {
+		return s.DeleteSession(ctx, session.ID)
+	}
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session %s: %w", session.ID, err)
+	}
+	if _, err := s.client.Eval(ctx, setScript, []string{s.prefix + session.ID}, raw, ttl.Milliseconds()); err != nil {
+		return fmt.Errorf("redis session save %s: %w", session.ID, err)
+	}
+	return nil
+}
+
+// DeleteSession removes a session.
+func (s *Sessions) DeleteSession(ctx context.Context, id string) error {
+	if _, err := s.client.Eval(ctx, delScript, []string{s.prefix + id}); err != nil {
+		return fmt.Errorf("redis session delete %s: %w", id, err)
+	}
+	return nil
+}
+
+func replyBytes(reply any) ([]byte, error) {
+	switch v := reply.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unexpected reply %T", reply)
+	}
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// fakeClient emulates the package's scripts against an in-memory keyspace,
+// replying the way Redis clients do (int64, string or nil).
+type fakeClient struct {
+	values map[string]string
+	counts map[string]int64
+	ttls   map[string]int64
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{values: map[string]string{}, counts: map[string]int64{}, ttls: map[string]int64{}}
+}
+
+func (f *fakeClient) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+	key := keys[0]
+	switch script {
+	case incrScript:
+		f.counts[key] += args[0].(int64)
+		if _, ok := f.ttls[key]; !ok {
+			f.ttls[key] = args[1].(int64)
+		}
+		return f.counts[key], nil
+	case getScript:
+		v, ok := f.values[key]
+		if !ok {
+			return nil, nil
+		}
+		return v, nil
+	case setScript:
+		f.values[key] = string(args[0].([]byte))
+		f.ttls[key] = args[1].(int64)
+		return "OK", nil
+	case setNXScript:
+		if _, ok := f.values[key]; ok {
+			return nil, nil
+		}
+		f.values[key] = string(args[0].([]byte))
+		f.ttls[key] = args[1].(int64)
+		return "OK", nil
+	case delScript:
+		delete(f.values, key)
+		return int64(1), nil
+	}
+	return nil, errors.New("unknown script")
+}
+
+func TestCounter(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	c := NewCounter(client, WithPrefix("app:"))
+
+	c.Incr(ctx, "ip:1", 1, time.Minute)
+	got, err := c.Incr(ctx, "ip:1", 2, time.Minute)
+	if err != nil || got != 3 {
+		t.Fatalf("Incr = %d, %v", got, err)
+	}
+	if client.ttls["app:counter:ip:1"] != 60000 {
+		t.Errorf("window = %dms, want 60000", client.ttls["app:counter:ip:1"])
+	}
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	c := NewCache(client)
+
+	if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Get(missing) = %v, %v", ok, err)
+	}
+
+	if err := c.Set(ctx, "k", []byte("v"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if val, ok, _ := c.Get(ctx, "k"); !ok || string(val) != "v" {
+		t.Errorf("Get = %q, %v", val, ok)
+	}
+	if client.ttls["ont:cache:k"] != 1000 {
+		t.Errorf("ttl = %dms, want 1000", client.ttls["ont:cache:k"])
+	}
+
+	if stored, _ := c.SetNX(ctx, "k", []byte("other"), 0); stored {
+		t.Error("SetNX should not replace an existing key")
+	}
+	if stored, _ := c.SetNX(ctx, "idem", []byte("1"), 0); !stored {
+		t.Error("SetNX should claim a missing key")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	s := NewSessions(client)
+	now := time.Unix(1000, 0)
+	s.now = func() time.Time { return now }
+
+	session := &store.Session{ID: "s1", Data: map[string]any{"userId": "u1"}, ExpiresAt: now.Add(time.Hour)}
+	if err := s.SaveSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+	if client.ttls["ont:session:s1"] != time.Hour.Milliseconds() {
+		t.Errorf("ttl = %dms, want one hour", client.ttls["ont:session:s1"])
+	}
+
+	got, err := s.GetSession(ctx, "s1")
+	if err != nil || got.Data["userId"] != "u1" {
+		t.Fatalf("GetSession = %+v, %v", got, err)
+	}
+
+	// Saving an expired session deletes it
+	session.ExpiresAt = now.Add(-time.Second)
+	if err := s.SaveSession(ctx, session); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetSession(ctx, "s1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("GetSession error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,295 @@
+// Package s3 provides a store.ObjectStore for Amazon S3 and S3-compatible
+// services (Google Cloud Storage interoperability mode, MinIO, R2). Requests
+// are signed with AWS Signature Version 4 using only the standard library.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanna-ai/ont-run/pkg/store"
+)
+
+// Config identifies a bucket and the credentials to access it.
+type Config struct {
+	Bucket          string
+	Region          string // e.g. "us-east-1"; GCS uses "auto"
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoint overrides the service URL, e.g. "https://storage.googleapis.com"
+	// for GCS or "http://localhost:9000" for MinIO. Defaults to AWS.
+	Endpoint string
+	// PathStyle addresses the bucket as endpoint/bucket/key instead of
+	// bucket.endpoint/key. Most S3-compatible services need it.
+	PathStyle bool
+	// HTTPClient defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Store is an S3-backed store.ObjectStore.
+type Store struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// New creates a store for the configured bucket.
+func New(config Config) (*Store, error) {
+	if config.Bucket == "" || config.Region == "" {
+		return nil, fmt.Errorf("s3: bucket and region are required")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3: credentials are required")
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Store{config: config, client: client, now: time.Now}, nil
+}
+
+var _ store.ObjectStore = (*Store)(nil)
+
+// Put uploads an object. The body is buffered so its length can be sent.
+func (s *Store) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("s3: failed to read %s: %w", key, err)
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Get downloads an object, or returns store.ErrNotFound.
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL returns a presigned GET URL. S3 allows at most seven days.
+func (s *Store) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	u := s.objectURL(key)
+	now := s.now().UTC()
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {"AWS4-HMAC-SHA256"},
+		"X-Amz-Credential":    {s.config.AccessKeyID + "/" + s.scope(now)},
+		"X-Amz-Date":          {now.Format(amzDateFormat)},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(expires/time.Second), 10)},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	headers := http.Header{}
+	headers.Set("Host", u.Host)
+	signature := s.signature(http.MethodGet, u, headers, unsignedBody, now)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+const (
+	amzDateFormat = "20060102T150405Z"
+	unsignedBody  = "UNSIGNED-PAYLOAD"
+)
+
+func (s *Store) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	req.Header.Set("Host", u.Host)
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", unsignedBody)
+
+	signature := s.signature(method, u, req.Header, unsignedBody, now)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, s.scope(now), signedHeaders(req.Header), signature))
+	return req, nil
+}
+
+// do sends a request and turns error statuses into errors.
+func (s *Store) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("object '%s': %w", req.URL.Path, store.ErrNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3: %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, msg)
+	}
+	return resp, nil
+}
+
+// objectURL builds the virtual-hosted or path-style URL of an object.
+func (s *Store) objectURL(key string) *url.URL {
+	endpoint := s.config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + s.config.Region + ".amazonaws.com"
+		if s.config.Region == "us-east-1" {
+			endpoint = "https://s3.amazonaws.com"
+		}
+	}
+	u, _ := url.Parse(endpoint)
+
+	escaped := uriEncode(key, false)
+	if s.config.PathStyle {
+		u.Path = "/" + s.config.Bucket + "/" + key
+		u.RawPath = "/" + uriEncode(s.config.Bucket, true) + "/" + escaped
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+		u.Path = "/" + key
+		u.RawPath = "/" + escaped
+	}
+	return u
+}
+
+func (s *Store) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+// signature computes the SigV4 signature of a request.
+func (s *Store) signature(method string, u *url.URL, headers http.Header, payloadHash string, now time.Time) string {
+	canonical := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		u.RawQuery,
+		canonicalHeaders(headers),
+		signedHeaders(headers),
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format(amzDateFormat),
+		s.scope(now),
+		sha256Hex(canonical),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func canonicalHeaders(headers http.Header) string {
+	var b strings.Builder
+	for _, name := range sortedHeaderNames(headers) {
+		b.WriteString(name + ":" + strings.TrimSpace(headers.Get(name)) + "\n")
+	}
+	return b.String()
+}
+
+func signedHeaders(headers http.Header) string {
+	return strings.Join(sortedHeaderNames(headers), ";")
+}
+
+func sortedHeaderNames(headers http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// canonicalQuery encodes query parameters sorted by name, as SigV4 requires.
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, uriEncode(k, true)+"="+uriEncode(values.Get(k), true))
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved
+// characters, and '/' unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
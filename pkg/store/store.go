@@ -0,0 +1,159 @@
+// Package store defines the persistence interface used by ontology resolvers
+// and built-in subsystems, along with its errors and conditional request helpers.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	ont "github.com/vanna-ai/ont-run/pkg/ontology"
+)
+
+var (
+	// ErrNotFound is returned when a record does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrExists is returned when creating a record whose ID is taken.
+	ErrExists = errors.New("record already exists")
+	// ErrConflict is returned when a write's expected version is stale.
+	ErrConflict = errors.New("version conflict")
+)
+
+// AnyVersion disables the version check on Update and Delete.
+const AnyVersion int64 = 0
+
+// Record is a stored entity. Version starts at 1 and increments on every update.
+type Record struct {
+	ID        string         `json:"id"`
+	Version   int64          `json:"version"`
+	Data      map[string]any `json:"data"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// Store is a CRUD store of records grouped into collections (typically one per entity).
+type Store interface {
+	// Get returns a record, or ErrNotFound.
+	Get(ctx context.Context, collection, id string) (*Record, error)
+
+	// List returns all records in a collection ordered by ID.
+	List(ctx context.Context, collection string) ([]*Record, error)
+
+	// Create stores a new record at version 1. An empty id is replaced with a
+	// generated one. Returns ErrExists if the id is taken.
+	Create(ctx context.Context, collection, id string, data map[string]any) (*Record, error)
+
+	// Update replaces a record's data if its current version is expectedVersion
+	// (or expectedVersion is AnyVersion) and returns it at the next version.
+	// Returns ErrNotFound or a *ConflictError.
+	Update(ctx context.Context, collection, id string, data map[string]any, expectedVersion int64) (*Record, error)
+
+	// Delete removes a record if its current version is expectedVersion
+	// (or expectedVersion is AnyVersion). Returns ErrNotFound or a *ConflictError.
+	Delete(ctx context.Context, collection, id string, expectedVersion int64) error
+}
+
+// Counter counts events in fixed time windows, for rate limits and quotas.
+type Counter interface {
+	// Incr adds delta to the counter for key and returns the new total. The
+	// counter starts at zero and resets window after its first increment.
+	Incr(ctx context.Context, key string, delta int64, window time.Duration) (int64, error)
+}
+
+// Cache stores opaque values with an optional time to live (0 means no expiry).
+type Cache interface {
+	// Get returns the value for key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores value for key, replacing any existing value.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// SetNX stores value only if key is absent and reports whether it did.
+	// Idempotency keys use it to claim a request exactly once.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Session is server-side state for a signed-in user.
+type Session struct {
+	ID        string         `json:"id"`
+	Data      map[string]any `json:"data"`
+	ExpiresAt time.Time      `json:"expiresAt"`
+}
+
+// SessionStore persists sessions until they expire.
+type SessionStore interface {
+	// GetSession returns a session, or ErrNotFound if it is missing or expired.
+	GetSession(ctx context.Context, id string) (*Session, error)
+
+	// SaveSession stores a session until its ExpiresAt.
+	SaveSession(ctx context.Context, session *Session) error
+
+	// DeleteSession removes a session. Deleting a missing session is not an error.
+	DeleteSession(ctx context.Context, id string) error
+}
+
+// ObjectStore holds large binary objects such as exported files and
+// offloaded results, and hands out time-limited URLs to download them.
+type ObjectStore interface {
+	// Put stores the contents of r under key, replacing any existing object.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+
+	// Get opens an object, or returns ErrNotFound. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes an object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// SignedURL returns a URL that downloads the object without further
+	// authentication until it expires.
+	SignedURL(ctx context.Context, key string, expires time.Duration) (string, error)
+}
+
+// ConflictError reports a write against a stale version. It matches ErrConflict.
+type ConflictError struct {
+	Collection string
+	ID         string
+	Expected   int64
+	Actual     int64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s '%s': version conflict: expected %d, current %d", e.Collection, e.ID, e.Expected, e.Actual)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// NewID generates a random record ID for Create calls without one.
+func NewID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ETag formats a record version as an ETag header value.
+func ETag(version int64) string {
+	return ont.FormatETag(strconv.FormatInt(version, 10))
+}
+
+// IfMatchVersion parses the version from an If-Match header value. An empty
+// header or "*" returns AnyVersion.
+func IfMatchVersion(header string) (int64, error) {
+	if header == "" || header == "*" {
+		return AnyVersion, nil
+	}
+	version, err := strconv.ParseInt(ont.ParseETag(header), 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid If-Match header %q", header)
+	}
+	return version, nil
+}